@@ -2,17 +2,20 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	nativeos "os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
-	"github.com/qdm12/dns/pkg/unbound"
 	"github.com/qdm12/gluetun/internal/alpine"
+	"github.com/qdm12/gluetun/internal/bypass"
 	"github.com/qdm12/gluetun/internal/cli"
 	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/gluetun/internal/constants"
@@ -23,19 +26,22 @@ import (
 	gluetunLogging "github.com/qdm12/gluetun/internal/logging"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/openvpn"
+	"github.com/qdm12/gluetun/internal/provider"
 	"github.com/qdm12/gluetun/internal/publicip"
+	"github.com/qdm12/gluetun/internal/reload"
 	"github.com/qdm12/gluetun/internal/routing"
 	"github.com/qdm12/gluetun/internal/server"
 	"github.com/qdm12/gluetun/internal/shadowsocks"
 	"github.com/qdm12/gluetun/internal/storage"
+	"github.com/qdm12/gluetun/internal/transparentproxy"
 	"github.com/qdm12/gluetun/internal/unix"
 	"github.com/qdm12/gluetun/internal/updater"
 	versionpkg "github.com/qdm12/gluetun/internal/version"
+	"github.com/qdm12/gluetun/internal/wireguard"
 	"github.com/qdm12/golibs/logging"
 	"github.com/qdm12/golibs/os"
 	"github.com/qdm12/golibs/os/user"
 	"github.com/qdm12/golibs/params"
-	"github.com/qdm12/updated/pkg/dnscrypto"
 )
 
 //nolint:gochecknoglobals
@@ -55,7 +61,11 @@ func main() {
 	ctx := context.Background()
 	ctx, cancel := context.WithCancel(ctx)
 
-	logger := logging.New(logging.StdLog)
+	// keep the last log lines to serve them with the control server
+	const logBufferLines = 1000
+	logBuffer := gluetunLogging.NewBuffer(logBufferLines)
+	logger := logging.New(logging.StdLog,
+		logging.SetWriter(io.MultiWriter(nativeos.Stdout, logBuffer)))
 
 	args := nativeos.Args
 	os := os.New()
@@ -65,7 +75,7 @@ func main() {
 
 	errorCh := make(chan error)
 	go func() {
-		errorCh <- _main(ctx, buildInfo, args, logger, os, osUser, unix, cli)
+		errorCh <- _main(ctx, buildInfo, args, logger, logBuffer, os, osUser, unix, cli)
 	}()
 
 	signalsCh := make(chan nativeos.Signal, 1)
@@ -105,14 +115,35 @@ func main() {
 
 //nolint:gocognit,gocyclo
 func _main(ctx context.Context, buildInfo models.BuildInformation,
-	args []string, logger logging.Logger, os os.OS, osUser user.OSUser, unix unix.Unix,
-	cli cli.CLI) error {
+	args []string, logger logging.Logger, logBuffer *gluetunLogging.Buffer,
+	os os.OS, osUser user.OSUser, unix unix.Unix, cli cli.CLI) error {
+	configFilepath, args, err := parseConfigFlag(args)
+	if err != nil {
+		return err
+	}
+
+	// Capture the environment before loading the configuration file and
+	// the environment defaults, to know which variables are set explicitly.
+	environment := configuration.CaptureEnvironment()
+	var configFileKeys configuration.FileKeys
+	if configFilepath != "" {
+		configFileKeys, err = configuration.LoadFile(configFilepath, environment, nil)
+		if err != nil {
+			return err
+		}
+	}
+	if err := configuration.ApplyEnvDefaults(constants.EnvDefaults); err != nil {
+		return err
+	}
+
 	if len(args) > 1 { // cli operation
 		switch args[1] {
 		case "healthcheck":
 			return cli.HealthCheck(ctx)
 		case "clientkey":
 			return cli.ClientKey(args[2:], os.OpenFile)
+		case "genkey":
+			return cli.GenKey()
 		case "openvpnconfig":
 			return cli.OpenvpnConfig(os)
 		case "update":
@@ -129,25 +160,22 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	// Create configurators
 	alpineConf := alpine.NewConfigurator(os.OpenFile, osUser)
 	ovpnConf := openvpn.NewConfigurator(logger, os, unix)
-	dnsCrypto := dnscrypto.New(httpClient, "", "")
-	const cacertsPath = "/etc/ssl/certs/ca-certificates.crt"
-	dnsConf := unbound.NewConfigurator(logger, os.OpenFile, dnsCrypto,
-		"/etc/unbound", "/usr/sbin/unbound", cacertsPath)
+	wireguardConf := wireguard.NewConfigurator(logger, os)
 	routingConf := routing.NewRouting(logger)
 	firewallConf := firewall.NewConfigurator(logger, routingConf, os.OpenFile)
 
 	fmt.Println(gluetunLogging.Splash(buildInfo))
 
 	printVersions(ctx, logger, map[string]func(ctx context.Context) (string, error){
-		"OpenVPN":  ovpnConf.Version,
-		"Unbound":  dnsConf.Version,
-		"IPtables": firewallConf.Version,
+		"OpenVPN":   ovpnConf.Version,
+		"Wireguard": wireguardConf.Version,
+		"IPtables":  firewallConf.Version,
 	})
 
 	var allSettings configuration.Settings
-	err := allSettings.Read(params.NewEnv(), os, logger.NewChild(logging.SetPrefix("configuration: ")))
+	err = allSettings.Read(params.NewEnv(), os, logger.NewChild(logging.SetPrefix("configuration: ")))
 	if err != nil {
-		return err
+		return configFileKeys.WrapError(err, configFilepath)
 	}
 	logger.Info(allSettings.String())
 
@@ -165,6 +193,23 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 		return err
 	}
 
+	// Provider data must be obtained before the firewall is enabled,
+	// unless the bootstrap mode allows it through the firewall. In this
+	// case, the provider API hosts are resolved before the firewall is enabled.
+	bootstrap := allSettings.Firewall.Enabled && allSettings.Firewall.Bootstrap
+	var bootstrapRules []models.OutboundRule
+	if bootstrap {
+		bootstrapRules, err = provider.BootstrapRules(ctx, net.DefaultResolver,
+			provider.BootstrapHosts(allSettings))
+		if err != nil {
+			return err
+		}
+		bootstrapRules = append(bootstrapRules, allSettings.Firewall.BootstrapRules...)
+	} else if err := fetchProviderData(ctx, &allSettings, &allServers,
+		httpClient, os.OpenFile, logger, nil); err != nil {
+		return err
+	}
+
 	// Should never change
 	puid, pgid := allSettings.System.PUID, allSettings.System.PGID
 
@@ -177,15 +222,21 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 		logger.Info("using existing username %s corresponding to user id %d", nonRootUsername, puid)
 	}
 
-	if err := os.Chown("/etc/unbound", puid, pgid); err != nil {
-		return err
-	}
-
 	if allSettings.Firewall.Debug {
 		firewallConf.SetDebug()
 		routingConf.SetDebug()
 	}
 
+	if err := firewallConf.SetBackend(ctx, allSettings.Firewall.Backend); err != nil {
+		return err
+	}
+	firewallConf.SetPostRulesFilepath(allSettings.Firewall.PostRulesFile)
+	firewallConf.SetLogDropped(allSettings.Firewall.LogDropped)
+	firewallConf.SetFlushConntrack(allSettings.Firewall.FlushConntrack)
+	if allSettings.Firewall.Audit {
+		firewallConf.SetAudit()
+	}
+
 	defaultInterface, defaultGateway, err := routingConf.DefaultRoute()
 	if err != nil {
 		return err
@@ -203,6 +254,17 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 
 	firewallConf.SetNetworkInformation(defaultInterface, defaultGateway, localNetworks, defaultIP)
 
+	if allSettings.VPNType == constants.OpenVPN && allSettings.OpenVPN.Provider.ExtraConfigOptions.OpenVPNIPv6 {
+		ipv6Supported, err := routingConf.IPv6Supported()
+		if err != nil {
+			return err
+		} else if !ipv6Supported {
+			return fmt.Errorf("IPv6 is disabled, please enable it with the sysctl net.ipv6.conf.all.disable_ipv6=0")
+		} else if !firewallConf.IPv6Supported() {
+			return firewall.ErrNeedIP6Tables
+		}
+	}
+
 	if err := routingConf.Setup(); err != nil {
 		return err
 	}
@@ -213,10 +275,32 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 		}
 	}()
 
-	if err := firewallConf.SetOutboundSubnets(ctx, allSettings.Firewall.OutboundSubnets); err != nil {
+	outboundSubnets := make([]net.IPNet, 0,
+		len(allSettings.Firewall.OutboundSubnets)+len(allSettings.Firewall.BypassSubnets))
+	outboundSubnets = append(outboundSubnets, allSettings.Firewall.OutboundSubnets...)
+	outboundSubnets = append(outboundSubnets, allSettings.Firewall.BypassSubnets...)
+	if err := firewallConf.SetOutboundSubnets(ctx, outboundSubnets); err != nil {
 		return err
 	}
-	if err := routingConf.SetOutboundRoutes(allSettings.Firewall.OutboundSubnets); err != nil {
+	outboundRules := make([]models.OutboundRule, 0,
+		len(allSettings.Firewall.OutboundRules)+len(allSettings.DNS.SplitRules))
+	outboundRules = append(outboundRules, allSettings.Firewall.OutboundRules...)
+	if allSettings.DNS.Enabled {
+		for _, rule := range allSettings.DNS.SplitRules {
+			outboundRules = append(outboundRules, rule.OutboundRule())
+		}
+	}
+	if err := firewallConf.SetOutboundRules(ctx, outboundRules); err != nil {
+		return err
+	}
+	outboundRulesSubnets := make([]net.IPNet, len(outboundRules))
+	for i, rule := range outboundRules {
+		outboundRulesSubnets[i] = rule.Subnet
+	}
+	routedSubnets := make([]net.IPNet, 0, len(outboundSubnets)+len(outboundRulesSubnets))
+	routedSubnets = append(routedSubnets, outboundSubnets...)
+	routedSubnets = append(routedSubnets, outboundRulesSubnets...)
+	if err := routingConf.SetOutboundRoutes(routedSubnets); err != nil {
 		return err
 	}
 
@@ -231,15 +315,43 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	tunnelReadyCh := make(chan struct{})
 	defer close(tunnelReadyCh)
 
+	// to detect traffic leaking outside the VPN and compare with the public IP through the VPN
+	var preVPNIP, preVPNIPv6 net.IP
+	if allSettings.Health.LeakCheck || allSettings.PublicIP.ComparePreVPN {
+		preVPNIP, preVPNIPv6 = fetchPreVPNIP(ctx, httpClient, allSettings.PublicIP, logger)
+	}
+
 	if allSettings.Firewall.Enabled {
-		err := firewallConf.SetEnabled(ctx, true) // disabled by default
+		err := firewallConf.SetBootstrapRules(ctx, bootstrapRules)
 		if err != nil {
 			return err
 		}
+		err = firewallConf.SetEnabled(ctx, true) // disabled by default
+		if err != nil {
+			return err
+		}
+	}
+
+	if bootstrap {
+		allowProbes := func(ctx context.Context, probeRules []models.OutboundRule) error {
+			return firewallConf.SetBootstrapRules(ctx, append(probeRules, bootstrapRules...))
+		}
+		if err := fetchProviderData(ctx, &allSettings, &allServers,
+			httpClient, os.OpenFile, logger, allowProbes); err != nil {
+			return err
+		}
+		if err := firewallConf.SetBootstrapRules(ctx, nil); err != nil {
+			return err
+		}
 	}
 
+	vpnInterface := string(constants.TUN)
+	if allSettings.VPNType == constants.Wireguard {
+		vpnInterface = allSettings.Wireguard.Interface
+	}
+	// VPN input ports follow the VPN interface as it changes
 	for _, vpnPort := range allSettings.Firewall.VPNInputPorts {
-		err = firewallConf.SetAllowedPort(ctx, vpnPort, string(constants.TUN))
+		err = firewallConf.SetAllowedPort(ctx, vpnPort, firewallConf.GetVPNInterface())
 		if err != nil {
 			return err
 		}
@@ -252,6 +364,66 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 		}
 	} // TODO move inside firewall?
 
+	if len(allSettings.Firewall.LANPorts) > 0 {
+		for _, port := range allSettings.Firewall.LANPorts {
+			err = firewallConf.SetAllowedPort(ctx, port, defaultInterface)
+			if err != nil {
+				return err
+			}
+		}
+		if err := routingConf.SetLANPortsRoute(true); err != nil {
+			return err
+		}
+		err = firewallConf.SetLANPorts(ctx, allSettings.Firewall.LANPorts)
+		if err != nil {
+			return err
+		}
+	}
+
+	if allSettings.Firewall.MulticastDNS {
+		if err := routingConf.SetMulticastRoute(true); err != nil {
+			return err
+		}
+		if err := firewallConf.SetMulticastDNS(ctx, true); err != nil {
+			return err
+		}
+	}
+
+	if allSettings.DNS.ServesLAN() {
+		const dnsPort = 53
+		if err := firewallConf.SetDNSServerPort(ctx, dnsPort); err != nil {
+			return err
+		}
+	}
+
+	if len(allSettings.Firewall.BypassSources) > 0 || len(allSettings.Firewall.BypassUIDs) > 0 {
+		if err := routingConf.SetBypassSourcesRoute(true); err != nil {
+			return err
+		}
+	}
+
+	if len(allSettings.Firewall.VPNSources) > 0 || len(allSettings.Firewall.BypassSources) > 0 {
+		err = firewallConf.SetForwardedSources(ctx,
+			allSettings.Firewall.VPNSources, allSettings.Firewall.BypassSources)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(allSettings.Firewall.BypassUIDs) > 0 {
+		if err := firewallConf.SetBypassUIDs(ctx, allSettings.Firewall.BypassUIDs); err != nil {
+			return err
+		}
+	}
+
+	if len(allSettings.Firewall.TransparentProxySources) > 0 {
+		err = firewallConf.SetTransparentProxy(ctx, allSettings.Firewall.TransparentProxySources,
+			allSettings.Firewall.TransparentProxyPorts, allSettings.Firewall.TransparentProxyListeningPort)
+		if err != nil {
+			return err
+		}
+	}
+
 	wg := &sync.WaitGroup{}
 
 	openvpnLooper := openvpn.NewLooper(allSettings.OpenVPN, nonRootUsername, puid, pgid, allServers,
@@ -260,25 +432,48 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	// wait for restartOpenvpn
 	go openvpnLooper.Run(ctx, wg)
 
+	wireguardLooper := wireguard.NewLooper(allSettings.Wireguard,
+		wireguardConf, firewallConf, routingConf, logger, httpClient, os.OpenFile, tunnelReadyCh)
+	wg.Add(1)
+	go wireguardLooper.Run(ctx, wg)
+
 	updaterLooper := updater.NewLooper(allSettings.Updater,
 		allServers, storage, openvpnLooper.SetServers, httpClient, logger)
 	wg.Add(1)
 	// wait for updaterLooper.Restart() or its ticket launched with RunRestartTicker
 	go updaterLooper.Run(ctx, wg)
 
-	unboundLooper := dns.NewLooper(dnsConf, allSettings.DNS, httpClient,
-		logger, nonRootUsername, puid, pgid)
+	dnsLooper := dns.NewLooper(allSettings.DNS, httpClient, os.OpenFile, logger)
 	wg.Add(1)
-	// wait for unboundLooper.Restart or its ticker launched with RunRestartTicker
-	go unboundLooper.Run(ctx, wg)
+	// wait for dnsLooper.Restart or its ticker launched with RunRestartTicker
+	go dnsLooper.Run(ctx, wg)
 
 	publicIPLooper := publicip.NewLooper(
-		httpClient, logger, allSettings.PublicIP, puid, pgid, os)
+		httpClient, logger, allSettings.PublicIP, puid, pgid, os, preVPNIP)
 	wg.Add(1)
 	go publicIPLooper.Run(ctx, wg)
 	wg.Add(1)
 	go publicIPLooper.RunRestartTicker(ctx, wg)
 
+	if len(allSettings.Firewall.BypassDomains) > 0 {
+		bypassUpdater := bypass.New(firewallConf, routingConf, logger, outboundSubnets, outboundRulesSubnets,
+			allSettings.Firewall.BypassDomains, allSettings.Firewall.BypassPeriod)
+		wg.Add(1)
+		go bypassUpdater.Run(ctx, wg)
+	}
+
+	if len(allSettings.Firewall.TransparentProxySources) > 0 {
+		transparentProxy := transparentproxy.New(logger, allSettings.Firewall.TransparentProxyListeningPort)
+		wg.Add(1)
+		go transparentProxy.Run(ctx, wg)
+	}
+
+	if allSettings.Firewall.Enabled && !allSettings.Firewall.Audit &&
+		allSettings.Firewall.VerifyPeriod > 0 {
+		wg.Add(1)
+		go firewallConf.RunVerifyTicker(ctx, wg, allSettings.Firewall.VerifyPeriod)
+	}
+
 	httpProxyLooper := httpproxy.NewLooper(logger, allSettings.HTTPProxy)
 	wg.Add(1)
 	go httpProxyLooper.Run(ctx, wg)
@@ -287,26 +482,79 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	wg.Add(1)
 	go shadowsocksLooper.Run(ctx, wg)
 
+	reloader := reload.New(allSettings, configFilepath, configFileKeys, environment, constants.EnvDefaults,
+		defaultInterface, openvpnLooper, dnsLooper, httpProxyLooper, shadowsocksLooper,
+		firewallConf, os, logger)
+	wg.Add(1)
+	go reloader.Run(ctx, wg)
+
 	wg.Add(1)
 	go routeReadyEvents(ctx, wg, buildInfo, tunnelReadyCh,
-		unboundLooper, updaterLooper, publicIPLooper, routingConf, logger, httpClient,
+		dnsLooper, updaterLooper, publicIPLooper, routingConf, logger, httpClient,
 		allSettings.VersionInformation, allSettings.OpenVPN.Provider.PortForwarding.Enabled, openvpnLooper.PortForward,
 	)
-	controlServerAddress := fmt.Sprintf("0.0.0.0:%d", allSettings.ControlServer.Port)
+	var openvpnState func() models.OpenVPNConnectionState
+	var vpnLooper healthcheck.VPNLooper
+	if allSettings.VPNType == constants.OpenVPN {
+		openvpnState = openvpnLooper.GetConnectionState
+		vpnLooper = openvpnLooper
+	}
+	healthcheckServer := healthcheck.NewServer(constants.HealthcheckAddress, logger,
+		openvpnState, allSettings.Health, vpnLooper, cancel, firewallConf, preVPNIP, preVPNIPv6,
+		allSettings.PublicIP)
+	wg.Add(1)
+	go healthcheckServer.Run(ctx, wg)
+
+	controlServerListen := server.ListenSettings{
+		Address:     fmt.Sprintf("0.0.0.0:%d", allSettings.ControlServer.Port),
+		TLS:         allSettings.ControlServer.TLS,
+		TLSCertFile: allSettings.ControlServer.TLSCertFile,
+		TLSKeyFile:  allSettings.ControlServer.TLSKeyFile,
+		UnixSocket:  allSettings.ControlServer.UnixSocket,
+	}
 	controlServerLogging := allSettings.ControlServer.Log
-	httpServer := server.New(controlServerAddress, controlServerLogging,
-		logger, buildInfo, openvpnLooper, unboundLooper, updaterLooper, publicIPLooper)
+	controlServerMetrics := allSettings.ControlServer.Metrics
+	controlServerAuth := server.AuthSettings{
+		APIKey:         allSettings.ControlServer.APIKey,
+		ReadOnlyAPIKey: allSettings.ControlServer.ReadOnlyAPIKey,
+		User:           allSettings.ControlServer.User,
+		Password:       allSettings.ControlServer.Password,
+	}
+	httpServer := server.New(controlServerListen, controlServerLogging, controlServerMetrics,
+		logger, logBuffer, buildInfo, reloader, openvpnLooper, dnsLooper, updaterLooper, publicIPLooper,
+		httpProxyLooper, healthcheckServer,
+		firewallConf, server.FirewallSettings{
+			VPNInterface: vpnInterface,
+			LANInterface: defaultInterface,
+		}, controlServerAuth, server.AccessSettings{
+			CORSOrigins: allSettings.ControlServer.CORSOrigins,
+			RateLimit:   allSettings.ControlServer.RateLimit,
+		}, server.AuditSettings{
+			Enabled:  allSettings.ControlServer.Audit,
+			Filepath: allSettings.ControlServer.AuditFile,
+		})
 	wg.Add(1)
 	go httpServer.Run(ctx, wg)
 
-	healthcheckServer := healthcheck.NewServer(
-		constants.HealthcheckAddress, logger)
-	wg.Add(1)
-	go healthcheckServer.Run(ctx, wg)
+	if allSettings.ControlServer.GRPC {
+		grpcListen := controlServerListen
+		grpcListen.Address = fmt.Sprintf("0.0.0.0:%d", allSettings.ControlServer.GRPCPort)
+		grpcServer, err := server.NewGRPC(grpcListen, logger, logBuffer, buildInfo,
+			openvpnLooper, dnsLooper, updaterLooper, publicIPLooper, controlServerAuth)
+		if err != nil {
+			return err
+		}
+		wg.Add(1)
+		go grpcServer.Run(ctx, wg)
+	}
 
-	// Start openvpn for the first time in a blocking call
-	// until openvpn is launched
-	_, _ = openvpnLooper.SetStatus(constants.Running) // TODO option to disable with variable
+	// Start the VPN for the first time in a blocking call
+	// until it is launched
+	if allSettings.VPNType == constants.Wireguard {
+		_, _ = wireguardLooper.SetStatus(constants.Running)
+	} else {
+		_, _ = openvpnLooper.SetStatus(constants.Running) // TODO option to disable with variable
+	}
 
 	<-ctx.Done()
 
@@ -330,6 +578,123 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	return nil
 }
 
+// fetchProviderData obtains the NordLynx settings, the PIA dedicated IP
+// server and the server latencies, and updates the settings and servers
+// given. It must run before the firewall is enabled, unless bootstrap
+// rules allow this traffic through the firewall, in which case allowProbes
+// is used to allow the latency probes through the firewall.
+func fetchProviderData(ctx context.Context, allSettings *configuration.Settings,
+	allServers *models.AllServers, httpClient *http.Client, openFile os.OpenFileFunc,
+	logger logging.Logger,
+	allowProbes func(ctx context.Context, rules []models.OutboundRule) error) (err error) {
+	if allSettings.VPNType == constants.Wireguard && allSettings.Wireguard.Provider == constants.Nordvpn {
+		wireguardSettings, err := provider.NordlynxSettings(ctx, httpClient, openFile,
+			allSettings.Wireguard, *allServers)
+		switch {
+		case errors.Is(err, provider.ErrNordlynxNoServer) && allSettings.OpenVPN.User != "":
+			logger.Warn("%s: falling back on OpenVPN", err)
+			allSettings.VPNType = constants.OpenVPN
+		case err != nil:
+			return err
+		default:
+			allSettings.Wireguard = wireguardSettings
+		}
+	}
+
+	if selection := &allSettings.OpenVPN.Provider.ServerSelection; allSettings.VPNType == constants.OpenVPN &&
+		allSettings.OpenVPN.Provider.Name == constants.PrivateInternetAccess && selection.DedicatedIPToken != "" {
+		server, err := provider.PIADedicatedIP(ctx, httpClient, openFile,
+			allSettings.OpenVPN.User, allSettings.OpenVPN.Password, selection.DedicatedIPToken)
+		if err != nil {
+			return err
+		}
+		logger.Info("using PIA dedicated IP %s", server.IP)
+		allServers.Pia.Servers = append(allServers.Pia.Servers, server)
+		selection.TargetIP = server.IP
+	}
+
+	if selection := allSettings.OpenVPN.Provider.ServerSelection; allSettings.VPNType == constants.OpenVPN &&
+		allSettings.OpenVPN.Config == "" && selection.Latency.Enabled {
+		logger.Info("probing latency of up to %d servers...", selection.Latency.Candidates)
+		providerConf := provider.New(allSettings.OpenVPN.Provider.Name, *allServers, time.Now)
+		candidates, err := provider.LatencyCandidates(providerConf, selection)
+		if err != nil {
+			logger.Warn("cannot probe server latencies: %s", err)
+			return nil
+		}
+		if allowProbes != nil {
+			if err := allowProbes(ctx, provider.LatencyProbeRules(candidates)); err != nil {
+				return err
+			}
+		}
+		latencies := provider.ProbeLatencies(ctx, candidates, selection.Latency.Timeout)
+		switch {
+		case len(latencies) == 0:
+			logger.Warn("no server replied to the latency probe, selecting a server randomly")
+		default:
+			logger.Info("%d servers replied to the latency probe", len(latencies))
+			allServers.Latencies = latencies
+		}
+	}
+
+	return nil
+}
+
+var errConfigFlagValue = errors.New("--config flag requires a file path")
+
+// parseConfigFlag extracts the configuration file path from the
+// --config flag in the arguments given, and returns the arguments
+// without the flag.
+func parseConfigFlag(args []string) (path string, otherArgs []string, err error) {
+	otherArgs = make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--config":
+			if i+1 == len(args) || args[i+1] == "" {
+				return "", nil, errConfigFlagValue
+			}
+			i++
+			path = args[i]
+		case strings.HasPrefix(args[i], "--config="):
+			path = strings.TrimPrefix(args[i], "--config=")
+			if path == "" {
+				return "", nil, errConfigFlagValue
+			}
+		default:
+			otherArgs = append(otherArgs, args[i])
+		}
+	}
+	return path, otherArgs, nil
+}
+
+// fetchPreVPNIP returns the public IP address before connecting to
+// the VPN, or nil if it cannot be found. It also returns the public
+// IPv6 address if IPv6 is enabled, or nil if it cannot be found.
+// It must run before the firewall is enabled.
+func fetchPreVPNIP(ctx context.Context, httpClient *http.Client,
+	settings configuration.PublicIP, logger logging.Logger) (ip, ipv6 net.IP) {
+	const timeout = 10 * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ip, err := publicip.NewIPGetter(httpClient, settings).Get(ctx)
+	if err != nil {
+		logger.Warn("cannot get public IP address before connecting to the VPN: %s", err)
+	} else {
+		logger.Info("recorded public IP address before connecting to the VPN")
+	}
+
+	if !settings.IPv6 {
+		return ip, nil
+	}
+	ipv6, err = publicip.NewIPv6Getter(httpClient, settings).Get(ctx)
+	if err != nil {
+		logger.Warn("cannot get public IPv6 address before connecting to the VPN: %s", err)
+	} else {
+		logger.Info("recorded public IPv6 address before connecting to the VPN")
+	}
+	return ip, ipv6
+}
+
 func printVersions(ctx context.Context, logger logging.Logger,
 	versionFunctions map[string]func(ctx context.Context) (string, error)) {
 	const timeout = 5 * time.Second
@@ -347,7 +712,7 @@ func printVersions(ctx context.Context, logger logging.Logger,
 
 func routeReadyEvents(ctx context.Context, wg *sync.WaitGroup, buildInfo models.BuildInformation,
 	tunnelReadyCh <-chan struct{},
-	unboundLooper dns.Looper, updaterLooper updater.Looper, publicIPLooper publicip.Looper,
+	dnsLooper dns.Looper, updaterLooper updater.Looper, publicIPLooper publicip.Looper,
 	routing routing.Routing, logger logging.Logger, httpClient *http.Client,
 	versionInformation, portForwardingEnabled bool, startPortForward func(vpnGateway net.IP)) {
 	defer wg.Done()
@@ -370,8 +735,8 @@ func routeReadyEvents(ctx context.Context, wg *sync.WaitGroup, buildInfo models.
 				logger.Info("VPN routing IP address: %s", vpnDestination)
 			}
 
-			if unboundLooper.GetSettings().Enabled {
-				_, _ = unboundLooper.SetStatus(constants.Running)
+			if dnsLooper.GetSettings().Enabled {
+				_, _ = dnsLooper.SetStatus(constants.Running)
 			}
 
 			restartTickerCancel() // stop previous restart tickers
@@ -396,7 +761,7 @@ func routeReadyEvents(ctx context.Context, wg *sync.WaitGroup, buildInfo models.
 
 			//nolint:gomnd
 			tickerWg.Add(2)
-			go unboundLooper.RunRestartTicker(restartTickerContext, tickerWg)
+			go dnsLooper.RunRestartTicker(restartTickerContext, tickerWg)
 			go updaterLooper.RunRestartTicker(restartTickerContext, tickerWg)
 			if portForwardingEnabled {
 				// vpnGateway required only for PIA
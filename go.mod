@@ -1,16 +1,35 @@
 module github.com/qdm12/gluetun
 
-go 1.16
+go 1.17
 
 require (
+	github.com/BurntSushi/toml v1.2.1
 	github.com/fatih/color v1.10.0
-	github.com/golang/mock v1.5.0
+	github.com/golang/mock v1.6.0
 	github.com/kyokomi/emoji v2.2.4+incompatible
 	github.com/qdm12/dns v1.4.0
 	github.com/qdm12/golibs v0.0.0-20210215133151-c711ebd3e56a
 	github.com/qdm12/ss-server v0.1.0
-	github.com/qdm12/updated v0.0.0-20210102005021-dd457d77f94a
-	github.com/stretchr/testify v1.7.0
+	github.com/stretchr/testify v1.8.3
 	github.com/vishvananda/netlink v1.1.0
-	golang.org/x/sys v0.0.0-20201223074533-0d417f636930
+	golang.org/x/crypto v0.0.0-20211108221036-ceb1ce70b4fa
+	golang.org/x/sys v0.7.0
+	google.golang.org/grpc v1.56.3
+	google.golang.org/protobuf v1.31.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/mattn/go-colorable v0.1.8 // indirect
+	github.com/mattn/go-isatty v0.0.12 // indirect
+	github.com/mr-tron/base58 v1.1.3 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/qdm12/updated v0.0.0-20210102005021-dd457d77f94a // indirect
+	github.com/riobard/go-bloom v0.0.0-20200614022211-cdc8013cb5b3 // indirect
+	github.com/vishvananda/netns v0.0.0-20191106174202-0a2b9b5464df // indirect
+	golang.org/x/net v0.9.0 // indirect
+	golang.org/x/text v0.9.0 // indirect
+	google.golang.org/genproto v0.0.0-20230410155749-daa745c078e1 // indirect
 )
@@ -0,0 +1,122 @@
+// Package bypass defines an updater keeping the outbound subnets bypassing
+// the VPN up to date with the IP addresses of the bypass domains.
+package bypass
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/qdm12/gluetun/internal/routing"
+	"github.com/qdm12/golibs/logging"
+)
+
+type Updater interface {
+	Run(ctx context.Context, wg *sync.WaitGroup)
+}
+
+type updater struct {
+	fw       firewall.Configurator
+	routing  routing.Routing
+	logger   logging.Logger
+	resolver *net.Resolver
+	// subnets are the outbound subnets not resolved from domains.
+	subnets []net.IPNet
+	// routedSubnets are subnets only routed outside the VPN, which are
+	// allowed through the firewall by other means.
+	routedSubnets []net.IPNet
+	domains       []string
+	period        time.Duration
+	// domainIPs maps each domain to its last resolved IPv4 addresses.
+	domainIPs map[string][]net.IP
+}
+
+func New(fw firewall.Configurator, routing routing.Routing, logger logging.Logger,
+	subnets, routedSubnets []net.IPNet, domains []string, period time.Duration) Updater {
+	return &updater{
+		fw:            fw,
+		routing:       routing,
+		logger:        logger.NewChild(logging.SetPrefix("bypass: ")),
+		resolver:      net.DefaultResolver,
+		subnets:       subnets,
+		routedSubnets: routedSubnets,
+		domains:       domains,
+		period:        period,
+		domainIPs:     make(map[string][]net.IP, len(domains)),
+	}
+}
+
+// retryPeriod is the period to resolve the domains again if one of
+// them cannot be resolved, for example before the VPN is up.
+const retryPeriod = time.Minute
+
+func (u *updater) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	for {
+		period := u.period
+		if !u.update(ctx) {
+			period = retryPeriod
+		}
+		timer := time.NewTimer(period)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// update resolves the domains and sets the outbound subnets through the
+// firewall and routing. It returns false if a domain cannot be resolved.
+func (u *updater) update(ctx context.Context) (resolved bool) {
+	resolved = true
+	for _, domain := range u.domains {
+		ips, err := u.resolver.LookupIP(ctx, "ip4", domain)
+		if err != nil {
+			if ctx.Err() == nil {
+				u.logger.Warn("cannot resolve %s: %s", domain, err)
+			}
+			resolved = false
+			continue // keep previous IP addresses
+		}
+		u.domainIPs[domain] = ips
+	}
+
+	subnets := makeSubnets(u.subnets, u.domains, u.domainIPs)
+	if err := u.fw.SetOutboundSubnets(ctx, subnets); err != nil {
+		u.logger.Error(err)
+		return false
+	}
+	if err := u.routing.SetOutboundRoutes(append(subnets, u.routedSubnets...)); err != nil {
+		u.logger.Error(err)
+		return false
+	}
+	return resolved
+}
+
+// makeSubnets returns the subnets given with a single IP address subnet
+// for each unique IP address of the domains.
+func makeSubnets(subnets []net.IPNet, domains []string,
+	domainIPs map[string][]net.IP) (all []net.IPNet) {
+	all = make([]net.IPNet, len(subnets))
+	copy(all, subnets)
+	seen := make(map[string]struct{})
+	for _, domain := range domains {
+		for _, ip := range domainIPs[domain] {
+			ip = ip.To4()
+			if ip == nil {
+				continue
+			}
+			if _, ok := seen[ip.String()]; ok {
+				continue
+			}
+			seen[ip.String()] = struct{}{}
+			const bits = 32
+			all = append(all, net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		}
+	}
+	return all
+}
@@ -0,0 +1,27 @@
+package bypass
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_makeSubnets(t *testing.T) {
+	t.Parallel()
+	subnets := []net.IPNet{{IP: net.IPv4(10, 0, 0, 0).To4(), Mask: net.CIDRMask(8, 32)}}
+	domains := []string{"a.com", "b.com", "c.com"}
+	domainIPs := map[string][]net.IP{
+		"a.com": {net.IPv4(1, 2, 3, 4)},
+		"b.com": {net.IPv4(1, 2, 3, 4), net.IPv4(5, 6, 7, 8)},
+	}
+
+	all := makeSubnets(subnets, domains, domainIPs)
+
+	expected := []net.IPNet{
+		{IP: net.IPv4(10, 0, 0, 0).To4(), Mask: net.CIDRMask(8, 32)},
+		{IP: net.IPv4(1, 2, 3, 4).To4(), Mask: net.CIDRMask(32, 32)},
+		{IP: net.IPv4(5, 6, 7, 8).To4(), Mask: net.CIDRMask(32, 32)},
+	}
+	assert.Equal(t, expected, all)
+}
@@ -9,6 +9,7 @@ import (
 
 type CLI interface {
 	ClientKey(args []string, openFile os.OpenFileFunc) error
+	GenKey() error
 	HealthCheck(ctx context.Context) error
 	OpenvpnConfig(os os.OS) error
 	Update(ctx context.Context, args []string, os os.OS) error
@@ -0,0 +1,17 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/wireguard"
+)
+
+func (c *cli) GenKey() error {
+	privateKey, publicKey, err := wireguard.GenerateKeyPair()
+	if err != nil {
+		return err
+	}
+	fmt.Println("Private key: " + privateKey)
+	fmt.Println("Public key: " + publicKey)
+	return nil
+}
@@ -2,9 +2,11 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration"
@@ -15,11 +17,18 @@ import (
 	"github.com/qdm12/golibs/os"
 )
 
+var ErrUpdateOutputMissing = errors.New("at least one of -file, -output, -stdout or -dry-run must be specified")
+
 func (c *cli) Update(ctx context.Context, args []string, os os.OS) error {
 	options := configuration.Updater{CLI: true}
-	var flushToFile bool
+	var flushToFile, dryRun bool
+	var providers, outputPath string
 	flagSet := flag.NewFlagSet("update", flag.ExitOnError)
-	flagSet.BoolVar(&flushToFile, "file", false, "Write results to /gluetun/servers.json (for end users)")
+	flagSet.BoolVar(&flushToFile, "file", false, "Write results to the output file path (for end users)")
+	flagSet.StringVar(&outputPath, "output", constants.ServersData, "Output file path to write results to")
+	flagSet.BoolVar(&dryRun, "dry-run", false, "Update servers and log the results without writing to any file")
+	flagSet.StringVar(&providers, "providers", "",
+		"Comma separated list of providers to update, or 'all', overriding individual provider flags")
 	flagSet.BoolVar(&options.Stdout, "stdout", false, "Write results to console to modify the program (for maintainers)")
 	flagSet.StringVar(&options.DNSAddress, "dns", "8.8.8.8", "DNS resolver address to use")
 	flagSet.BoolVar(&options.Cyberghost, "cyberghost", false, "Update Cyberghost servers")
@@ -35,17 +44,31 @@ func (c *cli) Update(ctx context.Context, args []string, os os.OS) error {
 	flagSet.BoolVar(&options.Torguard, "torguard", false, "Update Torguard servers")
 	flagSet.BoolVar(&options.Vyprvpn, "vyprvpn", false, "Update Vyprvpn servers")
 	flagSet.BoolVar(&options.Windscribe, "windscribe", false, "Update Windscribe servers")
+	flagSet.BoolVar(&options.Latency, "latency", false, "Measure and store the latency of each server IP address")
 	if err := flagSet.Parse(args); err != nil {
 		return err
 	}
 	logger := logging.New(logging.StdLog)
-	if !flushToFile && !options.Stdout {
-		return fmt.Errorf("at least one of -file or -stdout must be specified")
+	if providers != "" {
+		if err := options.SetProviders(strings.Split(providers, ",")); err != nil {
+			return err
+		}
+	}
+	flagSet.Visit(func(f *flag.Flag) {
+		if f.Name == "output" {
+			flushToFile = true
+		}
+	})
+	if dryRun {
+		flushToFile = false
+		outputPath = "" // do not read or write the servers file
+	} else if !flushToFile && !options.Stdout {
+		return ErrUpdateOutputMissing
 	}
 
 	const clientTimeout = 10 * time.Second
 	httpClient := &http.Client{Timeout: clientTimeout}
-	storage := storage.New(logger, os, constants.ServersData)
+	storage := storage.New(logger, os, outputPath)
 	currentServers, err := storage.SyncServers(constants.GetAllServers())
 	if err != nil {
 		return fmt.Errorf("cannot update servers: %w", err)
@@ -55,6 +78,10 @@ func (c *cli) Update(ctx context.Context, args []string, os os.OS) error {
 	if err != nil {
 		return err
 	}
+	if dryRun {
+		logger.Info("dry run: %d servers would be written", allServers.Count())
+		return nil
+	}
 	if flushToFile {
 		if err := storage.FlushToFile(allServers); err != nil {
 			return fmt.Errorf("cannot update servers: %w", err)
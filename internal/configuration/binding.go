@@ -0,0 +1,73 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/qdm12/golibs/params"
+)
+
+// Binding contains settings for the local end of the OpenVPN connection,
+// for example to set up policy routing on the host.
+type Binding struct {
+	// LocalPort is the local source port, and is 0 to use a random port.
+	LocalPort uint16 `json:"local_port"`
+	// Address is the local address to bind to, and is nil to not bind.
+	Address net.IP `json:"address"`
+	// Fwmark is the firewall mark set on the encrypted packets,
+	// and is 0 if disabled.
+	Fwmark uint32 `json:"fwmark"`
+}
+
+func (b *Binding) lines() (lines []string) {
+	if b.LocalPort == 0 && b.Address == nil && b.Fwmark == 0 {
+		return nil
+	}
+
+	lines = append(lines, lastIndent+"Binding:")
+	if b.LocalPort > 0 {
+		lines = append(lines, indent+lastIndent+"Local port: "+strconv.Itoa(int(b.LocalPort)))
+	}
+	if b.Address != nil {
+		lines = append(lines, indent+lastIndent+"Address: "+b.Address.String())
+	}
+	if b.Fwmark > 0 {
+		lines = append(lines, indent+lastIndent+"Firewall mark: "+strconv.FormatUint(uint64(b.Fwmark), 10))
+	}
+
+	return lines
+}
+
+var ErrFwmarkNotValid = errors.New("firewall mark is not valid")
+
+func (b *Binding) read(env params.Env) (err error) {
+	localPort, err := env.IntRange("OPENVPN_LOCAL_PORT", 0, 65535, params.Default("0"))
+	if err != nil {
+		return err
+	}
+	b.LocalPort = uint16(localPort)
+
+	b.Address, err = readIP(env, "OPENVPN_BIND_ADDRESS")
+	if err != nil {
+		return err
+	}
+
+	s, err := env.Get("OPENVPN_FWMARK")
+	if err != nil {
+		return err
+	} else if s == "" {
+		b.Fwmark = 0
+		return nil
+	}
+
+	// base 0 to accept hexadecimal marks such as 0xca6c
+	fwmark, err := strconv.ParseUint(s, 0, 32)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrFwmarkNotValid, err)
+	}
+	b.Fwmark = uint32(fwmark)
+
+	return nil
+}
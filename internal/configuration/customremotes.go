@@ -0,0 +1,118 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/params"
+)
+
+// CustomRemotes contains settings to pick the remote to connect to
+// amongst the remotes of the custom OpenVPN configuration.
+type CustomRemotes struct {
+	// Remotes are the remotes to use instead of the remote
+	// lines of the custom configuration, if not empty.
+	Remotes []models.OpenVPNConnection `json:"remotes"`
+	// Random is true if remotes should be picked randomly instead of
+	// in order. It is also true if the custom configuration sets
+	// the remote-random directive.
+	Random bool `json:"random"`
+	// Retries is the number of connection retries on a remote
+	// before moving on to another remote.
+	Retries int `json:"retries"`
+}
+
+func (c *CustomRemotes) lines() (lines []string) {
+	lines = append(lines, lastIndent+"Custom remotes:")
+
+	if len(c.Remotes) > 0 {
+		remotes := make([]string, len(c.Remotes))
+		for i, remote := range c.Remotes {
+			remotes[i] = formatCustomRemote(remote)
+		}
+		lines = append(lines, indent+lastIndent+"Remotes: "+commaJoin(remotes))
+	}
+
+	if c.Random {
+		lines = append(lines, indent+lastIndent+"Selection: random")
+	} else {
+		lines = append(lines, indent+lastIndent+"Selection: ordered")
+	}
+
+	lines = append(lines, indent+lastIndent+"Retries per remote: "+strconv.Itoa(c.Retries))
+
+	return lines
+}
+
+func (c *CustomRemotes) read(env params.Env) (err error) {
+	remotes, err := env.CSV("OPENVPN_CUSTOM_REMOTES")
+	if err != nil {
+		return err
+	}
+	c.Remotes = make([]models.OpenVPNConnection, len(remotes))
+	for i, remote := range remotes {
+		c.Remotes[i], err = parseCustomRemote(remote)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.Random, err = env.OnOff("OPENVPN_CUSTOM_REMOTE_RANDOM", params.Default("off"))
+	if err != nil {
+		return err
+	}
+
+	c.Retries, err = env.IntRange("OPENVPN_CUSTOM_REMOTE_RETRIES", 0, 100, params.Default("1")) //nolint:gomnd
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var ErrCustomRemoteNotValid = errors.New("custom remote is not valid")
+
+// parseCustomRemote parses a remote in the format ip:port:protocol,
+// where the IPv6 address must be enclosed in square brackets.
+func parseCustomRemote(s string) (connection models.OpenVPNConnection, err error) {
+	i := strings.LastIndex(s, ":")
+	if i == -1 {
+		return connection, fmt.Errorf("%w: %q is not in the format ip:port:protocol", ErrCustomRemoteNotValid, s)
+	}
+	hostPort, protocol := s[:i], strings.ToLower(s[i+1:])
+
+	host, portString, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return connection, fmt.Errorf("%w: %s", ErrCustomRemoteNotValid, err)
+	}
+
+	connection.IP = net.ParseIP(host)
+	if connection.IP == nil {
+		return connection, fmt.Errorf("%w: %q is not an IP address", ErrCustomRemoteNotValid, host)
+	}
+
+	port, err := strconv.ParseUint(portString, 10, 16) //nolint:gomnd
+	if err != nil || port == 0 {
+		return connection, fmt.Errorf("%w: port %q is not valid", ErrCustomRemoteNotValid, portString)
+	}
+	connection.Port = uint16(port)
+
+	switch protocol {
+	case constants.TCP, constants.UDP:
+		connection.Protocol = protocol
+	default:
+		return connection, fmt.Errorf("%w: protocol %q is not valid", ErrCustomRemoteNotValid, protocol)
+	}
+
+	return connection, nil
+}
+
+func formatCustomRemote(connection models.OpenVPNConnection) string {
+	return net.JoinHostPort(connection.IP.String(), strconv.Itoa(int(connection.Port))) +
+		":" + connection.Protocol
+}
@@ -0,0 +1,63 @@
+package configuration
+
+import (
+	"net"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseCustomRemote(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		connection models.OpenVPNConnection
+		err        error
+	}{
+		"IPv4": {
+			s: "1.2.3.4:1194:udp",
+			connection: models.OpenVPNConnection{
+				IP: net.IPv4(1, 2, 3, 4), Port: 1194, Protocol: "udp",
+			},
+		},
+		"IPv6": {
+			s: "[::1]:443:TCP",
+			connection: models.OpenVPNConnection{
+				IP: net.IPv6loopback, Port: 443, Protocol: "tcp",
+			},
+		},
+		"missing protocol": {
+			s:   "1.2.3.4:1194",
+			err: ErrCustomRemoteNotValid,
+		},
+		"hostname": {
+			s:   "vpn.example.com:1194:udp",
+			err: ErrCustomRemoteNotValid,
+		},
+		"bad protocol": {
+			s:   "1.2.3.4:1194:icmp",
+			err: ErrCustomRemoteNotValid,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			connection, err := parseCustomRemote(testCase.s)
+
+			if testCase.err != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, testCase.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, testCase.connection.Equal(connection))
+			assert.Equal(t, testCase.connection.Protocol, connection.Protocol)
+		})
+	}
+}
@@ -4,24 +4,81 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/url"
 	"strings"
 	"time"
 
-	unboundmodels "github.com/qdm12/dns/pkg/models"
 	unbound "github.com/qdm12/dns/pkg/unbound"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/golibs/params"
 )
 
-// DNS contains settings to configure Unbound for DNS over TLS operation.
+// DNS contains settings to configure the DNS server forwarding
+// queries to DNS over TLS or DNS over HTTPS servers.
 type DNS struct { //nolint:maligned
-	Enabled           bool
-	PlaintextAddress  net.IP
-	KeepNameserver    bool
-	BlockMalicious    bool
-	BlockAds          bool
-	BlockSurveillance bool
-	UpdatePeriod      time.Duration
-	Unbound           unboundmodels.Settings
+	Enabled bool `json:"enabled"`
+	// ListeningAddress is the address the DNS server listens on,
+	// which defaults to 127.0.0.1:53. It can be set for example
+	// to 0.0.0.0:53 to serve DNS to LAN clients.
+	ListeningAddress string `json:"listening_address"`
+	PlaintextAddress net.IP `json:"plaintext_address"`
+	KeepNameserver   bool   `json:"keep_nameserver"`
+	// Providers are the DNS over TLS providers to forward queries to.
+	Providers []string `json:"providers"`
+	Caching   bool     `json:"caching"`
+	// IPv6 is true to also reach the upstream servers over IPv6.
+	IPv6 bool `json:"ipv6"`
+	// DNSSEC is the DNSSEC mode, which is upstream, permissive or off.
+	// The validation is done by the encrypted upstream servers, and
+	// does not apply to the plaintext split DNS servers.
+	DNSSEC string `json:"dnssec"`
+	// StripECS is true to remove the EDNS client subnet
+	// of queries before forwarding them.
+	StripECS bool `json:"strip_ecs"`
+	// CaseRandomization is true to randomize the case of the
+	// names of forwarded queries, to detect spoofed responses.
+	CaseRandomization bool `json:"case_randomization"`
+	// UpstreamStrategy is how the upstream servers are picked,
+	// which is round-robin or priority.
+	UpstreamStrategy string `json:"upstream_strategy"`
+	// DoH is true to forward queries with DNS over HTTPS instead
+	// of DNS over TLS, for networks blocking TCP port 853.
+	DoH        bool               `json:"doh"`
+	DoHServers []models.DoHServer `json:"doh_servers"`
+	// BlockCategories are the names of the categories
+	// of hostnames and IP addresses to block.
+	BlockCategories []string `json:"block_categories"`
+	// RebindingProtection is true to refuse DNS responses with
+	// IP addresses in the private addresses, to prevent DNS
+	// rebinding attacks.
+	RebindingProtection bool `json:"rebinding_protection"`
+	// PrivateAddresses are the IP addresses and ranges which cannot
+	// be in DNS responses if the rebinding protection is enabled.
+	PrivateAddresses []net.IPNet `json:"private_addresses"`
+	// RebindingAllowedHostnames are the hostnames, and their subdomains,
+	// which can resolve to private addresses, such as split-horizon names.
+	RebindingAllowedHostnames []string `json:"rebinding_allowed_hostnames"`
+	// AllowedHostnames are the hostnames not to block.
+	AllowedHostnames []string `json:"allowed_hostnames"`
+	// BlocklistURLs are the URLs of user block lists, in the hosts
+	// file format or with one hostname per line.
+	BlocklistURLs []string `json:"blocklist_urls"`
+	// AllowlistURLs are the URLs of user lists of hostnames not to block.
+	AllowlistURLs []string      `json:"allowlist_urls"`
+	UpdatePeriod  time.Duration `json:"update_period"`
+	// SplitRules are the domains resolved with plaintext DNS
+	// servers outside the VPN, such as LAN resolvers.
+	SplitRules []models.DNSSplitRule `json:"split_rules"`
+	// LocalRecords are the hostnames answered by the DNS server itself,
+	// in addition to the hostnames of the hosts file.
+	LocalRecords []models.DNSLocalRecord `json:"local_records"`
+	HostsFile    string                  `json:"hosts_file"`
+	// QueryLog is the query log mode, which is none, anonymized or full.
+	QueryLog string `json:"query_log"`
+	// QueryLogFile is the file path to log the queries to,
+	// and is empty to log the queries with the other logs.
+	QueryLogFile string `json:"query_log_file"`
 }
 
 func (settings *DNS) String() string {
@@ -43,23 +100,97 @@ func (settings *DNS) lines() (lines []string) {
 		return lines
 	}
 
-	lines = append(lines, indent+lastIndent+"DNS over TLS:")
+	lines = append(lines, indent+lastIndent+"Listening address: "+settings.ListeningAddress)
 
-	lines = append(lines, indent+indent+lastIndent+"Unbound:")
-	for _, line := range settings.Unbound.Lines() {
-		lines = append(lines, indent+indent+indent+line)
+	lines = append(lines, indent+lastIndent+"Hosts file: "+settings.HostsFile)
+
+	if len(settings.LocalRecords) > 0 {
+		lines = append(lines, indent+lastIndent+"Local records:")
+		for _, record := range settings.LocalRecords {
+			lines = append(lines, indent+indent+lastIndent+record.String())
+		}
+	}
+
+	if settings.QueryLog == constants.DNSQueryLogAnonymized ||
+		settings.QueryLog == constants.DNSQueryLogFull {
+		queryLog := "Query log: " + settings.QueryLog
+		if settings.QueryLogFile != "" {
+			queryLog += " to " + settings.QueryLogFile
+		}
+		lines = append(lines, indent+lastIndent+queryLog)
+	}
+
+	if len(settings.SplitRules) > 0 {
+		lines = append(lines, indent+lastIndent+"Split DNS:")
+		for _, rule := range settings.SplitRules {
+			lines = append(lines, indent+indent+lastIndent+rule.String())
+		}
+	}
+
+	if settings.DoH {
+		lines = append(lines, indent+lastIndent+"DNS over HTTPS:")
+		lines = append(lines, indent+indent+lastIndent+"Servers: "+dohServersURLs(settings.DoHServers))
+	} else {
+		lines = append(lines, indent+lastIndent+"DNS over TLS:")
+		lines = append(lines, indent+indent+lastIndent+"Providers: "+strings.Join(settings.Providers, ", "))
 	}
 
-	if settings.BlockMalicious {
-		lines = append(lines, indent+indent+lastIndent+"Block malicious: enabled")
+	if settings.Caching {
+		lines = append(lines, indent+indent+lastIndent+"Caching: enabled")
 	}
 
-	if settings.BlockAds {
-		lines = append(lines, indent+indent+lastIndent+"Block ads: enabled")
+	lines = append(lines, indent+indent+lastIndent+"Upstream strategy: "+settings.UpstreamStrategy)
+
+	lines = append(lines, indent+indent+lastIndent+"DNSSEC: "+settings.DNSSEC)
+
+	if settings.StripECS {
+		lines = append(lines, indent+indent+lastIndent+"Strip EDNS client subnet: enabled")
 	}
 
-	if settings.BlockSurveillance {
-		lines = append(lines, indent+indent+lastIndent+"Block surveillance: enabled")
+	if settings.CaseRandomization {
+		lines = append(lines, indent+indent+lastIndent+"0x20 case randomization: enabled")
+	}
+
+	if settings.IPv6 {
+		lines = append(lines, indent+indent+lastIndent+"IPv6: enabled")
+	}
+
+	if len(settings.BlockCategories) > 0 {
+		lines = append(lines, indent+indent+lastIndent+"Block categories: "+
+			strings.Join(settings.BlockCategories, ", "))
+	}
+
+	if settings.RebindingProtection {
+		lines = append(lines, indent+indent+lastIndent+"Rebinding protection: enabled")
+		if len(settings.PrivateAddresses) > 0 {
+			lines = append(lines, indent+indent+lastIndent+"Private addresses:")
+			for _, subnet := range settings.PrivateAddresses {
+				lines = append(lines, indent+indent+indent+lastIndent+subnet.String())
+			}
+		}
+		if len(settings.RebindingAllowedHostnames) > 0 {
+			lines = append(lines, indent+indent+lastIndent+"Rebinding allowed hostnames: "+
+				strings.Join(settings.RebindingAllowedHostnames, ", "))
+		}
+	}
+
+	if len(settings.AllowedHostnames) > 0 {
+		lines = append(lines, indent+indent+lastIndent+"Unblocked hostnames: "+
+			strings.Join(settings.AllowedHostnames, ", "))
+	}
+
+	if len(settings.BlocklistURLs) > 0 {
+		lines = append(lines, indent+indent+lastIndent+"Block lists:")
+		for _, listURL := range settings.BlocklistURLs {
+			lines = append(lines, indent+indent+indent+lastIndent+listURL)
+		}
+	}
+
+	if len(settings.AllowlistURLs) > 0 {
+		lines = append(lines, indent+indent+lastIndent+"Allow lists:")
+		for _, listURL := range settings.AllowlistURLs {
+			lines = append(lines, indent+indent+indent+lastIndent+listURL)
+		}
 	}
 
 	if settings.UpdatePeriod > 0 {
@@ -70,10 +201,11 @@ func (settings *DNS) lines() (lines []string) {
 }
 
 var (
-	ErrUnboundSettings   = errors.New("failed getting Unbound settings")
-	ErrDNSProviderNoData = errors.New("DNS provider has no associated data")
-	ErrDNSProviderNoTLS  = errors.New("DNS provider does not support DNS over TLS")
-	ErrDNSNoIPv6Support  = errors.New("no DNS provider supports IPv6")
+	ErrDNSOverTLSSettings   = errors.New("failed getting DNS over TLS settings")
+	ErrDNSOverHTTPSSettings = errors.New("failed getting DNS over HTTPS settings")
+	ErrDNSProviderNoData    = errors.New("DNS provider has no associated data")
+	ErrDNSProviderNoTLS     = errors.New("DNS provider does not support DNS over TLS")
+	ErrDNSNoIPv6Support     = errors.New("no DNS provider supports IPv6")
 )
 
 func (settings *DNS) read(r reader) (err error) {
@@ -91,17 +223,65 @@ func (settings *DNS) read(r reader) (err error) {
 		return err
 	}
 
-	// DNS over TLS external settings
-	settings.BlockMalicious, err = r.env.OnOff("BLOCK_MALICIOUS", params.Default("on"))
+	if err := settings.readListeningAddress(r.env); err != nil {
+		return err
+	}
+
+	if err := settings.readSplitRules(r); err != nil {
+		return err
+	}
+
+	if err := settings.readLocalRecords(r); err != nil {
+		return err
+	}
+
+	settings.UpstreamStrategy, err = r.env.Inside("DNS_UPSTREAM_STRATEGY", []string{
+		constants.DNSUpstreamRoundRobin, constants.DNSUpstreamPriority},
+		params.Default(constants.DNSUpstreamRoundRobin))
 	if err != nil {
 		return err
 	}
-	settings.BlockSurveillance, err = r.env.OnOff("BLOCK_SURVEILLANCE", params.Default("on"),
-		params.RetroKeys([]string{"BLOCK_NSA"}, r.onRetroActive))
+
+	settings.DNSSEC, err = r.env.Inside("DNSSEC", []string{
+		constants.DNSSECUpstream, constants.DNSSECPermissive, constants.DNSSECOff},
+		params.Default(constants.DNSSECUpstream))
 	if err != nil {
 		return err
 	}
-	settings.BlockAds, err = r.env.OnOff("BLOCK_ADS", params.Default("off"))
+	if settings.DNSSEC != constants.DNSSECOff && len(settings.SplitRules) > 0 {
+		r.logger.Warn("DNSSEC is not validated for the split DNS domains, " +
+			"since their servers are plaintext servers")
+	}
+
+	settings.StripECS, err = r.env.OnOff("DNS_STRIP_ECS", params.Default("on"))
+	if err != nil {
+		return err
+	}
+	settings.CaseRandomization, err = r.env.OnOff("DNS_0X20", params.Default("off"))
+	if err != nil {
+		return err
+	}
+
+	settings.QueryLog, err = r.env.Inside("DNS_QUERY_LOG", []string{
+		constants.DNSQueryLogNone, constants.DNSQueryLogAnonymized, constants.DNSQueryLogFull},
+		params.Default(constants.DNSQueryLogNone))
+	if err != nil {
+		return err
+	}
+	settings.QueryLogFile, err = r.env.Get("DNS_QUERY_LOG_FILE", params.CaseSensitiveValue())
+	if err != nil {
+		return err
+	}
+
+	// DNS over TLS external settings
+	if err := settings.readBlockCategories(r); err != nil {
+		return err
+	}
+	settings.BlocklistURLs, err = readCSVListURLs(r.env, "DNS_BLOCKLIST_URLS")
+	if err != nil {
+		return err
+	}
+	settings.AllowlistURLs, err = readCSVListURLs(r.env, "DNS_ALLOWLIST_URLS")
 	if err != nil {
 		return err
 	}
@@ -110,13 +290,20 @@ func (settings *DNS) read(r reader) (err error) {
 		return err
 	}
 
-	if err := settings.readUnbound(r); err != nil {
-		return fmt.Errorf("%w: %s", ErrUnboundSettings, err)
+	if err := settings.readDNSOverTLS(r); err != nil {
+		return fmt.Errorf("%w: %s", ErrDNSOverTLSSettings, err)
+	}
+
+	if err := settings.readDNSOverHTTPS(r.env); err != nil {
+		return fmt.Errorf("%w: %s", ErrDNSOverHTTPSSettings, err)
+	}
+	if settings.DoH {
+		settings.Enabled = true
 	}
 
 	// Consistency check
 	IPv6Support := false
-	for _, provider := range settings.Unbound.Providers {
+	for _, provider := range settings.Providers {
 		providerData, ok := unbound.GetProviderData(provider)
 		switch {
 		case !ok:
@@ -128,7 +315,7 @@ func (settings *DNS) read(r reader) (err error) {
 		}
 	}
 
-	if settings.Unbound.IPv6 && !IPv6Support {
+	if settings.IPv6 && !IPv6Support {
 		return ErrDNSNoIPv6Support
 	}
 
@@ -152,3 +339,68 @@ func (settings *DNS) readDNSPlaintext(env params.Env) error {
 
 	return nil
 }
+
+var ErrInvalidDNSListeningAddress = errors.New("invalid DNS listening address")
+
+func (settings *DNS) readListeningAddress(env params.Env) error {
+	address, err := env.Get("DNS_LISTENING_ADDRESS", params.Default("127.0.0.1:53"))
+	if err != nil {
+		return err
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidDNSListeningAddress, err)
+	} else if host != "" && net.ParseIP(host) == nil {
+		return fmt.Errorf("%w: %s: host %q is not an IP address",
+			ErrInvalidDNSListeningAddress, address, host)
+	} else if port != "53" {
+		// the system nameserver cannot be set with a port
+		return fmt.Errorf("%w: %s: port must be 53", ErrInvalidDNSListeningAddress, address)
+	}
+
+	settings.ListeningAddress = address
+	return nil
+}
+
+// ListeningIP returns the IP address the DNS server listens on,
+// which is nil if it listens on all interfaces.
+func (settings *DNS) ListeningIP() (ip net.IP) {
+	host, _, _ := net.SplitHostPort(settings.ListeningAddress)
+	ip = net.ParseIP(host)
+	if ip != nil && ip.IsUnspecified() {
+		return nil
+	}
+	return ip
+}
+
+// ServesLAN returns true if the DNS server is enabled and listens on a
+// non loopback address, so the firewall should accept DNS queries
+// from the outbound subnets.
+func (settings *DNS) ServesLAN() bool {
+	if !settings.Enabled {
+		return false
+	}
+	ip := settings.ListeningIP()
+	return ip == nil || !ip.IsLoopback()
+}
+
+var ErrInvalidListURL = errors.New("invalid list URL")
+
+func readCSVListURLs(env params.Env, key string) (urls []string, err error) {
+	urls, err = env.CSV(key, params.CaseSensitiveValue())
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range urls {
+		u, err := url.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q from environment variable %s: %s",
+				ErrInvalidListURL, s, key, err)
+		} else if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("%w: %q from environment variable %s: must be an HTTP(S) URL",
+				ErrInvalidListURL, s, key)
+		}
+	}
+	return urls, nil
+}
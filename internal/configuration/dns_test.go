@@ -5,7 +5,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/qdm12/dns/pkg/models"
+	"github.com/qdm12/gluetun/internal/models"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -26,41 +26,82 @@ func Test_DNS_Lines(t *testing.T) {
 		},
 		"enabled DOT": {
 			settings: DNS{
-				Enabled:        true,
-				KeepNameserver: true,
-				Unbound: models.Settings{
-					Providers: []string{"cloudflare"},
+				Enabled:             true,
+				ListeningAddress:    ":53",
+				HostsFile:           "/gluetun/hosts",
+				KeepNameserver:      true,
+				Providers:           []string{"cloudflare", "quad9"},
+				Caching:             true,
+				DNSSEC:              "permissive",
+				StripECS:            true,
+				CaseRandomization:   true,
+				UpstreamStrategy:    "priority",
+				BlockCategories:     []string{"malicious", "ads", "surveillance"},
+				RebindingProtection: true,
+				PrivateAddresses: []net.IPNet{
+					{IP: net.IP{10, 0, 0, 0}, Mask: net.IPv4Mask(255, 0, 0, 0)},
 				},
-				BlockMalicious:    true,
-				BlockAds:          true,
-				BlockSurveillance: true,
-				UpdatePeriod:      time.Hour,
+				RebindingAllowedHostnames: []string{"nas.example.com"},
+				AllowedHostnames:          []string{"github.com"},
+				UpdatePeriod:              time.Hour,
 			},
 			lines: []string{
 				"|--DNS:",
 				"   |--Keep nameserver (disabled blocking): yes",
+				"   |--Listening address: :53",
+				"   |--Hosts file: /gluetun/hosts",
 				"   |--DNS over TLS:",
-				"      |--Unbound:",
-				"          |--DNS over TLS providers:",
-				"              |--cloudflare",
-				"          |--Listening port: 0",
-				"          |--Access control:",
-				"              |--Allowed:",
-				"          |--Caching: disabled",
-				"          |--IPv4 resolution: disabled",
-				"          |--IPv6 resolution: disabled",
-				"          |--Verbosity level: 0/5",
-				"          |--Verbosity details level: 0/4",
-				"          |--Validation log level: 0/2",
-				"          |--Blocked hostnames:",
-				"          |--Blocked IP addresses:",
-				"          |--Allowed hostnames:",
-				"      |--Block malicious: enabled",
-				"      |--Block ads: enabled",
-				"      |--Block surveillance: enabled",
+				"      |--Providers: cloudflare, quad9",
+				"      |--Caching: enabled",
+				"      |--Upstream strategy: priority",
+				"      |--DNSSEC: permissive",
+				"      |--Strip EDNS client subnet: enabled",
+				"      |--0x20 case randomization: enabled",
+				"      |--Block categories: malicious, ads, surveillance",
+				"      |--Rebinding protection: enabled",
+				"      |--Private addresses:",
+				"         |--10.0.0.0/8",
+				"      |--Rebinding allowed hostnames: nas.example.com",
+				"      |--Unblocked hostnames: github.com",
 				"      |--Update: every 1h0m0s",
 			},
 		},
+		"enabled DOH": {
+			settings: DNS{
+				Enabled:          true,
+				ListeningAddress: "0.0.0.0:53",
+				HostsFile:        "/gluetun/hosts",
+				QueryLog:         "anonymized",
+				QueryLogFile:     "/gluetun/queries.log",
+				LocalRecords: []models.DNSLocalRecord{
+					{Hostname: "nas.home", IP: net.IP{192, 168, 1, 10}},
+				},
+				SplitRules: []models.DNSSplitRule{
+					{Domain: "lan", IP: net.IP{192, 168, 1, 1}, Port: 53},
+				},
+				DoH:              true,
+				DNSSEC:           "upstream",
+				UpstreamStrategy: "round-robin",
+				DoHServers: []models.DoHServer{
+					{URL: "https://dns.google/dns-query"},
+					{URL: "https://doh.example.com/dns-query"},
+				},
+			},
+			lines: []string{
+				"|--DNS:",
+				"   |--Listening address: 0.0.0.0:53",
+				"   |--Hosts file: /gluetun/hosts",
+				"   |--Local records:",
+				"      |--nas.home -> 192.168.1.10",
+				"   |--Query log: anonymized to /gluetun/queries.log",
+				"   |--Split DNS:",
+				"      |--lan -> 192.168.1.1:53",
+				"   |--DNS over HTTPS:",
+				"      |--Servers: https://dns.google/dns-query, https://doh.example.com/dns-query",
+				"      |--Upstream strategy: round-robin",
+				"      |--DNSSEC: upstream",
+			},
+		},
 	}
 	for name, testCase := range testCases {
 		testCase := testCase
@@ -71,3 +112,57 @@ func Test_DNS_Lines(t *testing.T) {
 		})
 	}
 }
+
+func Test_DNS_ListeningIP(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]net.IP{
+		":53":          nil,
+		"0.0.0.0:53":   nil,
+		"[::]:53":      nil,
+		"127.0.0.1:53": net.IPv4(127, 0, 0, 1),
+		"[::1]:53":     net.IPv6loopback,
+	}
+	for address, ip := range testCases {
+		address, ip := address, ip
+		t.Run(address, func(t *testing.T) {
+			t.Parallel()
+			settings := DNS{ListeningAddress: address}
+			assert.Equal(t, ip, settings.ListeningIP())
+		})
+	}
+}
+
+func Test_DNS_ServesLAN(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		settings  DNS
+		servesLAN bool
+	}{
+		"disabled": {
+			settings: DNS{ListeningAddress: "0.0.0.0:53"},
+		},
+		"default loopback address": {
+			settings: DNS{Enabled: true, ListeningAddress: "127.0.0.1:53"},
+		},
+		"IPv6 loopback address": {
+			settings: DNS{Enabled: true, ListeningAddress: "[::1]:53"},
+		},
+		"all interfaces": {
+			settings:  DNS{Enabled: true, ListeningAddress: ":53"},
+			servesLAN: true,
+		},
+		"LAN address": {
+			settings:  DNS{Enabled: true, ListeningAddress: "192.168.1.2:53"},
+			servesLAN: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, testCase.servesLAN, testCase.settings.ServesLAN())
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package configuration
+
+import (
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/golibs/params"
+)
+
+func (settings *DNS) readBlockCategories(r reader) (err error) {
+	legacySet, err := settings.readLegacyBlockCategories(r)
+	if err != nil || legacySet {
+		return err
+	}
+
+	settings.BlockCategories, err = r.env.CSVInside("DNS_BLOCK_CATEGORIES",
+		constants.DNSBlockCategories())
+	return err
+}
+
+// readLegacyBlockCategories reads the block categories from the old
+// on/off environment variables of each category, if one of them is set.
+func (settings *DNS) readLegacyBlockCategories(r reader) (set bool, err error) {
+	legacyCategories := []struct {
+		key          string
+		retroKeys    []string
+		category     string
+		defaultValue string
+	}{
+		{key: "BLOCK_MALICIOUS", category: constants.DNSBlockMalicious, defaultValue: "on"},
+		{key: "BLOCK_SURVEILLANCE", retroKeys: []string{"BLOCK_NSA"},
+			category: constants.DNSBlockSurveillance, defaultValue: "off"},
+		{key: "BLOCK_ADS", category: constants.DNSBlockAds, defaultValue: "off"},
+	}
+
+	for _, legacy := range legacyCategories {
+		for _, key := range append([]string{legacy.key}, legacy.retroKeys...) {
+			value, err := r.env.Get(key)
+			if err != nil {
+				return false, err
+			} else if value != "" {
+				set = true
+			}
+		}
+	}
+	if !set {
+		return false, nil
+	}
+
+	for _, legacy := range legacyCategories {
+		r.onRetroActive(legacy.key, "DNS_BLOCK_CATEGORIES")
+		enabled, err := r.env.OnOff(legacy.key, params.Default(legacy.defaultValue),
+			params.RetroKeys(legacy.retroKeys, r.onRetroActive))
+		if err != nil {
+			return false, err
+		} else if enabled {
+			settings.BlockCategories = append(settings.BlockCategories, legacy.category)
+		}
+	}
+	return true, nil
+}
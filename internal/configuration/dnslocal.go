@@ -0,0 +1,59 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/params"
+)
+
+var (
+	ErrInvalidDNSLocalRecord = errors.New("invalid local DNS record")
+	ErrDNSLocalRecordFormat  = errors.New("local DNS record must be in the format hostname=ip")
+)
+
+func (settings *DNS) readLocalRecords(r reader) (err error) {
+	settings.HostsFile, err = r.env.Path("DNS_HOSTS_FILE",
+		params.Default(constants.DNSHosts))
+	if err != nil {
+		return err
+	}
+
+	values, err := r.env.CSV("DNS_LOCAL_RECORDS")
+	if err != nil || len(values) == 0 {
+		return err
+	}
+
+	settings.LocalRecords = make([]models.DNSLocalRecord, len(values))
+	for i, value := range values {
+		settings.LocalRecords[i], err = parseDNSLocalRecord(value)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %s", ErrInvalidDNSLocalRecord, value, err)
+		} else if !r.regex.MatchHostname(settings.LocalRecords[i].Hostname) {
+			return fmt.Errorf("%w: %s: %s", ErrInvalidDNSLocalRecord, value, ErrInvalidHostname)
+		}
+	}
+
+	return nil
+}
+
+// parseDNSLocalRecord parses a local DNS record in the format hostname=ip.
+func parseDNSLocalRecord(s string) (record models.DNSLocalRecord, err error) {
+	parts := strings.Split(s, "=")
+	const expectedParts = 2
+	if len(parts) != expectedParts {
+		return record, ErrDNSLocalRecordFormat
+	}
+
+	record.Hostname = strings.TrimSuffix(parts[0], ".")
+	record.IP = net.ParseIP(parts[1])
+	if record.IP == nil {
+		return record, fmt.Errorf("%w: %s", ErrInvalidIP, parts[1])
+	}
+
+	return record, nil
+}
@@ -0,0 +1,26 @@
+package configuration
+
+import (
+	"fmt"
+
+	"github.com/qdm12/golibs/params"
+)
+
+func (settings *DNS) readRebindingProtection(r reader) (err error) {
+	settings.RebindingProtection, err = r.env.OnOff("DNS_REBINDING_PROTECTION", params.Default("on"))
+	if err != nil || !settings.RebindingProtection {
+		return err
+	}
+
+	hostnames, err := r.env.CSV("DNS_REBINDING_ALLOWED_HOSTNAMES")
+	if err != nil {
+		return err
+	}
+	for _, hostname := range hostnames {
+		if !r.regex.MatchHostname(hostname) {
+			return fmt.Errorf("%w: %s", ErrInvalidHostname, hostname)
+		}
+	}
+	settings.RebindingAllowedHostnames = hostnames
+	return nil
+}
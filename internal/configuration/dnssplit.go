@@ -0,0 +1,76 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+var (
+	ErrInvalidDNSSplitRule = errors.New("invalid split DNS rule")
+	ErrDNSSplitRuleFormat  = errors.New("split DNS rule must be in the format domain=ip[:port]")
+)
+
+func (settings *DNS) readSplitRules(r reader) (err error) {
+	values, err := r.env.CSV("DNS_SPLIT")
+	if err != nil || len(values) == 0 {
+		return err
+	}
+
+	settings.SplitRules = make([]models.DNSSplitRule, len(values))
+	for i, value := range values {
+		settings.SplitRules[i], err = parseDNSSplitRule(value)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %s", ErrInvalidDNSSplitRule, value, err)
+		} else if !r.regex.MatchHostname(settings.SplitRules[i].Domain) {
+			return fmt.Errorf("%w: %s: %s", ErrInvalidDNSSplitRule, value, ErrInvalidHostname)
+		}
+	}
+
+	return nil
+}
+
+// parseDNSSplitRule parses a split DNS rule in the format domain=ip
+// or domain=ip:port, where the domain can be prefixed with "*.".
+func parseDNSSplitRule(s string) (rule models.DNSSplitRule, err error) {
+	parts := strings.Split(s, "=")
+	const expectedParts = 2
+	if len(parts) != expectedParts {
+		return rule, ErrDNSSplitRuleFormat
+	}
+	domain, address := parts[0], parts[1]
+
+	domain = strings.TrimPrefix(domain, "*.")
+	rule.Domain = strings.Trim(domain, ".")
+
+	const defaultPort = 53
+	rule.Port = defaultPort
+	host := address
+	if ip := net.ParseIP(strings.Trim(address, "[]")); ip != nil {
+		host = ip.String()
+	} else {
+		var portStr string
+		host, portStr, err = net.SplitHostPort(address)
+		if err != nil {
+			return rule, fmt.Errorf("%w: %s", ErrInvalidIP, address)
+		}
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			return rule, err
+		} else if port <= 0 || port > 65535 {
+			return rule, fmt.Errorf("%w: %d: must be between 1 and 65535", ErrInvalidPort, port)
+		}
+		rule.Port = uint16(port)
+	}
+
+	rule.IP = net.ParseIP(host)
+	if rule.IP == nil {
+		return rule, fmt.Errorf("%w: %s", ErrInvalidIP, host)
+	}
+
+	return rule, nil
+}
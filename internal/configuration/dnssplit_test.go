@@ -0,0 +1,61 @@
+package configuration
+
+import (
+	"net"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseDNSSplitRule(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		s    string
+		rule models.DNSSplitRule
+		err  error
+	}{
+		"missing address": {
+			s:   "lan",
+			err: ErrDNSSplitRuleFormat,
+		},
+		"invalid IP": {
+			s:   "lan=router",
+			err: ErrInvalidIP,
+		},
+		"invalid port": {
+			s:   "lan=192.168.1.1:0",
+			err: ErrInvalidPort,
+		},
+		"wildcard domain": {
+			s:    "*.lan=192.168.1.1",
+			rule: models.DNSSplitRule{Domain: "lan", IP: net.ParseIP("192.168.1.1"), Port: 53},
+		},
+		"IPv4 with port": {
+			s:    "corp.example.com=10.0.0.53:5353",
+			rule: models.DNSSplitRule{Domain: "corp.example.com", IP: net.ParseIP("10.0.0.53"), Port: 5353},
+		},
+		"IPv6": {
+			s:    "lan=fd00::1",
+			rule: models.DNSSplitRule{Domain: "lan", IP: net.ParseIP("fd00::1"), Port: 53},
+		},
+		"IPv6 with port": {
+			s:    "lan=[fd00::1]:5353",
+			rule: models.DNSSplitRule{Domain: "lan", IP: net.ParseIP("fd00::1"), Port: 5353},
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			rule, err := parseDNSSplitRule(testCase.s)
+			if testCase.err != nil {
+				assert.ErrorIs(t, err, testCase.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.rule, rule)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/params"
+)
+
+var (
+	ErrInvalidDoHProvider     = errors.New("invalid DNS over HTTPS provider")
+	ErrInvalidDoHURL          = errors.New("invalid DNS over HTTPS URL")
+	ErrDoHBootstrapIPsMissing = errors.New("DNS over HTTPS bootstrap IP addresses are missing")
+)
+
+func (settings *DNS) readDNSOverHTTPS(env params.Env) (err error) {
+	settings.DoH, err = env.OnOff("DOH", params.Default("off"))
+	if err != nil || !settings.DoH {
+		return err
+	}
+
+	providers, err := env.CSV("DOH_PROVIDERS", params.Default("cloudflare"))
+	if err != nil {
+		return err
+	}
+
+	servers := constants.DoHServers()
+	for _, provider := range providers {
+		if provider == constants.DoHCustom {
+			server, err := readCustomDoHServer(env)
+			if err != nil {
+				return err
+			}
+			settings.DoHServers = append(settings.DoHServers, server)
+			continue
+		}
+
+		server, ok := servers[provider]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrInvalidDoHProvider, provider)
+		}
+		settings.DoHServers = append(settings.DoHServers, server)
+	}
+
+	return nil
+}
+
+// readCustomDoHServer reads the custom DNS over HTTPS URL, and the IP
+// addresses to reach its hostname if the hostname is not an IP address.
+func readCustomDoHServer(env params.Env) (server models.DoHServer, err error) {
+	server.URL, err = env.Get("DOH_URL", params.Compulsory(), params.CaseSensitiveValue())
+	if err != nil {
+		return server, err
+	}
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		return server, fmt.Errorf("%w: %s", ErrInvalidDoHURL, err)
+	} else if u.Scheme != "https" || u.Hostname() == "" {
+		return server, fmt.Errorf("%w: %s: must be https://host/path", ErrInvalidDoHURL, server.URL)
+	}
+
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		server.IPs = []net.IP{ip}
+		return server, nil
+	}
+
+	ips, err := env.CSV("DOH_BOOTSTRAP_IPS")
+	if err != nil {
+		return server, err
+	} else if len(ips) == 0 {
+		return server, fmt.Errorf("%w: for hostname %s", ErrDoHBootstrapIPsMissing, u.Hostname())
+	}
+	for _, s := range ips {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return server, fmt.Errorf("%w: %s", ErrInvalidIP, s)
+		}
+		server.IPs = append(server.IPs, ip)
+	}
+
+	return server, nil
+}
+
+func dohServersURLs(servers []models.DoHServer) string {
+	urls := make([]string, len(servers))
+	for i, server := range servers {
+		urls[i] = server.URL
+	}
+	return strings.Join(urls, ", ")
+}
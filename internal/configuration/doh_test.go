@@ -0,0 +1,80 @@
+package configuration
+
+import (
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/params/mock_params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DNS_readDNSOverHTTPS(t *testing.T) {
+	t.Parallel()
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		env := mock_params.NewMockEnv(ctrl)
+		env.EXPECT().OnOff("DOH", gomock.Any()).Return(false, nil)
+
+		var settings DNS
+		err := settings.readDNSOverHTTPS(env)
+
+		require.NoError(t, err)
+		assert.Equal(t, DNS{}, settings)
+	})
+
+	t.Run("invalid provider", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		env := mock_params.NewMockEnv(ctrl)
+		env.EXPECT().OnOff("DOH", gomock.Any()).Return(true, nil)
+		env.EXPECT().CSV("DOH_PROVIDERS", gomock.Any()).Return([]string{"unknown"}, nil)
+
+		var settings DNS
+		err := settings.readDNSOverHTTPS(env)
+
+		assert.ErrorIs(t, err, ErrInvalidDoHProvider)
+	})
+
+	t.Run("custom URL without bootstrap IP", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		env := mock_params.NewMockEnv(ctrl)
+		env.EXPECT().OnOff("DOH", gomock.Any()).Return(true, nil)
+		env.EXPECT().CSV("DOH_PROVIDERS", gomock.Any()).Return([]string{"custom"}, nil)
+		env.EXPECT().Get("DOH_URL", gomock.Any(), gomock.Any()).
+			Return("https://doh.example.com/dns-query", nil)
+		env.EXPECT().CSV("DOH_BOOTSTRAP_IPS").Return(nil, nil)
+
+		var settings DNS
+		err := settings.readDNSOverHTTPS(env)
+
+		assert.ErrorIs(t, err, ErrDoHBootstrapIPsMissing)
+	})
+
+	t.Run("provider and custom URL", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		env := mock_params.NewMockEnv(ctrl)
+		env.EXPECT().OnOff("DOH", gomock.Any()).Return(true, nil)
+		env.EXPECT().CSV("DOH_PROVIDERS", gomock.Any()).Return([]string{"quad9", "custom"}, nil)
+		env.EXPECT().Get("DOH_URL", gomock.Any(), gomock.Any()).
+			Return("https://doh.example.com/dns-query", nil)
+		env.EXPECT().CSV("DOH_BOOTSTRAP_IPS").Return([]string{"1.2.3.4"}, nil)
+
+		var settings DNS
+		err := settings.readDNSOverHTTPS(env)
+
+		require.NoError(t, err)
+		require.Len(t, settings.DoHServers, 2)
+		assert.Equal(t, "https://dns.quad9.net/dns-query", settings.DoHServers[0].URL)
+		assert.Equal(t, models.DoHServer{
+			URL: "https://doh.example.com/dns-query",
+			IPs: []net.IP{net.ParseIP("1.2.3.4")},
+		}, settings.DoHServers[1])
+	})
+}
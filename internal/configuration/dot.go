@@ -0,0 +1,115 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	unbound "github.com/qdm12/dns/pkg/unbound"
+	"github.com/qdm12/golibs/params"
+)
+
+func (settings *DNS) readDNSOverTLS(r reader) (err error) {
+	if err := warnRemovedUnboundKeys(r); err != nil {
+		return err
+	}
+
+	if err := settings.readDNSOverTLSProviders(r.env); err != nil {
+		return err
+	}
+
+	settings.Caching, err = r.env.OnOff("DOT_CACHING", params.Default("on"))
+	if err != nil {
+		return err
+	}
+
+	settings.IPv6, err = r.env.OnOff("DOT_IPV6", params.Default("off"))
+	if err != nil {
+		return err
+	}
+
+	if err := settings.readPrivateAddresses(r.env); err != nil {
+		return err
+	}
+
+	if err := settings.readRebindingProtection(r); err != nil {
+		return err
+	}
+
+	return settings.readUnblockedHostnames(r)
+}
+
+// warnRemovedUnboundKeys warns about the environment variables
+// configuring Unbound logs, which are ignored since Unbound was
+// replaced by the built-in DNS server.
+func warnRemovedUnboundKeys(r reader) (err error) {
+	keys := []string{"DOT_VERBOSITY", "DOT_VERBOSITY_DETAILS", "DOT_VALIDATION_LOGLEVEL"}
+	for _, key := range keys {
+		value, err := r.env.Get(key)
+		if err != nil {
+			return err
+		} else if value != "" {
+			r.logger.Warn("The environment variable %s is no longer used and can be removed, "+
+				"please use DNS_QUERY_LOG to log DNS queries instead", key)
+		}
+	}
+	return nil
+}
+
+var (
+	ErrInvalidDNSOverTLSProvider = errors.New("invalid DNS over TLS provider")
+)
+
+func (settings *DNS) readDNSOverTLSProviders(env params.Env) (err error) {
+	s, err := env.Get("DOT_PROVIDERS", params.Default("cloudflare"))
+	if err != nil {
+		return err
+	}
+	for _, provider := range strings.Split(s, ",") {
+		_, ok := unbound.GetProviderData(provider)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrInvalidDNSOverTLSProvider, provider)
+		}
+		settings.Providers = append(settings.Providers, provider)
+	}
+	return nil
+}
+
+var (
+	ErrInvalidPrivateAddress = errors.New("private address is not a valid IP or CIDR range")
+)
+
+func (settings *DNS) readPrivateAddresses(env params.Env) (err error) {
+	privateAddresses, err := env.CSV("DOT_PRIVATE_ADDRESS")
+	if err != nil {
+		return err
+	}
+	for _, address := range privateAddresses {
+		subnet, err := parseIPOrCIDR(address)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidPrivateAddress, address)
+		}
+		settings.PrivateAddresses = append(settings.PrivateAddresses, subnet)
+	}
+	return nil
+}
+
+var (
+	ErrInvalidHostname = errors.New("invalid hostname")
+)
+
+func (settings *DNS) readUnblockedHostnames(r reader) (err error) {
+	hostnames, err := r.env.CSV("UNBLOCK")
+	if err != nil {
+		return err
+	} else if len(hostnames) == 0 {
+		return nil
+	}
+	for _, hostname := range hostnames {
+		if !r.regex.MatchHostname(hostname) {
+			return fmt.Errorf("%w: %s", ErrInvalidHostname, hostname)
+		}
+	}
+	settings.AllowedHostnames = append(settings.AllowedHostnames, hostnames...)
+	return nil
+}
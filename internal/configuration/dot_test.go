@@ -0,0 +1,64 @@
+package configuration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/qdm12/golibs/params/mock_params"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_warnRemovedUnboundKeys(t *testing.T) {
+	t.Parallel()
+
+	errDummy := errors.New("dummy")
+
+	testCases := map[string]struct {
+		values   map[string]string
+		getErr   error
+		warnings []string
+		err      error
+	}{
+		"no key set": {},
+		"keys set": {
+			values: map[string]string{
+				"DOT_VERBOSITY":           "1",
+				"DOT_VALIDATION_LOGLEVEL": "0",
+			},
+			warnings: []string{"DOT_VERBOSITY", "DOT_VALIDATION_LOGLEVEL"},
+		},
+		"get error": {
+			getErr: errDummy,
+			err:    errDummy,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			env := mock_params.NewMockEnv(ctrl)
+			if testCase.getErr != nil {
+				env.EXPECT().Get("DOT_VERBOSITY").Return("", testCase.getErr)
+			} else {
+				for _, key := range []string{"DOT_VERBOSITY", "DOT_VERBOSITY_DETAILS", "DOT_VALIDATION_LOGLEVEL"} {
+					env.EXPECT().Get(key).Return(testCase.values[key], nil)
+				}
+			}
+			logger := mock_logging.NewMockLogger(ctrl)
+			for _, key := range testCase.warnings {
+				logger.EXPECT().Warn("The environment variable %s is no longer used and can be removed, "+
+					"please use DNS_QUERY_LOG to log DNS queries instead", key)
+			}
+			r := reader{env: env, logger: logger}
+
+			err := warnRemovedUnboundKeys(r)
+
+			assert.ErrorIs(t, err, testCase.err)
+		})
+	}
+}
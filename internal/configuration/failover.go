@@ -0,0 +1,65 @@
+package configuration
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qdm12/golibs/params"
+)
+
+// Failover contains settings to fail over to the backup VPN connection.
+type Failover struct {
+	// Threshold is the duration the primary connection must stay
+	// unhealthy for before failing over to the backup connection.
+	Threshold time.Duration `json:"threshold"`
+	// FailbackPeriod is the period at which a server of the primary
+	// provider is probed once failed over to the backup connection,
+	// to fail back to the primary connection once it is reachable.
+	FailbackPeriod time.Duration `json:"failback_period"`
+}
+
+func (f *Failover) lines() (lines []string) {
+	return []string{
+		lastIndent + "Failover threshold: " + f.Threshold.String(),
+		lastIndent + "Failback period: " + f.FailbackPeriod.String(),
+	}
+}
+
+func (f *Failover) read(env params.Env) (err error) {
+	f.Threshold, err = env.Duration("FAILOVER_THRESHOLD", params.Default("2m"))
+	if err != nil {
+		return err
+	}
+
+	f.FailbackPeriod, err = env.Duration("FAILBACK_PERIOD", params.Default("30m"))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const backupPrefix = "BACKUP_"
+
+// readBackup reads the backup VPN settings from the environment variables
+// prefixed with BACKUP_, if BACKUP_VPNSP is set.
+func (settings *OpenVPN) readBackup(r reader) (err error) {
+	if r.prefix != "" { // no backup of the backup
+		return nil
+	}
+
+	backupReader := r.withPrefix(backupPrefix)
+	vpnsp, err := backupReader.env.Get("VPNSP")
+	if err != nil {
+		return err
+	} else if vpnsp == "" {
+		return nil
+	}
+
+	settings.Backup = new(OpenVPN)
+	if err := settings.Backup.read(backupReader); err != nil {
+		return fmt.Errorf("cannot read backup VPN settings: %w", err)
+	}
+
+	return settings.Failover.read(r.env)
+}
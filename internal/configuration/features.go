@@ -0,0 +1,30 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/golibs/params"
+)
+
+var ErrServerFeatureNotSupported = errors.New("server feature is not supported")
+
+// readServerFeatures reads the server features to filter servers by,
+// which must be supported by the VPN provider given.
+func readServerFeatures(env params.Env, providerName string) (features []string, err error) {
+	features, err = env.CSVInside("SERVER_FEATURES", constants.ServerFeatureChoices())
+	if err != nil {
+		return nil, err
+	}
+
+	capabilities := constants.ProviderCapabilities()[providerName]
+	for _, feature := range features {
+		if !capabilities.HasServerFeature(feature) {
+			return nil, fmt.Errorf("%w: %s for VPN provider %s",
+				ErrServerFeatureNotSupported, feature, providerName)
+		}
+	}
+
+	return features, nil
+}
@@ -0,0 +1,64 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/golibs/params/mock_params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_readServerFeatures(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		providerName string
+		mockFeatures []string
+		features     []string
+		err          error
+	}{
+		"no feature": {
+			providerName: constants.Cyberghost,
+		},
+		"supported features": {
+			providerName: constants.Windscribe,
+			mockFeatures: []string{constants.P2P, constants.Streaming},
+			features:     []string{constants.P2P, constants.Streaming},
+		},
+		"no feature for Mullvad": {
+			providerName: constants.Mullvad,
+			mockFeatures: []string{constants.Streaming},
+			err:          ErrServerFeatureNotSupported,
+		},
+		"unsupported feature": {
+			providerName: constants.Nordvpn,
+			mockFeatures: []string{constants.Streaming},
+			err:          ErrServerFeatureNotSupported,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			env := mock_params.NewMockEnv(ctrl)
+			env.EXPECT().
+				CSVInside("SERVER_FEATURES", constants.ServerFeatureChoices()).
+				Return(testCase.mockFeatures, nil)
+
+			features, err := readServerFeatures(env, testCase.providerName)
+
+			if testCase.err != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, testCase.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.features, features)
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package configuration
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrConfigFileExtension = errors.New("configuration file extension is not supported")
+	ErrConfigFileValue     = errors.New("configuration file value is not valid")
+)
+
+// FileKeys maps the environment variables set from a
+// configuration file to the file keys setting them.
+type FileKeys map[string]string
+
+// Environment holds the environment variables of the program as they
+// are when it starts, before any configuration file or environment
+// default is applied, to know which ones are set explicitly.
+type Environment map[string]string
+
+// CaptureEnvironment returns the current environment variables.
+func CaptureEnvironment() (environment Environment) {
+	keyValues := os.Environ()
+	environment = make(Environment, len(keyValues))
+	for _, keyValue := range keyValues {
+		parts := strings.SplitN(keyValue, "=", 2) //nolint:gomnd
+		if len(parts) == 2 {                      //nolint:gomnd
+			environment[parts[0]] = parts[1]
+		}
+	}
+	return environment
+}
+
+// LookupEnv returns the value of the environment variable given
+// and whether it was set when the environment was captured.
+func (e Environment) LookupEnv(key string) (value string, set bool) {
+	value, set = e[key]
+	return value, set
+}
+
+// LoadFile reads the TOML or YAML configuration file at the path given,
+// depending on its extension, and sets the environment variables of its
+// keys. The environment variable of a key is the key with its parent
+// keys joined with underscores in uppercase, for example the key user
+// in the table openvpn sets OPENVPN_USER. Environment variables set in
+// the environment captured at start take precedence over the file.
+// Booleans are set as on or off and arrays as comma separated values.
+// The previous keys are the keys returned when loading the file before,
+// if it is loaded again: their environment variables are overridden,
+// and unset if their key is no longer in the file, such that applying
+// the environment defaults afterwards resets them to their default.
+func LoadFile(path string, environment Environment, previous FileKeys) (keys FileKeys, err error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read configuration file: %w", err)
+	}
+
+	var tree map[string]interface{}
+	switch extension := strings.ToLower(filepath.Ext(path)); extension {
+	case ".toml":
+		err = toml.Unmarshal(content, &tree)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &tree)
+	default:
+		return nil, fmt.Errorf("%w: %q must be one of .toml, .yaml or .yml",
+			ErrConfigFileExtension, extension)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse configuration file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	keys = make(FileKeys)
+	if err := flattenFileTree(tree, "", values, keys); err != nil {
+		return nil, fmt.Errorf("configuration file %s: %w", path, err)
+	}
+
+	for envKey, value := range values {
+		if _, set := environment.LookupEnv(envKey); set {
+			delete(keys, envKey)
+			continue
+		}
+		if err := os.Setenv(envKey, value); err != nil {
+			return nil, err
+		}
+	}
+
+	for envKey := range previous {
+		if _, ok := keys[envKey]; ok {
+			continue
+		}
+		if err := os.Unsetenv(envKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+// ApplyEnvDefaults sets the environment variables which are not set
+// to their default value found in the environment defaults file at
+// the path given. It does nothing if the file does not exist.
+func ApplyEnvDefaults(path string) (err error) {
+	defaults, err := readEnvDefaults(path)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range defaults {
+		if _, set := os.LookupEnv(key); set {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flattenFileTree sets the values of the tree given in the values map
+// and their file keys in the keys map, both indexed by environment
+// variable, with the file key prefix given.
+func flattenFileTree(tree map[string]interface{}, prefix string,
+	values map[string]string, keys FileKeys) (err error) {
+	for key, value := range tree {
+		fileKey := prefix + key
+		if child, ok := value.(map[string]interface{}); ok {
+			if err := flattenFileTree(child, fileKey+".", values, keys); err != nil {
+				return err
+			}
+			continue
+		}
+
+		envKey := strings.ToUpper(strings.ReplaceAll(fileKey, ".", "_"))
+		if otherKey, exists := keys[envKey]; exists {
+			return fmt.Errorf("%w: keys %s and %s both set %s",
+				ErrConfigFileValue, otherKey, fileKey, envKey)
+		}
+
+		if array, ok := value.([]interface{}); ok {
+			elements := make([]string, len(array))
+			for i, element := range array {
+				elements[i], err = fileValueString(element)
+				if err != nil {
+					return fmt.Errorf("%w: key %s: %s", ErrConfigFileValue, fileKey, err)
+				}
+			}
+			values[envKey] = strings.Join(elements, ",")
+		} else if values[envKey], err = fileValueString(value); err != nil {
+			return fmt.Errorf("%w: key %s: %s", ErrConfigFileValue, fileKey, err)
+		}
+		keys[envKey] = fileKey
+	}
+	return nil
+}
+
+func fileValueString(value interface{}) (s string, err error) {
+	switch value := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	case bool:
+		if value {
+			return "on", nil
+		}
+		return "off", nil
+	case int:
+		return strconv.Itoa(value), nil
+	case int64:
+		return strconv.FormatInt(value, 10), nil //nolint:gomnd
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("value type %T is not supported", value)
+	}
+}
+
+// readEnvDefaults reads the environment variables default values
+// from the file given, which has one key=value pair per line. It
+// returns an empty map if the file does not exist.
+func readEnvDefaults(path string) (defaults map[string]string, err error) {
+	defaults = make(map[string]string)
+	content, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return defaults, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot read environment defaults: %w", err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), "=", 2) //nolint:gomnd
+		if len(parts) == 2 {                            //nolint:gomnd
+			defaults[parts[0]] = parts[1]
+		}
+	}
+	return defaults, scanner.Err()
+}
+
+// WrapError adds the configuration file key and path to the settings
+// error given if it is about an environment variable set from the
+// configuration file at the path given.
+func (k FileKeys) WrapError(err error, path string) error {
+	if err == nil || len(k) == 0 {
+		return err
+	}
+	message := err.Error()
+	for envKey, fileKey := range k {
+		if strings.Contains(message, `"`+envKey+`"`) {
+			return fmt.Errorf("%w (key %s in configuration file %s)", err, fileKey, path)
+		}
+	}
+	return err
+}
@@ -0,0 +1,321 @@
+package configuration
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_flattenFileTree(t *testing.T) {
+	t.Parallel()
+
+	const tomlContent = `
+vpnsp = "mullvad" # comment
+firewall_vpn_input_ports = [1000, 2000]
+
+[openvpn]
+user = "a#b"
+mssfix = 1400
+ipv6 = true
+password = """
+multi
+line"""
+
+[health]
+leak = { check = false, check_subnets = [
+  "1.2.3.0/24",
+  '5.6.7.0/24',
+] }
+`
+	const yamlContent = `
+vpnsp: mullvad # comment
+firewall_vpn_input_ports: [1000, 2000]
+openvpn:
+  user: "a#b"
+  mssfix: 1400
+  ipv6: true
+  password: |-
+    multi
+    line
+health:
+  leak:
+    check: false
+    check_subnets:
+      - 1.2.3.0/24
+      - 5.6.7.0/24
+`
+	expectedValues := map[string]string{
+		"VPNSP":                     "mullvad",
+		"FIREWALL_VPN_INPUT_PORTS":  "1000,2000",
+		"OPENVPN_USER":              "a#b",
+		"OPENVPN_MSSFIX":            "1400",
+		"OPENVPN_IPV6":              "on",
+		"OPENVPN_PASSWORD":          "multi\nline",
+		"HEALTH_LEAK_CHECK":         "off",
+		"HEALTH_LEAK_CHECK_SUBNETS": "1.2.3.0/24,5.6.7.0/24",
+	}
+	expectedKeys := FileKeys{
+		"VPNSP":                     "vpnsp",
+		"FIREWALL_VPN_INPUT_PORTS":  "firewall_vpn_input_ports",
+		"OPENVPN_USER":              "openvpn.user",
+		"OPENVPN_MSSFIX":            "openvpn.mssfix",
+		"OPENVPN_IPV6":              "openvpn.ipv6",
+		"OPENVPN_PASSWORD":          "openvpn.password",
+		"HEALTH_LEAK_CHECK":         "health.leak.check",
+		"HEALTH_LEAK_CHECK_SUBNETS": "health.leak.check_subnets",
+	}
+
+	var tomlTree map[string]interface{}
+	err := toml.Unmarshal([]byte(tomlContent), &tomlTree)
+	require.NoError(t, err)
+	var yamlTree map[string]interface{}
+	err = yaml.Unmarshal([]byte(yamlContent), &yamlTree)
+	require.NoError(t, err)
+
+	for name, tree := range map[string]map[string]interface{}{"toml": tomlTree, "yaml": yamlTree} {
+		values := make(map[string]string)
+		keys := make(FileKeys)
+		err := flattenFileTree(tree, "", values, keys)
+		require.NoError(t, err, name)
+		assert.Equal(t, expectedValues, values, name)
+		assert.Equal(t, expectedKeys, keys, name)
+	}
+}
+
+func Test_flattenFileTree_arrayOfTables(t *testing.T) {
+	t.Parallel()
+
+	var tree map[string]interface{}
+	err := toml.Unmarshal([]byte("[[servers]]\nname = \"a\""), &tree)
+	require.NoError(t, err)
+
+	err = flattenFileTree(tree, "", make(map[string]string), make(FileKeys))
+	assert.ErrorIs(t, err, ErrConfigFileValue)
+	assert.EqualError(t, err, "configuration file value is not valid: "+
+		"key servers: value type []map[string]interface {} is not supported")
+}
+
+// Test_LoadFile sets environment variables prefixed with
+// GLUETUN_TEST_LOADFILE_ which are not used by other tests.
+func Test_LoadFile(t *testing.T) {
+	t.Parallel()
+
+	const (
+		explicitKey = "GLUETUN_TEST_LOADFILE_EXPLICIT"
+		defaultKey  = "GLUETUN_TEST_LOADFILE_DEFAULT"
+		fileKey     = "GLUETUN_TEST_LOADFILE_FILE"
+		removedKey  = "GLUETUN_TEST_LOADFILE_REMOVED"
+	)
+	t.Cleanup(func() {
+		for _, key := range []string{explicitKey, defaultKey, fileKey, removedKey} {
+			_ = os.Unsetenv(key)
+		}
+	})
+
+	dir := t.TempDir()
+	envDefaultsPath := filepath.Join(dir, "env.defaults")
+	err := os.WriteFile(envDefaultsPath, []byte(
+		explicitKey+"=default\n"+defaultKey+"=default\n"+removedKey+"=default\n"), 0600)
+	require.NoError(t, err)
+
+	// The explicit variable is set to its default value, which
+	// must still take precedence over the configuration file.
+	environment := Environment{explicitKey: "default"}
+	require.NoError(t, os.Setenv(explicitKey, "default"))
+
+	configPath := filepath.Join(dir, "config.toml")
+	err = os.WriteFile(configPath, []byte(`[gluetun_test_loadfile]
+explicit = "file"
+default = "file"
+removed = "file"
+`), 0600)
+	require.NoError(t, err)
+
+	keys, err := LoadFile(configPath, environment, nil)
+	require.NoError(t, err)
+	require.NoError(t, ApplyEnvDefaults(envDefaultsPath))
+
+	assert.Equal(t, FileKeys{
+		defaultKey: "gluetun_test_loadfile.default",
+		removedKey: "gluetun_test_loadfile.removed",
+	}, keys)
+	assert.Equal(t, "default", os.Getenv(explicitKey))
+	assert.Equal(t, "file", os.Getenv(defaultKey))
+	assert.Equal(t, "file", os.Getenv(removedKey))
+
+	// Load the file again, with the removed key removed and a new key.
+	err = os.WriteFile(configPath, []byte(`[gluetun_test_loadfile]
+explicit = "file"
+default = "file2"
+file = "file"
+`), 0600)
+	require.NoError(t, err)
+
+	keys, err = LoadFile(configPath, environment, keys)
+	require.NoError(t, err)
+	require.NoError(t, ApplyEnvDefaults(envDefaultsPath))
+
+	assert.Equal(t, FileKeys{
+		defaultKey: "gluetun_test_loadfile.default",
+		fileKey:    "gluetun_test_loadfile.file",
+	}, keys)
+	assert.Equal(t, "default", os.Getenv(explicitKey))
+	assert.Equal(t, "file2", os.Getenv(defaultKey))
+	assert.Equal(t, "file", os.Getenv(fileKey))
+	assert.Equal(t, "default", os.Getenv(removedKey))
+}
+
+// Test_LoadFile_previous sets environment variables prefixed with
+// GLUETUN_TEST_PREVIOUS_ which are not used by other tests.
+func Test_LoadFile_previous(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		key      string
+		fileSets bool
+		previous bool
+		value    string
+		set      bool
+	}{
+		"key set by file": {
+			key:      "GLUETUN_TEST_PREVIOUS_NEW",
+			fileSets: true,
+			value:    "file",
+			set:      true,
+		},
+		"previous key still in file": {
+			key:      "GLUETUN_TEST_PREVIOUS_KEPT",
+			fileSets: true,
+			previous: true,
+			value:    "file",
+			set:      true,
+		},
+		"previous key removed from file": {
+			key:      "GLUETUN_TEST_PREVIOUS_REMOVED",
+			previous: true,
+		},
+		"key not from file": {
+			key:   "GLUETUN_TEST_PREVIOUS_OTHER",
+			value: "other",
+			set:   true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			require.NoError(t, os.Setenv(testCase.key, "other"))
+			t.Cleanup(func() { _ = os.Unsetenv(testCase.key) })
+
+			content := "gluetun_test_previous_unrelated = \"value\"\n"
+			if testCase.fileSets {
+				content += strings.ToLower(testCase.key) + " = \"file\"\n"
+			}
+			configPath := filepath.Join(t.TempDir(), "config.toml")
+			err := os.WriteFile(configPath, []byte(content), 0600)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = os.Unsetenv("GLUETUN_TEST_PREVIOUS_UNRELATED") })
+
+			var previous FileKeys
+			if testCase.previous {
+				previous = FileKeys{testCase.key: strings.ToLower(testCase.key)}
+			}
+
+			_, err = LoadFile(configPath, nil, previous)
+			require.NoError(t, err)
+
+			value, set := os.LookupEnv(testCase.key)
+			assert.Equal(t, testCase.set, set)
+			assert.Equal(t, testCase.value, value)
+		})
+	}
+}
+
+func Test_LoadFile_errors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile := func(name, content string) (path string) {
+		path = filepath.Join(dir, name)
+		err := os.WriteFile(path, []byte(content), 0600)
+		require.NoError(t, err)
+		return path
+	}
+
+	testCases := map[string]struct {
+		path       string
+		err        error
+		errMessage string
+	}{
+		"bad extension": {
+			path:       writeFile("config.json", "{}"),
+			err:        ErrConfigFileExtension,
+			errMessage: `configuration file extension is not supported: ".json" must be one of .toml, .yaml or .yml`,
+		},
+		"TOML syntax error": {
+			path:       writeFile("syntax.toml", "key value"),
+			errMessage: "cannot parse configuration file " + filepath.Join(dir, "syntax.toml") + ": ",
+		},
+		"TOML duplicate key": {
+			path:       writeFile("duplicate.toml", "key = 1\nkey = 2"),
+			errMessage: "cannot parse configuration file " + filepath.Join(dir, "duplicate.toml") + ": ",
+		},
+		"keys setting the same variable": {
+			path: writeFile("conflict.yaml", "a_b: 1\na:\n  b: 2\n"),
+			err:  ErrConfigFileValue,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := LoadFile(testCase.path, nil, nil)
+			require.Error(t, err)
+			if testCase.err != nil {
+				assert.ErrorIs(t, err, testCase.err)
+			}
+			assert.Contains(t, err.Error(), testCase.errMessage)
+		})
+	}
+}
+
+func Test_CaptureEnvironment(t *testing.T) {
+	t.Parallel()
+
+	const key = "GLUETUN_TEST_CAPTURE_ENVIRONMENT"
+	require.NoError(t, os.Setenv(key, "a=b"))
+	t.Cleanup(func() { _ = os.Unsetenv(key) })
+
+	environment := CaptureEnvironment()
+	require.NoError(t, os.Unsetenv(key))
+
+	value, set := environment.LookupEnv(key)
+	assert.True(t, set)
+	assert.Equal(t, "a=b", value)
+	_, set = environment.LookupEnv(key + "_UNSET")
+	assert.False(t, set)
+}
+
+func Test_FileKeys_WrapError(t *testing.T) {
+	t.Parallel()
+
+	keys := FileKeys{"OPENVPN_USER": "openvpn.user"}
+
+	err := errors.New(`environment variable "OPENVPN_USER": value is empty`)
+	wrapped := keys.WrapError(err, "/gluetun/config.toml")
+	assert.ErrorIs(t, wrapped, err)
+	assert.EqualError(t, wrapped, `environment variable "OPENVPN_USER": value is empty `+
+		`(key openvpn.user in configuration file /gluetun/config.toml)`)
+
+	err = errors.New(`environment variable "VPNSP": value is not valid`)
+	assert.Equal(t, err, keys.WrapError(err, "/gluetun/config.toml"))
+}
@@ -1,19 +1,87 @@
 package configuration
 
 import (
+	"errors"
 	"net"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/golibs/params"
 )
 
 // Firewall contains settings to customize the firewall operation.
 type Firewall struct {
-	VPNInputPorts   []uint16
-	InputPorts      []uint16
-	OutboundSubnets []net.IPNet
-	Enabled         bool
-	Debug           bool
+	VPNInputPorts []uint16 `json:"vpn_input_ports"`
+	InputPorts    []uint16 `json:"input_ports"`
+	// LANPorts are input ports accepted through the default interface
+	// like InputPorts, with their replies also routed through the
+	// default interface instead of the VPN.
+	LANPorts        []uint16    `json:"lan_ports"`
+	OutboundSubnets []net.IPNet `json:"outbound_subnets"`
+	// OutboundRules are destinations and ports allowed outside the VPN,
+	// for finer control than with OutboundSubnets.
+	OutboundRules []models.OutboundRule `json:"outbound_rules"`
+	// Bootstrap is true to enable the firewall before obtaining data from
+	// the VPN provider APIs, only allowing the traffic to the provider API
+	// endpoints, to the BootstrapRules destinations and the latency probes
+	// outside the VPN at start. Otherwise, this data is obtained before
+	// the firewall is enabled.
+	Bootstrap bool `json:"bootstrap"`
+	// BootstrapRules are additional destinations and ports allowed
+	// outside the VPN at start if Bootstrap is enabled.
+	BootstrapRules []models.OutboundRule `json:"bootstrap_rules"`
+	// BypassSubnets and BypassDomains are destinations routed through
+	// the default interface instead of the VPN, and BypassPeriod is the
+	// period to resolve the bypass domains again.
+	BypassSubnets []net.IPNet   `json:"bypass_subnets"`
+	BypassDomains []string      `json:"bypass_domains"`
+	BypassPeriod  time.Duration `json:"bypass_period"`
+	// VPNSources and BypassSources are source subnets, for example of
+	// containers using gluetun as their gateway, whose traffic is
+	// forwarded through the VPN and the default interface respectively.
+	VPNSources    []net.IPNet `json:"vpn_sources"`
+	BypassSources []net.IPNet `json:"bypass_sources"`
+	// BypassUIDs are user IDs of processes, for example of containers
+	// sharing the network namespace of gluetun, whose traffic is routed
+	// through the default interface instead of the VPN.
+	BypassUIDs []int `json:"bypass_uids"`
+	// TransparentProxySources are source subnets, for example of the LAN,
+	// whose TCP traffic to the TransparentProxyPorts is intercepted and
+	// proxied through the VPN, without configuring a proxy on the clients.
+	TransparentProxySources []net.IPNet `json:"transparent_proxy_sources"`
+	TransparentProxyPorts   []uint16    `json:"transparent_proxy_ports"`
+	// TransparentProxyListeningPort is the port the transparent
+	// proxy listens on, which the intercepted traffic is redirected to.
+	TransparentProxyListeningPort uint16 `json:"transparent_proxy_listening_port"`
+	Enabled                       bool   `json:"enabled"`
+	// Audit is true to only log the firewall rules instead of applying
+	// them, and Enabled is then true as well.
+	Audit bool `json:"audit"`
+	Debug bool `json:"debug"`
+	// LogDropped is true to log the packets dropped by the firewall,
+	// with a rate limit, to the kernel log.
+	LogDropped bool `json:"log_dropped"`
+	// VerifyPeriod is the period to verify the firewall rules were not
+	// modified externally, and is 0 to disable the verification.
+	VerifyPeriod time.Duration `json:"verify_period"`
+	// FlushConntrack is true to flush the connection tracking entries
+	// of the connections through the VPN when the tunnel goes down.
+	// It is off by default since it also drops the connections kept
+	// through the tunnel on a reconnection.
+	FlushConntrack bool `json:"flush_conntrack"`
+	// Backend is the firewall backend, which can be iptables,
+	// nftables or auto to detect it.
+	Backend string `json:"backend"`
+	// MulticastDNS is true to accept the multicast DNS and LLMNR
+	// traffic on the local network, for services behind the firewall
+	// to be discovered by devices on the local network.
+	MulticastDNS bool `json:"multicast_dns"`
+	// PostRulesFile is the file path to the user firewall rules
+	// applied after the firewall rules, ignored if it does not exist.
+	PostRulesFile string `json:"post_rules_file"`
 }
 
 func (settings *Firewall) String() string {
@@ -28,10 +96,35 @@ func (settings *Firewall) lines() (lines []string) {
 
 	lines = append(lines, lastIndent+"Firewall:")
 
+	if settings.Audit {
+		lines = append(lines, indent+lastIndent+"Audit mode: on, rules are only logged ⚠️")
+	}
+
+	lines = append(lines, indent+lastIndent+"Backend: "+settings.Backend)
+
+	lines = append(lines, indent+lastIndent+"User post rules file: "+settings.PostRulesFile)
+
 	if settings.Debug {
 		lines = append(lines, indent+lastIndent+"Debug: on")
 	}
 
+	if settings.LogDropped {
+		lines = append(lines, indent+lastIndent+"Log dropped packets: on")
+	}
+
+	if settings.FlushConntrack {
+		lines = append(lines, indent+lastIndent+"Flush tunnel connections: on")
+	}
+
+	if settings.VerifyPeriod > 0 {
+		lines = append(lines, indent+lastIndent+"Rules verification period: "+
+			settings.VerifyPeriod.String())
+	}
+
+	if settings.MulticastDNS {
+		lines = append(lines, indent+lastIndent+"Multicast DNS passthrough: on")
+	}
+
 	if len(settings.VPNInputPorts) > 0 {
 		lines = append(lines, indent+lastIndent+"VPN input ports: "+
 			strings.Join(uint16sToStrings(settings.VPNInputPorts), ", "))
@@ -42,25 +135,126 @@ func (settings *Firewall) lines() (lines []string) {
 			strings.Join(uint16sToStrings(settings.InputPorts), ", "))
 	}
 
+	if len(settings.LANPorts) > 0 {
+		lines = append(lines, indent+lastIndent+"LAN ports: "+
+			strings.Join(uint16sToStrings(settings.LANPorts), ", "))
+	}
+
 	if len(settings.OutboundSubnets) > 0 {
 		lines = append(lines, indent+lastIndent+"Outbound subnets: "+
 			strings.Join(ipNetsToStrings(settings.OutboundSubnets), ", "))
 	}
 
+	if len(settings.OutboundRules) > 0 {
+		rules := make([]string, len(settings.OutboundRules))
+		for i, rule := range settings.OutboundRules {
+			rules[i] = rule.String()
+		}
+		lines = append(lines, indent+lastIndent+"Outbound rules: "+
+			strings.Join(rules, ", "))
+	}
+
+	if settings.Bootstrap {
+		lines = append(lines, indent+lastIndent+"Bootstrap: on")
+		if len(settings.BootstrapRules) > 0 {
+			rules := make([]string, len(settings.BootstrapRules))
+			for i, rule := range settings.BootstrapRules {
+				rules[i] = rule.String()
+			}
+			lines = append(lines, indent+indent+lastIndent+"Additional rules: "+
+				strings.Join(rules, ", "))
+		}
+	}
+
+	if len(settings.BypassSubnets) > 0 {
+		lines = append(lines, indent+lastIndent+"Bypass subnets: "+
+			strings.Join(ipNetsToStrings(settings.BypassSubnets), ", "))
+	}
+
+	if len(settings.BypassDomains) > 0 {
+		lines = append(lines, indent+lastIndent+"Bypass domains: "+
+			strings.Join(settings.BypassDomains, ", "))
+		lines = append(lines, indent+lastIndent+"Bypass domains update period: "+
+			settings.BypassPeriod.String())
+	}
+
+	if len(settings.VPNSources) > 0 {
+		lines = append(lines, indent+lastIndent+"VPN sources: "+
+			strings.Join(ipNetsToStrings(settings.VPNSources), ", "))
+	}
+
+	if len(settings.BypassSources) > 0 {
+		lines = append(lines, indent+lastIndent+"Bypass sources: "+
+			strings.Join(ipNetsToStrings(settings.BypassSources), ", "))
+	}
+
+	if len(settings.BypassUIDs) > 0 {
+		uids := make([]string, len(settings.BypassUIDs))
+		for i, uid := range settings.BypassUIDs {
+			uids[i] = strconv.Itoa(uid)
+		}
+		lines = append(lines, indent+lastIndent+"Bypass user IDs: "+
+			strings.Join(uids, ", "))
+	}
+
+	if len(settings.TransparentProxySources) > 0 {
+		lines = append(lines, indent+lastIndent+"Transparent proxy sources: "+
+			strings.Join(ipNetsToStrings(settings.TransparentProxySources), ", "))
+		lines = append(lines, indent+indent+lastIndent+"Ports intercepted: "+
+			strings.Join(uint16sToStrings(settings.TransparentProxyPorts), ", "))
+		lines = append(lines, indent+indent+lastIndent+"Listening port: "+
+			strconv.Itoa(int(settings.TransparentProxyListeningPort)))
+	}
+
 	return lines
 }
 
 func (settings *Firewall) read(r reader) (err error) {
-	settings.Enabled, err = r.env.OnOff("FIREWALL", params.Default("on"))
+	mode, err := r.env.Inside("FIREWALL", []string{"on", "off", "audit"}, params.Default("on"))
 	if err != nil {
 		return err
 	}
+	settings.Enabled = mode != "off"
+	settings.Audit = mode == "audit"
 
 	settings.Debug, err = r.env.OnOff("FIREWALL_DEBUG", params.Default("off"))
 	if err != nil {
 		return err
 	}
 
+	settings.LogDropped, err = r.env.OnOff("FIREWALL_LOG_DROPPED", params.Default("off"))
+	if err != nil {
+		return err
+	}
+
+	settings.VerifyPeriod, err = r.env.Duration("FIREWALL_VERIFY_PERIOD", params.Default("0"))
+	if err != nil {
+		return err
+	}
+
+	settings.FlushConntrack, err = r.env.OnOff("FIREWALL_FLUSH_CONNTRACK", params.Default("off"))
+	if err != nil {
+		return err
+	}
+
+	settings.MulticastDNS, err = r.env.OnOff("FIREWALL_MULTICAST_DNS", params.Default("off"))
+	if err != nil {
+		return err
+	}
+
+	settings.Backend, err = r.env.Inside("FIREWALL_BACKEND", []string{
+		constants.FirewallBackendAuto, constants.IPTables, constants.NFTables},
+		params.Default(constants.FirewallBackendAuto))
+	if err != nil {
+		return err
+	}
+
+	settings.PostRulesFile, err = r.env.Path("FIREWALL_POST_RULES_FILE",
+		params.Default(constants.FirewallPostRules))
+	if err != nil {
+		return err
+	}
+
 	if err := settings.readVPNInputPorts(r.env); err != nil {
 		return err
 	}
@@ -69,11 +263,32 @@ func (settings *Firewall) read(r reader) (err error) {
 		return err
 	}
 
+	if err := settings.readLANPorts(r.env); err != nil {
+		return err
+	}
+
 	if err := settings.readOutboundSubnets(r); err != nil {
 		return err
 	}
 
-	return nil
+	settings.OutboundRules, err = readCSVOutboundRules(r.env, "FIREWALL_OUTBOUND_RULES")
+	if err != nil {
+		return err
+	}
+
+	if err := settings.readBootstrap(r.env); err != nil {
+		return err
+	}
+
+	if err := settings.readBypass(r.env); err != nil {
+		return err
+	}
+
+	if err := settings.readSources(r.env); err != nil {
+		return err
+	}
+
+	return settings.readTransparentProxy(r)
 }
 
 func (settings *Firewall) readVPNInputPorts(env params.Env) (err error) {
@@ -86,8 +301,86 @@ func (settings *Firewall) readInputPorts(env params.Env) (err error) {
 	return err
 }
 
+func (settings *Firewall) readLANPorts(env params.Env) (err error) {
+	settings.LANPorts, err = readCSVPorts(env, "FIREWALL_LAN_PORTS")
+	return err
+}
+
 func (settings *Firewall) readOutboundSubnets(r reader) (err error) {
 	retroOption := params.RetroKeys([]string{"EXTRA_SUBNETS"}, r.onRetroActive)
 	settings.OutboundSubnets, err = readCSVIPNets(r.env, "FIREWALL_OUTBOUND_SUBNETS", retroOption)
 	return err
 }
+
+func (settings *Firewall) readBypass(env params.Env) (err error) {
+	settings.BypassSubnets, err = readCSVIPNets(env, "OUTBOUND_BYPASS_SUBNETS")
+	if err != nil {
+		return err
+	}
+
+	settings.BypassDomains, err = env.CSV("OUTBOUND_BYPASS_DOMAINS")
+	if err != nil {
+		return err
+	}
+
+	settings.BypassPeriod, err = env.Duration("OUTBOUND_BYPASS_DOMAINS_PERIOD", params.Default("1h"))
+	return err
+}
+
+func (settings *Firewall) readSources(env params.Env) (err error) {
+	settings.VPNSources, err = readCSVIPNets(env, "OUTBOUND_VPN_SOURCES")
+	if err != nil {
+		return err
+	}
+
+	settings.BypassSources, err = readCSVIPNets(env, "OUTBOUND_BYPASS_SOURCES")
+	if err != nil {
+		return err
+	}
+
+	settings.BypassUIDs, err = readCSVUIDs(env, "OUTBOUND_BYPASS_UIDS")
+	return err
+}
+
+func (settings *Firewall) readTransparentProxy(r reader) (err error) {
+	settings.TransparentProxySources, err = readCSVIPNets(r.env, "OUTBOUND_TRANSPARENT_PROXY_SOURCES")
+	if err != nil || len(settings.TransparentProxySources) == 0 {
+		return err
+	}
+
+	settings.TransparentProxyPorts, err = readCSVPorts(r.env, "OUTBOUND_TRANSPARENT_PROXY_PORTS")
+	if err != nil {
+		return err
+	} else if len(settings.TransparentProxyPorts) == 0 {
+		const httpPort, httpsPort = 80, 443
+		settings.TransparentProxyPorts = []uint16{httpPort, httpsPort}
+	}
+
+	var warning string
+	settings.TransparentProxyListeningPort, warning, err = r.env.ListeningPort(
+		"OUTBOUND_TRANSPARENT_PROXY_LISTENING_PORT", params.Default("8887"))
+	if len(warning) > 0 {
+		r.logger.Warn(warning)
+	}
+	return err
+}
+
+var ErrBootstrapRulesWithoutBootstrap = errors.New("bootstrap rules are set but bootstrap is disabled")
+
+func (settings *Firewall) readBootstrap(env params.Env) (err error) {
+	settings.Bootstrap, err = env.OnOff("FIREWALL_BOOTSTRAP", params.Default("off"))
+	if err != nil {
+		return err
+	}
+
+	settings.BootstrapRules, err = readCSVOutboundRules(env, "FIREWALL_BOOTSTRAP_RULES")
+	if err != nil {
+		return err
+	}
+
+	if len(settings.BootstrapRules) > 0 && !settings.Bootstrap {
+		return ErrBootstrapRulesWithoutBootstrap
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package configuration
+
+import (
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/params/mock_params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Firewall_readTransparentProxy(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		sources       string
+		ports         string
+		settings      Firewall
+		mockListening bool
+	}{
+		"disabled": {},
+		"default ports": {
+			sources: "192.168.1.0/24",
+			settings: Firewall{
+				TransparentProxySources: []net.IPNet{{
+					IP:   net.IPv4(192, 168, 1, 0).To4(),
+					Mask: net.CIDRMask(24, 32),
+				}},
+				TransparentProxyPorts:         []uint16{80, 443},
+				TransparentProxyListeningPort: 8887,
+			},
+			mockListening: true,
+		},
+		"custom ports": {
+			sources: "192.168.1.0/24",
+			ports:   "8080",
+			settings: Firewall{
+				TransparentProxySources: []net.IPNet{{
+					IP:   net.IPv4(192, 168, 1, 0).To4(),
+					Mask: net.CIDRMask(24, 32),
+				}},
+				TransparentProxyPorts:         []uint16{8080},
+				TransparentProxyListeningPort: 8887,
+			},
+			mockListening: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			env := mock_params.NewMockEnv(ctrl)
+			env.EXPECT().Get("OUTBOUND_TRANSPARENT_PROXY_SOURCES").
+				Return(testCase.sources, nil)
+			if testCase.mockListening {
+				env.EXPECT().Get("OUTBOUND_TRANSPARENT_PROXY_PORTS").
+					Return(testCase.ports, nil)
+				env.EXPECT().ListeningPort("OUTBOUND_TRANSPARENT_PROXY_LISTENING_PORT", gomock.Any()).
+					Return(uint16(8887), "", nil)
+			}
+
+			var settings Firewall
+			err := settings.readTransparentProxy(reader{env: env})
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.settings, settings)
+		})
+	}
+}
+
+func Test_Firewall_readBootstrap(t *testing.T) {
+	t.Parallel()
+
+	rule := models.OutboundRule{
+		Subnet:   net.IPNet{IP: net.IP{1, 1, 1, 1}, Mask: net.CIDRMask(32, 32)},
+		Port:     443,
+		Protocol: "tcp",
+	}
+
+	testCases := map[string]struct {
+		bootstrap bool
+		rules     string
+		settings  Firewall
+		err       error
+	}{
+		"disabled": {},
+		"enabled": {
+			bootstrap: true,
+			settings:  Firewall{Bootstrap: true},
+		},
+		"enabled with rules": {
+			bootstrap: true,
+			rules:     "1.1.1.1:443/tcp",
+			settings: Firewall{
+				Bootstrap:      true,
+				BootstrapRules: []models.OutboundRule{rule},
+			},
+		},
+		"rules without bootstrap": {
+			rules: "1.1.1.1:443/tcp",
+			settings: Firewall{
+				BootstrapRules: []models.OutboundRule{rule},
+			},
+			err: ErrBootstrapRulesWithoutBootstrap,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			env := mock_params.NewMockEnv(ctrl)
+			env.EXPECT().OnOff("FIREWALL_BOOTSTRAP", gomock.Any()).
+				Return(testCase.bootstrap, nil)
+			env.EXPECT().Get("FIREWALL_BOOTSTRAP_RULES").
+				Return(testCase.rules, nil)
+
+			var settings Firewall
+			err := settings.readBootstrap(env)
+
+			assert.ErrorIs(t, err, testCase.err)
+			assert.Equal(t, testCase.settings, settings)
+		})
+	}
+}
+
+func Test_Firewall_readSources(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		vpnSources    string
+		bypassSources string
+		bypassUIDs    string
+		settings      Firewall
+		err           error
+	}{
+		"empty": {},
+		"sources and user IDs": {
+			vpnSources:    "172.18.0.2/32",
+			bypassSources: "172.18.0.3/32,172.19.0.0/16",
+			bypassUIDs:    "1000,1001",
+			settings: Firewall{
+				VPNSources: []net.IPNet{
+					{IP: net.IP{172, 18, 0, 2}, Mask: net.CIDRMask(32, 32)},
+				},
+				BypassSources: []net.IPNet{
+					{IP: net.IP{172, 18, 0, 3}, Mask: net.CIDRMask(32, 32)},
+					{IP: net.IP{172, 19, 0, 0}, Mask: net.CIDRMask(16, 32)},
+				},
+				BypassUIDs: []int{1000, 1001},
+			},
+		},
+		"user ID not a number": {
+			bypassUIDs: "1000,abc",
+			err:        ErrInvalidUID,
+		},
+		"user ID out of range": {
+			bypassUIDs: "70000",
+			err:        ErrInvalidUID,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			env := mock_params.NewMockEnv(ctrl)
+			env.EXPECT().Get("OUTBOUND_VPN_SOURCES").Return(testCase.vpnSources, nil)
+			env.EXPECT().Get("OUTBOUND_BYPASS_SOURCES").Return(testCase.bypassSources, nil)
+			env.EXPECT().Get("OUTBOUND_BYPASS_UIDS").Return(testCase.bypassUIDs, nil)
+
+			var settings Firewall
+			err := settings.readSources(env)
+
+			assert.ErrorIs(t, err, testCase.err)
+			if testCase.err == nil {
+				assert.Equal(t, testCase.settings, settings)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/params"
+)
+
+// Health contains settings for the healthcheck.
+type Health struct {
+	// Checks are the checks run by the healthcheck.
+	Checks []models.HealthCheck `json:"checks"`
+	// RequireAll is true if all the checks must succeed to be
+	// healthy, and false if only one of them must succeed.
+	RequireAll bool `json:"require_all"`
+	// FailureThreshold is the number of consecutive failed checks
+	// needed to become unhealthy.
+	FailureThreshold int `json:"failure_threshold"`
+	// SuccessThreshold is the number of consecutive successful
+	// checks needed to become healthy again.
+	SuccessThreshold int `json:"success_threshold"`
+	// BackoffInitial is the initial delay between checks while
+	// they fail, doubled after each check up to BackoffMax.
+	BackoffInitial time.Duration `json:"backoff_initial"`
+	// BackoffMax is the maximum delay between checks while they fail.
+	BackoffMax time.Duration `json:"backoff_max"`
+	// Recovery are the actions taken in order while unhealthy,
+	// one every RecoveryPeriod. It is empty to take no action.
+	Recovery []string `json:"recovery"`
+	// RecoveryPeriod is the duration to stay unhealthy for before
+	// taking the next recovery action.
+	RecoveryPeriod time.Duration `json:"recovery_period"`
+	// PingURL is the URL requested after each healthy run of the
+	// checks, such as a healthchecks.io URL. It is empty to disable
+	// pinging an external URL.
+	PingURL string `json:"ping_url"`
+	// PingFailURL is the URL requested when becoming unhealthy.
+	// It defaults to PingURL followed by /fail.
+	PingFailURL string `json:"ping_fail_url"`
+	// LeakCheck is true to periodically check the public IP address
+	// differs from the public IP address found before connecting to
+	// the VPN, and lock down all traffic otherwise.
+	LeakCheck bool `json:"leak_check"`
+	// LeakCheckPeriod is the period to check the public IP address.
+	LeakCheckPeriod time.Duration `json:"leak_check_period"`
+	// LeakCheckSubnets are the subnets the public IP address is
+	// expected to be in, and is empty to not check it.
+	LeakCheckSubnets []net.IPNet `json:"leak_check_subnets"`
+}
+
+func (settings *Health) String() string {
+	return strings.Join(settings.lines(), "\n")
+}
+
+func (settings *Health) lines() (lines []string) {
+	lines = append(lines, lastIndent+"Health:")
+
+	lines = append(lines, indent+lastIndent+"Checks:")
+	for _, check := range settings.Checks {
+		line := check.String() + " with timeout " + check.Timeout.String()
+		if check.Type == constants.HealthCheckHTTP {
+			line += " expecting status " + strconv.Itoa(check.Status)
+		}
+		lines = append(lines, indent+indent+lastIndent+line)
+	}
+
+	if settings.RequireAll {
+		lines = append(lines, indent+lastIndent+"Healthy if: all checks succeed")
+	} else {
+		lines = append(lines, indent+lastIndent+"Healthy if: one check succeeds")
+	}
+
+	lines = append(lines, indent+lastIndent+"Unhealthy after: "+
+		strconv.Itoa(settings.FailureThreshold)+" consecutive failures")
+	lines = append(lines, indent+lastIndent+"Healthy again after: "+
+		strconv.Itoa(settings.SuccessThreshold)+" consecutive successes")
+	lines = append(lines, indent+lastIndent+"Retry backoff: "+
+		settings.BackoffInitial.String()+" up to "+settings.BackoffMax.String())
+
+	if len(settings.Recovery) > 0 {
+		lines = append(lines, indent+lastIndent+"Recovery: "+strings.Join(settings.Recovery, ", ")+
+			" every "+settings.RecoveryPeriod.String())
+	}
+
+	if settings.LeakCheck {
+		lines = append(lines, indent+lastIndent+"IP leak check: every "+settings.LeakCheckPeriod.String())
+		if len(settings.LeakCheckSubnets) > 0 {
+			lines = append(lines, indent+indent+lastIndent+"Expected public IP subnets: "+
+				strings.Join(ipNetsToStrings(settings.LeakCheckSubnets), ", "))
+		}
+	}
+
+	if settings.PingURL != "" {
+		lines = append(lines, indent+lastIndent+"External ping: "+urlHost(settings.PingURL))
+	}
+
+	return lines
+}
+
+func (settings *Health) read(r reader) (err error) {
+	defaultTimeout, err := r.env.Duration("HEALTH_TIMEOUT", params.Default("5s"))
+	if err != nil {
+		return err
+	}
+
+	checks, err := r.env.CSV("HEALTH_CHECKS", params.Default("dns:github.com"),
+		params.CaseSensitiveValue())
+	if err != nil {
+		return err
+	}
+	settings.Checks = make([]models.HealthCheck, len(checks))
+	for i, s := range checks {
+		settings.Checks[i], err = parseHealthCheck(s, defaultTimeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	settings.RequireAll, err = r.env.OnOff("HEALTH_CHECKS_REQUIRE_ALL", params.Default("off"))
+	if err != nil {
+		return err
+	}
+
+	const maxThreshold = 100
+	settings.FailureThreshold, err = r.env.IntRange("HEALTH_FAILURE_THRESHOLD", 1, maxThreshold,
+		params.Default("1"))
+	if err != nil {
+		return err
+	}
+
+	settings.SuccessThreshold, err = r.env.IntRange("HEALTH_SUCCESS_THRESHOLD", 1, maxThreshold,
+		params.Default("1"))
+	if err != nil {
+		return err
+	}
+
+	settings.BackoffInitial, err = r.env.Duration("HEALTH_BACKOFF_INITIAL", params.Default("1s"))
+	if err != nil {
+		return err
+	}
+
+	settings.BackoffMax, err = r.env.Duration("HEALTH_BACKOFF_MAX", params.Default("30s"))
+	if err != nil {
+		return err
+	}
+	if settings.BackoffMax < settings.BackoffInitial {
+		return fmt.Errorf("%w: %s is lower than %s", ErrHealthBackoffMaxTooLow,
+			settings.BackoffMax, settings.BackoffInitial)
+	}
+
+	settings.Recovery, err = r.env.CSVInside("HEALTH_RECOVERY", constants.HealthRecoveryActions())
+	if err != nil {
+		return err
+	}
+	for i, action := range settings.Recovery {
+		if action == constants.HealthRecoveryExit && i != len(settings.Recovery)-1 {
+			return fmt.Errorf("%w: %s", ErrHealthRecoveryExitNotLast, strings.Join(settings.Recovery, ","))
+		}
+	}
+
+	settings.RecoveryPeriod, err = r.env.Duration("HEALTH_RECOVERY_PERIOD", params.Default("1m"))
+	if err != nil {
+		return err
+	}
+
+	if err := settings.readPing(r); err != nil {
+		return err
+	}
+
+	return settings.readLeakCheck(r)
+}
+
+func (settings *Health) readLeakCheck(r reader) (err error) {
+	settings.LeakCheck, err = r.env.OnOff("HEALTH_LEAK_CHECK", params.Default("off"))
+	if err != nil || !settings.LeakCheck {
+		return err
+	}
+
+	settings.LeakCheckPeriod, err = r.env.Duration("HEALTH_LEAK_CHECK_PERIOD", params.Default("10m"))
+	if err != nil {
+		return err
+	}
+
+	settings.LeakCheckSubnets, err = readCSVIPNets(r.env, "HEALTH_LEAK_CHECK_SUBNETS")
+	return err
+}
+
+func (settings *Health) readPing(r reader) (err error) {
+	settings.PingURL, err = r.env.Get("HEALTH_PING_URL", params.CaseSensitiveValue())
+	if err != nil {
+		return err
+	} else if settings.PingURL == "" {
+		return nil
+	}
+
+	settings.PingFailURL, err = r.env.Get("HEALTH_PING_FAIL_URL", params.CaseSensitiveValue())
+	if err != nil {
+		return err
+	} else if settings.PingFailURL == "" {
+		settings.PingFailURL = strings.TrimSuffix(settings.PingURL, "/") + "/fail"
+	}
+
+	for _, s := range []string{settings.PingURL, settings.PingFailURL} {
+		u, err := url.Parse(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: %s", ErrHealthPingURLInvalid, urlHost(s))
+		}
+	}
+
+	return nil
+}
+
+// urlHost returns the host of the URL given, to log
+// the URL without the secret token it can contain.
+func urlHost(s string) (host string) {
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return "invalid URL"
+	}
+	return u.Host
+}
+
+var (
+	ErrHealthCheckInvalid        = errors.New("health check is not valid")
+	ErrHealthRecoveryExitNotLast = errors.New("exit must be the last health recovery action")
+	ErrHealthBackoffMaxTooLow    = errors.New("maximum health check backoff is lower than the initial backoff")
+	ErrHealthPingURLInvalid      = errors.New("health ping URL is not a valid http or https URL")
+)
+
+// parseHealthCheck parses a health check in the format type:target,
+// optionally followed by @timeout such as tcp:1.1.1.1:443@3s. The
+// HTTP status code expected by http checks can be set as the
+// fragment of the URL, such as http:https://example.com/#204.
+func parseHealthCheck(s string, defaultTimeout time.Duration) (
+	check models.HealthCheck, err error) {
+	i := strings.Index(s, ":")
+	if i == -1 {
+		return check, fmt.Errorf("%w: %s: must be type:target", ErrHealthCheckInvalid, s)
+	}
+	check.Type = strings.ToLower(s[:i])
+	check.Target = s[i+1:]
+
+	check.Timeout = defaultTimeout
+	if i := strings.LastIndex(check.Target, "@"); i != -1 {
+		timeout, err := time.ParseDuration(check.Target[i+1:])
+		if err == nil { // not a timeout otherwise, such as for URL user info
+			if timeout <= 0 {
+				return check, fmt.Errorf("%w: %s: timeout must be positive", ErrHealthCheckInvalid, s)
+			}
+			check.Timeout = timeout
+			check.Target = check.Target[:i]
+		}
+	}
+
+	if check.Target == "" {
+		return check, fmt.Errorf("%w: %s: target is empty", ErrHealthCheckInvalid, s)
+	}
+
+	switch check.Type {
+	case constants.HealthCheckTCP, constants.HealthCheckTLS:
+		if _, _, err := net.SplitHostPort(check.Target); err != nil {
+			return check, fmt.Errorf("%w: %s: %s", ErrHealthCheckInvalid, s, err)
+		}
+	case constants.HealthCheckHTTP:
+		u, err := url.Parse(check.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return check, fmt.Errorf("%w: %s: target must be an http or https URL", ErrHealthCheckInvalid, s)
+		}
+		check.Status = http.StatusOK
+		if u.Fragment != "" {
+			check.Status, err = strconv.Atoi(u.Fragment)
+			if err != nil || check.Status < 100 || check.Status > 599 {
+				return check, fmt.Errorf("%w: %s: status code %q is not valid",
+					ErrHealthCheckInvalid, s, u.Fragment)
+			}
+			u.Fragment = ""
+			check.Target = u.String()
+		}
+	case constants.HealthCheckDNS, constants.HealthCheckICMP:
+	default:
+		return check, fmt.Errorf("%w: %s: type %q must be one of: %s", ErrHealthCheckInvalid,
+			s, check.Type, strings.Join(constants.HealthCheckTypes(), ", "))
+	}
+
+	return check, nil
+}
@@ -0,0 +1,65 @@
+package configuration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseHealthCheck(t *testing.T) {
+	t.Parallel()
+	const defaultTimeout = 5 * time.Second
+	testCases := map[string]struct {
+		s     string
+		check models.HealthCheck
+		err   error
+	}{
+		"dns": {
+			s:     "dns:github.com",
+			check: models.HealthCheck{Type: "dns", Target: "github.com", Timeout: defaultTimeout},
+		},
+		"tcp with timeout": {
+			s:     "TCP:1.1.1.1:443@3s",
+			check: models.HealthCheck{Type: "tcp", Target: "1.1.1.1:443", Timeout: 3 * time.Second},
+		},
+		"tcp without port": {
+			s:   "tcp:1.1.1.1",
+			err: ErrHealthCheckInvalid,
+		},
+		"http with status": {
+			s: "http:https://example.com/generate_204#204@2s",
+			check: models.HealthCheck{Type: "http", Target: "https://example.com/generate_204",
+				Timeout: 2 * time.Second, Status: 204},
+		},
+		"http with user info": {
+			s: "http:http://user@example.com",
+			check: models.HealthCheck{Type: "http", Target: "http://user@example.com",
+				Timeout: defaultTimeout, Status: 200},
+		},
+		"http with invalid status": {
+			s:   "http:https://example.com#abc",
+			err: ErrHealthCheckInvalid,
+		},
+		"unknown type": {
+			s:   "udp:1.1.1.1:53",
+			err: ErrHealthCheckInvalid,
+		},
+		"missing type": {
+			s:   "github.com",
+			err: ErrHealthCheckInvalid,
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			check, err := parseHealthCheck(testCase.s, defaultTimeout)
+			assert.ErrorIs(t, err, testCase.err)
+			if testCase.err == nil {
+				assert.Equal(t, testCase.check, check)
+			}
+		})
+	}
+}
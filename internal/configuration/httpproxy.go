@@ -1,20 +1,60 @@
 package configuration
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/golibs/params"
 )
 
 // HTTPProxy contains settings to configure the HTTP proxy.
 type HTTPProxy struct {
-	User     string
-	Password string
-	Port     uint16
-	Enabled  bool
-	Stealth  bool
-	Log      bool
+	User     string `json:"user"`
+	Password string `json:"password"`
+	// Users are the users allowed to use the proxy in addition to User,
+	// each with their own password and optional restrictions.
+	Users   []HTTPProxyUser `json:"users"`
+	Port    uint16          `json:"port"`
+	Enabled bool            `json:"enabled"`
+	Stealth bool            `json:"stealth"`
+	// Log is the access log verbosity, which can be
+	// off, errors to log accesses denied or failing, or all.
+	Log string `json:"log"`
+	// PAC is true to serve a proxy auto-config file
+	// on the proxy port at /proxy.pac and /wpad.dat.
+	PAC bool `json:"pac"`
+	// PACDomains are the domains, with their subdomains, the
+	// proxy auto-config file routes through the proxy. It is
+	// empty to route all domains through the proxy.
+	PACDomains []string `json:"pac_domains"`
+	// MaxBandwidth is the maximum bandwidth in bytes per second
+	// shared by all the clients, and is 0 for no limit.
+	MaxBandwidth uint64 `json:"max_bandwidth"`
+	// ClientMaxBandwidth is the maximum bandwidth in bytes per second
+	// of each client IP address, and is 0 for no limit.
+	ClientMaxBandwidth uint64 `json:"client_max_bandwidth"`
+	// MaxConnections is the maximum number of concurrent
+	// connections, and is 0 for no limit.
+	MaxConnections int `json:"max_connections"`
+	// ClientMaxConnections is the maximum number of concurrent
+	// connections of each client IP address, and is 0 for no limit.
+	ClientMaxConnections int `json:"client_max_connections"`
+}
+
+// HTTPProxyUser is a user of the HTTP proxy.
+type HTTPProxyUser struct {
+	Name     string `json:"user"`
+	Password string `json:"password"`
+	// Ports are the destination ports the user can connect to,
+	// and is empty to allow all ports.
+	Ports []uint16 `json:"ports"`
+	// MaxBandwidth is the maximum bandwidth in bytes per second shared
+	// by all the connections of the user, and is 0 for no limit.
+	MaxBandwidth uint64 `json:"max_bandwidth"`
 }
 
 func (settings *HTTPProxy) String() string {
@@ -30,18 +70,52 @@ func (settings *HTTPProxy) lines() (lines []string) {
 
 	lines = append(lines, indent+lastIndent+"Port: "+strconv.Itoa(int(settings.Port)))
 
-	if settings.User != "" {
+	if settings.User != "" || len(settings.Users) > 0 {
 		lines = append(lines, indent+lastIndent+"Authentication: enabled")
 	}
 
-	if settings.Log {
-		lines = append(lines, indent+lastIndent+"Log: enabled")
+	if len(settings.Users) > 0 {
+		lines = append(lines, indent+lastIndent+"Users:")
+		for _, user := range settings.Users {
+			lines = append(lines, indent+indent+lastIndent+user.String())
+		}
+	}
+
+	if settings.Log != constants.HTTPProxyLogOff {
+		lines = append(lines, indent+lastIndent+"Access log: "+settings.Log)
 	}
 
 	if settings.Stealth {
 		lines = append(lines, indent+lastIndent+"Stealth: enabled")
 	}
 
+	if settings.MaxBandwidth > 0 {
+		lines = append(lines, indent+lastIndent+"Maximum bandwidth: "+
+			strconv.FormatUint(settings.MaxBandwidth, 10)+" bytes/s")
+	}
+
+	if settings.ClientMaxBandwidth > 0 {
+		lines = append(lines, indent+lastIndent+"Maximum bandwidth per client: "+
+			strconv.FormatUint(settings.ClientMaxBandwidth, 10)+" bytes/s")
+	}
+
+	if settings.MaxConnections > 0 {
+		lines = append(lines, indent+lastIndent+"Maximum connections: "+
+			strconv.Itoa(settings.MaxConnections))
+	}
+
+	if settings.ClientMaxConnections > 0 {
+		lines = append(lines, indent+lastIndent+"Maximum connections per client: "+
+			strconv.Itoa(settings.ClientMaxConnections))
+	}
+
+	if settings.PAC {
+		lines = append(lines, indent+lastIndent+"Proxy auto-config file: /proxy.pac")
+		if len(settings.PACDomains) > 0 {
+			lines = append(lines, indent+indent+lastIndent+"Domains: "+strings.Join(settings.PACDomains, ", "))
+		}
+	}
+
 	return lines
 }
 
@@ -64,6 +138,10 @@ func (settings *HTTPProxy) read(r reader) (err error) {
 		return err
 	}
 
+	if err := settings.readUsers(r); err != nil {
+		return err
+	}
+
 	settings.Stealth, err = r.env.OnOff("HTTPPROXY_STEALTH", params.Default("off"))
 	if err != nil {
 		return err
@@ -73,6 +151,20 @@ func (settings *HTTPProxy) read(r reader) (err error) {
 		return err
 	}
 
+	if err := settings.readLimits(r); err != nil {
+		return err
+	}
+
+	settings.PAC, err = r.env.OnOff("HTTPPROXY_PAC", params.Default("off"))
+	if err != nil {
+		return err
+	}
+
+	settings.PACDomains, err = r.env.CSV("HTTPPROXY_PAC_DOMAINS")
+	if err != nil {
+		return err
+	}
+
 	var warning string
 	settings.Port, warning, err = r.env.ListeningPort("HTTPPROXY_PORT", params.Default("8888"),
 		params.RetroKeys([]string{"TINYPROXY_PORT", "PROXY_PORT"}, r.onRetroActive))
@@ -86,6 +178,45 @@ func (settings *HTTPProxy) read(r reader) (err error) {
 	return nil
 }
 
+var ErrHTTPProxyBandwidthInvalid = errors.New("HTTP proxy bandwidth is not valid")
+
+func (settings *HTTPProxy) readLimits(r reader) (err error) {
+	settings.MaxBandwidth, err = readBandwidth(r, "HTTPPROXY_MAX_BANDWIDTH")
+	if err != nil {
+		return err
+	}
+
+	settings.ClientMaxBandwidth, err = readBandwidth(r, "HTTPPROXY_CLIENT_MAX_BANDWIDTH")
+	if err != nil {
+		return err
+	}
+
+	const maxConnections = 65535
+	settings.MaxConnections, err = r.env.IntRange("HTTPPROXY_MAX_CONNECTIONS", 0, maxConnections,
+		params.Default("0"))
+	if err != nil {
+		return err
+	}
+
+	settings.ClientMaxConnections, err = r.env.IntRange("HTTPPROXY_CLIENT_MAX_CONNECTIONS", 0, maxConnections,
+		params.Default("0"))
+	return err
+}
+
+// readBandwidth reads a bandwidth in bytes per second,
+// which is 0 if the environment variable is empty.
+func readBandwidth(r reader, key string) (bytesPerSecond uint64, err error) {
+	s, err := r.env.Get(key)
+	if err != nil || s == "" {
+		return 0, err
+	}
+	bytesPerSecond, err = strconv.ParseUint(s, 10, 64) //nolint:gomnd
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s: %s", ErrHTTPProxyBandwidthInvalid, key, err)
+	}
+	return bytesPerSecond, nil
+}
+
 func (settings *HTTPProxy) readLog(r reader) error {
 	s, err := r.env.Get("HTTPPROXY_LOG",
 		params.RetroKeys([]string{"PROXY_LOG_LEVEL", "TINYPROXY_LOG"}, r.onRetroActive))
@@ -94,11 +225,87 @@ func (settings *HTTPProxy) readLog(r reader) error {
 	}
 
 	switch strings.ToLower(s) {
-	case "on":
-		settings.Log = true
+	case "on", constants.HTTPProxyLogAll:
+		settings.Log = constants.HTTPProxyLogAll
+	case constants.HTTPProxyLogErrors:
+		settings.Log = constants.HTTPProxyLogErrors
 	// Retro compatibility
 	case "info", "connect", "notice":
-		settings.Log = true
+		settings.Log = constants.HTTPProxyLogAll
+	default:
+		settings.Log = constants.HTTPProxyLogOff
+	}
+
+	return nil
+}
+
+func (user *HTTPProxyUser) String() string {
+	s := user.Name
+	if len(user.Ports) > 0 {
+		s += " to ports " + strings.Join(uint16sToStrings(user.Ports), ", ")
+	}
+	if user.MaxBandwidth > 0 {
+		s += " up to " + strconv.FormatUint(user.MaxBandwidth, 10) + " bytes/s"
+	}
+	return s
+}
+
+var (
+	ErrHTTPProxyUserInvalid   = errors.New("HTTP proxy user is not valid")
+	ErrHTTPProxyUsersFile     = errors.New("cannot read HTTP proxy users file")
+	ErrHTTPProxyUserDuplicate = errors.New("HTTP proxy user is duplicated")
+)
+
+// readUsers reads the users from the HTTPPROXY_USERS environment variable,
+// as a comma separated list of user:password, and from the JSON file
+// HTTPPROXY_USERS_FILE which can also set restrictions for each user.
+func (settings *HTTPProxy) readUsers(r reader) (err error) {
+	pairs, err := r.env.CSV("HTTPPROXY_USERS", params.CaseSensitiveValue(), params.Unset())
+	if err != nil {
+		return err
+	}
+	for i, pair := range pairs {
+		const expectedFields = 2
+		fields := strings.SplitN(pair, ":", expectedFields)
+		if len(fields) != expectedFields || fields[0] == "" || fields[1] == "" {
+			// do not log the value which can contain a password
+			return fmt.Errorf("%w: user number %d must be in the format user:password",
+				ErrHTTPProxyUserInvalid, i+1)
+		}
+		settings.Users = append(settings.Users, HTTPProxyUser{
+			Name:     fields[0],
+			Password: fields[1],
+		})
+	}
+
+	// Path is not used since it returns the working directory if unset
+	filepath, err := r.env.Get("HTTPPROXY_USERS_FILE", params.CaseSensitiveValue())
+	if err != nil {
+		return err
+	} else if filepath != "" {
+		b, err := readFromFile(r.os.OpenFile, filepath)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrHTTPProxyUsersFile, err)
+		}
+		var users []HTTPProxyUser
+		if err := json.Unmarshal(b, &users); err != nil {
+			return fmt.Errorf("%w: %s", ErrHTTPProxyUsersFile, err)
+		}
+		settings.Users = append(settings.Users, users...)
+	}
+
+	names := make(map[string]struct{}, len(settings.Users)+1)
+	if settings.User != "" {
+		names[settings.User] = struct{}{}
+	}
+	for _, user := range settings.Users {
+		if user.Name == "" || user.Password == "" {
+			return fmt.Errorf("%w: %q must have a user and a password",
+				ErrHTTPProxyUserInvalid, user.Name)
+		} else if _, ok := names[user.Name]; ok {
+			return fmt.Errorf("%w: %s", ErrHTTPProxyUserDuplicate, user.Name)
+		}
+		names[user.Name] = struct{}{}
 	}
 
 	return nil
@@ -0,0 +1,67 @@
+package configuration
+
+import (
+	"io"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/golibs/os"
+	"github.com/qdm12/golibs/os/mock_os"
+	"github.com/qdm12/golibs/params/mock_params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HTTPProxy_readUsers(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		users     []string
+		usersFile string
+		fileData  string
+		expected  []HTTPProxyUser
+	}{
+		"no users": {},
+		"users from environment": {
+			users: []string{"a:1", "b:2"},
+			expected: []HTTPProxyUser{
+				{Name: "a", Password: "1"},
+				{Name: "b", Password: "2"},
+			},
+		},
+		"users from file": {
+			usersFile: "/gluetun/users.json",
+			fileData:  `[{"user":"a","password":"1"}]`,
+			expected:  []HTTPProxyUser{{Name: "a", Password: "1"}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			env := mock_params.NewMockEnv(ctrl)
+			env.EXPECT().CSV("HTTPPROXY_USERS", gomock.Any()).Return(testCase.users, nil)
+			env.EXPECT().Get("HTTPPROXY_USERS_FILE", gomock.Any()).Return(testCase.usersFile, nil)
+			osMock := mock_os.NewMockOS(ctrl)
+			if testCase.usersFile != "" {
+				file := mock_os.NewMockFile(ctrl)
+				file.EXPECT().Read(gomock.Any()).DoAndReturn(func(b []byte) (int, error) {
+					return copy(b, testCase.fileData), nil
+				})
+				file.EXPECT().Read(gomock.Any()).Return(0, io.EOF)
+				file.EXPECT().Close().Return(nil)
+				osMock.EXPECT().OpenFile(testCase.usersFile, os.O_RDONLY, os.FileMode(0)).
+					Return(file, nil)
+			}
+
+			var settings HTTPProxy
+			err := settings.readUsers(reader{env: env, os: osMock})
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, settings.Users)
+		})
+	}
+}
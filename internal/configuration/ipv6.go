@@ -0,0 +1,31 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/golibs/params"
+)
+
+var ErrOpenVPNIPv6NotSupported = errors.New("IPv6 through the tunnel is not supported")
+
+// readOpenVPNIPv6 reads if the IPv6 address and routes pushed by the VPN
+// server should be used, which is only possible for providers capable of
+// it or with a custom OpenVPN configuration.
+func (options *ExtraConfigOptions) readOpenVPNIPv6(env params.Env,
+	providerName string, customConfig bool) (err error) {
+	options.OpenVPNIPv6, err = env.OnOff("OPENVPN_IPV6", params.Default("off"))
+	if err != nil {
+		return err
+	}
+
+	if !options.OpenVPNIPv6 || customConfig {
+		return nil
+	}
+
+	if !constants.ProviderCapabilities()[providerName].OpenVPNIPv6 {
+		return fmt.Errorf("%w: for VPN provider %s", ErrOpenVPNIPv6NotSupported, providerName)
+	}
+	return nil
+}
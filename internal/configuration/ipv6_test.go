@@ -0,0 +1,68 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/golibs/params/mock_params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ExtraConfigOptions_readOpenVPNIPv6(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		providerName string
+		customConfig bool
+		mockEnabled  bool
+		enabled      bool
+		err          error
+	}{
+		"disabled": {
+			providerName: constants.Nordvpn,
+		},
+		"supported provider": {
+			providerName: constants.Mullvad,
+			mockEnabled:  true,
+			enabled:      true,
+		},
+		"custom config": {
+			providerName: constants.Nordvpn,
+			customConfig: true,
+			mockEnabled:  true,
+			enabled:      true,
+		},
+		"unsupported provider": {
+			providerName: constants.Nordvpn,
+			mockEnabled:  true,
+			enabled:      true,
+			err:          ErrOpenVPNIPv6NotSupported,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			env := mock_params.NewMockEnv(ctrl)
+			env.EXPECT().
+				OnOff("OPENVPN_IPV6", gomock.Any()).
+				Return(testCase.mockEnabled, nil)
+
+			var options ExtraConfigOptions
+			err := options.readOpenVPNIPv6(env, testCase.providerName, testCase.customConfig)
+
+			if testCase.err != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, testCase.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.enabled, options.OpenVPNIPv6)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package configuration
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/qdm12/gluetun/internal/constants"
@@ -24,13 +25,20 @@ func (settings *Provider) mullvadLines() (lines []string) {
 		lines = append(lines, lastIndent+"Custom port: "+strconv.Itoa(int(settings.ServerSelection.CustomPort)))
 	}
 
-	if settings.ExtraConfigOptions.OpenVPNIPv6 {
-		lines = append(lines, lastIndent+"IPv6: enabled")
+	if settings.ServerSelection.MultiHopExitCity != "" {
+		lines = append(lines, lastIndent+"Multi-hop:")
+		lines = append(lines, indent+lastIndent+"Entry city: "+settings.ServerSelection.MultiHopEntryCity)
+		lines = append(lines, indent+lastIndent+"Exit city: "+settings.ServerSelection.MultiHopExitCity)
 	}
 
 	return lines
 }
 
+var (
+	ErrMullvadMultiHopCities     = errors.New("mullvad multi-hop requires both an entry and an exit city")
+	ErrMullvadMultiHopCustomPort = errors.New("mullvad multi-hop cannot be used with a custom port")
+)
+
 func (settings *Provider) readMullvad(r reader) (err error) {
 	settings.Name = constants.Mullvad
 
@@ -70,10 +78,28 @@ func (settings *Provider) readMullvad(r reader) (err error) {
 		return err
 	}
 
-	settings.ExtraConfigOptions.OpenVPNIPv6, err = r.env.OnOff("OPENVPN_IPV6", params.Default("off"))
+	return settings.ServerSelection.readMullvadMultiHop(r.env)
+}
+
+func (selection *ServerSelection) readMullvadMultiHop(env params.Env) (err error) {
+	selection.MultiHopEntryCity, err = env.Inside("MULLVAD_MULTIHOP_ENTRY_CITY", constants.MullvadCityChoices())
+	if err != nil {
+		return err
+	}
+
+	selection.MultiHopExitCity, err = env.Inside("MULLVAD_MULTIHOP_EXIT_CITY", constants.MullvadCityChoices())
 	if err != nil {
 		return err
 	}
 
+	switch {
+	case selection.MultiHopEntryCity == "" && selection.MultiHopExitCity == "":
+		return nil
+	case selection.MultiHopEntryCity == "" || selection.MultiHopExitCity == "":
+		return ErrMullvadMultiHopCities
+	case selection.CustomPort > 0:
+		return ErrMullvadMultiHopCustomPort
+	}
+
 	return nil
 }
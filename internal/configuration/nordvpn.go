@@ -21,6 +21,8 @@ func (settings *Provider) nordvpnLines() (lines []string) {
 		lines = append(lines, lastIndent+"Numbers: "+commaJoin(numbersString))
 	}
 
+	lines = append(lines, settings.ServerSelection.MultiHop.lines()...)
+
 	return lines
 }
 
@@ -47,6 +49,10 @@ func (settings *Provider) readNordvpn(r reader) (err error) {
 		return err
 	}
 
+	if err := settings.ServerSelection.MultiHop.read(r.env); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -0,0 +1,65 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/golibs/params"
+)
+
+// Obfuscation contains settings to wrap the OpenVPN TCP connection
+// in a TLS tunnel with stunnel, for networks blocking OpenVPN traffic.
+type Obfuscation struct {
+	// Method is empty if obfuscation is disabled,
+	// and stunnel is the only method supported.
+	Method     string `json:"method"`
+	ServerPort uint16 `json:"server_port"`
+	LocalPort  uint16 `json:"local_port"`
+}
+
+func (o *Obfuscation) lines() (lines []string) {
+	if o.Method == "" {
+		return nil
+	}
+
+	lines = append(lines, lastIndent+"Obfuscation:")
+	lines = append(lines, indent+lastIndent+"Method: "+o.Method)
+	lines = append(lines, indent+lastIndent+"Server port: "+strconv.Itoa(int(o.ServerPort)))
+	lines = append(lines, indent+lastIndent+"Local port: "+strconv.Itoa(int(o.LocalPort)))
+
+	return lines
+}
+
+var (
+	ErrObfuscationProtocol = errors.New("obfuscation requires the tcp protocol")
+)
+
+func (o *Obfuscation) read(env params.Env, protocol string) (err error) {
+	o.Method, err = env.Inside("OPENVPN_OBFUSCATION",
+		[]string{"off", constants.Stunnel}, params.Default("off"))
+	if err != nil {
+		return err
+	}
+	if o.Method == "off" {
+		o.Method = ""
+		return nil
+	}
+
+	if protocol != constants.TCP {
+		return fmt.Errorf("%w: protocol is %s", ErrObfuscationProtocol, protocol)
+	}
+
+	o.ServerPort, err = env.Port("OBFUSCATION_SERVER_PORT", params.Default("443"))
+	if err != nil {
+		return err
+	}
+
+	o.LocalPort, err = env.Port("OBFUSCATION_LOCAL_PORT", params.Default("1195"))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/golibs/params"
@@ -21,6 +22,39 @@ type OpenVPN struct {
 	Auth      string   `json:"auth"`
 	Provider  Provider `json:"provider"`
 	Config    string   `json:"custom_config"`
+	// CustomRemotes contains settings to pick the remote of the
+	// custom configuration, and is ignored if Config is empty.
+	CustomRemotes CustomRemotes `json:"custom_remotes"`
+	// RotationPeriod is the period after which the client reconnects
+	// to a different server, and is disabled if set to 0.
+	RotationPeriod time.Duration `json:"rotation_period"`
+	// SwitchFailures is the number of failed connection attempts within
+	// the failure cooldown after which a server is avoided for the failure
+	// cooldown, the next candidate server being used instead. It is
+	// disabled if set to 0, which is the default.
+	SwitchFailures  int           `json:"switch_failures"`
+	FailureCooldown time.Duration `json:"failure_cooldown"`
+	// StickyServer is true if the last server connected to should be
+	// persisted to file and connected to again after a restart.
+	StickyServer bool `json:"sticky_server"`
+	// MTUDiscovery is true if the tunnel MTU should be discovered once
+	// connected, to reconnect with a lower MTU if needed. It is ignored
+	// if MSSFix is set.
+	MTUDiscovery bool `json:"mtu_discovery"`
+	// TCPFallback is true if the connection should be retried over TCP
+	// after several consecutive failures over UDP.
+	TCPFallback bool `json:"tcp_fallback"`
+	// SeamlessSwitch is true if the tunnel to the new server should be
+	// up before tearing down the current one when switching servers.
+	SeamlessSwitch bool          `json:"seamless_switch"`
+	Obfuscation    Obfuscation   `json:"obfuscation"`
+	UpstreamProxy  UpstreamProxy `json:"upstream_proxy"`
+	Binding        Binding       `json:"binding"`
+	StaticServer   StaticServer  `json:"static_server"`
+	// Backup contains the settings of the backup VPN connection,
+	// and is nil if no backup is configured.
+	Backup   *OpenVPN `json:"backup,omitempty"`
+	Failover Failover `json:"failover"`
 }
 
 func (settings *OpenVPN) String() string {
@@ -45,6 +79,69 @@ func (settings *OpenVPN) lines() (lines []string) {
 
 	if len(settings.Config) > 0 {
 		lines = append(lines, indent+lastIndent+"Custom configuration: "+settings.Config)
+		for _, line := range settings.CustomRemotes.lines() {
+			lines = append(lines, indent+line)
+		}
+		if settings.Provider.PortForwarding.Enabled {
+			lines = append(lines, indent+lastIndent+"Port forwarding with NAT-PMP:")
+			for _, line := range settings.Provider.PortForwarding.lines() {
+				lines = append(lines, indent+indent+line)
+			}
+		}
+	}
+
+	if settings.RotationPeriod > 0 {
+		lines = append(lines, indent+lastIndent+"Server rotation period: "+settings.RotationPeriod.String())
+	}
+
+	if settings.SwitchFailures > 0 {
+		lines = append(lines, indent+lastIndent+"Switch server after failures: "+strconv.Itoa(settings.SwitchFailures))
+		lines = append(lines, indent+lastIndent+"Failed server cooldown: "+settings.FailureCooldown.String())
+	}
+
+	if settings.StickyServer {
+		lines = append(lines, indent+lastIndent+"Sticky server: enabled")
+	}
+
+	if settings.MSSFix > 0 {
+		lines = append(lines, indent+lastIndent+"MSS fix: "+strconv.Itoa(int(settings.MSSFix)))
+	} else if settings.MTUDiscovery {
+		lines = append(lines, indent+lastIndent+"MTU discovery: enabled")
+	}
+
+	if settings.TCPFallback && len(settings.Config) == 0 {
+		lines = append(lines, indent+lastIndent+"TCP fallback: enabled")
+	}
+
+	if settings.SeamlessSwitch {
+		lines = append(lines, indent+lastIndent+"Seamless server switch: enabled")
+	}
+
+	for _, line := range settings.Obfuscation.lines() {
+		lines = append(lines, indent+line)
+	}
+
+	for _, line := range settings.UpstreamProxy.lines() {
+		lines = append(lines, indent+line)
+	}
+
+	for _, line := range settings.Binding.lines() {
+		lines = append(lines, indent+line)
+	}
+
+	for _, line := range settings.StaticServer.lines() {
+		lines = append(lines, indent+line)
+	}
+
+	if settings.Backup != nil {
+		for _, line := range settings.Failover.lines() {
+			lines = append(lines, indent+line)
+		}
+		backupLines := settings.Backup.lines()
+		backupLines[0] = lastIndent + "Backup OpenVPN:"
+		for _, line := range backupLines {
+			lines = append(lines, indent+line)
+		}
 	}
 
 	lines = append(lines, indent+lastIndent+"Provider:")
@@ -56,14 +153,12 @@ func (settings *OpenVPN) lines() (lines []string) {
 }
 
 var (
-	ErrInvalidVPNProvider = errors.New("invalid VPN provider")
+	ErrInvalidVPNProvider              = errors.New("invalid VPN provider")
+	ErrPortForwardingCountNotSupported = errors.New("forwarding more than one port is not supported")
 )
 
 func (settings *OpenVPN) read(r reader) (err error) {
-	vpnsp, err := r.env.Inside("VPNSP", []string{
-		"cyberghost", "fastestvpn", "hidemyass", "mullvad", "nordvpn",
-		"privado", "pia", "private internet access", "privatevpn",
-		"purevpn", "surfshark", "torguard", "vyprvpn", "windscribe"},
+	vpnsp, err := r.env.Inside("VPNSP", append(constants.VPNProviders(), "pia"),
 		params.Default("private internet access"))
 	if err != nil {
 		return err
@@ -79,6 +174,12 @@ func (settings *OpenVPN) read(r reader) (err error) {
 		return err
 	}
 
+	if len(settings.Config) > 0 {
+		if err := settings.CustomRemotes.read(r.env); err != nil {
+			return err
+		}
+	}
+
 	credentialsRequired := len(settings.Config) == 0
 
 	settings.User, err = r.getFromEnvOrSecretFile("OPENVPN_USER", credentialsRequired, []string{"USER"})
@@ -97,6 +198,10 @@ func (settings *OpenVPN) read(r reader) (err error) {
 		}
 	}
 
+	if err := settings.Provider.ExtraConfigOptions.readTLSCrypt(r); err != nil {
+		return err
+	}
+
 	settings.Verbosity, err = r.env.IntRange("OPENVPN_VERBOSITY", 0, 6, params.Default("1"))
 	if err != nil {
 		return err
@@ -123,37 +228,163 @@ func (settings *OpenVPN) read(r reader) (err error) {
 	}
 	settings.MSSFix = uint16(mssFix)
 
+	settings.RotationPeriod, err = r.env.Duration("SERVER_ROTATION_PERIOD", params.Default("0"))
+	if err != nil {
+		return err
+	}
+
+	settings.SwitchFailures, err = r.env.IntRange("SERVER_SWITCH_FAILURES", 0, 100, params.Default("0"))
+	if err != nil {
+		return err
+	}
+
+	settings.FailureCooldown, err = r.env.Duration("SERVER_FAILURE_COOLDOWN", params.Default("30m"))
+	if err != nil {
+		return err
+	}
+
+	settings.StickyServer, err = r.env.OnOff("SERVER_STICKY", params.Default("off"))
+	if err != nil {
+		return err
+	}
+
+	settings.MTUDiscovery, err = r.env.OnOff("OPENVPN_MTU_DISCOVERY", params.Default("off"))
+	if err != nil {
+		return err
+	}
+
+	settings.TCPFallback, err = r.env.OnOff("OPENVPN_TCP_FALLBACK", params.Default("off"))
+	if err != nil {
+		return err
+	}
+
+	if err := settings.Provider.readProviderSettings(r); err != nil {
+		return err
+	}
+
+	if len(settings.Config) > 0 {
+		// ports are forwarded using NAT-PMP with a custom configuration
+		if err := settings.readCustomPortForwarding(r); err != nil {
+			return err
+		}
+	} else if settings.Provider.PortForwarding.Count > 1 {
+		return fmt.Errorf("%w by %s, only with a custom configuration",
+			ErrPortForwardingCountNotSupported, settings.Provider.Name)
+	}
+
+	err = settings.Provider.ExtraConfigOptions.readOpenVPNIPv6(r.env,
+		settings.Provider.Name, len(settings.Config) > 0)
+	if err != nil {
+		return err
+	}
+
+	if settings.Provider.Name != constants.Cyberghost { // required and read with the Cyberghost settings
+		if err := settings.Provider.ExtraConfigOptions.readClientCertificate(r, false); err != nil {
+			return err
+		}
+	}
+
+	if err := settings.Provider.ServerSelection.Latency.read(r.env); err != nil {
+		return err
+	}
+
+	settings.Provider.ServerSelection.Features, err = readServerFeatures(r.env, settings.Provider.Name)
+	if err != nil {
+		return err
+	}
+
+	protocol := settings.Provider.ServerSelection.Protocol
+	if len(settings.Config) > 0 {
+		// the custom configuration protocol is checked at runtime
+		protocol = constants.TCP
+	}
+	if err := settings.Obfuscation.read(r.env, protocol); err != nil {
+		return err
+	}
+
+	if err := settings.UpstreamProxy.read(r, protocol); err != nil {
+		return err
+	} else if settings.UpstreamProxy.Type != "" && settings.Obfuscation.Method != "" {
+		return ErrUpstreamProxyObfuscation
+	}
+
+	if err := settings.Binding.read(r.env); err != nil {
+		return err
+	}
+
+	if err := settings.readSeamlessSwitch(r.env); err != nil {
+		return err
+	}
+
+	err = settings.StaticServer.read(r.env, settings.Provider.ServerSelection.TargetIP)
+	if err != nil {
+		return err
+	}
+
+	return settings.readBackup(r)
+}
+
+// readProviderSettings reads the settings specific to the VPN provider.
+func (settings *Provider) readProviderSettings(r reader) (err error) {
 	var readProvider func(r reader) error
-	switch settings.Provider.Name {
+	switch settings.Name {
 	case constants.Cyberghost:
-		readProvider = settings.Provider.readCyberghost
+		readProvider = settings.readCyberghost
 	case constants.Fastestvpn:
-		readProvider = settings.Provider.readFastestvpn
+		readProvider = settings.readFastestvpn
 	case constants.HideMyAss:
-		readProvider = settings.Provider.readHideMyAss
+		readProvider = settings.readHideMyAss
 	case constants.Mullvad:
-		readProvider = settings.Provider.readMullvad
+		readProvider = settings.readMullvad
 	case constants.Nordvpn:
-		readProvider = settings.Provider.readNordvpn
+		readProvider = settings.readNordvpn
 	case constants.Privado:
-		readProvider = settings.Provider.readPrivado
+		readProvider = settings.readPrivado
 	case constants.PrivateInternetAccess:
-		readProvider = settings.Provider.readPrivateInternetAccess
+		readProvider = settings.readPrivateInternetAccess
 	case constants.Privatevpn:
-		readProvider = settings.Provider.readPrivatevpn
+		readProvider = settings.readPrivatevpn
 	case constants.Purevpn:
-		readProvider = settings.Provider.readPurevpn
+		readProvider = settings.readPurevpn
 	case constants.Surfshark:
-		readProvider = settings.Provider.readSurfshark
+		readProvider = settings.readSurfshark
 	case constants.Torguard:
-		readProvider = settings.Provider.readTorguard
+		readProvider = settings.readTorguard
 	case constants.Vyprvpn:
-		readProvider = settings.Provider.readVyprvpn
+		readProvider = settings.readVyprvpn
 	case constants.Windscribe:
-		readProvider = settings.Provider.readWindscribe
+		readProvider = settings.readWindscribe
 	default:
-		return fmt.Errorf("%w: %s", ErrInvalidVPNProvider, settings.Provider.Name)
+		return fmt.Errorf("%w: %s", ErrInvalidVPNProvider, settings.Name)
 	}
-
 	return readProvider(r)
 }
+
+// readCustomPortForwarding reads the NAT-PMP port forwarding settings of a
+// custom configuration. For ProtonVPN, a single port can be forwarded, and
+// the port forwarding suffix is added to the user name if it is missing.
+func (settings *OpenVPN) readCustomPortForwarding(r reader) (err error) {
+	portForwarding := &settings.Provider.PortForwarding
+	if err := portForwarding.read(r); err != nil || !portForwarding.Enabled {
+		return err
+	}
+
+	portForwarding.Provider, err = r.env.Inside("PORT_FORWARDING_PROVIDER",
+		[]string{constants.PortForwardProviderProtonvpn, ""})
+	if err != nil {
+		return err
+	}
+
+	if portForwarding.Provider != constants.PortForwardProviderProtonvpn {
+		return nil
+	}
+
+	if portForwarding.Count > 1 {
+		return fmt.Errorf("%w by %s", ErrPortForwardingCountNotSupported, portForwarding.Provider)
+	}
+
+	if !strings.HasSuffix(settings.User, constants.ProtonvpnPortForwardSuffix) {
+		settings.User += constants.ProtonvpnPortForwardSuffix
+	}
+	return nil
+}
@@ -4,6 +4,10 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/qdm12/golibs/os/mock_os"
+	"github.com/qdm12/golibs/params"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -19,9 +23,80 @@ func Test_OpenVPN_JSON(t *testing.T) {
 	data, err := json.Marshal(in)
 	require.NoError(t, err)
 	//nolint:lll
-	assert.Equal(t, `{"user":"","password":"","verbosity":0,"mssfix":0,"run_as_root":true,"cipher":"","auth":"","provider":{"name":"name","server_selection":{"network_protocol":"","regions":null,"group":"","countries":null,"cities":null,"hostnames":null,"isps":null,"owned":false,"custom_port":0,"numbers":null,"encryption_preset":""},"extra_config":{"encryption_preset":"","openvpn_ipv6":false},"port_forwarding":{"enabled":false,"filepath":""}},"custom_config":""}`, string(data))
+	assert.Equal(t, `{"user":"","password":"","verbosity":0,"mssfix":0,"run_as_root":true,"cipher":"","auth":"","provider":{"name":"name","server_selection":{"network_protocol":"","latency":{"enabled":false,"timeout":0,"candidates":0},"regions":null,"group":"","countries":null,"cities":null,"hostnames":null,"features":null,"isps":null,"owned":false,"multihop_entry_city":"","multihop_exit_city":"","custom_port":0,"numbers":null,"multihop":{"only":false,"entry_countries":null,"exit_countries":null},"encryption_preset":""},"extra_config":{"encryption_preset":"","openvpn_ipv6":false},"port_forwarding":{"enabled":false,"filepath":"","count":0,"provider":"","up_command":"","torrent_client":{"name":"","url":"","user":"","password":""},"proxies":null,"proxy_ports":null}},"custom_config":"","custom_remotes":{"remotes":null,"random":false,"retries":0},"rotation_period":0,"switch_failures":0,"failure_cooldown":0,"sticky_server":false,"mtu_discovery":false,"tcp_fallback":false,"seamless_switch":false,"obfuscation":{"method":"","server_port":0,"local_port":0},"upstream_proxy":{"type":"","ip":"","port":0,"user":""},"binding":{"local_port":0,"address":"","fwmark":0},"static_server":{"hostname":"","resolver":"","check_period":0},"failover":{"threshold":0,"failback_period":0}}`, string(data))
 	var out OpenVPN
 	err = json.Unmarshal(data, &out)
 	require.NoError(t, err)
 	assert.Equal(t, in, out)
 }
+
+// Test_OpenVPN_readCustomPortForwarding is not parallel since
+// it reads the settings from the process environment.
+func Test_OpenVPN_readCustomPortForwarding(t *testing.T) { //nolint:paralleltest
+	testCases := map[string]struct {
+		env         map[string]string
+		initialUser string
+		user        string
+		provider    string
+		err         error
+	}{
+		"disabled": {
+			env:  map[string]string{"PORT_FORWARDING": "off"},
+			user: "user",
+		},
+		"generic NAT-PMP": {
+			env:  map[string]string{"PORT_FORWARDING": "on", "PORT_FORWARDING_COUNT": "2"},
+			user: "user",
+		},
+		"protonvpn": {
+			env: map[string]string{
+				"PORT_FORWARDING":          "on",
+				"PORT_FORWARDING_PROVIDER": "protonvpn",
+			},
+			user:     "user+pmp",
+			provider: "protonvpn",
+		},
+		"protonvpn with suffixed user": {
+			env: map[string]string{
+				"PORT_FORWARDING":          "on",
+				"PORT_FORWARDING_PROVIDER": "protonvpn",
+			},
+			initialUser: "user+pmp",
+			user:        "user+pmp",
+			provider:    "protonvpn",
+		},
+		"protonvpn with more than one port": {
+			env: map[string]string{
+				"PORT_FORWARDING":          "on",
+				"PORT_FORWARDING_COUNT":    "2",
+				"PORT_FORWARDING_PROVIDER": "protonvpn",
+			},
+			user:     "user",
+			provider: "protonvpn",
+			err:      ErrPortForwardingCountNotSupported,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			for _, key := range []string{"PORT_FORWARDING", "PORT_FORWARDING_COUNT",
+				"PORT_FORWARDING_PROVIDER"} {
+				t.Setenv(key, testCase.env[key])
+			}
+			ctrl := gomock.NewController(t)
+			osMock := mock_os.NewMockOS(ctrl)
+			r := newReader(params.NewEnv(), osMock, mock_logging.NewMockLogger(ctrl))
+
+			settings := OpenVPN{User: "user"}
+			if testCase.initialUser != "" {
+				settings.User = testCase.initialUser
+			}
+			err := settings.readCustomPortForwarding(r)
+
+			assert.ErrorIs(t, err, testCase.err)
+			assert.Equal(t, testCase.user, settings.User)
+			assert.Equal(t, testCase.provider, settings.Provider.PortForwarding.Provider)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package configuration
+
+import (
+	liburl "net/url"
+	"time"
+
+	"github.com/qdm12/golibs/logging"
+	"github.com/qdm12/golibs/params"
+)
+
+// prefixedEnv reads environment variables with their keys prefixed,
+// to read the same settings structure from different variables.
+type prefixedEnv struct {
+	env    params.Env
+	prefix string
+}
+
+func (p *prefixedEnv) Get(key string, optionSetters ...params.OptionSetter) (value string, err error) {
+	return p.env.Get(p.prefix+key, optionSetters...)
+}
+
+func (p *prefixedEnv) Int(key string, optionSetters ...params.OptionSetter) (n int, err error) {
+	return p.env.Int(p.prefix+key, optionSetters...)
+}
+
+func (p *prefixedEnv) IntRange(key string, lower, upper int, optionSetters ...params.OptionSetter) (
+	n int, err error) {
+	return p.env.IntRange(p.prefix+key, lower, upper, optionSetters...)
+}
+
+func (p *prefixedEnv) YesNo(key string, optionSetters ...params.OptionSetter) (yes bool, err error) {
+	return p.env.YesNo(p.prefix+key, optionSetters...)
+}
+
+func (p *prefixedEnv) OnOff(key string, optionSetters ...params.OptionSetter) (on bool, err error) {
+	return p.env.OnOff(p.prefix+key, optionSetters...)
+}
+
+func (p *prefixedEnv) Inside(key string, possibilities []string, optionSetters ...params.OptionSetter) (
+	value string, err error) {
+	return p.env.Inside(p.prefix+key, possibilities, optionSetters...)
+}
+
+func (p *prefixedEnv) CSV(key string, optionSetters ...params.OptionSetter) (values []string, err error) {
+	return p.env.CSV(p.prefix+key, optionSetters...)
+}
+
+func (p *prefixedEnv) CSVInside(key string, possibilities []string, optionSetters ...params.OptionSetter) (
+	values []string, err error) {
+	return p.env.CSVInside(p.prefix+key, possibilities, optionSetters...)
+}
+
+func (p *prefixedEnv) Duration(key string, optionSetters ...params.OptionSetter) (
+	duration time.Duration, err error) {
+	return p.env.Duration(p.prefix+key, optionSetters...)
+}
+
+func (p *prefixedEnv) Port(key string, optionSetters ...params.OptionSetter) (port uint16, err error) {
+	return p.env.Port(p.prefix+key, optionSetters...)
+}
+
+func (p *prefixedEnv) ListeningPort(key string, optionSetters ...params.OptionSetter) (
+	port uint16, warning string, err error) {
+	return p.env.ListeningPort(p.prefix+key, optionSetters...)
+}
+
+func (p *prefixedEnv) ListeningAddress(key string, optionSetters ...params.OptionSetter) (
+	address, warning string, err error) {
+	return p.env.ListeningAddress(p.prefix+key, optionSetters...)
+}
+
+func (p *prefixedEnv) RootURL(key string, optionSetters ...params.OptionSetter) (rootURL string, err error) {
+	return p.env.RootURL(p.prefix+key, optionSetters...)
+}
+
+func (p *prefixedEnv) Path(key string, optionSetters ...params.OptionSetter) (path string, err error) {
+	return p.env.Path(p.prefix+key, optionSetters...)
+}
+
+func (p *prefixedEnv) LogCaller(key string, optionSetters ...params.OptionSetter) (
+	caller logging.Caller, err error) {
+	return p.env.LogCaller(p.prefix+key, optionSetters...)
+}
+
+func (p *prefixedEnv) LogLevel(key string, optionSetters ...params.OptionSetter) (
+	level logging.Level, err error) {
+	return p.env.LogLevel(p.prefix+key, optionSetters...)
+}
+
+func (p *prefixedEnv) URL(key string, optionSetters ...params.OptionSetter) (URL *liburl.URL, err error) {
+	return p.env.URL(p.prefix+key, optionSetters...)
+}
@@ -12,6 +12,10 @@ func (settings *Provider) privateinternetaccessLines() (lines []string) {
 		lines = append(lines, lastIndent+"Regions: "+commaJoin(settings.ServerSelection.Regions))
 	}
 
+	if settings.ServerSelection.DedicatedIPToken != "" {
+		lines = append(lines, lastIndent+"Dedicated IP: enabled")
+	}
+
 	lines = append(lines, lastIndent+"Encryption preset: "+settings.ServerSelection.EncryptionPreset)
 
 	lines = append(lines, lastIndent+"Custom port: "+strconv.Itoa(int(settings.ServerSelection.CustomPort)))
@@ -60,18 +64,10 @@ func (settings *Provider) readPrivateInternetAccess(r reader) (err error) {
 		return err
 	}
 
-	settings.PortForwarding.Enabled, err = r.env.OnOff("PORT_FORWARDING", params.Default("off"))
+	settings.ServerSelection.DedicatedIPToken, err = r.getFromEnvOrSecretFile("PIA_DIP_TOKEN", false, nil)
 	if err != nil {
 		return err
 	}
 
-	if settings.PortForwarding.Enabled {
-		settings.PortForwarding.Filepath, err = r.env.Path("PORT_FORWARDING_STATUS_FILE",
-			params.Default("/tmp/gluetun/forwarded_port"), params.CaseSensitiveValue())
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return settings.PortForwarding.read(r)
 }
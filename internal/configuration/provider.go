@@ -27,6 +27,14 @@ func (settings *Provider) lines() (lines []string) {
 		lines = append(lines, indent+lastIndent+"Target IP address: "+settings.ServerSelection.TargetIP.String())
 	}
 
+	for _, line := range settings.ServerSelection.Latency.lines() {
+		lines = append(lines, indent+line)
+	}
+
+	if len(settings.ServerSelection.Features) > 0 {
+		lines = append(lines, indent+lastIndent+"Server features: "+commaJoin(settings.ServerSelection.Features))
+	}
+
 	var providerLines []string
 	switch strings.ToLower(settings.Name) {
 	case "cyberghost":
@@ -64,6 +72,26 @@ func (settings *Provider) lines() (lines []string) {
 		lines = append(lines, indent+line)
 	}
 
+	if settings.ExtraConfigOptions.OpenVPNIPv6 {
+		lines = append(lines, indent+lastIndent+"IPv6: enabled")
+	}
+
+	if settings.ExtraConfigOptions.ClientKey != "" {
+		lines = append(lines, indent+lastIndent+"Client key: [redacted]")
+	}
+
+	if settings.ExtraConfigOptions.ClientCertificate != "" {
+		lines = append(lines, indent+lastIndent+"Client certificate: [redacted]")
+	}
+
+	if settings.ExtraConfigOptions.TLSCrypt != "" {
+		lines = append(lines, indent+lastIndent+"TLS crypt key: [redacted]")
+	}
+
+	if settings.ExtraConfigOptions.TLSCryptV2 != "" {
+		lines = append(lines, indent+lastIndent+"TLS crypt v2 key: [redacted]")
+	}
+
 	return lines
 }
 
@@ -38,8 +38,8 @@ func Test_Provider_lines(t *testing.T) {
 				"   |--Network protocol: udp",
 				"   |--Server group: group",
 				"   |--Regions: a, El country",
-				"   |--Client key is set",
-				"   |--Client certificate is set",
+				"   |--Client key: [redacted]",
+				"   |--Client certificate: [redacted]",
 			},
 		},
 		"fastestvpn": {
@@ -158,8 +158,9 @@ func Test_Provider_lines(t *testing.T) {
 					CustomPort:       1,
 				},
 				PortForwarding: PortForwarding{
-					Enabled:  true,
-					Filepath: string("/here"),
+					Enabled:   true,
+					Filepath:  string("/here"),
+					UpCommand: "echo $1",
 				},
 			},
 			lines: []string{
@@ -170,6 +171,7 @@ func Test_Provider_lines(t *testing.T) {
 				"   |--Custom port: 1",
 				"   |--Port forwarding:",
 				"      |--File path: /here",
+				"      |--Up command: [redacted]",
 			},
 		},
 		"purevpn": {
@@ -1,15 +1,48 @@
 package configuration
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/golibs/params"
 )
 
 type PublicIP struct {
 	Period     time.Duration `json:"period"`
 	IPFilepath string        `json:"ip_filepath"`
+	// Fetchers are the fetchers of the public IP address,
+	// tried in order until one of them succeeds.
+	Fetchers []string `json:"fetchers"`
+	// Consensus is the number of fetchers which must
+	// find the same public IP address for it to be used.
+	Consensus int `json:"consensus"`
+	// Timeout is the timeout of each fetcher.
+	Timeout time.Duration `json:"timeout"`
+	// IPv6 is true to also fetch the public IPv6 address, and is
+	// set if IPv6 is enabled through the VPN tunnel.
+	IPv6 bool `json:"ipv6"`
+	// InfoProvider is the provider of information on the public IP
+	// address, such as its location and organization, and is none
+	// to not fetch information.
+	InfoProvider string `json:"info_provider"`
+	// InfoAccountID is the account ID for the MaxMind provider.
+	InfoAccountID string `json:"info_account_id"`
+	// InfoAPIKey is the API key or token of the information provider,
+	// which is optional for ipinfo.
+	InfoAPIKey string `json:"info_api_key"`
+	// ComparePreVPN is true to compare the public IP address through
+	// the VPN with the public IP address found before connecting to the
+	// VPN, and report if the IP address, ASN and country changed.
+	ComparePreVPN bool `json:"compare_pre_vpn"`
+	// ChangeWebhook is the URL the previous and current public IP
+	// addresses are posted to as JSON when the public IP address
+	// changes. It is empty to disable the webhook.
+	ChangeWebhook string `json:"change_webhook"`
 }
 
 func (settings *PublicIP) String() string {
@@ -25,10 +58,34 @@ func (settings *PublicIP) lines() (lines []string) {
 	lines = append(lines, lastIndent+"Public IP getter:")
 	lines = append(lines, indent+lastIndent+"Fetch period: "+settings.Period.String())
 	lines = append(lines, indent+lastIndent+"IP file: "+settings.IPFilepath)
+	lines = append(lines, indent+lastIndent+"Fetchers: "+strings.Join(settings.Fetchers, ", ")+
+		" with timeout "+settings.Timeout.String())
+	if settings.IPv6 {
+		lines = append(lines, indent+lastIndent+"IPv6: enabled")
+	}
+	if settings.Consensus > 1 {
+		lines = append(lines, indent+lastIndent+"Consensus: "+strconv.Itoa(settings.Consensus)+" fetchers")
+	}
+	lines = append(lines, indent+lastIndent+"Information provider: "+settings.InfoProvider)
+	if settings.InfoAPIKey != "" {
+		lines = append(lines, indent+indent+lastIndent+"API key: [redacted]")
+	}
+	if settings.ComparePreVPN {
+		lines = append(lines, indent+lastIndent+"Compare with public IP before VPN: enabled")
+	}
+	if settings.ChangeWebhook != "" {
+		lines = append(lines, indent+lastIndent+"Change webhook: "+urlHost(settings.ChangeWebhook))
+	}
 
 	return lines
 }
 
+var (
+	ErrPublicIPConsensusTooHigh = errors.New("public IP consensus is higher than the number of fetchers")
+	ErrPublicIPInfoCredentials  = errors.New("public IP information provider credentials are missing")
+	ErrPublicIPWebhookInvalid   = errors.New("public IP change webhook is not a valid http or https URL")
+)
+
 func (settings *PublicIP) read(r reader) (err error) {
 	settings.Period, err = r.env.Duration("PUBLICIP_PERIOD", params.Default("12h"))
 	if err != nil {
@@ -42,5 +99,76 @@ func (settings *PublicIP) read(r reader) (err error) {
 		return err
 	}
 
+	settings.Fetchers, err = r.env.CSVInside("PUBLICIP_FETCHERS", constants.PublicIPFetchers(),
+		params.Default("ipify,icanhazip,ifconfig,ipinfo,opendns"))
+	if err != nil {
+		return err
+	}
+
+	const maxConsensus = 10
+	settings.Consensus, err = r.env.IntRange("PUBLICIP_CONSENSUS", 1, maxConsensus, params.Default("1"))
+	if err != nil {
+		return err
+	} else if settings.Consensus > len(settings.Fetchers) {
+		return fmt.Errorf("%w: %d fetchers must agree but only %d are set",
+			ErrPublicIPConsensusTooHigh, settings.Consensus, len(settings.Fetchers))
+	}
+
+	settings.Timeout, err = r.env.Duration("PUBLICIP_TIMEOUT", params.Default("5s"))
+	if err != nil {
+		return err
+	}
+
+	if err := settings.readInfo(r); err != nil {
+		return err
+	}
+
+	settings.ComparePreVPN, err = r.env.OnOff("PUBLICIP_COMPARE_PRE_VPN", params.Default("off"))
+	if err != nil {
+		return err
+	}
+
+	return settings.readChangeWebhook(r)
+}
+
+func (settings *PublicIP) readChangeWebhook(r reader) (err error) {
+	settings.ChangeWebhook, err = r.env.Get("PUBLICIP_CHANGE_WEBHOOK", params.CaseSensitiveValue())
+	if err != nil || settings.ChangeWebhook == "" {
+		return err
+	}
+
+	u, err := url.Parse(settings.ChangeWebhook)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: %s", ErrPublicIPWebhookInvalid, urlHost(settings.ChangeWebhook))
+	}
+	return nil
+}
+
+func (settings *PublicIP) readInfo(r reader) (err error) {
+	settings.InfoProvider, err = r.env.Inside("PUBLICIP_INFO_PROVIDER", constants.PublicIPInfoProviders(),
+		params.Default(constants.PublicIPInfoIPInfo))
+	if err != nil || settings.InfoProvider == constants.PublicIPInfoNone {
+		return err
+	}
+
+	settings.InfoAccountID, err = r.env.Get("PUBLICIP_INFO_ACCOUNT_ID", params.CaseSensitiveValue())
+	if err != nil {
+		return err
+	}
+
+	settings.InfoAPIKey, err = r.getFromEnvOrSecretFile("PUBLICIP_INFO_API_KEY", false, nil)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case settings.InfoProvider == constants.PublicIPInfoIP2Location && settings.InfoAPIKey == "":
+		return fmt.Errorf("%w: %s requires an API key", ErrPublicIPInfoCredentials, settings.InfoProvider)
+	case settings.InfoProvider == constants.PublicIPInfoMaxMind &&
+		(settings.InfoAccountID == "" || settings.InfoAPIKey == ""):
+		return fmt.Errorf("%w: %s requires an account ID and an API key",
+			ErrPublicIPInfoCredentials, settings.InfoProvider)
+	}
+
 	return nil
 }
@@ -7,6 +7,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/golibs/logging"
 	"github.com/qdm12/golibs/os"
 	"github.com/qdm12/golibs/params"
@@ -18,6 +20,9 @@ type reader struct {
 	logger logging.Logger
 	regex  verification.Regex
 	os     os.OS
+	// prefix is the prefix of the environment variables keys,
+	// and is empty except for the backup VPN settings.
+	prefix string
 }
 
 func newReader(env params.Env, os os.OS, logger logging.Logger) reader {
@@ -29,6 +34,15 @@ func newReader(env params.Env, os os.OS, logger logging.Logger) reader {
 	}
 }
 
+// withPrefix returns a copy of the reader reading environment
+// variables with their keys prefixed with the prefix given.
+func (r *reader) withPrefix(prefix string) reader {
+	prefixed := *r
+	prefixed.env = &prefixedEnv{env: r.env, prefix: prefix}
+	prefixed.prefix = r.prefix + prefix
+	return prefixed
+}
+
 func (r *reader) onRetroActive(oldKey, newKey string) {
 	r.logger.Warn(
 		"You are using the old environment variable %s, please consider changing it to %s",
@@ -65,6 +79,35 @@ func readCSVPorts(env params.Env, key string) (ports []uint16, err error) {
 	return ports, nil
 }
 
+var (
+	ErrInvalidUID = errors.New("invalid user ID")
+)
+
+func readCSVUIDs(env params.Env, key string) (uids []int, err error) {
+	s, err := env.Get(key)
+	if err != nil {
+		return nil, err
+	} else if len(s) == 0 {
+		return nil, nil
+	}
+
+	uidsStr := strings.Split(s, ",")
+	uids = make([]int, len(uidsStr))
+	for i, uidStr := range uidsStr {
+		uid, err := strconv.Atoi(uidStr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q from environment variable %s: %s",
+				ErrInvalidUID, uidStr, key, err)
+		} else if uid < 0 || uid > 65535 {
+			return nil, fmt.Errorf("%w: %d from environment variable %s: must be between 0 and 65535",
+				ErrInvalidUID, uid, key)
+		}
+		uids[i] = uid
+	}
+
+	return uids, nil
+}
+
 var (
 	ErrInvalidIPNet = errors.New("invalid IP network")
 )
@@ -127,3 +170,83 @@ func readPortOrZero(env params.Env, key string) (port uint16, err error) {
 
 	return env.Port(key)
 }
+
+var (
+	ErrInvalidOutboundRule = errors.New("invalid outbound rule")
+)
+
+// readCSVOutboundRules reads outbound rules in the format
+// subnet_or_ip:port[/protocol], for example 192.168.1.10:445/tcp.
+// IPv6 addresses must be between brackets, for example [fd00::1]:445.
+func readCSVOutboundRules(env params.Env, key string) (rules []models.OutboundRule, err error) {
+	s, err := env.Get(key)
+	if err != nil {
+		return nil, err
+	} else if s == "" {
+		return nil, nil
+	}
+
+	rulesStr := strings.Split(s, ",")
+	rules = make([]models.OutboundRule, len(rulesStr))
+	for i, ruleStr := range rulesStr {
+		rules[i], err = parseOutboundRule(ruleStr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q from environment variable %s: %s",
+				ErrInvalidOutboundRule, ruleStr, key, err)
+		}
+	}
+
+	return rules, nil
+}
+
+func parseOutboundRule(s string) (rule models.OutboundRule, err error) {
+	for _, protocol := range [...]string{constants.TCP, constants.UDP} {
+		if strings.HasSuffix(s, "/"+protocol) {
+			rule.Protocol = protocol
+			s = strings.TrimSuffix(s, "/"+protocol)
+			break
+		}
+	}
+
+	host, portStr, err := net.SplitHostPort(s)
+	if err != nil {
+		return rule, err
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return rule, err
+	} else if port <= 0 || port > 65535 {
+		return rule, fmt.Errorf("%w: %d: must be between 1 and 65535", ErrInvalidPort, port)
+	}
+	rule.Port = uint16(port)
+
+	rule.Subnet, err = parseIPOrCIDR(host)
+	if err != nil {
+		return rule, err
+	}
+	return rule, nil
+}
+
+// parseIPOrCIDR parses a CIDR range, or an IP address
+// as a range containing only this address.
+func parseIPOrCIDR(s string) (subnet net.IPNet, err error) {
+	if strings.Contains(s, "/") {
+		_, ipNet, err := net.ParseCIDR(s)
+		if err != nil {
+			return subnet, err
+		}
+		return *ipNet, nil
+	}
+
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return subnet, fmt.Errorf("%w: %s", ErrInvalidIP, s)
+	}
+	bits := 8 * net.IPv6len
+	if ipv4 := ip.To4(); ipv4 != nil {
+		ip = ipv4
+		bits = 8 * net.IPv4len
+	}
+	return net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
@@ -0,0 +1,66 @@
+package configuration
+
+import (
+	"net"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseOutboundRule(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s     string
+		rule  models.OutboundRule
+		error bool
+	}{
+		"ip with protocol": {
+			s: "192.168.1.10:445/tcp",
+			rule: models.OutboundRule{
+				Subnet:   net.IPNet{IP: net.IP{192, 168, 1, 10}, Mask: net.CIDRMask(32, 32)},
+				Port:     445,
+				Protocol: "tcp",
+			},
+		},
+		"subnet without protocol": {
+			s: "10.0.0.0/24:53",
+			rule: models.OutboundRule{
+				Subnet: net.IPNet{IP: net.IP{10, 0, 0, 0}, Mask: net.CIDRMask(24, 32)},
+				Port:   53,
+			},
+		},
+		"ipv6 address": {
+			s: "[fd00::1]:139/udp",
+			rule: models.OutboundRule{
+				Subnet:   net.IPNet{IP: net.ParseIP("fd00::1"), Mask: net.CIDRMask(128, 128)},
+				Port:     139,
+				Protocol: "udp",
+			},
+		},
+		"missing port": {
+			s:     "192.168.1.10",
+			error: true,
+		},
+		"invalid port": {
+			s:     "192.168.1.10:0",
+			error: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			rule, err := parseOutboundRule(testCase.s)
+			if testCase.error {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.rule, rule)
+		})
+	}
+}
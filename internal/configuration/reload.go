@@ -0,0 +1,113 @@
+package configuration
+
+import (
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/golibs/logging"
+	"github.com/qdm12/golibs/os"
+	"github.com/qdm12/golibs/params"
+)
+
+// ReadReloadable reads again the settings which can be reloaded at
+// runtime: the OpenVPN server filters, the DNS block lists, the firewall
+// input ports and the HTTP proxy and Shadowsocks credentials. The other
+// settings are left unchanged. Since the environment variables of
+// secrets are unset once read, credentials are only changed if they
+// are found again, for example in secret files or in a configuration
+// file loaded again.
+func (settings *Settings) ReadReloadable(env params.Env, os os.OS, logger logging.Logger) (err error) {
+	r := newReader(env, os, logger)
+
+	if settings.VPNType == constants.OpenVPN && settings.OpenVPN.Config == "" {
+		provider := Provider{Name: settings.OpenVPN.Provider.Name}
+		if err := provider.readProviderSettings(r); err != nil {
+			return err
+		}
+		selection := &settings.OpenVPN.Provider.ServerSelection
+		selection.Countries = provider.ServerSelection.Countries
+		selection.Regions = provider.ServerSelection.Regions
+		selection.Cities = provider.ServerSelection.Cities
+		selection.Hostnames = provider.ServerSelection.Hostnames
+	}
+
+	if err := settings.DNS.readBlocklists(r); err != nil {
+		return err
+	}
+
+	if err := settings.Firewall.readVPNInputPorts(r.env); err != nil {
+		return err
+	}
+	if err := settings.Firewall.readInputPorts(r.env); err != nil {
+		return err
+	}
+
+	if settings.HTTPProxy.Enabled {
+		if err := settings.HTTPProxy.readCredentials(r); err != nil {
+			return err
+		}
+	}
+
+	if settings.ShadowSocks.Enabled {
+		password, err := r.getFromEnvOrSecretFile("SHADOWSOCKS_PASSWORD", false, nil)
+		if err != nil {
+			return err
+		} else if password != "" {
+			settings.ShadowSocks.Password = password
+		}
+	}
+
+	return nil
+}
+
+// readBlocklists reads the settings of the DNS block lists.
+func (settings *DNS) readBlocklists(r reader) (err error) {
+	var blocklists DNS
+	if err := blocklists.readBlockCategories(r); err != nil {
+		return err
+	}
+	if err := blocklists.readUnblockedHostnames(r); err != nil {
+		return err
+	}
+	blocklists.BlocklistURLs, err = readCSVListURLs(r.env, "DNS_BLOCKLIST_URLS")
+	if err != nil {
+		return err
+	}
+	blocklists.AllowlistURLs, err = readCSVListURLs(r.env, "DNS_ALLOWLIST_URLS")
+	if err != nil {
+		return err
+	}
+
+	settings.BlockCategories = blocklists.BlockCategories
+	settings.AllowedHostnames = blocklists.AllowedHostnames
+	settings.BlocklistURLs = blocklists.BlocklistURLs
+	settings.AllowlistURLs = blocklists.AllowlistURLs
+	return nil
+}
+
+// readCredentials reads the HTTP proxy credentials, only changing
+// the credentials found.
+func (settings *HTTPProxy) readCredentials(r reader) (err error) {
+	user, err := r.getFromEnvOrSecretFile("HTTPPROXY_USER", false,
+		[]string{"TINYPROXY_USER", "PROXY_USER"})
+	if err != nil {
+		return err
+	}
+
+	password, err := r.getFromEnvOrSecretFile("HTTPPROXY_PASSWORD", false,
+		[]string{"TINYPROXY_PASSWORD", "PROXY_PASSWORD"})
+	if err != nil {
+		return err
+	}
+
+	if user != "" && password != "" {
+		settings.User = user
+		settings.Password = password
+	}
+
+	users := HTTPProxy{User: settings.User} // to check for duplicate users
+	if err := users.readUsers(r); err != nil {
+		return err
+	} else if len(users.Users) > 0 {
+		settings.Users = users.Users
+	}
+	return nil
+}
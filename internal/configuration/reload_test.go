@@ -0,0 +1,76 @@
+package configuration
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/qdm12/golibs/os/mock_os"
+	"github.com/qdm12/golibs/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test_Settings_ReadReloadable is not parallel since
+// it reads the settings from the process environment.
+func Test_Settings_ReadReloadable(t *testing.T) { //nolint:paralleltest
+	settings := Settings{VPNType: constants.OpenVPN}
+	settings.OpenVPN.User = "user"
+	settings.OpenVPN.Provider.Name = constants.Mullvad
+	settings.OpenVPN.Provider.ServerSelection = ServerSelection{
+		Protocol:  constants.UDP,
+		Countries: []string{"france"},
+		Owned:     true,
+	}
+	settings.DNS.BlockCategories = []string{constants.DNSBlockMalicious}
+	settings.DNS.Providers = []string{"cloudflare"}
+	settings.Firewall.VPNInputPorts = []uint16{1000}
+	settings.HTTPProxy.Enabled = true
+	settings.HTTPProxy.User = "proxyuser"
+	settings.HTTPProxy.Password = "proxypassword"
+	settings.ShadowSocks.Enabled = true
+	settings.ShadowSocks.Password = "sspassword"
+
+	t.Setenv("COUNTRY", "Sweden")
+	t.Setenv("CITY", "")
+	t.Setenv("OWNED", "no")
+	t.Setenv("DNS_BLOCK_CATEGORIES", "ads")
+	t.Setenv("UNBLOCK", "example.com")
+	t.Setenv("FIREWALL_VPN_INPUT_PORTS", "2000")
+	t.Setenv("FIREWALL_INPUT_PORTS", "3000,3001")
+	t.Setenv("HTTPPROXY_USER", "newuser")
+	t.Setenv("HTTPPROXY_PASSWORD", "newpassword")
+	t.Setenv("SHADOWSOCKS_PASSWORD", "")
+
+	ctrl := gomock.NewController(t)
+	osMock := mock_os.NewMockOS(ctrl)
+	// no secret file for the shadowsocks password nor the proxy users
+	osMock.EXPECT().OpenFile(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, fs.ErrNotExist).AnyTimes()
+	logger := mock_logging.NewMockLogger(ctrl)
+
+	err := settings.ReadReloadable(params.NewEnv(), osMock, logger)
+	require.NoError(t, err)
+
+	expected := Settings{VPNType: constants.OpenVPN}
+	expected.OpenVPN.User = "user"
+	expected.OpenVPN.Provider.Name = constants.Mullvad
+	expected.OpenVPN.Provider.ServerSelection = ServerSelection{
+		Protocol:  constants.UDP,
+		Countries: []string{"sweden"},
+		Owned:     true, // only the server filters are reloaded
+	}
+	expected.DNS.BlockCategories = []string{constants.DNSBlockAds}
+	expected.DNS.AllowedHostnames = []string{"example.com"}
+	expected.DNS.Providers = []string{"cloudflare"}
+	expected.Firewall.VPNInputPorts = []uint16{2000}
+	expected.Firewall.InputPorts = []uint16{3000, 3001}
+	expected.HTTPProxy.Enabled = true
+	expected.HTTPProxy.User = "newuser"
+	expected.HTTPProxy.Password = "newpassword"
+	expected.ShadowSocks.Enabled = true
+	expected.ShadowSocks.Password = "sspassword" // not found again so kept
+	assert.Equal(t, expected, settings)
+}
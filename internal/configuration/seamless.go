@@ -0,0 +1,39 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qdm12/golibs/params"
+)
+
+var ErrSeamlessSwitchNotSupported = errors.New("seamless server switch is not supported")
+
+// readSeamlessSwitch reads if server switches should bring up the new
+// tunnel before tearing down the old one. Both tunnels are up at the same
+// time, which is not possible with settings assuming a single tunnel.
+func (settings *OpenVPN) readSeamlessSwitch(env params.Env) (err error) {
+	settings.SeamlessSwitch, err = env.OnOff("OPENVPN_SEAMLESS_SWITCH", params.Default("off"))
+	if err != nil || !settings.SeamlessSwitch {
+		return err
+	}
+
+	var incompatible string
+	switch {
+	case len(settings.Config) > 0:
+		incompatible = "a custom configuration"
+	case settings.Provider.PortForwarding.Enabled:
+		incompatible = "port forwarding"
+	case settings.Provider.ExtraConfigOptions.OpenVPNIPv6:
+		incompatible = "IPv6"
+	case settings.Obfuscation.Method != "":
+		incompatible = "obfuscation"
+	case settings.UpstreamProxy.Type != "":
+		incompatible = "an upstream proxy"
+	case settings.Binding.LocalPort > 0:
+		incompatible = "a fixed local port"
+	default:
+		return nil
+	}
+	return fmt.Errorf("%w with %s", ErrSeamlessSwitchNotSupported, incompatible)
+}
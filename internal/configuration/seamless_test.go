@@ -0,0 +1,66 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/golibs/params/mock_params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OpenVPN_readSeamlessSwitch(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		settings    OpenVPN
+		mockEnabled bool
+		err         error
+	}{
+		"disabled": {
+			settings: OpenVPN{Config: "/gluetun/custom.conf"},
+		},
+		"enabled": {
+			mockEnabled: true,
+		},
+		"port forwarding": {
+			settings: OpenVPN{Provider: Provider{
+				PortForwarding: PortForwarding{Enabled: true},
+			}},
+			mockEnabled: true,
+			err:         ErrSeamlessSwitchNotSupported,
+		},
+		"obfuscation": {
+			settings: OpenVPN{Obfuscation: Obfuscation{
+				Method: constants.Stunnel,
+			}},
+			mockEnabled: true,
+			err:         ErrSeamlessSwitchNotSupported,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			env := mock_params.NewMockEnv(ctrl)
+			env.EXPECT().
+				OnOff("OPENVPN_SEAMLESS_SWITCH", gomock.Any()).
+				Return(testCase.mockEnabled, nil)
+
+			settings := testCase.settings
+			err := settings.readSeamlessSwitch(env)
+
+			if testCase.err != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, testCase.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.mockEnabled, settings.SeamlessSwitch)
+		})
+	}
+}
@@ -26,22 +26,21 @@ func (r *reader) getFromEnvOrSecretFile(envKey string, compulsory bool, retroKey
 		params.RetroKeys(retroKeys, r.onRetroActive),
 	}
 	value, envErr := r.env.Get(envKey, envOptions...)
-	if envErr == nil {
-		return value, nil
-	}
 
-	defaultSecretFile := "/run/secrets/" + strings.ToLower(envKey)
-	filepath, err := r.env.Get(envKey+"_SECRETFILE",
-		params.CaseSensitiveValue(),
-		params.Default(defaultSecretFile),
-	)
+	// The file given by <key>_FILE takes precedence over the value
+	// of the environment variable, which is still unset above.
+	filepath, explicit, err := r.getSecretFilepath(envKey)
 	if err != nil {
-		return "", fmt.Errorf("%w: %s", ErrGetSecretFilepath, err)
+		return "", err
+	} else if envErr == nil && !explicit {
+		return value, nil
 	}
 
 	file, fileErr := r.os.OpenFile(filepath, os.O_RDONLY, 0)
 	if os.IsNotExist(fileErr) {
-		if compulsory {
+		if explicit {
+			return "", fmt.Errorf("%w: %s", ErrReadSecretFile, fileErr)
+		} else if compulsory {
 			return "", envErr
 		}
 		return "", nil
@@ -50,6 +49,7 @@ func (r *reader) getFromEnvOrSecretFile(envKey string, compulsory bool, retroKey
 	}
 
 	b, err := ioutil.ReadAll(file)
+	_ = file.Close()
 	if err != nil {
 		return "", fmt.Errorf("%w: %s", ErrReadSecretFile, err)
 	}
@@ -66,17 +66,13 @@ func (r *reader) getFromEnvOrSecretFile(envKey string, compulsory bool, retroKey
 // Tries to read from the secret file then the non secret file.
 func (r *reader) getFromFileOrSecretFile(secretName, filepath string) (
 	b []byte, err error) {
-	defaultSecretFile := "/run/secrets/" + strings.ToLower(secretName)
-	secretFilepath, err := r.env.Get(strings.ToUpper(secretName)+"_SECRETFILE",
-		params.CaseSensitiveValue(),
-		params.Default(defaultSecretFile),
-	)
+	secretFilepath, explicit, err := r.getSecretFilepath(strings.ToUpper(secretName))
 	if err != nil {
-		return b, fmt.Errorf("%w: %s", ErrGetSecretFilepath, err)
+		return b, err
 	}
 
 	b, err = readFromFile(r.os.OpenFile, secretFilepath)
-	if err != nil && !os.IsNotExist(err) {
+	if err != nil && (explicit || !os.IsNotExist(err)) {
 		return b, fmt.Errorf("%w: %s", ErrReadSecretFile, err)
 	} else if err == nil {
 		return b, nil
@@ -92,6 +88,30 @@ func (r *reader) getFromFileOrSecretFile(secretName, filepath string) (
 	return nil, fmt.Errorf("%w: %s and %s", ErrFilesDoNotExist, secretFilepath, filepath)
 }
 
+// getSecretFilepath returns the secret file path from the environment
+// variable <key>_FILE, following the Docker secrets convention, or from
+// <key>_SECRETFILE defaulting to /run/secrets/<key>. The explicit boolean
+// is true if the file path comes from the <key>_FILE variable, in which
+// case the file must exist.
+func (r *reader) getSecretFilepath(key string) (filepath string, explicit bool, err error) {
+	filepath, err = r.env.Get(key+"_FILE", params.CaseSensitiveValue())
+	if err != nil {
+		return "", false, fmt.Errorf("%w: %s", ErrGetSecretFilepath, err)
+	} else if filepath != "" {
+		return filepath, true, nil
+	}
+
+	defaultSecretFile := "/run/secrets/" + strings.ToLower(r.prefix+key)
+	filepath, err = r.env.Get(key+"_SECRETFILE",
+		params.CaseSensitiveValue(),
+		params.Default(defaultSecretFile),
+	)
+	if err != nil {
+		return "", false, fmt.Errorf("%w: %s", ErrGetSecretFilepath, err)
+	}
+	return filepath, false, nil
+}
+
 func readFromFile(openFile os.OpenFileFunc, filepath string) (b []byte, err error) {
 	file, err := openFile(filepath, os.O_RDONLY, 0)
 	if err != nil {
@@ -0,0 +1,127 @@
+package configuration
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/golibs/os"
+	"github.com/qdm12/golibs/os/mock_os"
+	"github.com/qdm12/golibs/params/mock_params"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_reader_getFromEnvOrSecretFile(t *testing.T) {
+	t.Parallel()
+
+	errUnset := errors.New("environment variable not set")
+
+	testCases := map[string]struct {
+		envValue    string
+		fileEnv     string
+		secretFile  string
+		filepath    string
+		fileContent string
+		fileErr     error
+		value       string
+		err         error
+		errMessage  string
+	}{
+		"environment variable": {
+			envValue:   "user",
+			secretFile: "/run/secrets/openvpn_user",
+			value:      "user",
+		},
+		"file variable": {
+			fileEnv:     "/run/secrets/user",
+			filepath:    "/run/secrets/user",
+			fileContent: "user\n",
+			value:       "user",
+		},
+		"file variable missing file": {
+			fileEnv:    "/run/secrets/user",
+			filepath:   "/run/secrets/user",
+			fileErr:    fs.ErrNotExist,
+			err:        ErrReadSecretFile,
+			errMessage: "cannot read secret file: file does not exist",
+		},
+		"file variable over secret file variable": {
+			fileEnv:     "/run/secrets/user",
+			secretFile:  "/run/secrets/openvpn_user",
+			filepath:    "/run/secrets/user",
+			fileContent: "user",
+			value:       "user",
+		},
+		"file variable over environment variable": {
+			envValue:    "env user",
+			fileEnv:     "/run/secrets/user",
+			filepath:    "/run/secrets/user",
+			fileContent: "file user",
+			value:       "file user",
+		},
+		"secret file variable": {
+			secretFile:  "/run/secrets/openvpn_user",
+			filepath:    "/run/secrets/openvpn_user",
+			fileContent: "user",
+			value:       "user",
+		},
+		"secret file variable missing file": {
+			secretFile: "/run/secrets/openvpn_user",
+			filepath:   "/run/secrets/openvpn_user",
+			fileErr:    fs.ErrNotExist,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			env := mock_params.NewMockEnv(ctrl)
+			var envErr error
+			if testCase.envValue == "" {
+				envErr = errUnset
+			}
+			env.EXPECT().
+				Get("OPENVPN_USER", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(testCase.envValue, envErr)
+			env.EXPECT().
+				Get("OPENVPN_USER_FILE", gomock.Any()).
+				Return(testCase.fileEnv, nil)
+			if testCase.fileEnv == "" {
+				env.EXPECT().
+					Get("OPENVPN_USER_SECRETFILE", gomock.Any(), gomock.Any()).
+					Return(testCase.secretFile, nil)
+			}
+
+			osMock := mock_os.NewMockOS(ctrl)
+			if testCase.filepath != "" {
+				var file os.File
+				if testCase.fileErr == nil {
+					mockFile := mock_os.NewMockFile(ctrl)
+					mockFile.EXPECT().Read(gomock.Any()).DoAndReturn(func(b []byte) (int, error) {
+						return copy(b, testCase.fileContent), nil
+					})
+					mockFile.EXPECT().Read(gomock.Any()).Return(0, io.EOF)
+					mockFile.EXPECT().Close().Return(nil)
+					file = mockFile
+				}
+				osMock.EXPECT().OpenFile(testCase.filepath, os.O_RDONLY, os.FileMode(0)).
+					Return(file, testCase.fileErr)
+			}
+			r := reader{env: env, os: osMock}
+
+			const compulsory = false
+			value, err := r.getFromEnvOrSecretFile("OPENVPN_USER", compulsory, nil)
+
+			assert.ErrorIs(t, err, testCase.err)
+			if testCase.err != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.value, value)
+		})
+	}
+}
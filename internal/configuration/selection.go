@@ -1,13 +1,28 @@
 package configuration
 
 import (
+	"errors"
+	"fmt"
 	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/golibs/params"
 )
 
 type ServerSelection struct {
 	// Common
-	Protocol string `json:"network_protocol"`
-	TargetIP net.IP `json:"target_ip,omitempty"`
+	Protocol string           `json:"network_protocol"`
+	TargetIP net.IP           `json:"target_ip,omitempty"`
+	Latency  LatencySelection `json:"latency"`
+	// ExcludedIPs are IP addresses to avoid if possible,
+	// and are set at runtime by the OpenVPN loop.
+	ExcludedIPs []net.IP `json:"-"`
+	// PreferredIP is an IP address to pick if possible,
+	// and is set at runtime by the OpenVPN loop.
+	PreferredIP net.IP `json:"-"`
 	// TODO comments
 	// Cyberghost, PIA, Surfshark, Windscribe, Vyprvpn, NordVPN
 	Regions []string `json:"regions"`
@@ -18,10 +33,13 @@ type ServerSelection struct {
 	Countries []string `json:"countries"` // Fastestvpn, HideMyAss, Mullvad, PrivateVPN, PureVPN
 	Cities    []string `json:"cities"`    // HideMyAss, Mullvad, PrivateVPN, PureVPN, Windscribe
 	Hostnames []string `json:"hostnames"` // Fastestvpn, HideMyAss, PrivateVPN, Windscribe, Privado
+	Features  []string `json:"features"`  // NordVPN, Windscribe
 
 	// Mullvad
-	ISPs  []string `json:"isps"`
-	Owned bool     `json:"owned"`
+	ISPs              []string `json:"isps"`
+	Owned             bool     `json:"owned"`
+	MultiHopEntryCity string   `json:"multihop_entry_city"`
+	MultiHopExitCity  string   `json:"multihop_exit_city"`
 
 	// Mullvad, Windscribe, PIA
 	CustomPort uint16 `json:"custom_port"`
@@ -29,25 +47,201 @@ type ServerSelection struct {
 	// NordVPN
 	Numbers []uint16 `json:"numbers"`
 
+	// NordVPN, Surfshark
+	MultiHop MultiHopSelection `json:"multihop"`
+
 	// PIA
 	EncryptionPreset string `json:"encryption_preset"`
+	DedicatedIPToken string `json:"-"`
+}
+
+// MultiHopSelection contains settings to select multi-hop (double VPN) servers.
+type MultiHopSelection struct {
+	Only           bool     `json:"only"`
+	EntryCountries []string `json:"entry_countries"`
+	ExitCountries  []string `json:"exit_countries"`
+}
+
+func (m *MultiHopSelection) lines() (lines []string) {
+	if !m.Only && len(m.EntryCountries) == 0 && len(m.ExitCountries) == 0 {
+		return nil
+	}
+
+	lines = append(lines, lastIndent+"Multi-hop:")
+
+	if m.Only {
+		lines = append(lines, indent+lastIndent+"Multi-hop servers only: enabled")
+	}
+
+	if len(m.EntryCountries) > 0 {
+		lines = append(lines, indent+lastIndent+"Entry countries: "+commaJoin(m.EntryCountries))
+	}
+
+	if len(m.ExitCountries) > 0 {
+		lines = append(lines, indent+lastIndent+"Exit countries: "+commaJoin(m.ExitCountries))
+	}
+
+	return lines
+}
+
+func (m *MultiHopSelection) read(env params.Env) (err error) {
+	m.Only, err = env.OnOff("MULTIHOP_ONLY", params.Default("off"))
+	if err != nil {
+		return err
+	}
+
+	m.EntryCountries, err = env.CSV("MULTIHOP_ENTRY_COUNTRY")
+	if err != nil {
+		return err
+	}
+
+	m.ExitCountries, err = env.CSV("MULTIHOP_EXIT_COUNTRY")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LatencySelection contains settings to probe the latency of the candidate
+// servers at startup and select a server with a latency close to the lowest
+// latency. Without it, servers are selected randomly.
+type LatencySelection struct {
+	Enabled    bool          `json:"enabled"`
+	Timeout    time.Duration `json:"timeout"`
+	Candidates int           `json:"candidates"`
+}
+
+func (l *LatencySelection) lines() (lines []string) {
+	if !l.Enabled {
+		return nil
+	}
+
+	lines = append(lines, lastIndent+"Latency selection:")
+	lines = append(lines, indent+lastIndent+"Probe timeout: "+l.Timeout.String())
+	lines = append(lines, indent+lastIndent+"Maximum candidates: "+strconv.Itoa(l.Candidates))
+
+	return lines
+}
+
+func (l *LatencySelection) read(env params.Env) (err error) {
+	l.Enabled, err = env.OnOff("LATENCY_SELECTION", params.Default("off"))
+	if err != nil {
+		return err
+	}
+
+	l.Timeout, err = env.Duration("LATENCY_PROBE_TIMEOUT", params.Default("2s"))
+	if err != nil {
+		return err
+	}
+
+	l.Candidates, err = env.IntRange("LATENCY_CANDIDATES", 1, 1000, params.Default("20"))
+	if err != nil {
+		return err
+	}
+
+	return nil
 }
 
 type ExtraConfigOptions struct {
-	ClientCertificate string `json:"-"`                 // Cyberghost
-	ClientKey         string `json:"-"`                 // Cyberghost
+	ClientCertificate string `json:"-"`                 // Cyberghost, optional for others
+	ClientKey         string `json:"-"`                 // Cyberghost, optional for others
+	TLSCrypt          string `json:"-"`                 // all, optional
+	TLSCryptV2        string `json:"-"`                 // all, optional
 	EncryptionPreset  string `json:"encryption_preset"` // PIA
-	OpenVPNIPv6       bool   `json:"openvpn_ipv6"`      // Mullvad
+	OpenVPNIPv6       bool   `json:"openvpn_ipv6"`      // Mullvad, Privatevpn
 }
 
+var (
+	ErrPortForwardingProxiesTooMany = errors.New("not enough ports forwarded for the proxies exposed")
+	ErrPortForwardingProxyDisabled  = errors.New("proxy exposed on a port forwarded is disabled")
+)
+
 // PortForwarding contains settings for port forwarding.
 type PortForwarding struct {
 	Enabled  bool   `json:"enabled"`
 	Filepath string `json:"filepath"`
+	// Count is the number of ports to forward, which can only
+	// be more than one with NAT-PMP for a custom configuration.
+	Count int `json:"count"`
+	// Provider is the VPN provider of a custom configuration, to adapt
+	// the NAT-PMP port forwarding to it. It is empty for a generic
+	// NAT-PMP gateway.
+	Provider string `json:"provider"`
+	// UpCommand is the shell command run each time the ports forwarded
+	// are obtained or change, with the ports as arguments. It is
+	// empty to run no command.
+	UpCommand string `json:"up_command"`
+	// TorrentClient is the torrent client to set
+	// the listening port to the port forwarded.
+	TorrentClient TorrentClient `json:"torrent_client"`
+	// Proxies are the proxy servers exposed through the VPN on the
+	// last ports forwarded, in order, by redirecting the traffic to
+	// the ports forwarded to the listening ports of the proxies.
+	Proxies []string `json:"proxies"`
+	// ProxyPorts are the listening ports of the Proxies,
+	// set from the settings of each proxy server.
+	ProxyPorts []uint16 `json:"proxy_ports"`
 }
 
 func (p *PortForwarding) lines() (lines []string) {
-	return []string{
-		lastIndent + "File path: " + p.Filepath,
+	lines = append(lines, lastIndent+"File path: "+p.Filepath)
+	if p.Count > 1 {
+		lines = append(lines, lastIndent+"Ports: "+strconv.Itoa(p.Count))
+	}
+	if p.Provider != "" {
+		lines = append(lines, lastIndent+"Provider: "+p.Provider)
+	}
+	if p.UpCommand != "" {
+		lines = append(lines, lastIndent+"Up command: [redacted]")
+	}
+	if p.TorrentClient.Name != "" {
+		lines = append(lines, p.TorrentClient.lines()...)
+	}
+	if len(p.Proxies) > 0 {
+		lines = append(lines, lastIndent+"Exposed proxies: "+strings.Join(p.Proxies, ", "))
+	}
+	return lines
+}
+
+func (p *PortForwarding) read(r reader) (err error) {
+	p.Enabled, err = r.env.OnOff("PORT_FORWARDING", params.Default("off"))
+	if err != nil || !p.Enabled {
+		return err
+	}
+
+	p.Filepath, err = r.env.Path("PORT_FORWARDING_STATUS_FILE",
+		params.Default("/tmp/gluetun/forwarded_port"), params.CaseSensitiveValue())
+	if err != nil {
+		return err
+	}
+
+	const maxCount = 16
+	p.Count, err = r.env.IntRange("PORT_FORWARDING_COUNT", 1, maxCount, params.Default("1"))
+	if err != nil {
+		return err
+	}
+
+	p.UpCommand, err = r.env.Get("PORT_FORWARD_UP_COMMAND", params.CaseSensitiveValue())
+	if err != nil {
+		return err
+	}
+
+	if err := p.TorrentClient.read(r); err != nil {
+		return err
+	}
+
+	p.Proxies, err = r.env.CSVInside("PORT_FORWARDING_PROXIES", constants.PortForwardProxies())
+	if err != nil {
+		return err
+	}
+	portsNeeded := len(p.Proxies)
+	if p.TorrentClient.Name != "" && portsNeeded > 0 {
+		portsNeeded++ // the torrent client uses the first port forwarded
+	}
+	if portsNeeded > p.Count {
+		return fmt.Errorf("%w: %d ports are needed but only %d are forwarded",
+			ErrPortForwardingProxiesTooMany, portsNeeded, p.Count)
 	}
+	return nil
 }
@@ -1,6 +1,9 @@
 package configuration
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -9,8 +12,47 @@ import (
 
 // ControlServer contains settings to customize the control server operation.
 type ControlServer struct {
-	Port uint16
-	Log  bool
+	Port uint16 `json:"port"`
+	Log  bool   `json:"log"`
+	// TLS is true to serve HTTPS instead of HTTP on the listening port.
+	TLS bool `json:"tls"`
+	// TLSCertFile and TLSKeyFile are the file paths to the TLS
+	// certificate and key, and are empty to use a self-signed certificate.
+	TLSCertFile string `json:"tls_cert_file"`
+	TLSKeyFile  string `json:"tls_key_file"`
+	// UnixSocket is the file path of the Unix domain socket to
+	// listen on in addition to the listening port, if not empty.
+	UnixSocket string `json:"unix_socket"`
+	// GRPC is true to serve the gRPC control API on the GRPCPort,
+	// with the same TLS settings and credentials.
+	GRPC     bool   `json:"grpc"`
+	GRPCPort uint16 `json:"grpc_port"`
+	// Metrics is true to serve Prometheus metrics on /metrics.
+	Metrics bool `json:"metrics"`
+	// CORSOrigins are the origins allowed to call the server from
+	// a browser, where * allows all origins, and is empty to
+	// disallow cross origin requests.
+	CORSOrigins []string `json:"cors_origins"`
+	// RateLimit is the maximum number of requests per minute per
+	// client IP address, and is 0 to disable rate limiting.
+	RateLimit int `json:"rate_limit"`
+	// Audit is true to record the requests changing state,
+	// with the credentials used and the values changed.
+	Audit bool `json:"audit"`
+	// AuditFile is the file path to append the audit entries
+	// to as JSON lines, and is empty to log them instead.
+	AuditFile string `json:"audit_file"`
+	// APIKey is the key granting access to all the endpoints.
+	// The firewall endpoints are disabled if it and the User
+	// are empty.
+	APIKey string `json:"api_key"`
+	// ReadOnlyAPIKey is the key granting access only to
+	// the endpoints not changing any state.
+	ReadOnlyAPIKey string `json:"readonly_api_key"`
+	// User and Password are the HTTP basic authentication
+	// credentials granting access to all the endpoints.
+	User     string `json:"user"`
+	Password string `json:"password"`
 }
 
 func (settings *ControlServer) String() string {
@@ -22,10 +64,60 @@ func (settings *ControlServer) lines() (lines []string) {
 
 	lines = append(lines, indent+lastIndent+"Listening port: "+strconv.Itoa(int(settings.Port)))
 
+	if settings.TLS {
+		if settings.TLSCertFile == "" {
+			lines = append(lines, indent+lastIndent+"TLS: self-signed certificate")
+		} else {
+			lines = append(lines, indent+lastIndent+"TLS certificate file: "+settings.TLSCertFile)
+			lines = append(lines, indent+lastIndent+"TLS key file: "+settings.TLSKeyFile)
+		}
+	}
+
+	if settings.UnixSocket != "" {
+		lines = append(lines, indent+lastIndent+"Unix socket: "+settings.UnixSocket)
+	}
+
+	if settings.GRPC {
+		lines = append(lines, indent+lastIndent+"gRPC listening port: "+strconv.Itoa(int(settings.GRPCPort)))
+	}
+
 	if settings.Log {
 		lines = append(lines, indent+lastIndent+"Logging: enabled")
 	}
 
+	if settings.Metrics {
+		lines = append(lines, indent+lastIndent+"Prometheus metrics: enabled")
+	}
+
+	if len(settings.CORSOrigins) > 0 {
+		lines = append(lines, indent+lastIndent+"CORS origins: "+strings.Join(settings.CORSOrigins, ", "))
+	}
+
+	if settings.RateLimit > 0 {
+		lines = append(lines, indent+lastIndent+"Rate limit: "+strconv.Itoa(settings.RateLimit)+" requests per minute")
+	}
+
+	if settings.Audit {
+		auditTo := "logs"
+		if settings.AuditFile != "" {
+			auditTo = settings.AuditFile
+		}
+		lines = append(lines, indent+lastIndent+"Audit: "+auditTo)
+	}
+
+	if settings.APIKey != "" {
+		lines = append(lines, indent+lastIndent+"API key: [set]")
+	}
+
+	if settings.ReadOnlyAPIKey != "" {
+		lines = append(lines, indent+lastIndent+"Read only API key: [set]")
+	}
+
+	if settings.User != "" {
+		lines = append(lines, indent+lastIndent+"Basic authentication user: "+settings.User)
+		lines = append(lines, indent+lastIndent+"Basic authentication password: [set]")
+	}
+
 	return lines
 }
 
@@ -45,5 +137,139 @@ func (settings *ControlServer) read(r reader) (err error) {
 		return err
 	}
 
+	if err := settings.readListeners(r); err != nil {
+		return err
+	}
+
+	settings.Metrics, err = r.env.OnOff("HTTP_CONTROL_SERVER_METRICS", params.Default("off"))
+	if err != nil {
+		return err
+	}
+
+	if err := settings.readAccess(r); err != nil {
+		return err
+	}
+
+	settings.Audit, err = r.env.OnOff("HTTP_CONTROL_SERVER_AUDIT", params.Default("off"))
+	if err != nil {
+		return err
+	}
+	if settings.Audit {
+		settings.AuditFile, err = r.env.Get("HTTP_CONTROL_SERVER_AUDIT_FILE", params.CaseSensitiveValue())
+		if err != nil {
+			return err
+		}
+	}
+
+	settings.APIKey, err = r.getFromEnvOrSecretFile("HTTP_CONTROL_SERVER_API_KEY", false, nil)
+	if err != nil {
+		return err
+	}
+
+	settings.ReadOnlyAPIKey, err = r.getFromEnvOrSecretFile("HTTP_CONTROL_SERVER_READONLY_API_KEY", false, nil)
+	if err != nil {
+		return err
+	}
+
+	return settings.readBasicAuth(r)
+}
+
+var (
+	ErrControlServerTLSKeyPairIncomplete = errors.New("control server TLS certificate and key files must be set together")
+	ErrControlServerGRPCPortConflict     = errors.New("control server gRPC port must differ from the HTTP port")
+)
+
+func (settings *ControlServer) readListeners(r reader) (err error) {
+	settings.TLS, err = r.env.OnOff("HTTP_CONTROL_SERVER_TLS", params.Default("off"))
+	if err != nil {
+		return err
+	}
+
+	if settings.TLS {
+		settings.TLSCertFile, err = r.env.Get("HTTP_CONTROL_SERVER_TLS_CERT", params.CaseSensitiveValue())
+		if err != nil {
+			return err
+		}
+		settings.TLSKeyFile, err = r.env.Get("HTTP_CONTROL_SERVER_TLS_KEY", params.CaseSensitiveValue())
+		if err != nil {
+			return err
+		}
+		if (settings.TLSCertFile == "") != (settings.TLSKeyFile == "") {
+			return ErrControlServerTLSKeyPairIncomplete
+		}
+	}
+
+	settings.UnixSocket, err = r.env.Get("HTTP_CONTROL_SERVER_UNIX_SOCKET", params.CaseSensitiveValue())
+	if err != nil {
+		return err
+	}
+
+	settings.GRPC, err = r.env.OnOff("HTTP_CONTROL_SERVER_GRPC", params.Default("off"))
+	if err != nil {
+		return err
+	}
+	if settings.GRPC {
+		var warning string
+		settings.GRPCPort, warning, err = r.env.ListeningPort(
+			"HTTP_CONTROL_SERVER_GRPC_PORT", params.Default("8001"))
+		if len(warning) > 0 {
+			r.logger.Warn(warning)
+		}
+		if err != nil {
+			return err
+		}
+		if settings.GRPCPort == settings.Port {
+			return fmt.Errorf("%w: %d", ErrControlServerGRPCPortConflict, settings.GRPCPort)
+		}
+	}
+
+	return nil
+}
+
+var (
+	ErrControlServerCORSOriginInvalid = errors.New("control server CORS origin is not valid")
+)
+
+func (settings *ControlServer) readAccess(r reader) (err error) {
+	settings.CORSOrigins, err = r.env.CSV("HTTP_CONTROL_SERVER_CORS_ORIGINS", params.CaseSensitiveValue())
+	if err != nil {
+		return err
+	}
+	for _, origin := range settings.CORSOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+			u.Host == "" || (u.Path != "" && u.Path != "/") {
+			return fmt.Errorf("%w: %s: must be * or scheme://host[:port]", ErrControlServerCORSOriginInvalid, origin)
+		}
+	}
+
+	const maxRateLimit = 100000
+	settings.RateLimit, err = r.env.IntRange("HTTP_CONTROL_SERVER_RATE_LIMIT",
+		0, maxRateLimit, params.Default("0"))
+	return err
+}
+
+var (
+	ErrControlServerPasswordMissing = errors.New("control server password is missing")
+)
+
+func (settings *ControlServer) readBasicAuth(r reader) (err error) {
+	settings.User, err = r.getFromEnvOrSecretFile("HTTP_CONTROL_SERVER_USER", false, nil)
+	if err != nil {
+		return err
+	}
+
+	settings.Password, err = r.getFromEnvOrSecretFile("HTTP_CONTROL_SERVER_PASSWORD", false, nil)
+	if err != nil {
+		return err
+	}
+
+	if settings.User != "" && settings.Password == "" {
+		return ErrControlServerPasswordMissing
+	}
+
 	return nil
 }
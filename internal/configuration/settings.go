@@ -1,8 +1,10 @@
 package configuration
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/golibs/logging"
 	"github.com/qdm12/golibs/os"
 	"github.com/qdm12/golibs/params"
@@ -10,16 +12,19 @@ import (
 
 // Settings contains all settings for the program to run.
 type Settings struct {
-	OpenVPN            OpenVPN
-	System             System
-	DNS                DNS
-	Firewall           Firewall
-	HTTPProxy          HTTPProxy
-	ShadowSocks        ShadowSocks
-	Updater            Updater
-	PublicIP           PublicIP
-	VersionInformation bool
-	ControlServer      ControlServer
+	VPNType            string        `json:"vpn_type"`
+	OpenVPN            OpenVPN       `json:"openvpn"`
+	Wireguard          Wireguard     `json:"wireguard"`
+	System             System        `json:"system"`
+	DNS                DNS           `json:"dns"`
+	Firewall           Firewall      `json:"firewall"`
+	HTTPProxy          HTTPProxy     `json:"http_proxy"`
+	ShadowSocks        ShadowSocks   `json:"shadowsocks"`
+	Updater            Updater       `json:"updater"`
+	PublicIP           PublicIP      `json:"public_ip"`
+	VersionInformation bool          `json:"version_information"`
+	ControlServer      ControlServer `json:"control_server"`
+	Health             Health        `json:"health"`
 }
 
 func (settings *Settings) String() string {
@@ -28,7 +33,11 @@ func (settings *Settings) String() string {
 
 func (settings *Settings) lines() (lines []string) {
 	lines = append(lines, "Settings summary below:")
-	lines = append(lines, settings.OpenVPN.lines()...)
+	if settings.VPNType == constants.Wireguard {
+		lines = append(lines, settings.Wireguard.lines()...)
+	} else {
+		lines = append(lines, settings.OpenVPN.lines()...)
+	}
 	lines = append(lines, settings.DNS.lines()...)
 	lines = append(lines, settings.Firewall.lines()...)
 	lines = append(lines, settings.System.lines()...)
@@ -37,6 +46,7 @@ func (settings *Settings) lines() (lines []string) {
 	lines = append(lines, settings.ControlServer.lines()...)
 	lines = append(lines, settings.Updater.lines()...)
 	lines = append(lines, settings.PublicIP.lines()...)
+	lines = append(lines, settings.Health.lines()...)
 	if settings.VersionInformation {
 		lines = append(lines, lastIndent+"Github version information: enabled")
 	}
@@ -53,7 +63,26 @@ func (settings *Settings) Read(env params.Env, os os.OS, logger logging.Logger)
 		return err
 	}
 
-	if err := settings.OpenVPN.read(r); err != nil {
+	settings.VPNType, err = r.env.Inside("VPN_TYPE",
+		[]string{constants.OpenVPN, constants.Wireguard}, params.Default(constants.OpenVPN))
+	if err != nil {
+		return err
+	}
+
+	switch settings.VPNType {
+	case constants.Wireguard:
+		err = settings.Wireguard.read(r)
+		if err == nil && settings.Wireguard.Provider == constants.Nordvpn {
+			// OpenVPN settings are used as fallback if NordLynx is not available
+			if openvpnErr := settings.OpenVPN.read(r); openvpnErr != nil {
+				r.logger.Warn("OpenVPN fallback disabled: %s", openvpnErr)
+				settings.OpenVPN = OpenVPN{}
+			}
+		}
+	default:
+		err = settings.OpenVPN.read(r)
+	}
+	if err != nil {
 		return err
 	}
 
@@ -77,6 +106,10 @@ func (settings *Settings) Read(env params.Env, os os.OS, logger logging.Logger)
 		return err
 	}
 
+	if err := settings.setPortForwardingProxyPorts(); err != nil {
+		return err
+	}
+
 	if err := settings.ControlServer.read(r); err != nil {
 		return err
 	}
@@ -92,6 +125,49 @@ func (settings *Settings) Read(env params.Env, os os.OS, logger logging.Logger)
 	if err := settings.PublicIP.read(r); err != nil {
 		return err
 	}
+	settings.PublicIP.IPv6 = settings.tunnelIPv6()
+
+	if err := settings.Health.read(r); err != nil {
+		return err
+	}
 
 	return nil
 }
+
+// setPortForwardingProxyPorts sets the listening ports of the
+// proxies exposed on the ports forwarded from their settings.
+func (settings *Settings) setPortForwardingProxyPorts() (err error) {
+	portForwarding := &settings.OpenVPN.Provider.PortForwarding
+	if len(portForwarding.Proxies) == 0 {
+		return nil
+	}
+	portForwarding.ProxyPorts = make([]uint16, len(portForwarding.Proxies))
+	for i, proxy := range portForwarding.Proxies {
+		var enabled bool
+		switch proxy {
+		case constants.PortForwardProxyHTTP:
+			enabled = settings.HTTPProxy.Enabled
+			portForwarding.ProxyPorts[i] = settings.HTTPProxy.Port
+		case constants.PortForwardProxyShadowsocks:
+			enabled = settings.ShadowSocks.Enabled
+			portForwarding.ProxyPorts[i] = settings.ShadowSocks.Port
+		}
+		if !enabled {
+			return fmt.Errorf("%w: %s", ErrPortForwardingProxyDisabled, proxy)
+		}
+	}
+	return nil
+}
+
+// tunnelIPv6 returns true if IPv6 is enabled through the VPN tunnel.
+func (settings *Settings) tunnelIPv6() (enabled bool) {
+	if settings.VPNType == constants.OpenVPN {
+		return settings.OpenVPN.Provider.ExtraConfigOptions.OpenVPNIPv6
+	}
+	for _, address := range settings.Wireguard.Addresses {
+		if address.IP.To4() == nil {
+			return true
+		}
+	}
+	return false
+}
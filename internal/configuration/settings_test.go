@@ -38,6 +38,12 @@ func Test_Settings_lines(t *testing.T) {
 				"|--HTTP control server:",
 				"   |--Listening port: 0",
 				"|--Public IP getter: disabled",
+				"|--Health:",
+				"   |--Checks:",
+				"   |--Healthy if: one check succeeds",
+				"   |--Unhealthy after: 0 consecutive failures",
+				"   |--Healthy again after: 0 consecutive successes",
+				"   |--Retry backoff: 0s up to 0s",
 			},
 		},
 	}
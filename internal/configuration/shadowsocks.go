@@ -1,19 +1,28 @@
 package configuration
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/golibs/params"
 )
 
 // ShadowSocks contains settings to configure the Shadowsocks server.
 type ShadowSocks struct {
-	Method   string
-	Password string
-	Port     uint16
-	Enabled  bool
-	Log      bool
+	Method   string `json:"method"`
+	Password string `json:"password"`
+	Port     uint16 `json:"port"`
+	Enabled  bool   `json:"enabled"`
+	Log      bool   `json:"log"`
+	// Plugin is the SIP003 plugin executable to run in front of the
+	// Shadowsocks server, such as v2ray-plugin or obfs-server.
+	// It is empty to run no plugin.
+	Plugin string `json:"plugin"`
+	// PluginOptions are the options passed to the plugin.
+	PluginOptions string `json:"plugin_options"`
 }
 
 func (settings *ShadowSocks) String() string {
@@ -31,6 +40,13 @@ func (settings *ShadowSocks) lines() (lines []string) {
 
 	lines = append(lines, indent+lastIndent+"Method: "+settings.Method)
 
+	if settings.Plugin != "" {
+		lines = append(lines, indent+lastIndent+"Plugin: "+settings.Plugin)
+		if settings.PluginOptions != "" {
+			lines = append(lines, indent+indent+lastIndent+"Options: "+settings.PluginOptions)
+		}
+	}
+
 	if settings.Log {
 		lines = append(lines, indent+lastIndent+"Logging: enabled")
 	}
@@ -58,6 +74,19 @@ func (settings *ShadowSocks) read(r reader) (err error) {
 	if err != nil {
 		return err
 	}
+	if err := checkShadowsocksMethod(settings.Method); err != nil {
+		return err
+	}
+
+	settings.Plugin, err = r.env.Get("SHADOWSOCKS_PLUGIN", params.CaseSensitiveValue())
+	if err != nil {
+		return err
+	}
+
+	settings.PluginOptions, err = r.env.Get("SHADOWSOCKS_PLUGIN_OPTIONS", params.CaseSensitiveValue())
+	if err != nil {
+		return err
+	}
 
 	var warning string
 	settings.Port, warning, err = r.env.ListeningPort("SHADOWSOCKS_PORT", params.Default("8388"))
@@ -70,3 +99,23 @@ func (settings *ShadowSocks) read(r reader) (err error) {
 
 	return nil
 }
+
+var (
+	ErrShadowsocksMethodNotValid   = errors.New("shadowsocks method is not valid")
+	ErrShadowsocks2022NotSupported = errors.New("shadowsocks 2022 ciphers are not supported")
+)
+
+func checkShadowsocksMethod(method string) error {
+	for _, supported := range constants.ShadowsocksMethods() {
+		if method == supported {
+			return nil
+		}
+	}
+	for _, unsupported := range constants.Shadowsocks2022Methods() {
+		if method == unsupported {
+			return fmt.Errorf("%w: %s", ErrShadowsocks2022NotSupported, method)
+		}
+	}
+	return fmt.Errorf("%w: %s: must be one of: %s", ErrShadowsocksMethodNotValid,
+		method, strings.Join(constants.ShadowsocksMethods(), ", "))
+}
@@ -0,0 +1,40 @@
+package configuration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_checkShadowsocksMethod(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		method string
+		err    error
+	}{
+		"supported": {
+			method: "aes-256-gcm",
+		},
+		"2022 cipher": {
+			method: "2022-blake3-aes-256-gcm",
+			err:    ErrShadowsocks2022NotSupported,
+		},
+		"unknown": {
+			method: "rc4-md5",
+			err:    ErrShadowsocksMethodNotValid,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkShadowsocksMethod(testCase.method)
+
+			assert.True(t, errors.Is(err, testCase.err))
+		})
+	}
+}
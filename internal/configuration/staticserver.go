@@ -0,0 +1,68 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/qdm12/golibs/params"
+)
+
+// StaticServer contains settings to connect to a server pinned by
+// hostname, such as a dedicated IP or self-hosted server, whose IP
+// address may change over time.
+type StaticServer struct {
+	// Hostname is the hostname of the server, and is empty if disabled.
+	Hostname string `json:"hostname"`
+	// Resolver is the plaintext DNS server used to resolve the hostname
+	// through the default interface, outside the VPN.
+	Resolver net.IP `json:"resolver"`
+	// CheckPeriod is the period to resolve the hostname again, to
+	// reconnect if its IP address changed, and is 0 to disable it.
+	CheckPeriod time.Duration `json:"check_period"`
+}
+
+func (s *StaticServer) lines() (lines []string) {
+	if s.Hostname == "" {
+		return nil
+	}
+
+	lines = append(lines, lastIndent+"Static server:")
+	lines = append(lines, indent+lastIndent+"Hostname: "+s.Hostname)
+	lines = append(lines, indent+lastIndent+"Resolver: "+s.Resolver.String())
+	if s.CheckPeriod > 0 {
+		lines = append(lines, indent+lastIndent+"Check period: "+s.CheckPeriod.String())
+	}
+
+	return lines
+}
+
+var ErrStaticServerTargetIP = errors.New("static server hostname cannot be used with a target IP address")
+
+func (s *StaticServer) read(env params.Env, targetIP net.IP) (err error) {
+	s.Hostname, err = env.Get("OPENVPN_STATIC_HOSTNAME")
+	if err != nil {
+		return err
+	} else if s.Hostname == "" {
+		return nil
+	}
+
+	if targetIP != nil {
+		return fmt.Errorf("%w: %s", ErrStaticServerTargetIP, targetIP)
+	}
+
+	s.Resolver, err = readIP(env, "OPENVPN_STATIC_RESOLVER")
+	if err != nil {
+		return err
+	} else if s.Resolver == nil {
+		s.Resolver = net.IPv4(1, 1, 1, 1) //nolint:gomnd
+	}
+
+	s.CheckPeriod, err = env.Duration("OPENVPN_STATIC_CHECK_PERIOD", params.Default("5m"))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
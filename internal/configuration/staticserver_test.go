@@ -0,0 +1,61 @@
+package configuration
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/golibs/params/mock_params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StaticServer_read(t *testing.T) {
+	t.Parallel()
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		env := mock_params.NewMockEnv(ctrl)
+		env.EXPECT().Get("OPENVPN_STATIC_HOSTNAME").Return("", nil)
+
+		var settings StaticServer
+		err := settings.read(env, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, StaticServer{}, settings)
+	})
+
+	t.Run("target IP set", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		env := mock_params.NewMockEnv(ctrl)
+		env.EXPECT().Get("OPENVPN_STATIC_HOSTNAME").Return("vpn.example.com", nil)
+
+		var settings StaticServer
+		err := settings.read(env, net.IPv4(1, 2, 3, 4))
+
+		assert.ErrorIs(t, err, ErrStaticServerTargetIP)
+	})
+
+	t.Run("default resolver", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		env := mock_params.NewMockEnv(ctrl)
+		env.EXPECT().Get("OPENVPN_STATIC_HOSTNAME").Return("vpn.example.com", nil)
+		env.EXPECT().Get("OPENVPN_STATIC_RESOLVER").Return("", nil)
+		env.EXPECT().Duration("OPENVPN_STATIC_CHECK_PERIOD", gomock.Any()).Return(time.Minute, nil)
+
+		var settings StaticServer
+		err := settings.read(env, nil)
+
+		require.NoError(t, err)
+		expected := StaticServer{
+			Hostname:    "vpn.example.com",
+			Resolver:    net.IPv4(1, 1, 1, 1),
+			CheckPeriod: time.Minute,
+		}
+		assert.Equal(t, expected, settings)
+	})
+}
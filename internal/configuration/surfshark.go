@@ -9,6 +9,8 @@ func (settings *Provider) surfsharkLines() (lines []string) {
 		lines = append(lines, lastIndent+"Regions: "+commaJoin(settings.ServerSelection.Regions))
 	}
 
+	lines = append(lines, settings.ServerSelection.MultiHop.lines()...)
+
 	return lines
 }
 
@@ -30,5 +32,9 @@ func (settings *Provider) readSurfshark(r reader) (err error) {
 		return err
 	}
 
+	if err := settings.ServerSelection.MultiHop.read(r.env); err != nil {
+		return err
+	}
+
 	return nil
 }
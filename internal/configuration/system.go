@@ -9,9 +9,9 @@ import (
 
 // System contains settings to configure system related elements.
 type System struct {
-	PUID     int
-	PGID     int
-	Timezone string
+	PUID     int    `json:"puid"`
+	PGID     int    `json:"pgid"`
+	Timezone string `json:"timezone"`
 }
 
 func (settings *System) String() string {
@@ -0,0 +1,61 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrTLSCryptBothSet    = errors.New("tls-crypt and tls-crypt-v2 keys cannot be both set")
+	ErrTLSCryptKeyInvalid = errors.New("tls-crypt key is not valid")
+)
+
+const (
+	tlsCryptKeyHeader   = "-----BEGIN OpenVPN Static key V1-----"
+	tlsCryptV2KeyHeader = "-----BEGIN OpenVPN tls-crypt-v2 client key-----"
+)
+
+// readTLSCrypt reads the OpenVPN tls-crypt or tls-crypt-v2 client key from
+// the OPENVPN_TLS_CRYPT or OPENVPN_TLS_CRYPT_V2 environment variables, which
+// can be file paths or base64 encoded inline values.
+func (options *ExtraConfigOptions) readTLSCrypt(r reader) (err error) {
+	options.TLSCrypt, err = readTLSCryptKey(r, "OPENVPN_TLS_CRYPT", tlsCryptKeyHeader)
+	if err != nil {
+		return err
+	}
+
+	options.TLSCryptV2, err = readTLSCryptKey(r, "OPENVPN_TLS_CRYPT_V2", tlsCryptV2KeyHeader)
+	if err != nil {
+		return err
+	}
+
+	if options.TLSCrypt != "" && options.TLSCryptV2 != "" {
+		return ErrTLSCryptBothSet
+	}
+
+	return nil
+}
+
+func readTLSCryptKey(r reader, key, header string) (tlsCryptKey string, err error) {
+	b, err := readClientFile(r, key, "", false)
+	if err != nil || b == nil {
+		return "", err
+	}
+	return extractTLSCryptKey(string(b), header)
+}
+
+// extractTLSCryptKey returns the key block starting with the header given,
+// without any comment line OpenVPN key files usually start with.
+func extractTLSCryptKey(s, header string) (tlsCryptKey string, err error) {
+	start := strings.Index(s, header)
+	if start == -1 {
+		return "", fmt.Errorf("%w: %q header not found", ErrTLSCryptKeyInvalid, header)
+	}
+	footer := strings.Replace(header, "BEGIN", "END", 1)
+	end := strings.Index(s, footer)
+	if end == -1 {
+		return "", fmt.Errorf("%w: %q footer not found", ErrTLSCryptKeyInvalid, footer)
+	}
+	return s[start : end+len(footer)], nil
+}
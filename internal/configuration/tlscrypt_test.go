@@ -0,0 +1,44 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_extractTLSCryptKey(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		s           string
+		tlsCryptKey string
+		err         string
+	}{
+		"empty": {
+			err: `tls-crypt key is not valid: "-----BEGIN OpenVPN Static key V1-----" header not found`,
+		},
+		"missing footer": {
+			s:   "-----BEGIN OpenVPN Static key V1-----\nabcd\n",
+			err: `tls-crypt key is not valid: "-----END OpenVPN Static key V1-----" footer not found`,
+		},
+		"valid key with comments": {
+			s: "#\n# 2048 bit OpenVPN static key\n#\n" +
+				"-----BEGIN OpenVPN Static key V1-----\nabcd\n-----END OpenVPN Static key V1-----\n",
+			tlsCryptKey: "-----BEGIN OpenVPN Static key V1-----\nabcd\n-----END OpenVPN Static key V1-----",
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			tlsCryptKey, err := extractTLSCryptKey(testCase.s, tlsCryptKeyHeader)
+			if testCase.err != "" {
+				require.Error(t, err)
+				assert.Equal(t, testCase.err, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.tlsCryptKey, tlsCryptKey)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/golibs/params"
+)
+
+// TorrentClient contains settings for the torrent client
+// to set the listening port on.
+type TorrentClient struct {
+	// Name is the name of the torrent client, and is
+	// empty to not update any torrent client.
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+func (settings *TorrentClient) lines() (lines []string) {
+	lines = append(lines, lastIndent+"Torrent client: "+settings.Name)
+	lines = append(lines, indent+lastIndent+"URL: "+settings.URL)
+	if settings.User != "" {
+		lines = append(lines, indent+lastIndent+"User: "+settings.User)
+	}
+	if settings.Password != "" {
+		lines = append(lines, indent+lastIndent+"Password: [redacted]")
+	}
+	return lines
+}
+
+var ErrTorrentClientURLInvalid = errors.New("torrent client URL is not a valid http or https URL")
+
+func (settings *TorrentClient) read(r reader) (err error) {
+	settings.Name, err = r.env.Inside("PORT_FORWARD_TORRENT_CLIENT",
+		append(constants.TorrentClients(), ""))
+	if err != nil || settings.Name == "" {
+		return err
+	}
+
+	settings.URL, err = r.env.Get("PORT_FORWARD_TORRENT_CLIENT_URL", params.Compulsory(),
+		params.CaseSensitiveValue())
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(settings.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: %s", ErrTorrentClientURLInvalid, settings.URL)
+	}
+
+	settings.User, err = r.env.Get("PORT_FORWARD_TORRENT_CLIENT_USER", params.CaseSensitiveValue())
+	if err != nil {
+		return err
+	}
+
+	settings.Password, err = r.getFromEnvOrSecretFile("PORT_FORWARD_TORRENT_CLIENT_PASSWORD", false, nil)
+	return err
+}
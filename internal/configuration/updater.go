@@ -1,9 +1,12 @@
 package configuration
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/golibs/params"
 )
 
@@ -23,9 +26,12 @@ type Updater struct {
 	Torguard   bool          `json:"torguard"`
 	Vyprvpn    bool          `json:"vyprvpn"`
 	Windscribe bool          `json:"windscribe"`
-	// The two below should be used in CLI mode only
+	// The three below should be used in CLI mode only
 	Stdout bool `json:"-"` // in order to update constants file (maintainer side)
 	CLI    bool `json:"-"`
+	// Latency is to measure the latency of the servers, which is not done
+	// by the periodic updater since it runs through the VPN tunnel.
+	Latency bool `json:"-"`
 }
 
 func (settings *Updater) String() string {
@@ -45,11 +51,46 @@ func (settings *Updater) lines() (lines []string) {
 }
 
 func (settings *Updater) read(r reader) (err error) {
+	providers, err := r.env.CSVInside("UPDATER_VPN_SERVICE", UpdaterProviderChoices())
+	if err != nil {
+		return err
+	}
+	if len(providers) == 0 {
+		settings.setDefaultProviders()
+	} else if err := settings.SetProviders(providers); err != nil {
+		return err
+	}
+	settings.Stdout = false
+	settings.CLI = false
+	settings.Latency = false
+	// use cloudflare in plaintext to not be blocked by DNS over TLS by default.
+	// If a plaintext address is set in the DNS settings, this one will be used.
+	// TODO use custom future encrypted DNS written in Go without blocking
+	// as it's too much trouble to start another parallel unbound instance for now.
+	settings.DNSAddress = "1.1.1.1"
+
+	settings.Period, err = r.env.Duration("UPDATER_PERIOD", params.Default("0"))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdaterProviderChoices returns the provider names which can be given
+// to SetProviders.
+func UpdaterProviderChoices() []string {
+	return append([]string{"all", "pia"}, constants.VPNProviders()...)
+}
+
+// setDefaultProviders enables the update of the providers
+// updated periodically if UPDATER_VPN_SERVICE is not set.
+func (settings *Updater) setDefaultProviders() {
 	settings.Cyberghost = true
+	settings.Fastestvpn = false
 	settings.HideMyAss = true
 	settings.Mullvad = true
 	settings.Nordvpn = true
-	settings.Privado = true
 	settings.PIA = true
 	settings.Privado = true
 	settings.Privatevpn = true
@@ -58,18 +99,72 @@ func (settings *Updater) read(r reader) (err error) {
 	settings.Torguard = true
 	settings.Vyprvpn = true
 	settings.Windscribe = true
-	settings.Stdout = false
-	settings.CLI = false
-	// use cloudflare in plaintext to not be blocked by DNS over TLS by default.
-	// If a plaintext address is set in the DNS settings, this one will be used.
-	// TODO use custom future encrypted DNS written in Go without blocking
-	// as it's too much trouble to start another parallel unbound instance for now.
-	settings.DNSAddress = "1.1.1.1"
+}
 
-	settings.Period, err = r.env.Duration("UPDATER_PERIOD", params.Default("0"))
-	if err != nil {
-		return err
-	}
+var ErrUpdaterProviderUnknown = errors.New("updater VPN provider is unknown")
 
+// SetProviders enables the update of the providers given only.
+// The special provider name "all" enables all providers.
+func (settings *Updater) SetProviders(providers []string) (err error) {
+	settings.Cyberghost = false
+	settings.Fastestvpn = false
+	settings.HideMyAss = false
+	settings.Mullvad = false
+	settings.Nordvpn = false
+	settings.PIA = false
+	settings.Privado = false
+	settings.Privatevpn = false
+	settings.Purevpn = false
+	settings.Surfshark = false
+	settings.Torguard = false
+	settings.Vyprvpn = false
+	settings.Windscribe = false
+
+	for _, provider := range providers {
+		switch strings.ToLower(strings.TrimSpace(provider)) {
+		case "all":
+			settings.Cyberghost = true
+			settings.Fastestvpn = true
+			settings.HideMyAss = true
+			settings.Mullvad = true
+			settings.Nordvpn = true
+			settings.PIA = true
+			settings.Privado = true
+			settings.Privatevpn = true
+			settings.Purevpn = true
+			settings.Surfshark = true
+			settings.Torguard = true
+			settings.Vyprvpn = true
+			settings.Windscribe = true
+		case constants.Cyberghost:
+			settings.Cyberghost = true
+		case constants.Fastestvpn:
+			settings.Fastestvpn = true
+		case constants.HideMyAss:
+			settings.HideMyAss = true
+		case constants.Mullvad:
+			settings.Mullvad = true
+		case constants.Nordvpn:
+			settings.Nordvpn = true
+		case "pia", constants.PrivateInternetAccess:
+			settings.PIA = true
+		case constants.Privado:
+			settings.Privado = true
+		case constants.Privatevpn:
+			settings.Privatevpn = true
+		case constants.Purevpn:
+			settings.Purevpn = true
+		case constants.Surfshark:
+			settings.Surfshark = true
+		case constants.Torguard:
+			settings.Torguard = true
+		case constants.Vyprvpn:
+			settings.Vyprvpn = true
+		case constants.Windscribe:
+			settings.Windscribe = true
+		default:
+			return fmt.Errorf("%w: %s", ErrUpdaterProviderUnknown, provider)
+		}
+	}
 	return nil
 }
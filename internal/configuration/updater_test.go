@@ -0,0 +1,153 @@
+package configuration
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/golibs/params/mock_params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Updater_read(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		providers  []string
+		envErr     error
+		settings   Updater
+		err        error
+		errMessage string
+	}{
+		"default providers": {
+			settings: Updater{
+				DNSAddress: "1.1.1.1",
+				Cyberghost: true,
+				HideMyAss:  true,
+				Mullvad:    true,
+				Nordvpn:    true,
+				PIA:        true,
+				Privado:    true,
+				Privatevpn: true,
+				Purevpn:    true,
+				Surfshark:  true,
+				Torguard:   true,
+				Vyprvpn:    true,
+				Windscribe: true,
+				Period:     time.Hour,
+			},
+		},
+		"selected providers": {
+			providers: []string{"mullvad", "pia"},
+			settings: Updater{
+				DNSAddress: "1.1.1.1",
+				Mullvad:    true,
+				PIA:        true,
+				Period:     time.Hour,
+			},
+		},
+		"environment error": {
+			envErr:     errTest,
+			err:        errTest,
+			errMessage: "test error",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			env := mock_params.NewMockEnv(ctrl)
+			env.EXPECT().
+				CSVInside("UPDATER_VPN_SERVICE", UpdaterProviderChoices()).
+				Return(testCase.providers, testCase.envErr)
+			if testCase.envErr == nil {
+				env.EXPECT().Duration("UPDATER_PERIOD", gomock.Any()).Return(time.Hour, nil)
+			}
+			r := reader{env: env}
+
+			var settings Updater
+			err := settings.read(r)
+
+			assert.ErrorIs(t, err, testCase.err)
+			if testCase.err != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, testCase.settings, settings)
+		})
+	}
+}
+
+func Test_Updater_SetProviders(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		providers  []string
+		settings   Updater
+		err        error
+		errMessage string
+	}{
+		"no provider": {},
+		"all": {
+			providers: []string{"all"},
+			settings: Updater{
+				Cyberghost: true,
+				Fastestvpn: true,
+				HideMyAss:  true,
+				Mullvad:    true,
+				Nordvpn:    true,
+				PIA:        true,
+				Privado:    true,
+				Privatevpn: true,
+				Purevpn:    true,
+				Surfshark:  true,
+				Torguard:   true,
+				Vyprvpn:    true,
+				Windscribe: true,
+			},
+		},
+		"pia aliases with spaces and case": {
+			providers: []string{" PIA", "private internet access "},
+			settings:  Updater{PIA: true},
+		},
+		"several providers": {
+			providers: []string{"fastestvpn", "windscribe"},
+			settings: Updater{
+				Fastestvpn: true,
+				Windscribe: true,
+			},
+		},
+		"unknown provider": {
+			providers:  []string{"mullvad", "unknown"},
+			settings:   Updater{Mullvad: true},
+			err:        ErrUpdaterProviderUnknown,
+			errMessage: "updater VPN provider is unknown: unknown",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Previously enabled providers are disabled
+			settings := Updater{Cyberghost: true}
+
+			err := settings.SetProviders(testCase.providers)
+
+			assert.ErrorIs(t, err, testCase.err)
+			if testCase.err != nil {
+				require.Error(t, err)
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.settings, settings)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package configuration
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/golibs/params"
+)
+
+// Wireguard contains settings to configure the Wireguard client.
+type Wireguard struct {
+	PrivateKey   string      `json:"privatekey"`
+	PreSharedKey string      `json:"presharedkey"`
+	PublicKey    string      `json:"publickey"`
+	EndpointIP   net.IP      `json:"endpoint_ip"`
+	EndpointPort uint16      `json:"endpoint_port"`
+	Addresses    []net.IPNet `json:"addresses"`
+	Interface    string      `json:"interface"`
+	// Provider is empty for custom settings, or set to the provider name
+	// if the peer settings are obtained from the provider at runtime.
+	Provider        string          `json:"provider"`
+	Token           string          `json:"-"`
+	ServerSelection ServerSelection `json:"server_selection"`
+	// KeyRotationPeriod is the period to rotate the private key at using
+	// the VPN provider API, and is 0 if key rotation is disabled.
+	KeyRotationPeriod time.Duration `json:"key_rotation_period"`
+	// Account is the VPN provider account used to rotate the key.
+	Account string `json:"-"`
+}
+
+func (settings *Wireguard) String() string {
+	return strings.Join(settings.lines(), "\n")
+}
+
+func (settings *Wireguard) lines() (lines []string) {
+	lines = append(lines, lastIndent+"Wireguard:")
+
+	lines = append(lines, indent+lastIndent+"Network interface: "+settings.Interface)
+
+	if settings.Provider == constants.Nordvpn {
+		lines = append(lines, indent+lastIndent+"Provider: NordVPN (NordLynx)")
+		for _, line := range (&Provider{ServerSelection: settings.ServerSelection}).nordvpnLines() {
+			lines = append(lines, indent+indent+line)
+		}
+		return lines
+	}
+
+	lines = append(lines, indent+lastIndent+"Private key: [set]")
+
+	if settings.PreSharedKey != "" {
+		lines = append(lines, indent+lastIndent+"Pre-shared key: [set]")
+	}
+
+	endpoint := net.JoinHostPort(settings.EndpointIP.String(), strconv.Itoa(int(settings.EndpointPort)))
+	lines = append(lines, indent+lastIndent+"Peer endpoint: "+endpoint)
+	lines = append(lines, indent+lastIndent+"Peer public key: "+settings.PublicKey)
+
+	lines = append(lines, indent+lastIndent+"Addresses:")
+	for _, address := range settings.Addresses {
+		lines = append(lines, indent+indent+lastIndent+address.String())
+	}
+
+	if settings.KeyRotationPeriod > 0 {
+		lines = append(lines, indent+lastIndent+"Key rotation period: "+settings.KeyRotationPeriod.String())
+	}
+
+	return lines
+}
+
+var (
+	ErrWireguardKeyInvalid       = errors.New("wireguard key is not valid")
+	ErrWireguardEndpointIPNotSet = errors.New("wireguard endpoint IP address is not set")
+	ErrWireguardAddressNotSet    = errors.New("wireguard address is not set")
+	ErrWireguardAddressInvalid   = errors.New("wireguard address is not valid")
+	ErrWireguardKeyRotation      = errors.New("wireguard key rotation is not supported")
+)
+
+func (settings *Wireguard) read(r reader) (err error) {
+	settings.Interface, err = r.env.Get("WIREGUARD_INTERFACE",
+		params.Default(constants.WireguardDevice), params.CaseSensitiveValue())
+	if err != nil {
+		return err
+	}
+
+	vpnsp, err := r.env.Get("VPNSP", params.Default(constants.PrivateInternetAccess))
+	if err != nil {
+		return err
+	}
+	if vpnsp == constants.Nordvpn {
+		return settings.readNordlynx(r)
+	}
+	settings.Provider = ""
+
+	settings.PrivateKey, err = r.getFromEnvOrSecretFile("WIREGUARD_PRIVATE_KEY", true, nil)
+	if err != nil {
+		return err
+	} else if err := checkWireguardKey(settings.PrivateKey); err != nil {
+		return fmt.Errorf("environment variable WIREGUARD_PRIVATE_KEY: %w", err)
+	}
+
+	settings.PreSharedKey, err = r.getFromEnvOrSecretFile("WIREGUARD_PRESHARED_KEY", false, nil)
+	if err != nil {
+		return err
+	} else if settings.PreSharedKey != "" {
+		if err := checkWireguardKey(settings.PreSharedKey); err != nil {
+			return fmt.Errorf("environment variable WIREGUARD_PRESHARED_KEY: %w", err)
+		}
+	}
+
+	settings.PublicKey, err = r.env.Get("WIREGUARD_PUBLIC_KEY",
+		params.Compulsory(), params.CaseSensitiveValue())
+	if err != nil {
+		return err
+	} else if err := checkWireguardKey(settings.PublicKey); err != nil {
+		return fmt.Errorf("environment variable WIREGUARD_PUBLIC_KEY: %w", err)
+	}
+
+	settings.EndpointIP, err = readIP(r.env, "WIREGUARD_ENDPOINT_IP")
+	if err != nil {
+		return err
+	} else if settings.EndpointIP == nil {
+		return ErrWireguardEndpointIPNotSet
+	}
+
+	settings.EndpointPort, err = r.env.Port("WIREGUARD_ENDPOINT_PORT",
+		params.Default(strconv.Itoa(int(constants.WireguardDefaultPort))))
+	if err != nil {
+		return err
+	}
+
+	settings.Addresses, err = readWireguardAddresses(r.env)
+	if err != nil {
+		return err
+	}
+
+	return settings.readKeyRotation(r, vpnsp)
+}
+
+// readKeyRotation reads the key rotation settings, which is only supported
+// for providers capable of it, using the account number as OpenVPN user.
+func (settings *Wireguard) readKeyRotation(r reader, vpnsp string) (err error) {
+	settings.KeyRotationPeriod, err = r.env.Duration("WIREGUARD_KEY_ROTATION_PERIOD", params.Default("0"))
+	if err != nil {
+		return err
+	} else if settings.KeyRotationPeriod == 0 {
+		return nil
+	} else if !constants.ProviderCapabilities()[vpnsp].WireguardKeyRotation {
+		return fmt.Errorf("%w: for VPN provider %s", ErrWireguardKeyRotation, vpnsp)
+	}
+
+	settings.Account, err = r.getFromEnvOrSecretFile("OPENVPN_USER", true, []string{"USER"})
+	if err != nil {
+		return fmt.Errorf("cannot read account number: %w", err)
+	}
+	return nil
+}
+
+// readNordlynx reads the settings for NordLynx, where the keys and the
+// peer are only known at runtime using the NordVPN token and servers.
+func (settings *Wireguard) readNordlynx(r reader) (err error) {
+	settings.Provider = constants.Nordvpn
+
+	settings.Token, err = r.getFromEnvOrSecretFile("NORDVPN_TOKEN", true, nil)
+	if err != nil {
+		return err
+	}
+
+	settings.ServerSelection.Regions, err = r.env.CSVInside("REGION", constants.NordvpnRegionChoices())
+	if err != nil {
+		return err
+	}
+
+	settings.ServerSelection.Numbers, err = readNordVPNServerNumbers(r.env)
+	if err != nil {
+		return err
+	}
+
+	settings.EndpointPort = constants.WireguardDefaultPort
+	settings.Addresses = []net.IPNet{constants.NordlynxAddress()}
+
+	return nil
+}
+
+func checkWireguardKey(key string) error {
+	const keyLength = 32
+	b, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrWireguardKeyInvalid, err)
+	} else if len(b) != keyLength {
+		return fmt.Errorf("%w: %d bytes instead of %d bytes",
+			ErrWireguardKeyInvalid, len(b), keyLength)
+	}
+	return nil
+}
+
+// readWireguardAddresses reads the interface addresses in CIDR notation,
+// keeping the host bits of each address unlike readCSVIPNets.
+func readWireguardAddresses(env params.Env) (addresses []net.IPNet, err error) {
+	s, err := env.Get("WIREGUARD_ADDRESS")
+	if err != nil {
+		return nil, err
+	} else if s == "" {
+		return nil, ErrWireguardAddressNotSet
+	}
+
+	for _, addressStr := range strings.Split(s, ",") {
+		ip, ipNet, err := net.ParseCIDR(addressStr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrWireguardAddressInvalid, err)
+		}
+		ipNet.IP = ip
+		addresses = append(addresses, *ipNet)
+	}
+	return addresses, nil
+}
@@ -0,0 +1,53 @@
+package constants
+
+import (
+	"sort"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// ProviderCapabilities returns the capabilities of each VPN provider,
+// and is the registry of the VPN providers supported.
+func ProviderCapabilities() map[string]models.ProviderCapabilities {
+	const httpsPort = 443
+	return map[string]models.ProviderCapabilities{
+		Cyberghost: {},
+		Fastestvpn: {},
+		HideMyAss:  {},
+		Mullvad: {
+			OpenVPNIPv6:          true,
+			WireguardKeyRotation: true,
+			TCPFallbackPort:      httpsPort,
+		},
+		Nordvpn: {
+			ServerFeatures: []string{P2P, Tor},
+		},
+		Privado: {},
+		PrivateInternetAccess: {
+			PortForwarding:  true,
+			TCPFallbackPort: httpsPort,
+		},
+		Privatevpn: {
+			OpenVPNIPv6: true,
+		},
+		Purevpn:   {},
+		Surfshark: {},
+		Torguard:  {},
+		Vyprvpn:   {},
+		Windscribe: {
+			ServerFeatures:  []string{P2P, Streaming},
+			TCPFallbackPort: httpsPort,
+		},
+	}
+}
+
+// VPNProviders returns the names of the VPN providers supported, sorted.
+func VPNProviders() (providers []string) {
+	capabilities := ProviderCapabilities()
+	providers = make([]string, 0, len(capabilities))
+	for provider := range capabilities {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	return providers
+}
@@ -0,0 +1,91 @@
+package constants
+
+import (
+	"net"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+const (
+	// DoHCustom is the DNS over HTTPS provider name for a custom URL.
+	DoHCustom = "custom"
+)
+
+const (
+	// DNSQueryLogNone disables the DNS query log.
+	DNSQueryLogNone = "none"
+	// DNSQueryLogAnonymized logs the DNS queries with anonymized client IP addresses.
+	DNSQueryLogAnonymized = "anonymized"
+	// DNSQueryLogFull logs the DNS queries with the client IP addresses.
+	DNSQueryLogFull = "full"
+)
+
+const (
+	// DNSSECUpstream answers with a server failure to queries failing
+	// the DNSSEC validation done by the encrypted upstream servers.
+	// No validation is done locally: the failures are detected with the
+	// extended DNS errors of RFC 8914, so a bogus response without such
+	// error is answered as is.
+	DNSSECUpstream = "upstream"
+	// DNSSECPermissive answers queries failing the DNSSEC validation of
+	// the upstream servers with their response not validated, logging
+	// the failure.
+	DNSSECPermissive = "permissive"
+	// DNSSECOff disables the DNSSEC validation of the upstream servers.
+	DNSSECOff = "off"
+)
+
+const (
+	// DNSUpstreamRoundRobin rotates between the healthy upstream DNS servers.
+	DNSUpstreamRoundRobin = "round-robin"
+	// DNSUpstreamPriority uses the first healthy upstream DNS server
+	// in the order of the servers configured.
+	DNSUpstreamPriority = "priority"
+)
+
+const (
+	// DNSBlockMalicious is the block category of malicious hostnames and IP addresses.
+	DNSBlockMalicious = "malicious"
+	// DNSBlockSurveillance is the block category of surveillance hostnames and IP addresses.
+	DNSBlockSurveillance = "surveillance"
+	// DNSBlockAds is the block category of ads hostnames and IP addresses.
+	DNSBlockAds = "ads"
+)
+
+// DNSBlockCategories returns the names of the block categories supported.
+func DNSBlockCategories() []string {
+	return []string{DNSBlockMalicious, DNSBlockSurveillance, DNSBlockAds}
+}
+
+// DoHServers returns the DNS over HTTPS servers of the providers supported.
+func DoHServers() map[string]models.DoHServer {
+	return map[string]models.DoHServer{
+		"cloudflare": {
+			URL: "https://cloudflare-dns.com/dns-query",
+			IPs: []net.IP{
+				{1, 1, 1, 1},
+				{1, 0, 0, 1},
+				net.ParseIP("2606:4700:4700::1111"),
+				net.ParseIP("2606:4700:4700::1001"),
+			},
+		},
+		"google": {
+			URL: "https://dns.google/dns-query",
+			IPs: []net.IP{
+				{8, 8, 8, 8},
+				{8, 8, 4, 4},
+				net.ParseIP("2001:4860:4860::8888"),
+				net.ParseIP("2001:4860:4860::8844"),
+			},
+		},
+		"quad9": {
+			URL: "https://dns.quad9.net/dns-query",
+			IPs: []net.IP{
+				{9, 9, 9, 9},
+				{149, 112, 112, 112},
+				net.ParseIP("2620:fe::fe"),
+				net.ParseIP("2620:fe::9"),
+			},
+		},
+	}
+}
@@ -0,0 +1,14 @@
+package constants
+
+const (
+	// P2P is a server feature for servers allowing peer to peer traffic.
+	P2P = "p2p"
+	// Streaming is a server feature for servers unblocking streaming services.
+	Streaming = "streaming"
+	// Tor is a server feature for servers routing traffic through the Tor network.
+	Tor = "tor"
+)
+
+func ServerFeatureChoices() (choices []string) {
+	return []string{P2P, Streaming, Tor}
+}
@@ -0,0 +1,50 @@
+package constants
+
+import "net"
+
+const (
+	// FirewallBackendAuto is a firewall backend value to detect
+	// the firewall backend to use.
+	FirewallBackendAuto = "auto"
+	// IPTables is a firewall backend using iptables and ip6tables.
+	IPTables = "iptables"
+	// NFTables is a firewall backend using nftables.
+	NFTables = "nftables"
+)
+
+const (
+	// LANPortsMark is the firewall mark set on the connections to the LAN
+	// ports, to route their replies through the default interface.
+	LANPortsMark uint32 = 0x6c616e
+)
+
+const (
+	// BypassSourcesMark is the firewall mark set on the packets forwarded
+	// from the bypass sources and on the packets from the bypass user IDs,
+	// to route them through the default interface.
+	BypassSourcesMark uint32 = 0x627970
+)
+
+const (
+	// TunnelMark is the connection mark set on the connections through
+	// the VPN interface, to flush them once the tunnel changes.
+	TunnelMark uint32 = 0x74756e
+)
+
+// MulticastDNSGroup is a multicast group address and port used
+// to resolve hostnames on the local network.
+type MulticastDNSGroup struct {
+	IP   net.IP
+	Port uint16
+}
+
+// MulticastDNSGroups returns the mDNS and LLMNR groups, see
+// RFC 6762 section 3 and RFC 4795 section 2.
+func MulticastDNSGroups() []MulticastDNSGroup { //nolint:gomnd
+	return []MulticastDNSGroup{
+		{IP: net.IPv4(224, 0, 0, 251).To4(), Port: 5353}, // mDNS
+		{IP: net.ParseIP("ff02::fb"), Port: 5353},        // mDNS
+		{IP: net.IPv4(224, 0, 0, 252).To4(), Port: 5355}, // LLMNR
+		{IP: net.ParseIP("ff02::1:3"), Port: 5355},       // LLMNR
+	}
+}
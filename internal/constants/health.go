@@ -0,0 +1,36 @@
+package constants
+
+const (
+	// HealthCheckTCP checks a TCP connection can be established.
+	HealthCheckTCP = "tcp"
+	// HealthCheckTLS checks a TLS handshake succeeds.
+	HealthCheckTLS = "tls"
+	// HealthCheckHTTP checks an HTTP GET request gets the expected status code.
+	HealthCheckHTTP = "http"
+	// HealthCheckDNS checks a hostname resolves to at least one IP address.
+	HealthCheckDNS = "dns"
+	// HealthCheckICMP checks an ICMP echo request gets a reply.
+	HealthCheckICMP = "icmp"
+)
+
+// HealthCheckTypes returns the types of health checks supported.
+func HealthCheckTypes() []string {
+	return []string{HealthCheckTCP, HealthCheckTLS, HealthCheckHTTP, HealthCheckDNS, HealthCheckICMP}
+}
+
+const (
+	// HealthRecoveryRestart restarts the VPN connection.
+	HealthRecoveryRestart = "restart"
+	// HealthRecoverySwitchServer connects to another server matching the server selection.
+	HealthRecoverySwitchServer = "switch_server"
+	// HealthRecoverySwitchProtocol switches the VPN protocol between UDP and TCP.
+	HealthRecoverySwitchProtocol = "switch_protocol"
+	// HealthRecoveryExit exits the program, for the container to be restarted.
+	HealthRecoveryExit = "exit"
+)
+
+// HealthRecoveryActions returns the recovery actions supported.
+func HealthRecoveryActions() []string {
+	return []string{HealthRecoveryRestart, HealthRecoverySwitchServer,
+		HealthRecoverySwitchProtocol, HealthRecoveryExit}
+}
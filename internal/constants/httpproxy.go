@@ -0,0 +1,10 @@
+package constants
+
+const (
+	// HTTPProxyLogOff logs no access.
+	HTTPProxyLogOff = "off"
+	// HTTPProxyLogErrors logs the accesses denied or failing.
+	HTTPProxyLogErrors = "errors"
+	// HTTPProxyLogAll logs all accesses.
+	HTTPProxyLogAll = "all"
+)
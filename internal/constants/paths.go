@@ -1,30 +1,50 @@
 package constants
 
 const (
-	// UnboundConf is the file path to the Unbound configuration file.
-	UnboundConf string = "/etc/unbound/unbound.conf"
 	// ResolvConf is the file path to the system resolv.conf file.
 	ResolvConf string = "/etc/resolv.conf"
 	// CACertificates is the file path to the CA certificates file.
 	CACertificates string = "/etc/ssl/certs/ca-certificates.crt"
 	// OpenVPNAuthConf is the file path to the OpenVPN auth file.
 	OpenVPNAuthConf string = "/etc/openvpn/auth.conf"
+	// OpenVPNProxyAuthConf is the file path to the OpenVPN upstream proxy auth file.
+	OpenVPNProxyAuthConf string = "/etc/openvpn/proxyauth.conf"
 	// OpenVPNConf is the file path to the OpenVPN client configuration file.
 	OpenVPNConf string = "/etc/openvpn/target.ovpn"
+	// FirewallPostRules is the default file path to the user firewall
+	// rules applied after the firewall rules.
+	FirewallPostRules string = "/iptables/post-rules.txt"
+	// StunnelConf is the file path to the stunnel client configuration file.
+	StunnelConf string = "/etc/openvpn/stunnel.conf"
 	// PIAPortForward is the file path to the port forwarding JSON information for PIA servers.
 	PIAPortForward string = "/gluetun/piaportforward.json"
+	// StickyServer is the file path to the JSON information of the last server connected to.
+	StickyServer string = "/gluetun/server.json"
 	// TunnelDevice is the file path to tun device.
 	TunnelDevice string = "/dev/net/tun"
 	// NetRoute is the path to the file containing information on the network route.
 	NetRoute string = "/proc/net/route"
-	// RootHints is the filepath to the root.hints file used by Unbound.
-	RootHints string = "/etc/unbound/root.hints"
-	// RootKey is the filepath to the root.key file used by Unbound.
-	RootKey string = "/etc/unbound/root.key"
 	// Client key filepath, used by Cyberghost.
 	ClientKey string = "/gluetun/client.key"
 	// Client certificate filepath, used by Cyberghost.
 	ClientCertificate string = "/gluetun/client.crt"
+	// APITokens is the file path to the JSON cache of VPN provider API tokens.
+	APITokens string = "/gluetun/tokens.json"
+	// WireguardRotatedKey is the file path to the JSON file holding the
+	// Wireguard private key currently registered and its addresses,
+	// when the key is rotated automatically.
+	WireguardRotatedKey string = "/gluetun/wireguard.json"
+	// DNSLists is the file path to the JSON cache of the DNS block and allow lists.
+	DNSLists string = "/gluetun/dnslists.json"
+	// DNSHosts is the file path to the hosts file answered by the DNS server.
+	DNSHosts string = "/gluetun/hosts"
+	// ControlServerCertificate and ControlServerKey are the file paths to the
+	// self-signed TLS certificate and key generated for the control server.
+	ControlServerCertificate string = "/gluetun/controlserver.crt"
+	ControlServerKey         string = "/gluetun/controlserver.key"
+	// EnvDefaults is the file path to the default values of the
+	// environment variables of the image, written at build time.
+	EnvDefaults string = "/etc/gluetun/env.defaults"
 	// Servers information filepath.
 	ServersData = "/gluetun/servers.json"
 )
@@ -0,0 +1,23 @@
+package constants
+
+const (
+	// PortForwardProxyHTTP is the HTTP proxy exposed on a port forwarded.
+	PortForwardProxyHTTP = "httpproxy"
+	// PortForwardProxyShadowsocks is the Shadowsocks server exposed on a port forwarded.
+	PortForwardProxyShadowsocks = "shadowsocks"
+)
+
+const (
+	// PortForwardProviderProtonvpn is the port forwarding provider to
+	// forward a port with a ProtonVPN custom configuration.
+	PortForwardProviderProtonvpn = "protonvpn"
+	// ProtonvpnPortForwardSuffix is the suffix of the ProtonVPN OpenVPN
+	// user name to connect to a server with port forwarding.
+	ProtonvpnPortForwardSuffix = "+pmp"
+)
+
+// PortForwardProxies returns the proxy servers which
+// can be exposed on the ports forwarded.
+func PortForwardProxies() []string {
+	return []string{PortForwardProxyHTTP, PortForwardProxyShadowsocks}
+}
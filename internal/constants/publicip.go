@@ -0,0 +1,53 @@
+package constants
+
+const (
+	// PublicIPFetcherIpify fetches the public IP address from api.ipify.org.
+	PublicIPFetcherIpify = "ipify"
+	// PublicIPFetcherIcanhazip fetches the public IP address from icanhazip.com.
+	PublicIPFetcherIcanhazip = "icanhazip"
+	// PublicIPFetcherIfconfig fetches the public IP address from ifconfig.me.
+	PublicIPFetcherIfconfig = "ifconfig"
+	// PublicIPFetcherIPInfo fetches the public IP address from ipinfo.io.
+	PublicIPFetcherIPInfo = "ipinfo"
+	// PublicIPFetcherOpenDNS resolves myip.opendns.com with the OpenDNS resolvers.
+	PublicIPFetcherOpenDNS = "opendns"
+	// PublicIPFetcherGoogle resolves the TXT record o-o.myaddr.l.google.com
+	// with the Google authoritative name servers.
+	PublicIPFetcherGoogle = "google"
+)
+
+// PublicIPFetchers returns the public IP address fetchers supported.
+func PublicIPFetchers() []string {
+	return []string{
+		PublicIPFetcherIpify,
+		PublicIPFetcherIcanhazip,
+		PublicIPFetcherIfconfig,
+		PublicIPFetcherIPInfo,
+		PublicIPFetcherOpenDNS,
+		PublicIPFetcherGoogle,
+	}
+}
+
+const (
+	// PublicIPInfoNone disables fetching information on the public IP address.
+	PublicIPInfoNone = "none"
+	// PublicIPInfoIPInfo fetches information from ipinfo.io,
+	// with an optional API token.
+	PublicIPInfoIPInfo = "ipinfo"
+	// PublicIPInfoIP2Location fetches information from ip2location.io,
+	// with an API key.
+	PublicIPInfoIP2Location = "ip2location"
+	// PublicIPInfoMaxMind fetches information from the MaxMind GeoIP2
+	// web service, with an account ID and a license key.
+	PublicIPInfoMaxMind = "maxmind"
+)
+
+// PublicIPInfoProviders returns the public IP information providers supported.
+func PublicIPInfoProviders() []string {
+	return []string{
+		PublicIPInfoNone,
+		PublicIPInfoIPInfo,
+		PublicIPInfoIP2Location,
+		PublicIPInfoMaxMind,
+	}
+}
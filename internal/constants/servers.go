@@ -22,12 +22,12 @@ func GetAllServers() (allServers models.AllServers) {
 			Servers:   HideMyAssServers(),
 		},
 		Mullvad: models.MullvadServers{
-			Version:   1,
-			Timestamp: 1612031135,
+			Version:   2,
+			Timestamp: 1612031136,
 			Servers:   MullvadServers(),
 		},
 		Nordvpn: models.NordvpnServers{
-			Version:   1,
+			Version:   2,
 			Timestamp: 1611096594,
 			Servers:   NordvpnServers(),
 		},
@@ -52,8 +52,8 @@ func GetAllServers() (allServers models.AllServers) {
 			Servers:   PurevpnServers(),
 		},
 		Surfshark: models.SurfsharkServers{
-			Version:   1,
-			Timestamp: 1618612180,
+			Version:   2,
+			Timestamp: 1618612181,
 			Servers:   SurfsharkServers(),
 		},
 		Torguard: models.TorguardServers{
@@ -67,8 +67,8 @@ func GetAllServers() (allServers models.AllServers) {
 			Servers:   VyprvpnServers(),
 		},
 		Windscribe: models.WindscribeServers{
-			Version:   2,
-			Timestamp: 1612031135,
+			Version:   3,
+			Timestamp: 1612031136,
 			Servers:   WindscribeServers(),
 		},
 	}
@@ -52,12 +52,12 @@ func Test_versions(t *testing.T) {
 		"Mullvad": {
 			model:   models.MullvadServer{},
 			version: allServers.Mullvad.Version,
-			digest:  "665e9dc1",
+			digest:  "dc1e18df",
 		},
 		"Nordvpn": {
 			model:   models.NordvpnServer{},
 			version: allServers.Nordvpn.Version,
-			digest:  "040de8d0",
+			digest:  "6a172600",
 		},
 		"Privado": {
 			model:   models.PrivadoServer{},
@@ -82,7 +82,7 @@ func Test_versions(t *testing.T) {
 		"Surfshark": {
 			model:   models.SurfsharkServer{},
 			version: allServers.Surfshark.Version,
-			digest:  "042bef64",
+			digest:  "647e80e3",
 		},
 		"Torguard": {
 			model:   models.TorguardServer{},
@@ -97,7 +97,7 @@ func Test_versions(t *testing.T) {
 		"Windscribe": {
 			model:   models.WindscribeServer{},
 			version: allServers.Windscribe.Version,
-			digest:  "6e3ca639",
+			digest:  "a560b29a",
 		},
 	}
 	for name, testCase := range testCases {
@@ -153,7 +153,7 @@ func Test_timestamps(t *testing.T) {
 		"Mullvad": {
 			servers:   allServers.Mullvad.Servers,
 			timestamp: allServers.Mullvad.Timestamp,
-			digest:    "e2e006cf",
+			digest:    "9e4e39a4",
 		},
 		"Nordvpn": {
 			servers:   allServers.Nordvpn.Servers,
@@ -183,7 +183,7 @@ func Test_timestamps(t *testing.T) {
 		"Surfshark": {
 			servers:   allServers.Surfshark.Servers,
 			timestamp: allServers.Surfshark.Timestamp,
-			digest:    "c090bcd9",
+			digest:    "5658ccc5",
 		},
 		"Torguard": {
 			servers:   allServers.Torguard.Servers,
@@ -198,7 +198,7 @@ func Test_timestamps(t *testing.T) {
 		"Windscribe": {
 			servers:   allServers.Windscribe.Servers,
 			timestamp: allServers.Windscribe.Timestamp,
-			digest:    "4e719aa3",
+			digest:    "6ca4dcf6",
 		},
 	}
 	for name, testCase := range testCases {
@@ -0,0 +1,20 @@
+package constants
+
+// ShadowsocksMethods returns the ciphers supported by the Shadowsocks server.
+func ShadowsocksMethods() []string {
+	return []string{
+		"aes-128-gcm",
+		"aes-256-gcm",
+		"chacha20-ietf-poly1305",
+	}
+}
+
+// Shadowsocks2022Methods returns the Shadowsocks 2022 ciphers,
+// which are not supported by the Shadowsocks server yet.
+func Shadowsocks2022Methods() []string {
+	return []string{
+		"2022-blake3-aes-128-gcm",
+		"2022-blake3-aes-256-gcm",
+		"2022-blake3-chacha20-poly1305",
+	}
+}
@@ -12,3 +12,17 @@ const (
 	Crashed   models.LoopStatus = "crashed"
 	Completed models.LoopStatus = "completed"
 )
+
+const (
+	OpenVPNConnecting     models.OpenVPNState = "connecting"
+	OpenVPNAuthenticating models.OpenVPNState = "authenticating"
+	OpenVPNUp             models.OpenVPNState = "up"
+	OpenVPNDown           models.OpenVPNState = "down"
+	OpenVPNExiting        models.OpenVPNState = "exiting"
+)
+
+const (
+	OpenVPNAuthFailed models.OpenVPNFailure = "auth_failed"
+	OpenVPNTLSTimeout models.OpenVPNFailure = "tls_timeout"
+	OpenVPNRouteError models.OpenVPNFailure = "route_error"
+)
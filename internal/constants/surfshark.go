@@ -32,7 +32,7 @@ func SurfsharkServers() []models.SurfsharkServer {
 		{Region: "Australia Melbourne", IPs: []net.IP{{103, 192, 80, 11}, {103, 192, 80, 13}, {103, 192, 80, 131}, {103, 192, 80, 133}, {103, 192, 80, 141}, {103, 192, 80, 147}, {103, 192, 80, 149}, {103, 192, 80, 229}, {103, 192, 80, 243}, {103, 192, 80, 245}, {103, 192, 80, 253}, {144, 48, 38, 19}, {144, 48, 38, 21}, {144, 48, 38, 139}, {144, 48, 38, 141}, {144, 48, 38, 149}, {144, 48, 38, 179}}},
 		{Region: "Australia Perth", IPs: []net.IP{{45, 248, 78, 43}, {45, 248, 78, 45}, {124, 150, 139, 27}, {124, 150, 139, 29}, {124, 150, 139, 35}, {124, 150, 139, 37}, {124, 150, 139, 45}, {124, 150, 139, 123}, {124, 150, 139, 125}, {124, 150, 139, 179}}},
 		{Region: "Australia Sydney", IPs: []net.IP{{45, 125, 247, 43}, {45, 125, 247, 91}, {45, 125, 247, 93}, {45, 125, 247, 107}, {45, 125, 247, 155}, {45, 125, 247, 157}, {45, 125, 247, 197}, {45, 248, 76, 171}, {103, 25, 59, 51}, {103, 25, 59, 53}, {180, 149, 228, 27}, {180, 149, 228, 117}, {180, 149, 228, 163}, {180, 149, 228, 165}, {180, 149, 228, 171}, {180, 149, 228, 173}, {180, 149, 228, 179}, {180, 149, 228, 181}}},
-		{Region: "Australia US", IPs: []net.IP{{45, 76, 117, 108}}},
+		{Region: "Australia US", IPs: []net.IP{{45, 76, 117, 108}}, MultiHop: &models.MultiHop{EntryCountry: "Australia", ExitCountry: "United States"}},
 		{Region: "Austria", IPs: []net.IP{{5, 253, 207, 53}, {5, 253, 207, 83}, {5, 253, 207, 85}, {37, 120, 212, 75}, {37, 120, 212, 77}, {37, 120, 212, 131}, {37, 120, 212, 133}, {37, 120, 212, 139}, {37, 120, 212, 141}, {37, 120, 212, 149}, {89, 187, 168, 41}, {89, 187, 168, 49}, {89, 187, 168, 54}, {89, 187, 168, 56}}},
 		{Region: "Azerbaijan", IPs: []net.IP{{62, 212, 239, 43}, {62, 212, 239, 45}, {62, 212, 239, 53}, {62, 212, 239, 69}}},
 		{Region: "Belgium", IPs: []net.IP{{5, 253, 205, 99}, {5, 253, 205, 181}, {5, 253, 205, 211}, {37, 120, 143, 117}, {37, 120, 218, 253}, {91, 90, 123, 123}, {91, 90, 123, 147}, {91, 90, 123, 155}, {91, 90, 123, 165}, {91, 90, 123, 171}, {91, 90, 123, 173}, {91, 90, 123, 197}, {185, 104, 186, 77}, {185, 104, 186, 173}, {185, 210, 217, 107}, {185, 210, 217, 109}, {185, 210, 217, 189}, {194, 110, 115, 67}, {194, 110, 115, 69}, {194, 110, 115, 91}, {194, 110, 115, 243}, {194, 110, 115, 245}, {217, 138, 211, 219}, {217, 138, 211, 221}}},
@@ -42,7 +42,7 @@ func SurfsharkServers() []models.SurfsharkServer {
 		{Region: "Canada Montreal", IPs: []net.IP{{91, 245, 254, 19}, {91, 245, 254, 21}, {91, 245, 254, 35}, {91, 245, 254, 37}, {91, 245, 254, 45}, {91, 245, 254, 61}, {91, 245, 254, 77}, {91, 245, 254, 93}, {91, 245, 254, 109}, {91, 245, 254, 115}, {91, 245, 254, 123}, {91, 245, 254, 125}, {91, 245, 254, 133}, {172, 98, 82, 243}, {172, 98, 82, 245}}},
 		{Region: "Canada Toronto", IPs: []net.IP{{68, 71, 244, 131}, {68, 71, 244, 134}, {68, 71, 244, 195}, {68, 71, 244, 200}, {68, 71, 244, 202}, {68, 71, 244, 205}, {68, 71, 244, 212}, {68, 71, 244, 217}, {104, 200, 138, 5}, {104, 200, 138, 7}, {104, 200, 138, 99}, {104, 200, 138, 147}, {104, 200, 138, 149}, {104, 200, 138, 154}, {104, 200, 138, 165}, {162, 253, 71, 211}, {192, 111, 128, 136}, {192, 111, 128, 141}}},
 		{Region: "Canada Toronto mp001", IPs: []net.IP{{138, 197, 151, 26}}},
-		{Region: "Canada US", IPs: []net.IP{{159, 203, 57, 80}}},
+		{Region: "Canada US", IPs: []net.IP{{159, 203, 57, 80}}, MultiHop: &models.MultiHop{EntryCountry: "Canada", ExitCountry: "United States"}},
 		{Region: "Canada Vancouver", IPs: []net.IP{{66, 115, 147, 67}, {66, 115, 147, 69}, {66, 115, 147, 72}, {66, 115, 147, 74}, {66, 115, 147, 77}, {66, 115, 147, 84}, {66, 115, 147, 89}, {66, 115, 147, 92}, {107, 181, 177, 181}, {172, 83, 40, 147}, {172, 83, 40, 149}, {198, 8, 92, 69}, {198, 8, 92, 72}, {198, 8, 92, 77}, {198, 8, 92, 82}, {198, 8, 92, 87}, {208, 78, 41, 195}, {208, 78, 41, 197}, {208, 78, 41, 200}, {208, 78, 41, 202}}},
 		{Region: "Chile", IPs: []net.IP{{31, 169, 121, 3}, {31, 169, 121, 5}}},
 		{Region: "Colombia", IPs: []net.IP{{45, 129, 32, 3}, {45, 129, 32, 5}, {45, 129, 32, 8}, {45, 129, 32, 10}, {45, 129, 32, 13}, {45, 129, 32, 15}, {45, 129, 32, 20}, {45, 129, 32, 22}, {45, 129, 32, 27}, {45, 129, 32, 29}, {45, 129, 32, 32}, {45, 129, 32, 34}, {45, 129, 32, 36}, {45, 129, 32, 38}}},
@@ -56,7 +56,7 @@ func SurfsharkServers() []models.SurfsharkServer {
 		{Region: "France Bordeaux", IPs: []net.IP{{185, 108, 106, 19}, {185, 108, 106, 24}, {185, 108, 106, 51}, {185, 108, 106, 53}, {185, 108, 106, 72}, {185, 108, 106, 89}, {185, 108, 106, 91}, {185, 108, 106, 102}, {185, 108, 106, 106}, {185, 108, 106, 148}, {185, 108, 106, 150}, {185, 108, 106, 152}, {185, 108, 106, 158}, {185, 108, 106, 160}, {185, 108, 106, 164}, {185, 108, 106, 176}, {185, 108, 106, 184}, {185, 108, 106, 188}}},
 		{Region: "France Marseilles", IPs: []net.IP{{138, 199, 16, 130}, {138, 199, 16, 135}, {138, 199, 16, 137}, {138, 199, 16, 147}, {138, 199, 16, 152}, {185, 166, 84, 5}, {185, 166, 84, 19}, {185, 166, 84, 21}, {185, 166, 84, 29}, {185, 166, 84, 57}, {185, 166, 84, 59}, {185, 166, 84, 77}, {185, 166, 84, 81}, {185, 166, 84, 85}, {185, 166, 84, 91}, {185, 166, 84, 93}}},
 		{Region: "France Paris", IPs: []net.IP{{84, 17, 43, 180}, {84, 17, 43, 185}, {84, 17, 60, 235}, {84, 17, 60, 250}, {84, 247, 51, 243}, {84, 247, 51, 245}, {84, 247, 51, 251}, {143, 244, 56, 226}, {143, 244, 56, 228}, {143, 244, 56, 230}, {143, 244, 56, 232}, {143, 244, 57, 73}, {143, 244, 57, 83}, {143, 244, 57, 85}, {143, 244, 57, 91}, {143, 244, 57, 93}, {143, 244, 57, 99}, {143, 244, 57, 103}, {143, 244, 57, 106}, {143, 244, 57, 110}, {143, 244, 57, 112}, {143, 244, 57, 117}, {143, 244, 57, 122}, {185, 246, 211, 69}}},
-		{Region: "France Sweden", IPs: []net.IP{{199, 247, 8, 20}}},
+		{Region: "France Sweden", IPs: []net.IP{{199, 247, 8, 20}}, MultiHop: &models.MultiHop{EntryCountry: "France", ExitCountry: "Sweden"}},
 		{Region: "Germany Berlin", IPs: []net.IP{{37, 120, 217, 181}, {152, 89, 163, 21}, {152, 89, 163, 23}, {152, 89, 163, 229}, {152, 89, 163, 231}, {152, 89, 163, 243}, {152, 89, 163, 245}, {193, 29, 106, 3}, {193, 29, 106, 35}, {193, 29, 106, 43}, {193, 29, 106, 51}, {193, 29, 106, 59}, {193, 29, 106, 69}, {193, 29, 106, 99}, {193, 29, 106, 115}, {193, 29, 106, 133}, {193, 29, 106, 219}, {193, 29, 106, 221}, {193, 176, 86, 195}, {193, 176, 86, 199}}},
 		{Region: "Germany Frankfurt am Main", IPs: []net.IP{{37, 120, 196, 53}, {37, 120, 196, 171}, {45, 87, 212, 213}, {82, 102, 16, 99}, {89, 187, 169, 104}, {89, 187, 169, 119}, {138, 199, 19, 137}, {138, 199, 19, 149}, {138, 199, 19, 167}, {138, 199, 19, 169}, {138, 199, 19, 177}, {156, 146, 33, 65}, {156, 146, 33, 67}, {156, 146, 33, 79}, {156, 146, 33, 83}, {156, 146, 33, 87}}},
 		{Region: "Germany Frankfurt am Main st001", IPs: []net.IP{{45, 87, 212, 179}}},
@@ -67,8 +67,8 @@ func SurfsharkServers() []models.SurfsharkServer {
 		{Region: "Germany Frankfurt mp001", IPs: []net.IP{{46, 101, 189, 14}}},
 		{Region: "Germany Munich", IPs: []net.IP{{79, 143, 191, 139}}},
 		{Region: "Germany Nuremberg", IPs: []net.IP{{62, 171, 151, 158}, {62, 171, 151, 160}, {144, 91, 123, 50}, {144, 91, 123, 52}}},
-		{Region: "Germany Singapour", IPs: []net.IP{{159, 89, 14, 157}}},
-		{Region: "Germany UK", IPs: []net.IP{{46, 101, 250, 73}}},
+		{Region: "Germany Singapour", IPs: []net.IP{{159, 89, 14, 157}}, MultiHop: &models.MultiHop{EntryCountry: "Germany", ExitCountry: "Singapore"}},
+		{Region: "Germany UK", IPs: []net.IP{{46, 101, 250, 73}}, MultiHop: &models.MultiHop{EntryCountry: "Germany", ExitCountry: "United Kingdom"}},
 		{Region: "Greece", IPs: []net.IP{{194, 150, 167, 28}, {194, 150, 167, 30}, {194, 150, 167, 32}, {194, 150, 167, 34}, {194, 150, 167, 36}, {194, 150, 167, 38}, {194, 150, 167, 40}, {194, 150, 167, 42}, {194, 150, 167, 44}, {194, 150, 167, 46}, {194, 150, 167, 48}, {194, 150, 167, 50}, {194, 150, 167, 52}, {194, 150, 167, 54}}},
 		{Region: "Hong Kong", IPs: []net.IP{{84, 17, 37, 156}, {84, 17, 37, 158}, {84, 17, 37, 160}, {84, 17, 57, 66}, {84, 17, 57, 68}, {84, 17, 57, 73}, {84, 17, 57, 185}, {212, 102, 42, 196}, {212, 102, 42, 199}, {212, 102, 42, 201}, {212, 102, 42, 204}, {212, 102, 42, 206}, {212, 102, 42, 209}, {212, 102, 42, 211}}},
 		{Region: "Hungary", IPs: []net.IP{{37, 120, 144, 149}, {37, 120, 144, 151}, {37, 120, 144, 197}, {37, 120, 144, 199}, {37, 120, 144, 211}, {37, 120, 144, 213}, {37, 120, 144, 215}}},
@@ -76,7 +76,7 @@ func SurfsharkServers() []models.SurfsharkServer {
 		{Region: "India Chennai", IPs: []net.IP{{103, 94, 27, 99}, {103, 94, 27, 101}, {103, 94, 27, 115}, {103, 94, 27, 117}, {103, 94, 27, 181}, {103, 94, 27, 227}, {103, 108, 117, 116}, {103, 108, 117, 118}, {103, 108, 117, 147}, {103, 108, 117, 149}, {103, 108, 117, 151}}},
 		{Region: "India Indore", IPs: []net.IP{{103, 39, 132, 187}, {103, 39, 132, 189}, {103, 39, 134, 59}, {103, 39, 134, 61}}},
 		{Region: "India Mumbai", IPs: []net.IP{{103, 156, 50, 87}, {103, 156, 50, 89}, {103, 156, 50, 93}, {103, 156, 50, 95}, {103, 156, 50, 101}, {103, 156, 50, 103}, {103, 156, 50, 105}, {103, 156, 50, 107}, {103, 156, 50, 113}, {103, 156, 50, 117}, {103, 156, 51, 2}, {103, 156, 51, 4}, {103, 156, 51, 6}, {103, 156, 51, 10}, {103, 156, 51, 28}, {103, 156, 51, 30}, {103, 156, 51, 32}, {103, 156, 51, 39}, {103, 156, 51, 45}, {103, 156, 51, 51}, {103, 156, 51, 55}, {103, 156, 51, 57}, {103, 156, 51, 68}, {165, 231, 253, 147}, {165, 231, 253, 163}, {165, 231, 253, 165}}},
-		{Region: "India UK", IPs: []net.IP{{134, 209, 148, 122}}},
+		{Region: "India UK", IPs: []net.IP{{134, 209, 148, 122}}, MultiHop: &models.MultiHop{EntryCountry: "India", ExitCountry: "United Kingdom"}},
 		{Region: "Indonesia", IPs: []net.IP{{103, 120, 66, 214}, {103, 120, 66, 216}, {103, 120, 66, 219}, {103, 120, 66, 221}, {103, 120, 66, 227}, {103, 120, 66, 229}, {103, 120, 66, 234}, {103, 120, 66, 236}, {103, 148, 242, 163}, {103, 148, 242, 165}, {103, 148, 242, 168}, {103, 148, 242, 170}}},
 		{Region: "Ireland", IPs: []net.IP{{5, 157, 13, 51}, {5, 157, 13, 53}, {5, 157, 13, 67}, {5, 157, 13, 69}, {5, 157, 13, 85}, {5, 157, 13, 115}, {5, 157, 13, 117}, {5, 157, 13, 123}, {5, 157, 13, 131}, {23, 92, 127, 93}, {37, 120, 235, 67}, {37, 120, 235, 75}, {37, 120, 235, 77}, {37, 120, 235, 83}, {37, 120, 235, 93}, {37, 120, 235, 203}, {37, 120, 235, 211}, {37, 120, 235, 235}, {185, 108, 128, 118}, {185, 108, 128, 120}, {185, 108, 128, 183}, {217, 138, 222, 43}, {217, 138, 222, 45}, {217, 138, 222, 51}}},
 		{Region: "Israel", IPs: []net.IP{{5, 188, 95, 17}, {5, 188, 95, 21}, {87, 239, 255, 107}, {87, 239, 255, 109}, {87, 239, 255, 114}, {87, 239, 255, 116}, {87, 239, 255, 119}, {87, 239, 255, 121}}},
@@ -106,7 +106,7 @@ func SurfsharkServers() []models.SurfsharkServer {
 		{Region: "Netherlands Amsterdam", IPs: []net.IP{{81, 19, 208, 54}, {81, 19, 208, 78}, {81, 19, 208, 91}, {81, 19, 208, 111}, {81, 19, 209, 20}, {81, 19, 209, 59}, {81, 19, 209, 124}, {89, 46, 223, 62}, {89, 46, 223, 64}, {89, 46, 223, 72}, {89, 46, 223, 82}, {89, 46, 223, 84}, {89, 46, 223, 88}, {89, 46, 223, 94}, {89, 46, 223, 100}, {89, 46, 223, 104}, {89, 46, 223, 169}, {89, 46, 223, 181}, {89, 46, 223, 187}, {89, 46, 223, 190}, {89, 46, 223, 217}, {89, 46, 223, 219}, {143, 244, 42, 91}, {143, 244, 42, 96}, {178, 239, 173, 43}, {212, 102, 35, 201}, {212, 102, 35, 204}, {212, 102, 35, 206}}},
 		{Region: "Netherlands Amsterdam mp001", IPs: []net.IP{{188, 166, 43, 117}}},
 		{Region: "Netherlands Amsterdam st001", IPs: []net.IP{{81, 19, 209, 51}}},
-		{Region: "Netherlands US", IPs: []net.IP{{188, 166, 98, 91}}},
+		{Region: "Netherlands US", IPs: []net.IP{{188, 166, 98, 91}}, MultiHop: &models.MultiHop{EntryCountry: "Netherlands", ExitCountry: "United States"}},
 		{Region: "New Zealand", IPs: []net.IP{{180, 149, 231, 3}, {180, 149, 231, 11}, {180, 149, 231, 13}, {180, 149, 231, 43}, {180, 149, 231, 45}, {180, 149, 231, 67}, {180, 149, 231, 69}, {180, 149, 231, 117}, {180, 149, 231, 119}}},
 		{Region: "Nigeria", IPs: []net.IP{{102, 165, 23, 4}, {102, 165, 23, 6}, {102, 165, 23, 38}, {102, 165, 23, 40}, {102, 165, 23, 42}, {102, 165, 23, 44}}},
 		{Region: "North Macedonia", IPs: []net.IP{{185, 225, 28, 67}, {185, 225, 28, 83}, {185, 225, 28, 85}, {185, 225, 28, 91}, {185, 225, 28, 99}, {185, 225, 28, 101}, {185, 225, 28, 107}, {185, 225, 28, 109}, {185, 225, 28, 243}, {185, 225, 28, 245}}},
@@ -122,9 +122,9 @@ func SurfsharkServers() []models.SurfsharkServer {
 		{Region: "Russia St. Petersburg", IPs: []net.IP{{185, 246, 88, 101}, {185, 246, 88, 103}, {185, 246, 88, 107}, {185, 246, 88, 116}, {185, 246, 88, 118}}},
 		{Region: "Serbia", IPs: []net.IP{{37, 120, 193, 51}, {37, 120, 193, 53}, {152, 89, 160, 115}, {152, 89, 160, 117}, {152, 89, 160, 211}, {152, 89, 160, 213}, {152, 89, 160, 215}}},
 		{Region: "Singapore", IPs: []net.IP{{89, 187, 162, 184}, {89, 187, 162, 186}, {89, 187, 163, 132}, {89, 187, 163, 134}, {89, 187, 163, 136}, {89, 187, 163, 195}, {89, 187, 163, 197}, {89, 187, 163, 202}, {89, 187, 163, 207}, {89, 187, 163, 210}, {89, 187, 163, 217}, {156, 146, 56, 130}, {156, 146, 56, 135}, {156, 146, 56, 137}}},
-		{Region: "Singapore Hong Kong", IPs: []net.IP{{206, 189, 83, 129}}},
-		{Region: "Singapore Netherlands", IPs: []net.IP{{104, 248, 148, 18}}},
-		{Region: "Singapore in", IPs: []net.IP{{128, 199, 193, 35}}},
+		{Region: "Singapore Hong Kong", IPs: []net.IP{{206, 189, 83, 129}}, MultiHop: &models.MultiHop{EntryCountry: "Singapore", ExitCountry: "Hong Kong"}},
+		{Region: "Singapore Netherlands", IPs: []net.IP{{104, 248, 148, 18}}, MultiHop: &models.MultiHop{EntryCountry: "Singapore", ExitCountry: "Netherlands"}},
+		{Region: "Singapore in", IPs: []net.IP{{128, 199, 193, 35}}, MultiHop: &models.MultiHop{EntryCountry: "Singapore", ExitCountry: "India"}},
 		{Region: "Singapore mp001", IPs: []net.IP{{206, 189, 94, 229}}},
 		{Region: "Singapore st001", IPs: []net.IP{{217, 138, 201, 91}}},
 		{Region: "Singapore st002", IPs: []net.IP{{217, 138, 201, 93}}},
@@ -141,8 +141,8 @@ func SurfsharkServers() []models.SurfsharkServer {
 		{Region: "Taiwan", IPs: []net.IP{{2, 58, 241, 3}, {2, 58, 241, 5}, {2, 58, 241, 27}, {2, 58, 241, 29}, {2, 58, 241, 147}, {2, 58, 241, 149}, {2, 58, 242, 43}, {2, 58, 242, 53}, {2, 58, 242, 133}, {2, 58, 242, 157}, {103, 152, 151, 3}, {103, 152, 151, 5}, {103, 152, 151, 19}, {103, 152, 151, 67}, {103, 152, 151, 69}, {103, 152, 151, 83}, {103, 152, 151, 85}}},
 		{Region: "Thailand", IPs: []net.IP{{27, 131, 138, 174}, {27, 131, 138, 176}}},
 		{Region: "Turkey Istanbul", IPs: []net.IP{{107, 150, 95, 147}, {107, 150, 95, 155}, {107, 150, 95, 157}, {107, 150, 95, 163}, {107, 150, 95, 165}}},
-		{Region: "UK France", IPs: []net.IP{{188, 166, 168, 247}}},
-		{Region: "UK Germany", IPs: []net.IP{{45, 77, 58, 16}}},
+		{Region: "UK France", IPs: []net.IP{{188, 166, 168, 247}}, MultiHop: &models.MultiHop{EntryCountry: "United Kingdom", ExitCountry: "France"}},
+		{Region: "UK Germany", IPs: []net.IP{{45, 77, 58, 16}}, MultiHop: &models.MultiHop{EntryCountry: "United Kingdom", ExitCountry: "Germany"}},
 		{Region: "UK Glasgow", IPs: []net.IP{{185, 108, 105, 3}, {185, 108, 105, 13}, {185, 108, 105, 18}, {185, 108, 105, 22}, {185, 108, 105, 35}, {185, 108, 105, 55}, {185, 108, 105, 145}, {185, 108, 105, 151}, {185, 108, 105, 153}, {185, 108, 105, 174}, {185, 108, 105, 184}, {185, 108, 105, 209}, {185, 108, 105, 229}, {185, 108, 105, 239}, {185, 108, 105, 241}, {185, 108, 105, 243}}},
 		{Region: "UK London", IPs: []net.IP{{5, 226, 139, 216}, {81, 19, 214, 32}, {81, 19, 214, 36}, {81, 19, 214, 37}, {81, 19, 214, 65}, {86, 106, 157, 160}, {86, 106, 157, 206}, {89, 34, 96, 86}, {89, 34, 99, 87}, {178, 239, 166, 231}, {178, 239, 172, 57}, {185, 38, 148, 232}, {185, 44, 76, 164}, {185, 44, 77, 48}, {185, 44, 77, 60}, {185, 44, 77, 72}, {185, 44, 77, 123}, {185, 44, 78, 155}, {185, 44, 78, 174}, {185, 134, 22, 251}, {185, 134, 22, 253}, {185, 141, 206, 186}, {185, 141, 206, 218}, {185, 141, 206, 250}, {188, 240, 71, 163}, {188, 240, 71, 231}, {195, 140, 215, 100}, {195, 206, 169, 203}}},
 		{Region: "UK London mp001", IPs: []net.IP{{206, 189, 119, 92}}},
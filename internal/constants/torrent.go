@@ -0,0 +1,16 @@
+package constants
+
+const (
+	// QBittorrent is the qBittorrent Web API.
+	QBittorrent = "qbittorrent"
+	// Transmission is the Transmission RPC API.
+	Transmission = "transmission"
+	// Deluge is the Deluge Web JSON-RPC API.
+	Deluge = "deluge"
+)
+
+// TorrentClients returns the torrent clients supported
+// to update the listening port on.
+func TorrentClients() []string {
+	return []string{QBittorrent, Transmission, Deluge}
+}
@@ -35,3 +35,22 @@ const (
 	// UDP is a network protocol (unreliable and faster than TCP).
 	UDP string = "udp"
 )
+
+const (
+	// OpenVPN is a VPN type.
+	OpenVPN = "openvpn"
+	// Wireguard is a VPN type.
+	Wireguard = "wireguard"
+)
+
+const (
+	// Stunnel is an obfuscation method wrapping the OpenVPN TCP connection in TLS.
+	Stunnel = "stunnel"
+)
+
+const (
+	// HTTPProxyType is an upstream proxy type to connect to the VPN server.
+	HTTPProxyType = "http"
+	// SOCKSProxyType is an upstream proxy type to connect to the VPN server.
+	SOCKSProxyType = "socks"
+)
@@ -0,0 +1,15 @@
+package constants
+
+import "net"
+
+const (
+	// WireguardDevice is the default Wireguard network interface name.
+	WireguardDevice = "wg0"
+	// WireguardDefaultPort is the default Wireguard endpoint UDP port.
+	WireguardDefaultPort uint16 = 51820
+)
+
+// NordlynxAddress returns the address assigned to every NordLynx client.
+func NordlynxAddress() net.IPNet {
+	return net.IPNet{IP: net.IPv4(10, 5, 0, 2), Mask: net.CIDRMask(32, 32)} //nolint:gomnd
+}
@@ -0,0 +1,320 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+const (
+	adsHostnamesURL          = "https://raw.githubusercontent.com/qdm12/files/master/ads-hostnames.updated"
+	adsIPsURL                = "https://raw.githubusercontent.com/qdm12/files/master/ads-ips.updated"
+	maliciousHostnamesURL    = "https://raw.githubusercontent.com/qdm12/files/master/malicious-hostnames.updated"
+	maliciousIPsURL          = "https://raw.githubusercontent.com/qdm12/files/master/malicious-ips.updated"
+	surveillanceHostnamesURL = "https://raw.githubusercontent.com/qdm12/files/master/surveillance-hostnames.updated"
+	surveillanceIPsURL       = "https://raw.githubusercontent.com/qdm12/files/master/surveillance-ips.updated"
+)
+
+type categoryURLs struct {
+	hostnames string
+	ips       string
+}
+
+// blockCategoriesURLs returns the URLs of the hostnames
+// and IP addresses lists of each block category.
+func blockCategoriesURLs() map[string]categoryURLs {
+	return map[string]categoryURLs{
+		constants.DNSBlockMalicious:    {hostnames: maliciousHostnamesURL, ips: maliciousIPsURL},
+		constants.DNSBlockSurveillance: {hostnames: surveillanceHostnamesURL, ips: surveillanceIPsURL},
+		constants.DNSBlockAds:          {hostnames: adsHostnamesURL, ips: adsIPsURL},
+	}
+}
+
+// blocklist contains the hostnames and IP addresses to block.
+// Subdomains of a blocked hostname are blocked as well, unless
+// they are a subdomain of an allowed hostname.
+type blocklist struct {
+	hostnames map[string]struct{}
+	allowed   map[string]struct{}
+	subnets   []net.IPNet
+	// private are the IP address ranges refused in responses to
+	// prevent DNS rebinding, except for the rebindingAllowed
+	// hostnames and their subdomains.
+	private          []net.IPNet
+	rebindingAllowed map[string]struct{}
+}
+
+func (b *blocklist) hostnameBlocked(hostname string) (blocked bool) {
+	if matchDomain(b.allowed, hostname) {
+		return false
+	}
+	return matchDomain(b.hostnames, hostname)
+}
+
+func (b *blocklist) ipBlocked(ip net.IP) (blocked bool) {
+	return subnetsContain(b.subnets, ip)
+}
+
+func (b *blocklist) rebinding(hostname string, ip net.IP) (rebinding bool) {
+	return subnetsContain(b.private, ip) && !matchDomain(b.rebindingAllowed, hostname)
+}
+
+func subnetsContain(subnets []net.IPNet, ip net.IP) (contain bool) {
+	for _, subnet := range subnets {
+		if subnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchDomain returns true if the hostname or one of its
+// parent domains is in the domains given.
+func matchDomain(domains map[string]struct{}, hostname string) (match bool) {
+	for {
+		if _, ok := domains[hostname]; ok {
+			return true
+		}
+		i := strings.IndexByte(hostname, '.')
+		if i == -1 {
+			return false
+		}
+		hostname = hostname[i+1:]
+	}
+}
+
+// blockFilter holds the block list used by the DNS server, which
+// can be updated while the server is running.
+type blockFilter struct {
+	blocklist blocklist
+	mutex     sync.RWMutex
+}
+
+func (f *blockFilter) set(b blocklist) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.blocklist = b
+}
+
+func (f *blockFilter) hostnameBlocked(hostname string) (blocked bool) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.blocklist.hostnameBlocked(hostname)
+}
+
+// responseBlocked returns true if one of the A or AAAA records
+// of the response has a blocked IP address.
+func (f *blockFilter) responseBlocked(records []record) (blocked bool) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	for _, ip := range recordsIPs(records) {
+		if f.blocklist.ipBlocked(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// responseRebinding returns true if one of the A or AAAA records of
+// the response for the hostname has a private IP address.
+func (f *blockFilter) responseRebinding(hostname string, records []record) (rebinding bool) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	for _, ip := range recordsIPs(records) {
+		if f.blocklist.rebinding(hostname, ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func recordsIPs(records []record) (ips []net.IP) {
+	for _, r := range records {
+		if (r.rrtype == typeA && len(r.rdata) == net.IPv4len) ||
+			(r.rrtype == typeAAAA && len(r.rdata) == net.IPv6len) {
+			ips = append(ips, net.IP(r.rdata))
+		}
+	}
+	return ips
+}
+
+// listURLs returns the URLs of the hostnames block lists, of the IP
+// addresses block lists and of the allow lists enabled in the settings.
+func listURLs(settings configuration.DNS) (hostnames, ips, allowed []string) {
+	categoriesURLs := blockCategoriesURLs()
+	for _, category := range settings.BlockCategories {
+		urls, ok := categoriesURLs[category]
+		if !ok { // already validated by the configuration
+			continue
+		}
+		hostnames = append(hostnames, urls.hostnames)
+		ips = append(ips, urls.ips)
+	}
+	hostnames = append(hostnames, settings.BlocklistURLs...)
+	allowed = settings.AllowlistURLs
+	return hostnames, ips, allowed
+}
+
+// buildBlocklist builds the block list from the lines of the lists
+// enabled in the settings, merged with the unblocked hostnames and
+// the rebinding protection settings. Lists missing from the lines
+// map are skipped.
+func buildBlocklist(settings configuration.DNS, lists map[string][]string) (b blocklist) {
+	hostnamesURLs, ipsURLs, allowedURLs := listURLs(settings)
+
+	b.hostnames = make(map[string]struct{})
+	for _, url := range hostnamesURLs {
+		for _, line := range lists[url] {
+			for _, hostname := range parseHostnamesLine(line) {
+				b.hostnames[hostname] = struct{}{}
+			}
+		}
+	}
+
+	for _, url := range ipsURLs {
+		for _, line := range lists[url] {
+			if subnet, ok := parseIPNet(line); ok {
+				b.subnets = append(b.subnets, subnet)
+			}
+		}
+	}
+
+	b.allowed = make(map[string]struct{}, len(settings.AllowedHostnames))
+	for _, hostname := range settings.AllowedHostnames {
+		b.allowed[strings.ToLower(hostname)] = struct{}{}
+	}
+	for _, url := range allowedURLs {
+		for _, line := range lists[url] {
+			for _, hostname := range parseHostnamesLine(line) {
+				b.allowed[hostname] = struct{}{}
+			}
+		}
+	}
+
+	if settings.RebindingProtection {
+		b.private = settings.PrivateAddresses
+		b.rebindingAllowed = make(map[string]struct{}, len(settings.RebindingAllowedHostnames))
+		for _, hostname := range settings.RebindingAllowedHostnames {
+			b.rebindingAllowed[strings.ToLower(hostname)] = struct{}{}
+		}
+	}
+
+	return b
+}
+
+// parseHostnamesLine returns the hostnames of a line in the hosts file
+// format, such as "0.0.0.0 ads.example.com", or of a line containing
+// only a hostname. Comments and local hostnames are ignored.
+func parseHostnamesLine(line string) (hostnames []string) {
+	if i := strings.IndexByte(line, '#'); i != -1 {
+		line = line[:i]
+	}
+	fields := strings.Fields(strings.ToLower(line))
+	switch {
+	case len(fields) == 0:
+		return nil
+	case net.ParseIP(fields[0]) != nil:
+		fields = fields[1:]
+	case len(fields) > 1:
+		return nil // not a supported format
+	}
+
+	hostnames = make([]string, 0, len(fields))
+	for _, field := range fields {
+		hostname := strings.TrimSuffix(field, ".")
+		switch hostname {
+		case "", "localhost", "localhost.localdomain", "local",
+			"broadcasthost", "ip6-localhost", "ip6-loopback", "0.0.0.0":
+			continue
+		}
+		hostnames = append(hostnames, hostname)
+	}
+	return hostnames
+}
+
+// fetchLists downloads the lists at the URLs given, and returns the lines
+// of each list downloaded and the download error of each other list.
+func fetchLists(ctx context.Context, client *http.Client, urls []string) (
+	lists map[string][]string, errs map[string]error) {
+	type result struct {
+		url   string
+		lines []string
+		err   error
+	}
+	results := make(chan result)
+	for _, url := range urls {
+		go func(url string) {
+			lines, err := getList(ctx, client, url)
+			results <- result{url: url, lines: lines, err: err}
+		}(url)
+	}
+
+	lists = make(map[string][]string, len(urls))
+	errs = make(map[string]error)
+	for range urls {
+		result := <-results
+		if result.err != nil {
+			errs[result.url] = result.err
+			continue
+		}
+		lists[result.url] = result.lines
+	}
+	return lists, errs
+}
+
+// parseIPNet parses an IP address or CIDR range.
+func parseIPNet(s string) (subnet net.IPNet, ok bool) {
+	if ip := net.ParseIP(s); ip != nil {
+		bits := 8 * net.IPv6len
+		if ipv4 := ip.To4(); ipv4 != nil {
+			ip, bits = ipv4, 8*net.IPv4len
+		}
+		return net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, true
+	}
+	_, ipNet, err := net.ParseCIDR(s)
+	if err != nil {
+		return subnet, false
+	}
+	return *ipNet, true
+}
+
+var ErrBadStatusCode = errors.New("bad HTTP status code")
+
+func getList(ctx context.Context, client *http.Client, url string) (lines []string, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s for %s", ErrBadStatusCode, response.Status, url)
+	}
+
+	b, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines, response.Body.Close()
+}
@@ -0,0 +1,124 @@
+package dns
+
+import (
+	"net"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_blocklist_hostnameBlocked(t *testing.T) {
+	t.Parallel()
+	b := blocklist{
+		hostnames: map[string]struct{}{"ads.com": {}, "tracker.net": {}},
+		allowed:   map[string]struct{}{"cdn.ads.com": {}},
+	}
+	testCases := map[string]bool{
+		"ads.com":            true,
+		"x.ads.com":          true,
+		"cdn.ads.com":        false,
+		"images.cdn.ads.com": false,
+		"myads.com":          false,
+		"tracker.net":        true,
+		"github.com":         false,
+	}
+	for hostname, blocked := range testCases {
+		hostname, blocked := hostname, blocked
+		t.Run(hostname, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, blocked, b.hostnameBlocked(hostname))
+		})
+	}
+}
+
+func Test_blockFilter_responseBlocked(t *testing.T) {
+	t.Parallel()
+	filter := &blockFilter{}
+	filter.set(blocklist{
+		subnets: []net.IPNet{{IP: net.IP{93, 184, 0, 0}, Mask: net.IPv4Mask(255, 255, 0, 0)}},
+	})
+
+	response := exampleResponse()
+	_, questionEnd, _ := parseQuestion(response)
+	records, _ := parseRecords(response, questionEnd)
+	assert.True(t, filter.responseBlocked(records))
+
+	filter.set(blocklist{})
+	assert.False(t, filter.responseBlocked(records))
+}
+
+func Test_blockFilter_responseRebinding(t *testing.T) {
+	t.Parallel()
+	filter := &blockFilter{}
+	filter.set(blocklist{
+		private:          []net.IPNet{{IP: net.IP{93, 184, 0, 0}, Mask: net.IPv4Mask(255, 255, 0, 0)}},
+		rebindingAllowed: map[string]struct{}{"lan.example.com": {}},
+	})
+
+	response := exampleResponse()
+	_, questionEnd, _ := parseQuestion(response)
+	records, _ := parseRecords(response, questionEnd)
+	assert.True(t, filter.responseRebinding("example.com", records))
+	assert.False(t, filter.responseRebinding("nas.lan.example.com", records))
+	assert.False(t, filter.responseBlocked(records))
+}
+
+func Test_parseHostnamesLine(t *testing.T) {
+	t.Parallel()
+	testCases := map[string][]string{
+		"":                            nil,
+		"# comment":                   nil,
+		"Ads.example.com":             {"ads.example.com"},
+		"ads.example.com. # comment":  {"ads.example.com"},
+		"0.0.0.0 a.com b.com":         {"a.com", "b.com"},
+		"127.0.0.1 localhost":         {},
+		"0.0.0.0 0.0.0.0":             {},
+		"not a hostnames line format": nil,
+	}
+	for line, hostnames := range testCases {
+		line, hostnames := line, hostnames
+		t.Run(line, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, hostnames, parseHostnamesLine(line))
+		})
+	}
+}
+
+func Test_buildBlocklist(t *testing.T) {
+	t.Parallel()
+	settings := configuration.DNS{
+		BlockCategories:  []string{"ads"},
+		AllowedHostnames: []string{"Allowed.com"},
+		BlocklistURLs:    []string{"https://custom/block"},
+		AllowlistURLs:    []string{"https://custom/allow"},
+		PrivateAddresses: []net.IPNet{
+			{IP: net.IP{192, 168, 0, 0}, Mask: net.CIDRMask(16, 32)},
+		},
+		RebindingProtection:       true,
+		RebindingAllowedHostnames: []string{"NAS.lan"},
+	}
+	lists := map[string][]string{
+		adsHostnamesURL:        {"ads.com"},
+		adsIPsURL:              {"1.2.3.4", "10.0.0.0/8", "invalid"},
+		"https://custom/block": {"0.0.0.0 tracker.net"},
+		"https://custom/allow": {"cdn.ads.com"},
+		maliciousHostnamesURL:  {"malicious.com"}, // not enabled
+	}
+
+	b := buildBlocklist(settings, lists)
+
+	expected := blocklist{
+		hostnames: map[string]struct{}{"ads.com": {}, "tracker.net": {}},
+		allowed:   map[string]struct{}{"allowed.com": {}, "cdn.ads.com": {}},
+		subnets: []net.IPNet{
+			{IP: net.IP{1, 2, 3, 4}, Mask: net.CIDRMask(32, 32)},
+			{IP: net.IP{10, 0, 0, 0}, Mask: net.CIDRMask(8, 32)},
+		},
+		private: []net.IPNet{
+			{IP: net.IP{192, 168, 0, 0}, Mask: net.CIDRMask(16, 32)},
+		},
+		rebindingAllowed: map[string]struct{}{"nas.lan": {}},
+	}
+	assert.Equal(t, expected, b)
+}
@@ -0,0 +1,140 @@
+package dns
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+const (
+	maxCacheEntries = 10000
+	// maxCacheTTL caps how long a response is cached for.
+	maxCacheTTL = 24 * time.Hour
+)
+
+// cache caches DNS responses for the minimum TTL of their records,
+// and returns them with their TTLs decreased by the time elapsed.
+type cache struct {
+	entries    map[question]cacheEntry
+	maxEntries int
+	mutex      sync.Mutex
+	timeNow    func() time.Time
+}
+
+type cacheEntry struct {
+	response []byte
+	records  []record
+	stored   time.Time
+	expiry   time.Time
+}
+
+func newCache(maxEntries int, timeNow func() time.Time) *cache {
+	return &cache{
+		entries:    make(map[question]cacheEntry),
+		maxEntries: maxEntries,
+		timeNow:    timeNow,
+	}
+}
+
+// get returns a copy of the response cached for the question, with the
+// TTLs decreased by the time elapsed since it was cached.
+// The response ID has to be set by the caller.
+func (c *cache) get(q question) (response []byte, ok bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.entries[q]
+	if !ok {
+		return nil, false
+	}
+
+	now := c.timeNow()
+	if !now.Before(entry.expiry) {
+		delete(c.entries, q)
+		return nil, false
+	}
+
+	elapsed := uint32(now.Sub(entry.stored) / time.Second)
+	response = make([]byte, len(entry.response))
+	copy(response, entry.response)
+	for _, r := range entry.records {
+		if r.rrtype == typeOPT { // TTL field holds EDNS flags
+			continue
+		}
+		binary.BigEndian.PutUint32(response[r.ttlOffset:], r.ttl-elapsed)
+	}
+	return response, true
+}
+
+// set caches the response for the question, for the minimum TTL of its
+// records. The response is not cached if it has no record.
+func (c *cache) set(q question, response []byte, records []record) {
+	ttl, ok := minTTL(records)
+	if !ok || ttl == 0 {
+		return
+	}
+	expiration := time.Duration(ttl) * time.Second
+	if expiration > maxCacheTTL {
+		expiration = maxCacheTTL
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.timeNow()
+	if len(c.entries) >= c.maxEntries {
+		c.removeExpired(now)
+	}
+	if len(c.entries) >= c.maxEntries {
+		for key := range c.entries { // remove a random entry
+			delete(c.entries, key)
+			break
+		}
+	}
+
+	entry := cacheEntry{
+		response: make([]byte, len(response)),
+		records:  make([]record, len(records)),
+		stored:   now,
+		expiry:   now.Add(expiration),
+	}
+	copy(entry.response, response)
+	for i, r := range records {
+		r.rdata = nil // do not keep a reference to the response given
+		entry.records[i] = r
+	}
+	c.entries[q] = entry
+}
+
+// flush removes all the cached responses.
+func (c *cache) flush() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries = make(map[question]cacheEntry)
+}
+
+func (c *cache) size() (entries int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.entries)
+}
+
+func (c *cache) removeExpired(now time.Time) {
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiry) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func minTTL(records []record) (ttl uint32, ok bool) {
+	for _, r := range records {
+		if r.rrtype == typeOPT {
+			continue
+		}
+		if !ok || r.ttl < ttl {
+			ttl, ok = r.ttl, true
+		}
+	}
+	return ttl, ok
+}
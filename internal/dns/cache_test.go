@@ -0,0 +1,40 @@
+package dns
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_cache(t *testing.T) {
+	t.Parallel()
+	now := time.Unix(1000, 0)
+	c := newCache(1, func() time.Time { return now })
+
+	response := exampleResponse()
+	q, questionEnd, err := parseQuestion(response)
+	require.NoError(t, err)
+	records, err := parseRecords(response, questionEnd)
+	require.NoError(t, err)
+
+	c.set(q, response, records)
+
+	now = now.Add(100 * time.Second)
+	cached, ok := c.get(q)
+	require.True(t, ok)
+	assert.Equal(t, uint32(200), binary.BigEndian.Uint32(cached[records[0].ttlOffset:]))
+	assert.Equal(t, exampleResponse(), response)
+
+	now = now.Add(200 * time.Second)
+	_, ok = c.get(q)
+	assert.False(t, ok)
+	assert.Empty(t, c.entries)
+
+	c.set(q, response, records)
+	assert.Equal(t, 1, c.size())
+	c.flush()
+	assert.Zero(t, c.size())
+}
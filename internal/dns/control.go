@@ -0,0 +1,115 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+var (
+	ErrNotRunning           = errors.New("DNS server is not running")
+	ErrBlockCategoryUnknown = errors.New("block category is unknown")
+)
+
+// Restart stops and starts the DNS server again if it is running.
+func (l *looper) Restart() (outcome string, err error) {
+	if l.GetStatus() != constants.Running {
+		return "", ErrNotRunning
+	}
+	if _, err := l.SetStatus(constants.Stopped); err != nil {
+		return "", err
+	}
+	return l.SetStatus(constants.Running)
+}
+
+// ReloadBlocklists downloads the block and allow lists again
+// and updates the block list used by the DNS server.
+func (l *looper) ReloadBlocklists(ctx context.Context) (outcome string, err error) {
+	if l.GetStatus() != constants.Running {
+		return "", ErrNotRunning
+	}
+	l.updateBlocklist(ctx)
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return "block lists reloaded", nil
+}
+
+// SetBlockCategories sets the block categories and updates the block
+// list used by the DNS server if it is running, without restarting it.
+func (l *looper) SetBlockCategories(ctx context.Context, categories []string) (
+	outcome string, err error) {
+	categories, err = checkBlockCategories(categories)
+	if err != nil {
+		return "", err
+	}
+
+	l.state.settingsMu.Lock()
+	l.state.settings.BlockCategories = categories
+	l.state.settingsMu.Unlock()
+
+	if l.GetStatus() != constants.Running {
+		return "block categories set", nil
+	}
+	l.updateBlocklist(ctx)
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return "block categories set and block lists reloaded", nil
+}
+
+// SetBlocklists sets the block categories, the allowed hostnames and
+// the block and allow list URLs from the settings given, and updates
+// the block list if the DNS server is running.
+func (l *looper) SetBlocklists(ctx context.Context, settings configuration.DNS) (
+	outcome string, err error) {
+	categories, err := checkBlockCategories(settings.BlockCategories)
+	if err != nil {
+		return "", err
+	}
+
+	l.state.settingsMu.Lock()
+	l.state.settings.BlockCategories = categories
+	l.state.settings.AllowedHostnames = settings.AllowedHostnames
+	l.state.settings.BlocklistURLs = settings.BlocklistURLs
+	l.state.settings.AllowlistURLs = settings.AllowlistURLs
+	l.state.settingsMu.Unlock()
+
+	if l.GetStatus() != constants.Running {
+		return "block lists settings set", nil
+	}
+	l.updateBlocklist(ctx)
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return "block lists settings set and block lists reloaded", nil
+}
+
+// checkBlockCategories returns the categories given lowercased and
+// without duplicates, or an error if one of them is unknown.
+func checkBlockCategories(categories []string) (checked []string, err error) {
+	known := make(map[string]struct{})
+	for _, category := range constants.DNSBlockCategories() {
+		known[category] = struct{}{}
+	}
+
+	seen := make(map[string]struct{}, len(categories))
+	checked = make([]string, 0, len(categories))
+	for _, category := range categories {
+		category = strings.ToLower(category)
+		if _, ok := known[category]; !ok {
+			return nil, fmt.Errorf("%w: %s: possible values are: %s", ErrBlockCategoryUnknown,
+				category, strings.Join(constants.DNSBlockCategories(), ", "))
+		}
+		if _, ok := seen[category]; ok {
+			continue
+		}
+		seen[category] = struct{}{}
+		checked = append(checked, category)
+	}
+	return checked, nil
+}
@@ -0,0 +1,42 @@
+package dns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_checkBlockCategories(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		categories []string
+		checked    []string
+		err        error
+	}{
+		"empty": {
+			checked: []string{},
+		},
+		"lowercased without duplicates": {
+			categories: []string{"Ads", "malicious", "ads"},
+			checked:    []string{"ads", "malicious"},
+		},
+		"unknown category": {
+			categories: []string{"ads", "gambling"},
+			err:        ErrBlockCategoryUnknown,
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			checked, err := checkBlockCategories(testCase.categories)
+			if testCase.err != nil {
+				assert.ErrorIs(t, err, testCase.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.checked, checked)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package dns
+
+import (
+	"encoding/binary"
+
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+const (
+	adFlag = 0x20 // authentic data, in the 4th byte of the header
+	cdFlag = 0x10 // checking disabled, in the 4th byte of the header
+
+	// ednsUDPLength is the UDP payload size advertised by
+	// the EDNS record added to queries without one.
+	ednsUDPLength = 1232
+	// optionExtendedError is the EDNS option code of
+	// extended DNS errors, see RFC 8914.
+	optionExtendedError = 15
+)
+
+// dnssecQuery returns the query to send to the upstream servers for the
+// DNSSEC mode given, and whether an EDNS record was added to the query
+// to receive the extended DNS errors reporting DNSSEC failures.
+// The query given is not modified.
+func dnssecQuery(query []byte, questionEnd int, mode string) (
+	upstreamQuery []byte, ednsAdded bool) {
+	upstreamQuery = make([]byte, len(query))
+	copy(upstreamQuery, query)
+
+	if mode == constants.DNSSECOff {
+		upstreamQuery[3] |= cdFlag
+		return upstreamQuery, false
+	}
+
+	// Request the upstream validating resolver to report if
+	// the response is authenticated, see RFC 6840 section 5.7
+	upstreamQuery[3] |= adFlag
+
+	records, err := parseRecords(query, questionEnd)
+	if err != nil {
+		return upstreamQuery, false
+	}
+	for _, r := range records {
+		if r.rrtype == typeOPT {
+			return upstreamQuery, false
+		}
+	}
+
+	// root name, type, class as UDP payload size, TTL and no data
+	const optLength = 11
+	opt := make([]byte, optLength)
+	binary.BigEndian.PutUint16(opt[1:], typeOPT)
+	binary.BigEndian.PutUint16(opt[3:], ednsUDPLength)
+	upstreamQuery = append(upstreamQuery, opt...)
+	additionalCount := binary.BigEndian.Uint16(upstreamQuery[10:])
+	binary.BigEndian.PutUint16(upstreamQuery[10:], additionalCount+1)
+	return upstreamQuery, true
+}
+
+// withCheckingDisabled returns a copy of the query with the
+// checking disabled flag set, to get a response not validated.
+func withCheckingDisabled(query []byte) (cdQuery []byte) {
+	cdQuery = make([]byte, len(query))
+	copy(cdQuery, query)
+	cdQuery[3] |= cdFlag
+	return cdQuery
+}
+
+// dnssecBogus returns true if the records of a server failure response
+// contain an extended DNS error reporting a DNSSEC validation failure.
+func dnssecBogus(response []byte, records []record) (bogus bool) {
+	if responseCode(response) != rcodeServerFailure {
+		return false
+	}
+	for _, r := range records {
+		if r.rrtype != typeOPT {
+			continue
+		}
+		for options := r.rdata; len(options) >= 4; {
+			code := binary.BigEndian.Uint16(options)
+			length := int(binary.BigEndian.Uint16(options[2:]))
+			options = options[4:]
+			if length > len(options) {
+				break
+			}
+			const infoCodeLength = 2
+			if code == optionExtendedError && length >= infoCodeLength {
+				// DNSSEC Bogus, Signature Expired, Signature Not Yet Valid,
+				// DNSKEY Missing, RRSIGs Missing, No Zone Key Bit Set
+				// and NSEC Missing, see RFC 8914 section 4.
+				const firstBogusCode, lastBogusCode = 6, 12
+				infoCode := binary.BigEndian.Uint16(options)
+				if infoCode >= firstBogusCode && infoCode <= lastBogusCode {
+					return true
+				}
+			}
+			options = options[length:]
+		}
+	}
+	return false
+}
+
+// removeOPT removes the EDNS record of the response, if it is its last
+// record. It returns the response and its records without this record.
+func removeOPT(response []byte, records []record) ([]byte, []record) {
+	if len(records) == 0 {
+		return response, records
+	}
+	last := records[len(records)-1]
+	const rdataOffset = 6 // TTL and data length
+	if last.rrtype != typeOPT || last.ttlOffset+rdataOffset+len(last.rdata) != len(response) {
+		return response, records
+	}
+	const nameTypeClassLength = 5 // root name, type and class
+	response = response[:last.ttlOffset-nameTypeClassLength]
+	additionalCount := binary.BigEndian.Uint16(response[10:])
+	binary.BigEndian.PutUint16(response[10:], additionalCount-1)
+	return response, records[:len(records)-1]
+}
@@ -0,0 +1,52 @@
+package dns
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_dnssecQuery(t *testing.T) {
+	t.Parallel()
+	query := exampleQuery()
+
+	upstreamQuery, ednsAdded := dnssecQuery(query, len(query), constants.DNSSECOff)
+	assert.False(t, ednsAdded)
+	assert.Equal(t, byte(cdFlag), upstreamQuery[3]&cdFlag)
+
+	upstreamQuery, ednsAdded = dnssecQuery(query, len(query), constants.DNSSECUpstream)
+	require.True(t, ednsAdded)
+	assert.Equal(t, exampleQuery(), query)
+	assert.Equal(t, byte(adFlag), upstreamQuery[3]&adFlag)
+	records, err := parseRecords(upstreamQuery, len(query))
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, uint16(typeOPT), records[0].rrtype)
+	assert.Equal(t, uint16(ednsUDPLength), records[0].class)
+
+	// the query already has an EDNS record
+	_, ednsAdded = dnssecQuery(upstreamQuery, len(query), constants.DNSSECUpstream)
+	assert.False(t, ednsAdded)
+}
+
+func Test_dnssecBogus_removeOPT(t *testing.T) {
+	t.Parallel()
+	query := exampleQuery()
+	response := emptyResponse(query, len(query), rcodeServerFailure)
+	response[11] = 1 // additional records count
+	response = append(response,
+		0, 0, typeOPT, 0x04, 0xd0, 0, 0, 0, 0, // OPT record
+		0, 6, 0, optionExtendedError, 0, 2, 0, 6, // DNSSEC Bogus extended error
+	)
+	records, err := parseRecords(response, len(query))
+	require.NoError(t, err)
+
+	assert.True(t, dnssecBogus(response, records))
+
+	response, records = removeOPT(response, records)
+	assert.Equal(t, emptyResponse(query, len(query), rcodeServerFailure), response)
+	assert.Empty(t, records)
+	assert.False(t, dnssecBogus(response, records))
+}
@@ -0,0 +1,132 @@
+package dns
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+const (
+	dohContentType = "application/dns-message"
+	// dohIdleTimeout is the time after which an idle
+	// connection to a DNS over HTTPS server is closed.
+	dohIdleTimeout   = 30 * time.Second
+	maxMessageLength = 65535
+)
+
+// dohServer is a DNS over HTTPS server. The hostname of its URL
+// is not resolved but dialed using its pinned IP addresses, since
+// the DNS server itself would resolve it.
+type dohServer struct {
+	url    string
+	client *http.Client
+}
+
+// newDoHServers returns the DNS over HTTPS servers given,
+// sharing the same HTTP client.
+func newDoHServers(servers []models.DoHServer, ipv6 bool) (upstreams []upstream) {
+	hostIPs := make(map[string][]net.IP, len(servers))
+	for _, server := range servers {
+		u, err := url.Parse(server.URL)
+		if err != nil { // already validated by the configuration
+			continue
+		}
+		for _, ip := range server.IPs {
+			if ip.To4() != nil || ipv6 {
+				hostIPs[u.Hostname()] = append(hostIPs[u.Hostname()], ip)
+			}
+		}
+	}
+
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	transport := &http.Transport{
+		ForceAttemptHTTP2:   true,
+		MaxIdleConnsPerHost: maxIdleConnections,
+		IdleConnTimeout:     dohIdleTimeout,
+		TLSHandshakeTimeout: dialTimeout,
+		DialContext: func(ctx context.Context, network, address string) (conn net.Conn, err error) {
+			host, port, err := net.SplitHostPort(address)
+			if err != nil {
+				return nil, err
+			}
+			ips, ok := hostIPs[host]
+			if !ok {
+				return nil, fmt.Errorf("%w: for hostname %s", ErrNoUpstreamServer, host)
+			}
+			for _, ip := range ips {
+				conn, err = dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+				if err == nil {
+					return conn, nil
+				}
+			}
+			return nil, err
+		},
+	}
+	client := &http.Client{Transport: transport}
+
+	upstreams = make([]upstream, len(servers))
+	for i, server := range servers {
+		upstreams[i] = &dohServer{
+			url:    server.URL,
+			client: client,
+		}
+	}
+	return upstreams
+}
+
+func (d *dohServer) String() string {
+	return d.url
+}
+
+func (d *dohServer) exchange(ctx context.Context, query []byte) (response []byte, err error) {
+	// The ID is set to 0 as recommended by RFC 8484 section 4.1
+	body := make([]byte, len(query))
+	copy(body, query)
+	body[0], body[1] = 0, 0
+
+	response, err = d.post(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	response[0], response[1] = query[0], query[1]
+	return response, nil
+}
+
+func (d *dohServer) post(ctx context.Context, body []byte) (response []byte, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", dohContentType)
+	request.Header.Set("Accept", dohContentType)
+
+	httpResponse, err := d.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s from %s", ErrBadStatusCode, httpResponse.Status, d.url)
+	}
+
+	response, err = io.ReadAll(io.LimitReader(httpResponse.Body, maxMessageLength))
+	if err != nil {
+		return nil, err
+	} else if len(response) < headerLength {
+		return nil, fmt.Errorf("%w: from %s: %s", ErrResponseInvalid, d.url, ErrMessageTooShort)
+	}
+
+	return response, httpResponse.Body.Close()
+}
+
+func (d *dohServer) close() {
+	d.client.CloseIdleConnections()
+}
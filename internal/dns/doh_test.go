@@ -0,0 +1,140 @@
+package dns
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dohHandler replies with the status code and body given, and checks
+// the DNS over HTTPS request received.
+func dohHandler(t *testing.T, statusCode int, body []byte) http.HandlerFunc {
+	t.Helper()
+	return func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, dohContentType, r.Header.Get("Content-Type"))
+		assert.Equal(t, dohContentType, r.Header.Get("Accept"))
+		query, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		// The ID is set to 0 as recommended by RFC 8484 section 4.1
+		expectedQuery := exampleQuery()
+		expectedQuery[0], expectedQuery[1] = 0, 0
+		assert.Equal(t, expectedQuery, query)
+
+		w.WriteHeader(statusCode)
+		_, _ = w.Write(body)
+	}
+}
+
+func Test_dohServer_exchange(t *testing.T) {
+	t.Parallel()
+
+	responseWithoutID := exampleResponse()
+	responseWithoutID[0], responseWithoutID[1] = 0, 0
+
+	testCases := map[string]struct {
+		statusCode int
+		body       []byte
+		response   []byte
+		err        error
+		errMessage string
+	}{
+		"success": {
+			statusCode: http.StatusOK,
+			body:       responseWithoutID,
+			response:   exampleResponse(),
+		},
+		"bad status code": {
+			statusCode: http.StatusBadGateway,
+			body:       responseWithoutID,
+			err:        ErrBadStatusCode,
+			errMessage: "bad HTTP status code: 502 Bad Gateway from ",
+		},
+		"empty body": {
+			statusCode: http.StatusOK,
+			err:        ErrResponseInvalid,
+			errMessage: "response is invalid: from ",
+		},
+		"body shorter than a header": {
+			statusCode: http.StatusOK,
+			body:       []byte{0, 0, 0x81, 0x80},
+			err:        ErrResponseInvalid,
+			errMessage: "response is invalid: from ",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(dohHandler(t, testCase.statusCode, testCase.body))
+			defer server.Close()
+			d := &dohServer{url: server.URL, client: server.Client()}
+
+			response, err := d.exchange(context.Background(), exampleQuery())
+
+			assert.ErrorIs(t, err, testCase.err)
+			if testCase.err != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), testCase.errMessage+server.URL)
+			}
+			assert.Equal(t, testCase.response, response)
+		})
+	}
+}
+
+func Test_dohServer_exchange_serverDown(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	d := &dohServer{url: server.URL, client: server.Client()}
+	server.Close()
+
+	response, err := d.exchange(context.Background(), exampleQuery())
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+}
+
+func Test_newDoHServers(t *testing.T) {
+	t.Parallel()
+
+	responseWithoutID := exampleResponse()
+	responseWithoutID[0], responseWithoutID[1] = 0, 0
+	server := httptest.NewServer(dohHandler(t, http.StatusOK, responseWithoutID))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	// The hostname is not resolvable, so the server can only
+	// be reached by dialing its pinned IP address.
+	pinned := models.DoHServer{
+		URL: "http://doh.invalid:" + serverURL.Port() + "/dns-query",
+		IPs: []net.IP{net.IPv6loopback, net.IPv4(127, 0, 0, 1)},
+	}
+	unknown := models.DoHServer{
+		URL: "http://other.invalid:" + serverURL.Port() + "/dns-query",
+	}
+	const ipv6 = false
+
+	upstreams := newDoHServers([]models.DoHServer{pinned, unknown}, ipv6)
+	require.Len(t, upstreams, 2)
+	defer upstreams[0].close()
+	assert.Equal(t, pinned.URL, upstreams[0].String())
+
+	response, err := upstreams[0].exchange(context.Background(), exampleQuery())
+	require.NoError(t, err)
+	assert.Equal(t, exampleResponse(), response)
+
+	_, err = upstreams[1].exchange(context.Background(), exampleQuery())
+	assert.ErrorIs(t, err, ErrNoUpstreamServer)
+}
@@ -0,0 +1,139 @@
+package dns
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/qdm12/golibs/logging"
+)
+
+// healthCheckPeriod is the period to probe the unhealthy upstream servers.
+const healthCheckPeriod = 30 * time.Second
+
+// failover forwards DNS queries to the upstream servers, trying the
+// next server on failure. Failing servers are marked unhealthy and
+// are only tried after the healthy servers, until a health check
+// probe or a query to them succeeds again.
+type failover struct {
+	servers []upstream
+	healthy []uint32 // 1 if healthy, only accessed atomically
+	// priority is true to try the servers in their order,
+	// instead of rotating between them.
+	priority bool
+	next     uint32
+	logger   logging.Logger
+}
+
+func newFailover(servers []upstream, priority bool, logger logging.Logger) *failover {
+	healthy := make([]uint32, len(servers))
+	for i := range healthy {
+		healthy[i] = 1
+	}
+	return &failover{
+		servers:  servers,
+		healthy:  healthy,
+		priority: priority,
+		logger:   logger,
+	}
+}
+
+func (f *failover) exchange(ctx context.Context, query []byte) (response []byte, err error) {
+	if len(f.servers) == 0 {
+		return nil, ErrNoUpstreamServer
+	}
+
+	for _, i := range f.order() {
+		response, err = f.servers[i].exchange(ctx, query)
+		if err == nil {
+			f.setHealthy(i, true)
+			return response, nil
+		} else if ctx.Err() != nil {
+			break
+		}
+		f.setHealthy(i, false)
+	}
+	return nil, err
+}
+
+// order returns the indexes of the servers to try,
+// with the healthy servers first.
+func (f *failover) order() (indexes []int) {
+	start := 0
+	if !f.priority {
+		start = int(atomic.AddUint32(&f.next, 1))
+	}
+
+	indexes = make([]int, 0, len(f.servers))
+	var unhealthy []int
+	for i := range f.servers {
+		index := (start + i) % len(f.servers)
+		if atomic.LoadUint32(&f.healthy[index]) == 1 {
+			indexes = append(indexes, index)
+		} else {
+			unhealthy = append(unhealthy, index)
+		}
+	}
+	return append(indexes, unhealthy...)
+}
+
+func (f *failover) setHealthy(i int, healthy bool) {
+	var value uint32
+	if healthy {
+		value = 1
+	}
+	if atomic.SwapUint32(&f.healthy[i], value) == value {
+		return
+	}
+
+	if healthy {
+		f.logger.Info("upstream server %s is healthy again", f.servers[i])
+	} else {
+		f.logger.Warn("upstream server %s is unhealthy", f.servers[i])
+	}
+}
+
+// runHealthChecks probes the unhealthy servers periodically,
+// until the context is canceled.
+func (f *failover) runHealthChecks(ctx context.Context) {
+	ticker := time.NewTicker(healthCheckPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			f.probeUnhealthy(ctx)
+		}
+	}
+}
+
+func (f *failover) probeUnhealthy(ctx context.Context) {
+	for i, server := range f.servers {
+		if atomic.LoadUint32(&f.healthy[i]) == 1 {
+			continue
+		}
+		probeCtx, cancel := context.WithTimeout(ctx, exchangeTimeout)
+		_, err := server.exchange(probeCtx, probeQuery())
+		cancel()
+		if err == nil {
+			f.setHealthy(i, true)
+		} else if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (f *failover) close() {
+	for _, server := range f.servers {
+		server.close()
+	}
+}
+
+// probeQuery returns a query for the NS records of the root zone.
+func probeQuery() []byte {
+	return []byte{
+		0x70, 0x72, 0x01, 0x00, 0, 1, 0, 0, 0, 0, 0, 0, // header
+		0, 0, 2, 0, 1, // question
+	}
+}
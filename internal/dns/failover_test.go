@@ -0,0 +1,64 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testUpstream struct {
+	name    string
+	err     error
+	queries int
+}
+
+func (u *testUpstream) exchange(ctx context.Context, query []byte) (response []byte, err error) {
+	u.queries++
+	if u.err != nil {
+		return nil, u.err
+	}
+	return query, nil
+}
+
+func (u *testUpstream) close()         {}
+func (u *testUpstream) String() string { return u.name }
+
+func Test_failover(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	logger := mock_logging.NewMockLogger(ctrl)
+
+	errTest := errors.New("test error")
+	first := &testUpstream{name: "first", err: errTest}
+	second := &testUpstream{name: "second"}
+	const priority = true
+	f := newFailover([]upstream{first, second}, priority, logger)
+	ctx := context.Background()
+
+	logger.EXPECT().Warn("upstream server %s is unhealthy", first)
+	_, err := f.exchange(ctx, exampleQuery())
+	require.NoError(t, err)
+	assert.Equal(t, 1, first.queries)
+	assert.Equal(t, 1, second.queries)
+
+	// the unhealthy first server is tried last
+	_, err = f.exchange(ctx, exampleQuery())
+	require.NoError(t, err)
+	assert.Equal(t, 1, first.queries)
+	assert.Equal(t, 2, second.queries)
+
+	first.err = nil
+	logger.EXPECT().Info("upstream server %s is healthy again", first)
+	f.probeUnhealthy(ctx)
+	assert.Equal(t, 2, first.queries)
+
+	_, err = f.exchange(ctx, exampleQuery())
+	require.NoError(t, err)
+	assert.Equal(t, 3, first.queries)
+	assert.Equal(t, 2, second.queries)
+}
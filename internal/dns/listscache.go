@@ -0,0 +1,47 @@
+package dns
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/qdm12/golibs/os"
+)
+
+// listsCache caches the downloaded block and allow lists on disk, to
+// use them at startup until they are downloaded again, and in place of
+// lists failing to download.
+type listsCache struct {
+	filepath string
+	openFile os.OpenFileFunc
+}
+
+func (c *listsCache) load() (lists map[string][]string, err error) {
+	file, err := c.openFile(c.filepath, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&lists); err != nil && !errors.Is(err, io.EOF) {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return lists, file.Close()
+}
+
+func (c *listsCache) save(lists map[string][]string) (err error) {
+	file, err := c.openFile(c.filepath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600) //nolint:gomnd
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	if err := encoder.Encode(lists); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,94 @@
+package dns
+
+import (
+	"bufio"
+	"net"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/golibs/os"
+)
+
+// localRecordTTL is the TTL of the local records answers.
+const localRecordTTL = 60
+
+// localRecords are the IP addresses of hostnames answered by the
+// DNS server itself, without querying the upstream servers.
+type localRecords map[string][]net.IP
+
+// loadLocalRecords returns the local records of the settings and of the
+// hosts file. A missing hosts file is ignored.
+func loadLocalRecords(openFile os.OpenFileFunc, settings configuration.DNS) (
+	records localRecords, err error) {
+	records = make(localRecords, len(settings.LocalRecords))
+	for _, record := range settings.LocalRecords {
+		records.add(record.Hostname, record.IP)
+	}
+
+	if settings.HostsFile == "" {
+		return records, nil
+	}
+
+	file, err := openFile(settings.HostsFile, os.O_RDONLY, 0)
+	if os.IsNotExist(err) {
+		return records, nil
+	} else if err != nil {
+		return records, err
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		ip, hostnames := parseHostsLine(scanner.Text())
+		for _, hostname := range hostnames {
+			records.add(hostname, ip)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		_ = file.Close()
+		return records, err
+	}
+
+	return records, file.Close()
+}
+
+func (l localRecords) add(hostname string, ip net.IP) {
+	hostname = strings.TrimSuffix(strings.ToLower(hostname), ".")
+	l[hostname] = append(l[hostname], ip)
+}
+
+// answer returns the record data of the local records matching the
+// query type, and false if the hostname has no local record.
+func (l localRecords) answer(q question) (rdatas [][]byte, ok bool) {
+	ips, ok := l[q.name]
+	if !ok {
+		return nil, false
+	}
+	for _, ip := range ips {
+		ipv4 := ip.To4()
+		switch {
+		case q.qtype == typeA && ipv4 != nil:
+			rdatas = append(rdatas, ipv4)
+		case q.qtype == typeAAAA && ipv4 == nil:
+			rdatas = append(rdatas, ip.To16())
+		}
+	}
+	return rdatas, true
+}
+
+// parseHostsLine returns the IP address and hostnames of a hosts file
+// line, such as "192.168.1.10 nas.home nas". Comments are ignored.
+func parseHostsLine(line string) (ip net.IP, hostnames []string) {
+	if i := strings.IndexByte(line, '#'); i != -1 {
+		line = line[:i]
+	}
+	fields := strings.Fields(line)
+	const minFields = 2
+	if len(fields) < minFields {
+		return nil, nil
+	}
+	ip = net.ParseIP(fields[0])
+	if ip == nil {
+		return nil, nil
+	}
+	return ip, fields[1:]
+}
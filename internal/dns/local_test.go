@@ -0,0 +1,64 @@
+package dns
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseHostsLine(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		ip        net.IP
+		hostnames []string
+	}{
+		"":                               {},
+		"# comment":                      {},
+		"192.168.1.10":                   {},
+		"nas.home 192.168.1.10":          {},
+		"192.168.1.10 nas.home nas":      {ip: net.ParseIP("192.168.1.10"), hostnames: []string{"nas.home", "nas"}},
+		"fd00::10 nas.home # my NAS":     {ip: net.ParseIP("fd00::10"), hostnames: []string{"nas.home"}},
+		"\t192.168.1.11\tprinter.home\t": {ip: net.ParseIP("192.168.1.11"), hostnames: []string{"printer.home"}},
+	}
+	for line, testCase := range testCases {
+		line, testCase := line, testCase
+		t.Run(line, func(t *testing.T) {
+			t.Parallel()
+			ip, hostnames := parseHostsLine(line)
+			assert.Equal(t, testCase.ip, ip)
+			assert.Equal(t, testCase.hostnames, hostnames)
+		})
+	}
+}
+
+func Test_localRecords_answer(t *testing.T) {
+	t.Parallel()
+	records := make(localRecords)
+	records.add("Example.com.", net.ParseIP("192.168.1.10"))
+	records.add("example.com", net.ParseIP("fd00::10"))
+
+	query := exampleQuery()
+	q, questionEnd, err := parseQuestion(query)
+	require.NoError(t, err)
+
+	rdatas, ok := records.answer(q)
+	require.True(t, ok)
+	assert.Equal(t, [][]byte{{192, 168, 1, 10}}, rdatas)
+
+	response := answerResponse(query, questionEnd, q.qtype, localRecordTTL, rdatas)
+	answers, err := parseRecords(response, questionEnd)
+	require.NoError(t, err)
+	expected := []record{{
+		rrtype:    typeA,
+		class:     classIN,
+		ttlOffset: questionEnd + 6,
+		ttl:       localRecordTTL,
+		rdata:     []byte{192, 168, 1, 10},
+	}}
+	assert.Equal(t, expected, answers)
+
+	_, ok = records.answer(question{name: "github.com", qtype: typeA})
+	assert.False(t, ok)
+}
@@ -3,7 +3,6 @@ package dns
 
 import (
 	"context"
-	"errors"
 	"net"
 	"net/http"
 	"sync"
@@ -14,6 +13,7 @@ import (
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/golibs/logging"
+	"github.com/qdm12/golibs/os"
 )
 
 type Looper interface {
@@ -23,16 +23,28 @@ type Looper interface {
 	SetStatus(status models.LoopStatus) (outcome string, err error)
 	GetSettings() (settings configuration.DNS)
 	SetSettings(settings configuration.DNS) (outcome string)
+	GetQueries() (queries []models.DNSQuery)
+	GetStats() (stats models.DNSStats)
+	FlushCache() (outcome string)
+	Restart() (outcome string, err error)
+	ReloadBlocklists(ctx context.Context) (outcome string, err error)
+	SetBlockCategories(ctx context.Context, categories []string) (outcome string, err error)
+	SetBlocklists(ctx context.Context, settings configuration.DNS) (outcome string, err error)
 }
 
 type looper struct {
 	state        state
-	conf         unbound.Configurator
+	filter       *blockFilter
+	blocklistSet bool
+	// blocklistMu prevents concurrent updates of the block list.
+	blocklistMu  sync.Mutex
+	listsCache   *listsCache
+	queryLog     *queryLog
+	cache        *cache
+	stats        *stats
 	client       *http.Client
+	openFile     os.OpenFileFunc
 	logger       logging.Logger
-	username     string
-	puid         int
-	pgid         int
 	loopLock     sync.Mutex
 	start        chan struct{}
 	running      chan models.LoopStatus
@@ -46,19 +58,21 @@ type looper struct {
 
 const defaultBackoffTime = 10 * time.Second
 
-func NewLooper(conf unbound.Configurator, settings configuration.DNS, client *http.Client,
-	logger logging.Logger, username string, puid, pgid int) Looper {
+func NewLooper(settings configuration.DNS, client *http.Client,
+	openFile os.OpenFileFunc, logger logging.Logger) Looper {
 	return &looper{
 		state: state{
 			status:   constants.Stopped,
 			settings: settings,
 		},
-		conf:         conf,
+		filter:       &blockFilter{},
+		listsCache:   &listsCache{filepath: constants.DNSLists, openFile: openFile},
+		queryLog:     newQueryLog(logger.NewChild(logging.SetPrefix("dns query: "))),
+		cache:        newCache(maxCacheEntries, time.Now),
+		stats:        &stats{},
 		client:       client,
+		openFile:     openFile,
 		logger:       logger.NewChild(logging.SetPrefix("dns over tls: ")),
-		username:     username,
-		puid:         puid,
-		pgid:         pgid,
 		start:        make(chan struct{}),
 		running:      make(chan models.LoopStatus),
 		stop:         make(chan struct{}),
@@ -105,11 +119,10 @@ func (l *looper) Run(ctx context.Context, wg *sync.WaitGroup) {
 	l.backoffTime = defaultBackoffTime
 
 	for ctx.Err() == nil {
-		// Upper scope variables for Unbound only
+		// Upper scope variables for the DNS server only
 		// Their values are to be used if DOT=off
 		var waitError chan error
-		var unboundCancel context.CancelFunc
-		var closeStreams func()
+		var serverCancel context.CancelFunc
 
 		for l.GetSettings().Enabled {
 			if ctx.Err() != nil {
@@ -120,12 +133,10 @@ func (l *looper) Run(ctx context.Context, wg *sync.WaitGroup) {
 				return
 			}
 			var err error
-			unboundCancel, waitError, closeStreams, err = l.setupUnbound(ctx, wg, crashed)
+			serverCancel, waitError, err = l.setupServer(wg, crashed)
 			if err != nil {
-				if !errors.Is(err, errUpdateFiles) {
-					const fallback = true
-					l.useUnencryptedDNS(fallback)
-				}
+				const fallback = true
+				l.useUnencryptedDNS(fallback)
 				l.logAndWait(ctx, err)
 				continue
 			}
@@ -134,9 +145,8 @@ func (l *looper) Run(ctx context.Context, wg *sync.WaitGroup) {
 		if !l.GetSettings().Enabled {
 			const fallback = false
 			l.useUnencryptedDNS(fallback)
-			waitError := make(chan error)
-			unboundCancel = func() { waitError <- nil }
-			closeStreams = func() {}
+			waitError = make(chan error, 1)
+			serverCancel = func() { waitError <- nil }
 		}
 
 		stayHere := true
@@ -144,23 +154,22 @@ func (l *looper) Run(ctx context.Context, wg *sync.WaitGroup) {
 			select {
 			case <-ctx.Done():
 				l.logger.Warn("context canceled: exiting loop")
-				unboundCancel()
+				serverCancel()
 				<-waitError
 				close(waitError)
-				closeStreams()
 				return
 			case <-l.stop:
 				l.logger.Info("stopping")
 				const fallback = false
 				l.useUnencryptedDNS(fallback)
-				unboundCancel()
+				serverCancel()
 				<-waitError
 				l.stopped <- struct{}{}
 			case <-l.start:
 				l.logger.Info("starting")
 				stayHere = false
 			case err := <-waitError: // unexpected error
-				unboundCancel()
+				serverCancel()
 				l.state.setStatusWithLock(constants.Crashed)
 				const fallback = true
 				l.useUnencryptedDNS(fallback)
@@ -169,54 +178,48 @@ func (l *looper) Run(ctx context.Context, wg *sync.WaitGroup) {
 			}
 		}
 		close(waitError)
-		closeStreams()
 	}
 }
 
-var errUpdateFiles = errors.New("cannot update files")
+// setupServer starts the DNS server and uses it for DNS resolution.
+// The block lists are then updated in the background, since their
+// download is resolved through the DNS server.
+func (l *looper) setupServer(wg *sync.WaitGroup, previousCrashed bool) (
+	cancel context.CancelFunc, waitError chan error, err error) {
+	settings := l.GetSettings()
 
-// Returning cancel == nil signals we want to re-run setupUnbound
-// Returning err == errUpdateFiles signals we should not fall back
-// on the plaintext DNS as DOT is still up and running.
-func (l *looper) setupUnbound(ctx context.Context, wg *sync.WaitGroup,
-	previousCrashed bool) (cancel context.CancelFunc, waitError chan error,
-	closeStreams func(), err error) {
-	err = l.updateFiles(ctx)
+	local, err := loadLocalRecords(l.openFile, settings)
 	if err != nil {
-		l.state.setStatusWithLock(constants.Crashed)
-		return nil, nil, nil, errUpdateFiles
+		l.logger.Warn("cannot load local records from hosts file: %s", err)
 	}
 
-	settings := l.GetSettings()
+	if err := l.queryLog.setSettings(settings); err != nil {
+		l.logger.Warn("cannot set query log: %s", err)
+	}
 
-	unboundCtx, cancel := context.WithCancel(context.Background())
-	stdoutLines, stderrLines, waitError, err := l.conf.Start(unboundCtx, settings.Unbound.VerbosityDetailsLevel)
+	l.cache.flush() // settings may have changed
+	server := newServer(settings, local, l.cache, l.filter, l.queryLog, l.stats, l.logger)
+	udpConn, tcpListener, err := server.listen()
 	if err != nil {
-		cancel()
 		if !previousCrashed {
 			l.running <- constants.Crashed
 		}
-		return nil, nil, nil, err
+		return nil, nil, err
 	}
 
-	wg.Add(1)
-	go l.collectLines(wg, stdoutLines, stderrLines)
+	serverCtx, cancel := context.WithCancel(context.Background())
+	waitError = make(chan error)
+	go func() {
+		waitError <- server.serve(serverCtx, udpConn, tcpListener)
+	}()
 
-	l.conf.UseDNSInternally(net.IP{127, 0, 0, 1})                                                  // use Unbound
-	if err := l.conf.UseDNSSystemWide(net.IP{127, 0, 0, 1}, settings.KeepNameserver); err != nil { // use Unbound
-		l.logger.Error(err)
+	nameserver := settings.ListeningIP()
+	if nameserver == nil {
+		nameserver = net.IP{127, 0, 0, 1}
 	}
-
-	if err := l.conf.WaitForUnbound(ctx); err != nil {
-		if !previousCrashed {
-			l.running <- constants.Crashed
-		}
-		cancel()
-		<-waitError
-		close(waitError)
-		close(stdoutLines)
-		close(stderrLines)
-		return nil, nil, nil, err
+	useDNSInternally(nameserver)
+	if err := useDNSSystemWide(l.openFile, nameserver, settings.KeepNameserver); err != nil {
+		l.logger.Error(err)
 	}
 
 	l.logger.Info("ready")
@@ -227,12 +230,13 @@ func (l *looper) setupUnbound(ctx context.Context, wg *sync.WaitGroup,
 		l.state.setStatusWithLock(constants.Running)
 	}
 
-	closeStreams = func() {
-		close(stdoutLines)
-		close(stderrLines)
-	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		l.updateBlocklist(serverCtx)
+	}()
 
-	return cancel, waitError, closeStreams, nil
+	return cancel, waitError, nil
 }
 
 func (l *looper) useUnencryptedDNS(fallback bool) {
@@ -246,15 +250,15 @@ func (l *looper) useUnencryptedDNS(fallback bool) {
 		} else {
 			l.logger.Info("using plaintext DNS at address %s", targetIP)
 		}
-		l.conf.UseDNSInternally(targetIP)
-		if err := l.conf.UseDNSSystemWide(targetIP, settings.KeepNameserver); err != nil {
+		useDNSInternally(targetIP)
+		if err := useDNSSystemWide(l.openFile, targetIP, settings.KeepNameserver); err != nil {
 			l.logger.Error(err)
 		}
 		return
 	}
 
 	// Try with any IPv4 address from the providers chosen
-	for _, provider := range settings.Unbound.Providers {
+	for _, provider := range settings.Providers {
 		data, _ := unbound.GetProviderData(provider)
 		for _, targetIP = range data.IPs {
 			if targetIP.To4() != nil {
@@ -263,8 +267,8 @@ func (l *looper) useUnencryptedDNS(fallback bool) {
 				} else {
 					l.logger.Info("using plaintext DNS at address %s", targetIP)
 				}
-				l.conf.UseDNSInternally(targetIP)
-				if err := l.conf.UseDNSSystemWide(targetIP, settings.KeepNameserver); err != nil {
+				useDNSInternally(targetIP)
+				if err := useDNSSystemWide(l.openFile, targetIP, settings.KeepNameserver); err != nil {
 					l.logger.Error(err)
 				}
 				return
@@ -273,7 +277,7 @@ func (l *looper) useUnencryptedDNS(fallback bool) {
 	}
 
 	// No IPv4 address found
-	l.logger.Error("no ipv4 DNS address found for providers %s", settings.Unbound.Providers)
+	l.logger.Error("no ipv4 DNS address found for providers %s", settings.Providers)
 }
 
 func (l *looper) RunRestartTicker(ctx context.Context, wg *sync.WaitGroup) {
@@ -298,21 +302,13 @@ func (l *looper) RunRestartTicker(ctx context.Context, wg *sync.WaitGroup) {
 		case <-timer.C:
 			lastTick = l.timeNow()
 
-			status := l.GetStatus()
-			if status == constants.Running {
-				if err := l.updateFiles(ctx); err != nil {
-					l.state.setStatusWithLock(constants.Crashed)
-					l.logger.Error(err)
-					l.logger.Warn("skipping Unbound restart due to failed files update")
-					continue
-				}
+			if l.GetStatus() == constants.Running {
+				l.updateBlocklist(ctx)
 			}
 
-			_, _ = l.SetStatus(constants.Stopped)
-			_, _ = l.SetStatus(constants.Running)
-
 			settings := l.GetSettings()
 			timer.Reset(settings.UpdatePeriod)
+			l.logger.Info("next block lists update in %s", settings.UpdatePeriod)
 		case <-l.updateTicker:
 			if !timer.Stop() {
 				<-timer.C
@@ -334,26 +330,48 @@ func (l *looper) RunRestartTicker(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
-func (l *looper) updateFiles(ctx context.Context) (err error) {
-	l.logger.Info("downloading DNS over TLS cryptographic files")
-	if err := l.conf.SetupFiles(ctx); err != nil {
-		return err
-	}
+// updateBlocklist downloads the block and allow lists and updates the
+// block list used by the DNS server. The lists cached on disk are used
+// until the lists are downloaded, and in place of lists failing to download.
+func (l *looper) updateBlocklist(ctx context.Context) {
+	l.blocklistMu.Lock()
+	defer l.blocklistMu.Unlock()
+
 	settings := l.GetSettings()
+	hostnamesURLs, ipsURLs, allowedURLs := listURLs(settings)
+	urls := append(append(hostnamesURLs, ipsURLs...), allowedURLs...)
+
+	cached, err := l.listsCache.load()
+	if err != nil && !os.IsNotExist(err) {
+		l.logger.Warn("cannot load cached block lists: %s", err)
+	}
+	if !l.blocklistSet && len(cached) > 0 {
+		l.filter.set(buildBlocklist(settings, cached))
+		l.blocklistSet = true
+		l.logger.Info("using cached block lists until they are downloaded")
+	}
 
 	l.logger.Info("downloading hostnames and IP block lists")
-	hostnameLines, ipLines, errs := l.conf.BuildBlocked(ctx, l.client,
-		settings.BlockMalicious, settings.BlockAds, settings.BlockSurveillance,
-		settings.Unbound.BlockedHostnames, settings.Unbound.BlockedIPs,
-		settings.Unbound.AllowedHostnames)
-	for _, err := range errs {
+	lists, errs := fetchLists(ctx, l.client, urls)
+	if ctx.Err() != nil {
+		return
+	}
+	for url, err := range errs {
+		if lines, ok := cached[url]; ok {
+			l.logger.Warn("%s, using cached list", err)
+			lists[url] = lines
+			continue
+		}
 		l.logger.Warn(err)
 	}
 
-	if err := l.conf.MakeUnboundConf(
-		settings.Unbound, hostnameLines, ipLines,
-		l.username, l.puid, l.pgid); err != nil {
-		return err
+	if err := l.listsCache.save(lists); err != nil {
+		l.logger.Warn("cannot cache block lists: %s", err)
 	}
-	return nil
+
+	blocklist := buildBlocklist(settings, lists)
+	l.filter.set(blocklist)
+	l.blocklistSet = true
+	l.logger.Info("blocking %d hostnames and %d IP address ranges",
+		len(blocklist.hostnames), len(blocklist.subnets))
 }
@@ -0,0 +1,226 @@
+package dns
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DNS message format, see RFC 1035 section 4.
+const (
+	headerLength = 12
+	typeA        = 1
+	typeAAAA     = 28
+	typeOPT      = 41
+	classIN      = 1
+
+	rcodeSuccess       = 0
+	rcodeServerFailure = 2
+	rcodeNameError     = 3
+
+	tcFlag = 0x02
+
+	// maxUDPLength is the maximum length of a response over UDP,
+	// if the query does not advertise a larger size with EDNS.
+	maxUDPLength = 512
+	// maxPointers is the maximum number of compression pointers
+	// followed to read a name, to stop on pointer loops.
+	maxPointers = 16
+)
+
+var (
+	ErrMessageTooShort = errors.New("message is too short")
+	ErrQuestionsCount  = errors.New("message must have exactly one question")
+	ErrNameInvalid     = errors.New("name is invalid")
+)
+
+type question struct {
+	name   string // lowercased
+	qtype  uint16
+	qclass uint16
+}
+
+func (q question) String() string {
+	return fmt.Sprintf("%s (type %d)", q.name, q.qtype)
+}
+
+// record is a resource record of a DNS message.
+type record struct {
+	rrtype uint16
+	class  uint16
+	// ttlOffset is the offset of the TTL in the message.
+	ttlOffset int
+	ttl       uint32
+	rdata     []byte
+}
+
+// parseQuestion returns the question of the DNS message and the
+// message offset right after the question.
+func parseQuestion(message []byte) (q question, end int, err error) {
+	if len(message) < headerLength {
+		return q, 0, ErrMessageTooShort
+	}
+
+	if count := binary.BigEndian.Uint16(message[4:]); count != 1 {
+		return q, 0, fmt.Errorf("%w: %d questions", ErrQuestionsCount, count)
+	}
+
+	q.name, end, err = readName(message, headerLength)
+	if err != nil {
+		return q, 0, err
+	}
+
+	const typeAndClassLength = 4
+	if end+typeAndClassLength > len(message) {
+		return q, 0, ErrMessageTooShort
+	}
+	q.qtype = binary.BigEndian.Uint16(message[end:])
+	q.qclass = binary.BigEndian.Uint16(message[end+2:])
+	return q, end + typeAndClassLength, nil
+}
+
+// readName reads the possibly compressed name at the offset of the message,
+// and returns it lowercased with the offset right after the name.
+func readName(message []byte, offset int) (name string, end int, err error) {
+	var labels []string
+	end = -1
+	pointers := 0
+	for {
+		if offset >= len(message) {
+			return "", 0, ErrMessageTooShort
+		}
+		length := int(message[offset])
+		switch {
+		case length == 0:
+			if end == -1 {
+				end = offset + 1
+			}
+			return strings.ToLower(strings.Join(labels, ".")), end, nil
+		case length&0xc0 == 0xc0: // compression pointer
+			if offset+1 >= len(message) {
+				return "", 0, ErrMessageTooShort
+			}
+			if end == -1 {
+				end = offset + 2 //nolint:gomnd
+			}
+			pointers++
+			if pointers > maxPointers {
+				return "", 0, fmt.Errorf("%w: too many compression pointers", ErrNameInvalid)
+			}
+			offset = int(binary.BigEndian.Uint16(message[offset:]) & 0x3fff)
+		case length&0xc0 != 0:
+			return "", 0, fmt.Errorf("%w: unknown label type", ErrNameInvalid)
+		default:
+			offset++
+			if offset+length > len(message) {
+				return "", 0, ErrMessageTooShort
+			}
+			labels = append(labels, string(message[offset:offset+length]))
+			offset += length
+		}
+	}
+}
+
+// parseRecords returns the resource records of all the sections
+// of the DNS message, starting at the offset right after the question.
+func parseRecords(message []byte, offset int) (records []record, err error) {
+	count := int(binary.BigEndian.Uint16(message[6:])) +
+		int(binary.BigEndian.Uint16(message[8:])) +
+		int(binary.BigEndian.Uint16(message[10:]))
+	records = make([]record, 0, count)
+	for i := 0; i < count; i++ {
+		_, offset, err = readName(message, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		const fixedLength = 10 // type, class, TTL and data length
+		if offset+fixedLength > len(message) {
+			return nil, ErrMessageTooShort
+		}
+		r := record{
+			rrtype:    binary.BigEndian.Uint16(message[offset:]),
+			class:     binary.BigEndian.Uint16(message[offset+2:]),
+			ttlOffset: offset + 4, //nolint:gomnd
+			ttl:       binary.BigEndian.Uint32(message[offset+4:]),
+		}
+		dataLength := int(binary.BigEndian.Uint16(message[offset+8:]))
+		offset += fixedLength
+		if offset+dataLength > len(message) {
+			return nil, ErrMessageTooShort
+		}
+		r.rdata = message[offset : offset+dataLength]
+		offset += dataLength
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+func responseCode(message []byte) (rcode byte) {
+	return message[3] & 0x0f
+}
+
+// emptyResponse returns a response to the query containing only its
+// question, with the response code given.
+func emptyResponse(query []byte, questionEnd int, rcode byte) (response []byte) {
+	response = make([]byte, questionEnd)
+	copy(response, query[:questionEnd])
+	const qrFlag, opcodeAndRDFlags = 0x80, 0x79
+	response[2] = qrFlag | query[2]&opcodeAndRDFlags
+	const raFlag = 0x80
+	response[3] = raFlag | rcode
+	binary.BigEndian.PutUint16(response[6:], 0)
+	binary.BigEndian.PutUint16(response[8:], 0)
+	binary.BigEndian.PutUint16(response[10:], 0)
+	return response
+}
+
+// truncatedResponse returns a response to the query with the truncated
+// flag set, for the client to retry over TCP.
+func truncatedResponse(query []byte, questionEnd int) (response []byte) {
+	response = emptyResponse(query, questionEnd, rcodeSuccess)
+	response[2] |= tcFlag
+	return response
+}
+
+// answerResponse returns an authoritative response to the query,
+// with an answer record for each of the record data given.
+func answerResponse(query []byte, questionEnd int, rrtype uint16,
+	ttl uint32, rdatas [][]byte) (response []byte) {
+	response = emptyResponse(query, questionEnd, rcodeSuccess)
+	const aaFlag = 0x04
+	response[2] |= aaFlag
+	binary.BigEndian.PutUint16(response[6:], uint16(len(rdatas)))
+	for _, rdata := range rdatas {
+		const namePointer = 0xc000 | headerLength // name of the question
+		const fixedLength = 12
+		record := make([]byte, fixedLength, fixedLength+len(rdata))
+		binary.BigEndian.PutUint16(record[0:], namePointer)
+		binary.BigEndian.PutUint16(record[2:], rrtype)
+		binary.BigEndian.PutUint16(record[4:], classIN)
+		binary.BigEndian.PutUint32(record[6:], ttl)
+		binary.BigEndian.PutUint16(record[10:], uint16(len(rdata)))
+		response = append(response, append(record, rdata...)...)
+	}
+	return response
+}
+
+func truncated(message []byte) bool {
+	return message[2]&tcFlag != 0
+}
+
+// maxUDPResponseLength returns the maximum response length over UDP
+// advertised by the EDNS record of the query, or 512 bytes otherwise.
+func maxUDPResponseLength(query []byte, questionEnd int) (length int) {
+	records, err := parseRecords(query, questionEnd)
+	if err != nil {
+		return maxUDPLength
+	}
+	for _, r := range records {
+		if r.rrtype == typeOPT && int(r.class) > maxUDPLength {
+			return int(r.class)
+		}
+	}
+	return maxUDPLength
+}
@@ -0,0 +1,117 @@
+package dns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exampleQuery is a query for the A record of Example.com.
+func exampleQuery() []byte {
+	return []byte{
+		0x12, 0x34, 0x01, 0x00, 0, 1, 0, 0, 0, 0, 0, 0, // header
+		7, 'E', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0, 0, 1, 0, 1, // question
+	}
+}
+
+// exampleResponse is a response for exampleQuery with a compressed
+// name A record of 93.184.216.34 with a TTL of 300 seconds.
+func exampleResponse() []byte {
+	return append([]byte{
+		0x12, 0x34, 0x81, 0x80, 0, 1, 0, 1, 0, 0, 0, 0, // header
+		7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0, 0, 1, 0, 1, // question
+		0xc0, 12, 0, 1, 0, 1, 0, 0, 0x01, 0x2c, 0, 4, // answer
+	}, 93, 184, 216, 34)
+}
+
+func Test_parseQuestion(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		message  []byte
+		question question
+		end      int
+		err      error
+	}{
+		"too short": {
+			message: []byte{0x12, 0x34},
+			err:     ErrMessageTooShort,
+		},
+		"no question": {
+			message: []byte{0x12, 0x34, 0x01, 0x00, 0, 0, 0, 0, 0, 0, 0, 0},
+			err:     ErrQuestionsCount,
+		},
+		"truncated name": {
+			message: exampleQuery()[:20],
+			err:     ErrMessageTooShort,
+		},
+		"pointer loop": {
+			message: []byte{0x12, 0x34, 0x01, 0x00, 0, 1, 0, 0, 0, 0, 0, 0, 0xc0, 12},
+			err:     ErrNameInvalid,
+		},
+		"lowercased name": {
+			message:  exampleQuery(),
+			question: question{name: "example.com", qtype: typeA, qclass: 1},
+			end:      len(exampleQuery()),
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			q, end, err := parseQuestion(testCase.message)
+			if testCase.err != nil {
+				assert.ErrorIs(t, err, testCase.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.question, q)
+			assert.Equal(t, testCase.end, end)
+		})
+	}
+}
+
+func Test_parseRecords(t *testing.T) {
+	t.Parallel()
+	response := exampleResponse()
+	_, questionEnd, err := parseQuestion(response)
+	require.NoError(t, err)
+
+	records, err := parseRecords(response, questionEnd)
+	require.NoError(t, err)
+
+	expected := []record{{
+		rrtype:    typeA,
+		class:     1,
+		ttlOffset: 35,
+		ttl:       300,
+		rdata:     []byte{93, 184, 216, 34},
+	}}
+	assert.Equal(t, expected, records)
+
+	_, err = parseRecords(response[:len(response)-1], questionEnd)
+	assert.ErrorIs(t, err, ErrMessageTooShort)
+}
+
+func Test_emptyResponse(t *testing.T) {
+	t.Parallel()
+	query := exampleQuery()
+	response := emptyResponse(query, len(query), rcodeNameError)
+	expected := []byte{
+		0x12, 0x34, 0x81, 0x83, 0, 1, 0, 0, 0, 0, 0, 0,
+		7, 'E', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0, 0, 1, 0, 1,
+	}
+	assert.Equal(t, expected, response)
+	assert.Equal(t, exampleQuery(), query)
+}
+
+func Test_maxUDPResponseLength(t *testing.T) {
+	t.Parallel()
+	query := exampleQuery()
+	assert.Equal(t, maxUDPLength, maxUDPResponseLength(query, len(query)))
+
+	// add an OPT record advertising 4096 bytes
+	query[11] = 1
+	query = append(query, 0, 0, typeOPT, 0x10, 0, 0, 0, 0, 0, 0, 0)
+	assert.Equal(t, 4096, maxUDPResponseLength(query, len(exampleQuery())))
+}
@@ -0,0 +1,62 @@
+package dns
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/golibs/os"
+)
+
+// useDNSInternally changes the Go program DNS only.
+func useDNSInternally(ip net.IP) {
+	net.DefaultResolver.PreferGo = true
+	net.DefaultResolver.Dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		d := net.Dialer{}
+		return d.DialContext(ctx, "udp", net.JoinHostPort(ip.String(), "53"))
+	}
+}
+
+// useDNSSystemWide changes the nameserver to use for DNS system wide.
+func useDNSSystemWide(openFile os.OpenFileFunc, ip net.IP, keepNameserver bool) error {
+	file, err := openFile(constants.ResolvConf, os.O_RDONLY, 0)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	s := strings.TrimSuffix(string(data), "\n")
+
+	lines := []string{
+		"nameserver " + ip.String(),
+	}
+	for _, line := range strings.Split(s, "\n") {
+		if line == "" ||
+			(!keepNameserver && strings.HasPrefix(line, "nameserver ")) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	s = strings.Join(lines, "\n") + "\n"
+
+	file, err = openFile(constants.ResolvConf, os.O_WRONLY|os.O_TRUNC, 0644) //nolint:gomnd
+	if err != nil {
+		return err
+	}
+	_, err = file.WriteString(s)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
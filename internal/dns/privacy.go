@@ -0,0 +1,85 @@
+package dns
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+)
+
+// optionClientSubnet is the EDNS option code of the client subnet, see RFC 7871.
+const optionClientSubnet = 8
+
+// stripClientSubnet returns the query without the EDNS client subnet
+// options of its EDNS record, such that the upstream servers do not learn
+// the subnet of the client, as recommended by RFC 7871 section 11.1.
+func stripClientSubnet(query []byte, questionEnd int) (stripped []byte) {
+	records, err := parseRecords(query, questionEnd)
+	if err != nil {
+		return query
+	}
+	for _, r := range records {
+		if r.rrtype != typeOPT {
+			continue
+		}
+
+		options := make([]byte, 0, len(r.rdata))
+		for rest := r.rdata; len(rest) >= 4; {
+			code := binary.BigEndian.Uint16(rest)
+			length := int(binary.BigEndian.Uint16(rest[2:]))
+			if 4+length > len(rest) {
+				return query // malformed options
+			}
+			if code != optionClientSubnet {
+				options = append(options, rest[:4+length]...)
+			}
+			rest = rest[4+length:]
+		}
+		if len(options) == len(r.rdata) {
+			return query
+		}
+
+		dataLengthOffset := r.ttlOffset + 4 //nolint:gomnd
+		rdataEnd := dataLengthOffset + 2 + len(r.rdata)
+		stripped = make([]byte, 0, len(query)-len(r.rdata)+len(options))
+		stripped = append(stripped, query[:dataLengthOffset]...)
+		stripped = append(stripped, byte(len(options)>>8), byte(len(options))) //nolint:gomnd
+		stripped = append(stripped, options...)
+		return append(stripped, query[rdataEnd:]...)
+	}
+	return query
+}
+
+// randomizeCase randomly upper or lower cases the letters of the
+// question name of the query, in place. Since servers copy the question
+// in their response, a response with a different case is spoofed,
+// see draft-vixie-dnsext-dns0x20.
+func randomizeCase(query []byte, questionEnd int) {
+	random := make([]byte, questionEnd)
+	if _, err := rand.Read(random); err != nil {
+		return
+	}
+	for offset := headerLength; offset < questionEnd; {
+		length := int(query[offset])
+		if length == 0 || length&0xc0 != 0 {
+			return
+		}
+		for i := offset + 1; i <= offset+length && i < questionEnd; i++ {
+			c := query[i] | 0x20 //nolint:gomnd
+			if c < 'a' || c > 'z' {
+				continue
+			}
+			if random[i]&1 == 1 {
+				c &^= 0x20
+			}
+			query[i] = c
+		}
+		offset += length + 1
+	}
+}
+
+// caseMatches returns true if the question name of the response
+// has the same case as the question name of the query.
+func caseMatches(query, response []byte, questionEnd int) (match bool) {
+	return len(query) >= questionEnd && len(response) >= questionEnd &&
+		bytes.Equal(query[headerLength:questionEnd], response[headerLength:questionEnd])
+}
@@ -0,0 +1,49 @@
+package dns
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_stripClientSubnet(t *testing.T) {
+	t.Parallel()
+	query := exampleQuery()
+	query[11] = 1
+	opt := []byte{0, 0, typeOPT, 0x04, 0xd0, 0, 0, 0, 0, 0, 19}
+	clientSubnet := []byte{0, optionClientSubnet, 0, 7, 0, 1, 24, 0, 192, 168, 1}
+	cookie := []byte{0, 10, 0, 4, 1, 2, 3, 4}
+	query = append(append(append(query, opt...), clientSubnet...), cookie...)
+
+	stripped := stripClientSubnet(query, len(exampleQuery()))
+
+	expected := exampleQuery()
+	expected[11] = 1
+	expected = append(expected, 0, 0, typeOPT, 0x04, 0xd0, 0, 0, 0, 0, 0, 8)
+	expected = append(expected, cookie...)
+	assert.Equal(t, expected, stripped)
+
+	// no client subnet option
+	assert.Equal(t, expected, stripClientSubnet(expected, len(exampleQuery())))
+}
+
+func Test_randomizeCase(t *testing.T) {
+	t.Parallel()
+	query := exampleQuery()
+	questionEnd := len(query)
+
+	randomizeCase(query, questionEnd)
+
+	assert.True(t, bytes.EqualFold(exampleQuery(), query))
+	assert.Equal(t, exampleQuery()[:headerLength], query[:headerLength])
+	q, _, err := parseQuestion(query)
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com", q.name)
+
+	response := exampleResponse()
+	copy(response[headerLength:], query[headerLength:questionEnd])
+	assert.True(t, caseMatches(query, response, questionEnd))
+	response[headerLength+1] ^= 0x20
+	assert.False(t, caseMatches(query, response, questionEnd))
+}
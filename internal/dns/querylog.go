@@ -0,0 +1,205 @@
+package dns
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/logging"
+)
+
+const (
+	// maxRecentQueries is the number of recent queries
+	// kept in memory for the control server.
+	maxRecentQueries = 100
+	// maxQueryLogFileSize is the size at which the query log file
+	// is rotated, keeping the previous file with the .1 suffix.
+	maxQueryLogFileSize = 10 * 1024 * 1024
+)
+
+// Outcomes of a query logged.
+const (
+	outcomeLocal     = "local"
+	outcomeSplit     = "split"
+	outcomeBlocked   = "blocked"
+	outcomeBogus     = "bogus"
+	outcomeCached    = "cached"
+	outcomeForwarded = "forwarded"
+	outcomeFailed    = "failed"
+)
+
+// queryLog logs the DNS queries to the logger or to a rotated file,
+// and keeps the most recent queries in memory.
+type queryLog struct {
+	mode     string
+	filepath string
+	file     *os.File // nil if not logging to a file
+	size     int64
+	recent   []models.DNSQuery
+	next     int // index in recent to write the next query to
+	logger   logging.Logger
+	timeNow  func() time.Time
+	mutex    sync.Mutex
+}
+
+func newQueryLog(logger logging.Logger) *queryLog {
+	return &queryLog{
+		mode:    constants.DNSQueryLogNone,
+		logger:  logger,
+		timeNow: time.Now,
+	}
+}
+
+// setSettings changes the query log mode and file. The recent
+// queries are cleared if query logging is disabled.
+func (l *queryLog) setSettings(settings configuration.DNS) (err error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	switch settings.QueryLog {
+	case constants.DNSQueryLogAnonymized, constants.DNSQueryLogFull:
+		l.mode = settings.QueryLog
+	default:
+		l.mode = constants.DNSQueryLogNone
+		l.recent, l.next = nil, 0
+	}
+
+	filepath := settings.QueryLogFile
+	if l.mode == constants.DNSQueryLogNone {
+		filepath = ""
+	}
+	if filepath == l.filepath {
+		return nil
+	}
+
+	if l.file != nil {
+		_ = l.file.Close()
+		l.file = nil
+	}
+	l.filepath = filepath
+	if l.filepath == "" {
+		return nil
+	}
+	return l.openFile()
+}
+
+func (l *queryLog) openFile() (err error) {
+	const perm = 0600
+	file, err := os.OpenFile(l.filepath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, perm)
+	if err != nil {
+		return err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	l.file = file
+	l.size = stat.Size()
+	return nil
+}
+
+func (l *queryLog) log(client net.Addr, q question, outcome string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.mode == constants.DNSQueryLogNone {
+		return
+	}
+
+	query := models.DNSQuery{
+		Time:    l.timeNow(),
+		Name:    q.name,
+		Type:    q.qtype,
+		Outcome: outcome,
+	}
+	if ip := addressIP(client); ip != nil {
+		if l.mode == constants.DNSQueryLogAnonymized {
+			ip = anonymizeIP(ip)
+		}
+		query.Client = ip.String()
+	}
+
+	if len(l.recent) < maxRecentQueries {
+		l.recent = append(l.recent, query)
+	} else {
+		l.recent[l.next] = query
+	}
+	l.next = (l.next + 1) % maxRecentQueries
+
+	if l.file == nil {
+		l.logger.Info("query %s from %s: %s", q, query.Client, outcome)
+		return
+	}
+
+	if err := l.write(query.String() + "\n"); err != nil {
+		l.logger.Warn("cannot write to query log file: %s", err)
+	}
+}
+
+// write writes the line to the query log file, rotating
+// the file first if it would exceed its maximum size.
+func (l *queryLog) write(line string) (err error) {
+	if l.size > 0 && l.size+int64(len(line)) > maxQueryLogFileSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := l.file.WriteString(line)
+	l.size += int64(n)
+	return err
+}
+
+func (l *queryLog) rotate() (err error) {
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	l.file = nil
+	if err := os.Rename(l.filepath, l.filepath+".1"); err != nil {
+		return fmt.Errorf("cannot rotate query log file: %w", err)
+	}
+	return l.openFile()
+}
+
+// queries returns the most recent queries, from the oldest to the newest.
+func (l *queryLog) queries() (queries []models.DNSQuery) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	queries = make([]models.DNSQuery, 0, len(l.recent))
+	if len(l.recent) == maxRecentQueries {
+		queries = append(queries, l.recent[l.next:]...)
+		return append(queries, l.recent[:l.next]...)
+	}
+	return append(queries, l.recent...)
+}
+
+func addressIP(address net.Addr) (ip net.IP) {
+	switch address := address.(type) {
+	case *net.UDPAddr:
+		return address.IP
+	case *net.TCPAddr:
+		return address.IP
+	default:
+		return nil
+	}
+}
+
+// anonymizeIP zeroes the host part of the IP address, keeping
+// the first 24 bits of IPv4 addresses and 48 bits of IPv6 addresses.
+func anonymizeIP(ip net.IP) net.IP {
+	if ipv4 := ip.To4(); ipv4 != nil {
+		const bits = 24
+		return ipv4.Mask(net.CIDRMask(bits, 8*net.IPv4len))
+	}
+	const bits = 48
+	return ip.Mask(net.CIDRMask(bits, 8*net.IPv6len))
+}
+
+func (l *looper) GetQueries() (queries []models.DNSQuery) {
+	return l.queryLog.queries()
+}
@@ -0,0 +1,69 @@
+package dns
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_anonymizeIP(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]string{
+		"192.168.1.23":           "192.168.1.0",
+		"2001:db8:1:2:3:4:5:6":   "2001:db8:1::",
+		"::ffff:192.168.100.200": "192.168.100.0",
+	}
+	for ip, expected := range testCases {
+		ip, expected := ip, expected
+		t.Run(ip, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, expected, anonymizeIP(net.ParseIP(ip)).String())
+		})
+	}
+}
+
+func Test_queryLog(t *testing.T) {
+	t.Parallel()
+	logFilepath := filepath.Join(t.TempDir(), "queries.log")
+	queryLog := newQueryLog(nil)
+	queryLog.timeNow = func() time.Time { return time.Unix(0, 0).UTC() }
+	client := &net.UDPAddr{IP: net.IP{192, 168, 1, 23}, Port: 5353}
+	q := question{name: "example.com", qtype: typeA, qclass: classIN}
+
+	queryLog.log(client, q, outcomeForwarded)
+	assert.Empty(t, queryLog.queries())
+
+	err := queryLog.setSettings(configuration.DNS{
+		QueryLog:     constants.DNSQueryLogAnonymized,
+		QueryLogFile: logFilepath,
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < maxRecentQueries+1; i++ {
+		queryLog.log(client, q, outcomeForwarded)
+	}
+	queryLog.log(client, q, outcomeBlocked)
+
+	queries := queryLog.queries()
+	require.Len(t, queries, maxRecentQueries)
+	assert.Equal(t, "192.168.1.0", queries[0].Client)
+	assert.Equal(t, outcomeBlocked, queries[maxRecentQueries-1].Outcome)
+
+	data, err := os.ReadFile(logFilepath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	assert.Len(t, lines, maxRecentQueries+2)
+	assert.Equal(t, "1970-01-01T00:00:00Z 192.168.1.0 example.com type 1 blocked", lines[len(lines)-1])
+
+	err = queryLog.setSettings(configuration.DNS{QueryLog: constants.DNSQueryLogNone})
+	require.NoError(t, err)
+	assert.Empty(t, queryLog.queries())
+}
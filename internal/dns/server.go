@@ -0,0 +1,407 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/golibs/logging"
+)
+
+const (
+	// queryTimeout is the maximum time to resolve a query.
+	queryTimeout = 10 * time.Second
+	// tcpIdleTimeout is the time after which an idle
+	// DNS over TCP client connection is closed.
+	tcpIdleTimeout = 10 * time.Second
+	// maxUDPQueries is the maximum number of UDP queries resolved
+	// concurrently, after which queries are left in the socket buffer
+	// until a query is resolved.
+	maxUDPQueries = 256
+)
+
+// server is a DNS server forwarding queries to DNS over TLS
+// or DNS over HTTPS servers, with caching and blocking.
+type server struct {
+	address  string
+	upstream *failover
+	cache    *cache // nil if caching is disabled
+	split    []splitRoute
+	local    localRecords
+	filter   *blockFilter
+	queryLog *queryLog
+	stats    *stats
+	dnssec   string
+	// stripECS is true to remove the EDNS client subnet
+	// options of queries sent to the upstream servers.
+	stripECS bool
+	// randomizeCase is true to randomize the case of the question
+	// name of queries sent to the upstream servers, and check it is
+	// kept in their responses.
+	randomizeCase bool
+	logger        logging.Logger
+}
+
+func newServer(settings configuration.DNS, local localRecords, cache *cache,
+	filter *blockFilter, queryLog *queryLog, stats *stats, logger logging.Logger) *server {
+	s := &server{
+		address:       settings.ListeningAddress,
+		local:         local,
+		split:         newSplitRoutes(settings.SplitRules),
+		filter:        filter,
+		queryLog:      queryLog,
+		stats:         stats,
+		dnssec:        settings.DNSSEC,
+		stripECS:      settings.StripECS,
+		randomizeCase: settings.CaseRandomization,
+		logger:        logger,
+	}
+	var servers []upstream
+	if settings.DoH {
+		servers = newDoHServers(settings.DoHServers, settings.IPv6)
+	} else {
+		servers = newDoTServers(settings.Providers, settings.IPv6)
+	}
+	priority := settings.UpstreamStrategy == constants.DNSUpstreamPriority
+	s.upstream = newFailover(servers, priority, logger)
+	if settings.Caching {
+		s.cache = cache
+	}
+	return s
+}
+
+// listen listens on UDP and TCP, such that the server is
+// ready to answer queries once this returns.
+func (s *server) listen() (udpConn net.PacketConn, tcpListener net.Listener, err error) {
+	udpConn, err = net.ListenPacket("udp", s.address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tcpListener, err = net.Listen("tcp", s.address)
+	if err != nil {
+		_ = udpConn.Close()
+		return nil, nil, err
+	}
+
+	return udpConn, tcpListener, nil
+}
+
+// serve answers queries until the context is canceled
+// or one of the listeners fails.
+func (s *server) serve(ctx context.Context, udpConn net.PacketConn,
+	tcpListener net.Listener) (err error) {
+	serveCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	healthChecksDone := make(chan struct{})
+	go func() {
+		defer close(healthChecksDone)
+		s.upstream.runHealthChecks(serveCtx)
+	}()
+
+	errs := make(chan error)
+	go func() { errs <- s.serveUDP(serveCtx, udpConn) }()
+	go func() { errs <- s.serveTCP(serveCtx, tcpListener) }()
+
+	running := 2
+	select {
+	case <-ctx.Done():
+	case err = <-errs:
+		running--
+	}
+	cancel()
+	_ = udpConn.Close()
+	_ = tcpListener.Close()
+	for ; running > 0; running-- {
+		<-errs
+	}
+	<-healthChecksDone
+	s.upstream.close()
+	return err
+}
+
+func (s *server) serveUDP(ctx context.Context, conn net.PacketConn) (err error) {
+	return serveUDPQueries(ctx, conn, maxUDPQueries,
+		func(ctx context.Context, query []byte, address net.Addr) {
+			response := s.resolve(ctx, query, address, true)
+			if response == nil {
+				return
+			}
+			if _, err := conn.WriteTo(response, address); err != nil && ctx.Err() == nil {
+				s.logger.Debug("cannot write response to %s: %s", address, err)
+			}
+		})
+}
+
+// serveUDPQueries reads queries from the connection and handles each
+// of them in its own goroutine, with at most maxQueries goroutines
+// running at the same time.
+func serveUDPQueries(ctx context.Context, conn net.PacketConn, maxQueries int,
+	handle func(ctx context.Context, query []byte, address net.Addr)) (err error) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	slots := make(chan struct{}, maxQueries)
+	buffer := make([]byte, 1<<16) //nolint:gomnd
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil
+		}
+
+		n, address, err := conn.ReadFrom(buffer)
+		if err != nil {
+			<-slots
+			if ctx.Err() != nil {
+				return nil
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Temporary() {
+				continue
+			}
+			return err
+		}
+
+		query := make([]byte, n)
+		copy(query, buffer[:n])
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			handle(ctx, query, address)
+		}()
+	}
+}
+
+func (s *server) serveTCP(ctx context.Context, listener net.Listener) (err error) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Temporary() {
+				continue
+			}
+			return err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.serveTCPConn(ctx, conn)
+		}()
+	}
+}
+
+// serveTCPConn answers the queries of the connection until the
+// connection is idle for too long or is closed by the client.
+func (s *server) serveTCPConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	for ctx.Err() == nil {
+		if err := conn.SetDeadline(time.Now().Add(tcpIdleTimeout)); err != nil {
+			return
+		}
+
+		query, err := readTCPMessage(conn)
+		if err != nil {
+			return
+		}
+
+		response := s.resolve(ctx, query, conn.RemoteAddr(), false)
+		if response == nil {
+			return
+		}
+
+		if err := conn.SetWriteDeadline(time.Now().Add(tcpIdleTimeout)); err != nil {
+			return
+		}
+		if err := writeTCPMessage(conn, response); err != nil {
+			return
+		}
+	}
+}
+
+// resolve returns the response to the query, or nil if
+// the query is invalid and should not be answered.
+func (s *server) resolve(ctx context.Context, query []byte,
+	client net.Addr, udp bool) (response []byte) {
+	q, questionEnd, err := parseQuestion(query)
+	if err != nil {
+		s.logger.Debug("invalid query: %s", err)
+		return nil
+	}
+
+	response, outcome := s.answer(ctx, q, query, questionEnd)
+	s.queryLog.log(client, q, outcome)
+	s.stats.addQuery(outcome)
+
+	if udp && len(response) > maxUDPResponseLength(query, questionEnd) {
+		return truncatedResponse(query, questionEnd)
+	}
+	return response
+}
+
+// answer returns the response to the query and how it was obtained.
+func (s *server) answer(ctx context.Context, q question, query []byte,
+	questionEnd int) (response []byte, outcome string) {
+	if rdatas, ok := s.local.answer(q); ok {
+		return answerResponse(query, questionEnd, q.qtype, localRecordTTL, rdatas), outcomeLocal
+	}
+
+	if splitServer := matchSplitRoute(s.split, q.name); splitServer != nil {
+		return s.forwardSplit(ctx, splitServer, q, query, questionEnd)
+	}
+
+	if s.filter.hostnameBlocked(q.name) {
+		s.logger.Debug("blocked %s", q)
+		return emptyResponse(query, questionEnd, rcodeNameError), outcomeBlocked
+	}
+
+	if response, ok := s.cachedResponse(q, query); ok {
+		return response, outcomeCached
+	} else if s.cache != nil {
+		s.stats.addCacheMiss()
+	}
+
+	return s.forward(ctx, q, query, questionEnd)
+}
+
+func (s *server) cachedResponse(q question, query []byte) (response []byte, ok bool) {
+	if s.cache == nil {
+		return nil, false
+	}
+	response, ok = s.cache.get(q)
+	if !ok {
+		return nil, false
+	}
+	response[0], response[1] = query[0], query[1] // set the ID of the query
+	return response, true
+}
+
+// forward forwards the query to the upstream servers, and caches
+// the response if it is not blocked and passed DNSSEC validation.
+func (s *server) forward(ctx context.Context, q question, query []byte,
+	questionEnd int) (response []byte, outcome string) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	upstreamQuery, ednsAdded := dnssecQuery(query, questionEnd, s.dnssec)
+	if s.stripECS {
+		upstreamQuery = stripClientSubnet(upstreamQuery, questionEnd)
+	}
+	if s.randomizeCase {
+		randomizeCase(upstreamQuery, questionEnd)
+	}
+	response, records, err := s.exchange(ctx, q, upstreamQuery)
+	if err != nil {
+		s.logger.Warn("cannot resolve %s: %s", q, err)
+		return emptyResponse(query, questionEnd, rcodeServerFailure), outcomeFailed
+	}
+
+	validated := true
+	if dnssecBogus(response, records) {
+		s.stats.addBogus()
+		if s.dnssec != constants.DNSSECPermissive {
+			s.logger.Warn("DNSSEC validation failed for %s", q)
+			return emptyResponse(query, questionEnd, rcodeServerFailure), outcomeBogus
+		}
+		s.logger.Warn("DNSSEC validation failed for %s, answering without validation", q)
+		validated = false
+		response, records, err = s.exchange(ctx, q, withCheckingDisabled(upstreamQuery))
+		if err != nil {
+			s.logger.Warn("cannot resolve %s: %s", q, err)
+			return emptyResponse(query, questionEnd, rcodeServerFailure), outcomeFailed
+		}
+	}
+
+	if s.randomizeCase { // restore the case of the query
+		copy(response[headerLength:questionEnd], query[headerLength:questionEnd])
+	}
+
+	if ednsAdded {
+		response, records = removeOPT(response, records)
+	}
+
+	if s.filter.responseBlocked(records) {
+		s.logger.Debug("blocked response IP address for %s", q)
+		return emptyResponse(query, questionEnd, rcodeNameError), outcomeBlocked
+	}
+
+	if s.filter.responseRebinding(q.name, records) {
+		s.logger.Info("refused private IP address for %s to prevent DNS rebinding", q)
+		return emptyResponse(query, questionEnd, rcodeNameError), outcomeBlocked
+	}
+
+	if s.cache != nil && validated {
+		switch responseCode(response) {
+		case rcodeSuccess, rcodeNameError:
+			s.cache.set(q, response, records)
+		}
+	}
+
+	return response, outcomeForwarded
+}
+
+// exchange sends the query to the upstream servers, and returns
+// the response and its records if it is valid for the question.
+func (s *server) exchange(ctx context.Context, q question, query []byte) (
+	response []byte, records []record, err error) {
+	start := time.Now()
+	response, err = s.upstream.exchange(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.stats.addUpstreamQuery(time.Since(start))
+
+	responseQuestion, responseQuestionEnd, err := parseQuestion(response)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrResponseInvalid, err)
+	} else if responseQuestion != q {
+		return nil, nil, fmt.Errorf("%w: question %s does not match", ErrResponseInvalid, responseQuestion)
+	} else if s.randomizeCase && !caseMatches(query, response, responseQuestionEnd) {
+		return nil, nil, fmt.Errorf("%w: question %s case does not match", ErrResponseInvalid, responseQuestion)
+	}
+
+	records, err = parseRecords(response, responseQuestionEnd)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrResponseInvalid, err)
+	}
+	return response, records, nil
+}
+
+// forwardSplit forwards the query to the plaintext DNS server of its
+// split DNS rule. The response is not filtered since it is expected
+// to contain private IP addresses.
+func (s *server) forwardSplit(ctx context.Context, splitServer *plainServer,
+	q question, query []byte, questionEnd int) (response []byte, outcome string) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	response, err := splitServer.exchange(ctx, query)
+	if err != nil {
+		s.logger.Warn("cannot resolve %s: %s", q, err)
+		return emptyResponse(query, questionEnd, rcodeServerFailure), outcomeFailed
+	}
+
+	responseQuestion, _, err := parseQuestion(response)
+	if err != nil || responseQuestion != q {
+		s.logger.Warn("invalid response for %s from %s", q, splitServer.address)
+		return emptyResponse(query, questionEnd, rcodeServerFailure), outcomeFailed
+	}
+
+	// The authentic data flag of a plaintext server cannot be trusted,
+	// see RFC 6840 section 5.7, so it is cleared from its response.
+	response[3] &^= adFlag
+
+	return response, outcomeSplit
+}
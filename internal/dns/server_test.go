@@ -0,0 +1,84 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_serveUDPQueries(t *testing.T) {
+	t.Parallel()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	require.NoError(t, err)
+	defer client.Close()
+
+	const maxQueries, queries = 2, 5
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	release := make(chan struct{})
+	handled := make(chan byte, queries)
+	handle := func(ctx context.Context, query []byte, address net.Addr) {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		<-release
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		handled <- query[0]
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- serveUDPQueries(ctx, conn, maxQueries, handle) }()
+
+	for i := 0; i < queries; i++ {
+		_, err := client.Write([]byte{byte(i)})
+		require.NoError(t, err)
+	}
+
+	getRunning := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return running
+	}
+	assert.Eventually(t, func() bool { return getRunning() == maxQueries },
+		time.Second, time.Millisecond)
+	// Give time to other queries to be wrongly handled
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, maxQueries, getRunning())
+
+	close(release)
+	received := make(map[byte]struct{}, queries)
+	timeout := time.After(time.Second)
+	for len(received) < queries {
+		select {
+		case b := <-handled:
+			received[b] = struct{}{}
+		case <-timeout:
+			t.Fatalf("only %d of %d queries handled", len(received), queries)
+		}
+	}
+
+	cancel()
+	_ = conn.Close()
+	assert.NoError(t, <-done)
+
+	mu.Lock()
+	assert.Equal(t, maxQueries, maxRunning)
+	mu.Unlock()
+}
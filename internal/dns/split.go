@@ -0,0 +1,111 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// splitRoute forwards the queries for a domain and its
+// subdomains to a plaintext DNS server, for split DNS.
+type splitRoute struct {
+	domain string
+	server *plainServer
+}
+
+// newSplitRoutes returns the split DNS routes sorted from
+// the most specific domain to the least specific domain.
+func newSplitRoutes(rules []models.DNSSplitRule) (routes []splitRoute) {
+	routes = make([]splitRoute, len(rules))
+	for i, rule := range rules {
+		address := net.JoinHostPort(rule.IP.String(), strconv.Itoa(int(rule.Port)))
+		routes[i] = splitRoute{
+			domain: rule.Domain,
+			server: &plainServer{address: address},
+		}
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		return len(routes[i].domain) > len(routes[j].domain)
+	})
+	return routes
+}
+
+// matchSplitRoute returns the server of the first route matching the
+// hostname, or nil if no route matches the hostname.
+func matchSplitRoute(routes []splitRoute, hostname string) (server *plainServer) {
+	for _, route := range routes {
+		if hostname == route.domain || strings.HasSuffix(hostname, "."+route.domain) {
+			return route.server
+		}
+	}
+	return nil
+}
+
+// plainServer is a plaintext DNS server queried over UDP,
+// and over TCP if its UDP response is truncated.
+type plainServer struct {
+	address string
+}
+
+func (s *plainServer) exchange(ctx context.Context, query []byte) (response []byte, err error) {
+	response, err = s.roundTrip(ctx, "udp", query)
+	if err != nil || !truncated(response) {
+		return response, err
+	}
+	return s.roundTrip(ctx, "tcp", query)
+}
+
+func (s *plainServer) roundTrip(ctx context.Context, network string, query []byte) (
+	response []byte, err error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, network, s.address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(exchangeTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	if network == "tcp" {
+		response, err = exchangeTCP(conn, query)
+	} else {
+		response, err = exchangeUDP(conn, query)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot exchange with %s: %w", s.address, err)
+	}
+	return response, nil
+}
+
+// exchangeUDP writes the query and reads the response,
+// ignoring responses not matching the query ID.
+func exchangeUDP(conn net.Conn, query []byte) (response []byte, err error) {
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+
+	buffer := make([]byte, maxMessageLength)
+	for {
+		n, err := conn.Read(buffer)
+		if err != nil {
+			return nil, err
+		}
+		if n >= headerLength && buffer[0] == query[0] && buffer[1] == query[1] {
+			response = make([]byte, n)
+			copy(response, buffer[:n])
+			return response, nil
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_matchSplitRoute(t *testing.T) {
+	t.Parallel()
+	routes := newSplitRoutes([]models.DNSSplitRule{
+		{Domain: "example.com", IP: net.IP{10, 0, 0, 1}, Port: 53},
+		{Domain: "corp.example.com", IP: net.IP{10, 0, 0, 2}, Port: 5353},
+	})
+	testCases := map[string]string{
+		"example.com":           "10.0.0.1:53",
+		"www.example.com":       "10.0.0.1:53",
+		"corp.example.com":      "10.0.0.2:5353",
+		"host.corp.example.com": "10.0.0.2:5353",
+		"myexample.com":         "",
+		"github.com":            "",
+	}
+	for hostname, address := range testCases {
+		hostname, address := hostname, address
+		t.Run(hostname, func(t *testing.T) {
+			t.Parallel()
+			server := matchSplitRoute(routes, hostname)
+			if address == "" {
+				assert.Nil(t, server)
+				return
+			}
+			assert.Equal(t, address, server.address)
+		})
+	}
+}
+
+func Test_server_forwardSplit(t *testing.T) {
+	t.Parallel()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+	go func() {
+		buffer := make([]byte, 512)
+		_, address, err := conn.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+		response := exampleResponse()
+		response[3] |= adFlag // claims the response is authenticated
+		_, _ = conn.WriteTo(response, address)
+	}()
+
+	s := &server{dnssec: constants.DNSSECUpstream}
+	splitServer := &plainServer{address: conn.LocalAddr().String()}
+	query := exampleQuery()
+	q, questionEnd, err := parseQuestion(query)
+	require.NoError(t, err)
+
+	response, outcome := s.forwardSplit(context.Background(), splitServer, q, query, questionEnd)
+
+	assert.Equal(t, outcomeSplit, outcome)
+	// the plaintext server is not trusted to validate DNSSEC
+	assert.Zero(t, response[3]&adFlag)
+	expected := exampleResponse()
+	assert.Equal(t, expected, response)
+}
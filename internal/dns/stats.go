@@ -0,0 +1,75 @@
+package dns
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// stats counts the queries answered by the DNS server.
+// Its fields are only accessed atomically, and are all
+// 64 bits for their alignment on 32 bit platforms.
+type stats struct {
+	queries          uint64
+	cacheHits        uint64
+	cacheMisses      uint64
+	blocked          uint64
+	failed           uint64
+	bogus            uint64
+	upstreamQueries  uint64
+	upstreamDuration uint64 // nanoseconds
+}
+
+func (s *stats) addQuery(outcome string) {
+	atomic.AddUint64(&s.queries, 1)
+	switch outcome {
+	case outcomeCached:
+		atomic.AddUint64(&s.cacheHits, 1)
+	case outcomeBlocked:
+		atomic.AddUint64(&s.blocked, 1)
+	case outcomeFailed:
+		atomic.AddUint64(&s.failed, 1)
+	}
+}
+
+func (s *stats) addCacheMiss() {
+	atomic.AddUint64(&s.cacheMisses, 1)
+}
+
+func (s *stats) addBogus() {
+	atomic.AddUint64(&s.bogus, 1)
+}
+
+func (s *stats) addUpstreamQuery(duration time.Duration) {
+	atomic.AddUint64(&s.upstreamQueries, 1)
+	atomic.AddUint64(&s.upstreamDuration, uint64(duration))
+}
+
+func (s *stats) get() (dnsStats models.DNSStats) {
+	dnsStats = models.DNSStats{
+		Queries:     atomic.LoadUint64(&s.queries),
+		CacheHits:   atomic.LoadUint64(&s.cacheHits),
+		CacheMisses: atomic.LoadUint64(&s.cacheMisses),
+		Blocked:     atomic.LoadUint64(&s.blocked),
+		Failed:      atomic.LoadUint64(&s.failed),
+		Bogus:       atomic.LoadUint64(&s.bogus),
+	}
+	upstreamQueries := atomic.LoadUint64(&s.upstreamQueries)
+	if upstreamQueries > 0 {
+		average := time.Duration(atomic.LoadUint64(&s.upstreamDuration) / upstreamQueries)
+		dnsStats.UpstreamLatency = average.Round(time.Millisecond).String()
+	}
+	return dnsStats
+}
+
+func (l *looper) GetStats() (dnsStats models.DNSStats) {
+	dnsStats = l.stats.get()
+	dnsStats.CacheEntries = l.cache.size()
+	return dnsStats
+}
+
+func (l *looper) FlushCache() (outcome string) {
+	l.cache.flush()
+	return "cache flushed"
+}
@@ -0,0 +1,33 @@
+package dns
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_stats(t *testing.T) {
+	t.Parallel()
+	s := &stats{}
+	assert.Equal(t, models.DNSStats{}, s.get())
+
+	s.addQuery(outcomeCached)
+	s.addCacheMiss()
+	s.addQuery(outcomeForwarded)
+	s.addUpstreamQuery(10 * time.Millisecond)
+	s.addQuery(outcomeBlocked)
+	s.addQuery(outcomeFailed)
+	s.addUpstreamQuery(20 * time.Millisecond)
+
+	expected := models.DNSStats{
+		Queries:         4,
+		CacheHits:       1,
+		CacheMisses:     1,
+		Blocked:         1,
+		Failed:          1,
+		UpstreamLatency: "15ms",
+	}
+	assert.Equal(t, expected, s.get())
+}
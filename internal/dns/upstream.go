@@ -0,0 +1,167 @@
+package dns
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/qdm12/dns/pkg/unbound"
+)
+
+const (
+	dotPort         = "853"
+	dialTimeout     = 5 * time.Second
+	exchangeTimeout = 5 * time.Second
+	// maxIdleConnections is the maximum number of idle connections
+	// kept open to each DNS over TLS server.
+	maxIdleConnections = 4
+)
+
+var (
+	ErrNoUpstreamServer = errors.New("no upstream DNS server")
+	ErrResponseInvalid  = errors.New("response is invalid")
+)
+
+// upstream is an encrypted DNS server to forward queries to.
+type upstream interface {
+	exchange(ctx context.Context, query []byte) (response []byte, err error)
+	// close closes the idle connections to the server.
+	close()
+	String() string
+}
+
+// dotServer is a DNS over TLS server, keeping its
+// connections open to send multiple queries on them.
+type dotServer struct {
+	address string // ip:853
+	name    string // TLS server name
+	idle    chan net.Conn
+}
+
+func newDoTServers(providers []string, ipv6 bool) (servers []upstream) {
+	for _, provider := range providers {
+		data, _ := unbound.GetProviderData(provider)
+		for _, ip := range data.IPs {
+			if ip.To4() == nil && !ipv6 {
+				continue
+			}
+			servers = append(servers, &dotServer{
+				address: net.JoinHostPort(ip.String(), dotPort),
+				name:    string(data.Host),
+				idle:    make(chan net.Conn, maxIdleConnections),
+			})
+		}
+	}
+	return servers
+}
+
+func (s *dotServer) String() string {
+	return s.name + " (" + s.address + ")"
+}
+
+func (s *dotServer) close() {
+	for {
+		select {
+		case conn := <-s.idle:
+			_ = conn.Close()
+		default:
+			return
+		}
+	}
+}
+
+func (s *dotServer) exchange(ctx context.Context, query []byte) (response []byte, err error) {
+	select {
+	case conn := <-s.idle:
+		response, err = s.roundTrip(ctx, conn, query)
+		if err == nil {
+			return response, nil
+		}
+		// the idle connection may have been closed by the server,
+		// so retry with a new connection.
+	default:
+	}
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: dialTimeout},
+		Config: &tls.Config{
+			ServerName: s.name,
+			MinVersion: tls.VersionTLS12,
+		},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", s.address)
+	if err != nil {
+		return nil, err
+	}
+	return s.roundTrip(ctx, conn, query)
+}
+
+// roundTrip sends the query and reads the response on the connection,
+// which is then kept idle for another query or closed on error.
+func (s *dotServer) roundTrip(ctx context.Context, conn net.Conn, query []byte) (
+	response []byte, err error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(exchangeTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	response, err = exchangeTCP(conn, query)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("cannot exchange with %s: %w", s.address, err)
+	}
+
+	select {
+	case s.idle <- conn:
+	default:
+		_ = conn.Close()
+	}
+	return response, nil
+}
+
+// exchangeTCP writes the query and reads the response, both prefixed
+// with their 2 bytes length as for DNS over TCP.
+func exchangeTCP(conn net.Conn, query []byte) (response []byte, err error) {
+	if err := writeTCPMessage(conn, query); err != nil {
+		return nil, err
+	}
+
+	response, err = readTCPMessage(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(response) < headerLength || response[0] != query[0] || response[1] != query[1] {
+		return nil, fmt.Errorf("%w: ID does not match the query ID", ErrResponseInvalid)
+	}
+	return response, nil
+}
+
+func writeTCPMessage(w io.Writer, message []byte) (err error) {
+	buffer := make([]byte, 2+len(message)) //nolint:gomnd
+	binary.BigEndian.PutUint16(buffer, uint16(len(message)))
+	copy(buffer[2:], message)
+	_, err = w.Write(buffer)
+	return err
+}
+
+func readTCPMessage(r io.Reader) (message []byte, err error) {
+	var lengthBytes [2]byte
+	if _, err := io.ReadFull(r, lengthBytes[:]); err != nil {
+		return nil, err
+	}
+	message = make([]byte, binary.BigEndian.Uint16(lengthBytes[:]))
+	if _, err := io.ReadFull(r, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
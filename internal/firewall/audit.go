@@ -0,0 +1,91 @@
+package firewall
+
+import (
+	"context"
+	"strings"
+)
+
+// SetAudit sets the firewall in audit mode, where the rules are only
+// logged instead of being applied.
+func (c *configurator) SetAudit() {
+	c.audit = true
+}
+
+// logConflicts logs the existing host rules which may conflict with
+// the firewall rules, for example rules dropping packets.
+func (c *configurator) logConflicts(ctx context.Context) {
+	conflicts, err := c.rules.listConflicts(ctx)
+	if err != nil {
+		c.logger.Warn("audit: cannot list existing rules: %s", err)
+		return
+	}
+	if len(conflicts) == 0 {
+		c.logger.Info("audit: no existing rule conflicting found")
+		return
+	}
+	for _, conflict := range conflicts {
+		c.logger.Warn("audit: existing rule may conflict: %s", conflict)
+	}
+}
+
+func (c *configurator) listConflicts(ctx context.Context) (conflicts []string, err error) {
+	programs := []string{"iptables"}
+	if c.ip6Tables {
+		programs = append(programs, "ip6tables")
+	}
+	for _, program := range programs {
+		output, err := c.commander.Run(ctx, program, "-S")
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(output, "\n") {
+			if iptablesRuleConflicts(line) {
+				conflicts = append(conflicts, program+" "+line)
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+// iptablesRuleConflicts returns true if the iptables -S line given
+// drops or rejects packets.
+func iptablesRuleConflicts(line string) bool {
+	fields := strings.Fields(line)
+	const policyFields = 3
+	switch {
+	case len(fields) == 0:
+		return false
+	case fields[0] == "-P":
+		return len(fields) == policyFields && fields[2] == "DROP"
+	case fields[0] == "-A":
+		return strings.Contains(line, "-j DROP") || strings.Contains(line, "-j REJECT")
+	}
+	return false
+}
+
+func (n *nftables) listConflicts(ctx context.Context) (conflicts []string, err error) {
+	output, err := n.commander.Run(ctx, "nft", "list", "ruleset")
+	if err != nil {
+		return nil, err
+	}
+	table := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "table ") {
+			table = strings.TrimSuffix(line, " {")
+			continue
+		}
+		if nftablesRuleConflicts(line) {
+			conflicts = append(conflicts, table+": "+line)
+		}
+	}
+	return conflicts, nil
+}
+
+// nftablesRuleConflicts returns true if the nft list ruleset line given
+// drops or rejects packets.
+func nftablesRuleConflicts(line string) bool {
+	return strings.Contains(line, "policy drop;") ||
+		strings.HasSuffix(line, " drop") || line == "drop" ||
+		strings.Contains(line, " reject")
+}
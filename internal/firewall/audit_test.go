@@ -0,0 +1,23 @@
+package firewall
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_iptablesRuleConflicts(t *testing.T) {
+	t.Parallel()
+	assert.True(t, iptablesRuleConflicts("-P OUTPUT DROP"))
+	assert.True(t, iptablesRuleConflicts("-A INPUT -p tcp --dport 22 -j REJECT --reject-with icmp-port-unreachable"))
+	assert.False(t, iptablesRuleConflicts("-P INPUT ACCEPT"))
+	assert.False(t, iptablesRuleConflicts("-A INPUT -i lo -j ACCEPT"))
+}
+
+func Test_nftablesRuleConflicts(t *testing.T) {
+	t.Parallel()
+	assert.True(t, nftablesRuleConflicts("type filter hook input priority filter; policy drop;"))
+	assert.True(t, nftablesRuleConflicts(`iifname "eth0" tcp dport 22 drop`))
+	assert.True(t, nftablesRuleConflicts(`ip daddr 1.2.3.4 reject with icmp type port-unreachable`))
+	assert.False(t, nftablesRuleConflicts(`iifname "lo" accept`))
+}
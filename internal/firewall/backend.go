@@ -0,0 +1,95 @@
+package firewall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+var (
+	ErrBackendUnknown      = errors.New("unknown firewall backend")
+	ErrBackendNotSupported = errors.New("firewall backend not supported")
+)
+
+// rules applies the firewall rules, and is implemented by the
+// configurator itself for iptables and by nftables for nftables.
+type rules interface {
+	clearAllRules(ctx context.Context) error
+	setIPv4AllPolicies(ctx context.Context, policy string) error
+	setIPv6AllPolicies(ctx context.Context, policy string) error
+	acceptInputThroughInterface(ctx context.Context, intf string, remove bool) error
+	acceptInputToSubnet(ctx context.Context, intf string, destination net.IPNet, remove bool) error
+	acceptOutputThroughInterface(ctx context.Context, intf string, remove bool) error
+	acceptEstablishedRelatedTraffic(ctx context.Context, remove bool) error
+	acceptOutputTrafficToVPN(ctx context.Context,
+		defaultInterface string, connection models.OpenVPNConnection, remove bool) error
+	acceptOutputFromIPToSubnet(ctx context.Context,
+		intf string, sourceIP net.IP, destinationSubnet net.IPNet, remove bool) error
+	acceptOutputFromIPToSubnetPort(ctx context.Context, intf string, sourceIP net.IP,
+		destinationSubnet net.IPNet, protocol string, port uint16, remove bool) error
+	acceptInputToPort(ctx context.Context, intf string, port uint16, remove bool) error
+	acceptInputFromSubnetToPort(ctx context.Context, intf string, source net.IPNet, port uint16, remove bool) error
+	acceptNeighborDiscovery(ctx context.Context, intf string, remove bool) error
+	acceptMulticastDNS(ctx context.Context, intf string, remove bool) error
+	markInputToPort(ctx context.Context, intf string, port uint16, remove bool) error
+	restoreConnectionMark(ctx context.Context, remove bool) error
+	redirectInputPort(ctx context.Context, intf string, redirection models.PortRedirection, remove bool) error
+	redirectFromSubnetToPort(ctx context.Context, intf string, source net.IPNet,
+		port, targetPort uint16, remove bool) error
+	markOutputThroughInterface(ctx context.Context, intf string, remove bool) error
+	acceptForwardFromSubnet(ctx context.Context, intf string, source net.IPNet, remove bool) error
+	masqueradeFromSubnet(ctx context.Context, intf string, source net.IPNet, remove bool) error
+	markFromSubnet(ctx context.Context, source net.IPNet, remove bool) error
+	markOutputFromUID(ctx context.Context, uid int, remove bool) error
+	acceptOutputFromUID(ctx context.Context, intf string, uid int, remove bool) error
+	masqueradeBypassed(ctx context.Context, intf string, remove bool) error
+	logDroppedPackets(ctx context.Context, remove bool) error
+	runUserPostRules(ctx context.Context, filepath string, remove bool) error
+	listRules(ctx context.Context) (rules string, err error)
+	listCounters(ctx context.Context) (counters string, err error)
+	tampered(ctx context.Context) (tampered bool, err error)
+	listConflicts(ctx context.Context) (conflicts []string, err error)
+}
+
+func (c *configurator) SetBackend(ctx context.Context, backend string) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if backend == constants.FirewallBackendAuto {
+		backend = c.detectBackend(ctx)
+	}
+
+	switch backend {
+	case constants.IPTables:
+		c.rules = c
+	case constants.NFTables:
+		if !nftablesSupported(ctx, c.commander) {
+			return fmt.Errorf("%w: %s", ErrBackendNotSupported, backend)
+		}
+		c.rules = newNFTables(c.commander, c.openFile, c.logger, &c.debug, &c.audit)
+	default:
+		return fmt.Errorf("%w: %s", ErrBackendUnknown, backend)
+	}
+
+	c.logger.Info("using %s backend", backend)
+	return nil
+}
+
+// detectBackend returns nftables if iptables is not available or is
+// itself using nftables, and nftables is available. Otherwise it
+// returns iptables.
+func (c *configurator) detectBackend(ctx context.Context) (backend string) {
+	if !nftablesSupported(ctx, c.commander) {
+		return constants.IPTables
+	}
+	output, err := c.commander.Run(ctx, "iptables", "--version")
+	if err != nil || strings.Contains(output, "nf_tables") {
+		return constants.NFTables
+	}
+	return constants.IPTables
+}
@@ -0,0 +1,57 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+// SetFlushConntrack sets whether the connection tracking entries of the
+// connections through the VPN interface are flushed when the tunnel goes down.
+func (c *configurator) SetFlushConntrack(flush bool) {
+	c.flushConntrack = flush
+}
+
+// FlushTunnelConnections deletes the connection tracking entries of the
+// connections through the VPN interface, so the connections of a previous
+// tunnel do not keep using stale NAT mappings or routes.
+func (c *configurator) FlushTunnelConnections(ctx context.Context) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if !c.enabled || !c.flushConntrack || c.audit {
+		return nil
+	}
+
+	families := []string{"ipv4"}
+	if c.IPv6Supported() {
+		families = append(families, "ipv6")
+	}
+	mark := strconv.Itoa(int(constants.TunnelMark))
+	for _, family := range families {
+		output, err := c.commander.Run(ctx, "conntrack", "-D", "-f", family, "--mark", mark)
+		// conntrack exits with an error if no entry is deleted
+		if err != nil && !strings.Contains(output, "0 flow entries") {
+			return fmt.Errorf("cannot flush tunnel connections: %s: %w", output, err)
+		}
+	}
+	return nil
+}
+
+// markOutputThroughInterface marks the connections through the interface.
+func (c *configurator) markOutputThroughInterface(ctx context.Context, intf string, remove bool) error {
+	return c.runMixedIptablesInstruction(ctx, fmt.Sprintf(
+		"%s POSTROUTING --table mangle -o %s -j CONNMARK --set-mark %d",
+		appendOrDelete(remove), intf, constants.TunnelMark,
+	))
+}
+
+// markOutputThroughInterface marks the connections through the interface,
+// in the NAT chain which only sees the first packet of each connection.
+func (n *nftables) markOutputThroughInterface(ctx context.Context, intf string, remove bool) error {
+	rule := fmt.Sprintf("oifname %s ct mark set %d", nftablesInterface(intf), constants.TunnelMark)
+	return n.setRule(ctx, "postrouting", rule, remove)
+}
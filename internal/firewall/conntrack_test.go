@@ -0,0 +1,158 @@
+package firewall
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/golibs/command/mock_command"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_configurator_FlushTunnelConnections(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	type command struct {
+		family string
+		output string
+		err    error
+	}
+
+	testCases := map[string]struct {
+		disabled       bool
+		flushConntrack bool
+		audit          bool
+		ip6Tables      bool
+		commands       []command
+		errMessage     string
+	}{
+		"firewall disabled": {
+			disabled:       true,
+			flushConntrack: true,
+		},
+		"flush disabled": {},
+		"audit mode": {
+			flushConntrack: true,
+			audit:          true,
+		},
+		"ipv4 only": {
+			flushConntrack: true,
+			commands:       []command{{family: "ipv4"}},
+		},
+		"ipv4 and ipv6": {
+			flushConntrack: true,
+			ip6Tables:      true,
+			commands:       []command{{family: "ipv4"}, {family: "ipv6"}},
+		},
+		"no entry deleted": {
+			flushConntrack: true,
+			commands: []command{{
+				family: "ipv4",
+				output: "conntrack v1.4.6 (conntrack-tools): 0 flow entries have been deleted.",
+				err:    errTest,
+			}},
+		},
+		"command error": {
+			flushConntrack: true,
+			ip6Tables:      true,
+			commands: []command{{
+				family: "ipv4",
+				output: "conntrack: invalid option",
+				err:    errTest,
+			}},
+			errMessage: "cannot flush tunnel connections: conntrack: invalid option: test error",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+
+			commander := mock_command.NewMockCommander(ctrl)
+			calls := make([]*gomock.Call, len(testCase.commands))
+			for i, command := range testCase.commands {
+				calls[i] = commander.EXPECT().
+					Run(ctx, "conntrack", "-D", "-f", command.family, "--mark", "7632238").
+					Return(command.output, command.err)
+			}
+			gomock.InOrder(calls...)
+
+			c := &configurator{
+				commander:      commander,
+				enabled:        !testCase.disabled,
+				flushConntrack: testCase.flushConntrack,
+				audit:          testCase.audit,
+				ip6Tables:      testCase.ip6Tables,
+			}
+			c.rules = c
+
+			err := c.FlushTunnelConnections(ctx)
+
+			if testCase.errMessage != "" {
+				require.Error(t, err)
+				assert.Equal(t, testCase.errMessage, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_configurator_SetVPNInterface_marking(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		flushConntrack bool
+		rules          []string
+	}{
+		"without conntrack flush": {
+			rules: []string{
+				"OUTPUT -o tun1 -j ACCEPT",
+			},
+		},
+		"with conntrack flush": {
+			flushConntrack: true,
+			rules: []string{
+				"OUTPUT -o tun1 -j ACCEPT",
+				"POSTROUTING --table mangle -o tun1 -j CONNMARK --set-mark 7632238",
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+
+			logger := mock_logging.NewMockLogger(ctrl)
+			logger.EXPECT().Info("setting VPN interface %s through firewall...", "tun1")
+			commander := mock_command.NewMockCommander(ctrl)
+			commander.EXPECT().Run(ctx, "iptables", gomock.Any()).
+				Return("", nil).AnyTimes()
+
+			c := &configurator{
+				commander:      commander,
+				logger:         logger,
+				enabled:        true,
+				flushConntrack: testCase.flushConntrack,
+				vpnIntf:        "tun0",
+			}
+			c.rules = c
+
+			err := c.SetVPNInterface(ctx, "tun1")
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.rules, c.ipv4State.rules)
+		})
+	}
+}
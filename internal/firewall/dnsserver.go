@@ -0,0 +1,55 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// SetDNSServerPort accepts DNS queries to the port given from the outbound
+// subnets through the default interface, so LAN clients can use the DNS
+// server. A port of 0 removes these rules.
+func (c *configurator) SetDNSServerPort(ctx context.Context, port uint16) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if c.lockedDown {
+		return ErrLockedDown
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating DNS server port internal state")
+		c.dnsServerPort = port
+		return nil
+	}
+
+	if c.dnsServerPort == port {
+		return nil
+	}
+
+	c.logger.Info("setting DNS server port through firewall...")
+
+	const remove = true
+	for _, subnet := range c.outboundSubnets {
+		if err := c.acceptDNSFromSubnet(ctx, subnet, remove); err != nil {
+			c.logger.Error("cannot remove outdated DNS server port through firewall: %s", err)
+		}
+	}
+	c.dnsServerPort = port
+	for _, subnet := range c.outboundSubnets {
+		if err := c.acceptDNSFromSubnet(ctx, subnet, !remove); err != nil {
+			return fmt.Errorf("cannot set DNS server port through firewall: %w", err)
+		}
+	}
+	c.moveLogDroppedLast(ctx)
+	return nil
+}
+
+// acceptDNSFromSubnet accepts DNS queries from the subnet to the
+// DNS server port, if the DNS server port is set.
+func (c *configurator) acceptDNSFromSubnet(ctx context.Context, subnet net.IPNet, remove bool) error {
+	if c.dnsServerPort == 0 {
+		return nil
+	}
+	return c.rules.acceptInputFromSubnetToPort(ctx, c.defaultInterface, subnet, c.dnsServerPort, remove)
+}
@@ -4,8 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-
-	"github.com/qdm12/gluetun/internal/constants"
 )
 
 var (
@@ -18,6 +16,10 @@ func (c *configurator) SetEnabled(ctx context.Context, enabled bool) (err error)
 	c.stateMutex.Lock()
 	defer c.stateMutex.Unlock()
 
+	if c.lockedDown {
+		return ErrLockedDown
+	}
+
 	if enabled == c.enabled {
 		if enabled {
 			c.logger.Info("already enabled")
@@ -49,13 +51,13 @@ func (c *configurator) SetEnabled(ctx context.Context, enabled bool) (err error)
 }
 
 func (c *configurator) disable(ctx context.Context) (err error) {
-	if err = c.clearAllRules(ctx); err != nil {
+	if err = c.rules.clearAllRules(ctx); err != nil {
 		return fmt.Errorf("cannot disable firewall: %w", err)
 	}
-	if err = c.setIPv4AllPolicies(ctx, "ACCEPT"); err != nil {
+	if err = c.rules.setIPv4AllPolicies(ctx, "ACCEPT"); err != nil {
 		return fmt.Errorf("cannot disable firewall: %w", err)
 	}
-	if err = c.setIPv6AllPolicies(ctx, "ACCEPT"); err != nil {
+	if err = c.rules.setIPv6AllPolicies(ctx, "ACCEPT"); err != nil {
 		return fmt.Errorf("cannot disable firewall: %w", err)
 	}
 	return nil
@@ -72,73 +74,161 @@ func (c *configurator) fallbackToDisabled(ctx context.Context) {
 }
 
 func (c *configurator) enable(ctx context.Context) (err error) {
+	if c.audit {
+		c.logConflicts(ctx)
+	}
+
+	ipv6Enabled, err := c.routing.IPv6Supported()
+	if err != nil {
+		return fmt.Errorf("cannot enable firewall: %w", err)
+	} else if ipv6Enabled && !c.IPv6Supported() {
+		// IPv6 traffic would not be blocked and could leak outside the VPN
+		return fmt.Errorf("cannot enable firewall: IPv6 is enabled: %w", ErrNeedIP6Tables)
+	}
+
 	touched := false
-	if err = c.setIPv4AllPolicies(ctx, "DROP"); err != nil {
+	if err = c.rules.setIPv4AllPolicies(ctx, "DROP"); err != nil {
 		return fmt.Errorf("cannot enable firewall: %w", err)
 	}
 	touched = true
 
-	if err = c.setIPv6AllPolicies(ctx, "DROP"); err != nil {
+	if err = c.rules.setIPv6AllPolicies(ctx, "DROP"); err != nil {
 		return fmt.Errorf("cannot enable firewall: %w", err)
 	}
 
-	const remove = false
-
 	defer func() {
 		if touched && err != nil {
 			c.fallbackToDisabled(ctx)
 		}
 	}()
 
+	return c.applyRules(ctx, ipv6Enabled)
+}
+
+// applyRules appends all the rules accepting traffic, without changing
+// the policies of the chains.
+func (c *configurator) applyRules(ctx context.Context, ipv6Enabled bool) (err error) {
+	const remove = false
+
 	// Loopback traffic
-	if err = c.acceptInputThroughInterface(ctx, "lo", remove); err != nil {
+	if err = c.rules.acceptInputThroughInterface(ctx, "lo", remove); err != nil {
 		return fmt.Errorf("cannot enable firewall: %w", err)
 	}
-	if err = c.acceptOutputThroughInterface(ctx, "lo", remove); err != nil {
+	if err = c.rules.acceptOutputThroughInterface(ctx, "lo", remove); err != nil {
 		return fmt.Errorf("cannot enable firewall: %w", err)
 	}
 
-	if err = c.acceptEstablishedRelatedTraffic(ctx, remove); err != nil {
+	if err = c.rules.acceptEstablishedRelatedTraffic(ctx, remove); err != nil {
 		return fmt.Errorf("cannot enable firewall: %w", err)
 	}
 	if c.vpnConnection.IP != nil {
-		if err = c.acceptOutputTrafficToVPN(ctx, c.defaultInterface, c.vpnConnection, remove); err != nil {
+		if err = c.rules.acceptOutputTrafficToVPN(ctx, c.defaultInterface, c.vpnConnection, remove); err != nil {
 			return fmt.Errorf("cannot enable firewall: %w", err)
 		}
 	}
-	if err = c.acceptOutputThroughInterface(ctx, string(constants.TUN), remove); err != nil {
+	if c.nextVPNConnection.IP != nil {
+		if err = c.rules.acceptOutputTrafficToVPN(ctx, c.defaultInterface, c.nextVPNConnection, remove); err != nil {
+			return fmt.Errorf("cannot enable firewall: %w", err)
+		}
+	}
+	if c.resolverConnection.IP != nil {
+		if err = c.rules.acceptOutputTrafficToVPN(ctx, c.defaultInterface, c.resolverConnection, remove); err != nil {
+			return fmt.Errorf("cannot enable firewall: %w", err)
+		}
+	}
+	if err = c.rules.acceptOutputThroughInterface(ctx, c.vpnIntf, remove); err != nil {
 		return fmt.Errorf("cannot enable firewall: %w", err)
 	}
+	if c.flushConntrack {
+		if err = c.rules.markOutputThroughInterface(ctx, c.vpnIntf, remove); err != nil {
+			return fmt.Errorf("cannot enable firewall: %w", err)
+		}
+	}
 
 	for _, network := range c.localNetworks {
-		if err := c.acceptOutputFromIPToSubnet(ctx, network.InterfaceName, network.IP, *network.IPNet, remove); err != nil {
+		if err := c.rules.acceptOutputFromIPToSubnet(ctx, network.InterfaceName, network.IP, *network.IPNet, remove); err != nil {
 			return fmt.Errorf("cannot enable firewall: %w", err)
 		}
 	}
 
 	for _, subnet := range c.outboundSubnets {
-		if err := c.acceptOutputFromIPToSubnet(ctx, c.defaultInterface, c.localIP, subnet, remove); err != nil {
+		if err := c.rules.acceptOutputFromIPToSubnet(ctx, c.defaultInterface, c.localIP, subnet, remove); err != nil {
 			return fmt.Errorf("cannot enable firewall: %w", err)
 		}
+		if err := c.acceptDNSFromSubnet(ctx, subnet, remove); err != nil {
+			return fmt.Errorf("cannot enable firewall: %w", err)
+		}
+	}
+
+	if err := c.setOutboundRules(ctx, c.outboundRules, remove); err != nil {
+		return fmt.Errorf("cannot enable firewall: %w", err)
+	}
+
+	if err := c.setOutboundRules(ctx, c.bootstrapRules, remove); err != nil {
+		return fmt.Errorf("cannot enable firewall: %w", err)
 	}
 
 	// Allows packets from any IP address to go through eth0 / local network
 	// to reach Gluetun.
 	for _, network := range c.localNetworks {
-		if err := c.acceptInputToSubnet(ctx, network.InterfaceName, *network.IPNet, remove); err != nil {
+		if err := c.rules.acceptInputToSubnet(ctx, network.InterfaceName, *network.IPNet, remove); err != nil {
+			return fmt.Errorf("cannot enable firewall: %w", err)
+		}
+	}
+
+	if ipv6Enabled {
+		// IPv6 neighbors must be reachable on the local network, notably
+		// to reach the IPv6 gateway.
+		if err := c.rules.acceptNeighborDiscovery(ctx, c.defaultInterface, remove); err != nil {
+			return fmt.Errorf("cannot enable firewall: %w", err)
+		}
+	}
+
+	if c.multicastDNS {
+		if err := c.rules.acceptMulticastDNS(ctx, c.defaultInterface, remove); err != nil {
 			return fmt.Errorf("cannot enable firewall: %w", err)
 		}
 	}
 
 	for port, intf := range c.allowedInputPorts {
-		if err := c.acceptInputToPort(ctx, intf, port, remove); err != nil {
+		if err := c.rules.acceptInputToPort(ctx, intf, port, remove); err != nil {
 			return fmt.Errorf("cannot enable firewall: %w", err)
 		}
 	}
 
-	if err := c.runUserPostRules(ctx, "/iptables/post-rules.txt", remove); err != nil {
+	if err := c.setLANPorts(ctx, c.lanPorts, remove); err != nil {
+		return fmt.Errorf("cannot enable firewall: %w", err)
+	}
+
+	if err := c.setPortRedirections(ctx, c.redirectionsIntf, c.redirections, remove); err != nil {
+		return fmt.Errorf("cannot enable firewall: %w", err)
+	}
+
+	if err := c.setVPNSources(ctx, c.vpnIntf, c.vpnSources, remove); err != nil {
+		return fmt.Errorf("cannot enable firewall: %w", err)
+	}
+
+	if err := c.setBypassSources(ctx, c.bypassSources, remove); err != nil {
+		return fmt.Errorf("cannot enable firewall: %w", err)
+	}
+
+	if err := c.setBypassUIDs(ctx, c.bypassUIDs, remove); err != nil {
+		return fmt.Errorf("cannot enable firewall: %w", err)
+	}
+
+	if err := c.setTransparentProxy(ctx, c.transparentProxy, remove); err != nil {
+		return fmt.Errorf("cannot enable firewall: %w", err)
+	}
+
+	if err := c.rules.runUserPostRules(ctx, c.postRulesFilepath, remove); err != nil {
 		return fmt.Errorf("%w: %s", ErrUserPostRules, err)
 	}
 
+	if c.logDropped {
+		if err := c.rules.logDroppedPackets(ctx, remove); err != nil {
+			return fmt.Errorf("cannot enable firewall: %w", err)
+		}
+	}
+
 	return nil
 }
@@ -6,7 +6,9 @@ import (
 	"context"
 	"net"
 	"sync"
+	"time"
 
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/routing"
 	"github.com/qdm12/golibs/command"
@@ -14,62 +16,126 @@ import (
 	"github.com/qdm12/golibs/os"
 )
 
+//go:generate mockgen -destination=mock_$GOPACKAGE/$GOFILE . Configurator
+
 // Configurator allows to change firewall rules and modify network routes.
 type Configurator interface {
 	Version(ctx context.Context) (string, error)
 	SetEnabled(ctx context.Context, enabled bool) (err error)
+	Lockdown(ctx context.Context) (err error)
 	SetVPNConnection(ctx context.Context, connection models.OpenVPNConnection) (err error)
+	SetNextVPNConnection(ctx context.Context, connection models.OpenVPNConnection) (err error)
+	SetResolverConnection(ctx context.Context, connection models.OpenVPNConnection) (err error)
+	SetVPNInterface(ctx context.Context, intf string) (err error)
 	SetAllowedPort(ctx context.Context, port uint16, intf string) (err error)
+	SetLANPorts(ctx context.Context, ports []uint16) (err error)
+	SetPortRedirections(ctx context.Context, intf string, redirections []models.PortRedirection) (err error)
+	SetDNSServerPort(ctx context.Context, port uint16) (err error)
+	SetMulticastDNS(ctx context.Context, enabled bool) (err error)
+	SetForwardedSources(ctx context.Context, vpnSources, bypassSources []net.IPNet) (err error)
+	SetBypassUIDs(ctx context.Context, uids []int) (err error)
+	SetTransparentProxy(ctx context.Context, sources []net.IPNet, ports []uint16, listeningPort uint16) (err error)
 	SetOutboundSubnets(ctx context.Context, subnets []net.IPNet) (err error)
+	SetOutboundRules(ctx context.Context, rules []models.OutboundRule) (err error)
+	SetBootstrapRules(ctx context.Context, rules []models.OutboundRule) (err error)
 	RemoveAllowedPort(ctx context.Context, port uint16) (err error)
+	IPv6Supported() (supported bool)
+	GetEnabled() (enabled bool)
+	GetAllowedPorts() (ports map[uint16]string)
+	GetVPNInterface() (intf string)
+	GetRules(ctx context.Context) (rules string, err error)
+	GetCounters(ctx context.Context) (counters string, err error)
+	Verify(ctx context.Context) (err error)
+	RunVerifyTicker(ctx context.Context, wg *sync.WaitGroup, period time.Duration)
 	SetDebug()
+	// SetAudit is meant to be called only once, before enabling the firewall
+	SetAudit()
+	// SetFlushConntrack is meant to be called only once, before enabling the firewall
+	SetFlushConntrack(flush bool)
+	FlushTunnelConnections(ctx context.Context) (err error)
+	// SetLogDropped is meant to be called only once, before enabling the firewall
+	SetLogDropped(logDropped bool)
+	// SetPostRulesFilepath is meant to be called only once, before enabling the firewall
+	SetPostRulesFilepath(filepath string)
+	// SetBackend is meant to be called only once, before enabling the firewall
+	SetBackend(ctx context.Context, backend string) (err error)
 	// SetNetworkInformation is meant to be called only once
 	SetNetworkInformation(defaultInterface string, defaultGateway net.IP,
 		localNetworks []routing.LocalNetwork, localIP net.IP)
 }
 
 type configurator struct { //nolint:maligned
-	commander        command.Commander
-	logger           logging.Logger
-	routing          routing.Routing
-	openFile         os.OpenFileFunc // for custom iptables rules
-	iptablesMutex    sync.Mutex
-	ip6tablesMutex   sync.Mutex
-	debug            bool
-	defaultInterface string
-	defaultGateway   net.IP
-	localNetworks    []routing.LocalNetwork
-	localIP          net.IP
-	networkInfoMutex sync.Mutex
+	commander         command.Commander
+	rules             rules // iptables or nftables
+	logger            logging.Logger
+	routing           routing.Routing
+	openFile          os.OpenFileFunc // for custom iptables rules
+	postRulesFilepath string
+	iptablesMutex     sync.Mutex
+	ip6tablesMutex    sync.Mutex
+	ipv4State         iptablesState // protected by iptablesMutex
+	ipv6State         iptablesState // protected by ip6tablesMutex
+	debug             bool
+	audit             bool // only log rules
+	flushConntrack    bool
+	logDropped        bool
+	defaultInterface  string
+	defaultGateway    net.IP
+	localNetworks     []routing.LocalNetwork
+	localIP           net.IP
+	networkInfoMutex  sync.Mutex
 
 	// Fixed state
 	ip6Tables bool
 
 	// State
-	enabled           bool
-	vpnConnection     models.OpenVPNConnection
-	outboundSubnets   []net.IPNet
-	allowedInputPorts map[uint16]string // port to interface mapping
-	stateMutex        sync.Mutex
+	enabled            bool
+	lockedDown         bool
+	vpnConnection      models.OpenVPNConnection
+	nextVPNConnection  models.OpenVPNConnection
+	resolverConnection models.OpenVPNConnection
+	vpnIntf            string
+	outboundSubnets    []net.IPNet
+	outboundRules      []models.OutboundRule
+	bootstrapRules     []models.OutboundRule
+	allowedInputPorts  map[uint16]string // port to interface mapping
+	lanPorts           []uint16
+	redirectionsIntf   string
+	redirections       []models.PortRedirection
+	dnsServerPort      uint16
+	multicastDNS       bool
+	vpnSources         []net.IPNet
+	bypassSources      []net.IPNet
+	bypassUIDs         []int
+	transparentProxy   transparentProxyState
+	stateMutex         sync.Mutex
 }
 
 // NewConfigurator creates a new Configurator instance.
 func NewConfigurator(logger logging.Logger, routing routing.Routing, openFile os.OpenFileFunc) Configurator {
 	commander := command.NewCommander()
-	return &configurator{
+	c := &configurator{
 		commander:         commander,
 		logger:            logger.NewChild(logging.SetPrefix("firewall: ")),
 		routing:           routing,
 		openFile:          openFile,
+		postRulesFilepath: constants.FirewallPostRules,
 		allowedInputPorts: make(map[uint16]string),
+		vpnIntf:           constants.TUN,
 		ip6Tables:         ip6tablesSupported(context.Background(), commander),
 	}
+	c.rules = c
+	return c
 }
 
 func (c *configurator) SetDebug() {
 	c.debug = true
 }
 
+func (c *configurator) SetPostRulesFilepath(filepath string) {
+	c.postRulesFilepath = filepath
+}
+
 func (c *configurator) SetNetworkInformation(
 	defaultInterface string, defaultGateway net.IP, localNetworks []routing.LocalNetwork, localIP net.IP) {
 	c.networkInfoMutex.Lock()
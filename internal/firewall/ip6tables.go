@@ -21,6 +21,16 @@ func ip6tablesSupported(ctx context.Context, commander command.Commander) (suppo
 	return true
 }
 
+// IPv6Supported returns true if ip6tables is supported or if the
+// nftables backend is used, in which case IPv6 traffic is filtered
+// the same way as IPv4 traffic.
+func (c *configurator) IPv6Supported() (supported bool) {
+	if _, ok := c.rules.(*nftables); ok {
+		return true
+	}
+	return c.ip6Tables
+}
+
 func (c *configurator) runIP6tablesInstructions(ctx context.Context, instructions []string) error {
 	for _, instruction := range instructions {
 		if err := c.runIP6tablesInstruction(ctx, instruction); err != nil {
@@ -36,16 +46,42 @@ func (c *configurator) runIP6tablesInstruction(ctx context.Context, instruction
 	}
 	c.ip6tablesMutex.Lock() // only one ip6tables command at once
 	defer c.ip6tablesMutex.Unlock()
-	if c.debug {
+	if c.audit {
+		c.logger.Info("audit: ip6tables %s", instruction)
+		return nil
+	} else if c.debug {
 		fmt.Println("ip6tables " + instruction)
 	}
 	flags := strings.Fields(instruction)
 	if output, err := c.commander.Run(ctx, "ip6tables", flags...); err != nil {
 		return fmt.Errorf("%w \"ip6tables %s\": %s: %s", ErrIP6Tables, instruction, output, err)
 	}
+	c.ipv6State.track(instruction)
 	return nil
 }
 
+// neighborDiscoveryTypes are the ICMPv6 types required for IPv6 to work
+// on the local network, since IPv6 has no ARP protocol.
+var neighborDiscoveryTypes = [...]string{ //nolint:gochecknoglobals
+	"router-solicitation",
+	"router-advertisement",
+	"neighbour-solicitation",
+	"neighbour-advertisement",
+}
+
+func (c *configurator) acceptNeighborDiscovery(ctx context.Context, intf string, remove bool) error {
+	instructions := make([]string, 0, 2*len(neighborDiscoveryTypes))
+	for _, icmpType := range neighborDiscoveryTypes {
+		instructions = append(instructions,
+			fmt.Sprintf("%s INPUT -i %s -p ipv6-icmp --icmpv6-type %s -j ACCEPT",
+				appendOrDelete(remove), intf, icmpType),
+			fmt.Sprintf("%s OUTPUT -o %s -p ipv6-icmp --icmpv6-type %s -j ACCEPT",
+				appendOrDelete(remove), intf, icmpType),
+		)
+	}
+	return c.runIP6tablesInstructions(ctx, instructions)
+}
+
 func (c *configurator) setIPv6AllPolicies(ctx context.Context, policy string) error {
 	switch policy {
 	case "ACCEPT", "DROP":
@@ -7,8 +7,10 @@ import (
 	"io/ioutil"
 	"net"
 	"os"
+	"strconv"
 	"strings"
 
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/models"
 )
 
@@ -28,10 +30,17 @@ func appendOrDelete(remove bool) string {
 	return "--append"
 }
 
-// flipRule changes an append rule in a delete rule or a delete rule into an
-// append rule.
+// flipRule changes an append or insert rule in a delete rule or a delete rule
+// into an append rule, and a new chain rule in a delete chain rule or a
+// delete chain rule into a new chain rule.
 func flipRule(rule string) string {
 	switch {
+	case strings.HasPrefix(rule, "-N"):
+		return strings.Replace(rule, "-N", "-X", 1)
+	case strings.HasPrefix(rule, "-X"):
+		return strings.Replace(rule, "-X", "-N", 1)
+	case strings.HasPrefix(rule, "-I"), strings.HasPrefix(rule, "--insert"):
+		return deleteInsertedRule(rule)
 	case strings.HasPrefix(rule, "-A"):
 		return strings.Replace(rule, "-A", "-D", 1)
 	case strings.HasPrefix(rule, "--append"):
@@ -44,6 +53,21 @@ func flipRule(rule string) string {
 	return rule
 }
 
+// deleteInsertedRule changes an insert rule in a delete rule, removing
+// the optional rule position following the chain name since the rule
+// specification is used to find the rule to delete.
+func deleteInsertedRule(rule string) string {
+	fields := strings.Fields(rule)
+	fields[0] = "-D"
+	const positionIndex = 2
+	if len(fields) > positionIndex {
+		if _, err := strconv.Atoi(fields[positionIndex]); err == nil {
+			fields = append(fields[:positionIndex], fields[positionIndex+1:]...)
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
 // Version obtains the version of the installed iptables.
 func (c *configurator) Version(ctx context.Context) (string, error) {
 	output, err := c.commander.Run(ctx, "iptables", "--version")
@@ -70,17 +94,29 @@ func (c *configurator) runIptablesInstructions(ctx context.Context, instructions
 func (c *configurator) runIptablesInstruction(ctx context.Context, instruction string) error {
 	c.iptablesMutex.Lock() // only one iptables command at once
 	defer c.iptablesMutex.Unlock()
-	if c.debug {
+	if c.audit {
+		c.logger.Info("audit: iptables %s", instruction)
+		return nil
+	} else if c.debug {
 		fmt.Printf("iptables %s\n", instruction)
 	}
 	flags := strings.Fields(instruction)
 	if output, err := c.commander.Run(ctx, "iptables", flags...); err != nil {
 		return fmt.Errorf("%w \"iptables %s\": %s: %s", ErrIPTables, instruction, output, err)
 	}
+	c.ipv4State.track(instruction)
 	return nil
 }
 
 func (c *configurator) clearAllRules(ctx context.Context) error {
+	// the nat and mangle tables are not flushed since other programs
+	// use them, for example Docker for its DNS server, so only the
+	// rules applied in these tables are removed.
+	for _, table := range [...]string{"nat", "mangle"} {
+		if err := c.removeTableRules(ctx, table); err != nil {
+			return fmt.Errorf("%w: %s", ErrClearRules, err)
+		}
+	}
 	if err := c.runMixedIptablesInstructions(ctx, []string{
 		"--flush",        // flush all chains
 		"--delete-chain", // delete all chains
@@ -90,6 +126,34 @@ func (c *configurator) clearAllRules(ctx context.Context) error {
 	return nil
 }
 
+// removeTableRules removes the rules applied in the table given.
+func (c *configurator) removeTableRules(ctx context.Context, table string) error {
+	c.iptablesMutex.Lock()
+	state := copyIptablesState(c.ipv4State)
+	c.iptablesMutex.Unlock()
+	for _, rule := range state.rules {
+		if ruleTable(strings.Fields(rule)) != table {
+			continue
+		}
+		if err := c.runIptablesInstruction(ctx, "--delete "+rule); err != nil {
+			return err
+		}
+	}
+
+	c.ip6tablesMutex.Lock()
+	state = copyIptablesState(c.ipv6State)
+	c.ip6tablesMutex.Unlock()
+	for _, rule := range state.rules {
+		if ruleTable(strings.Fields(rule)) != table {
+			continue
+		}
+		if err := c.runIP6tablesInstruction(ctx, "--delete "+rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *configurator) setIPv4AllPolicies(ctx context.Context, policy string) error {
 	switch policy {
 	case "ACCEPT", "DROP":
@@ -162,15 +226,21 @@ func (c *configurator) acceptOutputTrafficToVPN(ctx context.Context,
 // Thanks to @npawelek.
 func (c *configurator) acceptOutputFromIPToSubnet(ctx context.Context,
 	intf string, sourceIP net.IP, destinationSubnet net.IPNet, remove bool) error {
-	doIPv4 := sourceIP.To4() != nil && destinationSubnet.IP.To4() != nil
+	doIPv4 := destinationSubnet.IP.To4() != nil
 
 	interfaceFlag := "-o " + intf
 	if intf == "*" { // all interfaces
 		interfaceFlag = ""
 	}
 
-	instruction := fmt.Sprintf("%s OUTPUT %s -s %s -d %s -j ACCEPT",
-		appendOrDelete(remove), interfaceFlag, sourceIP.String(), destinationSubnet.String())
+	sourceFlag := "-s " + sourceIP.String()
+	if (sourceIP.To4() != nil) != doIPv4 {
+		// no source address of the subnet family
+		sourceFlag = ""
+	}
+
+	instruction := fmt.Sprintf("%s OUTPUT %s %s -d %s -j ACCEPT",
+		appendOrDelete(remove), interfaceFlag, sourceFlag, destinationSubnet.String())
 
 	if doIPv4 {
 		return c.runIptablesInstruction(ctx, instruction)
@@ -180,6 +250,20 @@ func (c *configurator) acceptOutputFromIPToSubnet(ctx context.Context,
 	return c.runIP6tablesInstruction(ctx, instruction)
 }
 
+func (c *configurator) acceptOutputFromIPToSubnetPort(ctx context.Context, intf string,
+	sourceIP net.IP, destinationSubnet net.IPNet, protocol string, port uint16, remove bool) error {
+	sourceFlag := "-s " + sourceIP.String()
+	if (sourceIP.To4() != nil) != (destinationSubnet.IP.To4() != nil) {
+		// no source address of the subnet family
+		sourceFlag = ""
+	}
+
+	instruction := fmt.Sprintf("%s OUTPUT -o %s %s -d %s -p %s -m %s --dport %d -j ACCEPT",
+		appendOrDelete(remove), intf, sourceFlag, destinationSubnet.String(),
+		protocol, protocol, port)
+	return c.runSubnetIptablesInstructions(ctx, destinationSubnet, []string{instruction})
+}
+
 // Used for port forwarding, with intf set to tun.
 func (c *configurator) acceptInputToPort(ctx context.Context, intf string, port uint16, remove bool) error {
 	interfaceFlag := "-i " + intf
@@ -192,6 +276,149 @@ func (c *configurator) acceptInputToPort(ctx context.Context, intf string, port
 	})
 }
 
+func (c *configurator) acceptInputFromSubnetToPort(ctx context.Context, intf string,
+	source net.IPNet, port uint16, remove bool) error {
+	const format = "%s INPUT -i %s -s %s -p %s -m %s --dport %d -j ACCEPT"
+	return c.runSubnetIptablesInstructions(ctx, source, []string{
+		fmt.Sprintf(format, appendOrDelete(remove), intf, source.String(), "tcp", "tcp", port),
+		fmt.Sprintf(format, appendOrDelete(remove), intf, source.String(), "udp", "udp", port),
+	})
+}
+
+// acceptMulticastDNS accepts the multicast DNS and LLMNR queries
+// and responses to their multicast groups through the interface.
+func (c *configurator) acceptMulticastDNS(ctx context.Context, intf string, remove bool) error {
+	const format = "%s %s %s %s -d %s -p udp -m udp --dport %d -j ACCEPT"
+	for _, group := range constants.MulticastDNSGroups() {
+		instructions := []string{
+			fmt.Sprintf(format, appendOrDelete(remove), "INPUT", "-i", intf, group.IP, group.Port),
+			fmt.Sprintf(format, appendOrDelete(remove), "OUTPUT", "-o", intf, group.IP, group.Port),
+		}
+		run := c.runIptablesInstructions
+		if group.IP.To4() == nil {
+			run = c.runIP6tablesInstructions
+		}
+		if err := run(ctx, instructions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markInputToPort marks the connections to the port through the interface.
+func (c *configurator) markInputToPort(ctx context.Context, intf string, port uint16, remove bool) error {
+	const format = "%s PREROUTING --table mangle -i %s -p %s --dport %d -j CONNMARK --set-mark %d"
+	return c.runMixedIptablesInstructions(ctx, []string{
+		fmt.Sprintf(format, appendOrDelete(remove), intf, "tcp", port, constants.LANPortsMark),
+		fmt.Sprintf(format, appendOrDelete(remove), intf, "udp", port, constants.LANPortsMark),
+	})
+}
+
+// restoreConnectionMark sets the connection mark on the packets of
+// marked connections, so they can be routed using the mark.
+func (c *configurator) restoreConnectionMark(ctx context.Context, remove bool) error {
+	return c.runMixedIptablesInstruction(ctx, fmt.Sprintf(
+		"%s OUTPUT --table mangle -m connmark --mark %d -j CONNMARK --restore-mark",
+		appendOrDelete(remove), constants.LANPortsMark,
+	))
+}
+
+// redirectInputPort redirects the input traffic to the port through the
+// interface to the target port, and accepts the traffic redirected.
+func (c *configurator) redirectInputPort(ctx context.Context, intf string,
+	redirection models.PortRedirection, remove bool) error {
+	var instructions []string
+	for _, protocol := range [...]string{"tcp", "udp"} {
+		instructions = append(instructions,
+			fmt.Sprintf("%s PREROUTING --table nat -i %s -p %s --dport %d -j REDIRECT --to-ports %d",
+				appendOrDelete(remove), intf, protocol, redirection.Port, redirection.TargetPort),
+			fmt.Sprintf("%s INPUT -i %s -p %s -m %s --dport %d -m conntrack --ctstate DNAT -j ACCEPT",
+				appendOrDelete(remove), intf, protocol, protocol, redirection.TargetPort),
+		)
+	}
+	return c.runIptablesInstructions(ctx, instructions)
+}
+
+// redirectFromSubnetToPort redirects the TCP traffic from the source
+// subnet through the interface to the port to the target port.
+func (c *configurator) redirectFromSubnetToPort(ctx context.Context, intf string,
+	source net.IPNet, port, targetPort uint16, remove bool) error {
+	return c.runSubnetIptablesInstructions(ctx, source, []string{
+		fmt.Sprintf("%s PREROUTING --table nat -i %s -s %s -p tcp --dport %d -j REDIRECT --to-ports %d",
+			appendOrDelete(remove), intf, source.String(), port, targetPort),
+	})
+}
+
+// runSubnetIptablesInstructions runs the instructions with iptables if the
+// subnet given is an IPv4 subnet, and with ip6tables otherwise.
+func (c *configurator) runSubnetIptablesInstructions(ctx context.Context,
+	subnet net.IPNet, instructions []string) error {
+	if subnet.IP.To4() != nil {
+		return c.runIptablesInstructions(ctx, instructions)
+	} else if !c.ip6Tables {
+		return fmt.Errorf("subnet %s: %w", subnet, ErrNeedIP6Tables)
+	}
+	return c.runIP6tablesInstructions(ctx, instructions)
+}
+
+// acceptForwardFromSubnet accepts forwarding the traffic from the source
+// subnet through the interface, and its replies.
+func (c *configurator) acceptForwardFromSubnet(ctx context.Context,
+	intf string, source net.IPNet, remove bool) error {
+	return c.runSubnetIptablesInstructions(ctx, source, []string{
+		fmt.Sprintf("%s FORWARD -s %s -o %s -j ACCEPT",
+			appendOrDelete(remove), source.String(), intf),
+		fmt.Sprintf("%s FORWARD -d %s -i %s -m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT",
+			appendOrDelete(remove), source.String(), intf),
+	})
+}
+
+// masqueradeFromSubnet changes the source address of the packets from
+// the source subnet to the address of the interface.
+func (c *configurator) masqueradeFromSubnet(ctx context.Context,
+	intf string, source net.IPNet, remove bool) error {
+	return c.runSubnetIptablesInstructions(ctx, source, []string{
+		fmt.Sprintf("%s POSTROUTING --table nat -s %s -o %s -j MASQUERADE",
+			appendOrDelete(remove), source.String(), intf),
+	})
+}
+
+// markFromSubnet marks the packets from the source subnet so they are
+// routed through the default interface.
+func (c *configurator) markFromSubnet(ctx context.Context, source net.IPNet, remove bool) error {
+	return c.runSubnetIptablesInstructions(ctx, source, []string{
+		fmt.Sprintf("%s PREROUTING --table mangle -s %s -j MARK --set-mark %d",
+			appendOrDelete(remove), source.String(), constants.BypassSourcesMark),
+	})
+}
+
+// markOutputFromUID marks the packets from the processes running with
+// the user ID given so they are routed through the default interface.
+func (c *configurator) markOutputFromUID(ctx context.Context, uid int, remove bool) error {
+	return c.runMixedIptablesInstruction(ctx, fmt.Sprintf(
+		"%s OUTPUT --table mangle -m owner --uid-owner %d -j MARK --set-mark %d",
+		appendOrDelete(remove), uid, constants.BypassSourcesMark,
+	))
+}
+
+// acceptOutputFromUID accepts the traffic from the processes running
+// with the user ID given through the interface.
+func (c *configurator) acceptOutputFromUID(ctx context.Context, intf string, uid int, remove bool) error {
+	return c.runMixedIptablesInstruction(ctx, fmt.Sprintf(
+		"%s OUTPUT -o %s -m owner --uid-owner %d -j ACCEPT",
+		appendOrDelete(remove), intf, uid,
+	))
+}
+
+// masqueradeBypassed changes the source address of the marked packets
+// bypassing the VPN to the address of the interface.
+func (c *configurator) masqueradeBypassed(ctx context.Context, intf string, remove bool) error {
+	return c.runMixedIptablesInstruction(ctx, fmt.Sprintf(
+		"%s POSTROUTING --table nat -o %s -m mark --mark %d -j MASQUERADE",
+		appendOrDelete(remove), intf, constants.BypassSourcesMark,
+	))
+}
+
 func (c *configurator) runUserPostRules(ctx context.Context, filepath string, remove bool) error {
 	file, err := c.openFile(filepath, os.O_RDONLY, 0)
 	if os.IsNotExist(err) {
@@ -207,42 +434,43 @@ func (c *configurator) runUserPostRules(ctx context.Context, filepath string, re
 	if err := file.Close(); err != nil {
 		return err
 	}
-	lines := strings.Split(string(b), "\n")
-	successfulRules := []string{}
+	rules, err := parseUserPostRules(string(b))
+	if err != nil {
+		return err
+	}
+	if remove {
+		// remove rules before the chains they use
+		for i, j := 0, len(rules)-1; i < j; i, j = i+1, j-1 {
+			rules[i], rules[j] = rules[j], rules[i]
+		}
+	}
+	successfulRules := []userPostRule{}
 	defer func() {
 		// transaction-like rollback
 		if err == nil || ctx.Err() != nil {
 			return
 		}
-		for _, rule := range successfulRules {
-			_ = c.runIptablesInstruction(ctx, flipRule(rule))
+		for i := len(successfulRules) - 1; i >= 0; i-- {
+			rule := successfulRules[i]
+			if rule.ipv4 {
+				_ = c.runIptablesInstruction(ctx, flipRule(rule.rule))
+			} else {
+				_ = c.runIP6tablesInstruction(ctx, flipRule(rule.rule))
+			}
 		}
 	}()
-	for _, line := range lines {
-		var ipv4 bool
-		var rule string
-		switch {
-		case strings.HasPrefix(line, "iptables "):
-			ipv4 = true
-			rule = strings.TrimPrefix(line, "iptables ")
-		case strings.HasPrefix(line, "ip6tables "):
-			ipv4 = false
-			rule = strings.TrimPrefix(line, "ip6tables ")
-		default:
-			continue
-		}
-
+	for _, rule := range rules {
 		if remove {
-			rule = flipRule(rule)
+			rule.rule = flipRule(rule.rule)
 		}
 
 		switch {
-		case ipv4:
-			err = c.runIptablesInstruction(ctx, rule)
+		case rule.ipv4:
+			err = c.runIptablesInstruction(ctx, rule.rule)
 		case !c.ip6Tables:
 			err = fmt.Errorf("cannot run user ip6tables rule: %w", ErrNeedIP6Tables)
 		default: // ipv6
-			err = c.runIP6tablesInstruction(ctx, rule)
+			err = c.runIP6tablesInstruction(ctx, rule.rule)
 		}
 		if err != nil {
 			return err
@@ -0,0 +1,53 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+)
+
+// SetLANPorts marks the connections to the ports given through the default
+// interface, so their replies are routed through the default interface
+// instead of the VPN. The input traffic to these ports must be accepted
+// with SetAllowedPort.
+func (c *configurator) SetLANPorts(ctx context.Context, ports []uint16) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if c.lockedDown {
+		return ErrLockedDown
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating LAN ports internal list")
+		c.lanPorts = make([]uint16, len(ports))
+		copy(c.lanPorts, ports)
+		return nil
+	}
+
+	c.logger.Info("setting LAN ports through firewall...")
+
+	const remove = true
+	if err := c.setLANPorts(ctx, c.lanPorts, remove); err != nil {
+		c.logger.Error("cannot remove outdated LAN ports through firewall: %s", err)
+	}
+	c.lanPorts = nil
+	if err := c.setLANPorts(ctx, ports, !remove); err != nil {
+		return fmt.Errorf("cannot set LAN ports through firewall: %w", err)
+	}
+	c.lanPorts = make([]uint16, len(ports))
+	copy(c.lanPorts, ports)
+	c.moveLogDroppedLast(ctx)
+	return nil
+}
+
+func (c *configurator) setLANPorts(ctx context.Context, ports []uint16, remove bool) (err error) {
+	if len(ports) == 0 {
+		return nil
+	}
+	for _, port := range ports {
+		if err := c.rules.markInputToPort(ctx, c.defaultInterface, port, remove); err != nil {
+			return err
+		}
+	}
+	return c.rules.restoreConnectionMark(ctx, remove)
+}
@@ -0,0 +1,99 @@
+package firewall
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/golibs/command/mock_command"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRecordingCommander returns a commander mock recording the
+// iptables instructions run in the slice given.
+func newRecordingCommander(ctrl *gomock.Controller, instructions *[]string) *mock_command.MockCommander {
+	commander := mock_command.NewMockCommander(ctrl)
+	commander.EXPECT().Run(gomock.Any(), "iptables", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, flags ...string) (string, error) {
+			*instructions = append(*instructions, strings.Join(flags, " "))
+			return "", nil
+		}).AnyTimes()
+	return commander
+}
+
+func Test_configurator_SetLANPorts(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	logger := mock_logging.NewMockLogger(ctrl)
+	logger.EXPECT().Info("setting LAN ports through firewall...").Times(2)
+
+	var instructions []string
+	c := &configurator{
+		commander:        newRecordingCommander(ctrl, &instructions),
+		logger:           logger,
+		defaultInterface: "eth0",
+		enabled:          true,
+	}
+	c.rules = c
+
+	err := c.SetLANPorts(ctx, []uint16{8000})
+	require.NoError(t, err)
+
+	markRules := []string{
+		"PREROUTING --table mangle -i eth0 -p tcp --dport 8000 -j CONNMARK --set-mark 7102830",
+		"PREROUTING --table mangle -i eth0 -p udp --dport 8000 -j CONNMARK --set-mark 7102830",
+		"OUTPUT --table mangle -m connmark --mark 7102830 -j CONNMARK --restore-mark",
+	}
+	// the input traffic is accepted with SetAllowedPort,
+	// so only the connection marking rules are added.
+	assert.Equal(t, markRules, c.ipv4State.rules)
+	assert.Equal(t, []uint16{8000}, c.lanPorts)
+
+	instructions = nil
+	err = c.SetLANPorts(ctx, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"--delete " + markRules[0],
+		"--delete " + markRules[1],
+		"--delete " + markRules[2],
+	}, instructions)
+	assert.Empty(t, c.ipv4State.rules)
+	assert.Empty(t, c.lanPorts)
+}
+
+func Test_configurator_clearAllRules(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	var instructions []string
+	c := &configurator{
+		commander: newRecordingCommander(ctrl, &instructions),
+		ipv4State: iptablesState{
+			rules: []string{
+				"INPUT -i lo -j ACCEPT",
+				"PREROUTING --table nat -i eth0 -p tcp --dport 80 -j REDIRECT --to-ports 8000",
+				"PREROUTING --table mangle -i eth0 -p tcp --dport 8000 -j CONNMARK --set-mark 7102830",
+			},
+		},
+	}
+
+	err := c.clearAllRules(ctx)
+	require.NoError(t, err)
+
+	// the mangle and nat tables are not flushed, since
+	// they may hold rules of other programs.
+	assert.Equal(t, []string{
+		"--delete PREROUTING --table nat -i eth0 -p tcp --dport 80 -j REDIRECT --to-ports 8000",
+		"--delete PREROUTING --table mangle -i eth0 -p tcp --dport 8000 -j CONNMARK --set-mark 7102830",
+		"--flush",
+		"--delete-chain",
+	}, instructions)
+	assert.Empty(t, c.ipv4State.rules)
+}
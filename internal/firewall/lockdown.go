@@ -0,0 +1,46 @@
+package firewall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrLockdown   = errors.New("failed locking down firewall")
+	ErrLockedDown = errors.New("firewall is locked down until the program restarts")
+)
+
+// Lockdown blocks all traffic except loopback traffic, for example once
+// traffic is found leaking outside the VPN. The firewall stays locked
+// down until the program restarts: it can no longer be disabled and
+// all the methods changing its rules return ErrLockedDown.
+func (c *configurator) Lockdown(ctx context.Context) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	c.logger.Warn("locking down...")
+	c.lockedDown = true
+	c.enabled = true
+
+	if err := c.rules.clearAllRules(ctx); err != nil {
+		return fmt.Errorf("%w: %s", ErrLockdown, err)
+	}
+	if err := c.rules.setIPv4AllPolicies(ctx, "DROP"); err != nil {
+		return fmt.Errorf("%w: %s", ErrLockdown, err)
+	}
+	if err := c.rules.setIPv6AllPolicies(ctx, "DROP"); err != nil {
+		return fmt.Errorf("%w: %s", ErrLockdown, err)
+	}
+
+	const remove = false
+	if err := c.rules.acceptInputThroughInterface(ctx, "lo", remove); err != nil {
+		return fmt.Errorf("%w: %s", ErrLockdown, err)
+	}
+	if err := c.rules.acceptOutputThroughInterface(ctx, "lo", remove); err != nil {
+		return fmt.Errorf("%w: %s", ErrLockdown, err)
+	}
+
+	c.logger.Warn("locked down, all traffic is blocked")
+	return nil
+}
@@ -0,0 +1,84 @@
+package firewall
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/command/mock_command"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_configurator_Lockdown(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	logger := mock_logging.NewMockLogger(ctrl)
+	logger.EXPECT().Warn("locking down...")
+	logger.EXPECT().Warn("locked down, all traffic is blocked")
+
+	commands := 0
+	commander := mock_command.NewMockCommander(ctrl)
+	commander.EXPECT().Run(ctx, "iptables", gomock.Any()).
+		DoAndReturn(func(context.Context, string, ...string) (string, error) {
+			commands++
+			return "", nil
+		}).AnyTimes()
+
+	c := &configurator{
+		commander:         commander,
+		logger:            logger,
+		allowedInputPorts: make(map[uint16]string),
+		vpnIntf:           "tun0",
+		enabled:           true,
+	}
+	c.rules = c
+
+	err := c.Lockdown(ctx)
+	require.NoError(t, err)
+	lockedDownState := copyIptablesState(c.ipv4State)
+	lockedDownCommands := commands
+
+	connection := models.OpenVPNConnection{IP: net.IPv4(1, 2, 3, 4), Port: 1194, Protocol: "udp"}
+	subnet := net.IPNet{IP: net.IPv4(10, 0, 0, 0), Mask: net.IPv4Mask(255, 0, 0, 0)}
+	mutators := map[string]func() error{
+		"SetEnabled":            func() error { return c.SetEnabled(ctx, false) },
+		"SetVPNConnection":      func() error { return c.SetVPNConnection(ctx, connection) },
+		"SetNextVPNConnection":  func() error { return c.SetNextVPNConnection(ctx, connection) },
+		"SetResolverConnection": func() error { return c.SetResolverConnection(ctx, connection) },
+		"SetVPNInterface":       func() error { return c.SetVPNInterface(ctx, "tun1") },
+		"SetAllowedPort":        func() error { return c.SetAllowedPort(ctx, 8000, "eth0") },
+		"RemoveAllowedPort":     func() error { return c.RemoveAllowedPort(ctx, 8000) },
+		"SetLANPorts":           func() error { return c.SetLANPorts(ctx, []uint16{8000}) },
+		"SetPortRedirections": func() error {
+			return c.SetPortRedirections(ctx, "eth0", []models.PortRedirection{{Port: 80, TargetPort: 8000}})
+		},
+		"SetDNSServerPort":    func() error { return c.SetDNSServerPort(ctx, 53) },
+		"SetMulticastDNS":     func() error { return c.SetMulticastDNS(ctx, true) },
+		"SetOutboundSubnets":  func() error { return c.SetOutboundSubnets(ctx, []net.IPNet{subnet}) },
+		"SetOutboundRules":    func() error { return c.SetOutboundRules(ctx, []models.OutboundRule{{Subnet: subnet}}) },
+		"SetBootstrapRules":   func() error { return c.SetBootstrapRules(ctx, []models.OutboundRule{{Subnet: subnet}}) },
+		"SetForwardedSources": func() error { return c.SetForwardedSources(ctx, []net.IPNet{subnet}, nil) },
+		"SetBypassUIDs":       func() error { return c.SetBypassUIDs(ctx, []int{1000}) },
+		"SetTransparentProxy": func() error { return c.SetTransparentProxy(ctx, []net.IPNet{subnet}, []uint16{80}, 8080) },
+	}
+
+	for name, mutator := range mutators {
+		err := mutator()
+		assert.ErrorIs(t, err, ErrLockedDown, name)
+	}
+
+	assert.Equal(t, lockedDownCommands, commands)
+	assert.Equal(t, lockedDownState, c.ipv4State)
+	assert.Equal(t, []string{
+		"INPUT -i lo -j ACCEPT",
+		"OUTPUT -o lo -j ACCEPT",
+	}, c.ipv4State.rules)
+	assert.Equal(t, map[string]string{"INPUT": "DROP", "OUTPUT": "DROP", "FORWARD": "DROP"},
+		c.ipv4State.policies)
+}
@@ -0,0 +1,61 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	// logDroppedPrefix is the kernel log prefix of the dropped packets.
+	logDroppedPrefix = "gluetun-dropped:"
+	// logDroppedLimit and logDroppedBurst rate limit the dropped
+	// packets logged, to avoid flooding the kernel log.
+	logDroppedLimit = "10/minute"
+	logDroppedBurst = 5
+)
+
+// SetLogDropped sets whether packets dropped by the firewall are logged,
+// with a rate limit, to the kernel log.
+func (c *configurator) SetLogDropped(logDropped bool) {
+	c.logDropped = logDropped
+}
+
+// moveLogDroppedLast removes and appends again the rules logging dropped
+// packets, so they stay after all the accepting rules in their chains.
+// It must be called after appending accepting rules.
+func (c *configurator) moveLogDroppedLast(ctx context.Context) {
+	if !c.logDropped {
+		return
+	}
+	const remove = true
+	if err := c.rules.logDroppedPackets(ctx, remove); err != nil {
+		c.logger.Error("cannot remove dropped packets logging: %s", err)
+		return
+	}
+	if err := c.rules.logDroppedPackets(ctx, !remove); err != nil {
+		c.logger.Error("cannot log dropped packets: %s", err)
+	}
+}
+
+// logDroppedPackets logs the packets reaching the end of the input and
+// output chains, which are dropped by the chains policy.
+func (c *configurator) logDroppedPackets(ctx context.Context, remove bool) error {
+	const format = "%s %s -m limit --limit %s --limit-burst %d -j LOG --log-prefix %s"
+	return c.runMixedIptablesInstructions(ctx, []string{
+		fmt.Sprintf(format, appendOrDelete(remove), "INPUT", logDroppedLimit, logDroppedBurst, logDroppedPrefix),
+		fmt.Sprintf(format, appendOrDelete(remove), "OUTPUT", logDroppedLimit, logDroppedBurst, logDroppedPrefix),
+	})
+}
+
+// logDroppedPackets logs the packets reaching the end of the input and
+// output chains, which are dropped by the chains policy.
+func (n *nftables) logDroppedPackets(ctx context.Context, remove bool) error {
+	rule := fmt.Sprintf(`limit rate %s burst %d packets counter log prefix "%s"`,
+		logDroppedLimit, logDroppedBurst, logDroppedPrefix)
+	for _, chain := range [...]string{"input", "output"} {
+		if err := n.setRule(ctx, chain, rule, remove); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,519 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/qdm12/gluetun/internal/firewall (interfaces: Configurator)
+
+// Package mock_firewall is a generated GoMock package.
+package mock_firewall
+
+import (
+	context "context"
+	net "net"
+	reflect "reflect"
+	sync "sync"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/qdm12/gluetun/internal/models"
+	routing "github.com/qdm12/gluetun/internal/routing"
+)
+
+// MockConfigurator is a mock of Configurator interface.
+type MockConfigurator struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfiguratorMockRecorder
+}
+
+// MockConfiguratorMockRecorder is the mock recorder for MockConfigurator.
+type MockConfiguratorMockRecorder struct {
+	mock *MockConfigurator
+}
+
+// NewMockConfigurator creates a new mock instance.
+func NewMockConfigurator(ctrl *gomock.Controller) *MockConfigurator {
+	mock := &MockConfigurator{ctrl: ctrl}
+	mock.recorder = &MockConfiguratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfigurator) EXPECT() *MockConfiguratorMockRecorder {
+	return m.recorder
+}
+
+// FlushTunnelConnections mocks base method.
+func (m *MockConfigurator) FlushTunnelConnections(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlushTunnelConnections", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FlushTunnelConnections indicates an expected call of FlushTunnelConnections.
+func (mr *MockConfiguratorMockRecorder) FlushTunnelConnections(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushTunnelConnections", reflect.TypeOf((*MockConfigurator)(nil).FlushTunnelConnections), arg0)
+}
+
+// GetAllowedPorts mocks base method.
+func (m *MockConfigurator) GetAllowedPorts() map[uint16]string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllowedPorts")
+	ret0, _ := ret[0].(map[uint16]string)
+	return ret0
+}
+
+// GetAllowedPorts indicates an expected call of GetAllowedPorts.
+func (mr *MockConfiguratorMockRecorder) GetAllowedPorts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllowedPorts", reflect.TypeOf((*MockConfigurator)(nil).GetAllowedPorts))
+}
+
+// GetCounters mocks base method.
+func (m *MockConfigurator) GetCounters(arg0 context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCounters", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCounters indicates an expected call of GetCounters.
+func (mr *MockConfiguratorMockRecorder) GetCounters(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCounters", reflect.TypeOf((*MockConfigurator)(nil).GetCounters), arg0)
+}
+
+// GetEnabled mocks base method.
+func (m *MockConfigurator) GetEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// GetEnabled indicates an expected call of GetEnabled.
+func (mr *MockConfiguratorMockRecorder) GetEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnabled", reflect.TypeOf((*MockConfigurator)(nil).GetEnabled))
+}
+
+// GetRules mocks base method.
+func (m *MockConfigurator) GetRules(arg0 context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRules", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRules indicates an expected call of GetRules.
+func (mr *MockConfiguratorMockRecorder) GetRules(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRules", reflect.TypeOf((*MockConfigurator)(nil).GetRules), arg0)
+}
+
+// GetVPNInterface mocks base method.
+func (m *MockConfigurator) GetVPNInterface() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVPNInterface")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetVPNInterface indicates an expected call of GetVPNInterface.
+func (mr *MockConfiguratorMockRecorder) GetVPNInterface() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVPNInterface", reflect.TypeOf((*MockConfigurator)(nil).GetVPNInterface))
+}
+
+// IPv6Supported mocks base method.
+func (m *MockConfigurator) IPv6Supported() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IPv6Supported")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IPv6Supported indicates an expected call of IPv6Supported.
+func (mr *MockConfiguratorMockRecorder) IPv6Supported() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IPv6Supported", reflect.TypeOf((*MockConfigurator)(nil).IPv6Supported))
+}
+
+// Lockdown mocks base method.
+func (m *MockConfigurator) Lockdown(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lockdown", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lockdown indicates an expected call of Lockdown.
+func (mr *MockConfiguratorMockRecorder) Lockdown(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lockdown", reflect.TypeOf((*MockConfigurator)(nil).Lockdown), arg0)
+}
+
+// RemoveAllowedPort mocks base method.
+func (m *MockConfigurator) RemoveAllowedPort(arg0 context.Context, arg1 uint16) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAllowedPort", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAllowedPort indicates an expected call of RemoveAllowedPort.
+func (mr *MockConfiguratorMockRecorder) RemoveAllowedPort(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAllowedPort", reflect.TypeOf((*MockConfigurator)(nil).RemoveAllowedPort), arg0, arg1)
+}
+
+// RunVerifyTicker mocks base method.
+func (m *MockConfigurator) RunVerifyTicker(arg0 context.Context, arg1 *sync.WaitGroup, arg2 time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunVerifyTicker", arg0, arg1, arg2)
+}
+
+// RunVerifyTicker indicates an expected call of RunVerifyTicker.
+func (mr *MockConfiguratorMockRecorder) RunVerifyTicker(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunVerifyTicker", reflect.TypeOf((*MockConfigurator)(nil).RunVerifyTicker), arg0, arg1, arg2)
+}
+
+// SetAllowedPort mocks base method.
+func (m *MockConfigurator) SetAllowedPort(arg0 context.Context, arg1 uint16, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAllowedPort", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAllowedPort indicates an expected call of SetAllowedPort.
+func (mr *MockConfiguratorMockRecorder) SetAllowedPort(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAllowedPort", reflect.TypeOf((*MockConfigurator)(nil).SetAllowedPort), arg0, arg1, arg2)
+}
+
+// SetAudit mocks base method.
+func (m *MockConfigurator) SetAudit() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAudit")
+}
+
+// SetAudit indicates an expected call of SetAudit.
+func (mr *MockConfiguratorMockRecorder) SetAudit() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAudit", reflect.TypeOf((*MockConfigurator)(nil).SetAudit))
+}
+
+// SetBackend mocks base method.
+func (m *MockConfigurator) SetBackend(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBackend", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBackend indicates an expected call of SetBackend.
+func (mr *MockConfiguratorMockRecorder) SetBackend(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBackend", reflect.TypeOf((*MockConfigurator)(nil).SetBackend), arg0, arg1)
+}
+
+// SetBootstrapRules mocks base method.
+func (m *MockConfigurator) SetBootstrapRules(arg0 context.Context, arg1 []models.OutboundRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBootstrapRules", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBootstrapRules indicates an expected call of SetBootstrapRules.
+func (mr *MockConfiguratorMockRecorder) SetBootstrapRules(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBootstrapRules", reflect.TypeOf((*MockConfigurator)(nil).SetBootstrapRules), arg0, arg1)
+}
+
+// SetBypassUIDs mocks base method.
+func (m *MockConfigurator) SetBypassUIDs(arg0 context.Context, arg1 []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBypassUIDs", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBypassUIDs indicates an expected call of SetBypassUIDs.
+func (mr *MockConfiguratorMockRecorder) SetBypassUIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBypassUIDs", reflect.TypeOf((*MockConfigurator)(nil).SetBypassUIDs), arg0, arg1)
+}
+
+// SetDNSServerPort mocks base method.
+func (m *MockConfigurator) SetDNSServerPort(arg0 context.Context, arg1 uint16) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDNSServerPort", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDNSServerPort indicates an expected call of SetDNSServerPort.
+func (mr *MockConfiguratorMockRecorder) SetDNSServerPort(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDNSServerPort", reflect.TypeOf((*MockConfigurator)(nil).SetDNSServerPort), arg0, arg1)
+}
+
+// SetDebug mocks base method.
+func (m *MockConfigurator) SetDebug() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetDebug")
+}
+
+// SetDebug indicates an expected call of SetDebug.
+func (mr *MockConfiguratorMockRecorder) SetDebug() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDebug", reflect.TypeOf((*MockConfigurator)(nil).SetDebug))
+}
+
+// SetEnabled mocks base method.
+func (m *MockConfigurator) SetEnabled(arg0 context.Context, arg1 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEnabled", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetEnabled indicates an expected call of SetEnabled.
+func (mr *MockConfiguratorMockRecorder) SetEnabled(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEnabled", reflect.TypeOf((*MockConfigurator)(nil).SetEnabled), arg0, arg1)
+}
+
+// SetFlushConntrack mocks base method.
+func (m *MockConfigurator) SetFlushConntrack(arg0 bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetFlushConntrack", arg0)
+}
+
+// SetFlushConntrack indicates an expected call of SetFlushConntrack.
+func (mr *MockConfiguratorMockRecorder) SetFlushConntrack(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFlushConntrack", reflect.TypeOf((*MockConfigurator)(nil).SetFlushConntrack), arg0)
+}
+
+// SetForwardedSources mocks base method.
+func (m *MockConfigurator) SetForwardedSources(arg0 context.Context, arg1, arg2 []net.IPNet) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetForwardedSources", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetForwardedSources indicates an expected call of SetForwardedSources.
+func (mr *MockConfiguratorMockRecorder) SetForwardedSources(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetForwardedSources", reflect.TypeOf((*MockConfigurator)(nil).SetForwardedSources), arg0, arg1, arg2)
+}
+
+// SetLANPorts mocks base method.
+func (m *MockConfigurator) SetLANPorts(arg0 context.Context, arg1 []uint16) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLANPorts", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLANPorts indicates an expected call of SetLANPorts.
+func (mr *MockConfiguratorMockRecorder) SetLANPorts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLANPorts", reflect.TypeOf((*MockConfigurator)(nil).SetLANPorts), arg0, arg1)
+}
+
+// SetLogDropped mocks base method.
+func (m *MockConfigurator) SetLogDropped(arg0 bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLogDropped", arg0)
+}
+
+// SetLogDropped indicates an expected call of SetLogDropped.
+func (mr *MockConfiguratorMockRecorder) SetLogDropped(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLogDropped", reflect.TypeOf((*MockConfigurator)(nil).SetLogDropped), arg0)
+}
+
+// SetMulticastDNS mocks base method.
+func (m *MockConfigurator) SetMulticastDNS(arg0 context.Context, arg1 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMulticastDNS", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMulticastDNS indicates an expected call of SetMulticastDNS.
+func (mr *MockConfiguratorMockRecorder) SetMulticastDNS(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMulticastDNS", reflect.TypeOf((*MockConfigurator)(nil).SetMulticastDNS), arg0, arg1)
+}
+
+// SetNetworkInformation mocks base method.
+func (m *MockConfigurator) SetNetworkInformation(arg0 string, arg1 net.IP, arg2 []routing.LocalNetwork, arg3 net.IP) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetNetworkInformation", arg0, arg1, arg2, arg3)
+}
+
+// SetNetworkInformation indicates an expected call of SetNetworkInformation.
+func (mr *MockConfiguratorMockRecorder) SetNetworkInformation(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNetworkInformation", reflect.TypeOf((*MockConfigurator)(nil).SetNetworkInformation), arg0, arg1, arg2, arg3)
+}
+
+// SetNextVPNConnection mocks base method.
+func (m *MockConfigurator) SetNextVPNConnection(arg0 context.Context, arg1 models.OpenVPNConnection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNextVPNConnection", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNextVPNConnection indicates an expected call of SetNextVPNConnection.
+func (mr *MockConfiguratorMockRecorder) SetNextVPNConnection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNextVPNConnection", reflect.TypeOf((*MockConfigurator)(nil).SetNextVPNConnection), arg0, arg1)
+}
+
+// SetOutboundRules mocks base method.
+func (m *MockConfigurator) SetOutboundRules(arg0 context.Context, arg1 []models.OutboundRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOutboundRules", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOutboundRules indicates an expected call of SetOutboundRules.
+func (mr *MockConfiguratorMockRecorder) SetOutboundRules(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOutboundRules", reflect.TypeOf((*MockConfigurator)(nil).SetOutboundRules), arg0, arg1)
+}
+
+// SetOutboundSubnets mocks base method.
+func (m *MockConfigurator) SetOutboundSubnets(arg0 context.Context, arg1 []net.IPNet) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOutboundSubnets", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOutboundSubnets indicates an expected call of SetOutboundSubnets.
+func (mr *MockConfiguratorMockRecorder) SetOutboundSubnets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOutboundSubnets", reflect.TypeOf((*MockConfigurator)(nil).SetOutboundSubnets), arg0, arg1)
+}
+
+// SetPortRedirections mocks base method.
+func (m *MockConfigurator) SetPortRedirections(arg0 context.Context, arg1 string, arg2 []models.PortRedirection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPortRedirections", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPortRedirections indicates an expected call of SetPortRedirections.
+func (mr *MockConfiguratorMockRecorder) SetPortRedirections(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPortRedirections", reflect.TypeOf((*MockConfigurator)(nil).SetPortRedirections), arg0, arg1, arg2)
+}
+
+// SetPostRulesFilepath mocks base method.
+func (m *MockConfigurator) SetPostRulesFilepath(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPostRulesFilepath", arg0)
+}
+
+// SetPostRulesFilepath indicates an expected call of SetPostRulesFilepath.
+func (mr *MockConfiguratorMockRecorder) SetPostRulesFilepath(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPostRulesFilepath", reflect.TypeOf((*MockConfigurator)(nil).SetPostRulesFilepath), arg0)
+}
+
+// SetResolverConnection mocks base method.
+func (m *MockConfigurator) SetResolverConnection(arg0 context.Context, arg1 models.OpenVPNConnection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetResolverConnection", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetResolverConnection indicates an expected call of SetResolverConnection.
+func (mr *MockConfiguratorMockRecorder) SetResolverConnection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetResolverConnection", reflect.TypeOf((*MockConfigurator)(nil).SetResolverConnection), arg0, arg1)
+}
+
+// SetTransparentProxy mocks base method.
+func (m *MockConfigurator) SetTransparentProxy(arg0 context.Context, arg1 []net.IPNet, arg2 []uint16, arg3 uint16) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTransparentProxy", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTransparentProxy indicates an expected call of SetTransparentProxy.
+func (mr *MockConfiguratorMockRecorder) SetTransparentProxy(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTransparentProxy", reflect.TypeOf((*MockConfigurator)(nil).SetTransparentProxy), arg0, arg1, arg2, arg3)
+}
+
+// SetVPNConnection mocks base method.
+func (m *MockConfigurator) SetVPNConnection(arg0 context.Context, arg1 models.OpenVPNConnection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVPNConnection", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetVPNConnection indicates an expected call of SetVPNConnection.
+func (mr *MockConfiguratorMockRecorder) SetVPNConnection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVPNConnection", reflect.TypeOf((*MockConfigurator)(nil).SetVPNConnection), arg0, arg1)
+}
+
+// SetVPNInterface mocks base method.
+func (m *MockConfigurator) SetVPNInterface(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVPNInterface", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetVPNInterface indicates an expected call of SetVPNInterface.
+func (mr *MockConfiguratorMockRecorder) SetVPNInterface(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVPNInterface", reflect.TypeOf((*MockConfigurator)(nil).SetVPNInterface), arg0, arg1)
+}
+
+// Verify mocks base method.
+func (m *MockConfigurator) Verify(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockConfiguratorMockRecorder) Verify(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockConfigurator)(nil).Verify), arg0)
+}
+
+// Version mocks base method.
+func (m *MockConfigurator) Version(arg0 context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Version", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Version indicates an expected call of Version.
+func (mr *MockConfiguratorMockRecorder) Version(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Version", reflect.TypeOf((*MockConfigurator)(nil).Version), arg0)
+}
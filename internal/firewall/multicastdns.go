@@ -0,0 +1,42 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+)
+
+// SetMulticastDNS accepts the multicast DNS and LLMNR traffic through
+// the default interface if enabled, so services behind the firewall
+// can discover and be discovered by devices on the local network.
+func (c *configurator) SetMulticastDNS(ctx context.Context, enabled bool) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if c.lockedDown {
+		return ErrLockedDown
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating multicast DNS internal state")
+		c.multicastDNS = enabled
+		return nil
+	}
+
+	if c.multicastDNS == enabled {
+		return nil
+	}
+
+	if enabled {
+		c.logger.Info("accepting multicast DNS through firewall...")
+	} else {
+		c.logger.Info("blocking multicast DNS through firewall...")
+	}
+
+	remove := !enabled
+	if err := c.rules.acceptMulticastDNS(ctx, c.defaultInterface, remove); err != nil {
+		return fmt.Errorf("cannot set multicast DNS through firewall: %w", err)
+	}
+	c.multicastDNS = enabled
+	c.moveLogDroppedLast(ctx)
+	return nil
+}
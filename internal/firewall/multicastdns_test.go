@@ -0,0 +1,60 @@
+package firewall
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/golibs/command/mock_command"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_configurator_SetMulticastDNS(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	logger := mock_logging.NewMockLogger(ctrl)
+	logger.EXPECT().Info("accepting multicast DNS through firewall...")
+	logger.EXPECT().Info("blocking multicast DNS through firewall...")
+	commander := mock_command.NewMockCommander(ctrl)
+	commander.EXPECT().Run(ctx, "iptables", gomock.Any()).
+		Return("", nil).AnyTimes()
+	commander.EXPECT().Run(ctx, "ip6tables", gomock.Any()).
+		Return("", nil).AnyTimes()
+
+	c := &configurator{
+		commander:        commander,
+		logger:           logger,
+		defaultInterface: "eth0",
+		ip6Tables:        true,
+		enabled:          true,
+	}
+	c.rules = c
+
+	err := c.SetMulticastDNS(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"INPUT -i eth0 -d 224.0.0.251 -p udp -m udp --dport 5353 -j ACCEPT",
+		"OUTPUT -o eth0 -d 224.0.0.251 -p udp -m udp --dport 5353 -j ACCEPT",
+		"INPUT -i eth0 -d 224.0.0.252 -p udp -m udp --dport 5355 -j ACCEPT",
+		"OUTPUT -o eth0 -d 224.0.0.252 -p udp -m udp --dport 5355 -j ACCEPT",
+	}, c.ipv4State.rules)
+	assert.Equal(t, []string{
+		"INPUT -i eth0 -d ff02::fb -p udp -m udp --dport 5353 -j ACCEPT",
+		"OUTPUT -o eth0 -d ff02::fb -p udp -m udp --dport 5353 -j ACCEPT",
+		"INPUT -i eth0 -d ff02::1:3 -p udp -m udp --dport 5355 -j ACCEPT",
+		"OUTPUT -o eth0 -d ff02::1:3 -p udp -m udp --dport 5355 -j ACCEPT",
+	}, c.ipv6State.rules)
+
+	// setting the same state again is a no-op
+	err = c.SetMulticastDNS(ctx, true)
+	require.NoError(t, err)
+
+	err = c.SetMulticastDNS(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, c.ipv4State.rules)
+	assert.Empty(t, c.ipv6State.rules)
+}
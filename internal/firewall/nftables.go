@@ -0,0 +1,549 @@
+package firewall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/command"
+	"github.com/qdm12/golibs/logging"
+	"github.com/qdm12/golibs/os"
+)
+
+var (
+	ErrNFTables             = errors.New("failed nft command")
+	ErrNFTablesRuleNotFound = errors.New("nftables rule not found")
+	ErrNFTablesNoHandle     = errors.New("nftables rule handle not found in output")
+)
+
+// nftablesTable is the nftables table containing all the firewall rules,
+// for both IPv4 and IPv6 traffic.
+const nftablesTable = "inet gluetun"
+
+// nftables applies the firewall rules using nftables, in a table
+// separate from other programs rules.
+type nftables struct {
+	commander command.Commander
+	openFile  os.OpenFileFunc
+	logger    logging.Logger
+	debug     *bool // shared with the configurator
+	audit     *bool // shared with the configurator
+	// auditHandle is the last fake handle given in audit mode.
+	auditHandle int
+	mutex       sync.Mutex
+	// handles maps each rule added to its handles, since nftables
+	// rules can only be deleted using their handle.
+	handles map[string][]string
+	// userHandles are the handles of the user post rules.
+	userHandles map[string]struct{}
+	// policy is the policy of the input, output and forward chains.
+	policy string
+}
+
+func newNFTables(commander command.Commander, openFile os.OpenFileFunc,
+	logger logging.Logger, debug, audit *bool) *nftables {
+	return &nftables{
+		commander:   commander,
+		openFile:    openFile,
+		logger:      logger,
+		debug:       debug,
+		audit:       audit,
+		handles:     make(map[string][]string),
+		userHandles: make(map[string]struct{}),
+	}
+}
+
+func nftablesSupported(ctx context.Context, commander command.Commander) (supported bool) {
+	if _, err := commander.Run(ctx, "nft", "list", "tables"); err != nil {
+		return false
+	}
+	return true
+}
+
+func (n *nftables) run(ctx context.Context, instruction string) (output string, err error) {
+	n.mutex.Lock() // only one nft command at once
+	defer n.mutex.Unlock()
+	if *n.audit {
+		n.logger.Info("audit: nft %s", instruction)
+		if !strings.Contains(instruction, "--handle add rule") {
+			return "", nil
+		}
+		n.auditHandle++
+		return "# handle " + strconv.Itoa(n.auditHandle), nil
+	} else if *n.debug {
+		fmt.Printf("nft %s\n", instruction)
+	}
+	flags := strings.Fields(instruction)
+	output, err = n.commander.Run(ctx, "nft", flags...)
+	if err != nil {
+		return "", fmt.Errorf("%w \"nft %s\": %s: %s", ErrNFTables, instruction, output, err)
+	}
+	return output, nil
+}
+
+var regexHandle = regexp.MustCompile(`# handle ([0-9]+)`)
+
+// setRule adds or deletes the rule given in the chain given.
+func (n *nftables) setRule(ctx context.Context, chain, rule string, remove bool) (err error) {
+	key := chain + " " + rule
+	if remove {
+		handles := n.handles[key]
+		if len(handles) == 0 {
+			return fmt.Errorf("%w: %s", ErrNFTablesRuleNotFound, key)
+		}
+		handle := handles[len(handles)-1]
+		instruction := "delete rule " + nftablesTable + " " + chain + " handle " + handle
+		if _, err := n.run(ctx, instruction); err != nil {
+			return err
+		}
+		n.handles[key] = handles[:len(handles)-1]
+		return nil
+	}
+
+	output, err := n.run(ctx, "--echo --handle add rule "+nftablesTable+" "+chain+" "+withCounter(rule))
+	if err != nil {
+		return err
+	}
+	match := regexHandle.FindStringSubmatch(output)
+	if match == nil {
+		return fmt.Errorf("%w: %s", ErrNFTablesNoHandle, output)
+	}
+	n.handles[key] = append(n.handles[key], match[1])
+	return nil
+}
+
+// withCounter adds a counter to the accepting rule given, to have its
+// packets and bytes counters listed with the rules.
+func withCounter(rule string) string {
+	const verdict = " accept"
+	if !strings.HasSuffix(rule, verdict) {
+		return rule
+	}
+	return strings.TrimSuffix(rule, verdict) + " counter" + verdict
+}
+
+func (n *nftables) clearAllRules(ctx context.Context) error {
+	// add the table first so deleting it does not fail if it does not exist
+	if _, err := n.run(ctx, "add table "+nftablesTable); err != nil {
+		return fmt.Errorf("%w: %s", ErrClearRules, err)
+	}
+	if _, err := n.run(ctx, "delete table "+nftablesTable); err != nil {
+		return fmt.Errorf("%w: %s", ErrClearRules, err)
+	}
+	n.handles = make(map[string][]string)
+	n.userHandles = make(map[string]struct{})
+	return nil
+}
+
+// setIPv4AllPolicies sets the policy of the input, output and forward
+// chains, which apply to both IPv4 and IPv6 traffic.
+func (n *nftables) setIPv4AllPolicies(ctx context.Context, policy string) error {
+	switch policy {
+	case "ACCEPT", "DROP":
+	default:
+		return fmt.Errorf("%w: %s: %s", ErrSetIPtablesPolicies, ErrPolicyUnknown, policy)
+	}
+	policy = strings.ToLower(policy)
+	instructions := []string{"add table " + nftablesTable}
+	for _, hook := range [...]string{"input", "output", "forward"} {
+		instructions = append(instructions, fmt.Sprintf(
+			"add chain %s %s { type filter hook %s priority 0 ; policy %s ; }",
+			nftablesTable, hook, hook, policy))
+	}
+	// connection marking chains, equivalent to the iptables mangle table
+	instructions = append(instructions,
+		"add chain "+nftablesTable+" prerouting { type filter hook prerouting priority -150 ; policy accept ; }",
+		"add chain "+nftablesTable+" route { type route hook output priority -150 ; policy accept ; }",
+		"add chain "+nftablesTable+" postrouting { type nat hook postrouting priority 100 ; policy accept ; }",
+		"add chain "+nftablesTable+" redirect { type nat hook prerouting priority -100 ; policy accept ; }",
+	)
+	for _, instruction := range instructions {
+		if _, err := n.run(ctx, instruction); err != nil {
+			return fmt.Errorf("%w: %s", ErrSetIPtablesPolicies, err)
+		}
+	}
+	n.policy = policy
+	return nil
+}
+
+// setIPv6AllPolicies does nothing since the IPv6 policies are set
+// together with the IPv4 policies.
+func (n *nftables) setIPv6AllPolicies(ctx context.Context, policy string) error {
+	return nil
+}
+
+// nftablesInterface returns the quoted interface name for nftables,
+// where the iptables wildcard suffix + is replaced by *.
+func nftablesInterface(intf string) string {
+	if strings.HasSuffix(intf, "+") {
+		intf = strings.TrimSuffix(intf, "+") + "*"
+	}
+	return `"` + intf + `"`
+}
+
+// nftablesFamily returns the nftables address family of the IP address.
+func nftablesFamily(ip net.IP) string {
+	if ip.To4() != nil {
+		return "ip"
+	}
+	return "ip6"
+}
+
+func (n *nftables) acceptInputThroughInterface(ctx context.Context, intf string, remove bool) error {
+	return n.setRule(ctx, "input", "iifname "+nftablesInterface(intf)+" accept", remove)
+}
+
+func (n *nftables) acceptInputToSubnet(ctx context.Context, intf string, destination net.IPNet, remove bool) error {
+	interfaceFlag := "iifname " + nftablesInterface(intf) + " "
+	if intf == "*" { // all interfaces
+		interfaceFlag = ""
+	}
+	rule := fmt.Sprintf("%s%s daddr %s accept",
+		interfaceFlag, nftablesFamily(destination.IP), destination.String())
+	return n.setRule(ctx, "input", rule, remove)
+}
+
+func (n *nftables) acceptOutputThroughInterface(ctx context.Context, intf string, remove bool) error {
+	return n.setRule(ctx, "output", "oifname "+nftablesInterface(intf)+" accept", remove)
+}
+
+func (n *nftables) acceptEstablishedRelatedTraffic(ctx context.Context, remove bool) error {
+	const rule = "ct state established,related accept"
+	if err := n.setRule(ctx, "output", rule, remove); err != nil {
+		return err
+	}
+	return n.setRule(ctx, "input", rule, remove)
+}
+
+func (n *nftables) acceptOutputTrafficToVPN(ctx context.Context,
+	defaultInterface string, connection models.OpenVPNConnection, remove bool) error {
+	rule := fmt.Sprintf("oifname %s %s daddr %s %s dport %d accept",
+		nftablesInterface(defaultInterface), nftablesFamily(connection.IP), connection.IP,
+		connection.Protocol, connection.Port)
+	return n.setRule(ctx, "output", rule, remove)
+}
+
+func (n *nftables) acceptOutputFromIPToSubnet(ctx context.Context,
+	intf string, sourceIP net.IP, destinationSubnet net.IPNet, remove bool) error {
+	interfaceFlag := "oifname " + nftablesInterface(intf) + " "
+	if intf == "*" { // all interfaces
+		interfaceFlag = ""
+	}
+	family := nftablesFamily(destinationSubnet.IP)
+	sourceFlag := family + " saddr " + sourceIP.String() + " "
+	if nftablesFamily(sourceIP) != family {
+		// no source address of the subnet family
+		sourceFlag = ""
+	}
+	rule := fmt.Sprintf("%s%s%s daddr %s accept",
+		interfaceFlag, sourceFlag, family, destinationSubnet.String())
+	return n.setRule(ctx, "output", rule, remove)
+}
+
+func (n *nftables) acceptOutputFromIPToSubnetPort(ctx context.Context, intf string,
+	sourceIP net.IP, destinationSubnet net.IPNet, protocol string, port uint16, remove bool) error {
+	family := nftablesFamily(destinationSubnet.IP)
+	sourceFlag := family + " saddr " + sourceIP.String() + " "
+	if nftablesFamily(sourceIP) != family {
+		// no source address of the subnet family
+		sourceFlag = ""
+	}
+	rule := fmt.Sprintf("oifname %s %s%s daddr %s %s dport %d accept",
+		nftablesInterface(intf), sourceFlag, family, destinationSubnet.String(), protocol, port)
+	return n.setRule(ctx, "output", rule, remove)
+}
+
+// Used for port forwarding, with intf set to tun.
+func (n *nftables) acceptInputToPort(ctx context.Context, intf string, port uint16, remove bool) error {
+	interfaceFlag := "iifname " + nftablesInterface(intf) + " "
+	if intf == "*" { // all interfaces
+		interfaceFlag = ""
+	}
+	for _, protocol := range [...]string{"tcp", "udp"} {
+		rule := interfaceFlag + protocol + " dport " + strconv.Itoa(int(port)) + " accept"
+		if err := n.setRule(ctx, "input", rule, remove); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *nftables) acceptInputFromSubnetToPort(ctx context.Context, intf string,
+	source net.IPNet, port uint16, remove bool) error {
+	for _, protocol := range [...]string{"tcp", "udp"} {
+		rule := fmt.Sprintf("iifname %s %s saddr %s %s dport %d accept",
+			nftablesInterface(intf), nftablesFamily(source.IP), source.String(), protocol, port)
+		if err := n.setRule(ctx, "input", rule, remove); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *nftables) acceptNeighborDiscovery(ctx context.Context, intf string, remove bool) error {
+	for _, icmpType := range [...]string{
+		"nd-router-solicit", "nd-router-advert", "nd-neighbor-solicit", "nd-neighbor-advert",
+	} {
+		rule := "iifname " + nftablesInterface(intf) + " icmpv6 type " + icmpType + " accept"
+		if err := n.setRule(ctx, "input", rule, remove); err != nil {
+			return err
+		}
+		rule = "oifname " + nftablesInterface(intf) + " icmpv6 type " + icmpType + " accept"
+		if err := n.setRule(ctx, "output", rule, remove); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *nftables) acceptMulticastDNS(ctx context.Context, intf string, remove bool) error {
+	for _, group := range constants.MulticastDNSGroups() {
+		family := nftablesFamily(group.IP)
+		rule := fmt.Sprintf("iifname %s %s daddr %s udp dport %d accept",
+			nftablesInterface(intf), family, group.IP, group.Port)
+		if err := n.setRule(ctx, "input", rule, remove); err != nil {
+			return err
+		}
+		rule = fmt.Sprintf("oifname %s %s daddr %s udp dport %d accept",
+			nftablesInterface(intf), family, group.IP, group.Port)
+		if err := n.setRule(ctx, "output", rule, remove); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *nftables) listRules(ctx context.Context) (rules string, err error) {
+	output, err := n.run(ctx, "list table "+nftablesTable)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// markInputToPort marks the connections to the port through the interface.
+func (n *nftables) markInputToPort(ctx context.Context, intf string, port uint16, remove bool) error {
+	for _, protocol := range [...]string{"tcp", "udp"} {
+		rule := fmt.Sprintf("iifname %s %s dport %d ct mark set %d",
+			nftablesInterface(intf), protocol, port, constants.LANPortsMark)
+		if err := n.setRule(ctx, "prerouting", rule, remove); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreConnectionMark sets the connection mark on the packets of
+// marked connections, so they can be routed using the mark.
+func (n *nftables) restoreConnectionMark(ctx context.Context, remove bool) error {
+	rule := fmt.Sprintf("ct mark %d meta mark set ct mark", constants.LANPortsMark)
+	return n.setRule(ctx, "route", rule, remove)
+}
+
+// redirectInputPort redirects the input traffic to the port through the
+// interface to the target port, and accepts the traffic redirected.
+func (n *nftables) redirectInputPort(ctx context.Context, intf string,
+	redirection models.PortRedirection, remove bool) error {
+	for _, protocol := range [...]string{"tcp", "udp"} {
+		rule := fmt.Sprintf("iifname %s %s dport %d redirect to :%d",
+			nftablesInterface(intf), protocol, redirection.Port, redirection.TargetPort)
+		if err := n.setRule(ctx, "redirect", rule, remove); err != nil {
+			return err
+		}
+		rule = fmt.Sprintf("iifname %s %s dport %d ct status dnat accept",
+			nftablesInterface(intf), protocol, redirection.TargetPort)
+		if err := n.setRule(ctx, "input", rule, remove); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// redirectFromSubnetToPort redirects the TCP traffic from the source
+// subnet through the interface to the port to the target port.
+func (n *nftables) redirectFromSubnetToPort(ctx context.Context, intf string,
+	source net.IPNet, port, targetPort uint16, remove bool) error {
+	rule := fmt.Sprintf("iifname %s %s saddr %s tcp dport %d redirect to :%d",
+		nftablesInterface(intf), nftablesFamily(source.IP), source.String(), port, targetPort)
+	return n.setRule(ctx, "redirect", rule, remove)
+}
+
+// acceptForwardFromSubnet accepts forwarding the traffic from the source
+// subnet through the interface, and its replies.
+func (n *nftables) acceptForwardFromSubnet(ctx context.Context,
+	intf string, source net.IPNet, remove bool) error {
+	family := nftablesFamily(source.IP)
+	rule := fmt.Sprintf("%s saddr %s oifname %s accept",
+		family, source.String(), nftablesInterface(intf))
+	if err := n.setRule(ctx, "forward", rule, remove); err != nil {
+		return err
+	}
+	rule = fmt.Sprintf("%s daddr %s iifname %s ct state established,related accept",
+		family, source.String(), nftablesInterface(intf))
+	return n.setRule(ctx, "forward", rule, remove)
+}
+
+// masqueradeFromSubnet changes the source address of the packets from
+// the source subnet to the address of the interface.
+func (n *nftables) masqueradeFromSubnet(ctx context.Context,
+	intf string, source net.IPNet, remove bool) error {
+	rule := fmt.Sprintf("%s saddr %s oifname %s masquerade",
+		nftablesFamily(source.IP), source.String(), nftablesInterface(intf))
+	return n.setRule(ctx, "postrouting", rule, remove)
+}
+
+// markFromSubnet marks the packets from the source subnet so they are
+// routed through the default interface.
+func (n *nftables) markFromSubnet(ctx context.Context, source net.IPNet, remove bool) error {
+	rule := fmt.Sprintf("%s saddr %s meta mark set %d",
+		nftablesFamily(source.IP), source.String(), constants.BypassSourcesMark)
+	return n.setRule(ctx, "prerouting", rule, remove)
+}
+
+// markOutputFromUID marks the packets from the processes running with
+// the user ID given so they are routed through the default interface.
+func (n *nftables) markOutputFromUID(ctx context.Context, uid int, remove bool) error {
+	rule := fmt.Sprintf("meta skuid %d meta mark set %d", uid, constants.BypassSourcesMark)
+	return n.setRule(ctx, "route", rule, remove)
+}
+
+// acceptOutputFromUID accepts the traffic from the processes running
+// with the user ID given through the interface.
+func (n *nftables) acceptOutputFromUID(ctx context.Context, intf string, uid int, remove bool) error {
+	rule := fmt.Sprintf("oifname %s meta skuid %d accept", nftablesInterface(intf), uid)
+	return n.setRule(ctx, "output", rule, remove)
+}
+
+// masqueradeBypassed changes the source address of the marked packets
+// bypassing the VPN to the address of the interface.
+func (n *nftables) masqueradeBypassed(ctx context.Context, intf string, remove bool) error {
+	rule := fmt.Sprintf("oifname %s meta mark %d masquerade",
+		nftablesInterface(intf), constants.BypassSourcesMark)
+	return n.setRule(ctx, "postrouting", rule, remove)
+}
+
+// runUserPostRules runs the user nft commands, for example
+// "nft add rule inet gluetun input tcp dport 22 accept".
+// User rules cannot be removed individually since their handles are
+// unknown, and are removed together with the gluetun table instead.
+func (n *nftables) runUserPostRules(ctx context.Context, filepath string, remove bool) error {
+	if remove {
+		return nil
+	}
+	file, err := n.openFile(filepath, os.O_RDONLY, 0)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadAll(file)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		if !strings.HasPrefix(line, "nft ") {
+			continue
+		}
+		if _, err := n.run(ctx, strings.TrimPrefix(line, "nft ")); err != nil {
+			return err
+		}
+	}
+	return n.trackUserHandles(ctx)
+}
+
+// trackUserHandles records the handles of the rules not added by
+// setRule as the user post rules handles.
+func (n *nftables) trackUserHandles(ctx context.Context) error {
+	output, err := n.run(ctx, "--handle list table "+nftablesTable)
+	if err != nil {
+		return err
+	}
+	handles := n.expectedHandles()
+	for handle := range parseNFTablesHandles(output) {
+		if _, ok := handles[handle]; !ok {
+			n.userHandles[handle] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// expectedHandles returns the handles of all the rules added.
+func (n *nftables) expectedHandles() (handles map[string]struct{}) {
+	handles = make(map[string]struct{}, len(n.handles)+len(n.userHandles))
+	for _, ruleHandles := range n.handles {
+		for _, handle := range ruleHandles {
+			handles[handle] = struct{}{}
+		}
+	}
+	for handle := range n.userHandles {
+		handles[handle] = struct{}{}
+	}
+	return handles
+}
+
+// parseNFTablesHandles returns the rule handles of the table listed
+// with nft --handle list table.
+func parseNFTablesHandles(output string) (handles map[string]struct{}) {
+	handles = make(map[string]struct{})
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "table ") || strings.HasPrefix(line, "chain ") {
+			continue // table and chains have handles as well
+		}
+		match := regexHandle.FindStringSubmatch(line)
+		if match != nil {
+			handles[match[1]] = struct{}{}
+		}
+	}
+	return handles
+}
+
+// tampered returns true if the table was deleted, if a rule was added
+// or removed, or if the policy of a filter chain was changed.
+func (n *nftables) tampered(ctx context.Context) (tampered bool, err error) {
+	output, err := n.run(ctx, "--handle list table "+nftablesTable)
+	if err != nil {
+		return true, nil //nolint:nilerr
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.Contains(line, " hook ") || !strings.Contains(line, "type filter") {
+			continue
+		}
+		for _, hook := range [...]string{"input", "output", "forward"} {
+			if strings.Contains(line, " hook "+hook+" ") &&
+				!strings.Contains(line, "policy "+n.policy+";") {
+				return true, nil
+			}
+		}
+	}
+
+	handles := parseNFTablesHandles(output)
+	expectedHandles := n.expectedHandles()
+	if len(handles) != len(expectedHandles) {
+		return true, nil
+	}
+	for handle := range expectedHandles {
+		if _, ok := handles[handle]; !ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// listCounters returns the table rules, which contain their counters.
+func (n *nftables) listCounters(ctx context.Context) (counters string, err error) {
+	return n.listRules(ctx)
+}
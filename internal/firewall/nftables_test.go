@@ -0,0 +1,82 @@
+package firewall
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/command/mock_command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_nftables_acceptOutputTrafficToVPN(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	connection := models.OpenVPNConnection{
+		IP:       net.IPv4(1, 2, 3, 4),
+		Port:     1194,
+		Protocol: "udp",
+	}
+
+	commander := mock_command.NewMockCommander(ctrl)
+	gomock.InOrder(
+		commander.EXPECT().Run(ctx, "nft", "--echo", "--handle", "add", "rule",
+			"inet", "gluetun", "output", "oifname", `"eth0"`, "ip", "daddr", "1.2.3.4",
+			"udp", "dport", "1194", "counter", "accept").
+			Return(`add rule inet gluetun output oifname "eth0" ip daddr 1.2.3.4 udp dport 1194 accept # handle 7`, nil),
+		commander.EXPECT().Run(ctx, "nft", "delete", "rule", "inet", "gluetun", "output", "handle", "7").
+			Return("", nil),
+	)
+
+	debug, audit := false, false
+	n := newNFTables(commander, nil, nil, &debug, &audit)
+
+	const remove = false
+	err := n.acceptOutputTrafficToVPN(ctx, "eth0", connection, remove)
+	require.NoError(t, err)
+
+	err = n.acceptOutputTrafficToVPN(ctx, "eth0", connection, !remove)
+	require.NoError(t, err)
+
+	err = n.acceptOutputTrafficToVPN(ctx, "eth0", connection, !remove)
+	assert.ErrorIs(t, err, ErrNFTablesRuleNotFound)
+}
+
+func Test_nftablesInterface(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, `"tun0"`, nftablesInterface("tun0"))
+	assert.Equal(t, `"tun*"`, nftablesInterface("tun+"))
+}
+
+func Test_withCounter(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, `iifname "lo" counter accept`, withCounter(`iifname "lo" accept`))
+	assert.Equal(t, "ct mark 1 meta mark set ct mark", withCounter("ct mark 1 meta mark set ct mark"))
+}
+
+func Test_nftables_acceptOutputFromIPToSubnet(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	_, ipv6Subnet, err := net.ParseCIDR("fd00::/64")
+	require.NoError(t, err)
+
+	commander := mock_command.NewMockCommander(ctrl)
+	commander.EXPECT().Run(ctx, "nft", "--echo", "--handle", "add", "rule",
+		"inet", "gluetun", "output", "oifname", `"eth0"`, "ip6", "daddr", "fd00::/64",
+		"counter", "accept").
+		Return(`add rule inet gluetun output oifname "eth0" ip6 daddr fd00::/64 counter accept # handle 3`, nil)
+
+	debug, audit := false, false
+	n := newNFTables(commander, nil, nil, &debug, &audit)
+
+	const remove = false
+	err = n.acceptOutputFromIPToSubnet(ctx, "eth0", net.IPv4(10, 0, 0, 2), *ipv6Subnet, remove)
+	assert.NoError(t, err)
+}
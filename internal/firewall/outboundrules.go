@@ -0,0 +1,95 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// SetOutboundRules accepts the traffic from the local IP address to the
+// destinations and ports of the rules through the default interface.
+func (c *configurator) SetOutboundRules(ctx context.Context, rules []models.OutboundRule) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if c.lockedDown {
+		return ErrLockedDown
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating outbound rules internal list")
+		c.outboundRules = copyOutboundRules(rules)
+		return nil
+	}
+
+	c.logger.Info("setting outbound rules through firewall...")
+	if err := c.replaceOutboundRules(ctx, &c.outboundRules, rules); err != nil {
+		return fmt.Errorf("cannot set outbound rules through firewall: %w", err)
+	}
+	return nil
+}
+
+// SetBootstrapRules accepts the traffic from the local IP address to the
+// destinations and ports of the rules through the default interface, to
+// obtain data from VPN provider APIs before connecting to the VPN.
+// Empty rules remove the bootstrap rules.
+func (c *configurator) SetBootstrapRules(ctx context.Context, rules []models.OutboundRule) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if c.lockedDown {
+		return ErrLockedDown
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating bootstrap rules internal list")
+		c.bootstrapRules = copyOutboundRules(rules)
+		return nil
+	}
+
+	c.logger.Info("setting bootstrap rules through firewall...")
+	if err := c.replaceOutboundRules(ctx, &c.bootstrapRules, rules); err != nil {
+		return fmt.Errorf("cannot set bootstrap rules through firewall: %w", err)
+	}
+	return nil
+}
+
+func copyOutboundRules(rules []models.OutboundRule) (copied []models.OutboundRule) {
+	copied = make([]models.OutboundRule, len(rules))
+	copy(copied, rules)
+	return copied
+}
+
+// replaceOutboundRules removes the current rules and sets the new rules.
+func (c *configurator) replaceOutboundRules(ctx context.Context,
+	current *[]models.OutboundRule, rules []models.OutboundRule) (err error) {
+	const remove = true
+	if err := c.setOutboundRules(ctx, *current, remove); err != nil {
+		c.logger.Error("cannot remove outdated rules through firewall: %s", err)
+	}
+	*current = nil
+	if err := c.setOutboundRules(ctx, rules, !remove); err != nil {
+		return err
+	}
+	*current = copyOutboundRules(rules)
+	c.moveLogDroppedLast(ctx)
+	return nil
+}
+
+func (c *configurator) setOutboundRules(ctx context.Context,
+	rules []models.OutboundRule, remove bool) (err error) {
+	for _, rule := range rules {
+		protocols := []string{rule.Protocol}
+		if rule.Protocol == "" {
+			protocols = []string{"tcp", "udp"}
+		}
+		for _, protocol := range protocols {
+			if err := c.rules.acceptOutputFromIPToSubnetPort(ctx, c.defaultInterface,
+				c.localIP, rule.Subnet, protocol, rule.Port, remove); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
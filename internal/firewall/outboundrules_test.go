@@ -0,0 +1,86 @@
+package firewall
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/command/mock_command"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_configurator_SetBootstrapRules(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	logger := mock_logging.NewMockLogger(ctrl)
+	logger.EXPECT().Info("setting bootstrap rules through firewall...").Times(3)
+	commander := mock_command.NewMockCommander(ctrl)
+	commander.EXPECT().Run(ctx, "iptables", gomock.Any()).
+		Return("", nil).AnyTimes()
+
+	c := &configurator{
+		commander:        commander,
+		logger:           logger,
+		defaultInterface: "eth0",
+		localIP:          net.IPv4(192, 168, 1, 2),
+		enabled:          true,
+	}
+	c.rules = c
+
+	apiRule := models.OutboundRule{
+		Subnet:   net.IPNet{IP: net.IP{1, 1, 1, 1}, Mask: net.CIDRMask(32, 32)},
+		Port:     443,
+		Protocol: "tcp",
+	}
+	probeRule := models.OutboundRule{
+		Subnet: net.IPNet{IP: net.IP{2, 2, 2, 2}, Mask: net.CIDRMask(32, 32)},
+		Port:   1194,
+	}
+
+	err := c.SetBootstrapRules(ctx, []models.OutboundRule{apiRule})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"OUTPUT -o eth0 -s 192.168.1.2 -d 1.1.1.1/32 -p tcp -m tcp --dport 443 -j ACCEPT",
+	}, c.ipv4State.rules)
+
+	// allow the latency probes in addition to the API rule
+	err = c.SetBootstrapRules(ctx, []models.OutboundRule{probeRule, apiRule})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"OUTPUT -o eth0 -s 192.168.1.2 -d 2.2.2.2/32 -p tcp -m tcp --dport 1194 -j ACCEPT",
+		"OUTPUT -o eth0 -s 192.168.1.2 -d 2.2.2.2/32 -p udp -m udp --dport 1194 -j ACCEPT",
+		"OUTPUT -o eth0 -s 192.168.1.2 -d 1.1.1.1/32 -p tcp -m tcp --dport 443 -j ACCEPT",
+	}, c.ipv4State.rules)
+
+	err = c.SetBootstrapRules(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, c.ipv4State.rules)
+	assert.Empty(t, c.bootstrapRules)
+}
+
+func Test_configurator_SetBootstrapRules_disabled(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	logger := mock_logging.NewMockLogger(ctrl)
+	logger.EXPECT().Info("firewall disabled, only updating bootstrap rules internal list")
+
+	c := &configurator{logger: logger}
+	c.rules = c
+
+	rules := []models.OutboundRule{{
+		Subnet: net.IPNet{IP: net.IP{1, 1, 1, 1}, Mask: net.CIDRMask(32, 32)},
+		Port:   443,
+	}}
+	err := c.SetBootstrapRules(context.Background(), rules)
+
+	require.NoError(t, err)
+	assert.Equal(t, rules, c.bootstrapRules)
+	assert.Empty(t, c.ipv4State.rules)
+}
@@ -10,6 +10,10 @@ func (c *configurator) SetOutboundSubnets(ctx context.Context, subnets []net.IPN
 	c.stateMutex.Lock()
 	defer c.stateMutex.Unlock()
 
+	if c.lockedDown {
+		return ErrLockedDown
+	}
+
 	if !c.enabled {
 		c.logger.Info("firewall disabled, only updating allowed subnets internal list")
 		c.outboundSubnets = make([]net.IPNet, len(subnets))
@@ -29,6 +33,7 @@ func (c *configurator) SetOutboundSubnets(ctx context.Context, subnets []net.IPN
 	if err := c.addOutboundSubnets(ctx, subnetsToAdd); err != nil {
 		return fmt.Errorf("cannot set allowed subnets through firewall: %w", err)
 	}
+	c.moveLogDroppedLast(ctx)
 
 	return nil
 }
@@ -36,10 +41,13 @@ func (c *configurator) SetOutboundSubnets(ctx context.Context, subnets []net.IPN
 func (c *configurator) removeOutboundSubnets(ctx context.Context, subnets []net.IPNet) {
 	const remove = true
 	for _, subnet := range subnets {
-		if err := c.acceptOutputFromIPToSubnet(ctx, c.defaultInterface, c.localIP, subnet, remove); err != nil {
+		if err := c.rules.acceptOutputFromIPToSubnet(ctx, c.defaultInterface, c.localIP, subnet, remove); err != nil {
 			c.logger.Error("cannot remove outdated outbound subnet through firewall: %s", err)
 			continue
 		}
+		if err := c.acceptDNSFromSubnet(ctx, subnet, remove); err != nil {
+			c.logger.Error("cannot remove outdated outbound subnet through firewall: %s", err)
+		}
 		c.outboundSubnets = removeSubnetFromSubnets(c.outboundSubnets, subnet)
 	}
 }
@@ -47,7 +55,10 @@ func (c *configurator) removeOutboundSubnets(ctx context.Context, subnets []net.
 func (c *configurator) addOutboundSubnets(ctx context.Context, subnets []net.IPNet) error {
 	const remove = false
 	for _, subnet := range subnets {
-		if err := c.acceptOutputFromIPToSubnet(ctx, c.defaultInterface, c.localIP, subnet, remove); err != nil {
+		if err := c.rules.acceptOutputFromIPToSubnet(ctx, c.defaultInterface, c.localIP, subnet, remove); err != nil {
+			return fmt.Errorf("cannot add allowed subnet through firewall: %w", err)
+		}
+		if err := c.acceptDNSFromSubnet(ctx, subnet, remove); err != nil {
 			return fmt.Errorf("cannot add allowed subnet through firewall: %w", err)
 		}
 		c.outboundSubnets = append(c.outboundSubnets, subnet)
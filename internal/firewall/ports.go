@@ -9,6 +9,10 @@ func (c *configurator) SetAllowedPort(ctx context.Context, port uint16, intf str
 	c.stateMutex.Lock()
 	defer c.stateMutex.Unlock()
 
+	if c.lockedDown {
+		return ErrLockedDown
+	}
+
 	if port == 0 {
 		return nil
 	}
@@ -21,21 +25,25 @@ func (c *configurator) SetAllowedPort(ctx context.Context, port uint16, intf str
 
 	c.logger.Info("setting allowed input port %d through interface %s...", port, intf)
 
-	if existingIntf, ok := c.allowedInputPorts[port]; ok {
-		if intf == existingIntf {
-			return nil
-		}
-		const remove = true
-		if err := c.acceptInputToPort(ctx, existingIntf, port, remove); err != nil {
-			return fmt.Errorf("cannot remove old allowed port %d through interface %s: %w", port, existingIntf, err)
-		}
+	existingIntf, exists := c.allowedInputPorts[port]
+	if exists && intf == existingIntf {
+		return nil
 	}
 
+	// accept the new rule before removing the old rule, to not
+	// interrupt the traffic to the port.
 	const remove = false
-	if err := c.acceptInputToPort(ctx, intf, port, remove); err != nil {
+	if err := c.rules.acceptInputToPort(ctx, intf, port, remove); err != nil {
 		return fmt.Errorf("cannot set allowed port %d through interface %s: %w", port, intf, err)
 	}
+
+	if exists {
+		if err := c.rules.acceptInputToPort(ctx, existingIntf, port, !remove); err != nil {
+			return fmt.Errorf("cannot remove old allowed port %d through interface %s: %w", port, existingIntf, err)
+		}
+	}
 	c.allowedInputPorts[port] = intf
+	c.moveLogDroppedLast(ctx)
 
 	return nil
 }
@@ -44,6 +52,10 @@ func (c *configurator) RemoveAllowedPort(ctx context.Context, port uint16) (err
 	c.stateMutex.Lock()
 	defer c.stateMutex.Unlock()
 
+	if c.lockedDown {
+		return ErrLockedDown
+	}
+
 	if port == 0 {
 		return nil
 	}
@@ -62,7 +74,7 @@ func (c *configurator) RemoveAllowedPort(ctx context.Context, port uint16) (err
 	}
 
 	const remove = true
-	if err := c.acceptInputToPort(ctx, intf, port, remove); err != nil {
+	if err := c.rules.acceptInputToPort(ctx, intf, port, remove); err != nil {
 		return fmt.Errorf("cannot remove allowed port %d through interface %s: %w", port, intf, err)
 	}
 	delete(c.allowedInputPorts, port)
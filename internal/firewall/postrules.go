@@ -0,0 +1,70 @@
+package firewall
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUserPostRuleNotValid = errors.New("user post rule is not valid")
+
+// userPostRule is an iptables or ip6tables rule from the user post rules file.
+type userPostRule struct {
+	ipv4 bool
+	rule string
+}
+
+// parseUserPostRules parses the user post rules file content, which can
+// contain iptables and ip6tables commands such as
+// "iptables -A INPUT -p tcp --dport 22 -j ACCEPT", and iptables-save style
+// sections applied with iptables, such as:
+//
+//	*nat
+//	:CUSTOM - [0:0]
+//	-A POSTROUTING -o eth0 -j MASQUERADE
+//	COMMIT
+//
+// Built-in chain policies of iptables-save style sections are ignored,
+// since the policies are set by the firewall.
+func parseUserPostRules(content string) (rules []userPostRule, err error) {
+	table := ""
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "", strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "iptables "):
+			rules = append(rules, userPostRule{ipv4: true, rule: strings.TrimPrefix(line, "iptables ")})
+		case strings.HasPrefix(line, "ip6tables "):
+			rules = append(rules, userPostRule{rule: strings.TrimPrefix(line, "ip6tables ")})
+		case strings.HasPrefix(line, "*"):
+			table = strings.TrimPrefix(line, "*")
+		case line == "COMMIT":
+			table = ""
+		case table == "":
+			return nil, fmt.Errorf("%w: line %d is outside of a table section: %s",
+				ErrUserPostRuleNotValid, i+1, line)
+		case strings.HasPrefix(line, ":"):
+			fields := strings.Fields(strings.TrimPrefix(line, ":"))
+			const minFields = 2
+			if len(fields) < minFields {
+				return nil, fmt.Errorf("%w: line %d: %s", ErrUserPostRuleNotValid, i+1, line)
+			}
+			if fields[1] != "-" { // built-in chain policy
+				continue
+			}
+			rule := "-N " + fields[0] + " -t " + table
+			rules = append(rules, userPostRule{ipv4: true, rule: rule})
+		case strings.HasPrefix(line, "-A "), strings.HasPrefix(line, "-I "):
+			rule := line + " -t " + table
+			rules = append(rules, userPostRule{ipv4: true, rule: rule})
+		default:
+			return nil, fmt.Errorf("%w: line %d: %s", ErrUserPostRuleNotValid, i+1, line)
+		}
+	}
+
+	if table != "" {
+		return nil, fmt.Errorf("%w: table %s section is missing COMMIT", ErrUserPostRuleNotValid, table)
+	}
+
+	return rules, nil
+}
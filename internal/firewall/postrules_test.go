@@ -0,0 +1,125 @@
+package firewall
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseUserPostRules(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		content string
+		rules   []userPostRule
+		err     error
+	}{
+		"empty": {},
+		"commands": {
+			content: "# comment\niptables -A INPUT -p tcp --dport 22 -j ACCEPT\n" +
+				"ip6tables -A INPUT -p tcp --dport 22 -j ACCEPT\n",
+			rules: []userPostRule{
+				{ipv4: true, rule: "-A INPUT -p tcp --dport 22 -j ACCEPT"},
+				{rule: "-A INPUT -p tcp --dport 22 -j ACCEPT"},
+			},
+		},
+		"iptables-save": {
+			content: `*filter
+:INPUT ACCEPT [0:0]
+:CUSTOM - [0:0]
+-A INPUT -j CUSTOM
+COMMIT
+*nat
+-A POSTROUTING -o eth0 -j MASQUERADE
+COMMIT`,
+			rules: []userPostRule{
+				{ipv4: true, rule: "-N CUSTOM -t filter"},
+				{ipv4: true, rule: "-A INPUT -j CUSTOM -t filter"},
+				{ipv4: true, rule: "-A POSTROUTING -o eth0 -j MASQUERADE -t nat"},
+			},
+		},
+		"rule outside table": {
+			content: "-A INPUT -j ACCEPT",
+			err: errors.New("user post rule is not valid: " +
+				"line 1 is outside of a table section: -A INPUT -j ACCEPT"),
+		},
+		"missing commit": {
+			content: "*filter\n-A INPUT -j ACCEPT",
+			err:     errors.New("user post rule is not valid: table filter section is missing COMMIT"),
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			rules, err := parseUserPostRules(testCase.content)
+			if testCase.err != nil {
+				assert.EqualError(t, err, testCase.err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.rules, rules)
+		})
+	}
+}
+
+func Test_flipRule(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		rule    string
+		flipped string
+	}{
+		"append": {
+			rule:    "-A INPUT -j ACCEPT",
+			flipped: "-D INPUT -j ACCEPT",
+		},
+		"long append": {
+			rule:    "--append INPUT -j ACCEPT",
+			flipped: "-D INPUT -j ACCEPT",
+		},
+		"insert": {
+			rule:    "-I INPUT -j ACCEPT",
+			flipped: "-D INPUT -j ACCEPT",
+		},
+		"insert at position": {
+			rule:    "-I INPUT 1 -p tcp --dport 8080 -j ACCEPT",
+			flipped: "-D INPUT -p tcp --dport 8080 -j ACCEPT",
+		},
+		"long insert at position": {
+			rule:    "--insert OUTPUT 3 -t nat -j ACCEPT",
+			flipped: "-D OUTPUT -t nat -j ACCEPT",
+		},
+		"insert chain only": {
+			rule:    "-I INPUT",
+			flipped: "-D INPUT",
+		},
+		"delete": {
+			rule:    "-D INPUT -j ACCEPT",
+			flipped: "-A INPUT -j ACCEPT",
+		},
+		"long delete": {
+			rule:    "--delete INPUT -j ACCEPT",
+			flipped: "-A INPUT -j ACCEPT",
+		},
+		"new chain": {
+			rule:    "-N CUSTOM -t nat",
+			flipped: "-X CUSTOM -t nat",
+		},
+		"delete chain": {
+			rule:    "-X CUSTOM -t nat",
+			flipped: "-N CUSTOM -t nat",
+		},
+		"policy unchanged": {
+			rule:    "-P INPUT DROP",
+			flipped: "-P INPUT DROP",
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			flipped := flipRule(testCase.rule)
+			assert.Equal(t, testCase.flipped, flipped)
+		})
+	}
+}
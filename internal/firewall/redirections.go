@@ -0,0 +1,55 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// SetPortRedirections redirects the input traffic through the interface
+// to each port given to its target port, and accepts the traffic
+// redirected. It replaces the redirections previously set.
+func (c *configurator) SetPortRedirections(ctx context.Context, intf string,
+	redirections []models.PortRedirection) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if c.lockedDown {
+		return ErrLockedDown
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating port redirections internal list")
+		c.redirectionsIntf = intf
+		c.redirections = make([]models.PortRedirection, len(redirections))
+		copy(c.redirections, redirections)
+		return nil
+	}
+
+	c.logger.Info("setting port redirections through interface %s...", intf)
+
+	const remove = true
+	if err := c.setPortRedirections(ctx, c.redirectionsIntf, c.redirections, remove); err != nil {
+		c.logger.Error("cannot remove outdated port redirections through firewall: %s", err)
+	}
+	c.redirections = nil
+	if err := c.setPortRedirections(ctx, intf, redirections, !remove); err != nil {
+		return fmt.Errorf("cannot set port redirections through firewall: %w", err)
+	}
+	c.redirectionsIntf = intf
+	c.redirections = make([]models.PortRedirection, len(redirections))
+	copy(c.redirections, redirections)
+	c.moveLogDroppedLast(ctx)
+	return nil
+}
+
+func (c *configurator) setPortRedirections(ctx context.Context, intf string,
+	redirections []models.PortRedirection, remove bool) (err error) {
+	for _, redirection := range redirections {
+		if err := c.rules.redirectInputPort(ctx, intf, redirection, remove); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// SetResolverConnection accepts traffic to the DNS resolver connection
+// given through the default interface, to resolve hostnames outside the
+// VPN. An empty connection removes the resolver connection, which should
+// be done as soon as the hostnames are resolved.
+func (c *configurator) SetResolverConnection(ctx context.Context, connection models.OpenVPNConnection) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if c.lockedDown {
+		return ErrLockedDown
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating internal resolver connection")
+		c.resolverConnection = connection
+		return nil
+	}
+
+	if c.resolverConnection.Equal(connection) {
+		return nil
+	}
+
+	c.logger.Info("setting resolver connection through firewall...")
+
+	remove := true
+	if c.resolverConnection.IP != nil {
+		if err := c.rules.acceptOutputTrafficToVPN(ctx, c.defaultInterface, c.resolverConnection, remove); err != nil {
+			c.logger.Error("cannot remove outdated resolver connection through firewall: %s", err)
+		}
+	}
+	c.resolverConnection = models.OpenVPNConnection{}
+	if connection.IP == nil {
+		return nil
+	}
+	remove = false
+	if err := c.rules.acceptOutputTrafficToVPN(ctx, c.defaultInterface, connection, remove); err != nil {
+		return fmt.Errorf("cannot set resolver connection through firewall: %w", err)
+	}
+	c.resolverConnection = connection
+	c.moveLogDroppedLast(ctx)
+	return nil
+}
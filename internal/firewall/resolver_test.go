@@ -0,0 +1,52 @@
+package firewall
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/command/mock_command"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_configurator_SetResolverConnection(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	logger := mock_logging.NewMockLogger(ctrl)
+	logger.EXPECT().Info("setting resolver connection through firewall...").Times(2)
+
+	const rule = "OUTPUT -d 1.1.1.1 -o eth0 -p udp -m udp --dport 53 -j ACCEPT"
+	commander := mock_command.NewMockCommander(ctrl)
+	gomock.InOrder(
+		commander.EXPECT().Run(ctx, "iptables", "--append", "OUTPUT", "-d", "1.1.1.1",
+			"-o", "eth0", "-p", "udp", "-m", "udp", "--dport", "53", "-j", "ACCEPT").
+			Return("", nil),
+		commander.EXPECT().Run(ctx, "iptables", "--delete", "OUTPUT", "-d", "1.1.1.1",
+			"-o", "eth0", "-p", "udp", "-m", "udp", "--dport", "53", "-j", "ACCEPT").
+			Return("", nil),
+	)
+
+	c := &configurator{
+		commander:        commander,
+		logger:           logger,
+		defaultInterface: "eth0",
+		enabled:          true,
+	}
+	c.rules = c
+
+	connection := models.OpenVPNConnection{IP: net.IPv4(1, 1, 1, 1), Port: 53, Protocol: "udp"}
+	err := c.SetResolverConnection(ctx, connection)
+	require.NoError(t, err)
+	assert.Equal(t, []string{rule}, c.ipv4State.rules)
+
+	err = c.SetResolverConnection(ctx, models.OpenVPNConnection{})
+	require.NoError(t, err)
+	assert.Empty(t, c.ipv4State.rules)
+	assert.Equal(t, models.OpenVPNConnection{}, c.resolverConnection)
+}
@@ -0,0 +1,143 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// SetForwardedSources accepts forwarding the traffic from the VPN sources
+// subnets through the VPN interface, and from the bypass sources subnets
+// through the default interface. The packets from the bypass sources are
+// marked so they are routed through the default interface.
+func (c *configurator) SetForwardedSources(ctx context.Context,
+	vpnSources, bypassSources []net.IPNet) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if c.lockedDown {
+		return ErrLockedDown
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating forwarded sources internal lists")
+		c.vpnSources = copySubnets(vpnSources)
+		c.bypassSources = copySubnets(bypassSources)
+		return nil
+	}
+
+	c.logger.Info("setting forwarded sources through firewall...")
+
+	const remove = true
+	if err := c.setVPNSources(ctx, c.vpnIntf, c.vpnSources, remove); err != nil {
+		c.logger.Error("cannot remove outdated VPN sources through firewall: %s", err)
+	}
+	c.vpnSources = nil
+	if err := c.setBypassSources(ctx, c.bypassSources, remove); err != nil {
+		c.logger.Error("cannot remove outdated bypass sources through firewall: %s", err)
+	}
+	c.bypassSources = nil
+
+	if err := c.setVPNSources(ctx, c.vpnIntf, vpnSources, !remove); err != nil {
+		return fmt.Errorf("cannot set VPN sources through firewall: %w", err)
+	}
+	c.vpnSources = copySubnets(vpnSources)
+	if err := c.setBypassSources(ctx, bypassSources, !remove); err != nil {
+		return fmt.Errorf("cannot set bypass sources through firewall: %w", err)
+	}
+	c.bypassSources = copySubnets(bypassSources)
+	c.moveLogDroppedLast(ctx)
+	return nil
+}
+
+func copySubnets(subnets []net.IPNet) (copied []net.IPNet) {
+	copied = make([]net.IPNet, len(subnets))
+	copy(copied, subnets)
+	return copied
+}
+
+func (c *configurator) setVPNSources(ctx context.Context, vpnIntf string,
+	sources []net.IPNet, remove bool) (err error) {
+	for _, source := range sources {
+		if err := c.rules.acceptForwardFromSubnet(ctx, vpnIntf, source, remove); err != nil {
+			return err
+		}
+		if err := c.rules.masqueradeFromSubnet(ctx, vpnIntf, source, remove); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *configurator) setBypassSources(ctx context.Context,
+	sources []net.IPNet, remove bool) (err error) {
+	for _, source := range sources {
+		if err := c.rules.acceptForwardFromSubnet(ctx, c.defaultInterface, source, remove); err != nil {
+			return err
+		}
+		if err := c.rules.masqueradeFromSubnet(ctx, c.defaultInterface, source, remove); err != nil {
+			return err
+		}
+		if err := c.rules.markFromSubnet(ctx, source, remove); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetBypassUIDs accepts the traffic from the processes running with the
+// user IDs given through the default interface, and marks it so it is
+// routed through the default interface instead of the VPN. This is meant
+// for containers sharing the network namespace, which all have the same
+// source IP addresses and cannot be told apart with source subnets.
+func (c *configurator) SetBypassUIDs(ctx context.Context, uids []int) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if c.lockedDown {
+		return ErrLockedDown
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating bypass user IDs internal list")
+		c.bypassUIDs = copyUIDs(uids)
+		return nil
+	}
+
+	c.logger.Info("setting bypass user IDs through firewall...")
+
+	const remove = true
+	if err := c.setBypassUIDs(ctx, c.bypassUIDs, remove); err != nil {
+		c.logger.Error("cannot remove outdated bypass user IDs through firewall: %s", err)
+	}
+	c.bypassUIDs = nil
+	if err := c.setBypassUIDs(ctx, uids, !remove); err != nil {
+		return fmt.Errorf("cannot set bypass user IDs through firewall: %w", err)
+	}
+	c.bypassUIDs = copyUIDs(uids)
+	c.moveLogDroppedLast(ctx)
+	return nil
+}
+
+func copyUIDs(uids []int) (copied []int) {
+	copied = make([]int, len(uids))
+	copy(copied, uids)
+	return copied
+}
+
+func (c *configurator) setBypassUIDs(ctx context.Context, uids []int, remove bool) (err error) {
+	if len(uids) == 0 {
+		return nil
+	}
+	for _, uid := range uids {
+		if err := c.rules.markOutputFromUID(ctx, uid, remove); err != nil {
+			return err
+		}
+		if err := c.rules.acceptOutputFromUID(ctx, c.defaultInterface, uid, remove); err != nil {
+			return err
+		}
+	}
+	// the source address is selected before the packets are marked
+	// and rerouted, so it is the VPN address and must be changed.
+	return c.rules.masqueradeBypassed(ctx, c.defaultInterface, remove)
+}
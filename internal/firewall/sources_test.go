@@ -0,0 +1,141 @@
+package firewall
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/golibs/command/mock_command"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_configurator_SetForwardedSources(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	logger := mock_logging.NewMockLogger(ctrl)
+	logger.EXPECT().Info("setting forwarded sources through firewall...").Times(2)
+	commander := mock_command.NewMockCommander(ctrl)
+	commander.EXPECT().Run(ctx, "iptables", gomock.Any()).
+		Return("", nil).AnyTimes()
+
+	c := &configurator{
+		commander:        commander,
+		logger:           logger,
+		defaultInterface: "eth0",
+		vpnIntf:          "tun0",
+		enabled:          true,
+	}
+	c.rules = c
+
+	vpnSource := net.IPNet{IP: net.IP{172, 18, 0, 2}, Mask: net.CIDRMask(32, 32)}
+	bypassSource := net.IPNet{IP: net.IP{172, 18, 0, 3}, Mask: net.CIDRMask(32, 32)}
+
+	err := c.SetForwardedSources(ctx, []net.IPNet{vpnSource}, []net.IPNet{bypassSource})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"FORWARD -s 172.18.0.2/32 -o tun0 -j ACCEPT",
+		"FORWARD -d 172.18.0.2/32 -i tun0 -m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT",
+		"POSTROUTING --table nat -s 172.18.0.2/32 -o tun0 -j MASQUERADE",
+		"FORWARD -s 172.18.0.3/32 -o eth0 -j ACCEPT",
+		"FORWARD -d 172.18.0.3/32 -i eth0 -m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT",
+		"POSTROUTING --table nat -s 172.18.0.3/32 -o eth0 -j MASQUERADE",
+		"PREROUTING --table mangle -s 172.18.0.3/32 -j MARK --set-mark 6453616",
+	}, c.ipv4State.rules)
+	assert.Equal(t, []net.IPNet{vpnSource}, c.vpnSources)
+	assert.Equal(t, []net.IPNet{bypassSource}, c.bypassSources)
+
+	err = c.SetForwardedSources(ctx, nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, c.ipv4State.rules)
+	assert.Empty(t, c.vpnSources)
+	assert.Empty(t, c.bypassSources)
+}
+
+func Test_configurator_SetForwardedSources_ipv6(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	logger := mock_logging.NewMockLogger(ctrl)
+	logger.EXPECT().Info("setting forwarded sources through firewall...")
+
+	c := &configurator{
+		logger:  logger,
+		vpnIntf: "tun0",
+		enabled: true,
+	}
+	c.rules = c
+
+	source := net.IPNet{IP: net.ParseIP("fd00::2"), Mask: net.CIDRMask(128, 128)}
+	err := c.SetForwardedSources(context.Background(), []net.IPNet{source}, nil)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNeedIP6Tables)
+	assert.Empty(t, c.vpnSources)
+}
+
+func Test_configurator_SetBypassUIDs(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	logger := mock_logging.NewMockLogger(ctrl)
+	logger.EXPECT().Info("setting bypass user IDs through firewall...").Times(3)
+	commander := mock_command.NewMockCommander(ctrl)
+	commander.EXPECT().Run(ctx, "iptables", gomock.Any()).
+		Return("", nil).AnyTimes()
+
+	c := &configurator{
+		commander:        commander,
+		logger:           logger,
+		defaultInterface: "eth0",
+		vpnIntf:          "tun0",
+		enabled:          true,
+	}
+	c.rules = c
+
+	err := c.SetBypassUIDs(ctx, []int{1000, 1001})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"OUTPUT --table mangle -m owner --uid-owner 1000 -j MARK --set-mark 6453616",
+		"OUTPUT -o eth0 -m owner --uid-owner 1000 -j ACCEPT",
+		"OUTPUT --table mangle -m owner --uid-owner 1001 -j MARK --set-mark 6453616",
+		"OUTPUT -o eth0 -m owner --uid-owner 1001 -j ACCEPT",
+		"POSTROUTING --table nat -o eth0 -m mark --mark 6453616 -j MASQUERADE",
+	}, c.ipv4State.rules)
+
+	err = c.SetBypassUIDs(ctx, []int{1001})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"OUTPUT --table mangle -m owner --uid-owner 1001 -j MARK --set-mark 6453616",
+		"OUTPUT -o eth0 -m owner --uid-owner 1001 -j ACCEPT",
+		"POSTROUTING --table nat -o eth0 -m mark --mark 6453616 -j MASQUERADE",
+	}, c.ipv4State.rules)
+	assert.Equal(t, []int{1001}, c.bypassUIDs)
+
+	err = c.SetBypassUIDs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, c.ipv4State.rules)
+	assert.Empty(t, c.bypassUIDs)
+}
+
+func Test_configurator_SetBypassUIDs_disabled(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	logger := mock_logging.NewMockLogger(ctrl)
+	logger.EXPECT().Info("firewall disabled, only updating bypass user IDs internal list")
+
+	c := &configurator{logger: logger}
+	c.rules = c
+
+	err := c.SetBypassUIDs(context.Background(), []int{1000})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{1000}, c.bypassUIDs)
+	assert.Empty(t, c.ipv4State.rules)
+}
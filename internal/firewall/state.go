@@ -0,0 +1,80 @@
+package firewall
+
+import (
+	"context"
+	"strings"
+)
+
+// GetEnabled returns true if the firewall is enabled.
+func (c *configurator) GetEnabled() (enabled bool) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+	return c.enabled
+}
+
+// GetAllowedPorts returns a copy of the allowed input ports
+// mapped to their network interface.
+func (c *configurator) GetAllowedPorts() (ports map[uint16]string) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+	ports = make(map[uint16]string, len(c.allowedInputPorts))
+	for port, intf := range c.allowedInputPorts {
+		ports[port] = intf
+	}
+	return ports
+}
+
+// GetVPNInterface returns the current VPN network interface,
+// through which the VPN input ports should be allowed.
+func (c *configurator) GetVPNInterface() (intf string) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+	return c.vpnIntf
+}
+
+// GetRules returns the current firewall rules.
+func (c *configurator) GetRules(ctx context.Context) (rules string, err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+	return c.rules.listRules(ctx)
+}
+
+// GetCounters returns the current firewall rules with their
+// packets and bytes counters.
+func (c *configurator) GetCounters(ctx context.Context) (counters string, err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+	return c.rules.listCounters(ctx)
+}
+
+func (c *configurator) listRules(ctx context.Context) (rules string, err error) {
+	output, err := c.commander.Run(ctx, "iptables", "-S")
+	if err != nil {
+		return "", err
+	}
+	lines := []string{strings.TrimSpace(output)}
+	if c.ip6Tables {
+		output, err := c.commander.Run(ctx, "ip6tables", "-S")
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, strings.TrimSpace(output))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func (c *configurator) listCounters(ctx context.Context) (counters string, err error) {
+	output, err := c.commander.Run(ctx, "iptables", "-L", "-n", "-v", "-x")
+	if err != nil {
+		return "", err
+	}
+	lines := []string{strings.TrimSpace(output)}
+	if c.ip6Tables {
+		output, err := c.commander.Run(ctx, "ip6tables", "-L", "-n", "-v", "-x")
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, strings.TrimSpace(output))
+	}
+	return strings.Join(lines, "\n"), nil
+}
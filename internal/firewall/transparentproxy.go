@@ -0,0 +1,73 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// SetTransparentProxy redirects the TCP traffic from the source subnets
+// through the default interface to the ports given to the listening port
+// of the transparent proxy, and accepts the traffic to the listening port.
+func (c *configurator) SetTransparentProxy(ctx context.Context, sources []net.IPNet,
+	ports []uint16, listeningPort uint16) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if c.lockedDown {
+		return ErrLockedDown
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating transparent proxy internal state")
+		c.transparentProxy = newTransparentProxyState(sources, ports, listeningPort)
+		return nil
+	}
+
+	c.logger.Info("setting transparent proxy through firewall...")
+
+	const remove = true
+	if err := c.setTransparentProxy(ctx, c.transparentProxy, remove); err != nil {
+		c.logger.Error("cannot remove outdated transparent proxy through firewall: %s", err)
+	}
+	c.transparentProxy = transparentProxyState{}
+	state := newTransparentProxyState(sources, ports, listeningPort)
+	if err := c.setTransparentProxy(ctx, state, !remove); err != nil {
+		return fmt.Errorf("cannot set transparent proxy through firewall: %w", err)
+	}
+	c.transparentProxy = state
+	c.moveLogDroppedLast(ctx)
+	return nil
+}
+
+type transparentProxyState struct {
+	sources       []net.IPNet
+	ports         []uint16
+	listeningPort uint16
+}
+
+func newTransparentProxyState(sources []net.IPNet, ports []uint16,
+	listeningPort uint16) (state transparentProxyState) {
+	state.sources = copySubnets(sources)
+	state.ports = make([]uint16, len(ports))
+	copy(state.ports, ports)
+	state.listeningPort = listeningPort
+	return state
+}
+
+func (c *configurator) setTransparentProxy(ctx context.Context,
+	state transparentProxyState, remove bool) (err error) {
+	for _, source := range state.sources {
+		if err := c.rules.acceptInputFromSubnetToPort(ctx, c.defaultInterface,
+			source, state.listeningPort, remove); err != nil {
+			return err
+		}
+		for _, port := range state.ports {
+			if err := c.rules.redirectFromSubnetToPort(ctx, c.defaultInterface,
+				source, port, state.listeningPort, remove); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
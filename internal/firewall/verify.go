@@ -0,0 +1,217 @@
+package firewall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrRepair = errors.New("cannot repair firewall rules")
+
+// Verify verifies the firewall rules were not modified externally,
+// and repairs them if they were.
+func (c *configurator) Verify(ctx context.Context) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if !c.enabled || c.audit || c.lockedDown {
+		return nil
+	}
+
+	tampered, err := c.rules.tampered(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot verify firewall rules: %w", err)
+	} else if !tampered {
+		return nil
+	}
+
+	c.logger.Warn("firewall rules were modified externally, repairing them...")
+	if err := c.repair(ctx); err != nil {
+		return fmt.Errorf("%w: %s", ErrRepair, err)
+	}
+	c.logger.Info("firewall rules repaired")
+	return nil
+}
+
+// repair rebuilds all the rules. The DROP policies are set first and
+// are kept if the rebuild fails, so the firewall fails closed and only
+// traffic accepted by the rules rebuilt so far is let through.
+func (c *configurator) repair(ctx context.Context) (err error) {
+	ipv6Enabled, err := c.routing.IPv6Supported()
+	if err != nil {
+		return err
+	}
+	if err := c.rules.setIPv4AllPolicies(ctx, "DROP"); err != nil {
+		return err
+	}
+	if err := c.rules.setIPv6AllPolicies(ctx, "DROP"); err != nil {
+		return err
+	}
+	if err := c.rules.clearAllRules(ctx); err != nil {
+		return err
+	}
+	return c.applyRules(ctx, ipv6Enabled)
+}
+
+// RunVerifyTicker verifies the firewall rules periodically.
+func (c *configurator) RunVerifyTicker(ctx context.Context, wg *sync.WaitGroup, period time.Duration) {
+	defer wg.Done()
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Verify(ctx); err != nil {
+				c.logger.Error(err)
+			}
+		}
+	}
+}
+
+// iptablesState is the state of the rules applied with iptables or
+// ip6tables, used to detect external modifications of the rules.
+type iptablesState struct {
+	// rules are the rule specifications applied, such as
+	// "INPUT -i lo -j ACCEPT", in the order they were appended.
+	rules    []string
+	policies map[string]string // chain to policy
+}
+
+// track updates the state with the iptables instruction given,
+// which must have run successfully.
+func (s *iptablesState) track(instruction string) {
+	fields := strings.Fields(instruction)
+	const tableFields = 2
+	if len(fields) > tableFields && (fields[0] == "-t" || fields[0] == "--table") {
+		// move the table flag after the command, for example for
+		// "--table mangle --flush"
+		fields = append(fields[tableFields:], fields[:tableFields]...)
+	}
+	if len(fields) == 0 {
+		return
+	}
+	rule := strings.Join(fields[1:], " ")
+	switch fields[0] {
+	case "--append", "-A", "--insert", "-I":
+		s.rules = append(s.rules, rule)
+	case "--delete", "-D":
+		for i := range s.rules {
+			if s.rules[i] == rule {
+				s.rules = append(s.rules[:i], s.rules[i+1:]...)
+				break
+			}
+		}
+	case "--flush", "-F":
+		table := ruleTable(fields[1:])
+		rules := make([]string, 0, len(s.rules))
+		for _, rule := range s.rules {
+			if ruleTable(strings.Fields(rule)) != table {
+				rules = append(rules, rule)
+			}
+		}
+		s.rules = rules
+	case "--policy", "-P":
+		const policyFields = 3
+		if len(fields) != policyFields {
+			return
+		}
+		if s.policies == nil {
+			s.policies = make(map[string]string)
+		}
+		s.policies[fields[1]] = fields[2]
+	}
+}
+
+// ruleTable returns the table set in the iptables flags given,
+// and defaults to the filter table.
+func ruleTable(flags []string) (table string) {
+	for i := 0; i < len(flags)-1; i++ {
+		if flags[i] == "-t" || flags[i] == "--table" {
+			return flags[i+1]
+		}
+	}
+	return "filter"
+}
+
+// countedTables are the tables in which the number of rules is verified,
+// since other programs may add rules to other tables, for example Docker
+// to the nat table for its DNS server.
+var countedTables = [...]string{"filter"} //nolint:gochecknoglobals
+
+func (c *configurator) tampered(ctx context.Context) (tampered bool, err error) {
+	c.iptablesMutex.Lock()
+	state := copyIptablesState(c.ipv4State)
+	c.iptablesMutex.Unlock()
+	tampered, err = c.iptablesTampered(ctx, "iptables", state)
+	if err != nil || tampered || !c.ip6Tables {
+		return tampered, err
+	}
+
+	c.ip6tablesMutex.Lock()
+	state = copyIptablesState(c.ipv6State)
+	c.ip6tablesMutex.Unlock()
+	return c.iptablesTampered(ctx, "ip6tables", state)
+}
+
+func copyIptablesState(state iptablesState) (copied iptablesState) {
+	copied.rules = make([]string, len(state.rules))
+	copy(copied.rules, state.rules)
+	copied.policies = make(map[string]string, len(state.policies))
+	for chain, policy := range state.policies {
+		copied.policies[chain] = policy
+	}
+	return copied
+}
+
+// iptablesTampered returns true if a rule applied is missing, if there
+// are other rules in the filter table, or if the policies
+// of the filter table were changed.
+func (c *configurator) iptablesTampered(ctx context.Context, program string,
+	state iptablesState) (tampered bool, err error) {
+	for _, rule := range state.rules {
+		flags := append([]string{"-C"}, strings.Fields(rule)...)
+		if _, err := c.commander.Run(ctx, program, flags...); err != nil {
+			c.logger.Debug("%s rule is missing: %s", program, rule)
+			return true, nil
+		}
+	}
+
+	for _, table := range countedTables {
+		output, err := c.commander.Run(ctx, program, "-t", table, "-S")
+		if err != nil {
+			return false, err
+		}
+		expectedRules := 0
+		for _, rule := range state.rules {
+			if ruleTable(strings.Fields(rule)) == table {
+				expectedRules++
+			}
+		}
+		rules := 0
+		for _, line := range strings.Split(output, "\n") {
+			fields := strings.Fields(line)
+			switch {
+			case len(fields) == 0:
+			case fields[0] == "-A":
+				rules++
+			case table == "filter" && fields[0] == "-P" && len(fields) == 3:
+				if policy, ok := state.policies[fields[1]]; ok && policy != fields[2] {
+					c.logger.Debug("%s policy of chain %s changed to %s", program, fields[1], fields[2])
+					return true, nil
+				}
+			}
+		}
+		if rules != expectedRules {
+			c.logger.Debug("%s %s table has %d rules instead of %d",
+				program, table, rules, expectedRules)
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,132 @@
+package firewall
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/routing/mock_routing"
+	"github.com/qdm12/golibs/command/mock_command"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/qdm12/golibs/os"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_iptablesState_track(t *testing.T) {
+	t.Parallel()
+
+	var state iptablesState
+	instructions := []string{
+		"--policy INPUT DROP",
+		"--append INPUT -i lo -j ACCEPT",
+		"--append OUTPUT -o tun0 -j ACCEPT",
+		"--append PREROUTING --table mangle -i eth0 -j ACCEPT",
+		"-A FORWARD -i eth0 -j ACCEPT -t nat",
+		"--delete OUTPUT -o tun0 -j ACCEPT",
+		"--delete OUTPUT -o tun1 -j ACCEPT",
+		"--table mangle --flush",
+	}
+	for _, instruction := range instructions {
+		state.track(instruction)
+	}
+
+	expected := iptablesState{
+		rules: []string{
+			"INPUT -i lo -j ACCEPT",
+			"FORWARD -i eth0 -j ACCEPT -t nat",
+		},
+		policies: map[string]string{"INPUT": "DROP"},
+	}
+	assert.Equal(t, expected, state)
+
+	state.track("--flush")
+	assert.Equal(t, []string{"FORWARD -i eth0 -j ACCEPT -t nat"}, state.rules)
+}
+
+func Test_configurator_Verify(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		failingRule string
+		rules       []string
+		err         error
+	}{
+		"repaired": {
+			rules: []string{
+				"INPUT -i lo -j ACCEPT",
+				"OUTPUT -o lo -j ACCEPT",
+				"OUTPUT -m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT",
+				"INPUT -m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT",
+				"OUTPUT -o tun0 -j ACCEPT",
+			},
+		},
+		"rebuild failing keeps DROP policies": {
+			failingRule: "--append OUTPUT -o tun0 -j ACCEPT",
+			rules: []string{
+				"INPUT -i lo -j ACCEPT",
+				"OUTPUT -o lo -j ACCEPT",
+				"OUTPUT -m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT",
+				"INPUT -m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT",
+			},
+			err: ErrRepair,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+
+			logger := mock_logging.NewMockLogger(ctrl)
+			logger.EXPECT().Debug("%s rule is missing: %s", "iptables", "OUTPUT -o tun0 -j ACCEPT")
+			logger.EXPECT().Warn("firewall rules were modified externally, repairing them...")
+			if testCase.err == nil {
+				logger.EXPECT().Info("firewall rules repaired")
+			}
+
+			routing := mock_routing.NewMockRouting(ctrl)
+			routing.EXPECT().IPv6Supported().Return(false, nil)
+
+			commander := mock_command.NewMockCommander(ctrl)
+			commander.EXPECT().Run(ctx, "iptables", gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, flags ...string) (string, error) {
+					instruction := strings.Join(flags, " ")
+					if flags[0] == "-C" || instruction == testCase.failingRule {
+						return "", errTest
+					}
+					return "", nil
+				}).AnyTimes()
+
+			c := &configurator{
+				commander: commander,
+				logger:    logger,
+				routing:   routing,
+				openFile: func(string, int, os.FileMode) (os.File, error) {
+					return nil, fs.ErrNotExist
+				},
+				vpnIntf: "tun0",
+				enabled: true,
+				ipv4State: iptablesState{
+					rules:    []string{"OUTPUT -o tun0 -j ACCEPT"},
+					policies: map[string]string{"INPUT": "ACCEPT", "OUTPUT": "ACCEPT", "FORWARD": "ACCEPT"},
+				},
+			}
+			c.rules = c
+
+			err := c.Verify(ctx)
+
+			assert.ErrorIs(t, err, testCase.err)
+			assert.True(t, c.enabled)
+			assert.Equal(t, testCase.rules, c.ipv4State.rules)
+			assert.Equal(t, map[string]string{"INPUT": "DROP", "OUTPUT": "DROP", "FORWARD": "DROP"},
+				c.ipv4State.policies)
+		})
+	}
+}
@@ -11,9 +11,13 @@ func (c *configurator) SetVPNConnection(ctx context.Context, connection models.O
 	c.stateMutex.Lock()
 	defer c.stateMutex.Unlock()
 
+	if c.lockedDown {
+		return ErrLockedDown
+	}
+
 	if !c.enabled {
 		c.logger.Info("firewall disabled, only updating internal VPN connection")
-		c.vpnConnection = connection
+		c.vpnConnection, c.nextVPNConnection = connection, models.OpenVPNConnection{}
 		return nil
 	}
 
@@ -25,15 +29,141 @@ func (c *configurator) SetVPNConnection(ctx context.Context, connection models.O
 
 	remove := true
 	if c.vpnConnection.IP != nil {
-		if err := c.acceptOutputTrafficToVPN(ctx, c.defaultInterface, c.vpnConnection, remove); err != nil {
+		if err := c.rules.acceptOutputTrafficToVPN(ctx, c.defaultInterface, c.vpnConnection, remove); err != nil {
 			c.logger.Error("cannot remove outdated VPN connection through firewall: %s", err)
 		}
 	}
 	c.vpnConnection = models.OpenVPNConnection{}
+	if c.nextVPNConnection.Equal(connection) {
+		// already accepted by SetNextVPNConnection
+		c.vpnConnection, c.nextVPNConnection = connection, models.OpenVPNConnection{}
+		return nil
+	} else if c.nextVPNConnection.IP != nil {
+		if err := c.rules.acceptOutputTrafficToVPN(ctx, c.defaultInterface, c.nextVPNConnection, remove); err != nil {
+			c.logger.Error("cannot remove outdated next VPN connection through firewall: %s", err)
+		}
+		c.nextVPNConnection = models.OpenVPNConnection{}
+	}
 	remove = false
-	if err := c.acceptOutputTrafficToVPN(ctx, c.defaultInterface, connection, remove); err != nil {
+	if err := c.rules.acceptOutputTrafficToVPN(ctx, c.defaultInterface, connection, remove); err != nil {
 		return fmt.Errorf("cannot set VPN connection through firewall: %w", err)
 	}
 	c.vpnConnection = connection
+	c.moveLogDroppedLast(ctx)
+	return nil
+}
+
+// SetNextVPNConnection accepts traffic to the VPN connection given in
+// addition to the current VPN connection, until SetVPNConnection is
+// called. This is used to connect to a new VPN server while still
+// connected to the current one.
+func (c *configurator) SetNextVPNConnection(ctx context.Context, connection models.OpenVPNConnection) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if c.lockedDown {
+		return ErrLockedDown
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating internal next VPN connection")
+		c.nextVPNConnection = connection
+		return nil
+	}
+
+	if c.nextVPNConnection.Equal(connection) || c.vpnConnection.Equal(connection) {
+		return nil
+	}
+
+	c.logger.Info("setting next VPN connection through firewall...")
+
+	remove := true
+	if c.nextVPNConnection.IP != nil {
+		if err := c.rules.acceptOutputTrafficToVPN(ctx, c.defaultInterface, c.nextVPNConnection, remove); err != nil {
+			c.logger.Error("cannot remove outdated next VPN connection through firewall: %s", err)
+		}
+	}
+	c.nextVPNConnection = models.OpenVPNConnection{}
+	remove = false
+	if err := c.rules.acceptOutputTrafficToVPN(ctx, c.defaultInterface, connection, remove); err != nil {
+		return fmt.Errorf("cannot set next VPN connection through firewall: %w", err)
+	}
+	c.nextVPNConnection = connection
+	c.moveLogDroppedLast(ctx)
+	return nil
+}
+
+func (c *configurator) SetVPNInterface(ctx context.Context, intf string) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if c.lockedDown {
+		return ErrLockedDown
+	}
+
+	if intf == c.vpnIntf {
+		return nil
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating internal VPN interface")
+		for port, portIntf := range c.allowedInputPorts {
+			if portIntf == c.vpnIntf {
+				c.allowedInputPorts[port] = intf
+			}
+		}
+		c.vpnIntf = intf
+		return nil
+	}
+
+	c.logger.Info("setting VPN interface %s through firewall...", intf)
+
+	const remove = true
+	if err := c.rules.acceptOutputThroughInterface(ctx, intf, !remove); err != nil {
+		return fmt.Errorf("cannot set VPN interface through firewall: %w", err)
+	}
+	if err := c.rules.acceptOutputThroughInterface(ctx, c.vpnIntf, remove); err != nil {
+		c.logger.Error("cannot remove outdated VPN interface through firewall: %s", err)
+	}
+	if c.flushConntrack {
+		if err := c.rules.markOutputThroughInterface(ctx, intf, !remove); err != nil {
+			return fmt.Errorf("cannot set VPN interface through firewall: %w", err)
+		}
+		if err := c.rules.markOutputThroughInterface(ctx, c.vpnIntf, remove); err != nil {
+			c.logger.Error("cannot remove outdated VPN interface marking through firewall: %s", err)
+		}
+	}
+	if err := c.setVPNSources(ctx, intf, c.vpnSources, !remove); err != nil {
+		return fmt.Errorf("cannot set VPN interface through firewall: %w", err)
+	}
+	if err := c.setVPNSources(ctx, c.vpnIntf, c.vpnSources, remove); err != nil {
+		c.logger.Error("cannot remove outdated VPN sources through firewall: %s", err)
+	}
+	if err := c.moveVPNAllowedPorts(ctx, intf); err != nil {
+		return fmt.Errorf("cannot set VPN interface through firewall: %w", err)
+	}
+	c.vpnIntf = intf
+	c.moveLogDroppedLast(ctx)
+	return nil
+}
+
+// moveVPNAllowedPorts moves the input ports allowed through the current
+// VPN interface to the VPN interface given, since the tunnel device
+// changes when switching servers seamlessly.
+func (c *configurator) moveVPNAllowedPorts(ctx context.Context, intf string) (err error) {
+	const remove = true
+	for port, portIntf := range c.allowedInputPorts {
+		if portIntf != c.vpnIntf {
+			continue
+		}
+		if err := c.rules.acceptInputToPort(ctx, intf, port, !remove); err != nil {
+			return fmt.Errorf("cannot set allowed port %d through interface %s: %w", port, intf, err)
+		}
+		if err := c.rules.acceptInputToPort(ctx, c.vpnIntf, port, remove); err != nil {
+			c.logger.Error("cannot remove outdated allowed port %d through interface %s: %s",
+				port, c.vpnIntf, err)
+		}
+		c.allowedInputPorts[port] = intf
+	}
 	return nil
 }
@@ -0,0 +1,75 @@
+package firewall
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_configurator_SetVPNInterface_allowedPorts(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		enabled bool
+		rules   []string
+	}{
+		"firewall enabled": {
+			enabled: true,
+			rules: []string{
+				"INPUT -i eth0 -p tcp --dport 9000 -j ACCEPT",
+				"INPUT -i eth0 -p udp --dport 9000 -j ACCEPT",
+				"OUTPUT -o tun1 -j ACCEPT",
+				"INPUT -i tun1 -p tcp --dport 8000 -j ACCEPT",
+				"INPUT -i tun1 -p udp --dport 8000 -j ACCEPT",
+			},
+		},
+		"firewall disabled": {},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+
+			logger := mock_logging.NewMockLogger(ctrl)
+			var instructions []string
+			c := &configurator{
+				commander: newRecordingCommander(ctrl, &instructions),
+				logger:    logger,
+				enabled:   testCase.enabled,
+				vpnIntf:   "tun0",
+				allowedInputPorts: map[uint16]string{
+					8000: "tun0",
+					9000: "eth0",
+				},
+			}
+			c.rules = c
+			if testCase.enabled {
+				c.ipv4State.rules = []string{
+					"INPUT -i eth0 -p tcp --dport 9000 -j ACCEPT",
+					"INPUT -i eth0 -p udp --dport 9000 -j ACCEPT",
+					"OUTPUT -o tun0 -j ACCEPT",
+					"INPUT -i tun0 -p tcp --dport 8000 -j ACCEPT",
+					"INPUT -i tun0 -p udp --dport 8000 -j ACCEPT",
+				}
+				logger.EXPECT().Info("setting VPN interface %s through firewall...", "tun1")
+			} else {
+				logger.EXPECT().Info("firewall disabled, only updating internal VPN interface")
+			}
+
+			// switching seamlessly from tun0 to tun1
+			err := c.SetVPNInterface(ctx, "tun1")
+
+			require.NoError(t, err)
+			assert.Equal(t, "tun1", c.GetVPNInterface())
+			assert.Equal(t, map[uint16]string{8000: "tun1", 9000: "eth0"}, c.GetAllowedPorts())
+			assert.Equal(t, testCase.rules, c.ipv4State.rules)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package healthcheck
+
+import "time"
+
+// backoff computes exponentially increasing delays between
+// health checks, with a jitter of up to 20% of the delay.
+type backoff struct {
+	initial    time.Duration
+	max        time.Duration
+	current    time.Duration
+	randInt63n func(n int64) int64
+}
+
+func newBackoff(initial, max time.Duration, randInt63n func(n int64) int64) *backoff {
+	return &backoff{
+		initial:    initial,
+		max:        max,
+		randInt63n: randInt63n,
+	}
+}
+
+// next returns the next delay, doubling it up to the maximum delay.
+func (b *backoff) next() (delay time.Duration) {
+	switch {
+	case b.current == 0:
+		b.current = b.initial
+	case b.current < b.max:
+		b.current *= 2
+		if b.current > b.max {
+			b.current = b.max
+		}
+	}
+
+	const jitterDivider = 5
+	jitter := b.current / jitterDivider
+	if jitter == 0 {
+		return b.current
+	}
+	return b.current - jitter + time.Duration(b.randInt63n(2*int64(jitter)+1))
+}
+
+func (b *backoff) reset() {
+	b.current = 0
+}
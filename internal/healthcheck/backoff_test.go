@@ -0,0 +1,36 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_backoff(t *testing.T) {
+	t.Parallel()
+
+	lowestJitter := false
+	randInt63n := func(n int64) int64 {
+		if lowestJitter {
+			return 0
+		}
+		return n - 1
+	}
+	b := newBackoff(time.Second, 5*time.Second, randInt63n)
+
+	expected := []time.Duration{ // with the highest jitter of +20%
+		1200 * time.Millisecond,
+		2400 * time.Millisecond,
+		4800 * time.Millisecond,
+		6 * time.Second,
+		6 * time.Second,
+	}
+	for i, delay := range expected {
+		assert.Equal(t, delay, b.next(), "delay %d", i)
+	}
+
+	b.reset()
+	lowestJitter = true // -20%
+	assert.Equal(t, 800*time.Millisecond, b.next())
+}
@@ -0,0 +1,126 @@
+package healthcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+var (
+	errCheckTypeUnknown = errors.New("health check type is unknown")
+	errStatusUnexpected = errors.New("unexpected HTTP status code")
+	errAllChecksFailed  = errors.New("all health checks failed")
+)
+
+// runChecks runs the checks given and returns an error if all of them
+// fail, or if any of them fails when requireAll is true.
+func (s *server) runChecks(ctx context.Context, checks []models.HealthCheck,
+	requireAll bool) (err error) {
+	errorMessages := make([]string, 0, len(checks))
+	for _, check := range checks {
+		err := s.runCheck(ctx, check)
+		switch {
+		case err != nil && requireAll:
+			return fmt.Errorf("%s: %w", check, err)
+		case err != nil:
+			errorMessages = append(errorMessages, fmt.Sprintf("%s: %s", check, err))
+		case !requireAll:
+			return nil
+		}
+	}
+	if len(errorMessages) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", errAllChecksFailed, strings.Join(errorMessages, "; "))
+}
+
+func (s *server) runCheck(ctx context.Context, check models.HealthCheck) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, check.Timeout)
+	defer cancel()
+
+	switch check.Type {
+	case constants.HealthCheckDNS:
+		return s.checkDNS(ctx, check.Target)
+	case constants.HealthCheckTCP:
+		return s.checkTCP(ctx, check.Target)
+	case constants.HealthCheckTLS:
+		return s.checkTLS(ctx, check.Target)
+	case constants.HealthCheckHTTP:
+		return s.checkHTTP(ctx, check.Target, check.Status)
+	case constants.HealthCheckICMP:
+		return s.checkICMP(ctx, check.Target, check.Timeout.Seconds())
+	default:
+		return fmt.Errorf("%w: %s", errCheckTypeUnknown, check.Type)
+	}
+}
+
+func (s *server) checkDNS(ctx context.Context, hostname string) (err error) {
+	ips, err := s.resolver.LookupIP(ctx, "ip", hostname)
+	switch {
+	case err != nil:
+		return err
+	case len(ips) == 0:
+		return fmt.Errorf("%w for %s", errNoIPResolved, hostname)
+	default:
+		return nil
+	}
+}
+
+func (s *server) checkTCP(ctx context.Context, address string) (err error) {
+	dialer := &net.Dialer{Resolver: s.resolver}
+	connection, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return connection.Close()
+}
+
+func (s *server) checkTLS(ctx context.Context, address string) (err error) {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Resolver: s.resolver},
+	}
+	connection, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return connection.Close()
+}
+
+func (s *server) checkHTTP(ctx context.Context, url string, expectedStatus int) (err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	if err := response.Body.Close(); err != nil {
+		return err
+	}
+	if response.StatusCode != expectedStatus {
+		return fmt.Errorf("%w: %d instead of %d", errStatusUnexpected,
+			response.StatusCode, expectedStatus)
+	}
+	return nil
+}
+
+func (s *server) checkICMP(ctx context.Context, host string, timeoutSeconds float64) (err error) {
+	timeout := int(timeoutSeconds)
+	if timeout < 1 {
+		timeout = 1
+	}
+	output, err := s.commander.Run(ctx, "ping", "-c", "1", "-W", strconv.Itoa(timeout), host)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, output)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package healthcheck
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_server_runChecks(t *testing.T) {
+	t.Parallel()
+
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(httpServer.Close)
+	tcpAddress := strings.TrimPrefix(httpServer.URL, "http://")
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddress := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	const timeout = time.Second
+	tcpOK := models.HealthCheck{Type: constants.HealthCheckTCP, Target: tcpAddress, Timeout: timeout}
+	tcpFail := models.HealthCheck{Type: constants.HealthCheckTCP, Target: closedAddress, Timeout: timeout}
+	httpOK := models.HealthCheck{Type: constants.HealthCheckHTTP, Target: httpServer.URL,
+		Timeout: timeout, Status: http.StatusNoContent}
+	httpFail := models.HealthCheck{Type: constants.HealthCheckHTTP, Target: httpServer.URL,
+		Timeout: timeout, Status: http.StatusOK}
+
+	testCases := map[string]struct {
+		checks     []models.HealthCheck
+		requireAll bool
+		err        error
+		errMessage string
+	}{
+		"all succeed": {
+			checks:     []models.HealthCheck{tcpOK, httpOK},
+			requireAll: true,
+		},
+		"one succeeds": {
+			checks: []models.HealthCheck{tcpFail, httpOK},
+		},
+		"one fails with require all": {
+			checks:     []models.HealthCheck{tcpOK, httpFail},
+			requireAll: true,
+			err:        errStatusUnexpected,
+			errMessage: "http " + httpServer.URL + ": unexpected HTTP status code: 204 instead of 200",
+		},
+		"all fail": {
+			checks: []models.HealthCheck{httpFail},
+			err:    errAllChecksFailed,
+			errMessage: "all health checks failed: http " + httpServer.URL +
+				": unexpected HTTP status code: 204 instead of 200",
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s := &server{
+				resolver:   net.DefaultResolver,
+				httpClient: httpServer.Client(),
+			}
+			err := s.runChecks(context.Background(), testCase.checks, testCase.requireAll)
+			assert.ErrorIs(t, err, testCase.err)
+			if testCase.err != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/qdm12/golibs/logging"
 )
@@ -12,6 +13,9 @@ type handler struct {
 	logger      logging.Logger
 	healthErr   error
 	healthErrMu sync.RWMutex
+	// latency is the duration of the last successful health check.
+	latency   time.Duration
+	latencyMu sync.RWMutex
 }
 
 var errHealthcheckNotRunYet = errors.New("healthcheck did not run yet")
@@ -46,3 +50,15 @@ func (h *handler) getErr() (err error) {
 	defer h.healthErrMu.RUnlock()
 	return h.healthErr
 }
+
+func (h *handler) setLatency(latency time.Duration) {
+	h.latencyMu.Lock()
+	defer h.latencyMu.Unlock()
+	h.latency = latency
+}
+
+func (h *handler) getLatency() (latency time.Duration) {
+	h.latencyMu.RLock()
+	defer h.latencyMu.RUnlock()
+	return h.latency
+}
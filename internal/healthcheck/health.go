@@ -5,40 +5,70 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net"
 	"sync"
 	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
 )
 
 func (s *server) runHealthcheckLoop(ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
+	// failures and successes are the numbers of consecutive
+	// failed and successful health checks.
+	var failures, successes int
 	for {
 		previousErr := s.handler.getErr()
 
-		err := healthCheck(ctx, s.resolver)
-		s.handler.setErr(err)
+		start := time.Now()
+		err := s.healthCheck(ctx)
+		latency := time.Since(start)
+		s.history.add(start, latency, err)
+		if err == nil {
+			s.handler.setLatency(latency)
+			successes++
+			failures = 0
+		} else {
+			failures++
+			successes = 0
+		}
+
+		// The health only changes after enough consecutive results
+		// so a single dropped packet does not mark it as unhealthy.
+		switch {
+		case err != nil && (previousErr != nil || failures >= s.settings.FailureThreshold):
+			s.handler.setErr(err)
+		case err == nil && (previousErr == nil || successes >= s.settings.SuccessThreshold):
+			s.handler.setErr(nil)
+		}
+		currentErr := s.handler.getErr()
 
-		if previousErr != nil && err == nil {
+		if previousErr != nil && currentErr == nil {
 			s.logger.Info("healthy!")
-		} else if previousErr == nil && err != nil {
-			s.logger.Info("unhealthy: " + err.Error())
+		} else if previousErr == nil && currentErr != nil {
+			s.logger.Info("unhealthy: " + currentErr.Error())
+			s.pingExternal(ctx, currentErr)
+		}
+		if err == nil && currentErr == nil {
+			s.pingExternal(ctx, nil)
 		}
 
-		if err != nil { // try again after 1 second
-			timer := time.NewTimer(time.Second)
-			select {
-			case <-ctx.Done():
-				if !timer.Stop() {
-					<-timer.C
-				}
-				return
-			case <-timer.C:
-			}
-			continue
+		paused := s.vpnLooper != nil && s.vpnLooper.IsPaused()
+		if action := s.recovery.update(currentErr == nil || paused); action != "" {
+			s.recover(action)
 		}
-		// Success, check again in 10 minutes
+
+		// Check again in 10 minutes once healthy, and with an
+		// increasing backoff otherwise until the health settles.
 		const period = 10 * time.Minute
-		timer := time.NewTimer(period)
+		delay := period
+		if err == nil {
+			s.backoff.reset()
+		}
+		if err != nil || currentErr != nil {
+			delay = s.backoff.next()
+		}
+
+		timer := time.NewTimer(delay)
 		select {
 		case <-ctx.Done():
 			if !timer.Stop() {
@@ -52,18 +82,24 @@ func (s *server) runHealthcheckLoop(ctx context.Context, wg *sync.WaitGroup) {
 
 var (
 	errNoIPResolved = errors.New("no IP address resolved")
+	errOpenVPNNotUp = errors.New("openvpn is not up")
 )
 
-func healthCheck(ctx context.Context, resolver *net.Resolver) (err error) {
-	// TODO use mullvad API if current provider is Mullvad
-	const domainToResolve = "github.com"
-	ips, err := resolver.LookupIP(ctx, "ip", domainToResolve)
-	switch {
-	case err != nil:
+func (s *server) healthCheck(ctx context.Context) (err error) {
+	if s.openvpnState != nil {
+		state := s.openvpnState()
+		if state.State != constants.OpenVPNUp {
+			err = fmt.Errorf("%w: state is %s", errOpenVPNNotUp, state.State)
+			if state.Failure != "" {
+				err = fmt.Errorf("%w (last failure: %s)", err, state.Failure)
+			}
+			return err
+		}
+	}
+
+	if err := s.getLeakErr(); err != nil {
 		return err
-	case len(ips) == 0:
-		return fmt.Errorf("%w for %s", errNoIPResolved, domainToResolve)
-	default:
-		return nil
 	}
+
+	return s.runChecks(ctx, s.settings.Checks, s.settings.RequireAll)
 }
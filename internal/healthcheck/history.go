@@ -0,0 +1,73 @@
+package healthcheck
+
+import (
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// historySize is the number of health probes kept in the history.
+const historySize = 100
+
+// history keeps the last health probes, and counts all of them.
+type history struct {
+	probes []models.HealthProbe // oldest first
+	// latencies are the latencies of the probes.
+	latencies []time.Duration
+	total     uint64
+	failures  uint64
+	mu        sync.RWMutex
+}
+
+func (h *history) add(start time.Time, latency time.Duration, err error) {
+	probe := models.HealthProbe{
+		Time:    start,
+		Success: err == nil,
+		Latency: latency.String(),
+	}
+	if err != nil {
+		probe.Error = err.Error()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.probes) == historySize {
+		h.probes = h.probes[1:]
+		h.latencies = h.latencies[1:]
+	}
+	h.probes = append(h.probes, probe)
+	h.latencies = append(h.latencies, latency)
+	h.total++
+	if err != nil {
+		h.failures++
+	}
+}
+
+func (h *history) get() (probes []models.HealthProbe) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	probes = make([]models.HealthProbe, len(h.probes))
+	copy(probes, h.probes)
+	return probes
+}
+
+func (h *history) stats() (stats models.HealthStats) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	stats.Probes = h.total
+	stats.Failures = h.failures
+
+	var sum time.Duration
+	var successes int
+	for i, probe := range h.probes {
+		if probe.Success {
+			sum += h.latencies[i]
+			successes++
+		}
+	}
+	if successes > 0 {
+		stats.AverageLatency = sum / time.Duration(successes)
+	}
+	return stats
+}
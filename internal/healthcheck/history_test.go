@@ -0,0 +1,34 @@
+package healthcheck
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_history(t *testing.T) {
+	t.Parallel()
+
+	h := &history{}
+	start := time.Unix(0, 0)
+	for i := 0; i < historySize; i++ {
+		h.add(start, time.Hour, nil)
+	}
+	h.add(start, time.Second, nil)
+	h.add(start, 3*time.Second, nil)
+	h.add(start, 5*time.Second, errors.New("dial timeout"))
+
+	probes := h.get()
+	assert.Len(t, probes, historySize)
+	expectedLast := models.HealthProbe{Time: start, Latency: "5s", Error: "dial timeout"}
+	assert.Equal(t, expectedLast, probes[historySize-1])
+
+	stats := h.stats()
+	assert.Equal(t, uint64(historySize+3), stats.Probes)
+	assert.Equal(t, uint64(1), stats.Failures)
+	expectedAverage := (time.Duration(historySize-3)*time.Hour + 4*time.Second) / (historySize - 1)
+	assert.Equal(t, expectedAverage, stats.AverageLatency)
+}
@@ -0,0 +1,128 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/publicip"
+)
+
+var (
+	errTrafficLeaking       = errors.New("traffic is leaking outside the VPN")
+	errPublicIPNotInSubnets = errors.New("public IP address is not in the expected subnets")
+	errPublicIPUnknown      = errors.New("cannot get public IP address")
+)
+
+func (s *server) runLeakCheckLoop(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	if !s.settings.LeakCheck {
+		return
+	}
+
+	if s.preVPNIP == nil {
+		s.logger.Warn("public IP address before connecting to the VPN is unknown, " +
+			"only checking the public IP address is in the expected subnets")
+	}
+
+	ticker := time.NewTicker(s.settings.LeakCheckPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkLeak(ctx)
+		}
+	}
+}
+
+// checkLeak fetches the public IP addresses through the tunnel and
+// checks them. If traffic is leaking outside the VPN, it locks down the
+// firewall and the health check stays unhealthy until restarted.
+func (s *server) checkLeak(ctx context.Context) {
+	if s.openvpnState != nil && s.openvpnState().State != constants.OpenVPNUp {
+		return
+	}
+
+	err := s.checkPublicIPLeak(ctx, s.ipGetter, s.preVPNIP)
+	if err == nil && s.ipv6Getter != nil {
+		err = s.checkPublicIPLeak(ctx, s.ipv6Getter, s.preVPNIPv6)
+	}
+	if errors.Is(err, errPublicIPUnknown) {
+		s.logger.Warn(err)
+		return
+	}
+	s.setLeakErr(err)
+	switch {
+	case errors.Is(err, errTrafficLeaking):
+		s.logger.Error(err)
+		if err := s.fw.Lockdown(ctx); err != nil {
+			s.logger.Error(err)
+		}
+	case err != nil:
+		s.logger.Warn(err)
+	}
+}
+
+// checkPublicIPLeak fetches the public IP address with the getter
+// given and checks it against the public IP address before
+// connecting to the VPN given.
+func (s *server) checkPublicIPLeak(ctx context.Context, getter publicip.IPGetter,
+	preVPNIP net.IP) (err error) {
+	const timeout = 10 * time.Second
+	getCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	publicIP, err := getter.Get(getCtx)
+	if err != nil {
+		return fmt.Errorf("%w to check for leaks: %s", errPublicIPUnknown, err)
+	}
+	return checkPublicIP(publicIP, preVPNIP, s.settings.LeakCheckSubnets)
+}
+
+// checkPublicIP checks the public IP address differs from the public
+// IP address before connecting to the VPN, and is in one of the subnets
+// given of its IP version, if any.
+func checkPublicIP(publicIP, preVPNIP net.IP, subnets []net.IPNet) (err error) {
+	if publicIP.Equal(preVPNIP) {
+		return fmt.Errorf("%w: public IP address is the one before connecting to the VPN",
+			errTrafficLeaking)
+	}
+
+	isIPv4 := publicIP.To4() != nil
+	sameVersionSubnets := 0
+	for _, subnet := range subnets {
+		if (subnet.IP.To4() != nil) != isIPv4 {
+			continue
+		}
+		sameVersionSubnets++
+		if subnet.Contains(publicIP) {
+			return nil
+		}
+	}
+	if sameVersionSubnets == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", errPublicIPNotInSubnets, publicIP)
+}
+
+// setLeakErr sets the error of the last leak check. A leak
+// error is kept since the firewall stays locked down.
+func (s *server) setLeakErr(err error) {
+	s.leakErrMu.Lock()
+	defer s.leakErrMu.Unlock()
+	if errors.Is(s.leakErr, errTrafficLeaking) {
+		return
+	}
+	s.leakErr = err
+}
+
+func (s *server) getLeakErr() (err error) {
+	s.leakErrMu.RLock()
+	defer s.leakErrMu.RUnlock()
+	return s.leakErr
+}
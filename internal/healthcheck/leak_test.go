@@ -0,0 +1,59 @@
+package healthcheck
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_checkPublicIP(t *testing.T) {
+	t.Parallel()
+
+	preVPNIP := net.IPv4(1, 2, 3, 4)
+	_, subnet, _ := net.ParseCIDR("5.6.7.0/24")
+	testCases := map[string]struct {
+		publicIP net.IP
+		preVPNIP net.IP
+		subnets  []net.IPNet
+		err      error
+	}{
+		"different IP": {
+			publicIP: net.IPv4(5, 6, 7, 8),
+			preVPNIP: preVPNIP,
+		},
+		"pre VPN IP unknown": {
+			publicIP: net.IPv4(5, 6, 7, 8),
+		},
+		"leaking": {
+			publicIP: net.IPv4(1, 2, 3, 4),
+			preVPNIP: preVPNIP,
+			subnets:  []net.IPNet{*subnet},
+			err:      errTrafficLeaking,
+		},
+		"in subnets": {
+			publicIP: net.IPv4(5, 6, 7, 8),
+			preVPNIP: preVPNIP,
+			subnets:  []net.IPNet{*subnet},
+		},
+		"not in subnets": {
+			publicIP: net.IPv4(9, 9, 9, 9),
+			preVPNIP: preVPNIP,
+			subnets:  []net.IPNet{*subnet},
+			err:      errPublicIPNotInSubnets,
+		},
+		"IPv6 without IPv6 subnets": {
+			publicIP: net.ParseIP("2001:db8::1"),
+			preVPNIP: net.ParseIP("2001:db8::2"),
+			subnets:  []net.IPNet{*subnet},
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := checkPublicIP(testCase.publicIP, testCase.preVPNIP, testCase.subnets)
+			assert.ErrorIs(t, err, testCase.err)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
+)
+
+var errPingStatus = errors.New("bad HTTP status code")
+
+// pingExternal requests the external ping URL, or the external ping
+// failure URL with the error given as body if err is not nil. It does
+// nothing if no external ping URL is set. Note the failure ping only
+// reaches the external service if it is reachable despite the failure.
+func (s *server) pingExternal(ctx context.Context, healthErr error) {
+	if s.settings.PingURL == "" {
+		return
+	}
+
+	const timeout = 10 * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	url, method, body := s.settings.PingURL, http.MethodGet, ""
+	if healthErr != nil {
+		url, method, body = s.settings.PingFailURL, http.MethodPost, healthErr.Error()
+	}
+
+	if err := s.ping(ctx, method, url, body); err != nil {
+		s.logger.Warn("cannot ping external URL: %s", err)
+	}
+}
+
+func (s *server) ping(ctx context.Context, method, url, body string) (err error) {
+	request, err := http.NewRequestWithContext(ctx, method, url, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) { // do not log the URL containing a secret token
+			err = urlErr.Err
+		}
+		return err
+	}
+	if err := response.Body.Close(); err != nil {
+		return err
+	}
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %s", errPingStatus, response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_server_pingExternal(t *testing.T) {
+	t.Parallel()
+
+	var requests []string
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+	}))
+	t.Cleanup(httpServer.Close)
+
+	s := &server{
+		httpClient: httpServer.Client(),
+		settings: configuration.Health{
+			PingURL:     httpServer.URL + "/uuid",
+			PingFailURL: httpServer.URL + "/uuid/fail",
+		},
+	}
+
+	ctx := context.Background()
+	s.pingExternal(ctx, nil)
+	s.pingExternal(ctx, errors.New("all health checks failed"))
+
+	expected := []string{
+		"GET /uuid ",
+		"POST /uuid/fail all health checks failed",
+	}
+	assert.Equal(t, expected, requests)
+}
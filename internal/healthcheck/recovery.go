@@ -0,0 +1,89 @@
+package healthcheck
+
+import (
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// VPNLooper is the VPN loop the recovery actions are taken on.
+type VPNLooper interface {
+	SetStatus(status models.LoopStatus) (outcome string, err error)
+	SwitchServer() (outcome string)
+	SwitchProtocol() (outcome string, err error)
+	IsPaused() (paused bool)
+}
+
+// recovery escalates through the recovery actions while unhealthy.
+type recovery struct {
+	actions []string
+	period  time.Duration
+	timeNow func() time.Time
+	// next is the index of the next action to take.
+	next int
+	// since is the time the connection became unhealthy,
+	// or the time the last action was taken.
+	since time.Time
+}
+
+func newRecovery(actions []string, period time.Duration,
+	timeNow func() time.Time) *recovery {
+	return &recovery{
+		actions: actions,
+		period:  period,
+		timeNow: timeNow,
+	}
+}
+
+// update records the health of the connection, and returns the
+// recovery action to take, or the empty string if none.
+func (r *recovery) update(healthy bool) (action string) {
+	now := r.timeNow()
+	switch {
+	case healthy:
+		r.next = 0
+		r.since = time.Time{}
+		return ""
+	case r.since.IsZero():
+		r.since = now
+		return ""
+	case now.Sub(r.since) < r.period, r.next == len(r.actions):
+		return ""
+	}
+
+	action = r.actions[r.next]
+	r.next++
+	r.since = now
+	return action
+}
+
+func (s *server) recover(action string) {
+	if s.vpnLooper == nil && action != constants.HealthRecoveryExit {
+		s.logger.Warn("cannot take recovery action %s: only supported with OpenVPN", action)
+		return
+	}
+
+	s.logger.Warn("taking recovery action %s", action)
+	var outcome string
+	var err error
+	switch action {
+	case constants.HealthRecoveryRestart:
+		if _, err = s.vpnLooper.SetStatus(constants.Stopped); err == nil {
+			outcome, err = s.vpnLooper.SetStatus(constants.Running)
+		}
+	case constants.HealthRecoverySwitchServer:
+		outcome = s.vpnLooper.SwitchServer()
+	case constants.HealthRecoverySwitchProtocol:
+		outcome, err = s.vpnLooper.SwitchProtocol()
+	case constants.HealthRecoveryExit:
+		s.exit()
+		return
+	}
+
+	if err != nil {
+		s.logger.Error("recovery action %s failed: %s", action, err)
+		return
+	}
+	s.logger.Info("recovery action %s: %s", action, outcome)
+}
@@ -0,0 +1,39 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_recovery_update(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	timeNow := func() time.Time { return now }
+	actions := []string{constants.HealthRecoveryRestart, constants.HealthRecoverySwitchServer}
+	r := newRecovery(actions, time.Minute, timeNow)
+
+	steps := []struct {
+		elapsed time.Duration
+		healthy bool
+		action  string
+	}{
+		{healthy: false},
+		{elapsed: 59 * time.Second, healthy: false},
+		{elapsed: time.Second, healthy: false, action: constants.HealthRecoveryRestart},
+		{elapsed: 30 * time.Second, healthy: false},
+		{elapsed: 30 * time.Second, healthy: false, action: constants.HealthRecoverySwitchServer},
+		{elapsed: time.Hour, healthy: false},
+		{elapsed: time.Second, healthy: true},
+		{elapsed: time.Second, healthy: false},
+		{elapsed: time.Minute, healthy: false, action: constants.HealthRecoveryRestart},
+	}
+	for i, step := range steps {
+		now = now.Add(step.elapsed)
+		action := r.update(step.healthy)
+		assert.Equal(t, step.action, action, "step %d", i)
+	}
+}
@@ -3,32 +3,87 @@ package healthcheck
 import (
 	"context"
 	"errors"
+	"math/rand"
 	"net"
 	"net/http"
 	"sync"
 	"time"
 
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/publicip"
+	"github.com/qdm12/golibs/command"
 	"github.com/qdm12/golibs/logging"
 )
 
 type Server interface {
 	Run(ctx context.Context, wg *sync.WaitGroup)
+	GetLatency() (latency time.Duration)
+	GetHistory() (probes []models.HealthProbe)
+	GetStats() (stats models.HealthStats)
 }
 
 type server struct {
-	address  string
-	logger   logging.Logger
-	handler  *handler
-	resolver *net.Resolver
+	address      string
+	logger       logging.Logger
+	handler      *handler
+	resolver     *net.Resolver
+	httpClient   *http.Client
+	commander    command.Commander
+	openvpnState func() models.OpenVPNConnectionState
+	settings     configuration.Health
+	recovery     *recovery
+	backoff      *backoff
+	history      history
+	vpnLooper    VPNLooper
+	exit         context.CancelFunc
+	fw           firewall.Configurator
+	ipGetter     publicip.IPGetter
+	preVPNIP     net.IP
+	// ipv6Getter is nil if IPv6 is disabled.
+	ipv6Getter publicip.IPGetter
+	preVPNIPv6 net.IP
+	leakErr    error
+	leakErrMu  sync.RWMutex
 }
 
-func NewServer(address string, logger logging.Logger) Server {
+// NewServer creates a new healthcheck server. The openvpnState function
+// and the vpnLooper can be nil if OpenVPN is not used. The exit function
+// is called to exit the program as the last recovery action. The preVPNIP
+// and preVPNIPv6 are the public IP addresses before connecting to the VPN,
+// and can be nil. The publicIPSettings are used to fetch the public IP
+// addresses to check for leaks.
+func NewServer(address string, logger logging.Logger,
+	openvpnState func() models.OpenVPNConnectionState,
+	settings configuration.Health, vpnLooper VPNLooper,
+	exit context.CancelFunc, fw firewall.Configurator,
+	preVPNIP, preVPNIPv6 net.IP, publicIPSettings configuration.PublicIP) Server {
+	httpClient := &http.Client{}
 	healthcheckLogger := logger.NewChild(logging.SetPrefix("healthcheck: "))
+	var ipv6Getter publicip.IPGetter
+	if publicIPSettings.IPv6 {
+		ipv6Getter = publicip.NewIPv6Getter(httpClient, publicIPSettings)
+	}
 	return &server{
-		address:  address,
-		logger:   healthcheckLogger,
-		handler:  newHandler(healthcheckLogger),
-		resolver: net.DefaultResolver,
+		address:      address,
+		logger:       healthcheckLogger,
+		handler:      newHandler(healthcheckLogger),
+		resolver:     net.DefaultResolver,
+		httpClient:   httpClient,
+		commander:    command.NewCommander(),
+		openvpnState: openvpnState,
+		settings:     settings,
+		recovery:     newRecovery(settings.Recovery, settings.RecoveryPeriod, time.Now),
+		vpnLooper:    vpnLooper,
+		exit:         exit,
+		fw:           fw,
+		ipGetter:     publicip.NewIPGetter(httpClient, publicIPSettings),
+		preVPNIP:     preVPNIP,
+		ipv6Getter:   ipv6Getter,
+		preVPNIPv6:   preVPNIPv6,
+		backoff: newBackoff(settings.BackoffInitial, settings.BackoffMax,
+			rand.New(rand.NewSource(time.Now().UnixNano())).Int63n), //nolint:gosec
 	}
 }
 
@@ -38,6 +93,8 @@ func (s *server) Run(ctx context.Context, wg *sync.WaitGroup) {
 	internalWg := &sync.WaitGroup{}
 	internalWg.Add(1)
 	go s.runHealthcheckLoop(ctx, internalWg)
+	internalWg.Add(1)
+	go s.runLeakCheckLoop(ctx, internalWg)
 
 	server := http.Server{
 		Addr:    s.address,
@@ -65,3 +122,18 @@ func (s *server) Run(ctx context.Context, wg *sync.WaitGroup) {
 
 	internalWg.Wait()
 }
+
+func (s *server) GetLatency() (latency time.Duration) {
+	return s.handler.getLatency()
+}
+
+// GetHistory returns the last health probes, oldest first.
+func (s *server) GetHistory() (probes []models.HealthProbe) {
+	return s.history.get()
+}
+
+func (s *server) GetStats() (stats models.HealthStats) {
+	stats = s.history.stats()
+	stats.Healthy = s.handler.getErr() == nil
+	return stats
+}
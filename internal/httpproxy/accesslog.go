@@ -0,0 +1,109 @@
+package httpproxy
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+// accessEntry is an access through the proxy, logged and
+// counted once the request or the tunnel is done.
+type accessEntry struct {
+	// sent and received are the bytes sent to and received from
+	// the destination, accessed atomically and first in the struct
+	// for their alignment on 32 bit platforms.
+	sent, received uint64
+	start          time.Time
+	client         string
+	user           string
+	method         string
+	host           string
+	status         int
+}
+
+func (e *accessEntry) denied() bool {
+	switch e.status {
+	case http.StatusBadRequest, http.StatusUnauthorized,
+		http.StatusForbidden, http.StatusProxyAuthRequired:
+		return true
+	default:
+		return false
+	}
+}
+
+func (e *accessEntry) failed() bool {
+	return e.status >= http.StatusInternalServerError
+}
+
+func (e *accessEntry) String() string {
+	user := e.user
+	if user == "" {
+		user = "-"
+	}
+	return fmt.Sprintf("client=%s user=%s method=%s host=%s status=%d sent=%d received=%d duration=%s",
+		e.client, user, e.method, e.host, e.status,
+		atomic.LoadUint64(&e.sent), atomic.LoadUint64(&e.received),
+		time.Since(e.start).Round(time.Millisecond))
+}
+
+// logAccess counts the access and logs it depending on the log level.
+func (h *handler) logAccess(entry *accessEntry) {
+	h.stats.add(entry)
+	switch h.logLevel {
+	case constants.HTTPProxyLogAll:
+	case constants.HTTPProxyLogErrors:
+		if entry.status < http.StatusBadRequest {
+			return
+		}
+	default:
+		return
+	}
+	h.logger.Info("access " + entry.String())
+}
+
+// statusRecorder records the status code written.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (n int, err error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+var errHijackNotSupported = errors.New("hijacking not supported")
+
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errHijackNotSupported
+	}
+	return hijacker.Hijack()
+}
+
+// countingReadCloser counts the bytes read atomically.
+type countingReadCloser struct {
+	io.ReadCloser
+	count *uint64
+}
+
+func (r *countingReadCloser) Read(p []byte) (n int, err error) {
+	n, err = r.ReadCloser.Read(p)
+	atomic.AddUint64(r.count, uint64(n))
+	return n, err
+}
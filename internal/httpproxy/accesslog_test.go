@@ -0,0 +1,69 @@
+package httpproxy
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_handler_logAccess(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		logLevel string
+		status   int
+		logged   bool
+		stats    models.HTTPProxyStats
+	}{
+		"log off": {
+			logLevel: constants.HTTPProxyLogOff,
+			status:   http.StatusOK,
+			stats:    models.HTTPProxyStats{Requests: 1, BytesSent: 1, BytesReceived: 2},
+		},
+		"log errors with success": {
+			logLevel: constants.HTTPProxyLogErrors,
+			status:   http.StatusOK,
+			stats:    models.HTTPProxyStats{Requests: 1, BytesSent: 1, BytesReceived: 2},
+		},
+		"log errors with denied": {
+			logLevel: constants.HTTPProxyLogErrors,
+			status:   http.StatusProxyAuthRequired,
+			logged:   true,
+			stats:    models.HTTPProxyStats{Requests: 1, Denied: 1, BytesSent: 1, BytesReceived: 2},
+		},
+		"log all with failure": {
+			logLevel: constants.HTTPProxyLogAll,
+			status:   http.StatusServiceUnavailable,
+			logged:   true,
+			stats:    models.HTTPProxyStats{Requests: 1, Failed: 1, BytesSent: 1, BytesReceived: 2},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			logger := mock_logging.NewMockLogger(ctrl)
+			if testCase.logged {
+				logger.EXPECT().Info(gomock.Any())
+			}
+			h := &handler{
+				logger:   logger,
+				logLevel: testCase.logLevel,
+				stats:    &stats{},
+			}
+			entry := &accessEntry{sent: 1, received: 2, status: testCase.status}
+
+			h.logAccess(entry)
+
+			assert.Equal(t, testCase.stats, h.stats.get())
+		})
+	}
+}
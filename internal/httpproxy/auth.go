@@ -1,21 +1,26 @@
 package httpproxy
 
 import (
+	"crypto/subtle"
 	"encoding/base64"
 	"net/http"
 	"strings"
 )
 
-func (h *handler) isAuthorized(responseWriter http.ResponseWriter, request *http.Request) (authorized bool) {
-	if len(h.username) == 0 || (request.Method != "CONNECT" && !request.URL.IsAbs()) {
-		return true
+// authenticate returns the user authenticated by the request, which is
+// nil if authentication is disabled or not needed for the request, and
+// false if the request is not authorized and was responded to.
+func (h *handler) authenticate(responseWriter http.ResponseWriter, request *http.Request) (
+	u *user, authorized bool) {
+	if len(h.users) == 0 || (request.Method != "CONNECT" && !request.URL.IsAbs()) {
+		return nil, true
 	}
 	basicAuth := request.Header.Get("Proxy-Authorization")
 	if len(basicAuth) == 0 {
 		h.logger.Info("Proxy-Authorization header not found from %s", request.RemoteAddr)
 		responseWriter.Header().Set("Proxy-Authenticate", `Basic realm="Access to Gluetun over HTTP"`)
 		responseWriter.WriteHeader(http.StatusProxyAuthRequired)
-		return false
+		return nil, false
 	}
 	b64UsernamePassword := strings.TrimPrefix(basicAuth, "Basic ")
 	b, err := base64.StdEncoding.DecodeString(b64UsernamePassword)
@@ -23,19 +28,20 @@ func (h *handler) isAuthorized(responseWriter http.ResponseWriter, request *http
 		h.logger.Info("Cannot decode Proxy-Authorization header value from %s: %s",
 			request.RemoteAddr, err.Error())
 		responseWriter.WriteHeader(http.StatusUnauthorized)
-		return false
+		return nil, false
 	}
-	usernamePassword := strings.Split(string(b), ":")
 	const expectedFields = 2
+	usernamePassword := strings.SplitN(string(b), ":", expectedFields)
 	if len(usernamePassword) != expectedFields {
 		responseWriter.WriteHeader(http.StatusBadRequest)
-		return false
+		return nil, false
 	}
-	if h.username != usernamePassword[0] || h.password != usernamePassword[1] {
+	u, ok := h.users[usernamePassword[0]]
+	if !ok || subtle.ConstantTimeCompare([]byte(u.password), []byte(usernamePassword[1])) != 1 {
 		h.logger.Info("Username or password mismatch from %s", request.RemoteAddr)
-		h.logger.Debug("username provided %q and password provided %q", usernamePassword[0], usernamePassword[1])
+		h.logger.Debug("username provided %q", usernamePassword[0])
 		responseWriter.WriteHeader(http.StatusUnauthorized)
-		return false
+		return nil, false
 	}
-	return true
+	return u, true
 }
@@ -0,0 +1,102 @@
+package httpproxy
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// limiter limits the bandwidth shared by the readers using it.
+type limiter struct {
+	bytesPerSecond float64
+	timeNow        func() time.Time
+	// next is the time the next bytes can be transferred at.
+	next time.Time
+	mu   sync.Mutex
+}
+
+func newLimiter(bytesPerSecond uint64) *limiter {
+	return &limiter{
+		bytesPerSecond: float64(bytesPerSecond),
+		timeNow:        time.Now,
+	}
+}
+
+// reserve reserves the transfer of n bytes and returns
+// the duration to wait for before they can be transferred.
+func (l *limiter) reserve(n int) (wait time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.timeNow()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait = l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(float64(n) / l.bytesPerSecond * float64(time.Second)))
+	return wait
+}
+
+// wait blocks until n bytes can be transferred
+// or the context is canceled.
+func (l *limiter) wait(ctx context.Context, n int) (err error) {
+	wait := l.reserve(n)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		if !timer.Stop() {
+			<-timer.C
+		}
+		return ctx.Err()
+	}
+}
+
+// limiters are limiters which must all allow a transfer.
+type limiters []*limiter
+
+// wait blocks until n bytes can be transferred by all the
+// limiters or the context is canceled.
+func (l limiters) wait(ctx context.Context, n int) (err error) {
+	for _, limiter := range l {
+		if err := limiter.wait(ctx, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// limitedReadCloser is a read closer limited by limiters.
+type limitedReadCloser struct {
+	io.ReadCloser
+	ctx      context.Context
+	limiters limiters
+}
+
+func (r *limitedReadCloser) Read(p []byte) (n int, err error) {
+	n, err = r.ReadCloser.Read(p)
+	if n > 0 {
+		if waitErr := r.limiters.wait(r.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+// limitReadCloser returns the read closer given limited by
+// the limiters given, or as is if there is no limiter.
+func limitReadCloser(ctx context.Context, readCloser io.ReadCloser,
+	limiters limiters) io.ReadCloser {
+	if len(limiters) == 0 {
+		return readCloser
+	}
+	return &limitedReadCloser{
+		ReadCloser: readCloser,
+		ctx:        ctx,
+		limiters:   limiters,
+	}
+}
@@ -10,7 +10,8 @@ import (
 )
 
 func newHandler(ctx context.Context, wg *sync.WaitGroup, logger logging.Logger,
-	stealth, verbose bool, username, password string) http.Handler {
+	stealth bool, logLevel string, users map[string]*user, pac *pac,
+	limits *limits, stats *stats) http.Handler {
 	const httpTimeout = 24 * time.Hour
 	return &handler{
 		ctx: ctx,
@@ -19,37 +20,90 @@ func newHandler(ctx context.Context, wg *sync.WaitGroup, logger logging.Logger,
 			Timeout:       httpTimeout,
 			CheckRedirect: returnRedirect},
 		logger:   logger,
-		verbose:  verbose,
+		logLevel: logLevel,
 		stealth:  stealth,
-		username: username,
-		password: password,
+		users:    users,
+		pac:      pac,
+		limits:   limits,
+		stats:    stats,
 	}
 }
 
 type handler struct {
-	ctx                context.Context
-	wg                 *sync.WaitGroup
-	client             *http.Client
-	logger             logging.Logger
-	verbose, stealth   bool
-	username, password string
+	ctx      context.Context
+	wg       *sync.WaitGroup
+	client   *http.Client
+	logger   logging.Logger
+	logLevel string
+	stealth  bool
+	// users are the users allowed keyed by name,
+	// and is empty if authentication is disabled.
+	users map[string]*user
+	// pac serves the proxy auto-config file,
+	// and is nil if serving it is disabled.
+	pac    *pac
+	limits *limits
+	stats  *stats
 }
 
-func (h *handler) ServeHTTP(responseWriter http.ResponseWriter, request *http.Request) {
+func (h *handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	responseWriter := &statusRecorder{ResponseWriter: writer}
+	entry := &accessEntry{
+		start:  time.Now(),
+		client: request.RemoteAddr,
+		method: request.Method,
+		host:   request.Host,
+	}
+	tunneling := false
+	defer func() {
+		if !tunneling { // logged once the tunnel is closed otherwise
+			entry.status = responseWriter.status
+			h.logAccess(entry)
+		}
+	}()
+
 	if !h.isAccepted(responseWriter, request) {
 		return
 	}
-	if !h.isAuthorized(responseWriter, request) {
+	if h.pac != nil && isPACRequest(request) {
+		h.pac.serve(responseWriter, request)
+		return
+	}
+	u, authorized := h.authenticate(responseWriter, request)
+	if !authorized {
 		return
 	}
+	bandwidthLimiters, release, ok := h.limits.acquire(request.RemoteAddr)
+	if !ok {
+		h.logger.Info("too many connections from %s", request.RemoteAddr)
+		http.Error(responseWriter, "too many connections", http.StatusTooManyRequests)
+		return
+	}
+	defer func() {
+		if !tunneling { // released once the tunnel is closed otherwise
+			release()
+		}
+	}()
+	if u != nil {
+		entry.user = u.name
+		if !u.allows(request) {
+			h.logger.Info("destination %s not allowed for user %s from %s",
+				request.Host, u.name, request.RemoteAddr)
+			http.Error(responseWriter, "destination port not allowed", http.StatusForbidden)
+			return
+		}
+		if u.limiter != nil {
+			bandwidthLimiters = append(bandwidthLimiters, u.limiter)
+		}
+	}
 	request.Header.Del("Proxy-Connection")
 	request.Header.Del("Proxy-Authenticate")
 	request.Header.Del("Proxy-Authorization")
 	switch request.Method {
 	case http.MethodConnect:
-		h.handleHTTPS(responseWriter, request)
+		tunneling = h.handleHTTPS(responseWriter, request, bandwidthLimiters, release, entry)
 	default:
-		h.handleHTTP(responseWriter, request)
+		h.handleHTTP(responseWriter, request, bandwidthLimiters, entry)
 	}
 }
 
@@ -8,7 +8,8 @@ import (
 	"strings"
 )
 
-func (h *handler) handleHTTP(responseWriter http.ResponseWriter, request *http.Request) {
+func (h *handler) handleHTTP(responseWriter http.ResponseWriter, request *http.Request,
+	bandwidthLimiters limiters, entry *accessEntry) {
 	switch request.URL.Scheme {
 	case "http", "https":
 	default:
@@ -20,6 +21,12 @@ func (h *handler) handleHTTP(responseWriter http.ResponseWriter, request *http.R
 	request = request.WithContext(h.ctx)
 
 	request.RequestURI = ""
+	if request.Body != nil {
+		request.Body = &countingReadCloser{
+			ReadCloser: limitReadCloser(h.ctx, request.Body, bandwidthLimiters),
+			count:      &entry.sent,
+		}
+	}
 
 	for _, key := range hopHeaders {
 		request.Header.Del(key)
@@ -37,9 +44,6 @@ func (h *handler) handleHTTP(responseWriter http.ResponseWriter, request *http.R
 		return
 	}
 	defer response.Body.Close()
-	if h.verbose {
-		h.logger.Info("%s %s %s %s", request.RemoteAddr, response.Status, request.Method, request.URL)
-	}
 
 	for _, key := range hopHeaders {
 		response.Header.Del(key)
@@ -53,7 +57,11 @@ func (h *handler) handleHTTP(responseWriter http.ResponseWriter, request *http.R
 	}
 
 	responseWriter.WriteHeader(response.StatusCode)
-	if _, err := io.Copy(responseWriter, response.Body); err != nil {
+	body := &countingReadCloser{
+		ReadCloser: limitReadCloser(h.ctx, response.Body, bandwidthLimiters),
+		count:      &entry.received,
+	}
+	if _, err := io.Copy(responseWriter, body); err != nil {
 		h.logger.Error("%s %s: body copy error: %s", request.RemoteAddr, request.URL, err)
 	}
 }
@@ -8,12 +8,16 @@ import (
 	"sync"
 )
 
-func (h *handler) handleHTTPS(responseWriter http.ResponseWriter, request *http.Request) {
+// handleHTTPS tunnels the connection of the client to the destination,
+// and returns true if the tunnel is established, in which case the
+// access is logged and release is called once the tunnel is closed.
+func (h *handler) handleHTTPS(responseWriter http.ResponseWriter, request *http.Request,
+	bandwidthLimiters limiters, release func(), entry *accessEntry) (tunneling bool) {
 	dialer := net.Dialer{}
 	destinationConn, err := dialer.DialContext(h.ctx, "tcp", request.Host)
 	if err != nil {
 		http.Error(responseWriter, err.Error(), http.StatusServiceUnavailable)
-		return
+		return false
 	}
 
 	responseWriter.WriteHeader(http.StatusOK)
@@ -21,7 +25,7 @@ func (h *handler) handleHTTPS(responseWriter http.ResponseWriter, request *http.
 	hijacker, ok := responseWriter.(http.Hijacker)
 	if !ok {
 		http.Error(responseWriter, "Hijacking not supported", http.StatusInternalServerError)
-		return
+		return false
 	}
 	clientConnection, _, err := hijacker.Hijack()
 	if err != nil {
@@ -30,11 +34,7 @@ func (h *handler) handleHTTPS(responseWriter http.ResponseWriter, request *http.
 		if err := destinationConn.Close(); err != nil {
 			h.logger.Error("closing destination connection: %s", err)
 		}
-		return
-	}
-
-	if h.verbose {
-		h.logger.Info("%s <-> %s", request.RemoteAddr, request.Host)
+		return false
 	}
 
 	h.wg.Add(1)
@@ -50,10 +50,22 @@ func (h *handler) handleHTTPS(responseWriter http.ResponseWriter, request *http.
 		<-ctx.Done()
 		destinationConn.Close()
 		clientConnection.Close()
+		release()
+		entry.status = http.StatusOK
+		h.logAccess(entry)
 		h.wg.Done()
 	}()
-	go transfer(destinationConn, clientConnection, wg)
-	go transfer(clientConnection, destinationConn, wg)
+	fromClient := &countingReadCloser{
+		ReadCloser: limitReadCloser(ctx, clientConnection, bandwidthLimiters),
+		count:      &entry.sent,
+	}
+	fromDestination := &countingReadCloser{
+		ReadCloser: limitReadCloser(ctx, destinationConn, bandwidthLimiters),
+		count:      &entry.received,
+	}
+	go transfer(destinationConn, fromClient, wg)
+	go transfer(clientConnection, fromDestination, wg)
+	return true
 }
 
 func transfer(destination io.WriteCloser, source io.ReadCloser, wg *sync.WaitGroup) {
@@ -0,0 +1,101 @@
+package httpproxy
+
+import (
+	"net"
+	"sync"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+)
+
+// limits limits the bandwidth and the number of connections
+// of all the clients and of each client.
+type limits struct {
+	// maxConnections is the maximum number of concurrent
+	// connections, and is 0 for no limit.
+	maxConnections int
+	// clientMaxConnections is the maximum number of concurrent
+	// connections of each client, and is 0 for no limit.
+	clientMaxConnections int
+	// clientMaxBandwidth is the maximum bandwidth in bytes per
+	// second of each client, and is 0 for no limit.
+	clientMaxBandwidth uint64
+	// limiter limits the bandwidth of all the clients,
+	// and is nil for no limit.
+	limiter *limiter
+
+	mu          sync.Mutex
+	connections int
+	clients     map[string]*client
+}
+
+// client is a client of the proxy, identified by its IP address.
+type client struct {
+	connections int
+	// limiter limits the bandwidth of all the connections
+	// of the client, and is nil for no limit.
+	limiter *limiter
+}
+
+func newLimits(settings configuration.HTTPProxy) *limits {
+	l := &limits{
+		maxConnections:       settings.MaxConnections,
+		clientMaxConnections: settings.ClientMaxConnections,
+		clientMaxBandwidth:   settings.ClientMaxBandwidth,
+		clients:              make(map[string]*client),
+	}
+	if settings.MaxBandwidth > 0 {
+		l.limiter = newLimiter(settings.MaxBandwidth)
+	}
+	return l
+}
+
+// acquire reserves a connection for the client at the address given,
+// and returns the bandwidth limiters to use for the connection and the
+// function to call once the connection is closed. It returns false
+// if the connection is over the connection limits.
+func (l *limits) acquire(address string) (
+	bandwidthLimiters limiters, release func(), ok bool) {
+	ip, _, err := net.SplitHostPort(address)
+	if err != nil {
+		ip = address
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	c, exists := l.clients[ip]
+	switch {
+	case l.maxConnections > 0 && l.connections >= l.maxConnections,
+		exists && l.clientMaxConnections > 0 && c.connections >= l.clientMaxConnections:
+		return nil, nil, false
+	case !exists:
+		c = &client{}
+		if l.clientMaxBandwidth > 0 {
+			c.limiter = newLimiter(l.clientMaxBandwidth)
+		}
+		l.clients[ip] = c
+	}
+	l.connections++
+	c.connections++
+
+	if l.limiter != nil {
+		bandwidthLimiters = append(bandwidthLimiters, l.limiter)
+	}
+	if c.limiter != nil {
+		bandwidthLimiters = append(bandwidthLimiters, c.limiter)
+	}
+
+	once := &sync.Once{}
+	release = func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.connections--
+			c.connections--
+			if c.connections == 0 {
+				delete(l.clients, ip)
+			}
+		})
+	}
+	return bandwidthLimiters, release, true
+}
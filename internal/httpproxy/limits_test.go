@@ -0,0 +1,48 @@
+package httpproxy
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_limits_acquire(t *testing.T) {
+	t.Parallel()
+
+	l := newLimits(configuration.HTTPProxy{
+		MaxBandwidth:         1000,
+		ClientMaxBandwidth:   100,
+		MaxConnections:       3,
+		ClientMaxConnections: 2,
+	})
+
+	limitersA, releaseA1, ok := l.acquire("1.2.3.4:1000")
+	require.True(t, ok)
+	assert.Len(t, limitersA, 2)
+	_, releaseA2, ok := l.acquire("1.2.3.4:1001")
+	require.True(t, ok)
+
+	_, _, ok = l.acquire("1.2.3.4:1002")
+	assert.False(t, ok, "client connections limit")
+
+	limitersB, releaseB, ok := l.acquire("5.6.7.8:1000")
+	require.True(t, ok)
+	assert.Same(t, limitersA[0], limitersB[0], "global limiter shared")
+	assert.NotSame(t, limitersA[1], limitersB[1], "client limiters not shared")
+
+	_, _, ok = l.acquire("9.9.9.9:1000")
+	assert.False(t, ok, "connections limit")
+
+	releaseA1()
+	releaseA1() // no-op
+	_, releaseA3, ok := l.acquire("1.2.3.4:1003")
+	require.True(t, ok)
+
+	releaseA2()
+	releaseA3()
+	releaseB()
+	assert.Empty(t, l.clients)
+	assert.Zero(t, l.connections)
+}
@@ -3,7 +3,6 @@ package httpproxy
 
 import (
 	"context"
-	"fmt"
 	"sync"
 	"time"
 
@@ -19,12 +18,14 @@ type Looper interface {
 	GetStatus() (status models.LoopStatus)
 	GetSettings() (settings configuration.HTTPProxy)
 	SetSettings(settings configuration.HTTPProxy) (outcome string)
+	GetStats() (stats models.HTTPProxyStats)
 }
 
 type looper struct {
 	state state
 	// Other objects
 	logger logging.Logger
+	stats  *stats
 	// Internal channels and locks
 	loopLock      sync.Mutex
 	running       chan models.LoopStatus
@@ -42,6 +43,7 @@ func NewLooper(logger logging.Logger, settings configuration.HTTPProxy) Looper {
 			settings: settings,
 		},
 		logger:      logger.NewChild(logging.SetPrefix("http proxy: ")),
+		stats:       &stats{},
 		start:       make(chan struct{}),
 		running:     make(chan models.LoopStatus),
 		stop:        make(chan struct{}),
@@ -73,8 +75,7 @@ func (l *looper) Run(ctx context.Context, wg *sync.WaitGroup) {
 		runCtx, runCancel := context.WithCancel(ctx)
 
 		settings := l.GetSettings()
-		address := fmt.Sprintf(":%d", settings.Port)
-		server := New(runCtx, address, l.logger, settings.Stealth, settings.Log, settings.User, settings.Password)
+		server := New(runCtx, l.logger, settings, l.stats)
 
 		runWg := &sync.WaitGroup{}
 		runWg.Add(1)
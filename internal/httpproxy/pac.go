@@ -0,0 +1,67 @@
+package httpproxy
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// pacPath is the path the proxy auto-config file is served at.
+	pacPath = "/proxy.pac"
+	// wpadPath is the path clients using the Web Proxy Auto-Discovery
+	// protocol request the proxy auto-config file at.
+	wpadPath = "/wpad.dat"
+)
+
+// pac generates the proxy auto-config file.
+type pac struct {
+	// domains are the domains to proxy with their subdomains,
+	// and is empty to proxy all domains.
+	domains []string
+}
+
+func newPAC(domains []string) *pac {
+	return &pac{domains: domains}
+}
+
+// isPACRequest returns true if the request is a request for
+// the proxy auto-config file, and not a proxy request.
+func isPACRequest(request *http.Request) bool {
+	return request.Method == http.MethodGet && !request.URL.IsAbs() &&
+		(request.URL.Path == pacPath || request.URL.Path == wpadPath)
+}
+
+// serve writes the proxy auto-config file, using the host the
+// client requested it from as the proxy address.
+func (p *pac) serve(writer http.ResponseWriter, request *http.Request) {
+	writer.Header().Set("Content-Type", "application/x-ns-proxy-autoconfig")
+	_, _ = writer.Write([]byte(p.generate(request.Host)))
+}
+
+func (p *pac) generate(proxyHost string) string {
+	var conditions []string
+	for _, domain := range p.domains {
+		conditions = append(conditions,
+			"host === "+strconv.Quote(domain)+" || dnsDomainIs(host, "+strconv.Quote("."+domain)+")")
+	}
+
+	lines := []string{
+		"function FindProxyForURL(url, host) {",
+		`  if (isPlainHostName(host)) {`,
+		`    return "DIRECT";`,
+		"  }",
+	}
+	if len(conditions) == 0 {
+		lines = append(lines, `  return "PROXY `+proxyHost+`";`)
+	} else {
+		lines = append(lines,
+			"  if ("+strings.Join(conditions, " ||\n      ")+") {",
+			`    return "PROXY `+proxyHost+`";`,
+			"  }",
+			`  return "DIRECT";`,
+		)
+	}
+	lines = append(lines, "}")
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,86 @@
+package httpproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_pac_generate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		domains []string
+		pac     string
+	}{
+		"all domains": {
+			pac: `function FindProxyForURL(url, host) {
+  if (isPlainHostName(host)) {
+    return "DIRECT";
+  }
+  return "PROXY 192.168.1.2:8888";
+}
+`,
+		},
+		"domains": {
+			domains: []string{"example.com", "github.com"},
+			pac: `function FindProxyForURL(url, host) {
+  if (isPlainHostName(host)) {
+    return "DIRECT";
+  }
+  if (host === "example.com" || dnsDomainIs(host, ".example.com") ||
+      host === "github.com" || dnsDomainIs(host, ".github.com")) {
+    return "PROXY 192.168.1.2:8888";
+  }
+  return "DIRECT";
+}
+`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pac := newPAC(testCase.domains)
+
+			assert.Equal(t, testCase.pac, pac.generate("192.168.1.2:8888"))
+		})
+	}
+}
+
+func Test_isPACRequest(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		request *http.Request
+		pac     bool
+	}{
+		"pac file": {
+			request: httptest.NewRequest(http.MethodGet, "/proxy.pac", nil),
+			pac:     true,
+		},
+		"wpad file": {
+			request: httptest.NewRequest(http.MethodGet, "/wpad.dat", nil),
+			pac:     true,
+		},
+		"proxy request": {
+			request: httptest.NewRequest(http.MethodGet, "http://example.com/proxy.pac", nil),
+		},
+		"other path": {
+			request: httptest.NewRequest(http.MethodGet, "/", nil),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.pac, isPACRequest(testCase.request))
+		})
+	}
+}
@@ -2,10 +2,12 @@ package httpproxy
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"sync"
 	"time"
 
+	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/golibs/logging"
 )
 
@@ -20,12 +22,18 @@ type server struct {
 	internalWG *sync.WaitGroup
 }
 
-func New(ctx context.Context, address string, logger logging.Logger,
-	stealth, verbose bool, username, password string) Server {
+func New(ctx context.Context, logger logging.Logger,
+	settings configuration.HTTPProxy, stats *stats) Server {
 	wg := &sync.WaitGroup{}
+	var pac *pac
+	if settings.PAC {
+		pac = newPAC(settings.PACDomains)
+	}
 	return &server{
-		address:    address,
-		handler:    newHandler(ctx, wg, logger, stealth, verbose, username, password),
+		address: fmt.Sprintf(":%d", settings.Port),
+		handler: newHandler(ctx, wg, logger, settings.Stealth, settings.Log,
+			newUsers(settings.User, settings.Password, settings.Users), pac,
+			newLimits(settings), stats),
 		logger:     logger,
 		internalWG: wg,
 	}
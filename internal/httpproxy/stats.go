@@ -0,0 +1,44 @@
+package httpproxy
+
+import (
+	"sync/atomic"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// stats counts the accesses through the proxy.
+// Its fields are only accessed atomically, and are all
+// 64 bits for their alignment on 32 bit platforms.
+type stats struct {
+	requests      uint64
+	denied        uint64
+	failed        uint64
+	bytesSent     uint64
+	bytesReceived uint64
+}
+
+func (s *stats) add(entry *accessEntry) {
+	atomic.AddUint64(&s.requests, 1)
+	switch {
+	case entry.denied():
+		atomic.AddUint64(&s.denied, 1)
+	case entry.failed():
+		atomic.AddUint64(&s.failed, 1)
+	}
+	atomic.AddUint64(&s.bytesSent, atomic.LoadUint64(&entry.sent))
+	atomic.AddUint64(&s.bytesReceived, atomic.LoadUint64(&entry.received))
+}
+
+func (s *stats) get() (httpProxyStats models.HTTPProxyStats) {
+	return models.HTTPProxyStats{
+		Requests:      atomic.LoadUint64(&s.requests),
+		Denied:        atomic.LoadUint64(&s.denied),
+		Failed:        atomic.LoadUint64(&s.failed),
+		BytesSent:     atomic.LoadUint64(&s.bytesSent),
+		BytesReceived: atomic.LoadUint64(&s.bytesReceived),
+	}
+}
+
+func (l *looper) GetStats() (httpProxyStats models.HTTPProxyStats) {
+	return l.stats.get()
+}
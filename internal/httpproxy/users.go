@@ -0,0 +1,86 @@
+package httpproxy
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+)
+
+// user is a user of the proxy with its restrictions.
+type user struct {
+	name     string
+	password string
+	// ports are the destination ports allowed,
+	// and is empty to allow all ports.
+	ports map[uint16]struct{}
+	// limiter limits the bandwidth of all the connections
+	// of the user, and is nil for no limit.
+	limiter *limiter
+}
+
+// newUsers returns the users keyed by name, from the single user given
+// by username and password, if any, and from the users given.
+func newUsers(username, password string, users []configuration.HTTPProxyUser) (
+	nameToUser map[string]*user) {
+	nameToUser = make(map[string]*user, len(users)+1)
+	if username != "" {
+		nameToUser[username] = &user{name: username, password: password}
+	}
+	for _, settings := range users {
+		u := &user{
+			name:     settings.Name,
+			password: settings.Password,
+		}
+		if len(settings.Ports) > 0 {
+			u.ports = make(map[uint16]struct{}, len(settings.Ports))
+			for _, port := range settings.Ports {
+				u.ports[port] = struct{}{}
+			}
+		}
+		if settings.MaxBandwidth > 0 {
+			u.limiter = newLimiter(settings.MaxBandwidth)
+		}
+		nameToUser[settings.Name] = u
+	}
+	return nameToUser
+}
+
+// allows returns true if the user is allowed to
+// connect to the destination of the request.
+func (u *user) allows(request *http.Request) bool {
+	if len(u.ports) == 0 {
+		return true
+	}
+	port, ok := destinationPort(request)
+	if !ok {
+		return false
+	}
+	_, ok = u.ports[port]
+	return ok
+}
+
+// destinationPort returns the port of the destination of the request,
+// and false if it cannot be determined.
+func destinationPort(request *http.Request) (port uint16, ok bool) {
+	var portString string
+	if request.Method == http.MethodConnect {
+		_, portString, _ = net.SplitHostPort(request.Host)
+	} else {
+		portString = request.URL.Port()
+		if portString == "" {
+			switch request.URL.Scheme {
+			case "http":
+				return 80, true //nolint:gomnd
+			case "https":
+				return 443, true //nolint:gomnd
+			}
+		}
+	}
+	value, err := strconv.ParseUint(portString, 10, 16) //nolint:gomnd
+	if err != nil {
+		return 0, false
+	}
+	return uint16(value), true
+}
@@ -0,0 +1,70 @@
+package httpproxy
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_user_allows(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ports   map[uint16]struct{}
+		request *http.Request
+		allowed bool
+	}{
+		"all ports allowed": {
+			request: &http.Request{Method: http.MethodConnect, Host: "example.com:22"},
+			allowed: true,
+		},
+		"connect port allowed": {
+			ports:   map[uint16]struct{}{443: {}},
+			request: &http.Request{Method: http.MethodConnect, Host: "example.com:443"},
+			allowed: true,
+		},
+		"connect port not allowed": {
+			ports:   map[uint16]struct{}{443: {}},
+			request: &http.Request{Method: http.MethodConnect, Host: "example.com:22"},
+		},
+		"http default port allowed": {
+			ports: map[uint16]struct{}{80: {}},
+			request: &http.Request{Method: http.MethodGet,
+				URL: &url.URL{Scheme: "http", Host: "example.com"}},
+			allowed: true,
+		},
+		"http explicit port not allowed": {
+			ports: map[uint16]struct{}{80: {}},
+			request: &http.Request{Method: http.MethodGet,
+				URL: &url.URL{Scheme: "http", Host: "example.com:8080"}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			u := &user{ports: testCase.ports}
+			allowed := u.allows(testCase.request)
+			assert.Equal(t, testCase.allowed, allowed)
+		})
+	}
+}
+
+func Test_limiter_reserve(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	l := newLimiter(1000)
+	l.timeNow = func() time.Time { return now }
+
+	assert.Equal(t, time.Duration(0), l.reserve(500))
+	assert.Equal(t, 500*time.Millisecond, l.reserve(1000))
+	now = now.Add(time.Second)
+	assert.Equal(t, 500*time.Millisecond, l.reserve(100))
+	now = now.Add(time.Hour)
+	assert.Equal(t, time.Duration(0), l.reserve(100))
+}
@@ -0,0 +1,177 @@
+package logging
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogLine is a log line kept in the buffer.
+type LogLine struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+}
+
+// Buffer is a ring buffer of the last log lines written to it,
+// such that it can be used as the writer of the logger.
+type Buffer struct {
+	lines []LogLine
+	// next is the index to write the next line at.
+	next  int
+	full  bool
+	mutex sync.RWMutex
+	// subscribers receive each line added, with
+	// the minimum level rank of the lines they receive.
+	subscribers map[chan LogLine]int
+}
+
+// NewBuffer creates a buffer keeping the last capacity lines.
+func NewBuffer(capacity int) *Buffer {
+	return &Buffer{
+		lines: make([]LogLine, capacity),
+	}
+}
+
+var ansiEscapeRegex = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// Write parses and stores each line of p, formatted as written by the
+// standard library logger with the date and time flags. It never fails.
+func (b *Buffer) Write(p []byte) (n int, err error) {
+	text := ansiEscapeRegex.ReplaceAllString(string(p), "")
+	for _, s := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		b.add(parseLogLine(s))
+	}
+	return len(p), nil
+}
+
+func (b *Buffer) add(line LogLine) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if len(b.lines) == 0 {
+		return
+	}
+	b.lines[b.next] = line
+	b.next = (b.next + 1) % len(b.lines)
+	if b.next == 0 {
+		b.full = true
+	}
+
+	rank := levelRank(line.Level)
+	for channel, minRank := range b.subscribers {
+		if rank < minRank {
+			continue
+		}
+		select {
+		case channel <- line:
+		default: // drop the line for slow subscribers
+		}
+	}
+}
+
+// subscriberBuffer is the number of lines buffered for each subscriber.
+const subscriberBuffer = 100
+
+// Subscribe returns the last tail lines as Lines does, a channel
+// receiving each line added after them of the level given or of a
+// more severe level, and a function to call once done to stop
+// receiving on the channel. Lines are dropped if the channel buffer
+// is full.
+func (b *Buffer) Subscribe(tail int, level string) (last []LogLine,
+	lines <-chan LogLine, unsubscribe func()) {
+	channel := make(chan LogLine, subscriberBuffer)
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	last = b.lastLines(tail, level)
+	if b.subscribers == nil {
+		b.subscribers = make(map[chan LogLine]int)
+	}
+	b.subscribers[channel] = levelRank(level)
+	unsubscribe = func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		delete(b.subscribers, channel)
+	}
+	return last, channel, unsubscribe
+}
+
+// parseLogLine parses a line such as
+// "2021/02/15 10:00:00 INFO dns: ready". A line not in this format,
+// such as a continuation line, has its time and level left empty.
+func parseLogLine(s string) (line LogLine) {
+	const timeLayout = "2006/01/02 15:04:05"
+	if len(s) <= len(timeLayout) {
+		return LogLine{Message: s}
+	}
+	t, err := time.ParseInLocation(timeLayout, s[:len(timeLayout)], time.Local)
+	if err != nil {
+		return LogLine{Message: s}
+	}
+	line.Time = t
+
+	rest := strings.TrimPrefix(s[len(timeLayout):], " ")
+	fields := strings.SplitN(rest, " ", 2) //nolint:gomnd
+	switch fields[0] {
+	case "DEBUG", "INFO", "WARN", "ERROR":
+		line.Level = strings.ToLower(fields[0])
+		if len(fields) == 2 { //nolint:gomnd
+			rest = fields[1]
+		} else {
+			rest = ""
+		}
+	}
+	line.Message = rest
+	return line
+}
+
+// levelRank returns the rank of the level, where a greater rank is
+// a more severe level. Lines without level have the rank 0.
+func levelRank(level string) int {
+	switch level {
+	case "debug":
+		return 1
+	case "info":
+		return 2 //nolint:gomnd
+	case "warn":
+		return 3 //nolint:gomnd
+	case "error":
+		return 4 //nolint:gomnd
+	default:
+		return 0
+	}
+}
+
+// ValidLevel returns true if the level can be given to Lines.
+func ValidLevel(level string) bool {
+	return level == "" || levelRank(level) > 0
+}
+
+// Lines returns the last tail lines, oldest first, of the level given
+// or of a more severe level. All the lines kept are returned if tail
+// is 0, and lines of all levels are returned if level is empty.
+func (b *Buffer) Lines(tail int, level string) (lines []LogLine) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return b.lastLines(tail, level)
+}
+
+func (b *Buffer) lastLines(tail int, level string) (lines []LogLine) {
+	ordered := b.lines[:b.next]
+	if b.full {
+		ordered = append(append([]LogLine{}, b.lines[b.next:]...), b.lines[:b.next]...)
+	}
+
+	minRank := levelRank(level)
+	lines = make([]LogLine, 0, len(ordered))
+	for _, line := range ordered {
+		if levelRank(line.Level) >= minRank {
+			lines = append(lines, line)
+		}
+	}
+
+	if tail > 0 && len(lines) > tail {
+		lines = lines[len(lines)-tail:]
+	}
+	return lines
+}
@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Buffer(t *testing.T) {
+	t.Parallel()
+
+	buffer := NewBuffer(3)
+	for _, s := range []string{
+		"2021/02/15 10:00:00 INFO first\n",
+		"2021/02/15 10:00:01 WARN dns: \x1b[33msecond\x1b[0m\n",
+		"2021/02/15 10:00:02 DEBUG third\ncontinued\n",
+		"2021/02/15 10:00:03 ERROR fourth\n",
+	} {
+		n, err := buffer.Write([]byte(s))
+		require.NoError(t, err)
+		assert.Equal(t, len(s), n)
+	}
+
+	date := func(second int) time.Time {
+		return time.Date(2021, time.February, 15, 10, 0, second, 0, time.Local)
+	}
+
+	expected := []LogLine{
+		{Time: date(2), Level: "debug", Message: "third"},
+		{Message: "continued"},
+		{Time: date(3), Level: "error", Message: "fourth"},
+	}
+	assert.Equal(t, expected, buffer.Lines(0, ""))
+
+	assert.Equal(t, expected[1:], buffer.Lines(2, ""))
+	assert.Equal(t, expected[2:], buffer.Lines(0, "warn"))
+
+	buffer = NewBuffer(3)
+	_, _ = buffer.Write([]byte("2021/02/15 10:00:01 WARN dns: \x1b[33msecond\x1b[0m\n"))
+	expected = []LogLine{{Time: date(1), Level: "warn", Message: "dns: second"}}
+	assert.Equal(t, expected, buffer.Lines(0, "info"))
+}
+
+func Test_Buffer_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	buffer := NewBuffer(2)
+	_, _ = buffer.Write([]byte("2021/02/15 09:00:00 WARN zeroth\n"))
+	last, lines, unsubscribe := buffer.Subscribe(0, "warn")
+	assert.Equal(t, []LogLine{{Time: time.Date(2021, time.February, 15, 9, 0, 0, 0, time.Local),
+		Level: "warn", Message: "zeroth"}}, last)
+
+	_, _ = buffer.Write([]byte("2021/02/15 10:00:00 INFO first\n2021/02/15 10:00:01 ERROR second\n"))
+	line := <-lines
+	assert.Equal(t, "second", line.Message)
+	assert.Empty(t, lines)
+
+	unsubscribe()
+	_, _ = buffer.Write([]byte("2021/02/15 10:00:02 ERROR third\n"))
+	assert.Empty(t, lines)
+}
@@ -3,6 +3,10 @@ package models
 type (
 	// LoopStatus status such as stopped or running.
 	LoopStatus string
+	// OpenVPNState is the state of the OpenVPN process such as connecting or up.
+	OpenVPNState string
+	// OpenVPNFailure is the reason of an OpenVPN connection failure.
+	OpenVPNFailure string
 )
 
 func (ls LoopStatus) String() string {
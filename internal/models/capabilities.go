@@ -0,0 +1,28 @@
+package models
+
+// ProviderCapabilities contains the features supported by a VPN provider,
+// for the settings to be validated against them.
+type ProviderCapabilities struct {
+	// PortForwarding is true if a port can be forwarded through the tunnel.
+	PortForwarding bool
+	// OpenVPNIPv6 is true if IPv6 is supported through the OpenVPN tunnel.
+	OpenVPNIPv6 bool
+	// WireguardKeyRotation is true if the Wireguard key can be rotated
+	// using the VPN provider API.
+	WireguardKeyRotation bool
+	// ServerFeatures are the server features servers can be filtered by.
+	ServerFeatures []string
+	// TCPFallbackPort is the TCP port to use when falling back on TCP,
+	// and is 0 to use the default TCP port of the VPN provider.
+	TCPFallbackPort uint16
+}
+
+// HasServerFeature returns true if servers can be filtered by the feature given.
+func (c ProviderCapabilities) HasServerFeature(feature string) bool {
+	for _, serverFeature := range c.ServerFeatures {
+		if serverFeature == feature {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"net"
+	"strconv"
+	"time"
+)
+
+// DoHServer is a DNS over HTTPS server, with the IP addresses of
+// its URL hostname to reach it without resolving the hostname.
+type DoHServer struct {
+	URL string   `json:"url"`
+	IPs []net.IP `json:"ips"`
+}
+
+// DNSSplitRule forwards DNS queries for a domain and its subdomains
+// to a plaintext DNS server outside the VPN, such as a LAN resolver.
+type DNSSplitRule struct {
+	Domain string `json:"domain"`
+	IP     net.IP `json:"ip"`
+	Port   uint16 `json:"port"`
+}
+
+func (d DNSSplitRule) String() string {
+	return d.Domain + " -> " + net.JoinHostPort(d.IP.String(), strconv.Itoa(int(d.Port)))
+}
+
+// OutboundRule returns the firewall outbound rule
+// to reach the DNS server outside the VPN.
+func (d DNSSplitRule) OutboundRule() OutboundRule {
+	bits := 8 * net.IPv6len
+	ip := d.IP
+	if ipv4 := ip.To4(); ipv4 != nil {
+		ip, bits = ipv4, 8*net.IPv4len
+	}
+	return OutboundRule{
+		Subnet: net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)},
+		Port:   d.Port,
+	}
+}
+
+// DNSLocalRecord is a hostname answered with an IP
+// address by the DNS server, without querying upstream.
+type DNSLocalRecord struct {
+	Hostname string `json:"hostname"`
+	IP       net.IP `json:"ip"`
+}
+
+func (d DNSLocalRecord) String() string {
+	return d.Hostname + " -> " + d.IP.String()
+}
+
+// DNSQuery is a query logged by the DNS server.
+type DNSQuery struct {
+	Time time.Time `json:"time"`
+	// Client is the IP address of the client, which is anonymized
+	// or empty depending on the query log mode.
+	Client  string `json:"client,omitempty"`
+	Name    string `json:"name"`
+	Type    uint16 `json:"type"`
+	Outcome string `json:"outcome"`
+}
+
+func (d DNSQuery) String() string {
+	return d.Time.Format(time.RFC3339) + " " + d.Client + " " + d.Name +
+		" type " + strconv.Itoa(int(d.Type)) + " " + d.Outcome
+}
+
+// DNSStats are statistics of the queries answered by the DNS server.
+type DNSStats struct {
+	Queries      uint64 `json:"queries"`
+	CacheHits    uint64 `json:"cache_hits"`
+	CacheMisses  uint64 `json:"cache_misses"`
+	CacheEntries int    `json:"cache_entries"`
+	Blocked      uint64 `json:"blocked"`
+	Failed       uint64 `json:"failed"`
+	// Bogus is the number of responses failing DNSSEC validation.
+	Bogus uint64 `json:"bogus"`
+	// UpstreamLatency is the average duration of the queries
+	// to the upstream servers, and is empty if there was none.
+	UpstreamLatency string `json:"upstream_latency,omitempty"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"net"
+	"strconv"
+)
+
+// OutboundRule is a destination subnet and port allowed through
+// the firewall outside the VPN.
+type OutboundRule struct {
+	Subnet net.IPNet `json:"subnet"`
+	Port   uint16    `json:"port"`
+	// Protocol is tcp or udp, and is empty for both.
+	Protocol string `json:"protocol"`
+}
+
+func (o OutboundRule) String() string {
+	s := o.Subnet.String() + ":" + strconv.Itoa(int(o.Port))
+	if o.Protocol != "" {
+		s += "/" + o.Protocol
+	}
+	return s
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// HealthCheck is a check run by the healthcheck.
+type HealthCheck struct {
+	// Type is the type of check, such as tcp or dns.
+	Type string `json:"type"`
+	// Target is the address, URL or hostname to check.
+	Target string `json:"target"`
+	// Timeout is the maximum duration of the check.
+	Timeout time.Duration `json:"timeout"`
+	// Status is the HTTP status code expected for http checks.
+	Status int `json:"status,omitempty"`
+}
+
+func (h HealthCheck) String() string {
+	return h.Type + " " + h.Target
+}
+
+// HealthProbe is the result of a run of the health checks.
+type HealthProbe struct {
+	Time time.Time `json:"time"`
+	// Success is true if the health checks succeeded.
+	Success bool `json:"success"`
+	// Latency is the duration of the health checks.
+	Latency string `json:"latency"`
+	// Error is the error of the health checks if they failed.
+	Error string `json:"error,omitempty"`
+}
+
+// HealthStats are statistics of the health checks.
+type HealthStats struct {
+	Healthy  bool   `json:"healthy"`
+	Probes   uint64 `json:"probes"`
+	Failures uint64 `json:"failures"`
+	// AverageLatency is the average duration of the successful
+	// probes in the history, and is 0 if there is none.
+	AverageLatency time.Duration `json:"average_latency"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// HTTPProxyStats contains counters of the HTTP proxy accesses.
+type HTTPProxyStats struct {
+	Requests uint64 `json:"requests"`
+	// Denied is the number of requests not authorized or not allowed.
+	Denied uint64 `json:"denied"`
+	// Failed is the number of requests failing to reach their destination.
+	Failed uint64 `json:"failed"`
+	// BytesSent and BytesReceived are the bytes sent to and
+	// received from the destinations, excluding the HTTP headers.
+	BytesSent     uint64 `json:"bytes_sent"`
+	BytesReceived uint64 `json:"bytes_received"`
+}
@@ -0,0 +1,7 @@
+package models
+
+import "time"
+
+// IPLatencies maps the string representation of a server IP address
+// to its latency measured by the updater.
+type IPLatencies map[string]time.Duration
@@ -0,0 +1,20 @@
+package models
+
+import "fmt"
+
+// MultiHop contains the entry and exit countries of a multi-hop
+// (double VPN) server. It is nil for single hop servers.
+type MultiHop struct {
+	EntryCountry string `json:"entry_country,omitempty"`
+	ExitCountry  string `json:"exit_country,omitempty"`
+}
+
+// Enabled returns true if the server is a multi-hop server.
+func (m *MultiHop) Enabled() bool {
+	return m != nil && m.ExitCountry != ""
+}
+
+func (m MultiHop) String() string {
+	return fmt.Sprintf("models.MultiHop{EntryCountry: %q, ExitCountry: %q}",
+		m.EntryCountry, m.ExitCountry)
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"net"
+	"time"
 )
 
 type OpenVPNConnection struct {
@@ -15,3 +16,48 @@ func (o *OpenVPNConnection) Equal(other OpenVPNConnection) bool {
 	return o.IP.Equal(other.IP) && o.Port == other.Port && o.Protocol == other.Protocol &&
 		o.Hostname == other.Hostname
 }
+
+// OpenVPNConnectionState is the state of the OpenVPN process
+// with the reason of its last failure, if any.
+type OpenVPNConnectionState struct {
+	State   OpenVPNState   `json:"state"`
+	Failure OpenVPNFailure `json:"failure,omitempty"`
+}
+
+// TunnelStats are statistics of the VPN tunnel connections.
+type TunnelStats struct {
+	// UpSince is the time the tunnel came up, and is
+	// the zero time if the tunnel is not up.
+	UpSince time.Time
+	// Reconnects is the number of times the tunnel came
+	// up again after its first connection.
+	Reconnects uint64
+}
+
+// PortForwardStatus is the status of the port forwarded
+// through the VPN tunnel.
+type PortForwardStatus struct {
+	Provider string `json:"provider"`
+	// Port is the port forwarded, and is 0 if no port is forwarded.
+	Port uint16 `json:"port"`
+	// Expiration is the time the port forwarded expires, and is
+	// the zero time if it does not expire or no port is forwarded.
+	Expiration time.Time `json:"expires_at"`
+	// Ports are all the ports forwarded, the first one being Port.
+	Ports []ForwardedPort `json:"ports"`
+}
+
+// ForwardedPort is a port forwarded through the VPN tunnel.
+type ForwardedPort struct {
+	Port uint16 `json:"port"`
+	// Expiration is the time the port forwarded expires,
+	// and is the zero time if it does not expire.
+	Expiration time.Time `json:"expires_at"`
+}
+
+// PortRedirection redirects the traffic to a port forwarded
+// through the VPN tunnel to a local listening port.
+type PortRedirection struct {
+	Port       uint16 `json:"port"`
+	TargetPort uint16 `json:"target_port"`
+}
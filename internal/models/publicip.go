@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// PublicIPInfo contains information on a public IP address.
+type PublicIPInfo struct {
+	Country string `json:"country"`
+	Region  string `json:"region"`
+	City    string `json:"city"`
+	// ASN is the autonomous system number of the
+	// IP address, and is 0 if it is unknown.
+	ASN          uint32 `json:"asn"`
+	Organization string `json:"organization"`
+}
+
+// PublicIPRecord is a public IP address found at a given time.
+type PublicIPRecord struct {
+	// Time is the time the public IP address was first found.
+	Time     time.Time `json:"time"`
+	PublicIP string    `json:"public_ip"`
+	PublicIPInfo
+}
+
+// PublicIPComparison compares the public IP address before connecting
+// to the VPN with the public IP address found through the VPN.
+type PublicIPComparison struct {
+	PreVPN PublicIPRecord `json:"pre_vpn"`
+	VPN    PublicIPRecord `json:"vpn"`
+	// IPChanged is true if the public IP address differs
+	// from the one before connecting to the VPN.
+	IPChanged bool `json:"ip_changed"`
+	// ASNChanged and CountryChanged are true if the ASN and the
+	// country differ, and are false if either of them is unknown.
+	ASNChanged     bool `json:"asn_changed"`
+	CountryChanged bool `json:"country_changed"`
+}
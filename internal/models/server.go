@@ -52,24 +52,44 @@ type MullvadServer struct {
 	City    string   `json:"city"`
 	ISP     string   `json:"isp"`
 	Owned   bool     `json:"owned"`
+	// MultiHopPorts are the ports to connect to on an entry server
+	// to exit through one of the servers of this group.
+	MultiHopPorts []uint16 `json:"multihop_ports,omitempty"`
 }
 
 func (s *MullvadServer) String() string {
-	return fmt.Sprintf("{Country: %q, City: %q, ISP: %q, Owned: %t, IPs: %s, IPsV6: %s}",
+	str := fmt.Sprintf("{Country: %q, City: %q, ISP: %q, Owned: %t, IPs: %s, IPsV6: %s",
 		s.Country, s.City, s.ISP, s.Owned, goStringifyIPs(s.IPs), goStringifyIPs(s.IPsV6))
+	if len(s.MultiHopPorts) > 0 {
+		str += ", MultiHopPorts: " + goStringifyPorts(s.MultiHopPorts)
+	}
+	return str + "}"
 }
 
 type NordvpnServer struct { //nolint:maligned
-	Region string `json:"region"`
-	Number uint16 `json:"number"`
-	IP     net.IP `json:"ip"`
-	TCP    bool   `json:"tcp"`
-	UDP    bool   `json:"udp"`
+	Region   string    `json:"region"`
+	Number   uint16    `json:"number"`
+	IP       net.IP    `json:"ip"`
+	TCP      bool      `json:"tcp"`
+	UDP      bool      `json:"udp"`
+	WgPubKey string    `json:"wgpubkey,omitempty"`
+	MultiHop *MultiHop `json:"multihop,omitempty"`
+	Features []string  `json:"features,omitempty"`
 }
 
 func (s *NordvpnServer) String() string {
-	return fmt.Sprintf("{Region: %q, Number: %d, TCP: %t, UDP: %t, IP: %s}",
+	str := fmt.Sprintf("{Region: %q, Number: %d, TCP: %t, UDP: %t, IP: %s",
 		s.Region, s.Number, s.TCP, s.UDP, goStringifyIP(s.IP))
+	if s.WgPubKey != "" {
+		str += fmt.Sprintf(", WgPubKey: %q", s.WgPubKey)
+	}
+	if s.MultiHop != nil {
+		str += ", MultiHop: &" + s.MultiHop.String()
+	}
+	if len(s.Features) > 0 {
+		str += fmt.Sprintf(", Features: %#v", s.Features)
+	}
+	return str + "}"
 }
 
 type PrivadoServer struct {
@@ -121,12 +141,17 @@ func (s *PurevpnServer) String() string {
 }
 
 type SurfsharkServer struct {
-	Region string   `json:"region"`
-	IPs    []net.IP `json:"ips"`
+	Region   string    `json:"region"`
+	IPs      []net.IP  `json:"ips"`
+	MultiHop *MultiHop `json:"multihop,omitempty"`
 }
 
 func (s *SurfsharkServer) String() string {
-	return fmt.Sprintf("{Region: %q, IPs: %s}", s.Region, goStringifyIPs(s.IPs))
+	if s.MultiHop == nil {
+		return fmt.Sprintf("{Region: %q, IPs: %s}", s.Region, goStringifyIPs(s.IPs))
+	}
+	return fmt.Sprintf("{Region: %q, IPs: %s, MultiHop: &%s}",
+		s.Region, goStringifyIPs(s.IPs), s.MultiHop.String())
 }
 
 type TorguardServer struct {
@@ -151,15 +176,20 @@ func (s *VyprvpnServer) String() string {
 }
 
 type WindscribeServer struct {
-	Region   string `json:"region"`
-	City     string `json:"city"`
-	Hostname string `json:"hostname"`
-	IP       net.IP `json:"ip"`
+	Region   string   `json:"region"`
+	City     string   `json:"city"`
+	Hostname string   `json:"hostname"`
+	IP       net.IP   `json:"ip"`
+	Features []string `json:"features,omitempty"`
 }
 
 func (s *WindscribeServer) String() string {
-	return fmt.Sprintf("{Region: %q, City: %q, Hostname: %q, IP: %s}",
+	str := fmt.Sprintf("{Region: %q, City: %q, Hostname: %q, IP: %s",
 		s.Region, s.City, s.Hostname, goStringifyIP(s.IP))
+	if len(s.Features) > 0 {
+		str += fmt.Sprintf(", Features: %#v", s.Features)
+	}
+	return str + "}"
 }
 
 func goStringifyIP(ip net.IP) string {
@@ -207,3 +237,11 @@ func goStringifyIPs(ips []net.IP) string {
 	}
 	return "[]net.IP{" + strings.Join(ipStrings, ", ") + "}"
 }
+
+func goStringifyPorts(ports []uint16) string {
+	portStrings := make([]string, len(ports))
+	for i, port := range ports {
+		portStrings[i] = fmt.Sprintf("%d", port)
+	}
+	return "[]uint16{" + strings.Join(portStrings, ", ") + "}"
+}
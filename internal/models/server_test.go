@@ -25,6 +25,17 @@ func Test_MullvadServer_String(t *testing.T) {
 			//nolint:lll
 			s: `{Country: "That Country", City: "That City", ISP: "not spying on you", Owned: true, IPs: []net.IP{{1, 1, 1, 1}}, IPsV6: []net.IP{{0x20, 0x1, 0xd, 0xb8, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x2, 0x0, 0x1}}}`,
 		},
+		"multi-hop ports": {
+			server: MullvadServer{
+				IPs:           []net.IP{{1, 1, 1, 1}},
+				Country:       "Sweden",
+				City:          "Gothenburg",
+				ISP:           "31173",
+				Owned:         true,
+				MultiHopPorts: []uint16{3001, 3010},
+			},
+			s: `{Country: "Sweden", City: "Gothenburg", ISP: "31173", Owned: true, IPs: []net.IP{{1, 1, 1, 1}}, IPsV6: []net.IP{}, MultiHopPorts: []uint16{3001, 3010}}`,
+		},
 	}
 	for name, testCase := range testCases {
 		testCase := testCase
@@ -15,6 +15,7 @@ type AllServers struct {
 	Torguard   TorguardServers   `json:"torguard"`
 	Vyprvpn    VyprvpnServers    `json:"vyprvpn"`
 	Windscribe WindscribeServers `json:"windscribe"`
+	Latencies  IPLatencies       `json:"latencies,omitempty"`
 }
 
 func (a *AllServers) Count() int {
@@ -98,3 +99,14 @@ type WindscribeServers struct {
 	Timestamp int64              `json:"timestamp"`
 	Servers   []WindscribeServer `json:"servers"`
 }
+
+// ServersUpdate is the result of the servers update of a VPN provider.
+type ServersUpdate struct {
+	Provider string `json:"provider"`
+	// Previous and Servers are the number of servers
+	// before and after the update.
+	Previous int `json:"previous"`
+	Servers  int `json:"servers"`
+	// Error is the update error, and is empty if the update succeeded.
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,190 @@
+// Package natpmp implements a NAT-PMP client as defined in RFC 6886,
+// to map ports on a gateway such as the gateway of a VPN tunnel.
+package natpmp
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+const (
+	// gatewayPort is the UDP port the gateway listens on.
+	gatewayPort = 5351
+	// initialRetransmission is the delay before retransmitting a
+	// request, doubled after each retransmission.
+	initialRetransmission = 250 * time.Millisecond
+	// maxAttempts is the maximum number of requests sent.
+	maxAttempts = 9
+)
+
+const (
+	opExternalAddress = 0
+	opMapUDP          = 1
+	opMapTCP          = 2
+	responseOpOffset  = 128
+)
+
+var (
+	ErrProtocolUnknown  = errors.New("protocol is unknown")
+	ErrResponseInvalid  = errors.New("response is invalid")
+	ErrResultCode       = errors.New("gateway returned an error result code")
+	ErrNoResponse       = errors.New("no response from gateway")
+	ErrPortMappingEmpty = errors.New("gateway mapped no port")
+)
+
+// Client is a NAT-PMP client.
+type Client struct {
+	gatewayPort           int
+	initialRetransmission time.Duration
+	maxAttempts           int
+}
+
+// New creates a new NAT-PMP client.
+func New() *Client {
+	return &Client{
+		gatewayPort:           gatewayPort,
+		initialRetransmission: initialRetransmission,
+		maxAttempts:           maxAttempts,
+	}
+}
+
+// ExternalAddress returns the external IPv4 address of the gateway.
+func (c *Client) ExternalAddress(ctx context.Context, gateway net.IP) (
+	externalIP net.IP, err error) {
+	request := []byte{0, opExternalAddress}
+	const responseSize = 12
+	response, err := c.exchange(ctx, gateway, request, responseSize)
+	if err != nil {
+		return nil, err
+	}
+	return net.IPv4(response[8], response[9], response[10], response[11]), nil
+}
+
+// AddPortMapping maps the internal port given for the protocol given,
+// which can be udp or tcp, suggesting the external port given which
+// can be 0 to let the gateway choose. The mapping must be renewed
+// before the lifetime assigned by the gateway elapses, and is removed
+// if the lifetime requested is 0.
+func (c *Client) AddPortMapping(ctx context.Context, gateway net.IP, protocol string,
+	internalPort, suggestedExternalPort uint16, lifetime time.Duration) (
+	externalPort uint16, assignedLifetime time.Duration, err error) {
+	var op byte
+	switch protocol {
+	case "udp":
+		op = opMapUDP
+	case "tcp":
+		op = opMapTCP
+	default:
+		return 0, 0, fmt.Errorf("%w: %s", ErrProtocolUnknown, protocol)
+	}
+
+	const requestSize = 12
+	request := make([]byte, requestSize)
+	request[1] = op
+	binary.BigEndian.PutUint16(request[4:6], internalPort)
+	binary.BigEndian.PutUint16(request[6:8], suggestedExternalPort)
+	binary.BigEndian.PutUint32(request[8:12], uint32(lifetime/time.Second))
+
+	const responseSize = 16
+	response, err := c.exchange(ctx, gateway, request, responseSize)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	externalPort = binary.BigEndian.Uint16(response[10:12])
+	assignedLifetime = time.Duration(binary.BigEndian.Uint32(response[12:16])) * time.Second
+	if externalPort == 0 && lifetime > 0 {
+		return 0, 0, ErrPortMappingEmpty
+	}
+	return externalPort, assignedLifetime, nil
+}
+
+// exchange sends the request to the gateway and returns its response,
+// retransmitting the request with an exponential backoff until a
+// response is received. It checks the response header and size.
+func (c *Client) exchange(ctx context.Context, gateway net.IP,
+	request []byte, responseSize int) (response []byte, err error) {
+	address := net.JoinHostPort(gateway.String(), strconv.Itoa(c.gatewayPort))
+	dialer := net.Dialer{}
+	connection, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Close()
+
+	buffer := make([]byte, responseSize)
+	retransmission := c.initialRetransmission
+	for attempt := 0; attempt < c.maxAttempts; attempt++ {
+		if _, err := connection.Write(request); err != nil {
+			return nil, err
+		}
+
+		deadline := time.Now().Add(retransmission)
+		if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+			deadline = ctxDeadline
+		}
+		if err := connection.SetReadDeadline(deadline); err != nil {
+			return nil, err
+		}
+
+		n, err := connection.Read(buffer)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			retransmission *= 2
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		return checkResponse(buffer[:n], request[1], responseSize)
+	}
+
+	return nil, fmt.Errorf("%w: after %d attempts", ErrNoResponse, c.maxAttempts)
+}
+
+func checkResponse(response []byte, op byte, expectedSize int) (
+	checked []byte, err error) {
+	const headerSize = 4
+	switch {
+	case len(response) < headerSize:
+		return nil, fmt.Errorf("%w: %d bytes", ErrResponseInvalid, len(response))
+	case response[0] != 0:
+		return nil, fmt.Errorf("%w: version %d", ErrResponseInvalid, response[0])
+	case response[1] != responseOpOffset+op:
+		return nil, fmt.Errorf("%w: operation code %d", ErrResponseInvalid, response[1])
+	}
+
+	if resultCode := binary.BigEndian.Uint16(response[2:4]); resultCode != 0 {
+		return nil, fmt.Errorf("%w: %s", ErrResultCode, resultCodeString(resultCode))
+	}
+
+	if len(response) != expectedSize {
+		return nil, fmt.Errorf("%w: %d bytes instead of %d", ErrResponseInvalid, len(response), expectedSize)
+	}
+	return response, nil
+}
+
+func resultCodeString(resultCode uint16) string {
+	switch resultCode {
+	case 1:
+		return "unsupported version"
+	case 2: //nolint:gomnd
+		return "not authorized"
+	case 3: //nolint:gomnd
+		return "network failure"
+	case 4: //nolint:gomnd
+		return "out of resources"
+	case 5: //nolint:gomnd
+		return "unsupported operation code"
+	default:
+		return "result code " + strconv.Itoa(int(resultCode))
+	}
+}
@@ -0,0 +1,123 @@
+package natpmp
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runGateway runs a fake NAT-PMP gateway replying with the response
+// function given, and returns a client configured to use it.
+func runGateway(t *testing.T, respond func(request []byte) (response []byte)) *Client {
+	t.Helper()
+	connection, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = connection.Close() })
+
+	go func() {
+		buffer := make([]byte, 1024) //nolint:gomnd
+		for {
+			n, address, err := connection.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			response := respond(buffer[:n])
+			if response != nil {
+				_, _ = connection.WriteTo(response, address)
+			}
+		}
+	}()
+
+	return &Client{
+		gatewayPort:           connection.LocalAddr().(*net.UDPAddr).Port,
+		initialRetransmission: 10 * time.Millisecond,
+		maxAttempts:           3,
+	}
+}
+
+func Test_Client_ExternalAddress(t *testing.T) {
+	t.Parallel()
+
+	client := runGateway(t, func(request []byte) (response []byte) {
+		return []byte{0, 128, 0, 0, 0, 0, 0, 1, 1, 2, 3, 4}
+	})
+
+	ip, err := client.ExternalAddress(context.Background(), net.IPv4(127, 0, 0, 1))
+	require.NoError(t, err)
+	assert.True(t, net.IPv4(1, 2, 3, 4).Equal(ip))
+}
+
+func Test_Client_AddPortMapping(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		protocol     string
+		resultCode   uint16
+		dropRequests int
+		port         uint16
+		lifetime     time.Duration
+		errMessage   string
+	}{
+		"udp": {
+			protocol: "udp",
+			port:     45678,
+			lifetime: time.Minute,
+		},
+		"tcp after retransmission": {
+			protocol:     "tcp",
+			dropRequests: 1,
+			port:         45678,
+			lifetime:     time.Minute,
+		},
+		"not authorized": {
+			protocol:   "udp",
+			resultCode: 2,
+			errMessage: "gateway returned an error result code: not authorized",
+		},
+		"no response": {
+			protocol:     "udp",
+			dropRequests: 3,
+			errMessage:   "no response from gateway: after 3 attempts",
+		},
+		"unknown protocol": {
+			protocol:   "sctp",
+			errMessage: "protocol is unknown: sctp",
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			requests := 0
+			client := runGateway(t, func(request []byte) (response []byte) {
+				requests++
+				if requests <= testCase.dropRequests {
+					return nil
+				}
+				response = make([]byte, 16)
+				response[1] = 128 + request[1]
+				binary.BigEndian.PutUint16(response[2:4], testCase.resultCode)
+				copy(response[8:10], request[4:6])
+				binary.BigEndian.PutUint16(response[10:12], 45678)
+				copy(response[12:16], request[8:12])
+				return response
+			})
+
+			port, lifetime, err := client.AddPortMapping(context.Background(),
+				net.IPv4(127, 0, 0, 1), testCase.protocol, 1, 0, time.Minute)
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.port, port)
+			assert.Equal(t, testCase.lifetime, lifetime)
+		})
+	}
+}
@@ -10,14 +10,24 @@ import (
 
 // WriteAuthFile writes the OpenVPN auth file to disk with the right permissions.
 func (c *configurator) WriteAuthFile(user, password string, puid, pgid int) error {
-	file, err := c.os.OpenFile(constants.OpenVPNAuthConf, os.O_RDONLY, 0)
+	return c.writeAuthFile(constants.OpenVPNAuthConf, user, password, puid, pgid)
+}
+
+// WriteProxyAuthFile writes the OpenVPN upstream proxy auth file
+// to disk with the right permissions.
+func (c *configurator) WriteProxyAuthFile(user, password string, puid, pgid int) error {
+	return c.writeAuthFile(constants.OpenVPNProxyAuthConf, user, password, puid, pgid)
+}
+
+func (c *configurator) writeAuthFile(path, user, password string, puid, pgid int) error {
+	file, err := c.os.OpenFile(path, os.O_RDONLY, 0)
 
 	if err != nil && !os.IsNotExist(err) {
 		return err
 	}
 
 	if os.IsNotExist(err) {
-		file, err = c.os.OpenFile(constants.OpenVPNAuthConf, os.O_WRONLY|os.O_CREATE, 0400)
+		file, err = c.os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0400)
 		if err != nil {
 			return err
 		}
@@ -48,8 +58,8 @@ func (c *configurator) WriteAuthFile(user, password string, puid, pgid int) erro
 		return nil
 	}
 
-	c.logger.Info("username and password changed in %s", constants.OpenVPNAuthConf)
-	file, err = c.os.OpenFile(constants.OpenVPNAuthConf, os.O_TRUNC|os.O_WRONLY, 0400)
+	c.logger.Info("username and password changed in %s", path)
+	file, err = c.os.OpenFile(path, os.O_TRUNC|os.O_WRONLY, 0400)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,30 @@
+package openvpn
+
+import (
+	"strconv"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+)
+
+// setBinding modifies the OpenVPN configuration lines to bind to the local
+// address and port given, and to mark the encrypted packets.
+func setBinding(lines []string, binding configuration.Binding) (modified []string) {
+	modified = lines
+	if binding.LocalPort > 0 || binding.Address != nil {
+		modified = removeDirectives(modified, "nobind", "bind", "lport", "local")
+		modified = append(modified, "bind")
+		if binding.LocalPort > 0 {
+			modified = append(modified, "lport "+strconv.Itoa(int(binding.LocalPort)))
+		}
+		if binding.Address != nil {
+			modified = append(modified, "local "+binding.Address.String())
+		}
+	}
+
+	if binding.Fwmark > 0 {
+		modified = removeDirectives(modified, "mark")
+		modified = append(modified, "mark "+strconv.FormatUint(uint64(binding.Fwmark), 10))
+	}
+
+	return modified
+}
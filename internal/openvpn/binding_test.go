@@ -0,0 +1,48 @@
+package openvpn
+
+import (
+	"net"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_setBinding(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		lines    []string
+		binding  configuration.Binding
+		modified []string
+	}{
+		"no binding": {
+			lines:    []string{"client", "nobind"},
+			modified: []string{"client", "nobind"},
+		},
+		"local port and address": {
+			lines: []string{"client", "nobind", "lport 1234"},
+			binding: configuration.Binding{
+				LocalPort: 1195,
+				Address:   net.IPv4(192, 168, 1, 2),
+			},
+			modified: []string{"client", "bind", "lport 1195", "local 192.168.1.2"},
+		},
+		"fwmark only": {
+			lines:    []string{"client", "nobind", "mark 1"},
+			binding:  configuration.Binding{Fwmark: 51820},
+			modified: []string{"client", "nobind", "mark 51820"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			modified := setBinding(testCase.lines, testCase.binding)
+
+			assert.Equal(t, testCase.modified, modified)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package openvpn
+
+import (
+	"net"
+	"time"
+)
+
+// blacklist keeps track of recent connection failures for each server IP
+// address, and blacklists IP addresses failing too often for a cooldown period.
+type blacklist struct {
+	timeNow  func() time.Time
+	failures map[string][]time.Time
+	until    map[string]time.Time
+}
+
+func newBlacklist(timeNow func() time.Time) *blacklist {
+	return &blacklist{
+		timeNow:  timeNow,
+		failures: make(map[string][]time.Time),
+		until:    make(map[string]time.Time),
+	}
+}
+
+// addFailure records a connection failure for the IP address, and blacklists
+// it for the cooldown duration if it failed maxFailures times within the
+// cooldown duration. It returns true if the IP address got blacklisted.
+func (b *blacklist) addFailure(ip net.IP, maxFailures int,
+	cooldown time.Duration) (blacklisted bool) {
+	if maxFailures == 0 {
+		return false
+	}
+
+	now := b.timeNow()
+	key := ip.String()
+	var recentFailures []time.Time
+	for _, failure := range b.failures[key] {
+		if now.Sub(failure) < cooldown {
+			recentFailures = append(recentFailures, failure)
+		}
+	}
+	recentFailures = append(recentFailures, now)
+
+	if len(recentFailures) < maxFailures {
+		b.failures[key] = recentFailures
+		return false
+	}
+
+	delete(b.failures, key)
+	b.until[key] = now.Add(cooldown)
+	return true
+}
+
+// succeed clears the recent connection failures of the IP address.
+func (b *blacklist) succeed(ip net.IP) {
+	delete(b.failures, ip.String())
+}
+
+// ips returns the IP addresses currently blacklisted.
+func (b *blacklist) ips() (ips []net.IP) {
+	now := b.timeNow()
+	for key, until := range b.until {
+		if !now.Before(until) {
+			delete(b.until, key)
+			continue
+		}
+		ips = append(ips, net.ParseIP(key))
+	}
+	return ips
+}
@@ -0,0 +1,45 @@
+package openvpn
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_blacklist(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	b := newBlacklist(func() time.Time { return now })
+	ip := net.IPv4(1, 2, 3, 4)
+	const maxFailures, cooldown = 2, time.Minute
+
+	blacklisted := b.addFailure(ip, maxFailures, cooldown)
+	assert.False(t, blacklisted)
+
+	// First failure is no longer recent
+	now = now.Add(cooldown)
+	blacklisted = b.addFailure(ip, maxFailures, cooldown)
+	assert.False(t, blacklisted)
+	assert.Empty(t, b.ips())
+
+	now = now.Add(time.Second)
+	blacklisted = b.addFailure(ip, maxFailures, cooldown)
+	assert.True(t, blacklisted)
+	assert.Equal(t, []net.IP{ip}, b.ips())
+
+	now = now.Add(cooldown)
+	assert.Empty(t, b.ips())
+
+	blacklisted = b.addFailure(ip, 0, cooldown)
+	assert.False(t, blacklisted)
+
+	// A successful connection clears the recent failures
+	blacklisted = b.addFailure(ip, maxFailures, cooldown)
+	assert.False(t, blacklisted)
+	b.succeed(ip)
+	blacklisted = b.addFailure(ip, maxFailures, cooldown)
+	assert.False(t, blacklisted)
+}
@@ -0,0 +1,81 @@
+package openvpn
+
+import (
+	"net"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider"
+)
+
+// nextCandidate returns the first connection following the failed connection
+// in the ordered candidate connections given, skipping the excluded IP
+// addresses and wrapping around the candidates. The search starts at the
+// first candidate if the failed connection is not a candidate, and ok is
+// false if all the candidates are excluded.
+func nextCandidate(candidates []models.OpenVPNConnection, failed models.OpenVPNConnection,
+	excludedIPs []net.IP) (next models.OpenVPNConnection, ok bool) {
+	start := 0
+	for i, candidate := range candidates {
+		if candidate.IP.Equal(failed.IP) {
+			start = i + 1
+			break
+		}
+	}
+
+	for i := 0; i < len(candidates); i++ {
+		candidate := candidates[(start+i)%len(candidates)]
+		if !ipIsIn(candidate.IP, excludedIPs) && !candidate.IP.Equal(failed.IP) {
+			return candidate, true
+		}
+	}
+	return next, false
+}
+
+func ipIsIn(ip net.IP, ips []net.IP) bool {
+	for _, other := range ips {
+		if ip.Equal(other) {
+			return true
+		}
+	}
+	return false
+}
+
+// pickConnection returns the next candidate connection if the previous
+// server failed too many times, and the connection picked by the provider
+// otherwise. The candidates are the connections matching the server
+// selection, in the order of the servers data.
+func (l *looper) pickConnection(providerConf provider.Provider,
+	selection configuration.ServerSelection) (connection models.OpenVPNConnection, err error) {
+	if l.failedServer == nil {
+		return providerConf.GetOpenVPNConnection(selection)
+	}
+	failed := *l.failedServer
+	l.failedServer = nil
+
+	candidates, err := providerConf.GetOpenVPNConnections(selection)
+	if err != nil {
+		return connection, err
+	}
+	if next, ok := nextCandidate(candidates, failed, selection.ExcludedIPs); ok {
+		return next, nil
+	}
+	l.logger.Warn("no other candidate server than %s", failed.IP)
+	return providerConf.GetOpenVPNConnection(selection)
+}
+
+// recordServerFailure records a failed connection attempt to the server of
+// the connection given, and returns true if the server failed too many times,
+// in which case the next connection attempt uses the next candidate server.
+func (l *looper) recordServerFailure(connection models.OpenVPNConnection,
+	settings configuration.OpenVPN) (switching bool) {
+	if len(settings.Config) > 0 ||
+		!l.blacklist.addFailure(connection.IP, settings.SwitchFailures, settings.FailureCooldown) {
+		return false
+	}
+	l.logger.Warn("server %s failed %d times, switching to the next candidate server for %s",
+		connection.IP, settings.SwitchFailures, settings.FailureCooldown)
+	l.tcpFallback.reset()
+	l.failedServer = &connection
+	return true
+}
@@ -0,0 +1,185 @@
+package openvpn
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_nextCandidate(t *testing.T) {
+	t.Parallel()
+
+	a := models.OpenVPNConnection{IP: net.IPv4(1, 1, 1, 1)}
+	b := models.OpenVPNConnection{IP: net.IPv4(2, 2, 2, 2)}
+	c := models.OpenVPNConnection{IP: net.IPv4(3, 3, 3, 3)}
+
+	testCases := map[string]struct {
+		candidates []models.OpenVPNConnection
+		failed     models.OpenVPNConnection
+		excluded   []net.IP
+		next       models.OpenVPNConnection
+		ok         bool
+	}{
+		"no candidate": {
+			failed: a,
+		},
+		"following candidate": {
+			candidates: []models.OpenVPNConnection{a, b, c},
+			failed:     a,
+			next:       b,
+			ok:         true,
+		},
+		"wraps around": {
+			candidates: []models.OpenVPNConnection{a, b, c},
+			failed:     c,
+			next:       a,
+			ok:         true,
+		},
+		"skips excluded candidates": {
+			candidates: []models.OpenVPNConnection{a, b, c},
+			failed:     a,
+			excluded:   []net.IP{b.IP},
+			next:       c,
+			ok:         true,
+		},
+		"failed connection not a candidate": {
+			candidates: []models.OpenVPNConnection{b, c},
+			failed:     a,
+			next:       b,
+			ok:         true,
+		},
+		"only the failed candidate": {
+			candidates: []models.OpenVPNConnection{a},
+			failed:     a,
+		},
+		"all other candidates excluded": {
+			candidates: []models.OpenVPNConnection{a, b, c},
+			failed:     b,
+			excluded:   []net.IP{a.IP, c.IP},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			next, ok := nextCandidate(testCase.candidates, testCase.failed, testCase.excluded)
+
+			assert.Equal(t, testCase.ok, ok)
+			assert.Equal(t, testCase.next, next)
+		})
+	}
+}
+
+type testProvider struct {
+	provider.Provider
+	connection  models.OpenVPNConnection
+	connections []models.OpenVPNConnection
+	err         error
+}
+
+func (p *testProvider) GetOpenVPNConnection(configuration.ServerSelection) (
+	models.OpenVPNConnection, error) {
+	return p.connection, nil
+}
+
+func (p *testProvider) GetOpenVPNConnections(configuration.ServerSelection) (
+	[]models.OpenVPNConnection, error) {
+	return p.connections, p.err
+}
+
+func Test_looper_pickConnection(t *testing.T) {
+	t.Parallel()
+
+	a := models.OpenVPNConnection{IP: net.IPv4(1, 1, 1, 1)}
+	b := models.OpenVPNConnection{IP: net.IPv4(2, 2, 2, 2)}
+	c := models.OpenVPNConnection{IP: net.IPv4(3, 3, 3, 3)}
+
+	testCases := map[string]struct {
+		failedServer *models.OpenVPNConnection
+		candidates   []models.OpenVPNConnection
+		excluded     []net.IP
+		warn         bool
+		connection   models.OpenVPNConnection
+	}{
+		"no failed server": {
+			candidates: []models.OpenVPNConnection{a, b},
+			connection: c,
+		},
+		"next candidate server": {
+			failedServer: &a,
+			candidates:   []models.OpenVPNConnection{a, b},
+			connection:   b,
+		},
+		"no other candidate server": {
+			failedServer: &a,
+			candidates:   []models.OpenVPNConnection{a, b},
+			excluded:     []net.IP{b.IP},
+			warn:         true,
+			connection:   c,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			logger := mock_logging.NewMockLogger(ctrl)
+			if testCase.warn {
+				logger.EXPECT().Warn("no other candidate server than %s", a.IP)
+			}
+			l := &looper{logger: logger, failedServer: testCase.failedServer}
+			providerConf := &testProvider{connection: c, connections: testCase.candidates}
+			selection := configuration.ServerSelection{ExcludedIPs: testCase.excluded}
+
+			connection, err := l.pickConnection(providerConf, selection)
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.connection, connection)
+			assert.Nil(t, l.failedServer)
+		})
+	}
+}
+
+func Test_looper_recordServerFailure(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	now := time.Unix(0, 0)
+	logger := mock_logging.NewMockLogger(ctrl)
+	l := &looper{
+		logger:    logger,
+		blacklist: newBlacklist(func() time.Time { return now }),
+	}
+	connection := models.OpenVPNConnection{IP: net.IPv4(1, 1, 1, 1)}
+	settings := configuration.OpenVPN{SwitchFailures: 2, FailureCooldown: time.Minute}
+
+	// custom configurations have a single server
+	customSettings := settings
+	customSettings.Config = "/gluetun/custom.conf"
+	assert.False(t, l.recordServerFailure(connection, customSettings))
+
+	disabledSettings := settings
+	disabledSettings.SwitchFailures = 0
+	assert.False(t, l.recordServerFailure(connection, disabledSettings))
+
+	assert.False(t, l.recordServerFailure(connection, settings))
+	assert.Nil(t, l.failedServer)
+
+	logger.EXPECT().Warn("server %s failed %d times, switching to the next candidate server for %s",
+		connection.IP, 2, time.Minute)
+	assert.True(t, l.recordServerFailure(connection, settings))
+	assert.Equal(t, &connection, l.failedServer)
+	assert.Equal(t, []net.IP{connection.IP}, l.blacklist.ips())
+}
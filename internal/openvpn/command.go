@@ -2,7 +2,10 @@ package openvpn
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os/exec"
+	"strconv"
 	"strings"
 
 	"github.com/qdm12/gluetun/internal/constants"
@@ -14,6 +17,29 @@ func (c *configurator) Start(ctx context.Context) (
 	return c.commander.Start(ctx, "openvpn", "--config", constants.OpenVPNConf)
 }
 
+func (c *configurator) StartStunnel(ctx context.Context) (
+	stdoutLines, stderrLines chan string, waitError chan error, err error) {
+	c.logger.Info("starting stunnel")
+	return c.commander.Start(ctx, "stunnel", constants.StunnelConf)
+}
+
+// PingDontFragment pings the target through the tunnel device with the
+// don't fragment bit set, and returns true if the target replied.
+func (c *configurator) PingDontFragment(ctx context.Context, device, target string,
+	payloadSize int) (ok bool, err error) {
+	_, err = c.commander.Run(ctx, "ping", "-M", "do", "-c", "2", "-W", "1",
+		"-I", device, "-s", strconv.Itoa(payloadSize), target)
+	exitErr := new(exec.ExitError)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.As(err, &exitErr): // no reply or packet too big
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
 func (c *configurator) Version(ctx context.Context) (string, error) {
 	output, err := c.commander.Run(ctx, "openvpn", "--version")
 	if err != nil && err.Error() != "exit status 1" {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -23,20 +24,27 @@ func (l *looper) processCustomConfig(settings configuration.OpenVPN) (
 		return nil, connection, fmt.Errorf("%w: %s", errProcessCustomConfig, err)
 	}
 
+	lines = resolveCustomConfigPaths(lines, filepath.Dir(settings.Config))
 	lines = modifyCustomConfig(lines, l.username, settings)
 
-	connection, err = extractConnectionFromLines(lines)
-	if err != nil {
-		return nil, connection, fmt.Errorf("%w: %s", errProcessCustomConfig, err)
+	connections := settings.CustomRemotes.Remotes
+	if len(connections) == 0 {
+		connections, err = extractConnectionsFromLines(lines)
+		if err != nil {
+			return nil, connection, fmt.Errorf("%w: %s", errProcessCustomConfig, err)
+		}
 	}
 
+	random := settings.CustomRemotes.Random || hasDirective(lines, "remote-random")
+	connection = connections[l.remotes.pick(len(connections), random)]
+
 	lines = setConnectionToLines(lines, connection)
 	return lines, connection, nil
 }
 
-func readCustomConfigLines(filepath string, openFile os.OpenFileFunc) (
+func readCustomConfigLines(path string, openFile os.OpenFileFunc) (
 	lines []string, err error) {
-	file, err := openFile(filepath, os.O_RDONLY, 0)
+	file, err := openFile(path, os.O_RDONLY, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -54,6 +62,36 @@ func readCustomConfigLines(filepath string, openFile os.OpenFileFunc) (
 	return strings.Split(string(b), "\n"), nil
 }
 
+// resolveCustomConfigPaths changes relative file paths of directives to be
+// relative to the custom configuration file directory, since OpenVPN runs
+// with a copy of the configuration file in another directory.
+func resolveCustomConfigPaths(lines []string, configDir string) (modified []string) {
+	modified = make([]string, len(lines))
+	for i, line := range lines {
+		modified[i] = line
+		fields := strings.Fields(line)
+		const minFields = 2
+		if len(fields) < minFields {
+			continue
+		}
+		switch fields[0] {
+		case "ca", "cert", "key", "tls-auth", "tls-crypt", "tls-crypt-v2",
+			"crl-verify", "pkcs12", "secret", "extra-certs":
+		default:
+			continue
+		}
+		path := fields[1]
+		if path == "[inline]" || filepath.IsAbs(path) {
+			continue
+		}
+		fields[1] = filepath.Join(configDir, path)
+		modified[i] = strings.Join(fields, " ")
+	}
+	return modified
+}
+
+// modifyCustomConfig removes directives conflicting with gluetun or running
+// external programs, and adds gluetun's own directives.
 func modifyCustomConfig(lines []string, username string,
 	settings configuration.OpenVPN) (modified []string) {
 	// Remove some lines
@@ -61,6 +99,21 @@ func modifyCustomConfig(lines []string, username string,
 		switch {
 		case strings.HasPrefix(line, "up "),
 			strings.HasPrefix(line, "down "),
+			strings.HasPrefix(line, "route-up "),
+			strings.HasPrefix(line, "route-pre-down "),
+			strings.HasPrefix(line, "ipchange "),
+			strings.HasPrefix(line, "learn-address "),
+			strings.HasPrefix(line, "tls-verify "),
+			strings.HasPrefix(line, "plugin "),
+			strings.HasPrefix(line, "script-security "),
+			strings.HasPrefix(line, "management "),
+			strings.HasPrefix(line, "daemon"),
+			strings.HasPrefix(line, "log "),
+			strings.HasPrefix(line, "log-append "),
+			strings.HasPrefix(line, "writepid "),
+			strings.HasPrefix(line, "dev "),
+			strings.HasPrefix(line, "user "),
+			strings.HasPrefix(line, "group "),
 			strings.HasPrefix(line, "verb "),
 			strings.HasPrefix(line, "auth-user-pass "),
 			len(settings.Cipher) > 0 && strings.HasPrefix(line, "cipher "),
@@ -73,6 +126,8 @@ func modifyCustomConfig(lines []string, username string,
 	}
 
 	// Add values
+	modified = append(modified, "dev tun")
+	modified = append(modified, "script-security 1") // no external program
 	modified = append(modified, "mute-replay-warnings")
 	modified = append(modified, "auth-nocache")
 	modified = append(modified, "pull-filter ignore \"auth-token\"") // prevent auth failed loop
@@ -102,70 +157,85 @@ func modifyCustomConfig(lines []string, username string,
 
 var errExtractConnection = errors.New("cannot extract connection")
 
-// extractConnectionFromLines always takes the first remote line only.
-func extractConnectionFromLines(lines []string) ( //nolint:gocognit
-	connection models.OpenVPNConnection, err error) {
+// extractConnectionsFromLines returns the connections of the remote lines,
+// using the protocol of the proto line if not set on a remote line.
+// Remote lines which cannot be used are skipped, and an error is
+// returned only if no remote line can be used.
+func extractConnectionsFromLines(lines []string) (
+	connections []models.OpenVPNConnection, err error) {
+	defaultProtocol := ""
 	for _, line := range lines {
-		switch {
-		case strings.HasPrefix(line, "proto "):
-			fields := strings.Fields(line)
-			if n := len(fields); n != 2 { //nolint:gomnd
-				return connection, fmt.Errorf(
-					"%w: proto line has %d fields instead of 2: %s",
-					errExtractConnection, n, line)
-			}
-			connection.Protocol = fields[1]
-
-		// only take the first remote line
-		case strings.HasPrefix(line, "remote ") && connection.IP == nil:
-			fields := strings.Fields(line)
-			n := len(fields)
-			//nolint:gomnd
-			if n < 2 {
-				return connection, fmt.Errorf(
-					"%w: remote line has not enough fields: %s",
-					errExtractConnection, line)
-			}
+		if !strings.HasPrefix(line, "proto ") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if n := len(fields); n != 2 { //nolint:gomnd
+			return nil, fmt.Errorf(
+				"%w: proto line has %d fields instead of 2: %s",
+				errExtractConnection, n, line)
+		}
+		defaultProtocol = fields[1]
+	}
 
-			host := fields[1]
-			if ip := net.ParseIP(host); ip != nil {
-				connection.IP = ip
-			} else {
-				return connection, fmt.Errorf(
-					"%w: for now, the remote line must contain an IP adddress: %s",
-					errExtractConnection, line)
-				// TODO resolve hostname once there is an option to allow it through
-				// the firewall before the VPN is up.
-			}
+	err = fmt.Errorf("%w: remote line not found", errExtractConnection)
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "remote ") {
+			continue
+		}
+		connection, remoteErr := extractConnectionFromRemote(line, defaultProtocol)
+		if remoteErr != nil {
+			err = remoteErr
+			continue
+		}
+		connections = append(connections, connection)
+	}
 
-			if n > 2 { //nolint:gomnd
-				port, err := strconv.Atoi(fields[2])
-				if err != nil {
-					return connection, fmt.Errorf(
-						"%w: remote line has an invalid port: %s",
-						errExtractConnection, line)
-				}
-				connection.Port = uint16(port)
-			}
+	if len(connections) == 0 {
+		return nil, err
+	}
 
-			if n > 3 { //nolint:gomnd
-				connection.Protocol = strings.ToLower(fields[3])
-			}
+	return connections, nil
+}
 
-			if n > 4 { //nolint:gomnd
-				return connection, fmt.Errorf(
-					"%w: remote line has too many fields: %s",
-					errExtractConnection, line)
-			}
-		}
+func extractConnectionFromRemote(line, defaultProtocol string) (
+	connection models.OpenVPNConnection, err error) {
+	fields := strings.Fields(line)
+	n := len(fields)
+	//nolint:gomnd
+	if n < 2 {
+		return connection, fmt.Errorf(
+			"%w: remote line has not enough fields: %s",
+			errExtractConnection, line)
+	} else if n > 4 { //nolint:gomnd
+		return connection, fmt.Errorf(
+			"%w: remote line has too many fields: %s",
+			errExtractConnection, line)
+	}
+
+	host := fields[1]
+	if ip := net.ParseIP(host); ip != nil {
+		connection.IP = ip
+	} else {
+		return connection, fmt.Errorf(
+			"%w: for now, the remote line must contain an IP adddress: %s",
+			errExtractConnection, line)
+		// TODO resolve hostname once there is an option to allow it through
+		// the firewall before the VPN is up.
+	}
 
-		if connection.Protocol != "" && connection.IP != nil {
-			break
+	if n > 2 { //nolint:gomnd
+		port, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return connection, fmt.Errorf(
+				"%w: remote line has an invalid port: %s",
+				errExtractConnection, line)
 		}
+		connection.Port = uint16(port)
 	}
 
-	if connection.IP == nil {
-		return connection, fmt.Errorf("%w: remote line not found", errExtractConnection)
+	connection.Protocol = defaultProtocol
+	if n > 3 { //nolint:gomnd
+		connection.Protocol = strings.ToLower(fields[3])
 	}
 
 	switch connection.Protocol {
@@ -189,16 +259,32 @@ func extractConnectionFromLines(lines []string) ( //nolint:gocognit
 	return connection, nil
 }
 
+// setConnectionToLines sets the connection to the proto line and to the
+// first remote line, and removes the other remote lines, since OpenVPN
+// must only connect to the remote allowed through the firewall.
 func setConnectionToLines(lines []string, connection models.OpenVPNConnection) (modified []string) {
-	for i, line := range lines {
+	modified = make([]string, 0, len(lines))
+	protoSet, remoteSet := false, false
+	for _, line := range lines {
 		switch {
 		case strings.HasPrefix(line, "proto "):
-			lines[i] = "proto " + connection.Protocol
-
+			line = "proto " + connection.Protocol
+			protoSet = true
 		case strings.HasPrefix(line, "remote "):
-			lines[i] = "remote " + connection.IP.String() + " " + strconv.Itoa(int(connection.Port))
+			if remoteSet {
+				continue
+			}
+			line = "remote " + connection.IP.String() + " " + strconv.Itoa(int(connection.Port))
+			remoteSet = true
+		case line == "remote-random":
+			continue
 		}
+		modified = append(modified, line)
 	}
 
-	return lines
+	if !protoSet {
+		modified = append(modified, "proto "+connection.Protocol)
+	}
+
+	return modified
 }
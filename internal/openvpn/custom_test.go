@@ -0,0 +1,122 @@
+package openvpn
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_resolveCustomConfigPaths(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		lines    []string
+		modified []string
+	}{
+		"empty": {
+			lines:    []string{},
+			modified: []string{},
+		},
+		"paths": {
+			lines: []string{
+				"remote 1.2.3.4 1194",
+				"ca ca.crt",
+				"tls-auth keys/ta.key 1",
+				"cert /etc/certs/client.crt",
+				"key [inline]",
+				"<key>",
+			},
+			modified: []string{
+				"remote 1.2.3.4 1194",
+				"ca /gluetun/ca.crt",
+				"tls-auth /gluetun/keys/ta.key 1",
+				"cert /etc/certs/client.crt",
+				"key [inline]",
+				"<key>",
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			modified := resolveCustomConfigPaths(testCase.lines, "/gluetun")
+			assert.Equal(t, testCase.modified, modified)
+		})
+	}
+}
+
+func Test_extractConnectionsFromLines(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		lines       []string
+		connections []models.OpenVPNConnection
+		err         error
+	}{
+		"no remote": {
+			lines: []string{"proto udp"},
+			err:   errors.New("cannot extract connection: remote line not found"),
+		},
+		"no usable remote": {
+			lines: []string{"remote vpn.example.com 1194"},
+			err: errors.New("cannot extract connection: for now, the remote line " +
+				"must contain an IP adddress: remote vpn.example.com 1194"),
+		},
+		"multiple remotes": {
+			lines: []string{
+				"proto tcp",
+				"remote 1.2.3.4",
+				"remote vpn.example.com 1194",
+				"remote 5.6.7.8 1194 udp",
+				"remote-random",
+			},
+			connections: []models.OpenVPNConnection{
+				{IP: net.IPv4(1, 2, 3, 4), Port: 443, Protocol: "tcp"},
+				{IP: net.IPv4(5, 6, 7, 8), Port: 1194, Protocol: "udp"},
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			connections, err := extractConnectionsFromLines(testCase.lines)
+			if testCase.err != nil {
+				assert.EqualError(t, err, testCase.err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.connections, connections)
+		})
+	}
+}
+
+func Test_setConnectionToLines(t *testing.T) {
+	t.Parallel()
+	lines := []string{
+		"client",
+		"proto tcp",
+		"remote 1.2.3.4 443",
+		"remote 5.6.7.8 1194 udp",
+		"remote-random",
+	}
+	connection := models.OpenVPNConnection{IP: net.IPv4(5, 6, 7, 8), Port: 1194, Protocol: "udp"}
+	expected := []string{
+		"client",
+		"proto udp",
+		"remote 5.6.7.8 1194",
+	}
+	modified := setConnectionToLines(lines, connection)
+	assert.Equal(t, expected, modified)
+
+	lines = []string{"remote 1.2.3.4 443 tcp"}
+	connection = models.OpenVPNConnection{IP: net.IPv4(1, 2, 3, 4), Port: 443, Protocol: "tcp"}
+	expected = []string{
+		"remote 1.2.3.4 443",
+		"proto tcp",
+	}
+	modified = setConnectionToLines(lines, connection)
+	assert.Equal(t, expected, modified)
+}
@@ -0,0 +1,127 @@
+package openvpn
+
+import (
+	"context"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider"
+)
+
+// failover keeps track of the primary connection health, and decides
+// when to switch to the backup connection and back to the primary one.
+type failover struct {
+	timeNow        func() time.Time
+	usingBackup    bool
+	unhealthySince time.Time
+	backupSince    time.Time
+}
+
+func newFailover(timeNow func() time.Time) *failover {
+	return &failover{timeNow: timeNow}
+}
+
+// settings returns the backup settings if the backup is in use,
+// and the primary settings otherwise.
+func (f *failover) settings(primary configuration.OpenVPN) (settings configuration.OpenVPN) {
+	if !f.usingBackup || primary.Backup == nil {
+		return primary
+	}
+	return *primary.Backup
+}
+
+// update records the health of the current connection, and returns true
+// if the connection should be switched to the backup or primary one.
+// Once the failback period elapsed, the connection is only switched back
+// to the primary one if primaryUp returns true, and primaryUp is called
+// again after another failback period otherwise.
+func (f *failover) update(healthy bool, settings configuration.Failover,
+	primaryUp func() bool) (switched bool) {
+	now := f.timeNow()
+
+	if f.usingBackup {
+		if now.Sub(f.backupSince) < settings.FailbackPeriod {
+			return false
+		}
+		if !primaryUp() {
+			f.backupSince = now
+			return false
+		}
+		f.usingBackup = false
+		f.unhealthySince = time.Time{}
+		return true
+	}
+
+	switch {
+	case healthy:
+		f.unhealthySince = time.Time{}
+		return false
+	case f.unhealthySince.IsZero():
+		f.unhealthySince = now
+		return false
+	case now.Sub(f.unhealthySince) < settings.Threshold:
+		return false
+	}
+
+	f.usingBackup = true
+	f.backupSince = now
+	f.unhealthySince = time.Time{}
+	return true
+}
+
+const failoverCheckPeriod = 10 * time.Second
+
+// newHealthTicker returns a ticker to check the connection health
+// periodically, or a stopped ticker if no backup connection is set.
+func newHealthTicker(primary configuration.OpenVPN) (ticker *time.Ticker) {
+	ticker = time.NewTicker(failoverCheckPeriod)
+	if primary.Backup == nil {
+		ticker.Stop()
+	}
+	return ticker
+}
+
+// updateFailover records the connection health and returns true if the
+// loop should reconnect using the backup or primary settings.
+func (l *looper) updateFailover(ctx context.Context, primary configuration.OpenVPN,
+	allServers models.AllServers, healthy bool) (switched bool) {
+	if primary.Backup == nil {
+		return false
+	}
+	primaryUp := func() bool { return l.primaryUp(ctx, primary, allServers) }
+	switched = l.failover.update(healthy, primary.Failover, primaryUp)
+	if !switched {
+		return false
+	}
+	if l.failover.usingBackup {
+		l.logger.Warn("connection unhealthy for %s, failing over to backup provider %s",
+			primary.Failover.Threshold, primary.Backup.Provider.Name)
+	} else {
+		l.logger.Info("failing back to primary provider %s", primary.Provider.Name)
+	}
+	return true
+}
+
+const primaryProbeTimeout = 5 * time.Second
+
+// primaryUp returns true if a server of the primary provider answers
+// through the backup tunnel. A custom configuration is not probed and
+// is always considered up.
+func (l *looper) primaryUp(ctx context.Context, primary configuration.OpenVPN,
+	allServers models.AllServers) (up bool) {
+	if len(primary.Config) > 0 {
+		return true
+	}
+	providerConf := provider.New(primary.Provider.Name, allServers, time.Now)
+	connection, err := providerConf.GetOpenVPNConnection(primary.Provider.ServerSelection)
+	if err != nil {
+		l.logger.Warn("cannot probe primary provider %s: %s", primary.Provider.Name, err)
+		return false
+	}
+	if err := l.probe(ctx, connection, primaryProbeTimeout); err != nil {
+		l.logger.Info("primary provider %s still unreachable: %s", primary.Provider.Name, err)
+		return false
+	}
+	return true
+}
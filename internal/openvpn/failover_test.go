@@ -0,0 +1,200 @@
+package openvpn
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_failover(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	f := newFailover(func() time.Time { return now })
+	settings := configuration.Failover{
+		Threshold:      time.Minute,
+		FailbackPeriod: time.Hour,
+	}
+	primary := configuration.OpenVPN{
+		User:   "primary",
+		Backup: &configuration.OpenVPN{User: "backup"},
+	}
+	primaryUpCalls := 0
+	primaryIsUp := false
+	primaryUp := func() bool {
+		primaryUpCalls++
+		return primaryIsUp
+	}
+
+	assert.False(t, f.update(false, settings, primaryUp))
+	now = now.Add(time.Second)
+	assert.False(t, f.update(true, settings, primaryUp)) // resets unhealthy duration
+	now = now.Add(time.Minute)
+	assert.False(t, f.update(false, settings, primaryUp))
+	assert.Equal(t, "primary", f.settings(primary).User)
+
+	now = now.Add(time.Minute)
+	assert.True(t, f.update(false, settings, primaryUp))
+	assert.Equal(t, "backup", f.settings(primary).User)
+
+	now = now.Add(time.Minute)
+	assert.False(t, f.update(true, settings, primaryUp))
+	assert.Zero(t, primaryUpCalls) // failback period not elapsed
+
+	// the primary is still down once the failback period elapsed
+	now = now.Add(time.Hour)
+	assert.False(t, f.update(true, settings, primaryUp))
+	assert.Equal(t, 1, primaryUpCalls)
+	assert.Equal(t, "backup", f.settings(primary).User)
+	now = now.Add(time.Minute)
+	primaryIsUp = true
+	assert.False(t, f.update(true, settings, primaryUp)) // waits for another period
+	assert.Equal(t, 1, primaryUpCalls)
+
+	now = now.Add(time.Hour)
+	assert.True(t, f.update(true, settings, primaryUp))
+	assert.Equal(t, 2, primaryUpCalls)
+	assert.Equal(t, "primary", f.settings(primary).User)
+}
+
+func Test_looper_updateFailover(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	allServers := models.AllServers{
+		Mullvad: models.MullvadServers{Servers: []models.MullvadServer{
+			{Country: "Sweden", IPs: []net.IP{{1, 1, 1, 1}}},
+		}},
+	}
+	primary := configuration.OpenVPN{
+		Failover: configuration.Failover{
+			Threshold:      time.Minute,
+			FailbackPeriod: time.Hour,
+		},
+		Backup: &configuration.OpenVPN{},
+	}
+	primary.Provider.Name = constants.Mullvad
+	primary.Provider.ServerSelection.Protocol = constants.UDP
+	primary.Backup.Provider.Name = constants.Surfshark
+	noMatchingServer := primary
+	noMatchingServer.Provider.ServerSelection.Countries = []string{"france"}
+	customConfig := primary
+	customConfig.Config = "/gluetun/custom.conf"
+
+	testCases := map[string]struct {
+		primary      configuration.OpenVPN
+		usingBackup  bool
+		healthy      bool
+		probeErr     error
+		probed       bool
+		prepareMocks func(logger *mock_logging.MockLogger)
+		switched     bool
+		backup       bool
+	}{
+		"no backup": {
+			primary: configuration.OpenVPN{},
+		},
+		"primary unhealthy": {
+			primary: primary,
+			prepareMocks: func(logger *mock_logging.MockLogger) {
+				logger.EXPECT().Warn("connection unhealthy for %s, failing over to backup provider %s",
+					time.Minute, constants.Surfshark)
+			},
+			switched: true,
+			backup:   true,
+		},
+		"primary healthy": {
+			primary: primary,
+			healthy: true,
+		},
+		"failback to reachable primary": {
+			primary:     primary,
+			usingBackup: true,
+			healthy:     true,
+			probed:      true,
+			prepareMocks: func(logger *mock_logging.MockLogger) {
+				logger.EXPECT().Info("failing back to primary provider %s", constants.Mullvad)
+			},
+			switched: true,
+		},
+		"primary unreachable": {
+			primary:     primary,
+			usingBackup: true,
+			healthy:     true,
+			probeErr:    errTest,
+			probed:      true,
+			prepareMocks: func(logger *mock_logging.MockLogger) {
+				logger.EXPECT().Info("primary provider %s still unreachable: %s",
+					constants.Mullvad, errTest)
+			},
+			backup: true,
+		},
+		"no primary server to probe": {
+			primary:     noMatchingServer,
+			usingBackup: true,
+			healthy:     true,
+			prepareMocks: func(logger *mock_logging.MockLogger) {
+				logger.EXPECT().Warn("cannot probe primary provider %s: %s",
+					constants.Mullvad, gomock.Any())
+			},
+			backup: true,
+		},
+		"custom configuration not probed": {
+			primary:     customConfig,
+			usingBackup: true,
+			healthy:     true,
+			prepareMocks: func(logger *mock_logging.MockLogger) {
+				logger.EXPECT().Info("failing back to primary provider %s", constants.Mullvad)
+			},
+			switched: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			now := time.Unix(10000, 0)
+			logger := mock_logging.NewMockLogger(ctrl)
+			if testCase.prepareMocks != nil {
+				testCase.prepareMocks(logger)
+			}
+			probed := false
+			l := &looper{
+				logger: logger,
+				failover: &failover{
+					timeNow:        func() time.Time { return now },
+					usingBackup:    testCase.usingBackup,
+					unhealthySince: now.Add(-2 * time.Minute),
+					backupSince:    now.Add(-2 * time.Hour),
+				},
+				probe: func(ctx context.Context, connection models.OpenVPNConnection,
+					timeout time.Duration) (err error) {
+					probed = true
+					assert.Equal(t, net.IP{1, 1, 1, 1}, connection.IP)
+					assert.Equal(t, primaryProbeTimeout, timeout)
+					return testCase.probeErr
+				},
+			}
+
+			switched := l.updateFailover(context.Background(), testCase.primary,
+				allServers, testCase.healthy)
+
+			assert.Equal(t, testCase.switched, switched)
+			assert.Equal(t, testCase.backup, l.failover.usingBackup)
+			assert.Equal(t, testCase.probed, probed)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package openvpn
+
+import (
+	"net"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+const udpFailuresBeforeTCPFallback = 3
+
+// tcpFallback tracks the consecutive UDP connection failures to retry
+// a server over TCP, for example on networks throttling UDP.
+type tcpFallback struct {
+	// udpFailures is the number of consecutive UDP connection failures.
+	udpFailures int
+	// ip is the IP address of the server to retry over TCP,
+	// or nil if not falling back on TCP.
+	ip net.IP
+}
+
+// recordFailure counts a connection failure and returns true if the
+// connection should be retried over TCP, which happens after several
+// consecutive failures over UDP with the same server.
+func (f *tcpFallback) recordFailure(connection models.OpenVPNConnection,
+	settings configuration.OpenVPN) (fallback bool) {
+	if !settings.TCPFallback || len(settings.Config) > 0 ||
+		connection.Protocol != constants.UDP || f.ip != nil {
+		return false
+	}
+
+	f.udpFailures++
+	if f.udpFailures < udpFailuresBeforeTCPFallback {
+		return false
+	}
+
+	f.udpFailures = 0
+	f.ip = connection.IP
+	return true
+}
+
+// connected resets the failures count once connected. A connection
+// over UDP also stops falling back on TCP.
+func (f *tcpFallback) connected(connection models.OpenVPNConnection) {
+	f.udpFailures = 0
+	if connection.Protocol == constants.UDP {
+		f.ip = nil
+	}
+}
+
+// reset stops falling back on TCP and resets the failures count,
+// which must be done when changing server.
+func (f *tcpFallback) reset() {
+	f.udpFailures = 0
+	f.ip = nil
+}
+
+// tcpFallbackSelection returns the server selection to connect over TCP
+// to the server with the IP address given, preferably on port 443.
+func tcpFallbackSelection(selection configuration.ServerSelection,
+	providerName string, ip net.IP) configuration.ServerSelection {
+	selection.Protocol = constants.TCP
+	selection.CustomPort = constants.ProviderCapabilities()[providerName].TCPFallbackPort
+	selection.PreferredIP = ip
+
+	excludedIPs := make([]net.IP, 0, len(selection.ExcludedIPs))
+	for _, excludedIP := range selection.ExcludedIPs {
+		if !excludedIP.Equal(ip) {
+			excludedIPs = append(excludedIPs, excludedIP)
+		}
+	}
+	selection.ExcludedIPs = excludedIPs
+
+	return selection
+}
+
+// recordUDPFailure records a connection failure and returns true if the
+// same server should be retried over TCP.
+func (l *looper) recordUDPFailure(connection models.OpenVPNConnection,
+	settings configuration.OpenVPN) (fallback bool) {
+	fallback = l.tcpFallback.recordFailure(connection, settings)
+	if fallback {
+		l.logger.Warn("UDP connection to %s failed %d times in a row, retrying over TCP",
+			connection.IP, udpFailuresBeforeTCPFallback)
+	}
+	return fallback
+}
@@ -0,0 +1,170 @@
+package openvpn
+
+import (
+	"net"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_tcpFallbackSelection(t *testing.T) {
+	t.Parallel()
+
+	ip := net.IPv4(1, 2, 3, 4)
+
+	testCases := map[string]struct {
+		selection    configuration.ServerSelection
+		providerName string
+		fallback     configuration.ServerSelection
+	}{
+		"provider accepting port 443": {
+			selection: configuration.ServerSelection{
+				Protocol:    constants.UDP,
+				CustomPort:  1197,
+				ExcludedIPs: []net.IP{net.IPv4(5, 6, 7, 8), ip},
+			},
+			providerName: constants.Mullvad,
+			fallback: configuration.ServerSelection{
+				Protocol:    constants.TCP,
+				CustomPort:  443,
+				PreferredIP: ip,
+				ExcludedIPs: []net.IP{net.IPv4(5, 6, 7, 8)},
+			},
+		},
+		"provider default TCP port": {
+			selection: configuration.ServerSelection{
+				Protocol: constants.UDP,
+			},
+			providerName: constants.Surfshark,
+			fallback: configuration.ServerSelection{
+				Protocol:    constants.TCP,
+				PreferredIP: ip,
+				ExcludedIPs: []net.IP{},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			fallback := tcpFallbackSelection(testCase.selection, testCase.providerName, ip)
+
+			assert.Equal(t, testCase.fallback, fallback)
+		})
+	}
+}
+
+func Test_tcpFallback(t *testing.T) {
+	t.Parallel()
+
+	ip := net.IPv4(1, 2, 3, 4)
+	udp := models.OpenVPNConnection{IP: ip, Protocol: constants.UDP}
+	tcp := models.OpenVPNConnection{IP: ip, Protocol: constants.TCP}
+	enabled := configuration.OpenVPN{TCPFallback: true}
+
+	type event struct {
+		failure    models.OpenVPNConnection
+		connected  models.OpenVPNConnection
+		reset      bool
+		fallback   bool
+		expectedIP net.IP
+	}
+
+	testCases := map[string]struct {
+		settings configuration.OpenVPN
+		events   []event
+	}{
+		"disabled": {
+			events: []event{
+				{failure: udp}, {failure: udp}, {failure: udp},
+			},
+		},
+		"custom configuration": {
+			settings: configuration.OpenVPN{TCPFallback: true, Config: "config.ovpn"},
+			events: []event{
+				{failure: udp}, {failure: udp}, {failure: udp},
+			},
+		},
+		"TCP failures ignored": {
+			settings: enabled,
+			events: []event{
+				{failure: tcp}, {failure: tcp}, {failure: tcp},
+			},
+		},
+		"fallback after consecutive UDP failures": {
+			settings: enabled,
+			events: []event{
+				{failure: udp},
+				{failure: udp},
+				{failure: udp, fallback: true, expectedIP: ip},
+				{failure: udp, expectedIP: ip},
+			},
+		},
+		"UDP success resets failures": {
+			settings: enabled,
+			events: []event{
+				{failure: udp},
+				{failure: udp},
+				{connected: udp},
+				{failure: udp},
+				{failure: udp},
+			},
+		},
+		"TCP success keeps falling back": {
+			settings: enabled,
+			events: []event{
+				{failure: udp},
+				{failure: udp},
+				{failure: udp, fallback: true, expectedIP: ip},
+				{connected: tcp, expectedIP: ip},
+			},
+		},
+		"UDP success stops falling back": {
+			settings: enabled,
+			events: []event{
+				{failure: udp},
+				{failure: udp},
+				{failure: udp, fallback: true, expectedIP: ip},
+				{connected: udp},
+			},
+		},
+		"server change stops falling back": {
+			settings: enabled,
+			events: []event{
+				{failure: udp},
+				{failure: udp},
+				{failure: udp, fallback: true, expectedIP: ip},
+				{reset: true},
+				{failure: udp},
+				{failure: udp},
+				{failure: udp, fallback: true, expectedIP: ip},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var fallback tcpFallback
+			for i, event := range testCase.events {
+				switch {
+				case event.reset:
+					fallback.reset()
+				case event.connected.IP != nil:
+					fallback.connected(event.connected)
+				default:
+					result := fallback.recordFailure(event.failure, testCase.settings)
+					assert.Equal(t, event.fallback, result, "event %d", i)
+				}
+				assert.Equal(t, event.expectedIP, fallback.ip, "event %d", i)
+			}
+		})
+	}
+}
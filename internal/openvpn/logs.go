@@ -6,10 +6,15 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/golibs/logging"
 )
 
-func (l *looper) collectLines(wg *sync.WaitGroup, stdout, stderr <-chan string) {
+// collectLines logs the OpenVPN lines and updates the OpenVPN connection
+// state from them until superseded is closed. Connection failures are sent
+// to the failures channel without blocking, so it should be buffered.
+func (l *looper) collectLines(wg *sync.WaitGroup, stdout, stderr <-chan string,
+	superseded <-chan struct{}, onConnected func(), failures chan<- models.OpenVPNFailure) {
 	defer wg.Done()
 	var line string
 	var ok, errLine bool
@@ -24,6 +29,11 @@ func (l *looper) collectLines(wg *sync.WaitGroup, stdout, stderr <-chan string)
 		if !ok {
 			return
 		}
+		select {
+		case <-superseded:
+		default:
+			l.updateConnectionState(line, onConnected, failures)
+		}
 		line, level := processLogLine(line)
 		if len(line) == 0 {
 			continue // filtered out
@@ -41,8 +51,26 @@ func (l *looper) collectLines(wg *sync.WaitGroup, stdout, stderr <-chan string)
 		case logging.LevelError:
 			l.logger.Error(line)
 		}
-		if strings.Contains(line, "Initialization Sequence Completed") {
-			l.tunnelReady <- struct{}{}
+	}
+}
+
+func (l *looper) updateConnectionState(line string, onConnected func(),
+	failures chan<- models.OpenVPNFailure) {
+	previous := l.GetConnectionState()
+	next := nextConnectionState(previous, line)
+	if next == previous {
+		return
+	}
+	l.state.setConnectionState(next)
+
+	switch {
+	case next.State == constants.OpenVPNUp:
+		onConnected()
+		l.tunnelReady <- struct{}{}
+	case next.State == constants.OpenVPNDown && next.Failure != "" && next.Failure != previous.Failure:
+		select {
+		case failures <- next.Failure:
+		default:
 		}
 	}
 }
@@ -2,6 +2,9 @@ package openvpn
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/rand"
 	"net"
 	"net/http"
 	"strings"
@@ -14,10 +17,13 @@ import (
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/provider"
 	"github.com/qdm12/gluetun/internal/routing"
+	"github.com/qdm12/golibs/command"
 	"github.com/qdm12/golibs/logging"
 	"github.com/qdm12/golibs/os"
 )
 
+//go:generate mockgen -destination=mock_$GOPACKAGE/$GOFILE . Looper
+
 type Looper interface {
 	Run(ctx context.Context, wg *sync.WaitGroup)
 	GetStatus() (status models.LoopStatus)
@@ -27,7 +33,18 @@ type Looper interface {
 	GetServers() (servers models.AllServers)
 	SetServers(servers models.AllServers)
 	GetPortForwarded() (port uint16)
+	GetPortForwardStatus() (status models.PortForwardStatus)
+	SubscribePortForward() (updates <-chan models.PortForwardStatus, unsubscribe func())
+	RenewPortForward() (err error)
+	GetConnectionState() (connectionState models.OpenVPNConnectionState)
+	GetTunnelStats() (stats models.TunnelStats)
 	PortForward(vpnGatewayIP net.IP)
+	SwitchServer() (outcome string)
+	SwitchProtocol() (outcome string, err error)
+	Pause() (outcome string, err error)
+	Resume() (outcome string, err error)
+	IsPaused() (paused bool)
+	SetServerSelection(providerName string, selection configuration.ServerSelection) (outcome string, err error)
 }
 
 type looper struct {
@@ -44,6 +61,7 @@ type looper struct {
 	logger, pfLogger logging.Logger
 	client           *http.Client
 	openFile         os.OpenFileFunc
+	commander        command.Commander
 	tunnelReady      chan<- struct{}
 	cancel           context.CancelFunc
 	// Internal channels and locks
@@ -52,8 +70,33 @@ type looper struct {
 	stop, stopped      chan struct{}
 	start              chan struct{}
 	portForwardSignals chan net.IP
+	portForwardRenew   chan chan error
+	switchServer       chan struct{}
 	crashed            bool
 	backoffTime        time.Duration
+	blacklist          *blacklist
+	failover           *failover
+	// recentServers are the IP addresses of the servers recently
+	// connected to, from the most recent to the oldest.
+	recentServers []net.IP
+	// failedServer is the server which failed too many times, from which
+	// the next connection advances to the next candidate server, and is
+	// nil otherwise.
+	failedServer *models.OpenVPNConnection
+	// probe checks a server of the primary provider is reachable
+	// before failing back to it.
+	probe func(ctx context.Context, connection models.OpenVPNConnection,
+		timeout time.Duration) (err error)
+	// tunedMTU is the MTU discovered for the tunedConnection, and is 0
+	// if the discovery failed.
+	tunedMTU        int
+	tunedConnection models.OpenVPNConnection
+	tcpFallback     tcpFallback
+	// remotes selects the remote of the custom configuration to use.
+	remotes remoteSelector
+	// staticServerIP is the last IP address resolved for the static
+	// server hostname, and is nil if not resolved yet.
+	staticServerIP net.IP
 }
 
 const defaultBackoffTime = 15 * time.Second
@@ -65,9 +108,10 @@ func NewLooper(settings configuration.OpenVPN,
 	tunnelReady chan<- struct{}, cancel context.CancelFunc) Looper {
 	return &looper{
 		state: state{
-			status:     constants.Stopped,
-			settings:   settings,
-			allServers: allServers,
+			status:          constants.Stopped,
+			settings:        settings,
+			allServers:      allServers,
+			connectionState: models.OpenVPNConnectionState{State: constants.OpenVPNDown},
 		},
 		username:           username,
 		puid:               puid,
@@ -79,6 +123,7 @@ func NewLooper(settings configuration.OpenVPN,
 		pfLogger:           logger.NewChild(logging.SetPrefix("port forwarding: ")),
 		client:             client,
 		openFile:           openFile,
+		commander:          command.NewCommander(),
 		tunnelReady:        tunnelReady,
 		cancel:             cancel,
 		start:              make(chan struct{}),
@@ -86,7 +131,15 @@ func NewLooper(settings configuration.OpenVPN,
 		stop:               make(chan struct{}),
 		stopped:            make(chan struct{}),
 		portForwardSignals: make(chan net.IP),
+		portForwardRenew:   make(chan chan error),
+		switchServer:       make(chan struct{}, 1),
 		backoffTime:        defaultBackoffTime,
+		blacklist:          newBlacklist(time.Now),
+		failover:           newFailover(time.Now),
+		probe:              provider.ProbeConnection,
+		remotes: remoteSelector{
+			randIntn: rand.New(rand.NewSource(time.Now().UnixNano())).Intn, //nolint:gosec
+		},
 	}
 }
 
@@ -99,6 +152,13 @@ func (l *looper) signalCrashedStatus() {
 	}
 }
 
+// setReconnecting sets the starting status while the loop reconnects on its
+// own, such that the running status is not signaled again once reconnected.
+func (l *looper) setReconnecting() {
+	l.state.setStatusWithLock(constants.Starting)
+	l.crashed = true
+}
+
 func (l *looper) Run(ctx context.Context, wg *sync.WaitGroup) { //nolint:gocognit
 	defer wg.Done()
 	select {
@@ -108,22 +168,67 @@ func (l *looper) Run(ctx context.Context, wg *sync.WaitGroup) { //nolint:gocogni
 	}
 	defer l.logger.Warn("loop exited")
 
+	var excludedIPs []net.IP // previous server IP addresses when switching
+	// previous is the tunnel kept up while connecting to a new server,
+	// and is nil if not switching servers seamlessly.
+	var previous *tunnel
+
 	for ctx.Err() == nil {
-		settings, allServers := l.state.getSettingsAndServers()
+		primary, allServers := l.state.getSettingsAndServers()
+		settings := l.failover.settings(primary)
 
 		providerConf := provider.New(settings.Provider.Name, allServers, time.Now)
 
+		var staticServerIP net.IP
+		if settings.StaticServer.Hostname != "" {
+			ip, err := l.resolveStaticServer(ctx, settings.StaticServer)
+			switch {
+			case err == nil:
+				l.staticServerIP = ip
+				l.tcpFallback.reset()
+			case l.staticServerIP != nil:
+				l.logger.Warn("cannot resolve static server %s, using its previous IP address %s: %s",
+					settings.StaticServer.Hostname, l.staticServerIP, err)
+			default:
+				l.signalCrashedStatus()
+				l.logAndWait(ctx, err)
+				continue
+			}
+			staticServerIP = l.staticServerIP
+		}
+
 		var connection models.OpenVPNConnection
 		var lines []string
 		var err error
 		if len(settings.Config) == 0 {
-			connection, err = providerConf.GetOpenVPNConnection(settings.Provider.ServerSelection)
+			selection := settings.Provider.ServerSelection
+			selection.ExcludedIPs = append(excludedIPs, l.blacklist.ips()...)
+			if settings.StickyServer {
+				selection.PreferredIP, err = l.readStickyServer()
+				if err != nil {
+					l.logger.Warn("cannot read sticky server: %s", err)
+				}
+			}
+			if l.tcpFallback.ip != nil {
+				tcpSelection := tcpFallbackSelection(selection, settings.Provider.Name, l.tcpFallback.ip)
+				connection, err = providerConf.GetOpenVPNConnection(tcpSelection)
+				if err != nil {
+					l.logger.Warn("cannot fall back on TCP: %s", err)
+					l.tcpFallback.reset()
+				}
+			}
+			if l.tcpFallback.ip == nil {
+				connection, err = l.pickConnection(providerConf, selection)
+			}
 			if err != nil {
 				l.logger.Error(err)
 				l.signalCrashedStatus()
 				l.cancel()
 				return
 			}
+			if staticServerIP != nil {
+				connection.IP = staticServerIP
+			}
 			lines = providerConf.BuildConf(connection, l.username, settings)
 		} else {
 			lines, connection, err = l.processCustomConfig(settings)
@@ -132,6 +237,65 @@ func (l *looper) Run(ctx context.Context, wg *sync.WaitGroup) { //nolint:gocogni
 				l.logAndWait(ctx, err)
 				continue
 			}
+			if staticServerIP != nil {
+				connection.IP = staticServerIP
+				lines = setConnectionToLines(lines, connection)
+			}
+		}
+
+		if settings.Provider.ExtraConfigOptions.ClientCertificate != "" {
+			lines = setClientCertificate(lines, settings.Provider.ExtraConfigOptions)
+		}
+
+		if extra := settings.Provider.ExtraConfigOptions; extra.TLSCrypt != "" || extra.TLSCryptV2 != "" {
+			lines = setTLSCrypt(lines, extra)
+		}
+
+		if l.tunedMTU > 0 && l.tunedMTU < maxMTU && l.tunedConnection.Equal(connection) {
+			lines = setMTU(lines, l.tunedMTU)
+		}
+
+		lines = setBinding(lines, settings.Binding)
+
+		device := constants.TUN
+		if previous != nil {
+			device = nextDevice(previous.device)
+			lines = setSeamlessSwitch(lines, device)
+		}
+
+		// firewallConnection is the connection allowed through the firewall
+		firewallConnection := connection
+		if settings.Obfuscation.Method != "" {
+			lines, firewallConnection, err = obfuscateConnection(lines, connection, settings.Obfuscation)
+			if err == nil {
+				err = writeStunnelConf(settings.Obfuscation, firewallConnection, l.openFile)
+			}
+			if err != nil {
+				l.logger.Error(err)
+				l.signalCrashedStatus()
+				l.cancel()
+				return
+			}
+		}
+
+		if settings.UpstreamProxy.Type != "" {
+			if connection.Protocol != constants.TCP {
+				l.logger.Error("upstream proxy requires the tcp protocol but protocol is %s", connection.Protocol)
+				l.signalCrashedStatus()
+				l.cancel()
+				return
+			}
+			lines, firewallConnection = setUpstreamProxy(lines, settings.UpstreamProxy)
+			if settings.UpstreamProxy.User != "" {
+				err := l.conf.WriteProxyAuthFile(settings.UpstreamProxy.User,
+					settings.UpstreamProxy.Password, l.puid, l.pgid)
+				if err != nil {
+					l.logger.Error(err)
+					l.signalCrashedStatus()
+					l.cancel()
+					return
+				}
+			}
 		}
 
 		if err := writeOpenvpnConf(lines, l.openFile); err != nil {
@@ -148,7 +312,14 @@ func (l *looper) Run(ctx context.Context, wg *sync.WaitGroup) { //nolint:gocogni
 			return
 		}
 
-		if err := l.fw.SetVPNConnection(ctx, connection); err != nil {
+		if previous != nil {
+			if err := l.prepareSeamlessSwitch(ctx, firewallConnection); err != nil {
+				l.logger.Error("cannot switch seamlessly: %s", err)
+				l.stopTunnel(previous)
+				previous = nil
+				continue
+			}
+		} else if err := l.setVPNConnection(ctx, firewallConnection, device); err != nil {
 			l.logger.Error(err)
 			l.signalCrashedStatus()
 			l.cancel()
@@ -157,6 +328,20 @@ func (l *looper) Run(ctx context.Context, wg *sync.WaitGroup) { //nolint:gocogni
 
 		openvpnCtx, openvpnCancel := context.WithCancel(context.Background())
 
+		if settings.Obfuscation.Method != "" {
+			stdoutLines, stderrLines, waitError, err := l.conf.StartStunnel(openvpnCtx)
+			if err != nil {
+				openvpnCancel()
+				l.signalCrashedStatus()
+				l.logAndWait(ctx, err)
+				continue
+			}
+			// OpenVPN is stopped if stunnel crashes, and is then restarted by the loop
+			wg.Add(1)
+			go l.runStunnel(openvpnCtx, wg, stdoutLines, stderrLines, waitError, openvpnCancel)
+		}
+
+		l.state.setConnectionState(models.OpenVPNConnectionState{State: constants.OpenVPNConnecting})
 		stdoutLines, stderrLines, waitError, err := l.conf.Start(openvpnCtx)
 		if err != nil {
 			openvpnCancel()
@@ -165,22 +350,41 @@ func (l *looper) Run(ctx context.Context, wg *sync.WaitGroup) { //nolint:gocogni
 			continue
 		}
 
+		current := &tunnel{
+			device:      device,
+			connection:  connection,
+			routed:      previous != nil,
+			cancel:      openvpnCancel,
+			waitError:   waitError,
+			stdoutLines: stdoutLines,
+			stderrLines: stderrLines,
+			superseded:  make(chan struct{}),
+		}
+		switching := previous
+		previous = nil
+
 		wg.Add(1)
-		go l.collectLines(wg, stdoutLines, stderrLines)
+		connected := make(chan struct{}, 1)
+		onConnected := func() {
+			if switching != nil {
+				l.switchTunnel(ctx, switching, current, firewallConnection)
+			}
+			select {
+			case connected <- struct{}{}:
+			default:
+			}
+			if !settings.StickyServer || len(settings.Config) > 0 {
+				return
+			}
+			if err := l.writeStickyServer(connection); err != nil {
+				l.logger.Warn("cannot write sticky server: %s", err)
+			}
+		}
+		failures := make(chan models.OpenVPNFailure, 1)
+		go l.collectLines(wg, stdoutLines, stderrLines, current.superseded, onConnected, failures)
 
 		// Needs the stream line from main.go to know when the tunnel is up
-		go func(ctx context.Context) {
-			for {
-				select {
-				// TODO have a way to disable pf with a context
-				case <-ctx.Done():
-					return
-				case gateway := <-l.portForwardSignals:
-					wg.Add(1)
-					go l.portForward(ctx, wg, providerConf, l.client, gateway)
-				}
-			}
-		}(openvpnCtx)
+		go l.runPortForward(openvpnCtx, wg, providerConf)
 
 		if l.crashed {
 			l.crashed = false
@@ -189,41 +393,237 @@ func (l *looper) Run(ctx context.Context, wg *sync.WaitGroup) { //nolint:gocogni
 		} else {
 			l.running <- constants.Running
 		}
+		excludedIPs = nil
+		// attemptConnected is true once the connection attempt succeeded
+		attemptConnected := false
+
+		rotationTimer := newRotationTimer(settings)
+		mtus := make(chan int, 1)
+		healthTicker := newHealthTicker(primary)
+		staticServerTicker := newStaticServerTicker(settings.StaticServer)
+		staticServerIPs := make(chan net.IP, 1)
+		// The channels of the tunnel events are set to nil once the tunnel
+		// is stopped, so they cannot act on the stopped tunnel. They are
+		// created again for the next tunnel once the loop is started.
+		rotations, healthChecks := rotationTimer.C, healthTicker.C
+		staticServerChecks, switchServer := staticServerTicker.C, l.switchServer
+		tunnelErrors := waitError
 
 		stayHere := true
 		for stayHere {
 			select {
 			case <-ctx.Done():
 				l.logger.Warn("context canceled: exiting loop")
-				openvpnCancel()
-				<-waitError
-				close(waitError)
-				close(stdoutLines)
-				close(stderrLines)
+				rotationTimer.Stop()
+				healthTicker.Stop()
+				staticServerTicker.Stop()
+				if switching != nil {
+					l.stopTunnel(switching)
+				}
+				l.stopTunnel(current)
 				return
 			case <-l.stop:
 				l.logger.Info("stopping")
+				if switching != nil {
+					l.stopTunnel(switching)
+				}
 				openvpnCancel()
 				<-waitError
+				rotationTimer.Stop()
+				healthTicker.Stop()
+				staticServerTicker.Stop()
+				rotations, healthChecks, staticServerChecks, switchServer = nil, nil, nil, nil
+				tunnelErrors, failures, connected, mtus, staticServerIPs = nil, nil, nil, nil, nil
+				select {
+				case <-l.switchServer: // drop a switch requested while stopping
+				default:
+				}
+				l.flushTunnelConnections(ctx)
+				l.state.setConnectionState(models.OpenVPNConnectionState{State: constants.OpenVPNDown})
 				l.stopped <- struct{}{}
 			case <-l.start:
 				l.logger.Info("starting")
 				stayHere = false
-			case err := <-waitError: // unexpected error
+			case err := <-tunnelErrors: // unexpected error
 				openvpnCancel()
 				l.state.setStatusWithLock(constants.Crashed)
+				if attemptConnected || !l.recordServerFailure(connection, settings) {
+					l.recordUDPFailure(connection, settings)
+				}
+				l.recordRemoteFailure(settings, connection)
 				l.logAndWait(ctx, err)
+				l.updateFailover(ctx, primary, allServers, false)
+				l.crashed = true
+				stayHere = false
+			case failure := <-failures:
+				openvpnCancel()
+				<-waitError
+				l.state.setStatusWithLock(constants.Crashed)
+				l.recordRemoteFailure(settings, connection)
+				switch {
+				case failure != constants.OpenVPNAuthFailed && !attemptConnected &&
+					l.recordServerFailure(connection, settings):
+					excludedIPs = nil // advance to the next candidate server
+				case failure == constants.OpenVPNTLSTimeout && l.recordUDPFailure(connection, settings):
+					excludedIPs = nil // retry the same server over TCP
+				default:
+					if excludedIPs = l.handleFailure(ctx, failure, connection, settings); excludedIPs != nil {
+						l.tcpFallback.reset() // switching server
+					}
+				}
+				l.updateFailover(ctx, primary, allServers, false)
 				l.crashed = true
 				stayHere = false
+			case <-healthChecks:
+				healthy := l.GetConnectionState().State == constants.OpenVPNUp
+				if !l.updateFailover(ctx, primary, allServers, healthy) {
+					break
+				}
+				openvpnCancel()
+				<-waitError
+				l.setReconnecting()
+				stayHere = false
+			case <-connected:
+				attemptConnected = true
+				l.blacklist.succeed(connection.IP)
+				l.recentServers = addRecentServer(l.recentServers, connection.IP)
+				l.tcpFallback.connected(connection)
+				l.remotes.succeed()
+				if settings.MTUDiscovery && settings.MSSFix == 0 && !l.tunedConnection.Equal(connection) {
+					go func(mtus chan<- int) { mtus <- l.discoverMTU(openvpnCtx, device) }(mtus)
+				}
+			case mtu := <-mtus:
+				l.tunedMTU, l.tunedConnection = mtu, connection
+				if mtu == 0 || mtu >= maxMTU {
+					break
+				}
+				l.logger.Info("reconnecting with MTU %d", mtu)
+				openvpnCancel()
+				<-waitError
+				l.setReconnecting()
+				stayHere = false
+			case <-rotations:
+				l.logger.Info("rotating to a different server than %s", connection.IP)
+				l.tcpFallback.reset()
+				previous = l.switchServers(current, settings)
+				excludedIPs = l.rotationExcludedIPs(providerConf, settings.Provider.ServerSelection, connection.IP)
+				l.setReconnecting()
+				stayHere = false
+			case <-staticServerChecks:
+				go func() {
+					ip := l.checkStaticServer(openvpnCtx, settings.StaticServer)
+					select {
+					case staticServerIPs <- ip:
+					default: // previous check result not processed yet
+					}
+				}()
+			case ip := <-staticServerIPs:
+				if ip == nil || ip.Equal(connection.IP) {
+					break
+				}
+				l.logger.Info("static server %s IP address changed from %s to %s, reconnecting",
+					settings.StaticServer.Hostname, connection.IP, ip)
+				l.staticServerIP = ip
+				l.tcpFallback.reset()
+				previous = l.switchServers(current, settings)
+				l.setReconnecting()
+				stayHere = false
+			case <-switchServer:
+				l.logger.Info("switching to a different server than %s", connection.IP)
+				l.tcpFallback.reset()
+				previous = l.switchServers(current, settings)
+				excludedIPs = []net.IP{connection.IP}
+				l.setReconnecting()
+				stayHere = false
 			}
 		}
-		close(waitError)
-		close(stdoutLines)
-		close(stderrLines)
-		openvpnCancel() // just for the linter
+		rotationTimer.Stop()
+		healthTicker.Stop()
+		staticServerTicker.Stop()
+		if switching != nil {
+			l.stopTunnel(switching)
+		}
+		if previous != current {
+			close(current.superseded)
+			close(waitError)
+			close(stdoutLines)
+			close(stderrLines)
+			openvpnCancel() // just for the linter
+			l.removeTunnelRoutes(current)
+			l.flushTunnelConnections(ctx)
+		}
+	}
+}
+
+// switchServers returns the current tunnel to keep it up while connecting
+// to a new server if the seamless switch is enabled and the tunnel is up.
+// Otherwise it stops the current tunnel and returns nil.
+func (l *looper) switchServers(current *tunnel, settings configuration.OpenVPN) (previous *tunnel) {
+	if settings.SeamlessSwitch && l.GetConnectionState().State == constants.OpenVPNUp {
+		return current
+	}
+	current.cancel()
+	<-current.waitError
+	return nil
+}
+
+// setVPNConnection allows the VPN connection and its tunnel device through
+// the firewall, the device being left to all tunnels by a failed switch.
+func (l *looper) setVPNConnection(ctx context.Context,
+	firewallConnection models.OpenVPNConnection, device string) (err error) {
+	if err := l.fw.SetVPNInterface(ctx, device); err != nil {
+		return err
+	}
+	return l.fw.SetVPNConnection(ctx, firewallConnection)
+}
+
+// prepareSeamlessSwitch allows the new VPN connection through the firewall
+// in addition to the current one, and routes it outside the current tunnel.
+func (l *looper) prepareSeamlessSwitch(ctx context.Context,
+	firewallConnection models.OpenVPNConnection) (err error) {
+	if err := l.fw.SetVPNInterface(ctx, anyTUN); err != nil {
+		return err
+	}
+	if err := l.fw.SetNextVPNConnection(ctx, firewallConnection); err != nil {
+		return err
+	}
+	return l.routing.SetVPNEndpointRoute(firewallConnection.IP)
+}
+
+const authFailedBackoffTime = 5 * time.Minute
+
+// handleFailure applies a retry strategy depending on the OpenVPN connection
+// failure given, and returns the IP addresses to exclude for the next attempt.
+func (l *looper) handleFailure(ctx context.Context, failure models.OpenVPNFailure,
+	connection models.OpenVPNConnection, settings configuration.OpenVPN) (excludedIPs []net.IP) {
+	switch failure {
+	case constants.OpenVPNAuthFailed:
+		// Changing server does not help, so wait longer
+		// instead of being rate limited by the provider.
+		if l.backoffTime < authFailedBackoffTime {
+			l.backoffTime = authFailedBackoffTime
+		}
+		l.logAndWait(ctx, fmt.Errorf("%w: check your credentials", errAuthFailed))
+	case constants.OpenVPNTLSTimeout:
+		// The server is likely unreachable, so switch server right away.
+		if len(settings.Config) > 0 {
+			l.logAndWait(ctx, errTLSTimeout)
+			break
+		}
+		l.logger.Warn("%s with server %s, switching to another server", errTLSTimeout, connection.IP)
+		excludedIPs = []net.IP{connection.IP}
+	case constants.OpenVPNRouteError:
+		l.logAndWait(ctx, errRouteSetup)
 	}
+	return excludedIPs
 }
 
+var (
+	errAuthFailed = errors.New("authentication failed")
+	errTLSTimeout = errors.New("TLS negotiation timed out")
+	errRouteSetup = errors.New("routes or tunnel device setup failed")
+)
+
 func (l *looper) logAndWait(ctx context.Context, err error) {
 	if err != nil {
 		l.logger.Error(err)
@@ -240,30 +640,6 @@ func (l *looper) logAndWait(ctx context.Context, err error) {
 	}
 }
 
-// portForward is a blocking operation which may or may not be infinite.
-// You should therefore always call it in a goroutine.
-func (l *looper) portForward(ctx context.Context, wg *sync.WaitGroup,
-	providerConf provider.Provider, client *http.Client, gateway net.IP) {
-	defer wg.Done()
-	l.state.portForwardedMu.RLock()
-	settings := l.state.settings
-	l.state.portForwardedMu.RUnlock()
-	if !settings.Provider.PortForwarding.Enabled {
-		return
-	}
-	syncState := func(port uint16) (pfFilepath string) {
-		l.state.portForwardedMu.Lock()
-		defer l.state.portForwardedMu.Unlock()
-		l.state.portForwarded = port
-		l.state.settingsMu.RLock()
-		defer l.state.settingsMu.RUnlock()
-		return settings.Provider.PortForwarding.Filepath
-	}
-	providerConf.PortForward(ctx,
-		client, l.openFile, l.pfLogger,
-		gateway, l.fw, syncState)
-}
-
 func writeOpenvpnConf(lines []string, openFile os.OpenFileFunc) error {
 	file, err := openFile(constants.OpenVPNConf, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
 	if err != nil {
@@ -278,3 +654,11 @@ func writeOpenvpnConf(lines []string, openFile os.OpenFileFunc) error {
 	}
 	return nil
 }
+
+// flushTunnelConnections flushes the connection tracking entries of the
+// connections through the tunnel, which is down.
+func (l *looper) flushTunnelConnections(ctx context.Context) {
+	if err := l.fw.FlushTunnelConnections(ctx); err != nil {
+		l.logger.Warn(err)
+	}
+}
@@ -0,0 +1,128 @@
+package openvpn
+
+import (
+	"context"
+	"net"
+	nativeos "os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/firewall/mock_firewall"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/openvpn/mock_openvpn"
+	"github.com/qdm12/gluetun/internal/routing/mock_routing"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/qdm12/golibs/os"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLooper returns a looper connecting to a single Mullvad server
+// with fake OpenVPN processes, which are stopped when their context is
+// canceled. The loop is run until the test ends.
+func newTestLooper(t *testing.T, settings configuration.OpenVPN) (l *looper) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+
+	conf := mock_openvpn.NewMockConfigurator(ctrl)
+	conf.EXPECT().WriteAuthFile(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).AnyTimes()
+	conf.EXPECT().Start(gomock.Any()).DoAndReturn(
+		func(ctx context.Context) (stdoutLines, stderrLines chan string, waitError chan error, err error) {
+			waitError = make(chan error)
+			go func() {
+				<-ctx.Done()
+				waitError <- ctx.Err()
+			}()
+			return make(chan string), make(chan string), waitError, nil
+		}).AnyTimes()
+
+	fw := mock_firewall.NewMockConfigurator(ctrl)
+	fw.EXPECT().GetEnabled().Return(true).AnyTimes()
+	fw.EXPECT().SetVPNInterface(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	fw.EXPECT().SetVPNConnection(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	fw.EXPECT().FlushTunnelConnections(gomock.Any()).Return(nil).AnyTimes()
+
+	routing := mock_routing.NewMockRouting(ctrl)
+
+	logger := mock_logging.NewMockLogger(ctrl)
+	logger.EXPECT().NewChild(gomock.Any()).Return(logger).AnyTimes()
+	logger.EXPECT().Debug(gomock.Any()).AnyTimes()
+	logger.EXPECT().Info(gomock.Any()).AnyTimes()
+	logger.EXPECT().Warn(gomock.Any()).AnyTimes()
+	logger.EXPECT().Error(gomock.Any()).AnyTimes()
+
+	dir := t.TempDir()
+	openFile := func(name string, flag int, perm os.FileMode) (os.File, error) {
+		path := filepath.Join(dir, filepath.Base(name))
+		return nativeos.OpenFile(path, flag, nativeos.FileMode(perm))
+	}
+
+	settings.Provider.Name = constants.Mullvad
+	allServers := models.AllServers{
+		Mullvad: models.MullvadServers{
+			Servers: []models.MullvadServer{{IPs: []net.IP{{1, 2, 3, 4}}}},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l = NewLooper(settings, "user", 1000, 1000, allServers, conf, fw, routing,
+		logger, nil, openFile, nil, cancel).(*looper)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go l.Run(ctx, wg)
+	t.Cleanup(func() {
+		cancel()
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("loop did not exit")
+		}
+	})
+
+	return l
+}
+
+// setStatus sets the status of the loop, failing the test if
+// the loop does not handle it in time.
+func setStatus(t *testing.T, l *looper, status models.LoopStatus) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := l.SetStatus(status)
+		assert.NoError(t, err)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.FailNow(t, "loop is blocked", "status %s not set", status)
+	}
+}
+
+func Test_looper_Run_stopThenRotationTick(t *testing.T) {
+	t.Parallel()
+
+	const rotationPeriod = 100 * time.Millisecond
+	l := newTestLooper(t, configuration.OpenVPN{RotationPeriod: rotationPeriod})
+
+	setStatus(t, l, constants.Running)
+	setStatus(t, l, constants.Stopped)
+
+	// the rotation timer would fire while stopped
+	time.Sleep(3 * rotationPeriod)
+
+	setStatus(t, l, constants.Running)
+	assert.Equal(t, constants.Running, l.GetStatus())
+}
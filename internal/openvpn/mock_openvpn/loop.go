@@ -0,0 +1,319 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/qdm12/gluetun/internal/openvpn (interfaces: Looper)
+
+// Package mock_openvpn is a generated GoMock package.
+package mock_openvpn
+
+import (
+	context "context"
+	net "net"
+	reflect "reflect"
+	sync "sync"
+
+	gomock "github.com/golang/mock/gomock"
+	configuration "github.com/qdm12/gluetun/internal/configuration"
+	models "github.com/qdm12/gluetun/internal/models"
+)
+
+// MockLooper is a mock of Looper interface.
+type MockLooper struct {
+	ctrl     *gomock.Controller
+	recorder *MockLooperMockRecorder
+}
+
+// MockLooperMockRecorder is the mock recorder for MockLooper.
+type MockLooperMockRecorder struct {
+	mock *MockLooper
+}
+
+// NewMockLooper creates a new mock instance.
+func NewMockLooper(ctrl *gomock.Controller) *MockLooper {
+	mock := &MockLooper{ctrl: ctrl}
+	mock.recorder = &MockLooperMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLooper) EXPECT() *MockLooperMockRecorder {
+	return m.recorder
+}
+
+// GetConnectionState mocks base method.
+func (m *MockLooper) GetConnectionState() models.OpenVPNConnectionState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConnectionState")
+	ret0, _ := ret[0].(models.OpenVPNConnectionState)
+	return ret0
+}
+
+// GetConnectionState indicates an expected call of GetConnectionState.
+func (mr *MockLooperMockRecorder) GetConnectionState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConnectionState", reflect.TypeOf((*MockLooper)(nil).GetConnectionState))
+}
+
+// GetPortForwardStatus mocks base method.
+func (m *MockLooper) GetPortForwardStatus() models.PortForwardStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPortForwardStatus")
+	ret0, _ := ret[0].(models.PortForwardStatus)
+	return ret0
+}
+
+// GetPortForwardStatus indicates an expected call of GetPortForwardStatus.
+func (mr *MockLooperMockRecorder) GetPortForwardStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPortForwardStatus", reflect.TypeOf((*MockLooper)(nil).GetPortForwardStatus))
+}
+
+// GetPortForwarded mocks base method.
+func (m *MockLooper) GetPortForwarded() uint16 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPortForwarded")
+	ret0, _ := ret[0].(uint16)
+	return ret0
+}
+
+// GetPortForwarded indicates an expected call of GetPortForwarded.
+func (mr *MockLooperMockRecorder) GetPortForwarded() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPortForwarded", reflect.TypeOf((*MockLooper)(nil).GetPortForwarded))
+}
+
+// GetServers mocks base method.
+func (m *MockLooper) GetServers() models.AllServers {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServers")
+	ret0, _ := ret[0].(models.AllServers)
+	return ret0
+}
+
+// GetServers indicates an expected call of GetServers.
+func (mr *MockLooperMockRecorder) GetServers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServers", reflect.TypeOf((*MockLooper)(nil).GetServers))
+}
+
+// GetSettings mocks base method.
+func (m *MockLooper) GetSettings() configuration.OpenVPN {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings")
+	ret0, _ := ret[0].(configuration.OpenVPN)
+	return ret0
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockLooperMockRecorder) GetSettings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockLooper)(nil).GetSettings))
+}
+
+// GetStatus mocks base method.
+func (m *MockLooper) GetStatus() models.LoopStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus")
+	ret0, _ := ret[0].(models.LoopStatus)
+	return ret0
+}
+
+// GetStatus indicates an expected call of GetStatus.
+func (mr *MockLooperMockRecorder) GetStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockLooper)(nil).GetStatus))
+}
+
+// GetTunnelStats mocks base method.
+func (m *MockLooper) GetTunnelStats() models.TunnelStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTunnelStats")
+	ret0, _ := ret[0].(models.TunnelStats)
+	return ret0
+}
+
+// GetTunnelStats indicates an expected call of GetTunnelStats.
+func (mr *MockLooperMockRecorder) GetTunnelStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTunnelStats", reflect.TypeOf((*MockLooper)(nil).GetTunnelStats))
+}
+
+// IsPaused mocks base method.
+func (m *MockLooper) IsPaused() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsPaused")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsPaused indicates an expected call of IsPaused.
+func (mr *MockLooperMockRecorder) IsPaused() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPaused", reflect.TypeOf((*MockLooper)(nil).IsPaused))
+}
+
+// Pause mocks base method.
+func (m *MockLooper) Pause() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pause")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pause indicates an expected call of Pause.
+func (mr *MockLooperMockRecorder) Pause() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockLooper)(nil).Pause))
+}
+
+// PortForward mocks base method.
+func (m *MockLooper) PortForward(arg0 net.IP) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PortForward", arg0)
+}
+
+// PortForward indicates an expected call of PortForward.
+func (mr *MockLooperMockRecorder) PortForward(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PortForward", reflect.TypeOf((*MockLooper)(nil).PortForward), arg0)
+}
+
+// RenewPortForward mocks base method.
+func (m *MockLooper) RenewPortForward() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewPortForward")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenewPortForward indicates an expected call of RenewPortForward.
+func (mr *MockLooperMockRecorder) RenewPortForward() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewPortForward", reflect.TypeOf((*MockLooper)(nil).RenewPortForward))
+}
+
+// Resume mocks base method.
+func (m *MockLooper) Resume() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resume")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resume indicates an expected call of Resume.
+func (mr *MockLooperMockRecorder) Resume() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockLooper)(nil).Resume))
+}
+
+// Run mocks base method.
+func (m *MockLooper) Run(arg0 context.Context, arg1 *sync.WaitGroup) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Run", arg0, arg1)
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockLooperMockRecorder) Run(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockLooper)(nil).Run), arg0, arg1)
+}
+
+// SetServerSelection mocks base method.
+func (m *MockLooper) SetServerSelection(arg0 string, arg1 configuration.ServerSelection) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetServerSelection", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetServerSelection indicates an expected call of SetServerSelection.
+func (mr *MockLooperMockRecorder) SetServerSelection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetServerSelection", reflect.TypeOf((*MockLooper)(nil).SetServerSelection), arg0, arg1)
+}
+
+// SetServers mocks base method.
+func (m *MockLooper) SetServers(arg0 models.AllServers) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetServers", arg0)
+}
+
+// SetServers indicates an expected call of SetServers.
+func (mr *MockLooperMockRecorder) SetServers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetServers", reflect.TypeOf((*MockLooper)(nil).SetServers), arg0)
+}
+
+// SetSettings mocks base method.
+func (m *MockLooper) SetSettings(arg0 configuration.OpenVPN) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSettings", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SetSettings indicates an expected call of SetSettings.
+func (mr *MockLooperMockRecorder) SetSettings(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSettings", reflect.TypeOf((*MockLooper)(nil).SetSettings), arg0)
+}
+
+// SetStatus mocks base method.
+func (m *MockLooper) SetStatus(arg0 models.LoopStatus) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStatus", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetStatus indicates an expected call of SetStatus.
+func (mr *MockLooperMockRecorder) SetStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStatus", reflect.TypeOf((*MockLooper)(nil).SetStatus), arg0)
+}
+
+// SubscribePortForward mocks base method.
+func (m *MockLooper) SubscribePortForward() (<-chan models.PortForwardStatus, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribePortForward")
+	ret0, _ := ret[0].(<-chan models.PortForwardStatus)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// SubscribePortForward indicates an expected call of SubscribePortForward.
+func (mr *MockLooperMockRecorder) SubscribePortForward() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribePortForward", reflect.TypeOf((*MockLooper)(nil).SubscribePortForward))
+}
+
+// SwitchProtocol mocks base method.
+func (m *MockLooper) SwitchProtocol() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SwitchProtocol")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SwitchProtocol indicates an expected call of SwitchProtocol.
+func (mr *MockLooperMockRecorder) SwitchProtocol() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SwitchProtocol", reflect.TypeOf((*MockLooper)(nil).SwitchProtocol))
+}
+
+// SwitchServer mocks base method.
+func (m *MockLooper) SwitchServer() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SwitchServer")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SwitchServer indicates an expected call of SwitchServer.
+func (mr *MockLooperMockRecorder) SwitchServer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SwitchServer", reflect.TypeOf((*MockLooper)(nil).SwitchServer))
+}
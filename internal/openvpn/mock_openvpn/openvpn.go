@@ -0,0 +1,155 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/qdm12/gluetun/internal/openvpn (interfaces: Configurator)
+
+// Package mock_openvpn is a generated GoMock package.
+package mock_openvpn
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockConfigurator is a mock of Configurator interface.
+type MockConfigurator struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfiguratorMockRecorder
+}
+
+// MockConfiguratorMockRecorder is the mock recorder for MockConfigurator.
+type MockConfiguratorMockRecorder struct {
+	mock *MockConfigurator
+}
+
+// NewMockConfigurator creates a new mock instance.
+func NewMockConfigurator(ctrl *gomock.Controller) *MockConfigurator {
+	mock := &MockConfigurator{ctrl: ctrl}
+	mock.recorder = &MockConfiguratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfigurator) EXPECT() *MockConfiguratorMockRecorder {
+	return m.recorder
+}
+
+// CheckTUN mocks base method.
+func (m *MockConfigurator) CheckTUN() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckTUN")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckTUN indicates an expected call of CheckTUN.
+func (mr *MockConfiguratorMockRecorder) CheckTUN() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckTUN", reflect.TypeOf((*MockConfigurator)(nil).CheckTUN))
+}
+
+// CreateTUN mocks base method.
+func (m *MockConfigurator) CreateTUN() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTUN")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTUN indicates an expected call of CreateTUN.
+func (mr *MockConfiguratorMockRecorder) CreateTUN() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTUN", reflect.TypeOf((*MockConfigurator)(nil).CreateTUN))
+}
+
+// PingDontFragment mocks base method.
+func (m *MockConfigurator) PingDontFragment(arg0 context.Context, arg1, arg2 string, arg3 int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PingDontFragment", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PingDontFragment indicates an expected call of PingDontFragment.
+func (mr *MockConfiguratorMockRecorder) PingDontFragment(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PingDontFragment", reflect.TypeOf((*MockConfigurator)(nil).PingDontFragment), arg0, arg1, arg2, arg3)
+}
+
+// Start mocks base method.
+func (m *MockConfigurator) Start(arg0 context.Context) (chan string, chan string, chan error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", arg0)
+	ret0, _ := ret[0].(chan string)
+	ret1, _ := ret[1].(chan string)
+	ret2, _ := ret[2].(chan error)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockConfiguratorMockRecorder) Start(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockConfigurator)(nil).Start), arg0)
+}
+
+// StartStunnel mocks base method.
+func (m *MockConfigurator) StartStunnel(arg0 context.Context) (chan string, chan string, chan error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartStunnel", arg0)
+	ret0, _ := ret[0].(chan string)
+	ret1, _ := ret[1].(chan string)
+	ret2, _ := ret[2].(chan error)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// StartStunnel indicates an expected call of StartStunnel.
+func (mr *MockConfiguratorMockRecorder) StartStunnel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartStunnel", reflect.TypeOf((*MockConfigurator)(nil).StartStunnel), arg0)
+}
+
+// Version mocks base method.
+func (m *MockConfigurator) Version(arg0 context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Version", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Version indicates an expected call of Version.
+func (mr *MockConfiguratorMockRecorder) Version(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Version", reflect.TypeOf((*MockConfigurator)(nil).Version), arg0)
+}
+
+// WriteAuthFile mocks base method.
+func (m *MockConfigurator) WriteAuthFile(arg0, arg1 string, arg2, arg3 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteAuthFile", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteAuthFile indicates an expected call of WriteAuthFile.
+func (mr *MockConfiguratorMockRecorder) WriteAuthFile(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteAuthFile", reflect.TypeOf((*MockConfigurator)(nil).WriteAuthFile), arg0, arg1, arg2, arg3)
+}
+
+// WriteProxyAuthFile mocks base method.
+func (m *MockConfigurator) WriteProxyAuthFile(arg0, arg1 string, arg2, arg3 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteProxyAuthFile", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteProxyAuthFile indicates an expected call of WriteProxyAuthFile.
+func (mr *MockConfiguratorMockRecorder) WriteProxyAuthFile(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteProxyAuthFile", reflect.TypeOf((*MockConfigurator)(nil).WriteProxyAuthFile), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,74 @@
+package openvpn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+const (
+	minMTU = 1280
+	maxMTU = 1500
+	// icmpOverhead is the size of the IPv4 and ICMP headers.
+	icmpOverhead = 28
+	// tcpOverhead is the size of the IPv4 and TCP headers.
+	tcpOverhead   = 40
+	mtuPingTarget = "1.1.1.1"
+)
+
+var errMTUTooSmall = errors.New("no ping reply through the tunnel with the minimum MTU")
+
+type pingFunc func(ctx context.Context, payloadSize int) (ok bool, err error)
+
+// findMTU finds the largest MTU between the minimum and maximum MTU for
+// which packets with the don't fragment bit set go through the tunnel,
+// using a binary search.
+func findMTU(ctx context.Context, ping pingFunc) (mtu int, err error) {
+	ok, err := ping(ctx, minMTU-icmpOverhead)
+	if err != nil {
+		return 0, err
+	} else if !ok {
+		return 0, fmt.Errorf("%w %d", errMTUTooSmall, minMTU)
+	}
+
+	low, high := minMTU, maxMTU
+	for low < high {
+		middle := (low + high + 1) / 2 //nolint:gomnd
+		ok, err := ping(ctx, middle-icmpOverhead)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			low = middle
+		} else {
+			high = middle - 1
+		}
+	}
+	return low, nil
+}
+
+// discoverMTU returns the MTU of the tunnel device, or 0 if it cannot be found.
+func (l *looper) discoverMTU(ctx context.Context, device string) (mtu int) {
+	l.logger.Info("discovering MTU through the tunnel...")
+	ping := func(ctx context.Context, payloadSize int) (ok bool, err error) {
+		return l.conf.PingDontFragment(ctx, device, mtuPingTarget, payloadSize)
+	}
+	mtu, err := findMTU(ctx, ping)
+	if err != nil {
+		l.logger.Warn("cannot discover MTU: %s", err)
+		return 0
+	}
+	l.logger.Info("discovered MTU %d", mtu)
+	return mtu
+}
+
+// setMTU modifies the OpenVPN configuration lines to use the MTU given,
+// leaving room for the IPv4 and TCP headers in the mssfix value.
+func setMTU(lines []string, mtu int) (modified []string) {
+	modified = removeDirectives(lines, "tun-mtu", "mssfix")
+	return append(modified,
+		"tun-mtu "+strconv.Itoa(mtu),
+		"mssfix "+strconv.Itoa(mtu-tcpOverhead),
+	)
+}
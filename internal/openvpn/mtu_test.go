@@ -0,0 +1,75 @@
+package openvpn
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_findMTU(t *testing.T) {
+	t.Parallel()
+	errDummy := errors.New("dummy")
+	testCases := map[string]struct {
+		pathMTU int
+		pingErr error
+		mtu     int
+		err     string
+	}{
+		"maximum MTU": {
+			pathMTU: 1500,
+			mtu:     1500,
+		},
+		"lower MTU": {
+			pathMTU: 1412,
+			mtu:     1412,
+		},
+		"minimum MTU": {
+			pathMTU: 1280,
+			mtu:     1280,
+		},
+		"below minimum MTU": {
+			pathMTU: 1000,
+			err:     "no ping reply through the tunnel with the minimum MTU 1280",
+		},
+		"ping error": {
+			pathMTU: 1500,
+			pingErr: errDummy,
+			err:     "dummy",
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ping := func(ctx context.Context, payloadSize int) (ok bool, err error) {
+				return payloadSize+icmpOverhead <= testCase.pathMTU, testCase.pingErr
+			}
+			mtu, err := findMTU(context.Background(), ping)
+			if testCase.err != "" {
+				require.Error(t, err)
+				assert.Equal(t, testCase.err, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.mtu, mtu)
+		})
+	}
+}
+
+func Test_setMTU(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{"proto udp", "tun-mtu 1500", "mssfix 1450", "cipher AES-256-GCM"}
+
+	modified := setMTU(lines, 1412)
+
+	assert.Equal(t, []string{
+		"proto udp",
+		"cipher AES-256-GCM",
+		"tun-mtu 1412",
+		"mssfix 1372",
+	}, modified)
+}
@@ -0,0 +1,87 @@
+package openvpn
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/os"
+)
+
+// obfuscateConnection modifies the OpenVPN configuration lines to connect to
+// the local stunnel listener, and returns the server connection stunnel
+// should connect to.
+func obfuscateConnection(lines []string, connection models.OpenVPNConnection,
+	obfuscation configuration.Obfuscation) (modified []string,
+	serverConnection models.OpenVPNConnection, err error) {
+	if connection.Protocol != constants.TCP {
+		return nil, serverConnection, fmt.Errorf("%w: protocol is %s",
+			configuration.ErrObfuscationProtocol, connection.Protocol)
+	}
+
+	localConnection := models.OpenVPNConnection{
+		IP:       net.IPv4(127, 0, 0, 1), //nolint:gomnd
+		Port:     obfuscation.LocalPort,
+		Protocol: constants.TCP,
+	}
+	modified = setConnectionToLines(lines, localConnection)
+	// the server must be reached through the default gateway and not the tunnel
+	modified = append(modified, "route "+connection.IP.String()+" 255.255.255.255 net_gateway")
+
+	serverConnection = connection
+	serverConnection.Port = obfuscation.ServerPort
+	return modified, serverConnection, nil
+}
+
+func writeStunnelConf(obfuscation configuration.Obfuscation,
+	serverConnection models.OpenVPNConnection, openFile os.OpenFileFunc) error {
+	lines := []string{
+		"foreground = yes",
+		"pid =",
+		"[openvpn]",
+		"client = yes",
+		"accept = 127.0.0.1:" + strconv.Itoa(int(obfuscation.LocalPort)),
+		"connect = " + net.JoinHostPort(serverConnection.IP.String(),
+			strconv.Itoa(int(serverConnection.Port))),
+	}
+	file, err := openFile(constants.StunnelConf, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = file.WriteString(strings.Join(lines, "\n"))
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// runStunnel logs the stunnel output until it exits, and calls onCrash
+// if it exits before the context is canceled.
+func (l *looper) runStunnel(ctx context.Context, wg *sync.WaitGroup,
+	stdoutLines, stderrLines chan string, waitError chan error, onCrash func()) {
+	defer wg.Done()
+	for {
+		select {
+		case line := <-stdoutLines:
+			l.logger.Info("stunnel: " + line)
+		case line := <-stderrLines:
+			l.logger.Error("stunnel: " + line)
+		case err := <-waitError:
+			close(waitError)
+			close(stdoutLines)
+			close(stderrLines)
+			if ctx.Err() == nil {
+				l.logger.Error("stunnel exited: %s", err)
+				onCrash()
+			}
+			return
+		}
+	}
+}
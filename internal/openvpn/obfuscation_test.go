@@ -0,0 +1,46 @@
+package openvpn
+
+import (
+	"net"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_obfuscateConnection(t *testing.T) {
+	t.Parallel()
+
+	obfuscation := configuration.Obfuscation{
+		Method:     constants.Stunnel,
+		ServerPort: 443,
+		LocalPort:  1195,
+	}
+	connection := models.OpenVPNConnection{
+		IP:       net.IPv4(1, 2, 3, 4),
+		Port:     1194,
+		Protocol: constants.TCP,
+	}
+	lines := []string{"proto tcp", "remote 1.2.3.4 1194", "cipher AES-256-GCM"}
+
+	modified, serverConnection, err := obfuscateConnection(lines, connection, obfuscation)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"proto tcp",
+		"remote 127.0.0.1 1195",
+		"cipher AES-256-GCM",
+		"route 1.2.3.4 255.255.255.255 net_gateway",
+	}, modified)
+	assert.Equal(t, models.OpenVPNConnection{
+		IP:       net.IPv4(1, 2, 3, 4),
+		Port:     443,
+		Protocol: constants.TCP,
+	}, serverConnection)
+
+	connection.Protocol = constants.UDP
+	_, _, err = obfuscateConnection(lines, connection, obfuscation)
+	assert.ErrorIs(t, err, configuration.ErrObfuscationProtocol)
+}
@@ -11,13 +11,19 @@ import (
 	"github.com/qdm12/golibs/os"
 )
 
+//go:generate mockgen -destination=mock_$GOPACKAGE/$GOFILE . Configurator
+
 type Configurator interface {
 	Version(ctx context.Context) (string, error)
 	WriteAuthFile(user, password string, puid, pgid int) error
+	WriteProxyAuthFile(user, password string, puid, pgid int) error
 	CheckTUN() error
 	CreateTUN() error
 	Start(ctx context.Context) (stdoutLines, stderrLines chan string,
 		waitError chan error, err error)
+	StartStunnel(ctx context.Context) (stdoutLines, stderrLines chan string,
+		waitError chan error, err error)
+	PingDontFragment(ctx context.Context, device, target string, payloadSize int) (ok bool, err error)
 }
 
 type configurator struct {
@@ -0,0 +1,55 @@
+package openvpn
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+var ErrPauseFirewallDisabled = errors.New("cannot pause with the firewall disabled since traffic would leak")
+
+// Pause stops the tunnel, keeping the firewall blocking all traffic
+// except to the VPN server, until Resume is called.
+func (l *looper) Pause() (outcome string, err error) {
+	if !l.fw.GetEnabled() {
+		return "", ErrPauseFirewallDisabled
+	}
+	if status := l.GetStatus(); status != constants.Running {
+		return fmt.Sprintf("cannot pause: %s", status), nil
+	}
+
+	l.state.pausedMu.Lock()
+	l.state.paused = true
+	l.state.pausedMu.Unlock()
+
+	if _, err := l.SetStatus(constants.Stopped); err != nil {
+		return "", err
+	}
+	l.logger.Info("paused, traffic is blocked by the firewall until resumed")
+	return "paused", nil
+}
+
+// Resume starts the tunnel again if it is paused.
+func (l *looper) Resume() (outcome string, err error) {
+	if !l.IsPaused() {
+		return "not paused", nil
+	}
+
+	l.state.pausedMu.Lock()
+	l.state.paused = false
+	l.state.pausedMu.Unlock()
+
+	l.logger.Info("resuming")
+	return l.SetStatus(constants.Running)
+}
+
+// IsPaused returns true if the tunnel is stopped by Pause.
+// The tunnel is no longer paused if it was started
+// again by setting the loop status.
+func (l *looper) IsPaused() (paused bool) {
+	l.state.pausedMu.RLock()
+	paused = l.state.paused
+	l.state.pausedMu.RUnlock()
+	return paused && l.GetStatus() == constants.Stopped
+}
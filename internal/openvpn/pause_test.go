@@ -0,0 +1,42 @@
+package openvpn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_looper_Pause_rotationTickThenResume(t *testing.T) {
+	t.Parallel()
+
+	const rotationPeriod = 100 * time.Millisecond
+	l := newTestLooper(t, configuration.OpenVPN{RotationPeriod: rotationPeriod})
+
+	setStatus(t, l, constants.Running)
+
+	outcome, err := l.Pause()
+	require.NoError(t, err)
+	assert.Equal(t, "paused", outcome)
+	assert.True(t, l.IsPaused())
+
+	// the rotation timer would fire while paused
+	time.Sleep(3 * rotationPeriod)
+
+	resumed := make(chan string)
+	go func() {
+		outcome, err := l.Resume()
+		assert.NoError(t, err)
+		resumed <- outcome
+	}()
+	select {
+	case outcome = <-resumed:
+	case <-time.After(time.Second):
+		require.FailNow(t, "loop is blocked", "not resumed")
+	}
+	assert.Equal(t, constants.Running.String(), outcome)
+	assert.False(t, l.IsPaused())
+}
@@ -0,0 +1,324 @@
+package openvpn
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider"
+	"github.com/qdm12/gluetun/internal/torrent"
+	"github.com/qdm12/golibs/os"
+)
+
+var (
+	ErrPortForwardingDisabled   = errors.New("port forwarding is disabled")
+	ErrPortForwardingNotRunning = errors.New("port forwarding is not running")
+)
+
+// portForwardRenewTimeout is the maximum time to wait for the
+// port forwarding goroutine to accept a renewal request.
+const portForwardRenewTimeout = 10 * time.Second
+
+// runPortForward starts port forwarding on each signal received with
+// the VPN gateway IP address, and restarts it without its persisted
+// data on each renewal request, until the context is canceled.
+func (l *looper) runPortForward(ctx context.Context, wg *sync.WaitGroup,
+	providerConf provider.Provider) {
+	var gateway net.IP
+	started := false
+	pfCancel := context.CancelFunc(func() {})
+	pfDone := make(chan struct{})
+	close(pfDone)
+
+	stopPortForward := func() {
+		pfCancel()
+		<-pfDone
+	}
+
+	startPortForward := func() {
+		var pfCtx context.Context
+		pfCtx, pfCancel = context.WithCancel(ctx)
+		pfDone = make(chan struct{})
+		wg.Add(1)
+		go func(done chan<- struct{}) {
+			defer close(done)
+			l.portForward(pfCtx, wg, providerConf, l.client, gateway)
+		}(pfDone)
+		started = true
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			pfCancel()
+			return
+		case gateway = <-l.portForwardSignals:
+			stopPortForward()
+			startPortForward()
+		case result := <-l.portForwardRenew:
+			if !started {
+				result <- ErrPortForwardingNotRunning
+				continue
+			}
+			l.pfLogger.Info("renewing port forwarded")
+			stopPortForward()
+			err := clearPortForwardData(l.openFile)
+			startPortForward()
+			result <- err
+		}
+	}
+}
+
+// portForward is a blocking operation which may or may not be infinite.
+// You should therefore always call it in a goroutine.
+func (l *looper) portForward(ctx context.Context, wg *sync.WaitGroup,
+	providerConf provider.Provider, client *http.Client, gateway net.IP) {
+	defer wg.Done()
+	settings := l.GetSettings()
+	if !settings.Provider.PortForwarding.Enabled {
+		return
+	}
+	var torrentClient torrent.PortSetter
+	if settings.Provider.PortForwarding.TorrentClient.Name != "" {
+		var err error
+		torrentClient, err = torrent.New(client, settings.Provider.PortForwarding.TorrentClient)
+		if err != nil {
+			l.pfLogger.Error(err)
+		}
+	}
+	syncPorts := func(ports []models.ForwardedPort) (pfFilepath string) {
+		previousPorts := l.getPortsForwarded()
+		l.setPortsForwarded(settings.Provider.Name, ports)
+		if samePorts(previousPorts, ports) || len(ports) == 0 {
+			return settings.Provider.PortForwarding.Filepath
+		}
+		if settings.Provider.PortForwarding.UpCommand != "" {
+			l.runPortForwardUpCommand(ctx, settings.Provider.PortForwarding.UpCommand, ports)
+		}
+		if torrentClient != nil {
+			l.setTorrentClientPort(ctx, torrentClient, ports[0].Port)
+		}
+		if proxyPorts := settings.Provider.PortForwarding.ProxyPorts; len(proxyPorts) > 0 {
+			l.setPortRedirections(ctx, proxyRedirections(ports, proxyPorts))
+		}
+		return settings.Provider.PortForwarding.Filepath
+	}
+	syncState := func(port uint16, expiration time.Time) (pfFilepath string) {
+		if port == 0 {
+			return syncPorts(nil)
+		}
+		return syncPorts([]models.ForwardedPort{{Port: port, Expiration: expiration}})
+	}
+	if len(settings.Config) > 0 { // custom configuration
+		provider.NATPMPPortForward(ctx, l.openFile, l.pfLogger, gateway, l.fw,
+			settings.Provider.PortForwarding.Count, syncPorts)
+	} else {
+		providerConf.PortForward(ctx,
+			client, l.openFile, l.pfLogger,
+			gateway, l.fw, syncState)
+	}
+	l.setPortsForwarded(settings.Provider.Name, nil)
+	if len(settings.Provider.PortForwarding.ProxyPorts) > 0 {
+		removeCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		l.setPortRedirections(removeCtx, nil)
+	}
+}
+
+// proxyRedirections returns the redirections of the last ports
+// forwarded to the listening ports of the proxies exposed.
+func proxyRedirections(ports []models.ForwardedPort,
+	proxyPorts []uint16) (redirections []models.PortRedirection) {
+	offset := len(ports) - len(proxyPorts)
+	if offset < 0 {
+		return nil
+	}
+	redirections = make([]models.PortRedirection, len(proxyPorts))
+	for i, proxyPort := range proxyPorts {
+		redirections[i] = models.PortRedirection{
+			Port:       ports[offset+i].Port,
+			TargetPort: proxyPort,
+		}
+	}
+	return redirections
+}
+
+func (l *looper) setPortRedirections(ctx context.Context,
+	redirections []models.PortRedirection) {
+	if err := l.fw.SetPortRedirections(ctx, string(constants.TUN), redirections); err != nil {
+		l.pfLogger.Error(err)
+		return
+	}
+	for _, redirection := range redirections {
+		l.pfLogger.Info("proxy listening port %d exposed on port forwarded %d",
+			redirection.TargetPort, redirection.Port)
+	}
+}
+
+// samePorts returns true if the ports forwarded given
+// are the same, regardless of their expiration.
+func samePorts(a, b []models.ForwardedPort) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Port != b[i].Port {
+			return false
+		}
+	}
+	return true
+}
+
+// sameExpirations returns true if the ports forwarded given,
+// of the same length, expire at the same times.
+func sameExpirations(a, b []models.ForwardedPort) bool {
+	for i := range a {
+		if !a[i].Expiration.Equal(b[i].Expiration) {
+			return false
+		}
+	}
+	return true
+}
+
+// portForwardUpCommandTimeout is the maximum duration
+// the port forwarding up command can run for.
+const portForwardUpCommandTimeout = time.Minute
+
+// runPortForwardUpCommand runs the shell command given with the ports
+// forwarded as arguments, and logs its output.
+func (l *looper) runPortForwardUpCommand(ctx context.Context, upCommand string,
+	ports []models.ForwardedPort) {
+	ctx, cancel := context.WithTimeout(ctx, portForwardUpCommandTimeout)
+	defer cancel()
+	args := []string{"-c", upCommand, "sh"}
+	for _, port := range ports {
+		args = append(args, strconv.Itoa(int(port.Port)))
+	}
+	l.pfLogger.Info("running up command for ports %s", strings.Join(args[3:], ", "))
+	output, err := l.commander.Run(ctx, "/bin/sh", args...)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line != "" {
+			l.pfLogger.Info("up command: %s", line)
+		}
+	}
+	if err != nil {
+		l.pfLogger.Error("up command failed: %s", err)
+	}
+}
+
+// torrentClientTimeout is the maximum duration to
+// set the listening port of the torrent client.
+const torrentClientTimeout = 30 * time.Second
+
+func (l *looper) setTorrentClientPort(ctx context.Context,
+	torrentClient torrent.PortSetter, port uint16) {
+	ctx, cancel := context.WithTimeout(ctx, torrentClientTimeout)
+	defer cancel()
+	if err := torrentClient.SetPort(ctx, port); err != nil {
+		l.pfLogger.Error("cannot set torrent client listening port: %s", err)
+		return
+	}
+	l.pfLogger.Info("torrent client listening port set to %d", port)
+}
+
+// clearPortForwardData empties the persisted port forwarding
+// data so a new port is obtained when port forwarding restarts.
+func clearPortForwardData(openFile os.OpenFileFunc) (err error) {
+	file, err := openFile(constants.PIAPortForward, os.O_WRONLY|os.O_TRUNC, 0)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func (l *looper) getPortsForwarded() (ports []models.ForwardedPort) {
+	l.state.portForwardedMu.RLock()
+	defer l.state.portForwardedMu.RUnlock()
+	return l.state.portsForwarded
+}
+
+func (l *looper) setPortsForwarded(providerName string, ports []models.ForwardedPort) {
+	status := newPortForwardStatus(providerName, ports)
+
+	l.state.portForwardedMu.Lock()
+	defer l.state.portForwardedMu.Unlock()
+	if samePorts(ports, l.state.portsForwarded) &&
+		sameExpirations(ports, l.state.portsForwarded) {
+		return
+	}
+	l.state.portsForwarded = ports
+	for updates := range l.state.portForwardSubscribers {
+		// Only keep the latest status for slow subscribers
+		select {
+		case <-updates:
+		default:
+		}
+		updates <- status
+	}
+}
+
+func (l *looper) GetPortForwardStatus() (status models.PortForwardStatus) {
+	settings := l.GetSettings()
+	l.state.portForwardedMu.RLock()
+	defer l.state.portForwardedMu.RUnlock()
+	return newPortForwardStatus(settings.Provider.Name, l.state.portsForwarded)
+}
+
+func newPortForwardStatus(providerName string,
+	ports []models.ForwardedPort) (status models.PortForwardStatus) {
+	status.Provider = providerName
+	status.Ports = ports
+	if len(ports) > 0 {
+		status.Port = ports[0].Port
+		status.Expiration = ports[0].Expiration
+	}
+	return status
+}
+
+// SubscribePortForward returns a channel receiving the status of the
+// port forwarded each time it changes, and a function to call once
+// done to stop receiving on the channel.
+func (l *looper) SubscribePortForward() (updates <-chan models.PortForwardStatus, unsubscribe func()) {
+	channel := make(chan models.PortForwardStatus, 1)
+	l.state.portForwardedMu.Lock()
+	defer l.state.portForwardedMu.Unlock()
+	if l.state.portForwardSubscribers == nil {
+		l.state.portForwardSubscribers = make(map[chan models.PortForwardStatus]struct{})
+	}
+	l.state.portForwardSubscribers[channel] = struct{}{}
+	unsubscribe = func() {
+		l.state.portForwardedMu.Lock()
+		defer l.state.portForwardedMu.Unlock()
+		delete(l.state.portForwardSubscribers, channel)
+	}
+	return channel, unsubscribe
+}
+
+// RenewPortForward obtains a new port forwarded, discarding
+// the port forwarding data persisted.
+func (l *looper) RenewPortForward() (err error) {
+	if !l.GetSettings().Provider.PortForwarding.Enabled {
+		return ErrPortForwardingDisabled
+	} else if l.GetStatus() != constants.Running {
+		return ErrPortForwardingNotRunning
+	}
+
+	result := make(chan error)
+	timer := time.NewTimer(portForwardRenewTimeout)
+	defer timer.Stop()
+	select {
+	case l.portForwardRenew <- result:
+		return <-result
+	case <-timer.C:
+		return ErrPortForwardingNotRunning
+	}
+}
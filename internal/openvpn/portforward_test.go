@@ -0,0 +1,49 @@
+package openvpn
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_proxyRedirections(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ports        []models.ForwardedPort
+		proxyPorts   []uint16
+		redirections []models.PortRedirection
+	}{
+		"not enough ports": {
+			ports:      []models.ForwardedPort{{Port: 1000}},
+			proxyPorts: []uint16{8888, 8388},
+		},
+		"same number of ports": {
+			ports:      []models.ForwardedPort{{Port: 1000}, {Port: 2000}},
+			proxyPorts: []uint16{8888, 8388},
+			redirections: []models.PortRedirection{
+				{Port: 1000, TargetPort: 8888},
+				{Port: 2000, TargetPort: 8388},
+			},
+		},
+		"last ports": {
+			ports:      []models.ForwardedPort{{Port: 1000}, {Port: 2000}, {Port: 3000}},
+			proxyPorts: []uint16{8888},
+			redirections: []models.PortRedirection{
+				{Port: 3000, TargetPort: 8888},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			redirections := proxyRedirections(testCase.ports, testCase.proxyPorts)
+
+			assert.Equal(t, testCase.redirections, redirections)
+		})
+	}
+}
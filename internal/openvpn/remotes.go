@@ -0,0 +1,84 @@
+package openvpn
+
+import (
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// remoteSelector picks the remote to connect to amongst the remotes of
+// the custom configuration, and moves on to another remote after a
+// number of consecutive connection failures.
+type remoteSelector struct {
+	index    int
+	remotes  int
+	random   bool
+	failures int
+	randIntn func(n int) int
+}
+
+// pick returns the index of the remote to connect to, given the number
+// of remotes. The first remote is picked randomly if random is true.
+func (r *remoteSelector) pick(remotes int, random bool) (index int) {
+	if r.remotes != remotes || r.random != random {
+		r.remotes = remotes
+		r.random = random
+		r.failures = 0
+		r.index = 0
+		if random {
+			r.index = r.randIntn(remotes)
+		}
+	}
+	return r.index
+}
+
+// fail records a connection failure with the current remote, and moves on
+// to the next remote, or to another random remote, once the number of
+// retries is exceeded. It returns true if it moved on to another remote.
+func (r *remoteSelector) fail(retries int) (moved bool) {
+	r.failures++
+	if r.failures <= retries || r.remotes < 2 {
+		return false
+	}
+	r.failures = 0
+
+	if !r.random {
+		r.index = (r.index + 1) % r.remotes
+		return true
+	}
+
+	// pick amongst the other remotes
+	next := r.randIntn(r.remotes - 1)
+	if next >= r.index {
+		next++
+	}
+	r.index = next
+	return true
+}
+
+// succeed resets the number of consecutive failures.
+func (r *remoteSelector) succeed() {
+	r.failures = 0
+}
+
+// hasDirective returns true if one of the lines is the directive given.
+func hasDirective(lines []string, directive string) bool {
+	for _, line := range lines {
+		if line == directive || strings.HasPrefix(line, directive+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// recordRemoteFailure records a connection failure with the remote of
+// the custom configuration, and logs when moving on to another remote.
+func (l *looper) recordRemoteFailure(settings configuration.OpenVPN,
+	connection models.OpenVPNConnection) {
+	if len(settings.Config) == 0 || !l.remotes.fail(settings.CustomRemotes.Retries) {
+		return
+	}
+	l.logger.Warn("remote %s:%d:%s failed, switching to another remote",
+		connection.IP, connection.Port, connection.Protocol)
+}
@@ -0,0 +1,54 @@
+package openvpn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_remoteSelector(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ordered", func(t *testing.T) {
+		t.Parallel()
+		var selector remoteSelector
+		const remotes, retries = 3, 1
+		assert.Equal(t, 0, selector.pick(remotes, false))
+		assert.False(t, selector.fail(retries))
+		assert.True(t, selector.fail(retries))
+		assert.Equal(t, 1, selector.pick(remotes, false))
+		assert.False(t, selector.fail(retries))
+		selector.succeed()
+		assert.False(t, selector.fail(retries))
+		assert.True(t, selector.fail(retries))
+		assert.True(t, selector.fail(0))
+		assert.Equal(t, 0, selector.pick(remotes, false))
+	})
+
+	t.Run("random", func(t *testing.T) {
+		t.Parallel()
+		selector := remoteSelector{
+			randIntn: func(n int) int { return n - 1 },
+		}
+		const remotes = 3
+		assert.Equal(t, 2, selector.pick(remotes, true))
+		assert.True(t, selector.fail(0))
+		assert.Equal(t, 1, selector.pick(remotes, true))
+	})
+
+	t.Run("single remote", func(t *testing.T) {
+		t.Parallel()
+		var selector remoteSelector
+		assert.Equal(t, 0, selector.pick(1, false))
+		assert.False(t, selector.fail(0))
+		assert.Equal(t, 0, selector.pick(1, false))
+	})
+}
+
+func Test_hasDirective(t *testing.T) {
+	t.Parallel()
+	lines := []string{"remote 1.2.3.4", "remote-random", "remote-cert-tls server"}
+	assert.True(t, hasDirective(lines, "remote-random"))
+	assert.True(t, hasDirective(lines, "remote-cert-tls"))
+	assert.False(t, hasDirective(lines, "proto"))
+}
@@ -0,0 +1,77 @@
+package openvpn
+
+import (
+	"net"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider"
+)
+
+// newRotationTimer returns a timer firing after the server rotation period,
+// or a stopped timer if rotation is disabled or a custom configuration is used.
+func newRotationTimer(settings configuration.OpenVPN) (timer *time.Timer) {
+	if settings.RotationPeriod == 0 || len(settings.Config) > 0 {
+		timer = time.NewTimer(time.Hour)
+		timer.Stop()
+		return timer
+	}
+	return time.NewTimer(settings.RotationPeriod)
+}
+
+// maxRecentServers is the maximum number of servers recently
+// connected to which are avoided when rotating servers.
+const maxRecentServers = 10
+
+// addRecentServer adds the IP address given at the start of the recent
+// server IP addresses given, removing its previous occurrence and the
+// oldest IP addresses beyond maxRecentServers.
+func addRecentServer(recent []net.IP, ip net.IP) (updated []net.IP) {
+	updated = make([]net.IP, 1, maxRecentServers)
+	updated[0] = ip
+	for _, recentIP := range recent {
+		if len(updated) == maxRecentServers {
+			break
+		} else if !recentIP.Equal(ip) {
+			updated = append(updated, recentIP)
+		}
+	}
+	return updated
+}
+
+// rotationExcludedIPs returns the IP addresses of the current server and
+// of the servers recently connected to, to avoid when rotating servers
+// such that the rotation does not bounce between the same servers.
+func (l *looper) rotationExcludedIPs(providerConf provider.Provider,
+	selection configuration.ServerSelection, current net.IP) (excludedIPs []net.IP) {
+	recent := addRecentServer(l.recentServers, current)
+	candidates, err := providerConf.GetOpenVPNConnections(selection)
+	if err != nil { // only exclude the current server
+		return recent[:1]
+	}
+	return excludeRecentServers(recent, candidates)
+}
+
+// excludeRecentServers returns the recent server IP addresses to exclude,
+// from the most recent to the oldest, such that at least one candidate
+// is not excluded. The most recent server is always excluded.
+func excludeRecentServers(recent []net.IP,
+	candidates []models.OpenVPNConnection) (excludedIPs []net.IP) {
+	for i, ip := range recent {
+		excludedIPs = append(excludedIPs, ip)
+		if i > 0 && !hasCandidateNotIn(candidates, excludedIPs) {
+			return excludedIPs[:i]
+		}
+	}
+	return excludedIPs
+}
+
+func hasCandidateNotIn(candidates []models.OpenVPNConnection, ips []net.IP) bool {
+	for _, candidate := range candidates {
+		if !ipIsIn(candidate.IP, ips) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package openvpn
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_addRecentServer(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		recent  []net.IP
+		ip      net.IP
+		updated []net.IP
+	}{
+		"first server": {
+			ip:      net.IPv4(1, 1, 1, 1),
+			updated: []net.IP{net.IPv4(1, 1, 1, 1)},
+		},
+		"new server": {
+			recent:  []net.IP{net.IPv4(1, 1, 1, 1)},
+			ip:      net.IPv4(2, 2, 2, 2),
+			updated: []net.IP{net.IPv4(2, 2, 2, 2), net.IPv4(1, 1, 1, 1)},
+		},
+		"server already recent": {
+			recent:  []net.IP{net.IPv4(1, 1, 1, 1), net.IPv4(2, 2, 2, 2)},
+			ip:      net.IPv4(2, 2, 2, 2),
+			updated: []net.IP{net.IPv4(2, 2, 2, 2), net.IPv4(1, 1, 1, 1)},
+		},
+		"oldest server dropped": {
+			recent: []net.IP{{1, 1, 1, 1}, {2, 2, 2, 2}, {3, 3, 3, 3}, {4, 4, 4, 4}, {5, 5, 5, 5},
+				{6, 6, 6, 6}, {7, 7, 7, 7}, {8, 8, 8, 8}, {9, 9, 9, 9}, {10, 10, 10, 10}},
+			ip: net.IP{11, 11, 11, 11},
+			updated: []net.IP{{11, 11, 11, 11}, {1, 1, 1, 1}, {2, 2, 2, 2}, {3, 3, 3, 3}, {4, 4, 4, 4},
+				{5, 5, 5, 5}, {6, 6, 6, 6}, {7, 7, 7, 7}, {8, 8, 8, 8}, {9, 9, 9, 9}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			updated := addRecentServer(testCase.recent, testCase.ip)
+
+			assert.Equal(t, testCase.updated, updated)
+		})
+	}
+}
+
+func Test_excludeRecentServers(t *testing.T) {
+	t.Parallel()
+
+	a := models.OpenVPNConnection{IP: net.IPv4(1, 1, 1, 1)}
+	b := models.OpenVPNConnection{IP: net.IPv4(2, 2, 2, 2)}
+	c := models.OpenVPNConnection{IP: net.IPv4(3, 3, 3, 3)}
+
+	testCases := map[string]struct {
+		recent     []net.IP
+		candidates []models.OpenVPNConnection
+		excluded   []net.IP
+	}{
+		"no recent server": {
+			candidates: []models.OpenVPNConnection{a, b},
+		},
+		"all recent servers excluded": {
+			recent:     []net.IP{a.IP, b.IP},
+			candidates: []models.OpenVPNConnection{a, b, c},
+			excluded:   []net.IP{a.IP, b.IP},
+		},
+		"oldest recent server kept for the rotation": {
+			recent:     []net.IP{c.IP, a.IP, b.IP},
+			candidates: []models.OpenVPNConnection{a, b, c},
+			excluded:   []net.IP{c.IP, a.IP},
+		},
+		"current server excluded even if the only candidate": {
+			recent:     []net.IP{a.IP, b.IP},
+			candidates: []models.OpenVPNConnection{a},
+			excluded:   []net.IP{a.IP},
+		},
+		"no candidate": {
+			recent:   []net.IP{a.IP, b.IP},
+			excluded: []net.IP{a.IP},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			excluded := excludeRecentServers(testCase.recent, testCase.candidates)
+
+			assert.Equal(t, testCase.excluded, excluded)
+		})
+	}
+}
+
+func Test_looper_rotationExcludedIPs(t *testing.T) {
+	t.Parallel()
+
+	a := models.OpenVPNConnection{IP: net.IPv4(1, 1, 1, 1)}
+	b := models.OpenVPNConnection{IP: net.IPv4(2, 2, 2, 2)}
+	c := models.OpenVPNConnection{IP: net.IPv4(3, 3, 3, 3)}
+
+	testCases := map[string]struct {
+		recent   []net.IP
+		provider *testProvider
+		excluded []net.IP
+	}{
+		"rotation does not bounce back": {
+			recent:   []net.IP{a.IP},
+			provider: &testProvider{connections: []models.OpenVPNConnection{a, b, c}},
+			excluded: []net.IP{b.IP, a.IP},
+		},
+		"two servers only": {
+			recent:   []net.IP{a.IP},
+			provider: &testProvider{connections: []models.OpenVPNConnection{a, b}},
+			excluded: []net.IP{b.IP},
+		},
+		"candidates error": {
+			recent:   []net.IP{a.IP},
+			provider: &testProvider{err: errors.New("test error")},
+			excluded: []net.IP{b.IP},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			l := &looper{recentServers: testCase.recent}
+
+			excluded := l.rotationExcludedIPs(testCase.provider,
+				configuration.ServerSelection{}, b.IP)
+
+			assert.Equal(t, testCase.excluded, excluded)
+		})
+	}
+}
+
+func Test_looper_setReconnecting(t *testing.T) {
+	t.Parallel()
+
+	l := &looper{state: state{status: constants.Running}}
+
+	l.setReconnecting()
+
+	assert.Equal(t, constants.Starting, l.GetStatus())
+	assert.True(t, l.crashed)
+}
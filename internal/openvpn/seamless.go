@@ -0,0 +1,106 @@
+package openvpn
+
+import (
+	"context"
+	"sync"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+const (
+	// seamlessTUN is the tunnel device alternating with constants.TUN
+	// to have two tunnels up at the same time when switching servers.
+	seamlessTUN = "tun1"
+	// anyTUN matches all tunnel devices in iptables rules.
+	anyTUN = "tun+"
+)
+
+// tunnel is a running OpenVPN process, kept running while connecting
+// to a new server to switch tunnels seamlessly.
+type tunnel struct {
+	device     string
+	connection models.OpenVPNConnection
+	// routed is true if the VPN routes are managed by the loop instead
+	// of OpenVPN, and must be removed once the tunnel is down.
+	routed                   bool
+	cancel                   context.CancelFunc
+	waitError                chan error
+	stdoutLines, stderrLines chan string
+	// superseded is closed once the tunnel is replaced, so its
+	// remaining log lines no longer update the connection state.
+	superseded chan struct{}
+	stopOnce   sync.Once
+}
+
+// nextDevice returns the tunnel device to use next to the device given.
+func nextDevice(device string) string {
+	if device == constants.TUN {
+		return seamlessTUN
+	}
+	return constants.TUN
+}
+
+// setSeamlessSwitch modifies the OpenVPN configuration lines to use
+// the tunnel device given, and to not set the default routes since
+// these are swapped by the loop once the tunnel is up.
+func setSeamlessSwitch(lines []string, device string) (modified []string) {
+	modified = removeDirectives(lines, "dev", "redirect-gateway")
+	return append(modified,
+		"dev "+device,
+		`pull-filter ignore "redirect-gateway"`,
+	)
+}
+
+// switchTunnel routes all traffic through the next tunnel, which must
+// be up, stops the previous tunnel and restricts the firewall to the
+// next tunnel device. The connection tracking entries are not flushed,
+// since this would also drop the connections through the next tunnel.
+func (l *looper) switchTunnel(ctx context.Context, previous, next *tunnel,
+	firewallConnection models.OpenVPNConnection) {
+	if err := l.routing.SetVPNRoutes(next.device, next.connection.IP); err != nil {
+		l.logger.Error(err)
+	}
+	l.logger.Info("switched from server %s to server %s", previous.connection.IP, next.connection.IP)
+
+	l.stopTunnel(previous)
+	if previous.connection.IP.Equal(next.connection.IP) {
+		// the endpoint route shared with the next tunnel was removed
+		if err := l.routing.SetVPNEndpointRoute(next.connection.IP); err != nil {
+			l.logger.Error(err)
+		}
+	}
+
+	if err := l.fw.SetVPNConnection(ctx, firewallConnection); err != nil {
+		l.logger.Error(err)
+	}
+
+	if err := l.fw.SetVPNInterface(ctx, next.device); err != nil {
+		l.logger.Error(err)
+	}
+}
+
+// stopTunnel stops the tunnel OpenVPN process and removes its routes.
+// It is safe to call it more than once.
+func (l *looper) stopTunnel(t *tunnel) {
+	t.stopOnce.Do(func() {
+		close(t.superseded)
+		t.cancel()
+		<-t.waitError
+		close(t.waitError)
+		close(t.stdoutLines)
+		close(t.stderrLines)
+		l.removeTunnelRoutes(t)
+	})
+}
+
+// removeTunnelRoutes removes the VPN routes of the tunnel if
+// they were set by the loop.
+func (l *looper) removeTunnelRoutes(t *tunnel) {
+	if !t.routed {
+		return
+	}
+	if err := l.routing.RemoveVPNRoutes(t.device, t.connection.IP); err != nil {
+		l.logger.Warn(err)
+	}
+}
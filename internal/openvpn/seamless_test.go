@@ -0,0 +1,233 @@
+package openvpn
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/firewall/mock_firewall"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/routing/mock_routing"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_setSeamlessSwitch(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{"client", "dev tun", "redirect-gateway def1", "proto udp"}
+
+	modified := setSeamlessSwitch(lines, "tun1")
+
+	expected := []string{
+		"client",
+		"proto udp",
+		"dev tun1",
+		`pull-filter ignore "redirect-gateway"`,
+	}
+	assert.Equal(t, expected, modified)
+}
+
+func Test_nextDevice(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "tun1", nextDevice("tun0"))
+	assert.Equal(t, "tun0", nextDevice("tun1"))
+}
+
+func Test_looper_prepareSeamlessSwitch(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	connection := models.OpenVPNConnection{IP: net.IP{2, 2, 2, 2}, Port: 1194, Protocol: "udp"}
+
+	testCases := map[string]struct {
+		interfaceErr  error
+		connectionErr error
+		routeErr      error
+		err           error
+	}{
+		"success": {},
+		"interface error": {
+			interfaceErr: errTest,
+			err:          errTest,
+		},
+		"connection error": {
+			connectionErr: errTest,
+			err:           errTest,
+		},
+		"route error": {
+			routeErr: errTest,
+			err:      errTest,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+
+			fw := mock_firewall.NewMockConfigurator(ctrl)
+			routing := mock_routing.NewMockRouting(ctrl)
+			fw.EXPECT().SetVPNInterface(ctx, "tun+").Return(testCase.interfaceErr)
+			if testCase.interfaceErr == nil {
+				fw.EXPECT().SetNextVPNConnection(ctx, connection).Return(testCase.connectionErr)
+				if testCase.connectionErr == nil {
+					routing.EXPECT().SetVPNEndpointRoute(connection.IP).Return(testCase.routeErr)
+				}
+			}
+			l := &looper{fw: fw, routing: routing}
+
+			err := l.prepareSeamlessSwitch(ctx, connection)
+
+			assert.ErrorIs(t, err, testCase.err)
+		})
+	}
+}
+
+func Test_looper_setVPNConnection(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	connection := models.OpenVPNConnection{IP: net.IP{2, 2, 2, 2}, Port: 1194, Protocol: "udp"}
+
+	testCases := map[string]struct {
+		interfaceErr  error
+		connectionErr error
+		err           error
+	}{
+		"success": {},
+		"interface error": {
+			interfaceErr: errTest,
+			err:          errTest,
+		},
+		"connection error": {
+			connectionErr: errTest,
+			err:           errTest,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+
+			fw := mock_firewall.NewMockConfigurator(ctrl)
+			fw.EXPECT().SetVPNInterface(ctx, "tun0").Return(testCase.interfaceErr)
+			if testCase.interfaceErr == nil {
+				fw.EXPECT().SetVPNConnection(ctx, connection).Return(testCase.connectionErr)
+			}
+			l := &looper{fw: fw}
+
+			err := l.setVPNConnection(ctx, connection, "tun0")
+
+			assert.ErrorIs(t, err, testCase.err)
+		})
+	}
+}
+
+func newTestTunnel(device string, ip net.IP, routed bool) *tunnel {
+	waitError := make(chan error, 1)
+	return &tunnel{
+		device:      device,
+		connection:  models.OpenVPNConnection{IP: ip, Port: 1194, Protocol: "udp"},
+		routed:      routed,
+		cancel:      func() { waitError <- nil },
+		waitError:   waitError,
+		stdoutLines: make(chan string),
+		stderrLines: make(chan string),
+		superseded:  make(chan struct{}),
+	}
+}
+
+func Test_looper_switchTunnel(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		previous     *tunnel
+		next         *tunnel
+		prepareMocks func(fw *mock_firewall.MockConfigurator,
+			routing *mock_routing.MockRouting, logger *mock_logging.MockLogger)
+	}{
+		"different servers": {
+			previous: newTestTunnel("tun0", net.IP{1, 1, 1, 1}, false),
+			next:     newTestTunnel("tun1", net.IP{2, 2, 2, 2}, true),
+			prepareMocks: func(fw *mock_firewall.MockConfigurator,
+				routing *mock_routing.MockRouting, logger *mock_logging.MockLogger) {
+				gomock.InOrder(
+					routing.EXPECT().SetVPNRoutes("tun1", net.IP{2, 2, 2, 2}).Return(nil),
+					logger.EXPECT().Info("switched from server %s to server %s",
+						net.IP{1, 1, 1, 1}, net.IP{2, 2, 2, 2}),
+					fw.EXPECT().SetVPNConnection(gomock.Any(), gomock.Any()).Return(nil),
+					fw.EXPECT().SetVPNInterface(gomock.Any(), "tun1").Return(nil),
+				)
+			},
+		},
+		"same server with routed previous tunnel": {
+			previous: newTestTunnel("tun1", net.IP{1, 1, 1, 1}, true),
+			next:     newTestTunnel("tun0", net.IP{1, 1, 1, 1}, true),
+			prepareMocks: func(fw *mock_firewall.MockConfigurator,
+				routing *mock_routing.MockRouting, logger *mock_logging.MockLogger) {
+				gomock.InOrder(
+					routing.EXPECT().SetVPNRoutes("tun0", net.IP{1, 1, 1, 1}).Return(nil),
+					logger.EXPECT().Info("switched from server %s to server %s",
+						net.IP{1, 1, 1, 1}, net.IP{1, 1, 1, 1}),
+					routing.EXPECT().RemoveVPNRoutes("tun1", net.IP{1, 1, 1, 1}).Return(nil),
+					routing.EXPECT().SetVPNEndpointRoute(net.IP{1, 1, 1, 1}).Return(nil),
+					fw.EXPECT().SetVPNConnection(gomock.Any(), gomock.Any()).Return(nil),
+					fw.EXPECT().SetVPNInterface(gomock.Any(), "tun0").Return(nil),
+				)
+			},
+		},
+		"errors are logged": {
+			previous: newTestTunnel("tun0", net.IP{1, 1, 1, 1}, false),
+			next:     newTestTunnel("tun1", net.IP{2, 2, 2, 2}, true),
+			prepareMocks: func(fw *mock_firewall.MockConfigurator,
+				routing *mock_routing.MockRouting, logger *mock_logging.MockLogger) {
+				gomock.InOrder(
+					routing.EXPECT().SetVPNRoutes("tun1", net.IP{2, 2, 2, 2}).Return(errTest),
+					logger.EXPECT().Error(errTest),
+					logger.EXPECT().Info("switched from server %s to server %s",
+						net.IP{1, 1, 1, 1}, net.IP{2, 2, 2, 2}),
+					fw.EXPECT().SetVPNConnection(gomock.Any(), gomock.Any()).Return(errTest),
+					logger.EXPECT().Error(errTest),
+					fw.EXPECT().SetVPNInterface(gomock.Any(), "tun1").Return(errTest),
+					logger.EXPECT().Error(errTest),
+				)
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			fw := mock_firewall.NewMockConfigurator(ctrl)
+			routing := mock_routing.NewMockRouting(ctrl)
+			logger := mock_logging.NewMockLogger(ctrl)
+			testCase.prepareMocks(fw, routing, logger)
+			l := &looper{fw: fw, routing: routing, logger: logger}
+
+			l.switchTunnel(context.Background(), testCase.previous, testCase.next,
+				testCase.next.connection)
+
+			select {
+			case <-testCase.previous.superseded:
+			default:
+				t.Error("previous tunnel is not superseded")
+			}
+			// stopping the tunnel again is a no-op
+			l.stopTunnel(testCase.previous)
+		})
+	}
+}
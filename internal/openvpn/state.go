@@ -1,24 +1,43 @@
 package openvpn
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"sync"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider"
 )
 
 type state struct {
-	status          models.LoopStatus
-	settings        configuration.OpenVPN
-	allServers      models.AllServers
-	portForwarded   uint16
-	statusMu        sync.RWMutex
-	settingsMu      sync.RWMutex
-	allServersMu    sync.RWMutex
-	portForwardedMu sync.RWMutex
+	status     models.LoopStatus
+	settings   configuration.OpenVPN
+	allServers models.AllServers
+	// portsForwarded are the ports forwarded, and
+	// is empty if no port is forwarded.
+	portsForwarded []models.ForwardedPort
+	// portForwardSubscribers are notified of each change
+	// of the port forwarded.
+	portForwardSubscribers map[chan models.PortForwardStatus]struct{}
+	connectionState        models.OpenVPNConnectionState
+	// upSince is the time the tunnel last came up, and is
+	// the zero time if the tunnel is not up.
+	upSince time.Time
+	// connections is the number of times the tunnel came up.
+	connections uint64
+	// paused is true if the tunnel was stopped by Pause
+	// and not started again since.
+	paused            bool
+	statusMu          sync.RWMutex
+	settingsMu        sync.RWMutex
+	allServersMu      sync.RWMutex
+	portForwardedMu   sync.RWMutex
+	pausedMu          sync.RWMutex
+	connectionStateMu sync.RWMutex
 }
 
 func (s *state) setStatusWithLock(status models.LoopStatus) {
@@ -27,13 +46,28 @@ func (s *state) setStatusWithLock(status models.LoopStatus) {
 	s.status = status
 }
 
+func (s *state) setConnectionState(connectionState models.OpenVPNConnectionState) {
+	s.connectionStateMu.Lock()
+	defer s.connectionStateMu.Unlock()
+	wasUp := s.connectionState.State == constants.OpenVPNUp
+	isUp := connectionState.State == constants.OpenVPNUp
+	switch {
+	case isUp && !wasUp:
+		s.upSince = time.Now()
+		s.connections++
+	case !isUp:
+		s.upSince = time.Time{}
+	}
+	s.connectionState = connectionState
+}
+
 func (s *state) getSettingsAndServers() (settings configuration.OpenVPN, allServers models.AllServers) {
 	s.settingsMu.RLock()
 	s.allServersMu.RLock()
 	settings = s.settings
 	allServers = s.allServers
-	s.settingsMu.RLock()
-	s.allServersMu.RLock()
+	s.settingsMu.RUnlock()
+	s.allServersMu.RUnlock()
 	return settings, allServers
 }
 
@@ -97,11 +131,77 @@ func (l *looper) SetSettings(settings configuration.OpenVPN) (outcome string) {
 		return "settings left unchanged"
 	}
 	l.state.settings = settings
+	l.state.settingsMu.Unlock()
+	if l.IsPaused() {
+		return "settings set, applied once resumed"
+	}
 	_, _ = l.SetStatus(constants.Stopped)
 	outcome, _ = l.SetStatus(constants.Running)
 	return outcome
 }
 
+// SwitchServer makes the loop connect to a different server, keeping
+// the current tunnel up until connected if the seamless switch is enabled.
+func (l *looper) SwitchServer() (outcome string) {
+	if status := l.GetStatus(); status != constants.Running {
+		return fmt.Sprintf("cannot switch server: %s", status)
+	}
+	select {
+	case l.switchServer <- struct{}{}:
+		return "switching server"
+	default:
+		return "already switching server"
+	}
+}
+
+var (
+	ErrServerSelectionCustomConfig = errors.New("server selection cannot be set with a custom OpenVPN configuration")
+	ErrVPNProviderUnknown          = errors.New("VPN provider is unknown")
+	ErrServerSelectionNoServer     = errors.New("no server matches the server selection")
+)
+
+// SetServerSelection sets the VPN provider and server selection, and
+// switches to a server matching them if the loop is running.
+func (l *looper) SetServerSelection(providerName string,
+	selection configuration.ServerSelection) (outcome string, err error) {
+	if l.GetSettings().Config != "" {
+		return "", ErrServerSelectionCustomConfig
+	}
+
+	providerConf := provider.New(providerName, l.GetServers(), time.Now)
+	if providerConf == nil {
+		return "", fmt.Errorf("%w: %s", ErrVPNProviderUnknown, providerName)
+	}
+	if _, err := providerConf.GetOpenVPNConnections(selection); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrServerSelectionNoServer, err)
+	}
+
+	l.state.settingsMu.Lock()
+	l.state.settings.Provider.Name = providerName
+	l.state.settings.Provider.ServerSelection = selection
+	l.state.settingsMu.Unlock()
+
+	if l.GetStatus() != constants.Running {
+		return "server selection set", nil
+	}
+	return l.SwitchServer(), nil
+}
+
+// SwitchProtocol switches the protocol of the server selection
+// between UDP and TCP, and connects to a server using it.
+func (l *looper) SwitchProtocol() (outcome string, err error) {
+	settings := l.GetSettings()
+	selection := settings.Provider.ServerSelection
+	if selection.Protocol == constants.TCP {
+		selection.Protocol = constants.UDP
+	} else {
+		selection.Protocol = constants.TCP
+	}
+	selection.CustomPort = 0 // the custom port usually depends on the protocol
+	l.logger.Info("switching protocol to %s", selection.Protocol)
+	return l.SetServerSelection(settings.Provider.Name, selection)
+}
+
 func (l *looper) GetServers() (servers models.AllServers) {
 	l.state.allServersMu.RLock()
 	defer l.state.allServersMu.RUnlock()
@@ -117,5 +217,24 @@ func (l *looper) SetServers(servers models.AllServers) {
 func (l *looper) GetPortForwarded() (port uint16) {
 	l.state.portForwardedMu.RLock()
 	defer l.state.portForwardedMu.RUnlock()
-	return l.state.portForwarded
+	if len(l.state.portsForwarded) == 0 {
+		return 0
+	}
+	return l.state.portsForwarded[0].Port
+}
+
+func (l *looper) GetConnectionState() (connectionState models.OpenVPNConnectionState) {
+	l.state.connectionStateMu.RLock()
+	defer l.state.connectionStateMu.RUnlock()
+	return l.state.connectionState
+}
+
+func (l *looper) GetTunnelStats() (stats models.TunnelStats) {
+	l.state.connectionStateMu.RLock()
+	defer l.state.connectionStateMu.RUnlock()
+	stats.UpSince = l.state.upSince
+	if l.state.connections > 1 {
+		stats.Reconnects = l.state.connections - 1
+	}
+	return stats
 }
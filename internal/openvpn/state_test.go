@@ -0,0 +1,185 @@
+package openvpn
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// assertUnlocked fails the test if the mutex given cannot be
+// locked for writing within a second.
+func assertUnlocked(t *testing.T, mutex *sync.RWMutex, name string) (unlocked bool) {
+	t.Helper()
+	locked := make(chan struct{})
+	go func() {
+		mutex.Lock()
+		mutex.Unlock()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		return true
+	case <-time.After(time.Second):
+		t.Errorf("%s is still locked", name)
+		return false
+	}
+}
+
+func Test_state_getSettingsAndServers(t *testing.T) {
+	t.Parallel()
+
+	s := &state{
+		settings:   configuration.OpenVPN{User: "user"},
+		allServers: models.AllServers{Mullvad: models.MullvadServers{Version: 1}},
+	}
+
+	settings, allServers := s.getSettingsAndServers()
+
+	assert.Equal(t, s.settings, settings)
+	assert.Equal(t, s.allServers, allServers)
+	assertUnlocked(t, &s.settingsMu, "settings mutex")
+	assertUnlocked(t, &s.allServersMu, "servers mutex")
+}
+
+func Test_looper_SetSettings(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		settings configuration.OpenVPN
+		paused   bool
+		restart  bool
+		outcome  string
+	}{
+		"settings unchanged": {
+			outcome: "settings left unchanged",
+		},
+		"settings changed while paused": {
+			settings: configuration.OpenVPN{User: "user"},
+			paused:   true,
+			outcome:  "settings set, applied once resumed",
+		},
+		"settings changed": {
+			settings: configuration.OpenVPN{User: "user"},
+			restart:  true,
+			outcome:  "running",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			l := &looper{
+				state: state{
+					status: constants.Stopped,
+					paused: testCase.paused,
+				},
+				start:   make(chan struct{}),
+				running: make(chan models.LoopStatus),
+			}
+
+			if testCase.restart {
+				go func() {
+					<-l.start
+					l.running <- constants.Running
+				}()
+			}
+
+			outcome := l.SetSettings(testCase.settings)
+
+			assert.Equal(t, testCase.outcome, outcome)
+			if assertUnlocked(t, &l.state.settingsMu, "settings mutex") {
+				assert.Equal(t, testCase.settings, l.GetSettings())
+			}
+		})
+	}
+}
+
+func Test_looper_SetServerSelection(t *testing.T) {
+	t.Parallel()
+
+	allServers := models.AllServers{
+		Mullvad: models.MullvadServers{Servers: []models.MullvadServer{
+			{Country: "Sweden", City: "Gothenburg", IPs: []net.IP{{1, 1, 1, 1}}},
+		}},
+	}
+	settings := configuration.OpenVPN{}
+	settings.Provider.Name = constants.Mullvad
+
+	testCases := map[string]struct {
+		settings     configuration.OpenVPN
+		status       models.LoopStatus
+		providerName string
+		selection    configuration.ServerSelection
+		outcome      string
+		err          error
+		switched     bool
+	}{
+		"custom configuration": {
+			settings:     configuration.OpenVPN{Config: "/gluetun/custom.conf"},
+			providerName: constants.Mullvad,
+			err:          ErrServerSelectionCustomConfig,
+		},
+		"unknown provider": {
+			settings:     settings,
+			providerName: "unknown",
+			err:          ErrVPNProviderUnknown,
+		},
+		"no server matching": {
+			settings:     settings,
+			providerName: constants.Mullvad,
+			selection:    configuration.ServerSelection{Countries: []string{"france"}},
+			err:          ErrServerSelectionNoServer,
+		},
+		"set while stopped": {
+			settings:     settings,
+			status:       constants.Stopped,
+			providerName: constants.Mullvad,
+			selection:    configuration.ServerSelection{Countries: []string{"sweden"}},
+			outcome:      "server selection set",
+		},
+		"set while running": {
+			settings:     settings,
+			status:       constants.Running,
+			providerName: constants.Mullvad,
+			selection:    configuration.ServerSelection{Countries: []string{"sweden"}},
+			outcome:      "switching server",
+			switched:     true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			l := &looper{
+				state: state{
+					status:     testCase.status,
+					settings:   testCase.settings,
+					allServers: allServers,
+				},
+				switchServer: make(chan struct{}, 1),
+			}
+
+			outcome, err := l.SetServerSelection(testCase.providerName, testCase.selection)
+
+			assert.ErrorIs(t, err, testCase.err)
+			assert.Equal(t, testCase.outcome, outcome)
+			expectedSettings := testCase.settings
+			if testCase.err == nil {
+				expectedSettings.Provider.Name = testCase.providerName
+				expectedSettings.Provider.ServerSelection = testCase.selection
+			}
+			assert.Equal(t, expectedSettings, l.GetSettings())
+			assert.Equal(t, testCase.switched, len(l.switchServer) == 1)
+		})
+	}
+}
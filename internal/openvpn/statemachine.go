@@ -0,0 +1,44 @@
+package openvpn
+
+import (
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// nextConnectionState returns the OpenVPN connection state resulting from
+// the OpenVPN log line given, and the current state if the line does not
+// change it. A failure is set when the connection goes down because of
+// an authentication failure, a TLS negotiation timeout or a route error.
+func nextConnectionState(current models.OpenVPNConnectionState, line string) (
+	next models.OpenVPNConnectionState) {
+	switch {
+	case strings.Contains(line, "AUTH_FAILED"):
+		return models.OpenVPNConnectionState{State: constants.OpenVPNDown, Failure: constants.OpenVPNAuthFailed}
+	case strings.Contains(line, "TLS key negotiation failed to occur"),
+		strings.Contains(line, "TLS handshake failed"):
+		return models.OpenVPNConnectionState{State: constants.OpenVPNDown, Failure: constants.OpenVPNTLSTimeout}
+	case strings.Contains(line, "route add command failed"),
+		strings.Contains(line, "route addition failed"),
+		strings.Contains(line, "Cannot open TUN/TAP dev"):
+		return models.OpenVPNConnectionState{State: constants.OpenVPNDown, Failure: constants.OpenVPNRouteError}
+	case strings.Contains(line, "process exiting"),
+		strings.Contains(line, "Exiting due to fatal error"):
+		return models.OpenVPNConnectionState{State: constants.OpenVPNExiting, Failure: current.Failure}
+	case strings.Contains(line, "process restarting"),
+		strings.Contains(line, "Inactivity timeout"):
+		return models.OpenVPNConnectionState{State: constants.OpenVPNDown, Failure: current.Failure}
+	case strings.Contains(line, "Initialization Sequence Completed"):
+		return models.OpenVPNConnectionState{State: constants.OpenVPNUp}
+	case strings.Contains(line, "TLS: Initial packet from"),
+		strings.Contains(line, "Peer Connection Initiated"):
+		return models.OpenVPNConnectionState{State: constants.OpenVPNAuthenticating, Failure: current.Failure}
+	case strings.Contains(line, "Attempting to establish TCP connection"),
+		strings.Contains(line, "UDP link remote"),
+		strings.Contains(line, "TCP/UDP: Preserving recently used remote address"):
+		return models.OpenVPNConnectionState{State: constants.OpenVPNConnecting, Failure: current.Failure}
+	default:
+		return current
+	}
+}
@@ -0,0 +1,68 @@
+package openvpn
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_nextConnectionState(t *testing.T) {
+	t.Parallel()
+	down := models.OpenVPNConnectionState{State: constants.OpenVPNDown, Failure: constants.OpenVPNTLSTimeout}
+	testCases := map[string]struct {
+		current models.OpenVPNConnectionState
+		line    string
+		next    models.OpenVPNConnectionState
+	}{
+		"unrelated line": {
+			current: down,
+			line:    "some line",
+			next:    down,
+		},
+		"connecting keeps failure": {
+			current: down,
+			line:    "UDP link remote: [AF_INET]1.2.3.4:1194",
+			next:    models.OpenVPNConnectionState{State: constants.OpenVPNConnecting, Failure: constants.OpenVPNTLSTimeout},
+		},
+		"authenticating": {
+			current: models.OpenVPNConnectionState{State: constants.OpenVPNConnecting},
+			line:    "TLS: Initial packet from [AF_INET]1.2.3.4:1194, sid=1 2",
+			next:    models.OpenVPNConnectionState{State: constants.OpenVPNAuthenticating},
+		},
+		"up clears failure": {
+			current: down,
+			line:    "Initialization Sequence Completed",
+			next:    models.OpenVPNConnectionState{State: constants.OpenVPNUp},
+		},
+		"auth failed": {
+			current: models.OpenVPNConnectionState{State: constants.OpenVPNAuthenticating},
+			line:    "AUTH: Received control message: AUTH_FAILED",
+			next:    models.OpenVPNConnectionState{State: constants.OpenVPNDown, Failure: constants.OpenVPNAuthFailed},
+		},
+		"tls timeout": {
+			current: models.OpenVPNConnectionState{State: constants.OpenVPNConnecting},
+			line:    "TLS Error: TLS key negotiation failed to occur within 60 seconds (check your network connectivity)",
+			next:    down,
+		},
+		"route error": {
+			current: models.OpenVPNConnectionState{State: constants.OpenVPNUp},
+			line:    "ERROR: Linux route add command failed: external program exited with status: 2",
+			next:    models.OpenVPNConnectionState{State: constants.OpenVPNDown, Failure: constants.OpenVPNRouteError},
+		},
+		"exiting": {
+			current: models.OpenVPNConnectionState{State: constants.OpenVPNUp},
+			line:    "SIGTERM[hard,] received, process exiting",
+			next:    models.OpenVPNConnectionState{State: constants.OpenVPNExiting},
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			next := nextConnectionState(testCase.current, testCase.line)
+			assert.Equal(t, testCase.next, next)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package openvpn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+var ErrStaticServerNoIP = errors.New("no IP address found for static server")
+
+const staticServerResolveTimeout = 5 * time.Second
+
+// resolveStaticServer resolves the static server hostname using the resolver
+// through the default interface, since the VPN may not be up or may be
+// connected to a previous IP address of the server. The resolver traffic
+// is only allowed through the firewall while resolving.
+func (l *looper) resolveStaticServer(ctx context.Context,
+	settings configuration.StaticServer) (ip net.IP, err error) {
+	defaultInterface, _, err := l.routing.DefaultRoute()
+	if err != nil {
+		return nil, err
+	}
+
+	const dnsPort = 53
+	resolverConnection := models.OpenVPNConnection{
+		IP:       settings.Resolver,
+		Port:     dnsPort,
+		Protocol: constants.UDP,
+	}
+	if err := l.fw.SetResolverConnection(ctx, resolverConnection); err != nil {
+		return nil, err
+	}
+	defer func() {
+		// Remove the plaintext DNS traffic rule once the hostname is resolved.
+		// The context may be canceled already, so a new one is used.
+		if err := l.fw.SetResolverConnection(context.Background(), models.OpenVPNConnection{}); err != nil {
+			l.logger.Error("cannot remove resolver connection from firewall: %s", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, staticServerResolveTimeout)
+	defer cancel()
+	resolver := newInterfaceResolver(defaultInterface, resolverConnection)
+	ips, err := resolver.LookupIP(ctx, "ip", settings.Hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	return pickStaticServerIP(ips, settings.Hostname)
+}
+
+// pickStaticServerIP returns the first IPv4 address, or the first IPv6
+// address if there is no IPv4 address.
+func pickStaticServerIP(ips []net.IP, hostname string) (ip net.IP, err error) {
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrStaticServerNoIP, hostname)
+	}
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			return ip, nil
+		}
+	}
+	return ips[0], nil
+}
+
+// newInterfaceResolver returns a resolver sending its queries to the
+// resolver connection through the network interface given.
+func newInterfaceResolver(intf string, connection models.OpenVPNConnection) *net.Resolver {
+	dialer := net.Dialer{
+		Control: func(network, address string, c syscall.RawConn) (err error) {
+			controlErr := c.Control(func(fd uintptr) {
+				err = syscall.SetsockoptString(int(fd), syscall.SOL_SOCKET, syscall.SO_BINDTODEVICE, intf)
+			})
+			if controlErr != nil {
+				return controlErr
+			}
+			return err
+		},
+	}
+	address := net.JoinHostPort(connection.IP.String(), strconv.Itoa(int(connection.Port)))
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, connection.Protocol, address)
+		},
+	}
+}
+
+// newStaticServerTicker returns a ticker to resolve the static server
+// hostname periodically, or a stopped ticker if it is disabled.
+func newStaticServerTicker(settings configuration.StaticServer) (ticker *time.Ticker) {
+	if settings.Hostname == "" || settings.CheckPeriod == 0 {
+		ticker = time.NewTicker(time.Hour)
+		ticker.Stop()
+		return ticker
+	}
+	return time.NewTicker(settings.CheckPeriod)
+}
+
+// checkStaticServer resolves the static server hostname and returns its
+// IP address, or nil if it cannot be resolved.
+func (l *looper) checkStaticServer(ctx context.Context,
+	settings configuration.StaticServer) (ip net.IP) {
+	ip, err := l.resolveStaticServer(ctx, settings)
+	if err != nil {
+		l.logger.Warn("cannot resolve static server %s: %s", settings.Hostname, err)
+		return nil
+	}
+	return ip
+}
@@ -0,0 +1,118 @@
+package openvpn
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/firewall/mock_firewall"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/routing/mock_routing"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_pickStaticServerIP(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		ips []net.IP
+		ip  net.IP
+		err error
+	}{
+		"no IP": {
+			err: errors.New("no IP address found for static server: vpn.example.com"),
+		},
+		"IPv4 preferred": {
+			ips: []net.IP{net.ParseIP("2001:db8::1"), net.IPv4(1, 2, 3, 4)},
+			ip:  net.IPv4(1, 2, 3, 4),
+		},
+		"IPv6 only": {
+			ips: []net.IP{net.ParseIP("2001:db8::1")},
+			ip:  net.ParseIP("2001:db8::1"),
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ip, err := pickStaticServerIP(testCase.ips, "vpn.example.com")
+			if testCase.err != nil {
+				assert.EqualError(t, err, testCase.err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.ip, ip)
+		})
+	}
+}
+
+func Test_looper_resolveStaticServer(t *testing.T) {
+	t.Parallel()
+
+	settings := configuration.StaticServer{
+		Hostname: "vpn.example.com",
+		Resolver: net.IPv4(127, 0, 0, 1),
+	}
+	resolverConnection := models.OpenVPNConnection{
+		IP:       settings.Resolver,
+		Port:     53,
+		Protocol: constants.UDP,
+	}
+	errDummy := errors.New("dummy")
+
+	testCases := map[string]struct {
+		firewallErr   error
+		removeErr     error
+		errorLogged   bool
+		errorExpected error
+	}{
+		"resolver connection removed after resolving": {},
+		"resolver connection not set": {
+			firewallErr:   errDummy,
+			errorExpected: errDummy,
+		},
+		"resolver connection removal failed": {
+			removeErr:   errDummy,
+			errorLogged: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+
+			routing := mock_routing.NewMockRouting(ctrl)
+			routing.EXPECT().DefaultRoute().Return("lo", nil, nil)
+
+			fw := mock_firewall.NewMockConfigurator(ctrl)
+			setCall := fw.EXPECT().SetResolverConnection(ctx, resolverConnection).
+				Return(testCase.firewallErr)
+			if testCase.firewallErr == nil {
+				fw.EXPECT().SetResolverConnection(gomock.Any(), models.OpenVPNConnection{}).
+					Return(testCase.removeErr).After(setCall)
+			}
+
+			logger := mock_logging.NewMockLogger(ctrl)
+			if testCase.errorLogged {
+				logger.EXPECT().Error("cannot remove resolver connection from firewall: %s", errDummy)
+			}
+
+			l := &looper{routing: routing, fw: fw, logger: logger}
+
+			// The resolution itself fails or succeeds depending on the
+			// environment, only the firewall changes are checked.
+			_, err := l.resolveStaticServer(ctx, settings)
+			if testCase.errorExpected != nil {
+				require.ErrorIs(t, err, testCase.errorExpected)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package openvpn
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/os"
+)
+
+// readStickyServer returns the IP address of the last server connected to,
+// or nil if it was never persisted to file.
+func (l *looper) readStickyServer() (ip net.IP, err error) {
+	file, err := l.openFile(constants.StickyServer, os.O_RDONLY, 0)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var connection models.OpenVPNConnection
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&connection); err != nil {
+		_ = file.Close()
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+	return connection.IP, nil
+}
+
+func (l *looper) writeStickyServer(connection models.OpenVPNConnection) error {
+	file, err := l.openFile(constants.StickyServer, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	if err := encoder.Encode(connection); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
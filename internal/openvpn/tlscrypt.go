@@ -0,0 +1,15 @@
+package openvpn
+
+import "github.com/qdm12/gluetun/internal/configuration"
+
+// setTLSCrypt modifies the OpenVPN configuration lines to use the tls-crypt
+// or tls-crypt-v2 key of the settings given, replacing any tls-auth,
+// tls-crypt or tls-crypt-v2 key already present.
+func setTLSCrypt(lines []string, options configuration.ExtraConfigOptions) (modified []string) {
+	modified = removeDirectives(lines, "tls-auth", "key-direction", "tls-crypt", "tls-crypt-v2")
+	directive, key := "tls-crypt", options.TLSCrypt
+	if options.TLSCryptV2 != "" {
+		directive, key = "tls-crypt-v2", options.TLSCryptV2
+	}
+	return append(modified, "<"+directive+">", key, "</"+directive+">")
+}
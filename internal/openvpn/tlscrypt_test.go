@@ -0,0 +1,32 @@
+package openvpn
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_setTLSCrypt(t *testing.T) {
+	t.Parallel()
+	lines := []string{
+		"client",
+		"key-direction 1",
+		"<tls-auth>",
+		"old",
+		"</tls-auth>",
+		"remote 1.2.3.4 1194",
+	}
+	options := configuration.ExtraConfigOptions{
+		TLSCryptV2: "key",
+	}
+	expected := []string{
+		"client",
+		"remote 1.2.3.4 1194",
+		"<tls-crypt-v2>",
+		"key",
+		"</tls-crypt-v2>",
+	}
+	modified := setTLSCrypt(lines, options)
+	assert.Equal(t, expected, modified)
+}
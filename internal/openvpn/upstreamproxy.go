@@ -0,0 +1,42 @@
+package openvpn
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// setUpstreamProxy modifies the OpenVPN configuration lines to connect
+// through the upstream proxy, and returns the proxy connection.
+func setUpstreamProxy(lines []string, proxy configuration.UpstreamProxy) (
+	modified []string, proxyConnection models.OpenVPNConnection) {
+	for _, line := range lines {
+		if strings.HasPrefix(line, "http-proxy") || strings.HasPrefix(line, "socks-proxy") {
+			continue
+		}
+		modified = append(modified, line)
+	}
+
+	directive := "http-proxy"
+	if proxy.Type == constants.SOCKSProxyType {
+		directive = "socks-proxy"
+	}
+	line := directive + " " + proxy.IP.String() + " " + strconv.Itoa(int(proxy.Port))
+	if proxy.User != "" {
+		line += " " + constants.OpenVPNProxyAuthConf
+		if proxy.Type == constants.HTTPProxyType {
+			line += " basic"
+		}
+	}
+	modified = append(modified, line)
+
+	proxyConnection = models.OpenVPNConnection{
+		IP:       proxy.IP,
+		Port:     proxy.Port,
+		Protocol: constants.TCP,
+	}
+	return modified, proxyConnection
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+const (
+	nordvpnAPIHost = "api.nordvpn.com"
+	piaAPIHost     = "privateinternetaccess.com"
+	piaWWWHost     = "www.privateinternetaccess.com"
+)
+
+// BootstrapHosts returns the hosts of the VPN provider APIs
+// to reach before the VPN is up, for the settings given.
+func BootstrapHosts(settings configuration.Settings) (hosts []string) {
+	switch {
+	case settings.VPNType == constants.Wireguard &&
+		settings.Wireguard.Provider == constants.Nordvpn:
+		return []string{nordvpnAPIHost}
+	case settings.VPNType == constants.OpenVPN &&
+		settings.OpenVPN.Provider.Name == constants.PrivateInternetAccess &&
+		settings.OpenVPN.Provider.ServerSelection.DedicatedIPToken != "":
+		return []string{piaAPIHost, piaWWWHost}
+	default:
+		return nil
+	}
+}
+
+type hostResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// BootstrapRules resolves the hosts given and returns the outbound rules
+// allowing HTTPS traffic to their IP addresses. The IP addresses are pinned
+// for the bootstrap, so the hosts must be resolved before the firewall is enabled.
+func BootstrapRules(ctx context.Context, resolver hostResolver,
+	hosts []string) (rules []models.OutboundRule, err error) {
+	seen := make(map[string]struct{})
+	for _, host := range hosts {
+		addresses, err := resolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve bootstrap host %s: %w", host, err)
+		}
+		for _, address := range addresses {
+			key := address.IP.String()
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			rules = append(rules, models.OutboundRule{
+				Subnet:   ipToSubnet(address.IP),
+				Port:     443, //nolint:gomnd
+				Protocol: constants.TCP,
+			})
+		}
+	}
+	return rules, nil
+}
+
+// ipToSubnet returns the single IP address subnet of the IP address given.
+func ipToSubnet(ip net.IP) (subnet net.IPNet) {
+	bits := 8 * net.IPv6len
+	if ipv4 := ip.To4(); ipv4 != nil {
+		ip, bits = ipv4, 8*net.IPv4len
+	}
+	return net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+}
@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BootstrapHosts(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		settings configuration.Settings
+		hosts    []string
+	}{
+		"no provider data": {
+			settings: configuration.Settings{
+				VPNType: constants.OpenVPN,
+				OpenVPN: configuration.OpenVPN{
+					Provider: configuration.Provider{Name: constants.Mullvad},
+				},
+			},
+		},
+		"nordlynx": {
+			settings: configuration.Settings{
+				VPNType: constants.Wireguard,
+				Wireguard: configuration.Wireguard{
+					Provider: constants.Nordvpn,
+				},
+			},
+			hosts: []string{"api.nordvpn.com"},
+		},
+		"pia without dedicated IP": {
+			settings: configuration.Settings{
+				VPNType: constants.OpenVPN,
+				OpenVPN: configuration.OpenVPN{
+					Provider: configuration.Provider{Name: constants.PrivateInternetAccess},
+				},
+			},
+		},
+		"pia dedicated IP": {
+			settings: configuration.Settings{
+				VPNType: constants.OpenVPN,
+				OpenVPN: configuration.OpenVPN{
+					Provider: configuration.Provider{
+						Name: constants.PrivateInternetAccess,
+						ServerSelection: configuration.ServerSelection{
+							DedicatedIPToken: "token",
+						},
+					},
+				},
+			},
+			hosts: []string{"privateinternetaccess.com", "www.privateinternetaccess.com"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			hosts := BootstrapHosts(testCase.settings)
+
+			assert.Equal(t, testCase.hosts, hosts)
+		})
+	}
+}
+
+type fakeResolver map[string][]net.IPAddr
+
+var errHostNotFound = errors.New("host not found")
+
+func (f fakeResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	addresses, ok := f[host]
+	if !ok {
+		return nil, errHostNotFound
+	}
+	return addresses, nil
+}
+
+func Test_BootstrapRules(t *testing.T) {
+	t.Parallel()
+
+	resolver := fakeResolver{
+		"a.com": {{IP: net.IPv4(1, 1, 1, 1)}, {IP: net.ParseIP("2001:db8::1")}},
+		"b.com": {{IP: net.IPv4(1, 1, 1, 1)}, {IP: net.IPv4(2, 2, 2, 2)}},
+	}
+
+	testCases := map[string]struct {
+		hosts      []string
+		rules      []models.OutboundRule
+		errWrapped error
+		errMessage string
+	}{
+		"no host": {},
+		"hosts with duplicate IP addresses": {
+			hosts: []string{"a.com", "b.com"},
+			rules: []models.OutboundRule{
+				{
+					Subnet:   net.IPNet{IP: net.IP{1, 1, 1, 1}, Mask: net.CIDRMask(32, 32)},
+					Port:     443,
+					Protocol: "tcp",
+				},
+				{
+					Subnet:   net.IPNet{IP: net.ParseIP("2001:db8::1"), Mask: net.CIDRMask(128, 128)},
+					Port:     443,
+					Protocol: "tcp",
+				},
+				{
+					Subnet:   net.IPNet{IP: net.IP{2, 2, 2, 2}, Mask: net.CIDRMask(32, 32)},
+					Port:     443,
+					Protocol: "tcp",
+				},
+			},
+		},
+		"unresolvable host": {
+			hosts:      []string{"a.com", "c.com"},
+			errWrapped: errHostNotFound,
+			errMessage: "cannot resolve bootstrap host c.com: host not found",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rules, err := BootstrapRules(context.Background(), resolver, testCase.hosts)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				require.Error(t, err)
+				assert.Equal(t, testCase.errMessage, err.Error())
+			}
+			assert.Equal(t, testCase.rules, rules)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+// cipherProfile describes the OpenVPN data channel ciphers and
+// auth digest supported by the servers of a VPN provider.
+type cipherProfile struct {
+	// dataCiphers are the ciphers negotiated with OpenVPN 2.4+ servers,
+	// in order of preference.
+	dataCiphers []string
+	// fallbackCipher is used with servers not supporting cipher negotiation.
+	fallbackCipher string
+	// auth is the auth digest, left empty to use the OpenVPN default.
+	auth string
+}
+
+//nolint:gochecknoglobals
+var cipherProfiles = map[string]cipherProfile{
+	constants.Cyberghost: {
+		dataCiphers:    []string{aes256gcm, aes256cbc, aes128gcm},
+		fallbackCipher: aes256cbc,
+		auth:           sha256,
+	},
+	constants.Fastestvpn: {
+		dataCiphers:    []string{aes256cbc},
+		fallbackCipher: aes256cbc,
+		auth:           sha256,
+	},
+	constants.HideMyAss: {
+		dataCiphers:    []string{aes256cbc},
+		fallbackCipher: aes256cbc,
+	},
+	constants.Mullvad: {
+		dataCiphers:    []string{aes256gcm, aes256cbc},
+		fallbackCipher: aes256cbc,
+	},
+	constants.Nordvpn: {
+		dataCiphers:    []string{aes256gcm, aes256cbc},
+		fallbackCipher: aes256cbc,
+		auth:           sha512,
+	},
+	constants.Privado: {
+		dataCiphers:    []string{aes256cbc},
+		fallbackCipher: aes256cbc,
+		auth:           sha256,
+	},
+	// Fallback cipher and auth depend on the encryption preset
+	constants.PrivateInternetAccess: {
+		dataCiphers: []string{aes128gcm, aes256gcm, aes128cbc, aes256cbc},
+	},
+	constants.Privatevpn: {
+		dataCiphers:    []string{aes128gcm},
+		fallbackCipher: aes128gcm,
+		auth:           sha256,
+	},
+	constants.Purevpn: {
+		dataCiphers:    []string{aes256gcm, aes256cbc},
+		fallbackCipher: aes256cbc,
+	},
+	constants.Surfshark: {
+		dataCiphers:    []string{aes256gcm, aes256cbc},
+		fallbackCipher: aes256gcm,
+		auth:           sha512,
+	},
+	constants.Torguard: {
+		dataCiphers:    []string{aes256gcm, aes128gcm, aes256cbc, aes128cbc},
+		fallbackCipher: aes256gcm,
+		auth:           sha256,
+	},
+	constants.Vyprvpn: {
+		dataCiphers:    []string{aes256cbc},
+		fallbackCipher: aes256cbc,
+		auth:           sha256,
+	},
+	constants.Windscribe: {
+		dataCiphers:    []string{aes256gcm, aes256cbc, aes128gcm},
+		fallbackCipher: aes256cbc,
+		auth:           sha512,
+	},
+}
+
+// lines returns the OpenVPN configuration lines to negotiate the data cipher
+// and set the auth digest. The cipher and auth given override the profile
+// ones if they are set.
+func (p cipherProfile) lines(cipher, auth string) (lines []string) {
+	dataCiphers := p.dataCiphers
+	fallbackCipher := p.fallbackCipher
+	if cipher != "" {
+		dataCiphers = []string{cipher}
+		fallbackCipher = cipher
+	}
+	if auth == "" {
+		auth = p.auth
+	}
+
+	lines = []string{
+		"data-ciphers-fallback " + fallbackCipher,
+		"data-ciphers " + strings.Join(dataCiphers, ":"),
+	}
+	if auth != "" {
+		lines = append(lines, "auth "+auth)
+	}
+	return lines
+}
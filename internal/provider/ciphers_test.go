@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_cipherProfile_lines(t *testing.T) {
+	t.Parallel()
+	profile := cipherProfile{
+		dataCiphers:    []string{aes256gcm, aes256cbc},
+		fallbackCipher: aes256cbc,
+		auth:           sha256,
+	}
+	testCases := map[string]struct {
+		profile cipherProfile
+		cipher  string
+		auth    string
+		lines   []string
+	}{
+		"profile values": {
+			profile: profile,
+			lines: []string{
+				"data-ciphers-fallback aes-256-cbc",
+				"data-ciphers aes-256-gcm:aes-256-cbc",
+				"auth sha256",
+			},
+		},
+		"overridden values": {
+			profile: profile,
+			cipher:  "chacha20-poly1305",
+			auth:    "sha512",
+			lines: []string{
+				"data-ciphers-fallback chacha20-poly1305",
+				"data-ciphers chacha20-poly1305",
+				"auth sha512",
+			},
+		},
+		"no auth": {
+			profile: cipherProfile{dataCiphers: []string{aes256cbc}, fallbackCipher: aes256cbc},
+			lines: []string{
+				"data-ciphers-fallback aes-256-cbc",
+				"data-ciphers aes-256-cbc",
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			lines := testCase.profile.lines(testCase.cipher, testCase.auth)
+			assert.Equal(t, testCase.lines, lines)
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package provider
 
 const (
+	aes128cbc = "aes-128-cbc"
 	aes256cbc = "aes-256-cbc"
 	aes128gcm = "aes-128-gcm"
 	aes256gcm = "aes-256-gcm"
+	sha1      = "sha1"
 	sha256    = "sha256"
+	sha512    = "sha512"
 )
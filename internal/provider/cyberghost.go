@@ -7,7 +7,7 @@ import (
 	"net"
 	"net/http"
 	"strconv"
-	"strings"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/gluetun/internal/constants"
@@ -20,58 +20,68 @@ import (
 type cyberghost struct {
 	servers    []models.CyberghostServer
 	randSource rand.Source
+	latencies  models.IPLatencies
 }
 
-func newCyberghost(servers []models.CyberghostServer, timeNow timeNowFunc) *cyberghost {
+func newCyberghost(servers []models.CyberghostServer, latencies models.IPLatencies,
+	timeNow timeNowFunc) *cyberghost {
 	return &cyberghost{
 		servers:    servers,
 		randSource: rand.NewSource(timeNow().UnixNano()),
+		latencies:  latencies,
 	}
 }
 
-func (c *cyberghost) filterServers(regions []string, group string) (servers []models.CyberghostServer) {
-	for _, server := range c.servers {
-		switch {
-		case len(group) > 0 && !strings.EqualFold(group, server.Group),
-			filterByPossibilities(server.Region, regions):
-		default:
+func (c *cyberghost) filterServers(selection configuration.ServerSelection) (
+	servers []models.CyberghostServer, err error) {
+	filters := selectionFilters(selection)
+	attributes := make([]serverAttributes, len(c.servers))
+	for i, server := range c.servers {
+		attributes[i] = serverAttributes{
+			region: server.Region, group: server.Group, tcp: true, udp: true,
+		}
+		if !filterServer(filters, attributes[i]) {
 			servers = append(servers, server)
 		}
 	}
-	return servers
+	if len(servers) == 0 {
+		return nil, noServerFoundError(filters, attributes)
+	}
+	return servers, nil
 }
 
 func (c *cyberghost) GetOpenVPNConnection(selection configuration.ServerSelection) (
 	connection models.OpenVPNConnection, err error) {
+	connections, err := c.GetOpenVPNConnections(selection)
+	if err != nil {
+		return connection, err
+	}
+	return pickConnection(connections, selection, c.randSource, c.latencies), nil
+}
+
+func (c *cyberghost) GetOpenVPNConnections(selection configuration.ServerSelection) (
+	connections []models.OpenVPNConnection, err error) {
 	const httpsPort = 443
 	if selection.TargetIP != nil {
-		return models.OpenVPNConnection{IP: selection.TargetIP, Port: httpsPort, Protocol: selection.Protocol}, nil
+		return []models.OpenVPNConnection{{IP: selection.TargetIP, Port: httpsPort, Protocol: selection.Protocol}}, nil
 	}
 
-	servers := c.filterServers(selection.Regions, selection.Group)
-	if len(servers) == 0 {
-		return connection,
-			fmt.Errorf("no server found for regions %s and group %q", commaJoin(selection.Regions), selection.Group)
+	servers, err := c.filterServers(selection)
+	if err != nil {
+		return nil, err
 	}
 
-	var connections []models.OpenVPNConnection
 	for _, server := range servers {
 		for _, IP := range server.IPs {
 			connections = append(connections, models.OpenVPNConnection{IP: IP, Port: httpsPort, Protocol: selection.Protocol})
 		}
 	}
 
-	return pickRandomConnection(connections, c.randSource), nil
+	return connections, nil
 }
 
 func (c *cyberghost) BuildConf(connection models.OpenVPNConnection,
 	username string, settings configuration.OpenVPN) (lines []string) {
-	if len(settings.Cipher) == 0 {
-		settings.Cipher = aes256cbc
-	}
-	if len(settings.Auth) == 0 {
-		settings.Auth = sha256
-	}
 	lines = []string{
 		"client",
 		"dev tun",
@@ -86,7 +96,8 @@ func (c *cyberghost) BuildConf(connection models.OpenVPNConnection,
 
 		// Cyberghost specific
 		// "redirect-gateway def1",
-		"ncp-disable",
+		// ncp-disable is not set since it prevents negotiating the data
+		// cipher from the cipher profile, and is deprecated since OpenVPN 2.5
 		"explicit-exit-notify 2",
 		"script-security 2",
 		"route-delay 5",
@@ -103,13 +114,9 @@ func (c *cyberghost) BuildConf(connection models.OpenVPNConnection,
 		fmt.Sprintf("auth-user-pass %s", constants.OpenVPNAuthConf),
 		fmt.Sprintf("proto %s", connection.Protocol),
 		fmt.Sprintf("remote %s %d", connection.IP, connection.Port),
-		"data-ciphers-fallback " + settings.Cipher,
-		"data-ciphers " + settings.Cipher,
-		fmt.Sprintf("auth %s", settings.Auth),
-	}
-	if strings.HasSuffix(settings.Cipher, "-gcm") {
-		lines = append(lines, "ncp-ciphers AES-256-GCM:AES-256-CBC:AES-128-GCM")
 	}
+	lines = append(lines, cipherProfiles[constants.Cyberghost].lines(settings.Cipher, settings.Auth)...)
+	lines = append(lines, ipv6Lines(settings.Provider.ExtraConfigOptions.OpenVPNIPv6)...)
 	if !settings.Root {
 		lines = append(lines, "user "+username)
 	}
@@ -143,6 +150,6 @@ func (c *cyberghost) BuildConf(connection models.OpenVPNConnection,
 
 func (c *cyberghost) PortForward(ctx context.Context, client *http.Client,
 	openFile os.OpenFileFunc, pfLogger logging.Logger, gateway net.IP, fw firewall.Configurator,
-	syncState func(port uint16) (pfFilepath string)) {
+	syncState func(port uint16, expiration time.Time) (pfFilepath string)) {
 	panic("port forwarding is not supported for cyberghost")
 }
@@ -2,4 +2,8 @@ package provider
 
 import "errors"
 
-var ErrNoServerFound = errors.New("no server found")
+var (
+	ErrNoServerFound       = errors.New("no server found")
+	ErrHTTPStatusCodeNotOK = errors.New("HTTP status code not OK")
+	ErrPortMappingMismatch = errors.New("UDP and TCP ports mapped differ")
+)
@@ -2,11 +2,11 @@ package provider
 
 import (
 	"context"
-	"fmt"
 	"math/rand"
 	"net"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/gluetun/internal/constants"
@@ -19,64 +19,59 @@ import (
 type fastestvpn struct {
 	servers    []models.FastestvpnServer
 	randSource rand.Source
+	latencies  models.IPLatencies
 }
 
-func newFastestvpn(servers []models.FastestvpnServer, timeNow timeNowFunc) *fastestvpn {
+func newFastestvpn(servers []models.FastestvpnServer, latencies models.IPLatencies,
+	timeNow timeNowFunc) *fastestvpn {
 	return &fastestvpn{
 		servers:    servers,
 		randSource: rand.NewSource(timeNow().UnixNano()),
+		latencies:  latencies,
 	}
 }
 
-func (f *fastestvpn) filterServers(countries, hostnames []string, protocol string) (servers []models.FastestvpnServer) {
-	var tcp, udp bool
-	if protocol == "tcp" {
-		tcp = true
-	} else {
-		udp = true
-	}
-
-	for _, server := range f.servers {
-		switch {
-		case filterByPossibilities(server.Country, countries):
-		case filterByPossibilities(server.Hostname, hostnames):
-		case tcp && !server.TCP:
-		case udp && !server.UDP:
-		default:
+func (f *fastestvpn) filterServers(selection configuration.ServerSelection) (
+	servers []models.FastestvpnServer, err error) {
+	filters := selectionFilters(selection)
+	attributes := make([]serverAttributes, len(f.servers))
+	for i, server := range f.servers {
+		attributes[i] = serverAttributes{
+			country: server.Country, hostname: server.Hostname,
+			tcp: server.TCP, udp: server.UDP,
+		}
+		if !filterServer(filters, attributes[i]) {
 			servers = append(servers, server)
 		}
 	}
-	return servers
-}
-
-func (f *fastestvpn) notFoundErr(selection configuration.ServerSelection) error {
-	message := "no server found for protocol " + selection.Protocol
-
-	if len(selection.Hostnames) > 0 {
-		message += " + hostnames " + commaJoin(selection.Hostnames)
-	}
-
-	if len(selection.Countries) > 0 {
-		message += " + countries " + commaJoin(selection.Countries)
+	if len(servers) == 0 {
+		return nil, noServerFoundError(filters, attributes)
 	}
-
-	return fmt.Errorf(message)
+	return servers, nil
 }
 
 func (f *fastestvpn) GetOpenVPNConnection(selection configuration.ServerSelection) (
 	connection models.OpenVPNConnection, err error) {
+	connections, err := f.GetOpenVPNConnections(selection)
+	if err != nil {
+		return connection, err
+	}
+	return pickConnection(connections, selection, f.randSource, f.latencies), nil
+}
+
+func (f *fastestvpn) GetOpenVPNConnections(selection configuration.ServerSelection) (
+	connections []models.OpenVPNConnection, err error) {
 	var port uint16 = 4443
 
 	if selection.TargetIP != nil {
-		return models.OpenVPNConnection{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}, nil
+		return []models.OpenVPNConnection{{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}}, nil
 	}
 
-	servers := f.filterServers(selection.Countries, selection.Hostnames, selection.Protocol)
-	if len(servers) == 0 {
-		return connection, f.notFoundErr(selection)
+	servers, err := f.filterServers(selection)
+	if err != nil {
+		return nil, err
 	}
 
-	var connections []models.OpenVPNConnection
 	for _, server := range servers {
 		for _, IP := range server.IPs {
 			connection := models.OpenVPNConnection{
@@ -88,17 +83,11 @@ func (f *fastestvpn) GetOpenVPNConnection(selection configuration.ServerSelectio
 		}
 	}
 
-	return pickRandomConnection(connections, f.randSource), nil
+	return connections, nil
 }
 
 func (f *fastestvpn) BuildConf(connection models.OpenVPNConnection,
 	username string, settings configuration.OpenVPN) (lines []string) {
-	if len(settings.Cipher) == 0 {
-		settings.Cipher = aes256cbc
-	}
-	if len(settings.Auth) == 0 {
-		settings.Auth = sha256
-	}
 	if settings.MSSFix == 0 {
 		settings.MSSFix = 1450
 	}
@@ -135,10 +124,9 @@ func (f *fastestvpn) BuildConf(connection models.OpenVPNConnection,
 		"auth-user-pass " + constants.OpenVPNAuthConf,
 		"proto " + connection.Protocol,
 		"remote " + connection.IP.String() + " " + strconv.Itoa(int(connection.Port)),
-		"data-ciphers-fallback " + settings.Cipher,
-		"data-ciphers " + settings.Cipher,
-		"auth " + settings.Auth,
 	}
+	lines = append(lines, cipherProfiles[constants.Fastestvpn].lines(settings.Cipher, settings.Auth)...)
+	lines = append(lines, ipv6Lines(settings.Provider.ExtraConfigOptions.OpenVPNIPv6)...)
 	if !settings.Root {
 		lines = append(lines, "user "+username)
 	}
@@ -165,6 +153,6 @@ func (f *fastestvpn) BuildConf(connection models.OpenVPNConnection,
 
 func (f *fastestvpn) PortForward(ctx context.Context, client *http.Client,
 	openFile os.OpenFileFunc, pfLogger logging.Logger, gateway net.IP, fw firewall.Configurator,
-	syncState func(port uint16) (pfFilepath string)) {
+	syncState func(port uint16, expiration time.Time) (pfFilepath string)) {
 	panic("port forwarding is not supported for fastestvpn")
 }
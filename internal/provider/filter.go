@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// serverAttributes contains the attributes of a server the server selection
+// can filter on. Providers leave attributes they do not have empty, except for
+// tcp and udp which must both be set to true if the protocols are unknown.
+type serverAttributes struct {
+	region   string
+	country  string
+	city     string
+	hostname string
+	isp      string
+	group    string
+	number   uint16
+	owned    bool
+	features []string
+	tcp      bool
+	udp      bool
+	multiHop *models.MultiHop
+}
+
+// serverFilter is a criterion of the server selection, where filtered
+// returns true if the server does not match the criterion.
+type serverFilter struct {
+	description string
+	filtered    func(attributes serverAttributes) bool
+}
+
+// selectionFilters returns the filters for the criteria set in the selection.
+// A server is selected only if it matches all the filters, and it matches a
+// filter with multiple values if it matches any one of these values.
+func selectionFilters(selection configuration.ServerSelection) (filters []serverFilter) {
+	switch selection.Protocol {
+	case constants.TCP:
+		filters = append(filters, serverFilter{
+			description: "protocol tcp",
+			filtered:    func(attributes serverAttributes) bool { return !attributes.tcp },
+		})
+	case constants.UDP:
+		filters = append(filters, serverFilter{
+			description: "protocol udp",
+			filtered:    func(attributes serverAttributes) bool { return !attributes.udp },
+		})
+	}
+
+	filters = appendPossibilitiesFilter(filters, "regions", selection.Regions,
+		func(attributes serverAttributes) string { return attributes.region })
+	filters = appendPossibilitiesFilter(filters, "countries", selection.Countries,
+		func(attributes serverAttributes) string { return attributes.country })
+	filters = appendPossibilitiesFilter(filters, "cities", selection.Cities,
+		func(attributes serverAttributes) string { return attributes.city })
+	filters = appendPossibilitiesFilter(filters, "hostnames", selection.Hostnames,
+		func(attributes serverAttributes) string { return attributes.hostname })
+	filters = appendPossibilitiesFilter(filters, "ISPs", selection.ISPs,
+		func(attributes serverAttributes) string { return attributes.isp })
+
+	if selection.Group != "" {
+		filters = appendPossibilitiesFilter(filters, "group", []string{selection.Group},
+			func(attributes serverAttributes) string { return attributes.group })
+	}
+
+	if len(selection.Numbers) > 0 {
+		numbers := make([]string, len(selection.Numbers))
+		for i, number := range selection.Numbers {
+			numbers[i] = strconv.Itoa(int(number))
+		}
+		filters = appendPossibilitiesFilter(filters, "numbers", numbers,
+			func(attributes serverAttributes) string { return strconv.Itoa(int(attributes.number)) })
+	}
+
+	if selection.Owned {
+		filters = append(filters, serverFilter{
+			description: "owned servers only",
+			filtered:    func(attributes serverAttributes) bool { return !attributes.owned },
+		})
+	}
+
+	for _, feature := range selection.Features {
+		feature := feature
+		filters = append(filters, serverFilter{
+			description: "feature " + feature,
+			filtered: func(attributes serverAttributes) bool {
+				return !hasFeature(attributes, feature)
+			},
+		})
+	}
+
+	multiHop := selection.MultiHop
+	if multiHop.Only || len(multiHop.EntryCountries) > 0 || len(multiHop.ExitCountries) > 0 {
+		description := "multi-hop"
+		if multiHop.Only {
+			description += " only"
+		}
+		if len(multiHop.EntryCountries) > 0 {
+			description += " with entry countries " + commaJoin(multiHop.EntryCountries)
+		}
+		if len(multiHop.ExitCountries) > 0 {
+			description += " with exit countries " + commaJoin(multiHop.ExitCountries)
+		}
+		filters = append(filters, serverFilter{
+			description: description,
+			filtered: func(attributes serverAttributes) bool {
+				return filterMultiHop(attributes.multiHop, multiHop)
+			},
+		})
+	}
+
+	return filters
+}
+
+// hasFeature returns true if the server has the feature given.
+func hasFeature(attributes serverAttributes, feature string) bool {
+	for _, serverFeature := range attributes.features {
+		if serverFeature == feature {
+			return true
+		}
+	}
+	return false
+}
+
+func appendPossibilitiesFilter(filters []serverFilter, name string, possibilities []string,
+	getValue func(attributes serverAttributes) string) []serverFilter {
+	if len(possibilities) == 0 {
+		return filters
+	}
+	return append(filters, serverFilter{
+		description: name + " " + commaJoin(possibilities),
+		filtered: func(attributes serverAttributes) bool {
+			return filterByPossibilities(getValue(attributes), possibilities)
+		},
+	})
+}
+
+// filterServer returns true if the server does not match all the filters.
+func filterServer(filters []serverFilter, attributes serverAttributes) (filtered bool) {
+	for _, filter := range filters {
+		if filter.filtered(attributes) {
+			return true
+		}
+	}
+	return false
+}
+
+// noServerFoundError returns an error describing each filter with the number
+// of servers matching it, to help finding which filters are too restrictive.
+func noServerFoundError(filters []serverFilter, attributes []serverAttributes) error {
+	if len(filters) == 0 {
+		return fmt.Errorf("%w: provider has no server", ErrNoServerFound)
+	}
+
+	descriptions := make([]string, len(filters))
+	for i, filter := range filters {
+		matching := 0
+		for _, serverAttributes := range attributes {
+			if !filter.filtered(serverAttributes) {
+				matching++
+			}
+		}
+		descriptions[i] = fmt.Sprintf("%s (%d of %d servers match)",
+			filter.description, matching, len(attributes))
+	}
+	return fmt.Errorf("%w: for %s", ErrNoServerFound, strings.Join(descriptions, " and "))
+}
@@ -0,0 +1,236 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_selectionFilters(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		selection    configuration.ServerSelection
+		attributes   serverAttributes
+		descriptions []string
+		filtered     bool
+	}{
+		"no criteria": {
+			attributes: serverAttributes{country: "Germany"},
+		},
+		"protocol tcp": {
+			selection:    configuration.ServerSelection{Protocol: constants.TCP},
+			attributes:   serverAttributes{udp: true},
+			descriptions: []string{"protocol tcp"},
+			filtered:     true,
+		},
+		"protocol udp": {
+			selection:    configuration.ServerSelection{Protocol: constants.UDP},
+			attributes:   serverAttributes{tcp: true, udp: true},
+			descriptions: []string{"protocol udp"},
+		},
+		"any of the countries case insensitively": {
+			selection:    configuration.ServerSelection{Countries: []string{"France", "germany"}},
+			attributes:   serverAttributes{country: "Germany"},
+			descriptions: []string{"countries France,germany"},
+		},
+		"country and city both matching": {
+			selection: configuration.ServerSelection{
+				Countries: []string{"Germany"},
+				Cities:    []string{"Berlin"},
+			},
+			attributes:   serverAttributes{country: "Germany", city: "Berlin"},
+			descriptions: []string{"countries Germany", "cities Berlin"},
+		},
+		"country matching but not city": {
+			selection: configuration.ServerSelection{
+				Countries: []string{"Germany"},
+				Cities:    []string{"Berlin"},
+			},
+			attributes:   serverAttributes{country: "Germany", city: "Munich"},
+			descriptions: []string{"countries Germany", "cities Berlin"},
+			filtered:     true,
+		},
+		"region matching but not ISP": {
+			selection: configuration.ServerSelection{
+				Regions: []string{"Europe"},
+				ISPs:    []string{"M247"},
+			},
+			attributes:   serverAttributes{region: "Europe", isp: "31173"},
+			descriptions: []string{"regions Europe", "ISPs M247"},
+			filtered:     true,
+		},
+		"hostname and number matching": {
+			selection: configuration.ServerSelection{
+				Hostnames: []string{"de1.vpn.com"},
+				Numbers:   []uint16{1, 2},
+			},
+			attributes:   serverAttributes{hostname: "de1.vpn.com", number: 2},
+			descriptions: []string{"hostnames de1.vpn.com", "numbers 1,2"},
+		},
+		"number not matching": {
+			selection:    configuration.ServerSelection{Numbers: []uint16{1}},
+			attributes:   serverAttributes{number: 3},
+			descriptions: []string{"numbers 1"},
+			filtered:     true,
+		},
+		"group matching": {
+			selection:    configuration.ServerSelection{Group: "Premium UDP Europe"},
+			attributes:   serverAttributes{group: "premium udp europe"},
+			descriptions: []string{"group Premium UDP Europe"},
+		},
+		"owned server required": {
+			selection:    configuration.ServerSelection{Owned: true},
+			attributes:   serverAttributes{country: "Germany"},
+			descriptions: []string{"owned servers only"},
+			filtered:     true,
+		},
+		"feature matching": {
+			selection:    configuration.ServerSelection{Features: []string{constants.P2P}},
+			attributes:   serverAttributes{features: []string{constants.P2P}},
+			descriptions: []string{"feature p2p"},
+		},
+		"multi-hop matching": {
+			selection: configuration.ServerSelection{
+				MultiHop: configuration.MultiHopSelection{
+					Only:          true,
+					ExitCountries: []string{"Japan"},
+				},
+			},
+			attributes: serverAttributes{
+				multiHop: &models.MultiHop{EntryCountry: "Singapore", ExitCountry: "Japan"},
+			},
+			descriptions: []string{"multi-hop only with exit countries Japan"},
+		},
+		"multi-hop required": {
+			selection: configuration.ServerSelection{
+				MultiHop: configuration.MultiHopSelection{Only: true},
+			},
+			attributes:   serverAttributes{country: "Japan"},
+			descriptions: []string{"multi-hop only"},
+			filtered:     true,
+		},
+		"all criteria matching": {
+			selection: configuration.ServerSelection{
+				Protocol:  constants.UDP,
+				Countries: []string{"Germany"},
+				Cities:    []string{"Berlin"},
+				Hostnames: []string{"de1.vpn.com"},
+				Numbers:   []uint16{1},
+				Features:  []string{constants.P2P},
+			},
+			attributes: serverAttributes{
+				country:  "Germany",
+				city:     "Berlin",
+				hostname: "de1.vpn.com",
+				number:   1,
+				features: []string{constants.P2P},
+				udp:      true,
+			},
+			descriptions: []string{"protocol udp", "countries Germany", "cities Berlin",
+				"hostnames de1.vpn.com", "numbers 1", "feature p2p"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			filters := selectionFilters(testCase.selection)
+
+			var descriptions []string
+			for _, filter := range filters {
+				descriptions = append(descriptions, filter.description)
+			}
+			assert.Equal(t, testCase.descriptions, descriptions)
+
+			filtered := filterServer(filters, testCase.attributes)
+			assert.Equal(t, testCase.filtered, filtered)
+		})
+	}
+}
+
+func Test_noServerFoundError(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		selection  configuration.ServerSelection
+		attributes []serverAttributes
+		errMessage string
+	}{
+		"no filter": {
+			errMessage: "no server found: provider has no server",
+		},
+		"matching counts": {
+			selection: configuration.ServerSelection{
+				Countries: []string{"Germany"},
+				Cities:    []string{"Paris"},
+			},
+			attributes: []serverAttributes{
+				{country: "Germany", city: "Berlin"},
+				{country: "Germany", city: "Munich"},
+				{country: "France", city: "Paris"},
+			},
+			errMessage: "no server found: for countries Germany (2 of 3 servers match) " +
+				"and cities Paris (1 of 3 servers match)",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			filters := selectionFilters(testCase.selection)
+
+			err := noServerFoundError(filters, testCase.attributes)
+
+			assert.ErrorIs(t, err, ErrNoServerFound)
+			assert.EqualError(t, err, testCase.errMessage)
+		})
+	}
+}
+
+func Test_selectionFilters_features(t *testing.T) {
+	t.Parallel()
+
+	selection := configuration.ServerSelection{
+		Features: []string{constants.P2P, constants.Streaming},
+	}
+	filters := selectionFilters(selection)
+
+	testCases := map[string]struct {
+		attributes serverAttributes
+		filtered   bool
+	}{
+		"no feature": {
+			filtered: true,
+		},
+		"p2p only": {
+			attributes: serverAttributes{features: []string{constants.P2P}},
+			filtered:   true,
+		},
+		"owned only": {
+			attributes: serverAttributes{owned: true},
+			filtered:   true,
+		},
+		"p2p and streaming": {
+			attributes: serverAttributes{features: []string{constants.Streaming, constants.P2P}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			filtered := filterServer(filters, testCase.attributes)
+
+			assert.Equal(t, testCase.filtered, filtered)
+		})
+	}
+}
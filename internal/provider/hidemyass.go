@@ -2,12 +2,11 @@ package provider
 
 import (
 	"context"
-	"fmt"
 	"math/rand"
 	"net"
 	"net/http"
 	"strconv"
-	"strings"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/gluetun/internal/constants"
@@ -20,52 +19,48 @@ import (
 type hideMyAss struct {
 	servers    []models.HideMyAssServer
 	randSource rand.Source
+	latencies  models.IPLatencies
 }
 
-func newHideMyAss(servers []models.HideMyAssServer, timeNow timeNowFunc) *hideMyAss {
+func newHideMyAss(servers []models.HideMyAssServer, latencies models.IPLatencies,
+	timeNow timeNowFunc) *hideMyAss {
 	return &hideMyAss{
 		servers:    servers,
 		randSource: rand.NewSource(timeNow().UnixNano()),
+		latencies:  latencies,
 	}
 }
 
-func (h *hideMyAss) filterServers(countries, cities, hostnames []string,
-	protocol string) (servers []models.HideMyAssServer) {
-	for _, server := range h.servers {
-		switch {
-		case
-			filterByPossibilities(server.Country, countries),
-			filterByPossibilities(server.City, cities),
-			filterByPossibilities(server.Hostname, hostnames),
-			protocol == constants.TCP && !server.TCP,
-			protocol == constants.UDP && !server.UDP:
-		default:
+func (h *hideMyAss) filterServers(selection configuration.ServerSelection) (
+	servers []models.HideMyAssServer, err error) {
+	filters := selectionFilters(selection)
+	attributes := make([]serverAttributes, len(h.servers))
+	for i, server := range h.servers {
+		attributes[i] = serverAttributes{
+			region: server.Region, country: server.Country, city: server.City,
+			hostname: server.Hostname, tcp: server.TCP, udp: server.UDP,
+		}
+		if !filterServer(filters, attributes[i]) {
 			servers = append(servers, server)
 		}
 	}
-	return servers
-}
-
-func (h *hideMyAss) notFoundErr(selection configuration.ServerSelection) error {
-	var filters []string
-
-	if len(selection.Countries) > 0 {
-		filters = append(filters, "countries "+commaJoin(selection.Countries))
-	}
-
-	if len(selection.Cities) > 0 {
-		filters = append(filters, "countries "+commaJoin(selection.Cities))
-	}
-
-	if len(selection.Hostnames) > 0 {
-		filters = append(filters, "countries "+commaJoin(selection.Hostnames))
+	if len(servers) == 0 {
+		return nil, noServerFoundError(filters, attributes)
 	}
-
-	return fmt.Errorf("%w for %s", ErrNoServerFound, strings.Join(filters, " + "))
+	return servers, nil
 }
 
 func (h *hideMyAss) GetOpenVPNConnection(selection configuration.ServerSelection) (
 	connection models.OpenVPNConnection, err error) {
+	connections, err := h.GetOpenVPNConnections(selection)
+	if err != nil {
+		return connection, err
+	}
+	return pickConnection(connections, selection, h.randSource, h.latencies), nil
+}
+
+func (h *hideMyAss) GetOpenVPNConnections(selection configuration.ServerSelection) (
+	connections []models.OpenVPNConnection, err error) {
 	var defaultPort uint16 = 553
 	if selection.Protocol == constants.TCP {
 		defaultPort = 8080
@@ -76,30 +71,25 @@ func (h *hideMyAss) GetOpenVPNConnection(selection configuration.ServerSelection
 	}
 
 	if selection.TargetIP != nil {
-		return models.OpenVPNConnection{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}, nil
+		return []models.OpenVPNConnection{{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}}, nil
 	}
 
-	servers := h.filterServers(selection.Countries, selection.Cities, selection.Hostnames, selection.Protocol)
-	if len(servers) == 0 {
-		return models.OpenVPNConnection{}, h.notFoundErr(selection)
+	servers, err := h.filterServers(selection)
+	if err != nil {
+		return nil, err
 	}
 
-	var connections []models.OpenVPNConnection
 	for _, server := range servers {
 		for _, IP := range server.IPs {
 			connections = append(connections, models.OpenVPNConnection{IP: IP, Port: port, Protocol: selection.Protocol})
 		}
 	}
 
-	return pickRandomConnection(connections, h.randSource), nil
+	return connections, nil
 }
 
 func (h *hideMyAss) BuildConf(connection models.OpenVPNConnection,
 	username string, settings configuration.OpenVPN) (lines []string) {
-	if len(settings.Cipher) == 0 {
-		settings.Cipher = aes256cbc
-	}
-
 	lines = []string{
 		"client",
 		"dev tun",
@@ -128,9 +118,9 @@ func (h *hideMyAss) BuildConf(connection models.OpenVPNConnection,
 		"auth-user-pass " + constants.OpenVPNAuthConf,
 		"proto " + connection.Protocol,
 		"remote " + connection.IP.String() + strconv.Itoa(int(connection.Port)),
-		"data-ciphers-fallback " + settings.Cipher,
-		"data-ciphers " + settings.Cipher,
 	}
+	lines = append(lines, cipherProfiles[constants.HideMyAss].lines(settings.Cipher, settings.Auth)...)
+	lines = append(lines, ipv6Lines(settings.Provider.ExtraConfigOptions.OpenVPNIPv6)...)
 
 	if !settings.Root {
 		lines = append(lines, "user "+username)
@@ -164,6 +154,6 @@ func (h *hideMyAss) BuildConf(connection models.OpenVPNConnection,
 
 func (h *hideMyAss) PortForward(ctx context.Context, client *http.Client,
 	openFile os.OpenFileFunc, pfLogger logging.Logger, gateway net.IP, fw firewall.Configurator,
-	syncState func(port uint16) (pfFilepath string)) {
+	syncState func(port uint16, expiration time.Time) (pfFilepath string)) {
 	panic("port forwarding is not supported for hideMyAss")
 }
@@ -0,0 +1,14 @@
+package provider
+
+// ipv6Lines returns the OpenVPN configuration lines to accept the IPv6
+// address and routes pushed by the server if enabled, or to ignore them
+// otherwise.
+func ipv6Lines(enabled bool) (lines []string) {
+	if enabled {
+		return []string{"tun-ipv6"}
+	}
+	return []string{
+		`pull-filter ignore "route-ipv6"`,
+		`pull-filter ignore "ifconfig-ipv6"`,
+	}
+}
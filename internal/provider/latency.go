@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/tcpprobe"
+)
+
+type probeFunc func(ctx context.Context, connection models.OpenVPNConnection) (latency time.Duration, err error)
+
+// LatencyCandidates returns at most selection.Latency.Candidates randomly
+// chosen connections matching the server selection, with distinct IP addresses.
+func LatencyCandidates(provider Provider, selection configuration.ServerSelection) (
+	candidates []models.OpenVPNConnection, err error) {
+	connections, err := provider.GetOpenVPNConnections(selection)
+	if err != nil {
+		return nil, err
+	}
+	source := rand.NewSource(time.Now().UnixNano())
+	return latencyCandidates(connections, selection.Latency.Candidates, source), nil
+}
+
+// ProbeLatencies measures the latency to the candidate connections given.
+// The latencies returned can then be used by the provider to pick the
+// fastest connection.
+func ProbeLatencies(ctx context.Context, candidates []models.OpenVPNConnection,
+	timeout time.Duration) (latencies models.IPLatencies) {
+	probe := newTCPProbe(timeout)
+	return probeLatencies(ctx, probe, candidates)
+}
+
+// LatencyProbeRules returns the outbound rules allowing the latency
+// probes to the candidate connections given through the firewall.
+func LatencyProbeRules(candidates []models.OpenVPNConnection) (rules []models.OutboundRule) {
+	rules = make([]models.OutboundRule, len(candidates))
+	for i, candidate := range candidates {
+		rules[i] = models.OutboundRule{
+			Subnet:   ipToSubnet(candidate.IP),
+			Port:     probePort(candidate),
+			Protocol: constants.TCP,
+		}
+	}
+	return rules
+}
+
+// probePort returns the TCP port to probe for the connection given.
+// The connection port is used for TCP and port 443 is used for UDP,
+// since most VPN servers listen on it.
+func probePort(connection models.OpenVPNConnection) (port uint16) {
+	if connection.Protocol == constants.UDP {
+		return 443 //nolint:gomnd
+	}
+	return connection.Port
+}
+
+// newTCPProbe returns a function measuring the time taken to establish a
+// TCP connection to the connection IP address on the port given by probePort.
+func newTCPProbe(timeout time.Duration) probeFunc {
+	return func(ctx context.Context, connection models.OpenVPNConnection) (latency time.Duration, err error) {
+		return tcpprobe.Latency(ctx, connection.IP, probePort(connection), timeout)
+	}
+}
+
+// ProbeConnection returns an error if a TCP connection to the server of the
+// connection cannot be established within the timeout given. A refused
+// connection is an error since it does not prove the server is reachable.
+func ProbeConnection(ctx context.Context, connection models.OpenVPNConnection,
+	timeout time.Duration) (err error) {
+	return tcpprobe.Connect(ctx, connection.IP, probePort(connection), timeout)
+}
+
+func latencyCandidates(connections []models.OpenVPNConnection, maxCandidates int,
+	source rand.Source) (candidates []models.OpenVPNConnection) {
+	candidates = make([]models.OpenVPNConnection, 0, len(connections))
+	seen := make(map[string]struct{}, len(connections))
+	for _, connection := range connections {
+		key := connection.IP.String()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		candidates = append(candidates, connection)
+	}
+
+	if len(candidates) > maxCandidates {
+		generator := rand.New(source) //nolint:gosec
+		generator.Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+		candidates = candidates[:maxCandidates]
+	}
+
+	return candidates
+}
+
+func probeLatencies(ctx context.Context, probe probeFunc,
+	candidates []models.OpenVPNConnection) (latencies models.IPLatencies) {
+	const parallelism = 16
+	semaphore := make(chan struct{}, parallelism)
+	latencies = make(models.IPLatencies, len(candidates))
+	mutex := &sync.Mutex{}
+	wg := &sync.WaitGroup{}
+	for _, candidate := range candidates {
+		wg.Add(1)
+		go func(connection models.OpenVPNConnection) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			latency, err := probe(ctx, connection)
+			if err != nil {
+				return
+			}
+			mutex.Lock()
+			latencies[connection.IP.String()] = latency
+			mutex.Unlock()
+		}(candidate)
+	}
+	wg.Wait()
+
+	return latencies
+}
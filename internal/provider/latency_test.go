@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_probeLatencies(t *testing.T) {
+	t.Parallel()
+	errTest := errors.New("test error")
+	probe := func(ctx context.Context, connection models.OpenVPNConnection) (time.Duration, error) {
+		switch connection.IP.String() {
+		case "1.1.1.1":
+			return time.Second, nil
+		case "2.2.2.2":
+			return time.Millisecond, nil
+		default:
+			return 0, errTest
+		}
+	}
+	testCases := map[string]struct {
+		candidates []models.OpenVPNConnection
+		latencies  models.IPLatencies
+	}{
+		"no candidate": {
+			latencies: models.IPLatencies{},
+		},
+		"unreachable candidate": {
+			candidates: []models.OpenVPNConnection{
+				{IP: net.IPv4(1, 1, 1, 1), Port: 1194},
+				{IP: net.IPv4(2, 2, 2, 2)},
+				{IP: net.IPv4(3, 3, 3, 3)},
+			},
+			latencies: models.IPLatencies{
+				"1.1.1.1": time.Second,
+				"2.2.2.2": time.Millisecond,
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			latencies := probeLatencies(context.Background(), probe, testCase.candidates)
+			assert.Equal(t, testCase.latencies, latencies)
+		})
+	}
+}
+
+func Test_latencyCandidates(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		connections   []models.OpenVPNConnection
+		maxCandidates int
+		candidates    []models.OpenVPNConnection
+	}{
+		"no connection": {
+			maxCandidates: 10,
+			candidates:    []models.OpenVPNConnection{},
+		},
+		"duplicate connections": {
+			connections: []models.OpenVPNConnection{
+				{IP: net.IPv4(1, 1, 1, 1), Port: 1194},
+				{IP: net.IPv4(1, 1, 1, 1), Port: 443},
+				{IP: net.IPv4(2, 2, 2, 2)},
+			},
+			maxCandidates: 10,
+			candidates: []models.OpenVPNConnection{
+				{IP: net.IPv4(1, 1, 1, 1), Port: 1194},
+				{IP: net.IPv4(2, 2, 2, 2)},
+			},
+		},
+		"capped candidates": {
+			connections: []models.OpenVPNConnection{
+				{IP: net.IPv4(1, 1, 1, 1)},
+				{IP: net.IPv4(2, 2, 2, 2)},
+			},
+			maxCandidates: 1,
+			candidates: []models.OpenVPNConnection{
+				{IP: net.IPv4(1, 1, 1, 1)},
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			source := rand.NewSource(0)
+			candidates := latencyCandidates(testCase.connections, testCase.maxCandidates, source)
+			assert.Equal(t, testCase.candidates, candidates)
+		})
+	}
+}
+
+func Test_LatencyProbeRules(t *testing.T) {
+	t.Parallel()
+
+	candidates := []models.OpenVPNConnection{
+		{IP: net.IPv4(1, 1, 1, 1), Port: 1194, Protocol: "udp"},
+		{IP: net.IPv4(2, 2, 2, 2), Port: 1443, Protocol: "tcp"},
+		{IP: net.ParseIP("2001:db8::1"), Port: 1194, Protocol: "udp"},
+	}
+
+	rules := LatencyProbeRules(candidates)
+
+	expected := []models.OutboundRule{
+		{
+			Subnet:   net.IPNet{IP: net.IP{1, 1, 1, 1}, Mask: net.CIDRMask(32, 32)},
+			Port:     443,
+			Protocol: "tcp",
+		},
+		{
+			Subnet:   net.IPNet{IP: net.IP{2, 2, 2, 2}, Mask: net.CIDRMask(32, 32)},
+			Port:     1443,
+			Protocol: "tcp",
+		},
+		{
+			Subnet:   net.IPNet{IP: net.ParseIP("2001:db8::1"), Mask: net.CIDRMask(128, 128)},
+			Port:     443,
+			Protocol: "tcp",
+		},
+	}
+	assert.Equal(t, expected, rules)
+}
+
+func Test_ProbeConnection_refused(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := uint16(listener.Addr().(*net.TCPAddr).Port)
+	require.NoError(t, listener.Close())
+
+	connection := models.OpenVPNConnection{
+		IP:       net.IPv4(127, 0, 0, 1),
+		Port:     port,
+		Protocol: "tcp",
+	}
+
+	// a refused connection can come from any middlebox
+	err = ProbeConnection(context.Background(), connection, time.Second)
+
+	assert.ErrorIs(t, err, syscall.ECONNREFUSED)
+}
@@ -2,11 +2,14 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/gluetun/internal/constants"
@@ -19,32 +22,48 @@ import (
 type mullvad struct {
 	servers    []models.MullvadServer
 	randSource rand.Source
+	latencies  models.IPLatencies
 }
 
-func newMullvad(servers []models.MullvadServer, timeNow timeNowFunc) *mullvad {
+func newMullvad(servers []models.MullvadServer, latencies models.IPLatencies,
+	timeNow timeNowFunc) *mullvad {
 	return &mullvad{
 		servers:    servers,
 		randSource: rand.NewSource(timeNow().UnixNano()),
+		latencies:  latencies,
 	}
 }
 
-func (m *mullvad) filterServers(countries, cities, isps []string, owned bool) (servers []models.MullvadServer) {
-	for _, server := range m.servers {
-		switch {
-		case
-			filterByPossibilities(server.Country, countries),
-			filterByPossibilities(server.City, cities),
-			filterByPossibilities(server.ISP, isps),
-			owned && !server.Owned:
-		default:
+func (m *mullvad) filterServers(selection configuration.ServerSelection) (
+	servers []models.MullvadServer, err error) {
+	filters := selectionFilters(selection)
+	attributes := make([]serverAttributes, len(m.servers))
+	for i, server := range m.servers {
+		attributes[i] = serverAttributes{
+			country: server.Country, city: server.City, isp: server.ISP,
+			owned: server.Owned, tcp: true, udp: true,
+		}
+		if !filterServer(filters, attributes[i]) {
 			servers = append(servers, server)
 		}
 	}
-	return servers
+	if len(servers) == 0 {
+		return nil, noServerFoundError(filters, attributes)
+	}
+	return servers, nil
 }
 
 func (m *mullvad) GetOpenVPNConnection(selection configuration.ServerSelection) (
 	connection models.OpenVPNConnection, err error) {
+	connections, err := m.GetOpenVPNConnections(selection)
+	if err != nil {
+		return connection, err
+	}
+	return pickConnection(connections, selection, m.randSource, m.latencies), nil
+}
+
+func (m *mullvad) GetOpenVPNConnections(selection configuration.ServerSelection) (
+	connections []models.OpenVPNConnection, err error) {
 	var defaultPort uint16 = 1194
 	if selection.Protocol == constants.TCP {
 		defaultPort = 443
@@ -54,31 +73,51 @@ func (m *mullvad) GetOpenVPNConnection(selection configuration.ServerSelection)
 		port = selection.CustomPort
 	}
 
+	if selection.MultiHopExitCity != "" {
+		port, err = m.multiHopPort(selection.MultiHopExitCity)
+		if err != nil {
+			return nil, err
+		}
+		selection.Cities = []string{selection.MultiHopEntryCity}
+	}
+
 	if selection.TargetIP != nil {
-		return models.OpenVPNConnection{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}, nil
+		return []models.OpenVPNConnection{{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}}, nil
 	}
 
-	servers := m.filterServers(selection.Countries, selection.Cities, selection.ISPs, selection.Owned)
-	if len(servers) == 0 {
-		return connection, fmt.Errorf("no server found for countries %s, cities %s, ISPs %s and owned %t",
-			commaJoin(selection.Countries), commaJoin(selection.Cities), commaJoin(selection.ISPs), selection.Owned)
+	servers, err := m.filterServers(selection)
+	if err != nil {
+		return nil, err
 	}
 
-	var connections []models.OpenVPNConnection
 	for _, server := range servers {
 		for _, IP := range server.IPs {
 			connections = append(connections, models.OpenVPNConnection{IP: IP, Port: port, Protocol: selection.Protocol})
 		}
 	}
 
-	return pickRandomConnection(connections, m.randSource), nil
+	return connections, nil
+}
+
+var ErrMullvadMultiHopNoPort = errors.New("no multi-hop port found for exit city")
+
+// multiHopPort returns the port to connect to on an entry server
+// to exit through a server of the exit city given.
+func (m *mullvad) multiHopPort(exitCity string) (port uint16, err error) {
+	var ports []uint16
+	for _, server := range m.servers {
+		if strings.EqualFold(server.City, exitCity) {
+			ports = append(ports, server.MultiHopPorts...)
+		}
+	}
+	if len(ports) == 0 {
+		return 0, fmt.Errorf("%w: %s", ErrMullvadMultiHopNoPort, exitCity)
+	}
+	return ports[rand.New(m.randSource).Intn(len(ports))], nil //nolint:gosec
 }
 
 func (m *mullvad) BuildConf(connection models.OpenVPNConnection,
 	username string, settings configuration.OpenVPN) (lines []string) {
-	if len(settings.Cipher) == 0 {
-		settings.Cipher = aes256cbc
-	}
 	lines = []string{
 		"client",
 		"dev tun",
@@ -109,15 +148,9 @@ func (m *mullvad) BuildConf(connection models.OpenVPNConnection,
 		fmt.Sprintf("auth-user-pass %s", constants.OpenVPNAuthConf),
 		fmt.Sprintf("proto %s", connection.Protocol),
 		fmt.Sprintf("remote %s %d", connection.IP, connection.Port),
-		"data-ciphers-fallback " + settings.Cipher,
-		"data-ciphers " + settings.Cipher,
-	}
-	if settings.Provider.ExtraConfigOptions.OpenVPNIPv6 {
-		lines = append(lines, "tun-ipv6")
-	} else {
-		lines = append(lines, `pull-filter ignore "route-ipv6"`)
-		lines = append(lines, `pull-filter ignore "ifconfig-ipv6"`)
 	}
+	lines = append(lines, cipherProfiles[constants.Mullvad].lines(settings.Cipher, settings.Auth)...)
+	lines = append(lines, ipv6Lines(settings.Provider.ExtraConfigOptions.OpenVPNIPv6)...)
 	if !settings.Root {
 		lines = append(lines, "user "+username)
 	}
@@ -137,6 +170,6 @@ func (m *mullvad) BuildConf(connection models.OpenVPNConnection,
 
 func (m *mullvad) PortForward(ctx context.Context, client *http.Client,
 	openFile os.OpenFileFunc, pfLogger logging.Logger, gateway net.IP, fw firewall.Configurator,
-	syncState func(port uint16) (pfFilepath string)) {
+	syncState func(port uint16, expiration time.Time) (pfFilepath string)) {
 	panic("port forwarding is not supported for mullvad")
 }
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"math/rand"
+	"net"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mullvad_GetOpenVPNConnections(t *testing.T) {
+	t.Parallel()
+	servers := []models.MullvadServer{
+		{City: "Entry", IPs: []net.IP{{1, 1, 1, 1}}, MultiHopPorts: []uint16{3001}},
+		{City: "Exit", IPs: []net.IP{{2, 2, 2, 2}}, MultiHopPorts: []uint16{3002}},
+		{City: "Other", IPs: []net.IP{{3, 3, 3, 3}}},
+	}
+	testCases := map[string]struct {
+		selection   configuration.ServerSelection
+		connections []models.OpenVPNConnection
+		err         string
+	}{
+		"no multi-hop": {
+			selection: configuration.ServerSelection{
+				Protocol: constants.UDP,
+				Cities:   []string{"exit"},
+			},
+			connections: []models.OpenVPNConnection{
+				{IP: net.IP{2, 2, 2, 2}, Port: 1194, Protocol: constants.UDP},
+			},
+		},
+		"multi-hop": {
+			selection: configuration.ServerSelection{
+				Protocol:          constants.UDP,
+				MultiHopEntryCity: "entry",
+				MultiHopExitCity:  "exit",
+			},
+			connections: []models.OpenVPNConnection{
+				{IP: net.IP{1, 1, 1, 1}, Port: 3002, Protocol: constants.UDP},
+			},
+		},
+		"multi-hop exit city without port": {
+			selection: configuration.ServerSelection{
+				Protocol:          constants.UDP,
+				MultiHopEntryCity: "entry",
+				MultiHopExitCity:  "other",
+			},
+			err: "no multi-hop port found for exit city: other",
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			m := &mullvad{
+				servers:    servers,
+				randSource: rand.NewSource(0),
+			}
+			connections, err := m.GetOpenVPNConnections(testCase.selection)
+			if testCase.err != "" {
+				require.Error(t, err)
+				assert.Equal(t, testCase.err, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, testCase.connections, connections)
+		})
+	}
+}
+
+func Test_mullvad_multiHopPort_hardcodedServers(t *testing.T) {
+	t.Parallel()
+
+	m := &mullvad{
+		servers:    constants.MullvadServers(),
+		randSource: rand.NewSource(0),
+	}
+
+	port, err := m.multiHopPort("gothenburg")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, port, uint16(3001))
+
+	_, err = m.multiHopPort("tirana")
+	assert.ErrorIs(t, err, ErrMullvadMultiHopNoPort)
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+var ErrMullvadWireguardAddress = errors.New("mullvad Wireguard address is not valid")
+
+// MullvadReplaceWireguardKey replaces the Wireguard public key registered
+// for the Mullvad account with a new public key, using the Mullvad API.
+// It returns the interface addresses assigned to the new key.
+func MullvadReplaceWireguardKey(ctx context.Context, client *http.Client,
+	account, oldPublicKey, newPublicKey string) (addresses []net.IPNet, err error) {
+	body, err := json.Marshal(struct {
+		Old string `json:"old"`
+		New string `json:"new"`
+	}{Old: oldPublicKey, New: newPublicKey})
+	if err != nil {
+		return nil, err
+	}
+
+	const url = "https://api.mullvad.net/app/v1/replace-wireguard-key"
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Token "+account)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("%w: %s", ErrHTTPStatusCodeNotOK, response.Status)
+	}
+
+	var data struct {
+		IPv4Address string `json:"ipv4_address"`
+		IPv6Address string `json:"ipv6_address"`
+	}
+	decoder := json.NewDecoder(response.Body)
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+
+	for _, address := range []string{data.IPv4Address, data.IPv6Address} {
+		if address == "" {
+			continue
+		}
+		ip, ipNet, err := net.ParseCIDR(address)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrMullvadWireguardAddress, err)
+		}
+		ipNet.IP = ip
+		addresses = append(addresses, *ipNet)
+	}
+	return addresses, nil
+}
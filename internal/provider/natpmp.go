@@ -0,0 +1,211 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/natpmp"
+	"github.com/qdm12/golibs/logging"
+	"github.com/qdm12/golibs/os"
+)
+
+const (
+	// natpmpLifetime is the lifetime requested for the port mappings.
+	natpmpLifetime = 60 * time.Second
+	// natpmpKeepAlivePeriod is the period to renew the port mappings,
+	// which must be shorter than their lifetime.
+	natpmpKeepAlivePeriod = 45 * time.Second
+)
+
+// NATPMPPortForward forwards the number of ports given using NAT-PMP
+// against the VPN gateway, for VPN servers supporting it such as the
+// ones of a custom configuration. It renews the port mappings until
+// the context is canceled, and updates the firewall and the port file
+// on each change. It is a blocking operation.
+func NATPMPPortForward(ctx context.Context, openFile os.OpenFileFunc,
+	pfLogger logging.Logger, gateway net.IP, fw firewall.Configurator, count int,
+	syncPorts func(ports []models.ForwardedPort) (pfFilepath string)) {
+	if gateway == nil {
+		pfLogger.Error("aborting because: VPN gateway IP address was not found")
+		return
+	}
+	defer pfLogger.Warn("loop exited")
+
+	forwarder := natpmpForwarder{
+		client:          natpmp.New(),
+		retryPeriod:     defaultRetryPeriod,
+		keepAlivePeriod: natpmpKeepAlivePeriod,
+	}
+	forwarder.run(ctx, openFile, pfLogger, gateway, fw, count, syncPorts)
+}
+
+type portMapper interface {
+	AddPortMapping(ctx context.Context, gateway net.IP, protocol string,
+		internalPort, suggestedExternalPort uint16, lifetime time.Duration) (
+		externalPort uint16, assignedLifetime time.Duration, err error)
+}
+
+// natpmpForwarder forwards ports using the NAT-PMP client given,
+// retrying and renewing the mappings at the periods given.
+type natpmpForwarder struct {
+	client          portMapper
+	retryPeriod     time.Duration
+	keepAlivePeriod time.Duration
+}
+
+// run maps the ports and renews them until the context is canceled.
+func (n *natpmpForwarder) run(ctx context.Context, openFile os.OpenFileFunc,
+	pfLogger logging.Logger, gateway net.IP, fw firewall.Configurator, count int,
+	syncPorts func(ports []models.ForwardedPort) (pfFilepath string)) {
+	var ports []models.ForwardedPort
+	setPorts := func(newPorts []models.ForwardedPort) {
+		for i, port := range newPorts {
+			if i < len(ports) && ports[i].Port == port.Port {
+				continue
+			}
+			if i < len(ports) {
+				if err := fw.RemoveAllowedPort(ctx, ports[i].Port); err != nil {
+					pfLogger.Error(err)
+				}
+			}
+			pfLogger.Info("Port forwarded is %d", port.Port)
+			if err := fw.SetAllowedPort(ctx, port.Port, fw.GetVPNInterface()); err != nil {
+				pfLogger.Error(err)
+			}
+		}
+		portsChanged := !sameForwardedPorts(ports, newPorts)
+		ports = newPorts
+		filepath := syncPorts(ports)
+		if !portsChanged {
+			return
+		}
+		pfLogger.Info("Writing ports to %s", filepath)
+		if err := writePortsForwardedToFile(openFile, filepath, ports); err != nil {
+			pfLogger.Error(err)
+		}
+	}
+
+	tryUntilSuccessful(ctx, pfLogger, n.retryPeriod, func() error {
+		newPorts, err := mapNATPMPPorts(ctx, n.client, gateway, count, nil)
+		if err != nil {
+			return err
+		}
+		setPorts(newPorts)
+		return nil
+	})
+	if len(ports) == 0 { // context canceled
+		return
+	}
+
+	ticker := time.NewTicker(n.keepAlivePeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			removeCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			for _, port := range ports {
+				if err := fw.RemoveAllowedPort(removeCtx, port.Port); err != nil {
+					pfLogger.Error(err)
+				}
+			}
+			return
+		case <-ticker.C:
+			newPorts, err := mapNATPMPPorts(ctx, n.client, gateway, count, ports)
+			if err != nil {
+				pfLogger.Error("cannot renew port mappings: %s", err)
+				continue
+			}
+			setPorts(newPorts)
+		}
+	}
+}
+
+// mapNATPMPPorts maps the number of ports given, renewing the
+// mappings of the current ports given if any.
+func mapNATPMPPorts(ctx context.Context, client portMapper, gateway net.IP,
+	count int, current []models.ForwardedPort) (ports []models.ForwardedPort, err error) {
+	ports = make([]models.ForwardedPort, count)
+	for i := range ports {
+		var suggestedPort uint16
+		if i < len(current) {
+			suggestedPort = current[i].Port
+		}
+		// The internal port only identifies the mapping, since the gateway
+		// forwards the traffic to the tunnel IP address on the external port.
+		internalPort := uint16(i + 1)
+		port, lifetime, err := mapNATPMPPort(ctx, client, gateway, internalPort, suggestedPort)
+		if err != nil {
+			return nil, err
+		}
+		ports[i] = models.ForwardedPort{
+			Port:       port,
+			Expiration: time.Now().Add(lifetime),
+		}
+	}
+	return ports, nil
+}
+
+// mapNATPMPPort maps a port for both UDP and TCP, suggesting the port
+// given to the gateway, and returns the port mapped and its lifetime.
+func mapNATPMPPort(ctx context.Context, client portMapper, gateway net.IP,
+	internalPort, suggestedPort uint16) (port uint16, lifetime time.Duration, err error) {
+	port, lifetime, err = client.AddPortMapping(ctx, gateway, "udp",
+		internalPort, suggestedPort, natpmpLifetime)
+	if err != nil {
+		return 0, 0, fmt.Errorf("cannot map UDP port: %w", err)
+	}
+
+	tcpPort, tcpLifetime, err := client.AddPortMapping(ctx, gateway, "tcp",
+		internalPort, port, natpmpLifetime)
+	if err != nil {
+		return 0, 0, fmt.Errorf("cannot map TCP port: %w", err)
+	} else if tcpPort != port {
+		return 0, 0, fmt.Errorf("%w: UDP port %d and TCP port %d", ErrPortMappingMismatch, port, tcpPort)
+	}
+
+	if tcpLifetime < lifetime {
+		lifetime = tcpLifetime
+	}
+	return port, lifetime, nil
+}
+
+// sameForwardedPorts returns true if the ports forwarded
+// given are the same, regardless of their expiration.
+func sameForwardedPorts(a, b []models.ForwardedPort) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Port != b[i].Port {
+			return false
+		}
+	}
+	return true
+}
+
+// writePortsForwardedToFile writes the ports forwarded
+// given to the file given, one port per line.
+func writePortsForwardedToFile(openFile os.OpenFileFunc,
+	filepath string, ports []models.ForwardedPort) (err error) {
+	lines := make([]string, len(ports))
+	for i, port := range ports {
+		lines[i] = strconv.Itoa(int(port.Port))
+	}
+	file, err := openFile(filepath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write([]byte(strings.Join(lines, "\n")))
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,250 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	nativeos "os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/firewall/mock_firewall"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/qdm12/golibs/os"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNATPMPGateway is a fake NAT-PMP gateway failing its first requests,
+// mapping UDP ports to the external ports given in order, and mapping
+// TCP ports to the external port suggested.
+type fakeNATPMPGateway struct {
+	mu       sync.Mutex
+	failures int
+	ports    []uint16
+	requests []string
+}
+
+var errTestGateway = errors.New("test gateway error")
+
+func (g *fakeNATPMPGateway) AddPortMapping(_ context.Context, _ net.IP, protocol string,
+	internalPort, suggestedExternalPort uint16, _ time.Duration) (
+	externalPort uint16, assignedLifetime time.Duration, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.requests = append(g.requests, fmt.Sprintf("%s %d %d", protocol, internalPort, suggestedExternalPort))
+	if g.failures > 0 {
+		g.failures--
+		return 0, 0, errTestGateway
+	}
+	if protocol == "tcp" {
+		return suggestedExternalPort, time.Minute, nil
+	}
+	externalPort = g.ports[0]
+	if len(g.ports) > 1 {
+		g.ports = g.ports[1:]
+	}
+	return externalPort, time.Minute, nil
+}
+
+type portMapperFunc func(protocol string, internalPort, suggestedExternalPort uint16) (
+	externalPort uint16, assignedLifetime time.Duration, err error)
+
+func (f portMapperFunc) AddPortMapping(_ context.Context, _ net.IP, protocol string,
+	internalPort, suggestedExternalPort uint16, _ time.Duration) (
+	externalPort uint16, assignedLifetime time.Duration, err error) {
+	return f(protocol, internalPort, suggestedExternalPort)
+}
+
+func Test_mapNATPMPPort(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		client     portMapperFunc
+		port       uint16
+		lifetime   time.Duration
+		err        error
+		errMessage string
+	}{
+		"same port for UDP and TCP": {
+			client: func(protocol string, _, suggested uint16) (uint16, time.Duration, error) {
+				if protocol == "udp" {
+					return 1000, time.Minute, nil
+				}
+				return suggested, time.Minute, nil
+			},
+			port:     1000,
+			lifetime: time.Minute,
+		},
+		"shortest lifetime": {
+			client: func(protocol string, _, _ uint16) (uint16, time.Duration, error) {
+				if protocol == "udp" {
+					return 1000, time.Minute, nil
+				}
+				return 1000, time.Second, nil
+			},
+			port:     1000,
+			lifetime: time.Second,
+		},
+		"UDP mapping failing": {
+			client: func(protocol string, _, _ uint16) (uint16, time.Duration, error) {
+				return 0, 0, errTestGateway
+			},
+			err:        errTestGateway,
+			errMessage: "cannot map UDP port: test gateway error",
+		},
+		"TCP mapping failing": {
+			client: func(protocol string, _, _ uint16) (uint16, time.Duration, error) {
+				if protocol == "udp" {
+					return 1000, time.Minute, nil
+				}
+				return 0, 0, errTestGateway
+			},
+			err:        errTestGateway,
+			errMessage: "cannot map TCP port: test gateway error",
+		},
+		"UDP and TCP ports mismatch": {
+			client: func(protocol string, _, _ uint16) (uint16, time.Duration, error) {
+				if protocol == "udp" {
+					return 1000, time.Minute, nil
+				}
+				return 2000, time.Minute, nil
+			},
+			err:        ErrPortMappingMismatch,
+			errMessage: "UDP and TCP ports mapped differ: UDP port 1000 and TCP port 2000",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			port, lifetime, err := mapNATPMPPort(context.Background(),
+				testCase.client, net.IPv4(10, 0, 0, 1), 1, 0)
+
+			assert.ErrorIs(t, err, testCase.err)
+			if testCase.err != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.port, port)
+			assert.Equal(t, testCase.lifetime, lifetime)
+		})
+	}
+}
+
+func Test_mapNATPMPPorts(t *testing.T) {
+	t.Parallel()
+
+	gateway := &fakeNATPMPGateway{ports: []uint16{1000, 2000}}
+	current := []models.ForwardedPort{{Port: 3000}}
+
+	ports, err := mapNATPMPPorts(context.Background(), gateway,
+		net.IPv4(10, 0, 0, 1), 2, current)
+
+	require.NoError(t, err)
+	require.Len(t, ports, 2)
+	assert.Equal(t, uint16(1000), ports[0].Port)
+	assert.Equal(t, uint16(2000), ports[1].Port)
+	expectedRequests := []string{
+		"udp 1 3000", "tcp 1 1000",
+		"udp 2 0", "tcp 2 2000",
+	}
+	assert.Equal(t, expectedRequests, gateway.requests)
+}
+
+func Test_NATPMPPortForward_noGateway(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	logger := mock_logging.NewMockLogger(ctrl)
+	logger.EXPECT().Error("aborting because: VPN gateway IP address was not found")
+
+	NATPMPPortForward(context.Background(), nil, logger, nil, nil, 1, nil)
+}
+
+func Test_natpmpForwarder_run(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	dir := t.TempDir()
+	openFile := func(name string, flag int, perm os.FileMode) (os.File, error) {
+		path := filepath.Join(dir, filepath.Base(name))
+		return nativeos.OpenFile(path, flag, nativeos.FileMode(perm))
+	}
+	pfFilepath := filepath.Join(dir, "forwarded_port")
+
+	logger := mock_logging.NewMockLogger(ctrl)
+	logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	logger.EXPECT().Error(gomock.Any()).AnyTimes()
+	logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+	fw := mock_firewall.NewMockConfigurator(ctrl)
+	// the tunnel device changed to tun1 after a seamless switch
+	fw.EXPECT().GetVPNInterface().Return("tun1").AnyTimes()
+	gomock.InOrder(
+		fw.EXPECT().SetAllowedPort(gomock.Any(), uint16(1000), "tun1").Return(nil),
+		fw.EXPECT().RemoveAllowedPort(gomock.Any(), uint16(1000)).Return(nil),
+		fw.EXPECT().SetAllowedPort(gomock.Any(), uint16(2000), "tun1").Return(nil),
+		fw.EXPECT().RemoveAllowedPort(gomock.Any(), uint16(2000)).Return(nil),
+	)
+
+	// The gateway fails the first request, maps port 1000 and renews
+	// it once, and then maps port 2000 for the following renewals.
+	gateway := &fakeNATPMPGateway{
+		failures: 1,
+		ports:    []uint16{1000, 1000, 2000},
+	}
+	forwarder := natpmpForwarder{
+		client:          gateway,
+		retryPeriod:     time.Millisecond,
+		keepAlivePeriod: time.Millisecond,
+	}
+
+	synced := make(chan uint16)
+	syncPorts := func(ports []models.ForwardedPort) (path string) {
+		select {
+		case synced <- ports[0].Port:
+		default:
+		}
+		return pfFilepath
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		forwarder.run(ctx, openFile, logger, net.IPv4(10, 0, 0, 1), fw, 1, syncPorts)
+	}()
+
+	timeout := time.After(5 * time.Second)
+	for port := uint16(0); port != 2000; {
+		select {
+		case port = <-synced:
+		case <-timeout:
+			t.Fatal("port forwarding did not renew the port mapping")
+		}
+	}
+	cancel()
+	<-done
+
+	gateway.mu.Lock()
+	expectedRequests := []string{
+		"udp 1 0",
+		"udp 1 0", "tcp 1 1000",
+		"udp 1 1000", "tcp 1 1000",
+		"udp 1 1000", "tcp 1 2000",
+	}
+	assert.Equal(t, expectedRequests, gateway.requests[:len(expectedRequests)])
+	gateway.mu.Unlock()
+
+	data, err := ioutil.ReadFile(pfFilepath)
+	require.NoError(t, err)
+	assert.Equal(t, "2000", string(data))
+}
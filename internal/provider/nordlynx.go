@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/os"
+)
+
+var (
+	ErrNordlynxNoServer   = errors.New("no NordLynx server found")
+	ErrNordlynxPrivateKey = errors.New("cannot obtain NordLynx private key")
+)
+
+const (
+	nordlynxCredentialsURL = "https://api.nordvpn.com/v1/users/services/credentials"
+	nordlynxServersURL     = "https://api.nordvpn.com/v1/servers?limit=16384&" +
+		"filters[servers_technologies][identifier]=wireguard_udp"
+)
+
+// NordlynxSettings returns the Wireguard settings given completed with
+// a NordLynx server matching the server selection, and the private key
+// obtained from the NordVPN API using the token. The public keys of the
+// servers are fetched from the NordVPN API if the servers given have none,
+// for example if the servers were not updated since NordLynx is supported.
+func NordlynxSettings(ctx context.Context, client *http.Client, openFile os.OpenFileFunc,
+	settings configuration.Wireguard, allServers models.AllServers) (
+	wireguard configuration.Wireguard, err error) {
+	tokens := newTokenCache(constants.APITokens, time.Now)
+	return nordlynxSettings(ctx, client, openFile, tokens, nordlynxCredentialsURL,
+		nordlynxServersURL, settings, allServers)
+}
+
+func nordlynxSettings(ctx context.Context, client *http.Client, openFile os.OpenFileFunc,
+	tokens *tokenCache, credentialsURL, serversURL string,
+	settings configuration.Wireguard, allServers models.AllServers) (
+	wireguard configuration.Wireguard, err error) {
+	n := newNordvpn(allServers.Nordvpn.Servers, allServers.Latencies, time.Now)
+	selection := settings.ServerSelection
+	servers, err := n.filterServers(selection)
+	if err != nil {
+		return wireguard, fmt.Errorf("%w: %s", ErrNordlynxNoServer, err)
+	}
+
+	if !hasNordlynxPublicKey(servers) {
+		servers = copyNordvpnServers(servers)
+		if err := setNordlynxPublicKeys(ctx, client, serversURL, servers); err != nil {
+			return wireguard, err
+		}
+	}
+
+	var connections []models.OpenVPNConnection
+	publicKeys := make(map[string]string, len(servers))
+	for _, server := range servers {
+		if server.WgPubKey == "" {
+			continue
+		}
+		connections = append(connections, models.OpenVPNConnection{
+			IP:       server.IP,
+			Port:     settings.EndpointPort,
+			Protocol: constants.UDP,
+		})
+		publicKeys[server.IP.String()] = server.WgPubKey
+	}
+	if len(connections) == 0 {
+		return wireguard, fmt.Errorf("%w: for region %s and numbers %v",
+			ErrNordlynxNoServer, commaJoin(selection.Regions), selection.Numbers)
+	}
+	connection := pickConnection(connections, selection, n.randSource, n.latencies)
+
+	privateKey, err := tokens.get(ctx, openFile, constants.Nordvpn,
+		[]string{settings.Token}, nordlynxPrivateKeyValidity,
+		func(ctx context.Context) (privateKey string, err error) {
+			return fetchNordlynxPrivateKey(ctx, client, credentialsURL, settings.Token)
+		})
+	if err != nil {
+		return wireguard, fmt.Errorf("%w: %s", ErrNordlynxPrivateKey, err)
+	}
+
+	wireguard = settings
+	wireguard.PrivateKey = privateKey
+	wireguard.PublicKey = publicKeys[connection.IP.String()]
+	wireguard.EndpointIP = connection.IP
+	return wireguard, nil
+}
+
+func hasNordlynxPublicKey(servers []models.NordvpnServer) bool {
+	for _, server := range servers {
+		if server.WgPubKey != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func copyNordvpnServers(servers []models.NordvpnServer) (copied []models.NordvpnServer) {
+	copied = make([]models.NordvpnServer, len(servers))
+	copy(copied, servers)
+	return copied
+}
+
+// SetNordlynxPublicKeys sets the NordLynx (Wireguard) public key of each
+// server supporting it using the NordVPN API, matching servers by their
+// IP address. It returns ErrNordlynxNoServer if no server supports it.
+func SetNordlynxPublicKeys(ctx context.Context, client *http.Client,
+	servers []models.NordvpnServer) (err error) {
+	return setNordlynxPublicKeys(ctx, client, nordlynxServersURL, servers)
+}
+
+func setNordlynxPublicKeys(ctx context.Context, client *http.Client,
+	url string, servers []models.NordvpnServer) (err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s for %s", ErrHTTPStatusCodeNotOK, response.Status, url)
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	var data []struct {
+		Station      string `json:"station"`
+		Technologies []struct {
+			Identifier string `json:"identifier"`
+			Metadata   []struct {
+				Name  string `json:"name"`
+				Value string `json:"value"`
+			} `json:"metadata"`
+		} `json:"technologies"`
+	}
+	if err := decoder.Decode(&data); err != nil {
+		return fmt.Errorf("cannot decode NordLynx servers: %w", err)
+	}
+
+	if err := response.Body.Close(); err != nil {
+		return err
+	}
+
+	ipToKey := make(map[string]string, len(data))
+	for _, jsonServer := range data {
+		for _, technology := range jsonServer.Technologies {
+			if technology.Identifier != "wireguard_udp" {
+				continue
+			}
+			for _, metadata := range technology.Metadata {
+				if metadata.Name == "public_key" {
+					ipToKey[jsonServer.Station] = metadata.Value
+				}
+			}
+		}
+	}
+
+	found := false
+	for i := range servers {
+		key, ok := ipToKey[servers[i].IP.String()]
+		if ok {
+			servers[i].WgPubKey = key
+			found = true
+		}
+	}
+	if !found {
+		return ErrNordlynxNoServer
+	}
+	return nil
+}
+
+// nordlynxPrivateKeyValidity is how long the NordLynx private key
+// obtained from the NordVPN API is cached for.
+const nordlynxPrivateKeyValidity = 24 * time.Hour
+
+func fetchNordlynxPrivateKey(ctx context.Context, client *http.Client,
+	url, token string) (privateKey string, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	request.SetBasicAuth("token", token)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: %s", ErrHTTPStatusCodeNotOK, response.Status)
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	var data struct {
+		PrivateKey string `json:"nordlynx_private_key"`
+	}
+	if err := decoder.Decode(&data); err != nil {
+		return "", err
+	}
+
+	return data.PrivateKey, response.Body.Close()
+}
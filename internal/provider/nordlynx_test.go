@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/os"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const nordlynxServersResponse = `[
+	{"station": "1.1.1.1", "technologies": [
+		{"identifier": "openvpn_udp"},
+		{"identifier": "wireguard_udp", "metadata": [{"name": "public_key", "value": "key1"}]}
+	]},
+	{"station": "3.3.3.3", "technologies": [
+		{"identifier": "wireguard_udp", "metadata": [{"name": "public_key", "value": "key3"}]}
+	]}
+]`
+
+func Test_setNordlynxPublicKeys(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		statusCode int
+		body       string
+		servers    []models.NordvpnServer
+		expected   []models.NordvpnServer
+		err        error
+		errMessage string
+	}{
+		"keys set": {
+			statusCode: http.StatusOK,
+			body:       nordlynxServersResponse,
+			servers: []models.NordvpnServer{
+				{IP: net.IPv4(1, 1, 1, 1)},
+				{IP: net.IPv4(2, 2, 2, 2)},
+			},
+			expected: []models.NordvpnServer{
+				{IP: net.IPv4(1, 1, 1, 1), WgPubKey: "key1"},
+				{IP: net.IPv4(2, 2, 2, 2)},
+			},
+		},
+		"no server supporting NordLynx": {
+			statusCode: http.StatusOK,
+			body:       nordlynxServersResponse,
+			servers:    []models.NordvpnServer{{IP: net.IPv4(2, 2, 2, 2)}},
+			expected:   []models.NordvpnServer{{IP: net.IPv4(2, 2, 2, 2)}},
+			err:        ErrNordlynxNoServer,
+			errMessage: "no NordLynx server found",
+		},
+		"bad status code": {
+			statusCode: http.StatusInternalServerError,
+			servers:    []models.NordvpnServer{{IP: net.IPv4(1, 1, 1, 1)}},
+			expected:   []models.NordvpnServer{{IP: net.IPv4(1, 1, 1, 1)}},
+			err:        ErrHTTPStatusCodeNotOK,
+		},
+		"malformed body": {
+			statusCode: http.StatusOK,
+			body:       "{",
+			servers:    []models.NordvpnServer{{IP: net.IPv4(1, 1, 1, 1)}},
+			expected:   []models.NordvpnServer{{IP: net.IPv4(1, 1, 1, 1)}},
+			errMessage: "cannot decode NordLynx servers: unexpected EOF",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(testCase.statusCode)
+				_, _ = w.Write([]byte(testCase.body))
+			}))
+			defer server.Close()
+
+			err := setNordlynxPublicKeys(context.Background(), server.Client(),
+				server.URL, testCase.servers)
+
+			if testCase.err != nil {
+				assert.ErrorIs(t, err, testCase.err)
+			}
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			if testCase.err == nil && testCase.errMessage == "" {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.expected, testCase.servers)
+		})
+	}
+}
+
+func Test_nordlynxSettings(t *testing.T) {
+	t.Parallel()
+
+	errNoFile := errors.New("no file")
+	openFile := func(name string, flag int, perm os.FileMode) (os.File, error) {
+		return nil, errNoFile
+	}
+
+	servers := []models.NordvpnServer{
+		{Region: "Canada", Number: 1, IP: net.IPv4(1, 1, 1, 1)},
+		{Region: "Canada", Number: 2, IP: net.IPv4(2, 2, 2, 2)},
+		{Region: "Sweden", Number: 3, IP: net.IPv4(3, 3, 3, 3)},
+	}
+	serversWithKeys := []models.NordvpnServer{
+		{Region: "Canada", Number: 1, IP: net.IPv4(1, 1, 1, 1), WgPubKey: "stored1"},
+		{Region: "Canada", Number: 2, IP: net.IPv4(2, 2, 2, 2)},
+	}
+
+	testCases := map[string]struct {
+		servers           []models.NordvpnServer
+		regions           []string
+		credentialsStatus int
+		serversFetched    bool
+		wireguard         configuration.Wireguard
+		err               error
+		errMessage        string
+	}{
+		"public keys fetched": {
+			servers:           servers,
+			regions:           []string{"canada"},
+			credentialsStatus: http.StatusOK,
+			serversFetched:    true,
+			wireguard: configuration.Wireguard{
+				Token:        "token",
+				PrivateKey:   "private",
+				PublicKey:    "key1",
+				EndpointIP:   net.IPv4(1, 1, 1, 1),
+				EndpointPort: 51820,
+			},
+		},
+		"stored public keys used": {
+			servers:           serversWithKeys,
+			regions:           []string{"canada"},
+			credentialsStatus: http.StatusOK,
+			wireguard: configuration.Wireguard{
+				Token:        "token",
+				PrivateKey:   "private",
+				PublicKey:    "stored1",
+				EndpointIP:   net.IPv4(1, 1, 1, 1),
+				EndpointPort: 51820,
+			},
+		},
+		"no server matching selection": {
+			servers:    servers,
+			regions:    []string{"france"},
+			err:        ErrNordlynxNoServer,
+			errMessage: "no NordLynx server found: no server found: for regions france (0 of 3 servers match)",
+		},
+		"private key not obtained": {
+			servers:           servers,
+			regions:           []string{"sweden"},
+			credentialsStatus: http.StatusUnauthorized,
+			serversFetched:    true,
+			err:               ErrNordlynxPrivateKey,
+			errMessage:        "cannot obtain NordLynx private key: HTTP status code not OK: 401 Unauthorized",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var serversFetched int32
+			mux := http.NewServeMux()
+			mux.HandleFunc("/servers", func(w http.ResponseWriter, r *http.Request) {
+				atomic.StoreInt32(&serversFetched, 1)
+				_, _ = w.Write([]byte(nordlynxServersResponse))
+			})
+			mux.HandleFunc("/credentials", func(w http.ResponseWriter, r *http.Request) {
+				user, password, ok := r.BasicAuth()
+				assert.True(t, ok)
+				assert.Equal(t, "token", user)
+				assert.Equal(t, "token", password)
+				w.WriteHeader(testCase.credentialsStatus)
+				_, _ = w.Write([]byte(`{"nordlynx_private_key": "private"}`))
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			settings := configuration.Wireguard{
+				Token:        "token",
+				EndpointPort: 51820,
+			}
+			settings.ServerSelection.Regions = testCase.regions
+			allServers := models.AllServers{
+				Nordvpn: models.NordvpnServers{Servers: testCase.servers},
+			}
+			serversBefore := copyNordvpnServers(testCase.servers)
+
+			tokens := newTokenCache("/tokens.json", time.Now)
+			wireguard, err := nordlynxSettings(context.Background(), server.Client(), openFile,
+				tokens, server.URL+"/credentials", server.URL+"/servers", settings, allServers)
+
+			if testCase.err != nil {
+				require.ErrorIs(t, err, testCase.err)
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				require.NoError(t, err)
+			}
+			expected := testCase.wireguard
+			expected.ServerSelection = settings.ServerSelection
+			if testCase.err != nil {
+				expected = configuration.Wireguard{}
+			}
+			assert.Equal(t, expected, wireguard)
+			assert.Equal(t, testCase.serversFetched, atomic.LoadInt32(&serversFetched) == 1)
+			assert.Equal(t, serversBefore, testCase.servers, "servers given must not be modified")
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/gluetun/internal/constants"
@@ -19,38 +20,49 @@ import (
 type nordvpn struct {
 	servers    []models.NordvpnServer
 	randSource rand.Source
+	latencies  models.IPLatencies
 }
 
-func newNordvpn(servers []models.NordvpnServer, timeNow timeNowFunc) *nordvpn {
+func newNordvpn(servers []models.NordvpnServer, latencies models.IPLatencies,
+	timeNow timeNowFunc) *nordvpn {
 	return &nordvpn{
 		servers:    servers,
 		randSource: rand.NewSource(timeNow().UnixNano()),
+		latencies:  latencies,
 	}
 }
 
-func (n *nordvpn) filterServers(regions []string, protocol string, numbers []uint16) (
-	servers []models.NordvpnServer) {
-	numbersStr := make([]string, len(numbers))
-	for i := range numbers {
-		numbersStr[i] = fmt.Sprintf("%d", numbers[i])
-	}
-	for _, server := range n.servers {
-		numberStr := fmt.Sprintf("%d", server.Number)
-		switch {
-		case
-			protocol == constants.TCP && !server.TCP,
-			protocol == constants.UDP && !server.UDP,
-			filterByPossibilities(server.Region, regions),
-			filterByPossibilities(numberStr, numbersStr):
-		default:
+func (n *nordvpn) filterServers(selection configuration.ServerSelection) (
+	servers []models.NordvpnServer, err error) {
+	filters := selectionFilters(selection)
+	attributes := make([]serverAttributes, len(n.servers))
+	for i, server := range n.servers {
+		attributes[i] = serverAttributes{
+			region: server.Region, number: server.Number,
+			tcp: server.TCP, udp: server.UDP, multiHop: server.MultiHop,
+			features: server.Features,
+		}
+		if !filterServer(filters, attributes[i]) {
 			servers = append(servers, server)
 		}
 	}
-	return servers
+	if len(servers) == 0 {
+		return nil, noServerFoundError(filters, attributes)
+	}
+	return servers, nil
 }
 
 func (n *nordvpn) GetOpenVPNConnection(selection configuration.ServerSelection) (
 	connection models.OpenVPNConnection, err error) {
+	connections, err := n.GetOpenVPNConnections(selection)
+	if err != nil {
+		return connection, err
+	}
+	return pickConnection(connections, selection, n.randSource, n.latencies), nil
+}
+
+func (n *nordvpn) GetOpenVPNConnections(selection configuration.ServerSelection) (
+	connections []models.OpenVPNConnection, err error) {
 	var port uint16
 	switch {
 	case selection.Protocol == constants.UDP:
@@ -58,36 +70,28 @@ func (n *nordvpn) GetOpenVPNConnection(selection configuration.ServerSelection)
 	case selection.Protocol == constants.TCP:
 		port = 443
 	default:
-		return connection, fmt.Errorf("protocol %q is unknown", selection.Protocol)
+		return nil, fmt.Errorf("protocol %q is unknown", selection.Protocol)
 	}
 
 	if selection.TargetIP != nil {
-		return models.OpenVPNConnection{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}, nil
+		return []models.OpenVPNConnection{{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}}, nil
 	}
 
-	servers := n.filterServers(selection.Regions, selection.Protocol, selection.Numbers)
-	if len(servers) == 0 {
-		return connection, fmt.Errorf("no server found for region %s, protocol %s and numbers %v",
-			commaJoin(selection.Regions), selection.Protocol, selection.Numbers)
+	servers, err := n.filterServers(selection)
+	if err != nil {
+		return nil, err
 	}
 
-	connections := make([]models.OpenVPNConnection, len(servers))
+	connections = make([]models.OpenVPNConnection, len(servers))
 	for i := range servers {
 		connections[i] = models.OpenVPNConnection{IP: servers[i].IP, Port: port, Protocol: selection.Protocol}
 	}
 
-	return pickRandomConnection(connections, n.randSource), nil
+	return connections, nil
 }
 
 func (n *nordvpn) BuildConf(connection models.OpenVPNConnection,
 	username string, settings configuration.OpenVPN) (lines []string) {
-	if len(settings.Cipher) == 0 {
-		settings.Cipher = aes256cbc
-	}
-	if len(settings.Auth) == 0 {
-		settings.Auth = "sha512"
-	}
-
 	const defaultMSSFix = 1450
 	if settings.MSSFix == 0 {
 		settings.MSSFix = defaultMSSFix
@@ -124,10 +128,9 @@ func (n *nordvpn) BuildConf(connection models.OpenVPNConnection,
 		fmt.Sprintf("auth-user-pass %s", constants.OpenVPNAuthConf),
 		fmt.Sprintf("proto %s", connection.Protocol),
 		fmt.Sprintf("remote %s %d", connection.IP.String(), connection.Port),
-		"data-ciphers-fallback " + settings.Cipher,
-		"data-ciphers " + settings.Cipher,
-		fmt.Sprintf("auth %s", settings.Auth),
 	}
+	lines = append(lines, cipherProfiles[constants.Nordvpn].lines(settings.Cipher, settings.Auth)...)
+	lines = append(lines, ipv6Lines(settings.Provider.ExtraConfigOptions.OpenVPNIPv6)...)
 	if !settings.Root {
 		lines = append(lines, "user "+username)
 	}
@@ -151,6 +154,6 @@ func (n *nordvpn) BuildConf(connection models.OpenVPNConnection,
 
 func (n *nordvpn) PortForward(ctx context.Context, client *http.Client,
 	openFile os.OpenFileFunc, pfLogger logging.Logger, gateway net.IP, fw firewall.Configurator,
-	syncState func(port uint16) (pfFilepath string)) {
+	syncState func(port uint16, expiration time.Time) (pfFilepath string)) {
 	panic("port forwarding is not supported for nordvpn")
 }
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/os"
+)
+
+var (
+	ErrPIADedicatedIPNotFound  = errors.New("no PIA dedicated IP found for token")
+	ErrPIADedicatedIPNotActive = errors.New("PIA dedicated IP is not active")
+	ErrPIADedicatedIPInvalid   = errors.New("PIA dedicated IP address is not valid")
+)
+
+const piaDedicatedIPURL = "https://www.privateinternetaccess.com/api/client/v2/dedicated_ip"
+
+// PIADedicatedIP exchanges the dedicated IP token for the dedicated server
+// using the PIA API. It must be called before the firewall is enabled.
+func PIADedicatedIP(ctx context.Context, client *http.Client, openFile os.OpenFileFunc,
+	username, password, dipToken string) (server models.PIAServer, err error) {
+	tokens := newTokenCache(constants.APITokens, time.Now)
+	return fetchPIADedicatedIP(ctx, client, openFile, tokens, piaDedicatedIPURL,
+		username, password, dipToken)
+}
+
+func fetchPIADedicatedIP(ctx context.Context, client *http.Client, openFile os.OpenFileFunc,
+	tokens *tokenCache, url, username, password, dipToken string) (server models.PIAServer, err error) {
+	token, err := cachedPIAToken(ctx, client, openFile, tokens, username, password)
+	if err != nil {
+		return server, fmt.Errorf("cannot obtain token: %w", err)
+	}
+
+	body, err := json.Marshal(struct {
+		Tokens []string `json:"tokens"`
+	}{Tokens: []string{dipToken}})
+	if err != nil {
+		return server, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return server, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Token "+token)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return server, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusUnauthorized {
+		// The cached token may have been revoked, so fetch a new one next time
+		tokens.invalidate(openFile, constants.PrivateInternetAccess, []string{username, password})
+	}
+	if response.StatusCode != http.StatusOK {
+		return server, fmt.Errorf("%w: %s", ErrHTTPStatusCodeNotOK, response.Status)
+	}
+
+	var data []struct {
+		Status string `json:"status"`
+		IP     string `json:"ip"`
+		CN     string `json:"cn"`
+		ID     string `json:"id"`
+	}
+	decoder := json.NewDecoder(response.Body)
+	if err := decoder.Decode(&data); err != nil {
+		return server, err
+	}
+
+	switch {
+	case len(data) == 0:
+		return server, ErrPIADedicatedIPNotFound
+	case data[0].Status != "active":
+		return server, fmt.Errorf("%w: status is %s", ErrPIADedicatedIPNotActive, data[0].Status)
+	}
+
+	ip := net.ParseIP(data[0].IP)
+	if ip == nil {
+		return server, fmt.Errorf("%w: %s", ErrPIADedicatedIPInvalid, data[0].IP)
+	}
+
+	return models.PIAServer{
+		Region:     "dedicated IP",
+		ServerName: data[0].CN,
+		TCP:        true,
+		UDP:        true,
+		// Port forwarding is not available for US dedicated IPs
+		PortForward: !strings.HasPrefix(data[0].ID, "us_"),
+		IP:          ip,
+	}, nil
+}
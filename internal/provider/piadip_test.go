@@ -0,0 +1,185 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	nativeos "os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/os"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePIADedicatedIPServer is a fake PIA API handing out the token
+// "token" and answering dedicated IP requests with the status code
+// and body given.
+type fakePIADedicatedIPServer struct {
+	t          *testing.T
+	statusCode int
+	body       string
+
+	mu             sync.Mutex
+	tokenRequests  int
+	dedicatedToken string
+}
+
+func (s *fakePIADedicatedIPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.URL.Path {
+	case "/gtoken/generateToken":
+		s.tokenRequests++
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": "token"})
+	case "/api/client/v2/dedicated_ip":
+		assert.Equal(s.t, http.MethodPost, r.Method)
+		assert.Equal(s.t, "Token token", r.Header.Get("Authorization"))
+		assert.Equal(s.t, "application/json", r.Header.Get("Content-Type"))
+		var body struct {
+			Tokens []string `json:"tokens"`
+		}
+		assert.NoError(s.t, json.NewDecoder(r.Body).Decode(&body))
+		if assert.Len(s.t, body.Tokens, 1) {
+			s.dedicatedToken = body.Tokens[0]
+		}
+		w.WriteHeader(s.statusCode)
+		_, _ = w.Write([]byte(s.body))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func Test_fetchPIADedicatedIP(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		statusCode    int
+		body          string
+		server        models.PIAServer
+		tokenRequests int
+		err           error
+		errMessage    string
+	}{
+		"active dedicated IP": {
+			statusCode: http.StatusOK,
+			body:       `[{"status":"active","ip":"1.2.3.4","cn":"server","id":"de_frankfurt"}]`,
+			server: models.PIAServer{
+				Region:      "dedicated IP",
+				ServerName:  "server",
+				TCP:         true,
+				UDP:         true,
+				PortForward: true,
+				IP:          net.IPv4(1, 2, 3, 4),
+			},
+			tokenRequests: 1,
+		},
+		"US dedicated IP without port forwarding": {
+			statusCode: http.StatusOK,
+			body:       `[{"status":"active","ip":"1.2.3.4","cn":"server","id":"us_chicago"}]`,
+			server: models.PIAServer{
+				Region:     "dedicated IP",
+				ServerName: "server",
+				TCP:        true,
+				UDP:        true,
+				IP:         net.IPv4(1, 2, 3, 4),
+			},
+			tokenRequests: 1,
+		},
+		"no dedicated IP": {
+			statusCode:    http.StatusOK,
+			body:          `[]`,
+			tokenRequests: 1,
+			err:           ErrPIADedicatedIPNotFound,
+			errMessage:    "no PIA dedicated IP found for token",
+		},
+		"inactive dedicated IP": {
+			statusCode:    http.StatusOK,
+			body:          `[{"status":"expired","ip":"1.2.3.4","cn":"server","id":"de_frankfurt"}]`,
+			tokenRequests: 1,
+			err:           ErrPIADedicatedIPNotActive,
+			errMessage:    "PIA dedicated IP is not active: status is expired",
+		},
+		"invalid dedicated IP": {
+			statusCode:    http.StatusOK,
+			body:          `[{"status":"active","ip":"x","cn":"server","id":"de_frankfurt"}]`,
+			tokenRequests: 1,
+			err:           ErrPIADedicatedIPInvalid,
+			errMessage:    "PIA dedicated IP address is not valid: x",
+		},
+		"malformed response": {
+			statusCode:    http.StatusOK,
+			body:          `{`,
+			tokenRequests: 1,
+			errMessage:    "unexpected EOF",
+		},
+		"bad status": {
+			statusCode:    http.StatusInternalServerError,
+			tokenRequests: 1,
+			err:           ErrHTTPStatusCodeNotOK,
+			errMessage:    "HTTP status code not OK: 500 Internal Server Error",
+		},
+		"unauthorized invalidates the token": {
+			statusCode:    http.StatusUnauthorized,
+			tokenRequests: 2,
+			err:           ErrHTTPStatusCodeNotOK,
+			errMessage:    "HTTP status code not OK: 401 Unauthorized",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := &fakePIADedicatedIPServer{
+				t:          t,
+				statusCode: testCase.statusCode,
+				body:       testCase.body,
+			}
+			server := httptest.NewServer(handler)
+			defer server.Close()
+			client := &http.Client{
+				Transport: &rewriteTransport{host: server.Listener.Addr().String()},
+			}
+
+			dir := t.TempDir()
+			openFile := func(name string, flag int, perm os.FileMode) (os.File, error) {
+				path := filepath.Join(dir, filepath.Base(name))
+				return nativeos.OpenFile(path, flag, nativeos.FileMode(perm))
+			}
+
+			tokens := newTokenCache(constants.APITokens, time.Now)
+			url := server.URL + "/api/client/v2/dedicated_ip"
+
+			piaServer, err := fetchPIADedicatedIP(context.Background(), client, openFile,
+				tokens, url, "user", "password", "dip token")
+
+			if testCase.err != nil {
+				assert.ErrorIs(t, err, testCase.err)
+			}
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, testCase.server, piaServer)
+
+			// A second exchange reuses the cached token unless it was invalidated
+			_, _ = fetchPIADedicatedIP(context.Background(), client, openFile,
+				tokens, url, "user", "password", "dip token")
+
+			handler.mu.Lock()
+			defer handler.mu.Unlock()
+			assert.Equal(t, "dip token", handler.dedicatedToken)
+			assert.Equal(t, testCase.tokenRequests, handler.tokenRequests)
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math/rand"
 	"net"
@@ -30,14 +31,25 @@ type pia struct {
 	servers      []models.PIAServer
 	timeNow      timeNowFunc
 	randSource   rand.Source
+	latencies    models.IPLatencies
 	activeServer models.PIAServer
+	// newPrivateIPClient returns the HTTP client to reach
+	// the port forwarding API of the VPN server given.
+	newPrivateIPClient func(serverName string) (client *http.Client, err error)
+	retryPeriod        time.Duration
+	tokens             *tokenCache
 }
 
-func newPrivateInternetAccess(servers []models.PIAServer, timeNow timeNowFunc) *pia {
+func newPrivateInternetAccess(servers []models.PIAServer, latencies models.IPLatencies,
+	timeNow timeNowFunc) *pia {
 	return &pia{
-		servers:    servers,
-		timeNow:    timeNow,
-		randSource: rand.NewSource(timeNow().UnixNano()),
+		servers:            servers,
+		timeNow:            timeNow,
+		randSource:         rand.NewSource(timeNow().UnixNano()),
+		latencies:          latencies,
+		newPrivateIPClient: newPIAHTTPClient,
+		retryPeriod:        defaultRetryPeriod,
+		tokens:             newTokenCache(constants.APITokens, timeNow),
 	}
 }
 
@@ -95,32 +107,14 @@ func (p *pia) getPort(selection configuration.ServerSelection) (port uint16, err
 
 func (p *pia) GetOpenVPNConnection(selection configuration.ServerSelection) (
 	connection models.OpenVPNConnection, err error) {
-	port, err := p.getPort(selection)
+	connections, err := p.GetOpenVPNConnections(selection)
 	if err != nil {
 		return connection, err
 	}
-
-	servers := p.servers
-	if selection.TargetIP != nil {
-		connection = models.OpenVPNConnection{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}
-	} else {
-		servers := filterPIAServers(servers, selection.Regions, selection.Protocol)
-		if len(servers) == 0 {
-			return connection, fmt.Errorf("no server found for region %s and protocol %s",
-				commaJoin(selection.Regions), selection.Protocol)
-		}
-
-		var connections []models.OpenVPNConnection
-		for _, server := range servers {
-			connection := models.OpenVPNConnection{IP: server.IP, Port: port, Protocol: selection.Protocol}
-			connections = append(connections, connection)
-		}
-
-		connection = pickRandomConnection(connections, p.randSource)
-	}
+	connection = pickConnection(connections, selection, p.randSource, p.latencies)
 
 	// Reverse lookup server from picked connection
-	for _, server := range servers {
+	for _, server := range p.servers {
 		if connection.IP.Equal(server.IP) {
 			p.activeServer = server
 			break
@@ -130,27 +124,45 @@ func (p *pia) GetOpenVPNConnection(selection configuration.ServerSelection) (
 	return connection, nil
 }
 
+func (p *pia) GetOpenVPNConnections(selection configuration.ServerSelection) (
+	connections []models.OpenVPNConnection, err error) {
+	port, err := p.getPort(selection)
+	if err != nil {
+		return nil, err
+	}
+
+	if selection.TargetIP != nil {
+		return []models.OpenVPNConnection{{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}}, nil
+	}
+
+	servers, err := p.filterServers(selection)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, server := range servers {
+		connection := models.OpenVPNConnection{IP: server.IP, Port: port, Protocol: selection.Protocol}
+		connections = append(connections, connection)
+	}
+
+	return connections, nil
+}
+
 func (p *pia) BuildConf(connection models.OpenVPNConnection,
 	username string, settings configuration.OpenVPN) (lines []string) {
 	var X509CRL, certificate string
-	var defaultCipher, defaultAuth string
+	cipherProfile := cipherProfiles[constants.PrivateInternetAccess]
 	if settings.Provider.ExtraConfigOptions.EncryptionPreset == constants.PIAEncryptionPresetNormal {
-		defaultCipher = "aes-128-cbc"
-		defaultAuth = "sha1"
+		cipherProfile.fallbackCipher = aes128cbc
+		cipherProfile.auth = sha1
 		X509CRL = constants.PiaX509CRLNormal
 		certificate = constants.PIACertificateNormal
 	} else { // strong encryption
-		defaultCipher = aes256cbc
-		defaultAuth = "sha256"
+		cipherProfile.fallbackCipher = aes256cbc
+		cipherProfile.auth = sha256
 		X509CRL = constants.PiaX509CRLStrong
 		certificate = constants.PIACertificateStrong
 	}
-	if len(settings.Cipher) == 0 {
-		settings.Cipher = defaultCipher
-	}
-	if len(settings.Auth) == 0 {
-		settings.Auth = defaultAuth
-	}
 	lines = []string{
 		"client",
 		"dev tun",
@@ -175,13 +187,9 @@ func (p *pia) BuildConf(connection models.OpenVPNConnection,
 		fmt.Sprintf("auth-user-pass %s", constants.OpenVPNAuthConf),
 		fmt.Sprintf("proto %s", connection.Protocol),
 		fmt.Sprintf("remote %s %d", connection.IP, connection.Port),
-		"data-ciphers-fallback " + settings.Cipher,
-		"data-ciphers " + settings.Cipher,
-		fmt.Sprintf("auth %s", settings.Auth),
-	}
-	if strings.HasSuffix(settings.Cipher, "-gcm") {
-		lines = append(lines, "ncp-disable")
 	}
+	lines = append(lines, cipherProfile.lines(settings.Cipher, settings.Auth)...)
+	lines = append(lines, ipv6Lines(settings.Provider.ExtraConfigOptions.OpenVPNIPv6)...)
 	if !settings.Root {
 		lines = append(lines, "user "+username)
 	}
@@ -209,7 +217,7 @@ func (p *pia) BuildConf(connection models.OpenVPNConnection,
 //nolint:gocognit
 func (p *pia) PortForward(ctx context.Context, client *http.Client,
 	openFile os.OpenFileFunc, pfLogger logging.Logger, gateway net.IP, fw firewall.Configurator,
-	syncState func(port uint16) (pfFilepath string)) {
+	syncState func(port uint16, expiration time.Time) (pfFilepath string)) {
 	commonName := p.activeServer.ServerName
 	if !p.activeServer.PortForward {
 		pfLogger.Error("The server %s (region %s) does not support port forwarding",
@@ -221,7 +229,7 @@ func (p *pia) PortForward(ctx context.Context, client *http.Client,
 		return
 	}
 
-	privateIPClient, err := newPIAHTTPClient(commonName)
+	privateIPClient, err := p.newPrivateIPClient(commonName)
 	if err != nil {
 		pfLogger.Error("aborting because: %s", err)
 		return
@@ -231,22 +239,25 @@ func (p *pia) PortForward(ctx context.Context, client *http.Client,
 	if err != nil {
 		pfLogger.Error(err)
 	}
-	dataFound := data.Port > 0
 	durationToExpiration := data.Expiration.Sub(p.timeNow())
-	expired := durationToExpiration <= 0
+	reused := false
 
-	if dataFound {
+	if data.Port > 0 {
 		pfLogger.Info("Found persistent forwarded port data for port %d", data.Port)
-		if expired {
+		switch {
+		case durationToExpiration <= 0:
 			pfLogger.Warn("Forwarded port data expired on %s, getting another one", data.Expiration.Format(time.RFC1123))
-		} else {
+		case data.ServerName != commonName:
+			pfLogger.Info("Forwarded port data is for another server, getting another one")
+		default:
 			pfLogger.Info("Forwarded port data expires in %s", gluetunLog.FormatDuration(durationToExpiration))
+			reused = true
 		}
 	}
 
-	if !dataFound || expired {
-		tryUntilSuccessful(ctx, pfLogger, func() error {
-			data, err = refreshPIAPortForwardData(ctx, client, privateIPClient, gateway, openFile)
+	if !reused {
+		tryUntilSuccessful(ctx, pfLogger, p.retryPeriod, func() error {
+			data, err = refreshPIAPortForwardData(ctx, client, privateIPClient, gateway, openFile, p.tokens, commonName)
 			return err
 		})
 		if ctx.Err() != nil {
@@ -257,7 +268,24 @@ func (p *pia) PortForward(ctx context.Context, client *http.Client,
 	pfLogger.Info("Port forwarded is %d expiring in %s", data.Port, gluetunLog.FormatDuration(durationToExpiration))
 
 	// First time binding
-	tryUntilSuccessful(ctx, pfLogger, func() error {
+	tryUntilSuccessful(ctx, pfLogger, p.retryPeriod, func() error {
+		err := bindPIAPort(ctx, privateIPClient, gateway, data)
+		if err == nil {
+			return nil
+		} else if !reused {
+			return fmt.Errorf("cannot bind port: %w", err)
+		}
+		// The server may no longer accept the persisted data
+		pfLogger.Warn("cannot bind persisted port %d: %s, getting another one", data.Port, err)
+		newData, err := refreshPIAPortForwardData(ctx, client, privateIPClient, gateway, openFile, p.tokens, commonName)
+		if err != nil {
+			// the persisted data is kept to be refreshed again on the next try
+			return err
+		}
+		reused = false
+		data = newData
+		durationToExpiration = data.Expiration.Sub(p.timeNow())
+		pfLogger.Info("Port forwarded is %d expiring in %s", data.Port, gluetunLog.FormatDuration(durationToExpiration))
 		if err := bindPIAPort(ctx, privateIPClient, gateway, data); err != nil {
 			return fmt.Errorf("cannot bind port: %w", err)
 		}
@@ -267,13 +295,13 @@ func (p *pia) PortForward(ctx context.Context, client *http.Client,
 		return
 	}
 
-	filepath := syncState(data.Port)
+	filepath := syncState(data.Port, data.Expiration)
 	pfLogger.Info("Writing port to %s", filepath)
 	if err := writePortForwardedToFile(openFile, filepath, data.Port); err != nil {
 		pfLogger.Error(err)
 	}
 
-	if err := fw.SetAllowedPort(ctx, data.Port, string(constants.TUN)); err != nil {
+	if err := fw.SetAllowedPort(ctx, data.Port, fw.GetVPNInterface()); err != nil {
 		pfLogger.Error(err)
 	}
 
@@ -304,23 +332,30 @@ func (p *pia) PortForward(ctx context.Context, client *http.Client,
 		case <-expiryTimer.C:
 			pfLogger.Warn("Forward port has expired on %s, getting another one", data.Expiration.Format(time.RFC1123))
 			oldPort := data.Port
-			for {
-				data, err = refreshPIAPortForwardData(ctx, client, privateIPClient, gateway, openFile)
-				if err != nil {
+			tryUntilSuccessful(ctx, pfLogger, p.retryPeriod, func() error {
+				data, err = refreshPIAPortForwardData(ctx, client, privateIPClient, gateway, openFile, p.tokens, commonName)
+				return err
+			})
+			if ctx.Err() != nil {
+				removeCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				if err := fw.RemoveAllowedPort(removeCtx, oldPort); err != nil {
 					pfLogger.Error(err)
-					continue
 				}
-				break
+				if !keepAliveTimer.Stop() {
+					<-keepAliveTimer.C
+				}
+				return
 			}
 			durationToExpiration := data.Expiration.Sub(p.timeNow())
 			pfLogger.Info("Port forwarded is %d expiring in %s", data.Port, gluetunLog.FormatDuration(durationToExpiration))
 			if err := fw.RemoveAllowedPort(ctx, oldPort); err != nil {
 				pfLogger.Error(err)
 			}
-			if err := fw.SetAllowedPort(ctx, data.Port, string(constants.TUN)); err != nil {
+			if err := fw.SetAllowedPort(ctx, data.Port, fw.GetVPNInterface()); err != nil {
 				pfLogger.Error(err)
 			}
-			filepath := syncState(data.Port)
+			filepath := syncState(data.Port, data.Expiration)
 			pfLogger.Info("Writing port to %s", filepath)
 			if err := writePortForwardedToFile(openFile, filepath, data.Port); err != nil {
 				pfLogger.Error(err)
@@ -337,18 +372,22 @@ func (p *pia) PortForward(ctx context.Context, client *http.Client,
 	}
 }
 
-func filterPIAServers(servers []models.PIAServer, regions []string, protocol string) (
-	filtered []models.PIAServer) {
-	for _, server := range servers {
-		switch {
-		case filterByPossibilities(server.Region, regions),
-			protocol == constants.TCP && !server.TCP,
-			protocol == constants.UDP && !server.UDP:
-		default:
-			filtered = append(filtered, server)
+func (p *pia) filterServers(selection configuration.ServerSelection) (
+	servers []models.PIAServer, err error) {
+	filters := selectionFilters(selection)
+	attributes := make([]serverAttributes, len(p.servers))
+	for i, server := range p.servers {
+		attributes[i] = serverAttributes{
+			region: server.Region, tcp: server.TCP, udp: server.UDP,
+		}
+		if !filterServer(filters, attributes[i]) {
+			servers = append(servers, server)
 		}
 	}
-	return filtered
+	if len(servers) == 0 {
+		return nil, noServerFoundError(filters, attributes)
+	}
+	return servers, nil
 }
 
 func newPIAHTTPClient(serverName string) (client *http.Client, err error) {
@@ -391,13 +430,17 @@ func newPIAHTTPClient(serverName string) (client *http.Client, err error) {
 }
 
 func refreshPIAPortForwardData(ctx context.Context, client, privateIPClient *http.Client,
-	gateway net.IP, openFile os.OpenFileFunc) (data piaPortForwardData, err error) {
-	data.Token, err = fetchPIAToken(ctx, openFile, client)
+	gateway net.IP, openFile os.OpenFileFunc, tokens *tokenCache, serverName string) (
+	data piaPortForwardData, err error) {
+	data.ServerName = serverName
+	data.Token, err = fetchPIAToken(ctx, openFile, tokens, client)
 	if err != nil {
 		return data, fmt.Errorf("cannot obtain token: %w", err)
 	}
 	data.Port, data.Signature, data.Expiration, err = fetchPIAPortForwardData(ctx, privateIPClient, gateway, data.Token)
 	if err != nil {
+		// The cached token may have been revoked, so fetch a new one next time
+		invalidatePIAToken(openFile, tokens)
 		return data, fmt.Errorf("cannot obtain port forwarding data: %w", err)
 	}
 	if err := writePIAPortForwardData(openFile, data); err != nil {
@@ -417,6 +460,9 @@ type piaPortForwardData struct {
 	Token      string    `json:"token"`
 	Signature  string    `json:"signature"`
 	Expiration time.Time `json:"expires_at"`
+	// ServerName is the name of the server the port is forwarded on,
+	// since the port forwarding data is only valid for this server.
+	ServerName string `json:"server_name"`
 }
 
 func readPIAPortForwardData(openFile os.OpenFileFunc) (data piaPortForwardData, err error) {
@@ -429,7 +475,9 @@ func readPIAPortForwardData(openFile os.OpenFileFunc) (data piaPortForwardData,
 
 	decoder := json.NewDecoder(file)
 	err = decoder.Decode(&data)
-	if err != nil {
+	if errors.Is(err, io.EOF) { // file emptied to renew the port forwarded
+		return data, file.Close()
+	} else if err != nil {
 		_ = file.Close()
 		return data, err
 	}
@@ -480,12 +528,39 @@ func packPIAPayload(port uint16, token string, expiration time.Time) (payload st
 	return payload, nil
 }
 
-func fetchPIAToken(ctx context.Context, openFile os.OpenFileFunc,
+func fetchPIAToken(ctx context.Context, openFile os.OpenFileFunc, tokens *tokenCache,
 	client *http.Client) (token string, err error) {
 	username, password, err := getOpenvpnCredentials(openFile)
 	if err != nil {
 		return "", fmt.Errorf("cannot get Openvpn credentials: %w", err)
 	}
+	return cachedPIAToken(ctx, client, openFile, tokens, username, password)
+}
+
+// piaTokenValidity is a bit less than the 24 hours a PIA token is valid for.
+const piaTokenValidity = 23 * time.Hour
+
+// cachedPIAToken returns the cached PIA token for the credentials given,
+// or fetches a new one if it is not cached or expired.
+func cachedPIAToken(ctx context.Context, client *http.Client, openFile os.OpenFileFunc,
+	tokens *tokenCache, username, password string) (token string, err error) {
+	return tokens.get(ctx, openFile, constants.PrivateInternetAccess,
+		[]string{username, password}, piaTokenValidity,
+		func(ctx context.Context) (token string, err error) {
+			return fetchPIATokenWithCredentials(ctx, client, username, password)
+		})
+}
+
+func invalidatePIAToken(openFile os.OpenFileFunc, tokens *tokenCache) {
+	username, password, err := getOpenvpnCredentials(openFile)
+	if err != nil {
+		return
+	}
+	tokens.invalidate(openFile, constants.PrivateInternetAccess, []string{username, password})
+}
+
+func fetchPIATokenWithCredentials(ctx context.Context, client *http.Client,
+	username, password string) (token string, err error) {
 	url := url.URL{
 		Scheme: "https",
 		User:   url.UserPassword(username, password),
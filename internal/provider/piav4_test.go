@@ -1,16 +1,28 @@
 package provider
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	nativeos "os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/golang/mock/gomock"
 	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/firewall/mock_firewall"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/qdm12/golibs/os"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -112,3 +124,217 @@ func makePIAPayload(t *testing.T, token string, port uint16, expiration time.Tim
 
 	return base64.StdEncoding.EncodeToString(b)
 }
+
+// rewriteTransport sends all the requests to the test
+// server host given, whatever their scheme and host.
+type rewriteTransport struct {
+	host string
+}
+
+func (t *rewriteTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request = request.Clone(request.Context())
+	request.URL.Scheme = "http"
+	request.URL.Host = t.host
+	return http.DefaultTransport.RoundTrip(request)
+}
+
+// fakePIAServer is a fake PIA API handing out the ports given in
+// order, where a zero port is an error, and refusing to bind the
+// rejected ports.
+type fakePIAServer struct {
+	ports      []uint16
+	rejected   map[uint16]struct{}
+	expiration time.Time
+
+	mu         sync.Mutex
+	signatures int
+	bound      []uint16
+}
+
+func (s *fakePIAServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	encoder := json.NewEncoder(w)
+	switch r.URL.Path {
+	case "/gtoken/generateToken":
+		_ = encoder.Encode(map[string]string{"token": "token"})
+	case "/getSignature":
+		if s.signatures >= len(s.ports) || s.ports[s.signatures] == 0 {
+			s.signatures++
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		payload, err := packPIAPayload(s.ports[s.signatures], "token", s.expiration)
+		s.signatures++
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = encoder.Encode(map[string]string{
+			"status": "OK", "payload": payload, "signature": "signature"})
+	case "/bindPort":
+		port, _, _, err := unpackPIAPayload(r.URL.Query().Get("payload"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.bound = append(s.bound, port)
+		if _, rejected := s.rejected[port]; rejected {
+			_ = encoder.Encode(map[string]string{"status": "ERROR", "message": "rejected"})
+			return
+		}
+		_ = encoder.Encode(map[string]string{"status": "OK"})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func Test_pia_PortForward(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1600000000, 0).UTC()
+	expiration := now.Add(48 * time.Hour)
+	persisted := piaPortForwardData{
+		Port:       2000,
+		Token:      "token",
+		Signature:  "signature",
+		Expiration: expiration,
+		ServerName: "server",
+	}
+	otherServer := persisted
+	otherServer.ServerName = "other"
+	expired := persisted
+	expired.Expiration = now.Add(-time.Hour)
+
+	testCases := map[string]struct {
+		persisted  *piaPortForwardData
+		ports      []uint16
+		rejected   []uint16
+		port       uint16
+		bound      []uint16
+		signatures int
+	}{
+		"no persisted data": {
+			ports:      []uint16{1000},
+			port:       1000,
+			bound:      []uint16{1000},
+			signatures: 1,
+		},
+		"persisted data reused": {
+			persisted: &persisted,
+			port:      2000,
+			bound:     []uint16{2000},
+		},
+		"persisted data for another server": {
+			persisted:  &otherServer,
+			ports:      []uint16{1000},
+			port:       1000,
+			bound:      []uint16{1000},
+			signatures: 1,
+		},
+		"persisted data expired": {
+			persisted:  &expired,
+			ports:      []uint16{1000},
+			port:       1000,
+			bound:      []uint16{1000},
+			signatures: 1,
+		},
+		"persisted port rejected": {
+			persisted:  &persisted,
+			ports:      []uint16{1000},
+			rejected:   []uint16{2000},
+			port:       1000,
+			bound:      []uint16{2000, 1000},
+			signatures: 1,
+		},
+		"persisted port rejected and first refresh failing": {
+			persisted:  &persisted,
+			ports:      []uint16{0, 1000},
+			rejected:   []uint16{2000},
+			port:       1000,
+			bound:      []uint16{2000, 2000, 1000},
+			signatures: 2,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			dir := t.TempDir()
+			openFile := func(name string, flag int, perm os.FileMode) (os.File, error) {
+				path := filepath.Join(dir, filepath.Base(name))
+				return nativeos.OpenFile(path, flag, nativeos.FileMode(perm))
+			}
+			err := ioutil.WriteFile(filepath.Join(dir, filepath.Base(constants.OpenVPNAuthConf)),
+				[]byte("user\npassword"), 0600)
+			require.NoError(t, err)
+			if testCase.persisted != nil {
+				err = writePIAPortForwardData(openFile, *testCase.persisted)
+				require.NoError(t, err)
+			}
+
+			piaServer := &fakePIAServer{
+				ports:      testCase.ports,
+				rejected:   make(map[uint16]struct{}, len(testCase.rejected)),
+				expiration: expiration,
+			}
+			for _, port := range testCase.rejected {
+				piaServer.rejected[port] = struct{}{}
+			}
+			server := httptest.NewServer(piaServer)
+			defer server.Close()
+			client := &http.Client{
+				Transport: &rewriteTransport{host: server.Listener.Addr().String()},
+			}
+
+			logger := mock_logging.NewMockLogger(ctrl)
+			logger.EXPECT().Info(gomock.Any()).AnyTimes()
+			logger.EXPECT().Warn(gomock.Any()).AnyTimes()
+			logger.EXPECT().Error(gomock.Any()).AnyTimes()
+			fw := mock_firewall.NewMockConfigurator(ctrl)
+			fw.EXPECT().GetVPNInterface().Return("tun1")
+			fw.EXPECT().SetAllowedPort(gomock.Any(), testCase.port, "tun1").Return(nil)
+			fw.EXPECT().RemoveAllowedPort(gomock.Any(), testCase.port).Return(nil)
+
+			p := newPrivateInternetAccess(nil, nil, func() time.Time { return now })
+			p.activeServer = models.PIAServer{ServerName: "server", PortForward: true}
+			p.newPrivateIPClient = func(string) (*http.Client, error) { return client, nil }
+			p.retryPeriod = time.Millisecond
+
+			synced := make(chan uint16, 1)
+			syncState := func(port uint16, _ time.Time) (pfFilepath string) {
+				synced <- port
+				return filepath.Join(dir, "forwarded_port")
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				p.PortForward(ctx, client, openFile, logger, net.IPv4(10, 0, 0, 1), fw, syncState)
+			}()
+
+			select {
+			case port := <-synced:
+				assert.Equal(t, testCase.port, port)
+			case <-time.After(5 * time.Second):
+				t.Error("port forwarding state not synced")
+			}
+			cancel()
+			<-done
+
+			piaServer.mu.Lock()
+			assert.Equal(t, testCase.bound, piaServer.bound)
+			assert.Equal(t, testCase.signatures, piaServer.signatures)
+			piaServer.mu.Unlock()
+
+			data, err := readPIAPortForwardData(openFile)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.port, data.Port)
+			assert.Equal(t, "server", data.ServerName)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/gluetun/internal/constants"
@@ -19,46 +20,64 @@ import (
 type privado struct {
 	servers    []models.PrivadoServer
 	randSource rand.Source
+	latencies  models.IPLatencies
 }
 
-func newPrivado(servers []models.PrivadoServer, timeNow timeNowFunc) *privado {
+func newPrivado(servers []models.PrivadoServer, latencies models.IPLatencies,
+	timeNow timeNowFunc) *privado {
 	return &privado{
 		servers:    servers,
 		randSource: rand.NewSource(timeNow().UnixNano()),
+		latencies:  latencies,
 	}
 }
 
-func (s *privado) filterServers(hostnames []string) (servers []models.PrivadoServer) {
-	for _, server := range s.servers {
-		switch {
-		case filterByPossibilities(server.Hostname, hostnames):
-		default:
+func (s *privado) filterServers(selection configuration.ServerSelection) (
+	servers []models.PrivadoServer, err error) {
+	filters := selectionFilters(selection)
+	attributes := make([]serverAttributes, len(s.servers))
+	for i, server := range s.servers {
+		attributes[i] = serverAttributes{
+			hostname: server.Hostname, tcp: true, udp: true,
+		}
+		if !filterServer(filters, attributes[i]) {
 			servers = append(servers, server)
 		}
 	}
-	return servers
+	if len(servers) == 0 {
+		return nil, noServerFoundError(filters, attributes)
+	}
+	return servers, nil
 }
 
 func (s *privado) GetOpenVPNConnection(selection configuration.ServerSelection) (
 	connection models.OpenVPNConnection, err error) {
+	connections, err := s.GetOpenVPNConnections(selection)
+	if err != nil {
+		return connection, err
+	}
+	return pickConnection(connections, selection, s.randSource, s.latencies), nil
+}
+
+func (s *privado) GetOpenVPNConnections(selection configuration.ServerSelection) (
+	connections []models.OpenVPNConnection, err error) {
 	var port uint16 = 1194
 	switch selection.Protocol {
 	case constants.UDP:
 	default:
-		return connection, fmt.Errorf("protocol %q is not supported by Privado", selection.Protocol)
+		return nil, fmt.Errorf("protocol %q is not supported by Privado", selection.Protocol)
 	}
 
 	if selection.TargetIP != nil {
-		return models.OpenVPNConnection{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}, nil
+		return []models.OpenVPNConnection{{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}}, nil
 	}
 
-	servers := s.filterServers(selection.Hostnames)
-	if len(servers) == 0 {
-		return connection, fmt.Errorf("no server found for cities %s and server numbers %v",
-			commaJoin(selection.Cities), selection.Numbers)
+	servers, err := s.filterServers(selection)
+	if err != nil {
+		return nil, err
 	}
 
-	connections := make([]models.OpenVPNConnection, len(servers))
+	connections = make([]models.OpenVPNConnection, len(servers))
 	for i := range servers {
 		connection := models.OpenVPNConnection{
 			IP:       servers[i].IP,
@@ -69,17 +88,11 @@ func (s *privado) GetOpenVPNConnection(selection configuration.ServerSelection)
 		connections[i] = connection
 	}
 
-	return pickRandomConnection(connections, s.randSource), nil
+	return connections, nil
 }
 
 func (s *privado) BuildConf(connection models.OpenVPNConnection,
 	username string, settings configuration.OpenVPN) (lines []string) {
-	if len(settings.Cipher) == 0 {
-		settings.Cipher = aes256cbc
-	}
-	if len(settings.Auth) == 0 {
-		settings.Auth = sha256
-	}
 	lines = []string{
 		"client",
 		"dev tun",
@@ -106,10 +119,9 @@ func (s *privado) BuildConf(connection models.OpenVPNConnection,
 		fmt.Sprintf("auth-user-pass %s", constants.OpenVPNAuthConf),
 		fmt.Sprintf("proto %s", connection.Protocol),
 		fmt.Sprintf("remote %s %d", connection.IP, connection.Port),
-		"data-ciphers-fallback " + settings.Cipher,
-		"data-ciphers " + settings.Cipher,
-		fmt.Sprintf("auth %s", settings.Auth),
 	}
+	lines = append(lines, cipherProfiles[constants.Privado].lines(settings.Cipher, settings.Auth)...)
+	lines = append(lines, ipv6Lines(settings.Provider.ExtraConfigOptions.OpenVPNIPv6)...)
 	if !settings.Root {
 		lines = append(lines, "user "+username)
 	}
@@ -128,6 +140,6 @@ func (s *privado) BuildConf(connection models.OpenVPNConnection,
 
 func (s *privado) PortForward(ctx context.Context, client *http.Client,
 	openFile os.OpenFileFunc, pfLogger logging.Logger, gateway net.IP, fw firewall.Configurator,
-	syncState func(port uint16) (pfFilepath string)) {
+	syncState func(port uint16, expiration time.Time) (pfFilepath string)) {
 	panic("port forwarding is not supported for privado")
 }
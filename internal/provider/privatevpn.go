@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/gluetun/internal/constants"
@@ -19,49 +20,48 @@ import (
 type privatevpn struct {
 	servers    []models.PrivatevpnServer
 	randSource rand.Source
+	latencies  models.IPLatencies
 }
 
-func newPrivatevpn(servers []models.PrivatevpnServer, timeNow timeNowFunc) *privatevpn {
+func newPrivatevpn(servers []models.PrivatevpnServer, latencies models.IPLatencies,
+	timeNow timeNowFunc) *privatevpn {
 	return &privatevpn{
 		servers:    servers,
 		randSource: rand.NewSource(timeNow().UnixNano()),
+		latencies:  latencies,
 	}
 }
 
-func (p *privatevpn) filterServers(countries, cities, hostnames []string) (servers []models.PrivatevpnServer) {
-	for _, server := range p.servers {
-		switch {
-		case
-			filterByPossibilities(server.Country, countries),
-			filterByPossibilities(server.City, cities),
-			filterByPossibilities(server.Hostname, hostnames):
-		default:
+func (p *privatevpn) filterServers(selection configuration.ServerSelection) (
+	servers []models.PrivatevpnServer, err error) {
+	filters := selectionFilters(selection)
+	attributes := make([]serverAttributes, len(p.servers))
+	for i, server := range p.servers {
+		attributes[i] = serverAttributes{
+			country: server.Country, city: server.City,
+			hostname: server.Hostname, tcp: true, udp: true,
+		}
+		if !filterServer(filters, attributes[i]) {
 			servers = append(servers, server)
 		}
 	}
-	return servers
-}
-
-func (p *privatevpn) notFoundErr(selection configuration.ServerSelection) error {
-	message := "no server found for protocol " + selection.Protocol
-
-	if len(selection.Countries) > 0 {
-		message += " + countries " + commaJoin(selection.Countries)
-	}
-
-	if len(selection.Cities) > 0 {
-		message += " + cities " + commaJoin(selection.Cities)
-	}
-
-	if len(selection.Hostnames) > 0 {
-		message += " + hostnames " + commaJoin(selection.Hostnames)
+	if len(servers) == 0 {
+		return nil, noServerFoundError(filters, attributes)
 	}
-
-	return fmt.Errorf(message)
+	return servers, nil
 }
 
 func (p *privatevpn) GetOpenVPNConnection(selection configuration.ServerSelection) (
 	connection models.OpenVPNConnection, err error) {
+	connections, err := p.GetOpenVPNConnections(selection)
+	if err != nil {
+		return connection, err
+	}
+	return pickConnection(connections, selection, p.randSource, p.latencies), nil
+}
+
+func (p *privatevpn) GetOpenVPNConnections(selection configuration.ServerSelection) (
+	connections []models.OpenVPNConnection, err error) {
 	var port uint16
 	if selection.Protocol == constants.TCP {
 		port = 443
@@ -70,15 +70,14 @@ func (p *privatevpn) GetOpenVPNConnection(selection configuration.ServerSelectio
 	}
 
 	if selection.TargetIP != nil {
-		return models.OpenVPNConnection{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}, nil
+		return []models.OpenVPNConnection{{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}}, nil
 	}
 
-	servers := p.filterServers(selection.Countries, selection.Cities, selection.Hostnames)
-	if len(servers) == 0 {
-		return connection, p.notFoundErr(selection)
+	servers, err := p.filterServers(selection)
+	if err != nil {
+		return nil, err
 	}
 
-	var connections []models.OpenVPNConnection
 	for _, server := range servers {
 		for _, ip := range server.IPs {
 			connection := models.OpenVPNConnection{
@@ -90,18 +89,11 @@ func (p *privatevpn) GetOpenVPNConnection(selection configuration.ServerSelectio
 		}
 	}
 
-	return pickRandomConnection(connections, p.randSource), nil
+	return connections, nil
 }
 
 func (p *privatevpn) BuildConf(connection models.OpenVPNConnection,
 	username string, settings configuration.OpenVPN) (lines []string) {
-	if len(settings.Cipher) == 0 {
-		settings.Cipher = aes128gcm
-	}
-	if len(settings.Auth) == 0 {
-		settings.Auth = sha256
-	}
-
 	lines = []string{
 		"client",
 		"dev tun",
@@ -112,7 +104,6 @@ func (p *privatevpn) BuildConf(connection models.OpenVPNConnection,
 
 		// Privatevpn specific
 		"comp-lzo",
-		"tun-ipv6",
 
 		// Added constant values
 		"auth-nocache",
@@ -127,10 +118,9 @@ func (p *privatevpn) BuildConf(connection models.OpenVPNConnection,
 		fmt.Sprintf("auth-user-pass %s", constants.OpenVPNAuthConf),
 		fmt.Sprintf("proto %s", connection.Protocol),
 		fmt.Sprintf("remote %s %d", connection.IP, connection.Port),
-		"data-ciphers-fallback " + settings.Cipher,
-		"data-ciphers " + settings.Cipher,
-		fmt.Sprintf("auth %s", settings.Auth),
 	}
+	lines = append(lines, cipherProfiles[constants.Privatevpn].lines(settings.Cipher, settings.Auth)...)
+	lines = append(lines, ipv6Lines(settings.Provider.ExtraConfigOptions.OpenVPNIPv6)...)
 	if connection.Protocol == constants.UDP {
 		lines = append(lines, "key-direction 1")
 	}
@@ -161,6 +151,6 @@ func (p *privatevpn) BuildConf(connection models.OpenVPNConnection,
 
 func (p *privatevpn) PortForward(ctx context.Context, client *http.Client,
 	openFile os.OpenFileFunc, pfLogger logging.Logger, gateway net.IP, fw firewall.Configurator,
-	syncState func(port uint16) (pfFilepath string)) {
+	syncState func(port uint16, expiration time.Time) (pfFilepath string)) {
 	panic("port forwarding is not supported for privatevpn")
 }
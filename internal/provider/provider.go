@@ -5,6 +5,7 @@ import (
 	"context"
 	"net"
 	"net/http"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/gluetun/internal/constants"
@@ -17,41 +18,67 @@ import (
 // Provider contains methods to read and modify the openvpn configuration to connect as a client.
 type Provider interface {
 	GetOpenVPNConnection(selection configuration.ServerSelection) (connection models.OpenVPNConnection, err error)
+	GetOpenVPNConnections(selection configuration.ServerSelection) (connections []models.OpenVPNConnection, err error)
 	BuildConf(connection models.OpenVPNConnection, username string, settings configuration.OpenVPN) (lines []string)
 	PortForward(ctx context.Context, client *http.Client,
 		openFile os.OpenFileFunc, pfLogger logging.Logger, gateway net.IP, fw firewall.Configurator,
-		syncState func(port uint16) (pfFilepath string))
+		syncState func(port uint16, expiration time.Time) (pfFilepath string))
+}
+
+// constructor creates a Provider using the servers and latencies given.
+type constructor func(allServers models.AllServers, timeNow timeNowFunc) Provider
+
+// constructors returns the Provider constructor of each VPN provider.
+// A new VPN provider must be registered here as well as in
+// constants.ProviderCapabilities.
+func constructors() map[string]constructor {
+	return map[string]constructor{
+		constants.Cyberghost: func(allServers models.AllServers, timeNow timeNowFunc) Provider {
+			return newCyberghost(allServers.Cyberghost.Servers, allServers.Latencies, timeNow)
+		},
+		constants.Fastestvpn: func(allServers models.AllServers, timeNow timeNowFunc) Provider {
+			return newFastestvpn(allServers.Fastestvpn.Servers, allServers.Latencies, timeNow)
+		},
+		constants.HideMyAss: func(allServers models.AllServers, timeNow timeNowFunc) Provider {
+			return newHideMyAss(allServers.HideMyAss.Servers, allServers.Latencies, timeNow)
+		},
+		constants.Mullvad: func(allServers models.AllServers, timeNow timeNowFunc) Provider {
+			return newMullvad(allServers.Mullvad.Servers, allServers.Latencies, timeNow)
+		},
+		constants.Nordvpn: func(allServers models.AllServers, timeNow timeNowFunc) Provider {
+			return newNordvpn(allServers.Nordvpn.Servers, allServers.Latencies, timeNow)
+		},
+		constants.Privado: func(allServers models.AllServers, timeNow timeNowFunc) Provider {
+			return newPrivado(allServers.Privado.Servers, allServers.Latencies, timeNow)
+		},
+		constants.PrivateInternetAccess: func(allServers models.AllServers, timeNow timeNowFunc) Provider {
+			return newPrivateInternetAccess(allServers.Pia.Servers, allServers.Latencies, timeNow)
+		},
+		constants.Privatevpn: func(allServers models.AllServers, timeNow timeNowFunc) Provider {
+			return newPrivatevpn(allServers.Privatevpn.Servers, allServers.Latencies, timeNow)
+		},
+		constants.Purevpn: func(allServers models.AllServers, timeNow timeNowFunc) Provider {
+			return newPurevpn(allServers.Purevpn.Servers, allServers.Latencies, timeNow)
+		},
+		constants.Surfshark: func(allServers models.AllServers, timeNow timeNowFunc) Provider {
+			return newSurfshark(allServers.Surfshark.Servers, allServers.Latencies, timeNow)
+		},
+		constants.Torguard: func(allServers models.AllServers, timeNow timeNowFunc) Provider {
+			return newTorguard(allServers.Torguard.Servers, allServers.Latencies, timeNow)
+		},
+		constants.Vyprvpn: func(allServers models.AllServers, timeNow timeNowFunc) Provider {
+			return newVyprvpn(allServers.Vyprvpn.Servers, allServers.Latencies, timeNow)
+		},
+		constants.Windscribe: func(allServers models.AllServers, timeNow timeNowFunc) Provider {
+			return newWindscribe(allServers.Windscribe.Servers, allServers.Latencies, timeNow)
+		},
+	}
 }
 
 func New(provider string, allServers models.AllServers, timeNow timeNowFunc) Provider {
-	switch provider {
-	case constants.Cyberghost:
-		return newCyberghost(allServers.Cyberghost.Servers, timeNow)
-	case constants.Fastestvpn:
-		return newFastestvpn(allServers.Fastestvpn.Servers, timeNow)
-	case constants.HideMyAss:
-		return newHideMyAss(allServers.HideMyAss.Servers, timeNow)
-	case constants.Mullvad:
-		return newMullvad(allServers.Mullvad.Servers, timeNow)
-	case constants.Nordvpn:
-		return newNordvpn(allServers.Nordvpn.Servers, timeNow)
-	case constants.Privado:
-		return newPrivado(allServers.Privado.Servers, timeNow)
-	case constants.PrivateInternetAccess:
-		return newPrivateInternetAccess(allServers.Pia.Servers, timeNow)
-	case constants.Privatevpn:
-		return newPrivatevpn(allServers.Privatevpn.Servers, timeNow)
-	case constants.Purevpn:
-		return newPurevpn(allServers.Purevpn.Servers, timeNow)
-	case constants.Surfshark:
-		return newSurfshark(allServers.Surfshark.Servers, timeNow)
-	case constants.Torguard:
-		return newTorguard(allServers.Torguard.Servers, timeNow)
-	case constants.Vyprvpn:
-		return newVyprvpn(allServers.Vyprvpn.Servers, timeNow)
-	case constants.Windscribe:
-		return newWindscribe(allServers.Windscribe.Servers, timeNow)
-	default:
+	newProvider, ok := constructors()[provider]
+	if !ok {
 		return nil // should never occur
 	}
+	return newProvider(allServers, timeNow)
 }
@@ -0,0 +1,20 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_constructors(t *testing.T) {
+	t.Parallel()
+
+	constructors := constructors()
+
+	for _, provider := range constants.VPNProviders() {
+		_, ok := constructors[provider]
+		assert.True(t, ok, "no constructor registered for VPN provider %s", provider)
+	}
+	assert.Len(t, constructors, len(constants.VPNProviders()))
+}
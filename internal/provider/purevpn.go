@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/gluetun/internal/constants"
@@ -19,31 +20,48 @@ import (
 type purevpn struct {
 	servers    []models.PurevpnServer
 	randSource rand.Source
+	latencies  models.IPLatencies
 }
 
-func newPurevpn(servers []models.PurevpnServer, timeNow timeNowFunc) *purevpn {
+func newPurevpn(servers []models.PurevpnServer, latencies models.IPLatencies,
+	timeNow timeNowFunc) *purevpn {
 	return &purevpn{
 		servers:    servers,
 		randSource: rand.NewSource(timeNow().UnixNano()),
+		latencies:  latencies,
 	}
 }
 
-func (p *purevpn) filterServers(regions, countries, cities []string) (servers []models.PurevpnServer) {
-	for _, server := range p.servers {
-		switch {
-		case
-			filterByPossibilities(server.Region, regions),
-			filterByPossibilities(server.Country, countries),
-			filterByPossibilities(server.City, cities):
-		default:
+func (p *purevpn) filterServers(selection configuration.ServerSelection) (
+	servers []models.PurevpnServer, err error) {
+	filters := selectionFilters(selection)
+	attributes := make([]serverAttributes, len(p.servers))
+	for i, server := range p.servers {
+		attributes[i] = serverAttributes{
+			region: server.Region, country: server.Country,
+			city: server.City, tcp: true, udp: true,
+		}
+		if !filterServer(filters, attributes[i]) {
 			servers = append(servers, server)
 		}
 	}
-	return servers
+	if len(servers) == 0 {
+		return nil, noServerFoundError(filters, attributes)
+	}
+	return servers, nil
 }
 
 func (p *purevpn) GetOpenVPNConnection(selection configuration.ServerSelection) (
 	connection models.OpenVPNConnection, err error) {
+	connections, err := p.GetOpenVPNConnections(selection)
+	if err != nil {
+		return connection, err
+	}
+	return pickConnection(connections, selection, p.randSource, p.latencies), nil
+}
+
+func (p *purevpn) GetOpenVPNConnections(selection configuration.ServerSelection) (
+	connections []models.OpenVPNConnection, err error) {
 	var port uint16
 	switch {
 	case selection.Protocol == constants.UDP:
@@ -51,34 +69,29 @@ func (p *purevpn) GetOpenVPNConnection(selection configuration.ServerSelection)
 	case selection.Protocol == constants.TCP:
 		port = 80
 	default:
-		return connection, fmt.Errorf("protocol %q is unknown", selection.Protocol)
+		return nil, fmt.Errorf("protocol %q is unknown", selection.Protocol)
 	}
 
 	if selection.TargetIP != nil {
-		return models.OpenVPNConnection{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}, nil
+		return []models.OpenVPNConnection{{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}}, nil
 	}
 
-	servers := p.filterServers(selection.Regions, selection.Countries, selection.Cities)
-	if len(servers) == 0 {
-		return connection, fmt.Errorf("no server found for regions %s, countries %s and cities %s",
-			commaJoin(selection.Regions), commaJoin(selection.Countries), commaJoin(selection.Cities))
+	servers, err := p.filterServers(selection)
+	if err != nil {
+		return nil, err
 	}
 
-	var connections []models.OpenVPNConnection
 	for _, server := range servers {
 		for _, IP := range server.IPs {
 			connections = append(connections, models.OpenVPNConnection{IP: IP, Port: port, Protocol: selection.Protocol})
 		}
 	}
 
-	return pickRandomConnection(connections, p.randSource), nil
+	return connections, nil
 }
 
 func (p *purevpn) BuildConf(connection models.OpenVPNConnection,
 	username string, settings configuration.OpenVPN) (lines []string) {
-	if len(settings.Cipher) == 0 {
-		settings.Cipher = aes256cbc
-	}
 	lines = []string{
 		"client",
 		"dev tun",
@@ -93,7 +106,6 @@ func (p *purevpn) BuildConf(connection models.OpenVPNConnection,
 		// Purevpn specific
 		"key-direction 1",
 		"remote-cert-tls server",
-		"cipher AES-256-CBC",
 		"route-method exe",
 		"route-delay 0",
 		"script-security 2",
@@ -110,9 +122,9 @@ func (p *purevpn) BuildConf(connection models.OpenVPNConnection,
 		fmt.Sprintf("auth-user-pass %s", constants.OpenVPNAuthConf),
 		fmt.Sprintf("proto %s", connection.Protocol),
 		fmt.Sprintf("remote %s %d", connection.IP.String(), connection.Port),
-		"data-ciphers-fallback " + settings.Cipher,
-		"data-ciphers " + settings.Cipher,
 	}
+	lines = append(lines, cipherProfiles[constants.Purevpn].lines(settings.Cipher, settings.Auth)...)
+	lines = append(lines, ipv6Lines(settings.Provider.ExtraConfigOptions.OpenVPNIPv6)...)
 	if !settings.Root {
 		lines = append(lines, "user "+username)
 	}
@@ -149,9 +161,6 @@ func (p *purevpn) BuildConf(connection models.OpenVPNConnection,
 		"</tls-auth>",
 		"",
 	}...)
-	if len(settings.Auth) > 0 {
-		lines = append(lines, "auth "+settings.Auth)
-	}
 	if connection.Protocol == constants.UDP {
 		lines = append(lines, "explicit-exit-notify")
 	}
@@ -160,6 +169,6 @@ func (p *purevpn) BuildConf(connection models.OpenVPNConnection,
 
 func (p *purevpn) PortForward(ctx context.Context, client *http.Client,
 	openFile os.OpenFileFunc, pfLogger logging.Logger, gateway net.IP, fw firewall.Configurator,
-	syncState func(port uint16) (pfFilepath string)) {
+	syncState func(port uint16, expiration time.Time) (pfFilepath string)) {
 	panic("port forwarding is not supported for purevpn")
 }
@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/gluetun/internal/constants"
@@ -19,29 +20,47 @@ import (
 type surfshark struct {
 	servers    []models.SurfsharkServer
 	randSource rand.Source
+	latencies  models.IPLatencies
 }
 
-func newSurfshark(servers []models.SurfsharkServer, timeNow timeNowFunc) *surfshark {
+func newSurfshark(servers []models.SurfsharkServer, latencies models.IPLatencies,
+	timeNow timeNowFunc) *surfshark {
 	return &surfshark{
 		servers:    servers,
 		randSource: rand.NewSource(timeNow().UnixNano()),
+		latencies:  latencies,
 	}
 }
 
-func (s *surfshark) filterServers(regions []string) (servers []models.SurfsharkServer) {
-	for _, server := range s.servers {
-		switch {
-		case
-			filterByPossibilities(server.Region, regions):
-		default:
+func (s *surfshark) filterServers(selection configuration.ServerSelection) (
+	servers []models.SurfsharkServer, err error) {
+	filters := selectionFilters(selection)
+	attributes := make([]serverAttributes, len(s.servers))
+	for i, server := range s.servers {
+		attributes[i] = serverAttributes{
+			region: server.Region, tcp: true, udp: true, multiHop: server.MultiHop,
+		}
+		if !filterServer(filters, attributes[i]) {
 			servers = append(servers, server)
 		}
 	}
-	return servers
+	if len(servers) == 0 {
+		return nil, noServerFoundError(filters, attributes)
+	}
+	return servers, nil
 }
 
 func (s *surfshark) GetOpenVPNConnection(selection configuration.ServerSelection) (
 	connection models.OpenVPNConnection, err error) {
+	connections, err := s.GetOpenVPNConnections(selection)
+	if err != nil {
+		return connection, err
+	}
+	return pickConnection(connections, selection, s.randSource, s.latencies), nil
+}
+
+func (s *surfshark) GetOpenVPNConnections(selection configuration.ServerSelection) (
+	connections []models.OpenVPNConnection, err error) {
 	var port uint16
 	switch {
 	case selection.Protocol == constants.TCP:
@@ -49,41 +68,29 @@ func (s *surfshark) GetOpenVPNConnection(selection configuration.ServerSelection
 	case selection.Protocol == constants.UDP:
 		port = 1194
 	default:
-		return connection, fmt.Errorf("protocol %q is unknown", selection.Protocol)
+		return nil, fmt.Errorf("protocol %q is unknown", selection.Protocol)
 	}
 
 	if selection.TargetIP != nil {
-		return models.OpenVPNConnection{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}, nil
+		return []models.OpenVPNConnection{{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}}, nil
 	}
 
-	servers := s.filterServers(selection.Regions)
-	if len(servers) == 0 {
-		return connection, fmt.Errorf("no server found for region %s", commaJoin(selection.Regions))
+	servers, err := s.filterServers(selection)
+	if err != nil {
+		return nil, err
 	}
 
-	var connections []models.OpenVPNConnection
 	for _, server := range servers {
 		for _, IP := range server.IPs {
 			connections = append(connections, models.OpenVPNConnection{IP: IP, Port: port, Protocol: selection.Protocol})
 		}
 	}
 
-	if selection.TargetIP != nil {
-		return connection, fmt.Errorf("target IP %s not found in IP addresses", selection.TargetIP)
-	}
-
-	return pickRandomConnection(connections, s.randSource), nil
+	return connections, nil
 }
 
 func (s *surfshark) BuildConf(connection models.OpenVPNConnection,
 	username string, settings configuration.OpenVPN) (lines []string) {
-	if len(settings.Cipher) == 0 {
-		settings.Cipher = aes256gcm
-	}
-	if len(settings.Auth) == 0 {
-		settings.Auth = "SHA512"
-	}
-
 	const defaultMSSFix = 1450
 	if settings.MSSFix == 0 {
 		settings.MSSFix = defaultMSSFix
@@ -122,10 +129,9 @@ func (s *surfshark) BuildConf(connection models.OpenVPNConnection,
 		fmt.Sprintf("auth-user-pass %s", constants.OpenVPNAuthConf),
 		fmt.Sprintf("proto %s", connection.Protocol),
 		fmt.Sprintf("remote %s %d", connection.IP, connection.Port),
-		"data-ciphers-fallback " + settings.Cipher,
-		"data-ciphers " + settings.Cipher,
-		fmt.Sprintf("auth %s", settings.Auth),
 	}
+	lines = append(lines, cipherProfiles[constants.Surfshark].lines(settings.Cipher, settings.Auth)...)
+	lines = append(lines, ipv6Lines(settings.Provider.ExtraConfigOptions.OpenVPNIPv6)...)
 	if !settings.Root {
 		lines = append(lines, "user "+username)
 	}
@@ -149,6 +155,6 @@ func (s *surfshark) BuildConf(connection models.OpenVPNConnection,
 
 func (s *surfshark) PortForward(ctx context.Context, client *http.Client,
 	openFile os.OpenFileFunc, pfLogger logging.Logger, gateway net.IP, fw firewall.Configurator,
-	syncState func(port uint16) (pfFilepath string)) {
+	syncState func(port uint16, expiration time.Time) (pfFilepath string)) {
 	panic("port forwarding is not supported for surfshark")
 }
@@ -0,0 +1,191 @@
+package provider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	cryptosha256 "crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/qdm12/golibs/os"
+)
+
+// tokenCache caches API tokens in memory and on disk, to avoid logging in
+// to the VPN provider API again on every loop iteration or restart.
+// Each provider instance has its own cache, and caches using the same
+// file share their tokens through that file.
+type tokenCache struct {
+	filepath string
+	timeNow  func() time.Time
+	randRead func(b []byte) (n int, err error)
+	data     tokensData
+	loaded   bool
+	mu       sync.Mutex
+}
+
+// tokensData is the data of the token cache file, only readable by its owner.
+type tokensData struct {
+	// Salt is the random salt hashing the credentials into the tokens keys,
+	// so the credentials cannot be recovered from the file with precomputed
+	// hashes.
+	Salt   []byte                 `json:"salt"`
+	Tokens map[string]cachedToken `json:"tokens"`
+}
+
+type cachedToken struct {
+	Token  string    `json:"token"`
+	Expiry time.Time `json:"expiry"`
+}
+
+func newTokenCache(filepath string, timeNow func() time.Time) *tokenCache {
+	return &tokenCache{
+		filepath: filepath,
+		timeNow:  timeNow,
+		randRead: rand.Read,
+		data: tokensData{
+			Tokens: make(map[string]cachedToken),
+		},
+	}
+}
+
+const tokenSaltLength = 32
+
+// get returns the cached token for the provider and credentials if it is
+// not expired, and fetches, caches and returns a new token otherwise.
+func (c *tokenCache) get(ctx context.Context, openFile os.OpenFileFunc,
+	provider string, credentials []string, validity time.Duration,
+	fetch func(ctx context.Context) (token string, err error)) (
+	token string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.ensureLoaded(openFile); err != nil {
+		return "", err
+	}
+
+	key := c.key(provider, credentials)
+	now := c.timeNow()
+	if cached, ok := c.data.Tokens[key]; ok && now.Before(cached.Expiry) {
+		return cached.Token, nil
+	}
+
+	token, err = fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	c.data.Tokens[key] = cachedToken{Token: token, Expiry: now.Add(validity)}
+	// the cache is only an optimization, so ignore errors
+	_ = c.save(openFile)
+	return token, nil
+}
+
+// invalidate removes the token for the provider and credentials, for example
+// if the token was rejected by the API, so a new token is fetched on the next get.
+func (c *tokenCache) invalidate(openFile os.OpenFileFunc, provider string, credentials []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.loaded {
+		return
+	}
+	key := c.key(provider, credentials)
+	if _, ok := c.data.Tokens[key]; !ok {
+		return
+	}
+	delete(c.data.Tokens, key)
+	_ = c.save(openFile)
+}
+
+// ensureLoaded loads the cache file once, and creates the salt if
+// the file does not exist or has no salt.
+func (c *tokenCache) ensureLoaded(openFile os.OpenFileFunc) (err error) {
+	if c.loaded {
+		return nil
+	}
+
+	// the cache is only an optimization, so ignore errors
+	_ = c.load(openFile)
+
+	if len(c.data.Salt) == 0 {
+		// tokens without salt cannot be looked up
+		c.data.Tokens = make(map[string]cachedToken)
+		c.data.Salt = make([]byte, tokenSaltLength)
+		if _, err := c.randRead(c.data.Salt); err != nil {
+			c.data.Salt = nil
+			return err
+		}
+	}
+
+	c.loaded = true
+	return nil
+}
+
+// key returns the cache key for the provider and credentials given,
+// hashing the credentials with the salt so they are not written to disk.
+func (c *tokenCache) key(provider string, credentials []string) (key string) {
+	hasher := hmac.New(cryptosha256.New, c.data.Salt)
+	for _, credential := range credentials {
+		_, _ = hasher.Write([]byte(credential))
+		_, _ = hasher.Write([]byte{0})
+	}
+	return provider + ":" + hex.EncodeToString(hasher.Sum(nil))
+}
+
+func (c *tokenCache) load(openFile os.OpenFileFunc) (err error) {
+	file, err := openFile(c.filepath, os.O_RDONLY, 0)
+	if err != nil {
+		return err
+	}
+
+	var data tokensData
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&data); err != nil && !errors.Is(err, io.EOF) {
+		_ = file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	c.data.Salt = data.Salt
+	c.data.Tokens = data.Tokens
+	if c.data.Tokens == nil {
+		c.data.Tokens = make(map[string]cachedToken)
+	}
+	return nil
+}
+
+func (c *tokenCache) save(openFile os.OpenFileFunc) (err error) {
+	now := c.timeNow()
+	for key, cached := range c.data.Tokens {
+		if !now.Before(cached.Expiry) {
+			delete(c.data.Tokens, key)
+		}
+	}
+
+	const perm = 0600
+	file, err := openFile(c.filepath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+
+	// restrict the permissions of a file created with other permissions
+	if err := file.Chmod(perm); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	if err := encoder.Encode(c.data); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
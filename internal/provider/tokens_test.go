@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	nativeos "os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/golibs/os"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_tokenCache(t *testing.T) {
+	t.Parallel()
+
+	errNoFile := errors.New("no file")
+	openFile := func(name string, flag int, perm os.FileMode) (os.File, error) {
+		return nil, errNoFile
+	}
+
+	now := time.Unix(0, 0)
+	cache := newTokenCache("/tokens.json", func() time.Time { return now })
+	const validity = time.Hour
+	credentials := []string{"user", "password"}
+
+	fetches := 0
+	fetch := func(ctx context.Context) (token string, err error) {
+		fetches++
+		return "token", nil
+	}
+
+	token, err := cache.get(context.Background(), openFile, "provider", credentials, validity, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, "token", token)
+	assert.Equal(t, 1, fetches)
+
+	// cached
+	_, err = cache.get(context.Background(), openFile, "provider", credentials, validity, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fetches)
+
+	// other credentials
+	_, err = cache.get(context.Background(), openFile, "provider",
+		[]string{"user", "other password"}, validity, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 2, fetches)
+
+	// expired
+	now = now.Add(validity)
+	_, err = cache.get(context.Background(), openFile, "provider", credentials, validity, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 3, fetches)
+
+	cache.invalidate(openFile, "provider", credentials)
+	_, err = cache.get(context.Background(), openFile, "provider", credentials, validity, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 4, fetches)
+}
+
+func Test_tokenCache_saltError(t *testing.T) {
+	t.Parallel()
+
+	errNoFile := errors.New("no file")
+	openFile := func(name string, flag int, perm os.FileMode) (os.File, error) {
+		return nil, errNoFile
+	}
+
+	errRandom := errors.New("no randomness")
+	cache := newTokenCache("/tokens.json", time.Now)
+	cache.randRead = func(b []byte) (n int, err error) { return 0, errRandom }
+
+	fetch := func(ctx context.Context) (token string, err error) {
+		t.Error("token should not be fetched")
+		return "", nil
+	}
+
+	_, err := cache.get(context.Background(), openFile, "provider", []string{"user"}, time.Hour, fetch)
+	assert.ErrorIs(t, err, errRandom)
+}
+
+func Test_tokenCache_file(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		fileContent string
+	}{
+		"no file": {},
+		"empty file": {
+			fileContent: " ",
+		},
+		"malformed file": {
+			fileContent: "{",
+		},
+		"unsalted file": {
+			fileContent: `{"provider:abc":{"token":"old","expiry":"2100-01-01T00:00:00Z"}}`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			path := filepath.Join(dir, "tokens.json")
+			if testCase.fileContent != "" {
+				err := ioutil.WriteFile(path, []byte(testCase.fileContent), 0644)
+				require.NoError(t, err)
+			}
+			openFile := func(name string, flag int, perm os.FileMode) (os.File, error) {
+				return nativeos.OpenFile(name, flag, nativeos.FileMode(perm))
+			}
+
+			now := time.Unix(1600000000, 0).UTC()
+			timeNow := func() time.Time { return now }
+			credentials := []string{"user", "secret password"}
+			fetches := 0
+			fetch := func(ctx context.Context) (token string, err error) {
+				fetches++
+				return "token", nil
+			}
+
+			cache := newTokenCache(path, timeNow)
+			token, err := cache.get(context.Background(), openFile, "provider",
+				credentials, time.Hour, fetch)
+			require.NoError(t, err)
+			assert.Equal(t, "token", token)
+			assert.Equal(t, 1, fetches)
+
+			info, err := nativeos.Stat(path)
+			require.NoError(t, err)
+			assert.Equal(t, nativeos.FileMode(0600), info.Mode().Perm())
+
+			content, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			assert.False(t, strings.Contains(string(content), "secret password"))
+			var data tokensData
+			err = json.Unmarshal(content, &data)
+			require.NoError(t, err)
+			assert.Len(t, data.Salt, tokenSaltLength)
+			require.Len(t, data.Tokens, 1)
+			for _, cached := range data.Tokens {
+				assert.Equal(t, cachedToken{Token: "token", Expiry: now.Add(time.Hour)}, cached)
+			}
+
+			// Another cache of the same file uses the persisted salt and token
+			otherCache := newTokenCache(path, timeNow)
+			token, err = otherCache.get(context.Background(), openFile, "provider",
+				credentials, time.Hour, fetch)
+			require.NoError(t, err)
+			assert.Equal(t, "token", token)
+			assert.Equal(t, 1, fetches)
+
+			// Invalidating removes the token from the file
+			otherCache.invalidate(openFile, "provider", credentials)
+			content, err = ioutil.ReadFile(path)
+			require.NoError(t, err)
+			data = tokensData{}
+			err = json.Unmarshal(content, &data)
+			require.NoError(t, err)
+			assert.Len(t, data.Salt, tokenSaltLength)
+			assert.Empty(t, data.Tokens)
+		})
+	}
+}
+
+func Test_tokenCache_key(t *testing.T) {
+	t.Parallel()
+
+	cache := newTokenCache("", time.Now)
+	cache.data.Salt = []byte{1}
+	otherCache := newTokenCache("", time.Now)
+	otherCache.data.Salt = []byte{2}
+
+	key := cache.key("provider", []string{"user", "password"})
+
+	assert.True(t, strings.HasPrefix(key, "provider:"))
+	assert.Equal(t, key, cache.key("provider", []string{"user", "password"}))
+	assert.NotEqual(t, key, cache.key("provider", []string{"userpassword"}))
+	assert.NotEqual(t, key, cache.key("other", []string{"user", "password"}))
+	assert.NotEqual(t, key, otherCache.key("provider", []string{"user", "password"}))
+}
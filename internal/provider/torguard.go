@@ -2,11 +2,11 @@ package provider
 
 import (
 	"context"
-	"fmt"
 	"math/rand"
 	"net"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/gluetun/internal/constants"
@@ -19,63 +19,63 @@ import (
 type torguard struct {
 	servers    []models.TorguardServer
 	randSource rand.Source
+	latencies  models.IPLatencies
 }
 
-func newTorguard(servers []models.TorguardServer, timeNow timeNowFunc) *torguard {
+func newTorguard(servers []models.TorguardServer, latencies models.IPLatencies,
+	timeNow timeNowFunc) *torguard {
 	return &torguard{
 		servers:    servers,
 		randSource: rand.NewSource(timeNow().UnixNano()),
+		latencies:  latencies,
 	}
 }
 
-func (t *torguard) filterServers(countries, cities, hostnames []string) (servers []models.TorguardServer) {
-	for _, server := range t.servers {
-		switch {
-		case filterByPossibilities(server.Country, countries):
-		case filterByPossibilities(server.City, cities):
-		case filterByPossibilities(server.Hostname, hostnames):
-		default:
+func (t *torguard) filterServers(selection configuration.ServerSelection) (
+	servers []models.TorguardServer, err error) {
+	filters := selectionFilters(selection)
+	attributes := make([]serverAttributes, len(t.servers))
+	for i, server := range t.servers {
+		attributes[i] = serverAttributes{
+			country: server.Country, city: server.City,
+			hostname: server.Hostname, tcp: true, udp: true,
+		}
+		if !filterServer(filters, attributes[i]) {
 			servers = append(servers, server)
 		}
 	}
-	return servers
-}
-
-func (t *torguard) notFoundErr(selection configuration.ServerSelection) error {
-	message := "no server found for protocol " + selection.Protocol
-
-	if len(selection.Countries) > 0 {
-		message += " + countries " + commaJoin(selection.Countries)
-	}
-
-	if len(selection.Cities) > 0 {
-		message += " + cities " + commaJoin(selection.Cities)
-	}
-
-	if len(selection.Hostnames) > 0 {
-		message += " + hostnames " + commaJoin(selection.Hostnames)
+	if len(servers) == 0 {
+		return nil, noServerFoundError(filters, attributes)
 	}
-
-	return fmt.Errorf(message)
+	return servers, nil
 }
 
 func (t *torguard) GetOpenVPNConnection(selection configuration.ServerSelection) (
 	connection models.OpenVPNConnection, err error) {
+	connections, err := t.GetOpenVPNConnections(selection)
+	if err != nil {
+		return connection, err
+	}
+	return pickConnection(connections, selection, t.randSource, t.latencies), nil
+}
+
+func (t *torguard) GetOpenVPNConnections(selection configuration.ServerSelection) (
+	connections []models.OpenVPNConnection, err error) {
 	var port uint16 = 1912
 	if selection.CustomPort > 0 {
 		port = selection.CustomPort
 	}
 
 	if selection.TargetIP != nil {
-		return models.OpenVPNConnection{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}, nil
+		return []models.OpenVPNConnection{{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}}, nil
 	}
 
-	servers := t.filterServers(selection.Countries, selection.Cities, selection.Hostnames)
-	if len(servers) == 0 {
-		return connection, t.notFoundErr(selection)
+	servers, err := t.filterServers(selection)
+	if err != nil {
+		return nil, err
 	}
 
-	connections := make([]models.OpenVPNConnection, len(servers))
+	connections = make([]models.OpenVPNConnection, len(servers))
 	for i := range servers {
 		connections[i] = models.OpenVPNConnection{
 			IP:       servers[i].IP,
@@ -84,18 +84,11 @@ func (t *torguard) GetOpenVPNConnection(selection configuration.ServerSelection)
 		}
 	}
 
-	return pickRandomConnection(connections, t.randSource), nil
+	return connections, nil
 }
 
 func (t *torguard) BuildConf(connection models.OpenVPNConnection,
 	username string, settings configuration.OpenVPN) (lines []string) {
-	if len(settings.Cipher) == 0 {
-		settings.Cipher = aes256gcm
-	}
-	if len(settings.Auth) == 0 {
-		settings.Auth = sha256
-	}
-
 	const defaultMSSFix = 1450
 	if settings.MSSFix == 0 {
 		settings.MSSFix = defaultMSSFix
@@ -117,7 +110,6 @@ func (t *torguard) BuildConf(connection models.OpenVPNConnection,
 		"fast-io",
 		"key-direction 1",
 		"script-security 2",
-		"ncp-disable",
 		"compress",
 		"keepalive 5 30",
 		"sndbuf 393216",
@@ -138,10 +130,9 @@ func (t *torguard) BuildConf(connection models.OpenVPNConnection,
 		"auth-user-pass " + constants.OpenVPNAuthConf,
 		"proto " + connection.Protocol,
 		"remote " + connection.IP.String() + " " + strconv.Itoa(int(connection.Port)),
-		"data-ciphers-fallback " + settings.Cipher,
-		"data-ciphers " + settings.Cipher,
-		"auth " + settings.Auth,
 	}
+	lines = append(lines, cipherProfiles[constants.Torguard].lines(settings.Cipher, settings.Auth)...)
+	lines = append(lines, ipv6Lines(settings.Provider.ExtraConfigOptions.OpenVPNIPv6)...)
 
 	if !settings.Root {
 		lines = append(lines, "user "+username)
@@ -169,6 +160,6 @@ func (t *torguard) BuildConf(connection models.OpenVPNConnection,
 
 func (t *torguard) PortForward(ctx context.Context, client *http.Client,
 	openFile os.OpenFileFunc, pfLogger logging.Logger, gateway net.IP, fw firewall.Configurator,
-	syncState func(port uint16) (pfFilepath string)) {
+	syncState func(port uint16, expiration time.Time) (pfFilepath string)) {
 	panic("port forwarding is not supported for torguard")
 }
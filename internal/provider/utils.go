@@ -3,17 +3,22 @@ package provider
 import (
 	"context"
 	"math/rand"
+	"net"
 	"strings"
 	"time"
 
+	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/golibs/logging"
 )
 
 type timeNowFunc func() time.Time
 
-func tryUntilSuccessful(ctx context.Context, logger logging.Logger, fn func() error) {
-	const retryPeriod = 10 * time.Second
+// defaultRetryPeriod is the period to retry port forwarding operations.
+const defaultRetryPeriod = 10 * time.Second
+
+func tryUntilSuccessful(ctx context.Context, logger logging.Logger,
+	retryPeriod time.Duration, fn func() error) {
 	for {
 		err := fn()
 		if err == nil {
@@ -37,6 +42,81 @@ func pickRandomConnection(connections []models.OpenVPNConnection, source rand.So
 	return connections[rand.New(source).Intn(len(connections))] //nolint:gosec
 }
 
+// nearBestLatencyFactor is the factor of the lowest latency below which
+// connections are considered as fast as the fastest connection, so users
+// with the same server selection do not all connect to the same server.
+const nearBestLatencyFactor = 1.25
+
+// pickConnection picks a random connection, among the connections with a
+// latency close to the lowest latency known if the latency selection is
+// enabled. Connections to the excluded IP addresses of the selection are
+// avoided, unless there is no other connection available, and the connection
+// to the preferred IP address of the selection is picked if available.
+func pickConnection(connections []models.OpenVPNConnection, selection configuration.ServerSelection,
+	source rand.Source, latencies models.IPLatencies) models.OpenVPNConnection {
+	connections = excludeConnections(connections, selection.ExcludedIPs)
+	if selection.PreferredIP != nil {
+		for _, connection := range connections {
+			if connection.IP.Equal(selection.PreferredIP) {
+				return connection
+			}
+		}
+	}
+	if selection.Latency.Enabled {
+		if fastest := fastestConnections(connections, latencies); len(fastest) > 0 {
+			connections = fastest
+		}
+	}
+	return pickRandomConnection(connections, source)
+}
+
+// fastestConnections returns the connections with a known latency within
+// nearBestLatencyFactor of the lowest known latency, and returns no
+// connection if no latency is known.
+func fastestConnections(connections []models.OpenVPNConnection,
+	latencies models.IPLatencies) (fastest []models.OpenVPNConnection) {
+	lowest := time.Duration(-1)
+	for _, connection := range connections {
+		latency, ok := latencies[connection.IP.String()]
+		if ok && (lowest == -1 || latency < lowest) {
+			lowest = latency
+		}
+	}
+	if lowest == -1 {
+		return nil
+	}
+
+	maxLatency := time.Duration(float64(lowest) * nearBestLatencyFactor)
+	for _, connection := range connections {
+		latency, ok := latencies[connection.IP.String()]
+		if ok && latency <= maxLatency {
+			fastest = append(fastest, connection)
+		}
+	}
+	return fastest
+}
+
+func excludeConnections(connections []models.OpenVPNConnection,
+	excludedIPs []net.IP) (kept []models.OpenVPNConnection) {
+	kept = make([]models.OpenVPNConnection, 0, len(connections))
+	for _, connection := range connections {
+		excluded := false
+		for _, excludedIP := range excludedIPs {
+			if connection.IP.Equal(excludedIP) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			kept = append(kept, connection)
+		}
+	}
+	if len(kept) == 0 {
+		return connections
+	}
+	return kept
+}
+
 func filterByPossibilities(value string, possibilities []string) (filtered bool) {
 	if len(possibilities) == 0 {
 		return false
@@ -49,6 +129,18 @@ func filterByPossibilities(value string, possibilities []string) (filtered bool)
 	return true
 }
 
+// filterMultiHop returns true if the server should be filtered out
+// given the multi-hop selection.
+func filterMultiHop(multiHop *models.MultiHop, selection configuration.MultiHopSelection) (filtered bool) {
+	var entryCountry, exitCountry string
+	if multiHop != nil {
+		entryCountry, exitCountry = multiHop.EntryCountry, multiHop.ExitCountry
+	}
+	return (selection.Only && !multiHop.Enabled()) ||
+		filterByPossibilities(entryCountry, selection.EntryCountries) ||
+		filterByPossibilities(exitCountry, selection.ExitCountries)
+}
+
 func commaJoin(slice []string) string {
 	return strings.Join(slice, ",")
 }
@@ -2,8 +2,11 @@ package provider
 
 import (
 	"math/rand"
+	"net"
 	"testing"
+	"time"
 
+	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/stretchr/testify/assert"
 )
@@ -25,6 +28,141 @@ func Test_pickRandomConnection(t *testing.T) {
 	assert.Equal(t, models.OpenVPNConnection{Port: 2}, connection)
 }
 
+func Test_pickConnection(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		connections []models.OpenVPNConnection
+		selection   configuration.ServerSelection
+		latencies   models.IPLatencies
+		connection  models.OpenVPNConnection
+	}{
+		"no latency": {
+			connections: []models.OpenVPNConnection{
+				{IP: net.IP{1, 1, 1, 1}}, {IP: net.IP{2, 2, 2, 2}}, {IP: net.IP{3, 3, 3, 3}},
+			},
+			connection: models.OpenVPNConnection{IP: net.IP{1, 1, 1, 1}},
+		},
+		"latency selection disabled": {
+			connections: []models.OpenVPNConnection{
+				{IP: net.IP{1, 1, 1, 1}}, {IP: net.IP{2, 2, 2, 2}}, {IP: net.IP{3, 3, 3, 3}},
+			},
+			latencies: models.IPLatencies{
+				"2.2.2.2": 20 * time.Millisecond,
+			},
+			connection: models.OpenVPNConnection{IP: net.IP{1, 1, 1, 1}},
+		},
+		"latency selection without latency": {
+			connections: []models.OpenVPNConnection{
+				{IP: net.IP{1, 1, 1, 1}}, {IP: net.IP{2, 2, 2, 2}}, {IP: net.IP{3, 3, 3, 3}},
+			},
+			selection: configuration.ServerSelection{
+				Latency: configuration.LatencySelection{Enabled: true},
+			},
+			connection: models.OpenVPNConnection{IP: net.IP{1, 1, 1, 1}},
+		},
+		"lowest latency": {
+			connections: []models.OpenVPNConnection{
+				{IP: net.IP{1, 1, 1, 1}}, {IP: net.IP{2, 2, 2, 2}}, {IP: net.IP{3, 3, 3, 3}},
+			},
+			selection: configuration.ServerSelection{
+				Latency: configuration.LatencySelection{Enabled: true},
+			},
+			latencies: models.IPLatencies{
+				"1.1.1.1": 50 * time.Millisecond,
+				"2.2.2.2": 20 * time.Millisecond,
+			},
+			connection: models.OpenVPNConnection{IP: net.IP{2, 2, 2, 2}},
+		},
+		"lowest latency excluded": {
+			connections: []models.OpenVPNConnection{
+				{IP: net.IP{1, 1, 1, 1}}, {IP: net.IP{2, 2, 2, 2}}, {IP: net.IP{3, 3, 3, 3}},
+			},
+			selection: configuration.ServerSelection{
+				ExcludedIPs: []net.IP{{2, 2, 2, 2}},
+				Latency:     configuration.LatencySelection{Enabled: true},
+			},
+			latencies: models.IPLatencies{
+				"1.1.1.1": 50 * time.Millisecond,
+				"2.2.2.2": 20 * time.Millisecond,
+			},
+			connection: models.OpenVPNConnection{IP: net.IP{1, 1, 1, 1}},
+		},
+		"preferred IP": {
+			connections: []models.OpenVPNConnection{
+				{IP: net.IP{1, 1, 1, 1}}, {IP: net.IP{2, 2, 2, 2}}, {IP: net.IP{3, 3, 3, 3}},
+			},
+			selection: configuration.ServerSelection{
+				PreferredIP: net.IP{3, 3, 3, 3},
+				Latency:     configuration.LatencySelection{Enabled: true},
+			},
+			latencies: models.IPLatencies{
+				"2.2.2.2": 20 * time.Millisecond,
+			},
+			connection: models.OpenVPNConnection{IP: net.IP{3, 3, 3, 3}},
+		},
+		"preferred IP excluded": {
+			connections: []models.OpenVPNConnection{
+				{IP: net.IP{1, 1, 1, 1}}, {IP: net.IP{2, 2, 2, 2}},
+			},
+			selection: configuration.ServerSelection{
+				ExcludedIPs: []net.IP{{2, 2, 2, 2}},
+				PreferredIP: net.IP{2, 2, 2, 2},
+			},
+			connection: models.OpenVPNConnection{IP: net.IP{1, 1, 1, 1}},
+		},
+		"all excluded": {
+			connections: []models.OpenVPNConnection{
+				{IP: net.IP{2, 2, 2, 2}},
+			},
+			selection: configuration.ServerSelection{
+				ExcludedIPs: []net.IP{{2, 2, 2, 2}},
+			},
+			connection: models.OpenVPNConnection{IP: net.IP{2, 2, 2, 2}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			source := rand.NewSource(0)
+			connection := pickConnection(testCase.connections, testCase.selection, source, testCase.latencies)
+			assert.Equal(t, testCase.connection, connection)
+		})
+	}
+}
+
+func Test_pickConnection_nearBestLatencies(t *testing.T) {
+	t.Parallel()
+
+	connections := []models.OpenVPNConnection{
+		{IP: net.IP{1, 1, 1, 1}}, {IP: net.IP{2, 2, 2, 2}},
+		{IP: net.IP{3, 3, 3, 3}}, {IP: net.IP{4, 4, 4, 4}},
+	}
+	selection := configuration.ServerSelection{
+		Latency: configuration.LatencySelection{Enabled: true},
+	}
+	// 4.4.4.4 has no known latency
+	latencies := models.IPLatencies{
+		"1.1.1.1": 50 * time.Millisecond,
+		"2.2.2.2": 20 * time.Millisecond,
+		"3.3.3.3": 24 * time.Millisecond,
+	}
+
+	picked := make(map[string]int)
+	const picks = 100
+	for seed := int64(0); seed < picks; seed++ {
+		source := rand.NewSource(seed)
+		connection := pickConnection(connections, selection, source, latencies)
+		picked[connection.IP.String()]++
+	}
+
+	// connections near the lowest latency are picked randomly
+	assert.Len(t, picked, 2)
+	assert.Greater(t, picked["2.2.2.2"], 0)
+	assert.Greater(t, picked["3.3.3.3"], 0)
+}
+
 func Test_filterByPossibilities(t *testing.T) {
 	t.Parallel()
 	testCases := map[string]struct {
@@ -52,3 +190,45 @@ func Test_filterByPossibilities(t *testing.T) {
 		})
 	}
 }
+
+func Test_filterMultiHop(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		multiHop  *models.MultiHop
+		selection configuration.MultiHopSelection
+		filtered  bool
+	}{
+		"no selection": {},
+		"single hop server with multi hop only": {
+			selection: configuration.MultiHopSelection{Only: true},
+			filtered:  true,
+		},
+		"multi hop server with multi hop only": {
+			multiHop:  &models.MultiHop{EntryCountry: "Canada", ExitCountry: "United States"},
+			selection: configuration.MultiHopSelection{Only: true},
+		},
+		"exit country not matching": {
+			multiHop: &models.MultiHop{EntryCountry: "Canada", ExitCountry: "United States"},
+			selection: configuration.MultiHopSelection{
+				ExitCountries: []string{"germany"},
+			},
+			filtered: true,
+		},
+		"entry and exit countries matching": {
+			multiHop: &models.MultiHop{EntryCountry: "Canada", ExitCountry: "United States"},
+			selection: configuration.MultiHopSelection{
+				EntryCountries: []string{"canada"},
+				ExitCountries:  []string{"united states"},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			filtered := filterMultiHop(testCase.multiHop, testCase.selection)
+			assert.Equal(t, testCase.filtered, filtered)
+		})
+	}
+}
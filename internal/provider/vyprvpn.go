@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/gluetun/internal/constants"
@@ -19,66 +20,77 @@ import (
 type vyprvpn struct {
 	servers    []models.VyprvpnServer
 	randSource rand.Source
+	latencies  models.IPLatencies
 }
 
-func newVyprvpn(servers []models.VyprvpnServer, timeNow timeNowFunc) *vyprvpn {
+func newVyprvpn(servers []models.VyprvpnServer, latencies models.IPLatencies,
+	timeNow timeNowFunc) *vyprvpn {
 	return &vyprvpn{
 		servers:    servers,
 		randSource: rand.NewSource(timeNow().UnixNano()),
+		latencies:  latencies,
 	}
 }
 
-func (v *vyprvpn) filterServers(regions []string) (servers []models.VyprvpnServer) {
-	for _, server := range v.servers {
-		switch {
-		case
-			filterByPossibilities(server.Region, regions):
-		default:
+func (v *vyprvpn) filterServers(selection configuration.ServerSelection) (
+	servers []models.VyprvpnServer, err error) {
+	filters := selectionFilters(selection)
+	attributes := make([]serverAttributes, len(v.servers))
+	for i, server := range v.servers {
+		attributes[i] = serverAttributes{
+			region: server.Region, tcp: true, udp: true,
+		}
+		if !filterServer(filters, attributes[i]) {
 			servers = append(servers, server)
 		}
 	}
-	return servers
+	if len(servers) == 0 {
+		return nil, noServerFoundError(filters, attributes)
+	}
+	return servers, nil
 }
 
 func (v *vyprvpn) GetOpenVPNConnection(selection configuration.ServerSelection) (
 	connection models.OpenVPNConnection, err error) {
+	connections, err := v.GetOpenVPNConnections(selection)
+	if err != nil {
+		return connection, err
+	}
+	return pickConnection(connections, selection, v.randSource, v.latencies), nil
+}
+
+func (v *vyprvpn) GetOpenVPNConnections(selection configuration.ServerSelection) (
+	connections []models.OpenVPNConnection, err error) {
 	var port uint16
 	switch {
 	case selection.Protocol == constants.TCP:
-		return connection, fmt.Errorf("TCP protocol not supported by this VPN provider")
+		return nil, fmt.Errorf("TCP protocol not supported by this VPN provider")
 	case selection.Protocol == constants.UDP:
 		port = 443
 	default:
-		return connection, fmt.Errorf("protocol %q is unknown", selection.Protocol)
+		return nil, fmt.Errorf("protocol %q is unknown", selection.Protocol)
 	}
 
 	if selection.TargetIP != nil {
-		return models.OpenVPNConnection{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}, nil
+		return []models.OpenVPNConnection{{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}}, nil
 	}
 
-	servers := v.filterServers(selection.Regions)
-	if len(servers) == 0 {
-		return connection, fmt.Errorf("no server found for region %s", commaJoin(selection.Regions))
+	servers, err := v.filterServers(selection)
+	if err != nil {
+		return nil, err
 	}
 
-	var connections []models.OpenVPNConnection
 	for _, server := range servers {
 		for _, IP := range server.IPs {
 			connections = append(connections, models.OpenVPNConnection{IP: IP, Port: port, Protocol: selection.Protocol})
 		}
 	}
 
-	return pickRandomConnection(connections, v.randSource), nil
+	return connections, nil
 }
 
 func (v *vyprvpn) BuildConf(connection models.OpenVPNConnection,
 	username string, settings configuration.OpenVPN) (lines []string) {
-	if len(settings.Cipher) == 0 {
-		settings.Cipher = aes256cbc
-	}
-	if len(settings.Auth) == 0 {
-		settings.Auth = "SHA256"
-	}
 	lines = []string{
 		"client",
 		"dev tun",
@@ -107,10 +119,9 @@ func (v *vyprvpn) BuildConf(connection models.OpenVPNConnection,
 		fmt.Sprintf("auth-user-pass %s", constants.OpenVPNAuthConf),
 		fmt.Sprintf("proto %s", connection.Protocol),
 		fmt.Sprintf("remote %s %d", connection.IP, connection.Port),
-		"data-ciphers-fallback " + settings.Cipher,
-		"data-ciphers " + settings.Cipher,
-		fmt.Sprintf("auth %s", settings.Auth),
 	}
+	lines = append(lines, cipherProfiles[constants.Vyprvpn].lines(settings.Cipher, settings.Auth)...)
+	lines = append(lines, ipv6Lines(settings.Provider.ExtraConfigOptions.OpenVPNIPv6)...)
 	if !settings.Root {
 		lines = append(lines, "user "+username)
 	}
@@ -129,6 +140,6 @@ func (v *vyprvpn) BuildConf(connection models.OpenVPNConnection,
 
 func (v *vyprvpn) PortForward(ctx context.Context, client *http.Client,
 	openFile os.OpenFileFunc, pfLogger logging.Logger, gateway net.IP, fw firewall.Configurator,
-	syncState func(port uint16) (pfFilepath string)) {
+	syncState func(port uint16, expiration time.Time) (pfFilepath string)) {
 	panic("port forwarding is not supported for vyprvpn")
 }
@@ -7,7 +7,7 @@ import (
 	"net"
 	"net/http"
 	"strconv"
-	"strings"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/gluetun/internal/constants"
@@ -20,31 +20,50 @@ import (
 type windscribe struct {
 	servers    []models.WindscribeServer
 	randSource rand.Source
+	latencies  models.IPLatencies
 }
 
-func newWindscribe(servers []models.WindscribeServer, timeNow timeNowFunc) *windscribe {
+func newWindscribe(servers []models.WindscribeServer, latencies models.IPLatencies,
+	timeNow timeNowFunc) *windscribe {
 	return &windscribe{
 		servers:    servers,
 		randSource: rand.NewSource(timeNow().UnixNano()),
+		latencies:  latencies,
 	}
 }
 
-func (w *windscribe) filterServers(regions, cities, hostnames []string) (servers []models.WindscribeServer) {
-	for _, server := range w.servers {
-		switch {
-		case
-			filterByPossibilities(server.Region, regions),
-			filterByPossibilities(server.City, cities),
-			filterByPossibilities(server.Hostname, hostnames):
-		default:
+func (w *windscribe) filterServers(selection configuration.ServerSelection) (
+	servers []models.WindscribeServer, err error) {
+	filters := selectionFilters(selection)
+	attributes := make([]serverAttributes, len(w.servers))
+	for i, server := range w.servers {
+		attributes[i] = serverAttributes{
+			region: server.Region, city: server.City,
+			hostname: server.Hostname, tcp: true, udp: true,
+			features: server.Features,
+		}
+		if !filterServer(filters, attributes[i]) {
 			servers = append(servers, server)
 		}
 	}
-	return servers
+	if len(servers) == 0 {
+		return nil, noServerFoundError(filters, attributes)
+	}
+	return servers, nil
 }
 
 //nolint:lll
-func (w *windscribe) GetOpenVPNConnection(selection configuration.ServerSelection) (connection models.OpenVPNConnection, err error) {
+func (w *windscribe) GetOpenVPNConnection(selection configuration.ServerSelection) (
+	connection models.OpenVPNConnection, err error) {
+	connections, err := w.GetOpenVPNConnections(selection)
+	if err != nil {
+		return connection, err
+	}
+	return pickConnection(connections, selection, w.randSource, w.latencies), nil
+}
+
+func (w *windscribe) GetOpenVPNConnections(selection configuration.ServerSelection) (
+	connections []models.OpenVPNConnection, err error) {
 	var port uint16
 	switch {
 	case selection.CustomPort > 0:
@@ -54,34 +73,28 @@ func (w *windscribe) GetOpenVPNConnection(selection configuration.ServerSelectio
 	case selection.Protocol == constants.UDP:
 		port = 443
 	default:
-		return connection, fmt.Errorf("protocol %q is unknown", selection.Protocol)
+		return nil, fmt.Errorf("protocol %q is unknown", selection.Protocol)
 	}
 
 	if selection.TargetIP != nil {
-		return models.OpenVPNConnection{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}, nil
+		return []models.OpenVPNConnection{{IP: selection.TargetIP, Port: port, Protocol: selection.Protocol}}, nil
 	}
 
-	servers := w.filterServers(selection.Regions, selection.Cities, selection.Hostnames)
-	if len(servers) == 0 {
-		return connection, fmt.Errorf("no server found for region %s", commaJoin(selection.Regions))
+	servers, err := w.filterServers(selection)
+	if err != nil {
+		return nil, err
 	}
 
-	connections := make([]models.OpenVPNConnection, len(servers))
+	connections = make([]models.OpenVPNConnection, len(servers))
 	for i := range servers {
 		connections[i] = models.OpenVPNConnection{IP: servers[i].IP, Port: port, Protocol: selection.Protocol}
 	}
 
-	return pickRandomConnection(connections, w.randSource), nil
+	return connections, nil
 }
 
 func (w *windscribe) BuildConf(connection models.OpenVPNConnection,
 	username string, settings configuration.OpenVPN) (lines []string) {
-	if len(settings.Cipher) == 0 {
-		settings.Cipher = aes256cbc
-	}
-	if len(settings.Auth) == 0 {
-		settings.Auth = "sha512"
-	}
 	lines = []string{
 		"client",
 		"dev tun",
@@ -98,7 +111,6 @@ func (w *windscribe) BuildConf(connection models.OpenVPNConnection,
 		"key-direction 1",
 		"script-security 2",
 		"reneg-sec 0",
-		"ncp-disable",
 
 		// Added constant values
 		"auth-nocache",
@@ -112,13 +124,9 @@ func (w *windscribe) BuildConf(connection models.OpenVPNConnection,
 		fmt.Sprintf("auth-user-pass %s", constants.OpenVPNAuthConf),
 		fmt.Sprintf("proto %s", connection.Protocol),
 		fmt.Sprintf("remote %s %d", connection.IP, connection.Port),
-		"data-ciphers-fallback " + settings.Cipher,
-		"data-ciphers " + settings.Cipher,
-		fmt.Sprintf("auth %s", settings.Auth),
-	}
-	if strings.HasSuffix(settings.Cipher, "-gcm") {
-		lines = append(lines, "ncp-ciphers AES-256-GCM:AES-256-CBC:AES-128-GCM")
 	}
+	lines = append(lines, cipherProfiles[constants.Windscribe].lines(settings.Cipher, settings.Auth)...)
+	lines = append(lines, ipv6Lines(settings.Provider.ExtraConfigOptions.OpenVPNIPv6)...)
 	if !settings.Root {
 		lines = append(lines, "user "+username)
 	}
@@ -145,6 +153,6 @@ func (w *windscribe) BuildConf(connection models.OpenVPNConnection,
 
 func (w *windscribe) PortForward(ctx context.Context, client *http.Client,
 	openFile os.OpenFileFunc, pfLogger logging.Logger, gateway net.IP, fw firewall.Configurator,
-	syncState func(port uint16) (pfFilepath string)) {
+	syncState func(port uint16, expiration time.Time) (pfFilepath string)) {
 	panic("port forwarding is not supported for windscribe")
 }
@@ -0,0 +1,83 @@
+package publicip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// comparePublicIPs compares the public IP address record before
+// connecting to the VPN with the public IP address record through
+// the VPN.
+func comparePublicIPs(preVPN, vpn models.PublicIPRecord) (
+	comparison models.PublicIPComparison) {
+	return models.PublicIPComparison{
+		PreVPN:    preVPN,
+		VPN:       vpn,
+		IPChanged: preVPN.PublicIP != vpn.PublicIP,
+		ASNChanged: preVPN.ASN != 0 && vpn.ASN != 0 &&
+			preVPN.ASN != vpn.ASN,
+		CountryChanged: preVPN.Country != "" && vpn.Country != "" &&
+			preVPN.Country != vpn.Country,
+	}
+}
+
+// comparisonLines returns the lines of the comparison report to log.
+func comparisonLines(comparison models.PublicIPComparison) (lines []string) {
+	preVPN, vpn := comparison.PreVPN, comparison.VPN
+	lines = append(lines, changedString("public IP address", comparison.IPChanged,
+		preVPN.PublicIP, vpn.PublicIP))
+	if preVPN.ASN != 0 && vpn.ASN != 0 {
+		lines = append(lines, changedString("ASN", comparison.ASNChanged,
+			fmt.Sprint(preVPN.ASN), fmt.Sprint(vpn.ASN)))
+	}
+	if preVPN.Country != "" && vpn.Country != "" {
+		lines = append(lines, changedString("country", comparison.CountryChanged,
+			preVPN.Country, vpn.Country))
+	}
+	return lines
+}
+
+func changedString(name string, changed bool, before, after string) string {
+	if changed {
+		return name + " changed from " + before + " to " + after
+	}
+	return name + " " + after + " unchanged"
+}
+
+// comparePreVPN compares the public IP address record through the VPN
+// given with the public IP address before connecting to the VPN, and
+// logs and stores the comparison if the public IP address changed
+// since the last comparison. It does nothing if the comparison is
+// disabled or the public IP address before connecting is unknown.
+func (l *looper) comparePreVPN(ctx context.Context, vpn models.PublicIPRecord) {
+	settings := l.GetSettings()
+	if !settings.ComparePreVPN || l.preVPN.PublicIP == "" ||
+		l.GetPublicIPComparison().VPN.PublicIP == vpn.PublicIP {
+		return
+	}
+
+	if !l.preVPNInfoFetched && settings.InfoProvider != constants.PublicIPInfoNone {
+		info, err := FetchInfo(ctx, l.client, settings, net.ParseIP(l.preVPN.PublicIP))
+		if err != nil {
+			l.logger.Warn("cannot fetch information on the public IP address before connecting to the VPN: %s", err)
+		} else {
+			l.preVPN.PublicIPInfo = info
+			l.preVPNInfoFetched = true
+		}
+	}
+
+	comparison := comparePublicIPs(l.preVPN, vpn)
+	l.state.setPublicIPComparison(comparison)
+	message := "compared to before connecting to the VPN: " +
+		strings.Join(comparisonLines(comparison), ", ")
+	if comparison.IPChanged {
+		l.logger.Info(message)
+	} else {
+		l.logger.Warn(message)
+	}
+}
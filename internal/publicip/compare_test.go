@@ -0,0 +1,65 @@
+package publicip
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_comparePublicIPs(t *testing.T) {
+	t.Parallel()
+
+	preVPN := models.PublicIPRecord{
+		PublicIP:     "1.2.3.4",
+		PublicIPInfo: models.PublicIPInfo{Country: "France", ASN: 3215},
+	}
+
+	testCases := map[string]struct {
+		vpn        models.PublicIPRecord
+		comparison models.PublicIPComparison
+		lines      []string
+	}{
+		"all changed": {
+			vpn: models.PublicIPRecord{
+				PublicIP:     "5.6.7.8",
+				PublicIPInfo: models.PublicIPInfo{Country: "Sweden", ASN: 39351},
+			},
+			comparison: models.PublicIPComparison{
+				IPChanged: true, ASNChanged: true, CountryChanged: true,
+			},
+			lines: []string{
+				"public IP address changed from 1.2.3.4 to 5.6.7.8",
+				"ASN changed from 3215 to 39351",
+				"country changed from France to Sweden",
+			},
+		},
+		"unchanged": {
+			vpn: preVPN,
+			lines: []string{
+				"public IP address 1.2.3.4 unchanged",
+				"ASN 3215 unchanged",
+				"country France unchanged",
+			},
+		},
+		"information unknown": {
+			vpn:        models.PublicIPRecord{PublicIP: "5.6.7.8"},
+			comparison: models.PublicIPComparison{IPChanged: true},
+			lines:      []string{"public IP address changed from 1.2.3.4 to 5.6.7.8"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			comparison := comparePublicIPs(preVPN, testCase.vpn)
+
+			testCase.comparison.PreVPN = preVPN
+			testCase.comparison.VPN = testCase.vpn
+			assert.Equal(t, testCase.comparison, comparison)
+			assert.Equal(t, testCase.lines, comparisonLines(comparison))
+		})
+	}
+}
@@ -3,6 +3,10 @@ package publicip
 import "errors"
 
 var (
-	ErrBadStatusCode  = errors.New("bad HTTP status")
-	ErrCannotReadBody = errors.New("cannot read response body")
+	ErrBadStatusCode     = errors.New("bad HTTP status")
+	ErrCannotReadBody    = errors.New("cannot read response body")
+	ErrParseIP           = errors.New("cannot parse IP address")
+	ErrAllFetchersFailed = errors.New("all public IP fetchers failed")
+	ErrNoConsensus       = errors.New("public IP fetchers do not agree")
+	ErrIPVersionMismatch = errors.New("IP address is not of the IP version requested")
 )
@@ -0,0 +1,162 @@
+package publicip
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+// fetcher fetches the public IP address from a single source.
+type fetcher struct {
+	name  string
+	fetch func(ctx context.Context) (ip net.IP, err error)
+}
+
+// IP versions of the public IP address to fetch,
+// as network names of the net package lookups.
+const (
+	ipVersion4 = "ip4"
+	ipVersion6 = "ip6"
+)
+
+// fetcherEndpoints contains, for each fetcher, its IPv4 and IPv6
+// endpoints, which are URLs for HTTP fetchers and DNS server
+// addresses for DNS fetchers.
+var fetcherEndpoints = map[string]map[string]string{ //nolint:gochecknoglobals
+	constants.PublicIPFetcherIpify: {
+		ipVersion4: "https://api.ipify.org",
+		ipVersion6: "https://api6.ipify.org",
+	},
+	constants.PublicIPFetcherIcanhazip: {
+		ipVersion4: "https://ipv4.icanhazip.com",
+		ipVersion6: "https://ipv6.icanhazip.com",
+	},
+	constants.PublicIPFetcherIfconfig: {
+		ipVersion4: "https://ifconfig.me/ip",
+		ipVersion6: "https://ifconfig.me/ip",
+	},
+	constants.PublicIPFetcherIPInfo: {
+		ipVersion4: "https://ipinfo.io/ip",
+		ipVersion6: "https://v6.ipinfo.io/ip",
+	},
+	constants.PublicIPFetcherOpenDNS: {
+		ipVersion4: "208.67.222.222:53",
+		ipVersion6: "[2620:119:35::35]:53",
+	},
+	constants.PublicIPFetcherGoogle: {
+		ipVersion4: "216.239.32.10:53",
+		ipVersion6: "[2001:4860:4802:32::a]:53",
+	},
+}
+
+// newFetcher returns the fetcher of the public IP address of the
+// IP version given, which is ip4 or ip6. HTTP requests are only
+// made over the IP version given, so dual stack endpoints return
+// the public IP address of this IP version.
+func newFetcher(name string, client *http.Client, ipVersion string) fetcher {
+	f := fetcher{name: name}
+	endpoint := fetcherEndpoints[name][ipVersion]
+	switch name {
+	case constants.PublicIPFetcherIpify, constants.PublicIPFetcherIcanhazip,
+		constants.PublicIPFetcherIfconfig, constants.PublicIPFetcherIPInfo:
+		f.fetch = httpFetch(ipVersionClient(client, ipVersion), endpoint)
+	case constants.PublicIPFetcherOpenDNS:
+		resolver := newResolver(endpoint)
+		f.fetch = func(ctx context.Context) (ip net.IP, err error) {
+			ips, err := resolver.LookupIP(ctx, ipVersion, "myip.opendns.com")
+			if err != nil {
+				return nil, err
+			} else if len(ips) == 0 {
+				return nil, fmt.Errorf("%w: no IP address found", ErrParseIP)
+			}
+			return ips[0], nil
+		}
+	case constants.PublicIPFetcherGoogle:
+		resolver := newResolver(endpoint)
+		f.fetch = func(ctx context.Context) (ip net.IP, err error) {
+			records, err := resolver.LookupTXT(ctx, "o-o.myaddr.l.google.com")
+			if err != nil {
+				return nil, err
+			}
+			for _, record := range records {
+				if ip = net.ParseIP(record); ip != nil {
+					return ip, nil
+				}
+			}
+			return nil, fmt.Errorf("%w from TXT records %q", ErrParseIP, records)
+		}
+	}
+	return f
+}
+
+// ipVersionClient returns a copy of the client given only
+// connecting over the IP version given, which is ip4 or ip6.
+func ipVersionClient(client *http.Client, ipVersion string) *http.Client {
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		transport = http.DefaultTransport.(*http.Transport) //nolint:forcetypeassert
+	}
+	transport = transport.Clone()
+
+	network := "tcp4"
+	if ipVersion == ipVersion6 {
+		network = "tcp6"
+	}
+	dialer := &net.Dialer{}
+	transport.DialContext = func(ctx context.Context, _, address string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	versionClient := *client
+	versionClient.Transport = transport
+	return &versionClient
+}
+
+// httpFetch returns a function fetching the public IP address
+// from the body of the response to a GET request to the URL given.
+func httpFetch(client *http.Client, url string) func(ctx context.Context) (ip net.IP, err error) {
+	return func(ctx context.Context) (ip net.IP, err error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := client.Do(request)
+		if err != nil {
+			return nil, err
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("%w from %s: %s", ErrBadStatusCode, url, response.Status)
+		}
+
+		content, err := ioutil.ReadAll(response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrCannotReadBody, err)
+		}
+
+		s := strings.TrimSpace(string(content))
+		ip = net.ParseIP(s)
+		if ip == nil {
+			return nil, fmt.Errorf("%w from %q", ErrParseIP, s)
+		}
+		return ip, nil
+	}
+}
+
+// newResolver returns a resolver using the DNS server at the address given.
+func newResolver(address string) *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			dialer := net.Dialer{}
+			return dialer.DialContext(ctx, network, address)
+		},
+	}
+}
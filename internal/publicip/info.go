@@ -7,48 +7,193 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
+	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
 )
 
-type ipInfoData struct {
-	Region  string `json:"region"`
-	Country string `json:"country"`
-	City    string `json:"city"`
-}
-
-var ErrBadHTTPStatus = errors.New("bad HTTP status received")
+var (
+	ErrBadHTTPStatus       = errors.New("bad HTTP status received")
+	ErrInfoProviderUnknown = errors.New("public IP information provider is unknown")
+)
 
+// Info returns the country, region and city of the IP address
+// using ipinfo.io without API token.
 func Info(ctx context.Context, client *http.Client, ip net.IP) ( //nolint:interfacer
 	country, region, city string, err error) {
-	const baseURL = "https://ipinfo.io/"
-	url := baseURL + ip.String()
-	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	info, err := FetchInfo(ctx, client, configuration.PublicIP{
+		InfoProvider: constants.PublicIPInfoIPInfo,
+	}, ip)
+	return info.Country, info.Region, info.City, err
+}
+
+// FetchInfo returns information on the IP address from the
+// information provider set in the settings.
+func FetchInfo(ctx context.Context, client *http.Client,
+	settings configuration.PublicIP, ip net.IP) (info models.PublicIPInfo, err error) {
+	switch settings.InfoProvider {
+	case constants.PublicIPInfoIPInfo:
+		info, err = fetchIPInfo(ctx, client, settings.InfoAPIKey, ip)
+	case constants.PublicIPInfoIP2Location:
+		info, err = fetchIP2Location(ctx, client, settings.InfoAPIKey, ip)
+	case constants.PublicIPInfoMaxMind:
+		info, err = fetchMaxMind(ctx, client, settings.InfoAccountID, settings.InfoAPIKey, ip)
+	default:
+		return info, fmt.Errorf("%w: %s", ErrInfoProviderUnknown, settings.InfoProvider)
+	}
+	if err != nil {
+		return info, fmt.Errorf("cannot fetch information from %s: %w", settings.InfoProvider, err)
+	}
+	return info, nil
+}
+
+func fetchIPInfo(ctx context.Context, client *http.Client, token string,
+	ip net.IP) (info models.PublicIPInfo, err error) {
+	u := "https://ipinfo.io/" + ip.String()
+	if token != "" {
+		u += "?token=" + url.QueryEscape(token)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
 	if err != nil {
-		return "", "", "", err
+		return info, err
 	}
 
+	var data struct {
+		Country string `json:"country"`
+		Region  string `json:"region"`
+		City    string `json:"city"`
+		// Org is the ASN followed by the organization,
+		// for example AS15169 Google LLC.
+		Org string `json:"org"`
+	}
+	if err := doJSON(client, request, &data); err != nil {
+		return info, err
+	}
+
+	info.Country = countryName(data.Country)
+	info.Region = data.Region
+	info.City = data.City
+	info.Organization = data.Org
+	fields := strings.SplitN(data.Org, " ", 2) //nolint:gomnd
+	if asn, err := strconv.ParseUint(strings.TrimPrefix(fields[0], "AS"), 10, 32); err == nil {
+		info.ASN = uint32(asn)
+		if len(fields) == 2 { //nolint:gomnd
+			info.Organization = fields[1]
+		}
+	}
+	return info, nil
+}
+
+func fetchIP2Location(ctx context.Context, client *http.Client, apiKey string,
+	ip net.IP) (info models.PublicIPInfo, err error) {
+	values := url.Values{}
+	values.Set("key", apiKey)
+	values.Set("ip", ip.String())
+	u := "https://api.ip2location.io/?" + values.Encode()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return info, err
+	}
+
+	var data struct {
+		CountryName string `json:"country_name"`
+		RegionName  string `json:"region_name"`
+		CityName    string `json:"city_name"`
+		ASN         string `json:"asn"`
+		AS          string `json:"as"`
+	}
+	if err := doJSON(client, request, &data); err != nil {
+		return info, err
+	}
+
+	info.Country = data.CountryName
+	info.Region = data.RegionName
+	info.City = data.CityName
+	info.Organization = data.AS
+	if asn, err := strconv.ParseUint(data.ASN, 10, 32); err == nil {
+		info.ASN = uint32(asn)
+	}
+	return info, nil
+}
+
+func fetchMaxMind(ctx context.Context, client *http.Client, accountID, licenseKey string,
+	ip net.IP) (info models.PublicIPInfo, err error) {
+	u := "https://geoip.maxmind.com/geoip/v2.1/city/" + ip.String()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return info, err
+	}
+	request.SetBasicAuth(accountID, licenseKey)
+
+	type names struct {
+		Names map[string]string `json:"names"`
+	}
+	var data struct {
+		Country      names   `json:"country"`
+		Subdivisions []names `json:"subdivisions"`
+		City         names   `json:"city"`
+		Traits       struct {
+			ASN          uint32 `json:"autonomous_system_number"`
+			Organization string `json:"autonomous_system_organization"`
+		} `json:"traits"`
+	}
+	if err := doJSON(client, request, &data); err != nil {
+		return info, err
+	}
+
+	const language = "en"
+	info.Country = data.Country.Names[language]
+	if len(data.Subdivisions) > 0 {
+		info.Region = data.Subdivisions[0].Names[language]
+	}
+	info.City = data.City.Names[language]
+	info.ASN = data.Traits.ASN
+	info.Organization = data.Traits.Organization
+	return info, nil
+}
+
+// doJSON runs the request and decodes the JSON response body into data.
+func doJSON(client *http.Client, request *http.Request, data interface{}) (err error) {
 	response, err := client.Do(request)
 	if err != nil {
-		return "", "", "", err
+		// do not return the URL which can contain the API key
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return urlErr.Err
+		}
+		return err
 	}
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		return "", "", "", fmt.Errorf("%w: %d", ErrBadHTTPStatus, response.StatusCode)
+		return fmt.Errorf("%w: %d", ErrBadHTTPStatus, response.StatusCode)
 	}
 
 	decoder := json.NewDecoder(response.Body)
-	var data ipInfoData
-	if err := decoder.Decode(&data); err != nil {
-		return "", "", "", err
-	}
+	return decoder.Decode(data)
+}
 
-	countryCode := strings.ToLower(data.Country)
-	country, ok := constants.CountryCodes()[countryCode]
+// countryName returns the country name of the country
+// code given, or the country code if it is unknown.
+func countryName(countryCode string) (name string) {
+	name, ok := constants.CountryCodes()[strings.ToLower(countryCode)]
 	if !ok {
-		country = data.Country
+		return countryCode
+	}
+	return name
+}
+
+// infoString returns the information given as a string to log.
+func infoString(info models.PublicIPInfo) string {
+	var fields []string
+	for _, field := range []string{info.Country, info.Region, info.City, info.Organization} {
+		if field != "" {
+			fields = append(fields, field)
+		}
 	}
-	return country, data.Region, data.City, nil
+	return strings.Join(fields, ", ")
 }
@@ -0,0 +1,105 @@
+package publicip
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(request *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+func Test_FetchInfo(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		settings configuration.PublicIP
+		url      string
+		body     string
+		info     models.PublicIPInfo
+	}{
+		"ipinfo": {
+			settings: configuration.PublicIP{
+				InfoProvider: constants.PublicIPInfoIPInfo,
+				InfoAPIKey:   "token",
+			},
+			url:  "https://ipinfo.io/1.2.3.4?token=token",
+			body: `{"country":"US","region":"California","city":"Mountain View","org":"AS15169 Google LLC"}`,
+			info: models.PublicIPInfo{
+				Country:      "United States",
+				Region:       "California",
+				City:         "Mountain View",
+				ASN:          15169,
+				Organization: "Google LLC",
+			},
+		},
+		"ip2location": {
+			settings: configuration.PublicIP{
+				InfoProvider: constants.PublicIPInfoIP2Location,
+				InfoAPIKey:   "key",
+			},
+			url: "https://api.ip2location.io/?ip=1.2.3.4&key=key",
+			body: `{"country_name":"United States of America","region_name":"California",` +
+				`"city_name":"Mountain View","asn":"15169","as":"Google LLC"}`,
+			info: models.PublicIPInfo{
+				Country:      "United States of America",
+				Region:       "California",
+				City:         "Mountain View",
+				ASN:          15169,
+				Organization: "Google LLC",
+			},
+		},
+		"maxmind": {
+			settings: configuration.PublicIP{
+				InfoProvider:  constants.PublicIPInfoMaxMind,
+				InfoAccountID: "id",
+				InfoAPIKey:    "key",
+			},
+			url: "https://geoip.maxmind.com/geoip/v2.1/city/1.2.3.4",
+			body: `{"country":{"names":{"en":"United States"}},"subdivisions":[{"names":{"en":"California"}}],` +
+				`"city":{"names":{"en":"Mountain View"}},"traits":{"autonomous_system_number":15169,` +
+				`"autonomous_system_organization":"GOOGLE"}}`,
+			info: models.PublicIPInfo{
+				Country:      "United States",
+				Region:       "California",
+				City:         "Mountain View",
+				ASN:          15169,
+				Organization: "GOOGLE",
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &http.Client{
+				Transport: roundTripFunc(func(request *http.Request) (*http.Response, error) {
+					assert.Equal(t, testCase.url, request.URL.String())
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ioutil.NopCloser(strings.NewReader(testCase.body)),
+					}, nil
+				}),
+			}
+
+			info, err := FetchInfo(context.Background(), client, testCase.settings, net.IPv4(1, 2, 3, 4))
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.info, info)
+		})
+	}
+}
@@ -22,18 +22,26 @@ type Looper interface {
 	GetSettings() (settings configuration.PublicIP)
 	SetSettings(settings configuration.PublicIP) (outcome string)
 	GetPublicIP() (publicIP net.IP)
+	GetPublicIPv6() (publicIPv6 net.IP)
+	GetPublicIPInfo() (info models.PublicIPInfo)
+	GetPublicIPChanges() (changes uint64)
+	GetPublicIPHistory() (records []models.PublicIPRecord)
+	GetPublicIPComparison() (comparison models.PublicIPComparison)
 }
 
 type looper struct {
 	state state
 	// Objects
-	getter IPGetter
 	client *http.Client
 	logger logging.Logger
 	os     os.OS
 	// Fixed settings
 	puid int
 	pgid int
+	// preVPN is the public IP address record before connecting to
+	// the VPN, and its public IP address is empty if it is unknown.
+	preVPN            models.PublicIPRecord
+	preVPNInfoFetched bool
 	// Internal channels and locks
 	loopLock     sync.Mutex
 	start        chan struct{}
@@ -49,9 +57,17 @@ type looper struct {
 
 const defaultBackoffTime = 5 * time.Second
 
+// NewLooper creates a new public IP getter loop. The preVPNIP is the
+// public IP address before connecting to the VPN, to compare it with
+// the public IP address through the VPN, and can be nil.
 func NewLooper(client *http.Client, logger logging.Logger,
 	settings configuration.PublicIP, puid, pgid int,
-	os os.OS) Looper {
+	os os.OS, preVPNIP net.IP) Looper {
+	var preVPN models.PublicIPRecord
+	if preVPNIP != nil {
+		preVPN.Time = time.Now()
+		preVPN.PublicIP = preVPNIP.String()
+	}
 	return &looper{
 		state: state{
 			status:   constants.Stopped,
@@ -59,11 +75,11 @@ func NewLooper(client *http.Client, logger logging.Logger,
 		},
 		// Objects
 		client:       client,
-		getter:       NewIPGetter(client),
 		logger:       logger.NewChild(logging.SetPrefix("ip getter: ")),
 		os:           os,
 		puid:         puid,
 		pgid:         pgid,
+		preVPN:       preVPN,
 		start:        make(chan struct{}),
 		running:      make(chan models.LoopStatus),
 		stop:         make(chan struct{}),
@@ -106,11 +122,12 @@ func (l *looper) Run(ctx context.Context, wg *sync.WaitGroup) {
 	for ctx.Err() == nil {
 		getCtx, getCancel := context.WithCancel(ctx)
 		defer getCancel()
+		getter := NewIPGetter(l.client, l.GetSettings())
 
 		ipCh := make(chan net.IP)
 		errorCh := make(chan error)
 		go func() {
-			ip, err := l.getter.Get(getCtx)
+			ip, err := getter.Get(getCtx)
 			if err != nil {
 				if getCtx.Err() == nil {
 					errorCh <- err
@@ -151,18 +168,28 @@ func (l *looper) Run(ctx context.Context, wg *sync.WaitGroup) {
 				l.stopped <- struct{}{}
 			case ip := <-ipCh:
 				getCancel()
-				l.state.setPublicIP(ip)
 
 				message := "Public IP address is " + ip.String()
-				country, region, city, err := Info(ctx, l.client, ip)
-				if err != nil {
-					l.logger.Warn(err)
-				} else {
-					message += " (" + country + ", " + region + ", " + city + ")"
+				var info models.PublicIPInfo
+				if settings := l.GetSettings(); settings.InfoProvider != constants.PublicIPInfoNone {
+					var err error
+					info, err = FetchInfo(ctx, l.client, settings, ip)
+					if err != nil {
+						l.logger.Warn(err)
+					} else {
+						message += " (" + infoString(info) + ")"
+					}
 				}
+				previous, changed := l.state.setPublicIP(ip, info, l.timeNow())
 				l.logger.Info(message)
+				l.state.setPublicIPv6(l.getIPv6(ctx))
+				if changed {
+					l.onChange(ctx, previous)
+				}
+				history := l.GetPublicIPHistory()
+				l.comparePreVPN(ctx, history[len(history)-1])
 
-				err = persistPublicIP(l.os.OpenFile, l.state.settings.IPFilepath,
+				err := persistPublicIP(l.os.OpenFile, l.state.settings.IPFilepath,
 					ip.String(), l.puid, l.pgid)
 				if err != nil {
 					l.logger.Error(err)
@@ -181,6 +208,41 @@ func (l *looper) Run(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
+// getIPv6 returns the public IPv6 address if IPv6 is enabled,
+// and nil if it is disabled or cannot be found.
+func (l *looper) getIPv6(ctx context.Context) (ipv6 net.IP) {
+	settings := l.GetSettings()
+	if !settings.IPv6 {
+		return nil
+	}
+	ipv6, err := NewIPv6Getter(l.client, settings).Get(ctx)
+	if err != nil {
+		l.logger.Warn("cannot get public IPv6 address: %s", err)
+		return nil
+	}
+	l.logger.Info("Public IPv6 address is " + ipv6.String())
+	return ipv6
+}
+
+// onChange logs the public IP address change from the previous
+// record given and notifies the change webhook if it is set.
+func (l *looper) onChange(ctx context.Context, previous models.PublicIPRecord) {
+	history := l.GetPublicIPHistory()
+	current := history[len(history)-1]
+	l.logger.Warn("public IP address changed from %s to %s", previous.PublicIP, current.PublicIP)
+
+	webhookURL := l.GetSettings().ChangeWebhook
+	if webhookURL == "" {
+		return
+	}
+	const timeout = 10 * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := notifyChange(ctx, l.client, webhookURL, previous, current); err != nil {
+		l.logger.Warn("cannot notify public IP change webhook: %s", err)
+	}
+}
+
 func (l *looper) RunRestartTicker(ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 	timer := time.NewTimer(time.Hour)
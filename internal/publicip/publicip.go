@@ -4,11 +4,12 @@ package publicip
 import (
 	"context"
 	"fmt"
-	"io/ioutil"
-	"math/rand"
 	"net"
 	"net/http"
 	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration"
 )
 
 type IPGetter interface {
@@ -16,54 +17,71 @@ type IPGetter interface {
 }
 
 type ipGetter struct {
-	client   *http.Client
-	randIntn func(n int) int
+	fetchers  []fetcher
+	consensus int
+	timeout   time.Duration
+	ipVersion string
 }
 
-func NewIPGetter(client *http.Client) IPGetter {
-	return &ipGetter{
-		client:   client,
-		randIntn: rand.Intn,
-	}
+// NewIPGetter returns a getter of the public IPv4 address.
+func NewIPGetter(client *http.Client, settings configuration.PublicIP) IPGetter {
+	return newIPGetter(client, settings, ipVersion4)
 }
 
-func (i *ipGetter) Get(ctx context.Context) (ip net.IP, err error) {
-	urls := []string{
-		"https://ifconfig.me/ip",
-		"http://ip1.dynupdate.no-ip.com:8245",
-		"http://ip1.dynupdate.no-ip.com",
-		"https://api.ipify.org",
-		"https://diagnostic.opendns.com/myip",
-		"https://domains.google.com/checkip",
-		"https://ifconfig.io/ip",
-		"https://ipinfo.io/ip",
-	}
-	url := urls[i.randIntn(len(urls))]
+// NewIPv6Getter returns a getter of the public IPv6 address.
+func NewIPv6Getter(client *http.Client, settings configuration.PublicIP) IPGetter {
+	return newIPGetter(client, settings, ipVersion6)
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
+func newIPGetter(client *http.Client, settings configuration.PublicIP,
+	ipVersion string) *ipGetter {
+	fetchers := make([]fetcher, len(settings.Fetchers))
+	for i, name := range settings.Fetchers {
+		fetchers[i] = newFetcher(name, client, ipVersion)
 	}
-
-	response, err := i.client.Do(req)
-	if err != nil {
-		return nil, err
+	return &ipGetter{
+		fetchers:  fetchers,
+		consensus: settings.Consensus,
+		timeout:   settings.Timeout,
+		ipVersion: ipVersion,
 	}
-	defer response.Body.Close()
+}
+
+// Get tries the fetchers in order until the consensus number
+// of them find the same public IP address of the IP version
+// of the getter.
+func (i *ipGetter) Get(ctx context.Context) (ip net.IP, err error) {
+	ipToCount := make(map[string]int, len(i.fetchers))
+	var errorMessages []string
+	for _, fetcher := range i.fetchers {
+		fetchCtx, cancel := context.WithTimeout(ctx, i.timeout)
+		ip, err := fetcher.fetch(fetchCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		} else if err != nil {
+			errorMessages = append(errorMessages, fetcher.name+": "+err.Error())
+			continue
+		} else if isIPv4 := ip.To4() != nil; isIPv4 != (i.ipVersion == ipVersion4) {
+			errorMessages = append(errorMessages, fmt.Sprintf("%s: %s: %s",
+				fetcher.name, ErrIPVersionMismatch, ip))
+			continue
+		}
 
-	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%w from %s: %s", ErrBadStatusCode, url, response.Status)
+		ipToCount[ip.String()]++
+		if ipToCount[ip.String()] >= i.consensus {
+			return ip, nil
+		}
 	}
 
-	content, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return nil, fmt.Errorf("%w: %s", ErrCannotReadBody, err)
+	if len(ipToCount) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrAllFetchersFailed, strings.Join(errorMessages, "; "))
 	}
 
-	s := strings.ReplaceAll(string(content), "\n", "")
-	ip = net.ParseIP(s)
-	if ip == nil {
-		return nil, fmt.Errorf("cannot parse IP address from %q", s)
+	results := make([]string, 0, len(ipToCount))
+	for ip, count := range ipToCount {
+		results = append(results, fmt.Sprintf("%s found by %d fetcher(s)", ip, count))
 	}
-	return ip, nil
+	return nil, fmt.Errorf("%w: %d fetchers must agree: %s", ErrNoConsensus,
+		i.consensus, strings.Join(results, ", "))
 }
@@ -0,0 +1,91 @@
+package publicip
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ipGetter_Get(t *testing.T) {
+	t.Parallel()
+
+	errFetch := errors.New("test error")
+	ipA := net.IPv4(1, 2, 3, 4)
+	ipB := net.IPv4(5, 6, 7, 8)
+	newFetcher := func(name string, ip net.IP, err error) fetcher {
+		return fetcher{
+			name: name,
+			fetch: func(ctx context.Context) (net.IP, error) {
+				return ip, err
+			},
+		}
+	}
+
+	testCases := map[string]struct {
+		fetchers   []fetcher
+		consensus  int
+		ip         net.IP
+		err        error
+		errMessage string
+	}{
+		"first fetcher succeeds": {
+			fetchers:  []fetcher{newFetcher("a", ipA, nil), newFetcher("b", ipB, nil)},
+			consensus: 1,
+			ip:        ipA,
+		},
+		"fallback": {
+			fetchers:  []fetcher{newFetcher("a", nil, errFetch), newFetcher("b", ipB, nil)},
+			consensus: 1,
+			ip:        ipB,
+		},
+		"all fail": {
+			fetchers:   []fetcher{newFetcher("a", nil, errFetch), newFetcher("b", nil, errFetch)},
+			consensus:  1,
+			err:        ErrAllFetchersFailed,
+			errMessage: "all public IP fetchers failed: a: test error; b: test error",
+		},
+		"consensus reached": {
+			fetchers: []fetcher{newFetcher("a", ipA, nil), newFetcher("b", ipB, nil),
+				newFetcher("c", ipA, nil)},
+			consensus: 2,
+			ip:        ipA,
+		},
+		"IP version mismatch": {
+			fetchers:  []fetcher{newFetcher("a", net.ParseIP("::1"), nil), newFetcher("b", ipB, nil)},
+			consensus: 1,
+			ip:        ipB,
+		},
+		"no consensus": {
+			fetchers:   []fetcher{newFetcher("a", ipA, nil), newFetcher("b", nil, errFetch)},
+			consensus:  2,
+			err:        ErrNoConsensus,
+			errMessage: "public IP fetchers do not agree: 2 fetchers must agree: 1.2.3.4 found by 1 fetcher(s)",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			getter := &ipGetter{
+				fetchers:  testCase.fetchers,
+				consensus: testCase.consensus,
+				timeout:   time.Second,
+				ipVersion: ipVersion4,
+			}
+
+			ip, err := getter.Get(context.Background())
+
+			assert.ErrorIs(t, err, testCase.err)
+			if testCase.err != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.True(t, testCase.ip.Equal(ip))
+		})
+	}
+}
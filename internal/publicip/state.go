@@ -5,6 +5,7 @@ import (
 	"net"
 	"reflect"
 	"sync"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration"
 	"github.com/qdm12/gluetun/internal/constants"
@@ -12,9 +13,22 @@ import (
 )
 
 type state struct {
-	status     models.LoopStatus
-	settings   configuration.PublicIP
-	ip         net.IP
+	status   models.LoopStatus
+	settings configuration.PublicIP
+	ip       net.IP
+	// ipv6 is the public IPv6 address, and is nil
+	// if IPv6 is disabled or it cannot be found.
+	ipv6 net.IP
+	info models.PublicIPInfo
+	// ipChanges is the number of times the public IP
+	// address changed after it was first found.
+	ipChanges uint64
+	// history contains the last public IP addresses
+	// found, oldest first.
+	history []models.PublicIPRecord
+	// comparison is the last comparison of the public IP address
+	// with the public IP address before connecting to the VPN.
+	comparison models.PublicIPComparison
 	statusMu   sync.RWMutex
 	settingsMu sync.RWMutex
 	ipMu       sync.RWMutex
@@ -102,9 +116,87 @@ func (l *looper) GetPublicIP() (publicIP net.IP) {
 	return publicIP
 }
 
-func (s *state) setPublicIP(publicIP net.IP) {
+func (l *looper) GetPublicIPv6() (publicIPv6 net.IP) {
+	l.state.ipMu.RLock()
+	defer l.state.ipMu.RUnlock()
+	if l.state.ipv6 == nil {
+		return nil
+	}
+	publicIPv6 = make(net.IP, len(l.state.ipv6))
+	copy(publicIPv6, l.state.ipv6)
+	return publicIPv6
+}
+
+func (l *looper) GetPublicIPInfo() (info models.PublicIPInfo) {
+	l.state.ipMu.RLock()
+	defer l.state.ipMu.RUnlock()
+	return l.state.info
+}
+
+func (l *looper) GetPublicIPChanges() (changes uint64) {
+	l.state.ipMu.RLock()
+	defer l.state.ipMu.RUnlock()
+	return l.state.ipChanges
+}
+
+func (s *state) setPublicIPv6(publicIPv6 net.IP) {
 	s.ipMu.Lock()
 	defer s.ipMu.Unlock()
+	s.ipv6 = publicIPv6
+}
+
+// GetPublicIPHistory returns the last public IP addresses found, oldest first.
+func (l *looper) GetPublicIPHistory() (records []models.PublicIPRecord) {
+	l.state.ipMu.RLock()
+	defer l.state.ipMu.RUnlock()
+	records = make([]models.PublicIPRecord, len(l.state.history))
+	copy(records, l.state.history)
+	return records
+}
+
+// GetPublicIPComparison returns the last comparison of the public IP
+// address with the public IP address before connecting to the VPN.
+func (l *looper) GetPublicIPComparison() (comparison models.PublicIPComparison) {
+	l.state.ipMu.RLock()
+	defer l.state.ipMu.RUnlock()
+	return l.state.comparison
+}
+
+func (s *state) setPublicIPComparison(comparison models.PublicIPComparison) {
+	s.ipMu.Lock()
+	defer s.ipMu.Unlock()
+	s.comparison = comparison
+}
+
+// historySize is the number of public IP addresses kept in the history.
+const historySize = 20
+
+// setPublicIP sets the public IP address found at the time given, and
+// adds it to the history if it differs from the last one found.
+// It returns the previous public IP address record if it changed.
+func (s *state) setPublicIP(publicIP net.IP, info models.PublicIPInfo,
+	now time.Time) (previous models.PublicIPRecord, changed bool) {
+	s.ipMu.Lock()
+	defer s.ipMu.Unlock()
+	s.info = info
+	if s.ip.Equal(publicIP) {
+		return previous, false
+	}
+
+	if s.ip != nil {
+		s.ipChanges++
+		previous, changed = s.history[len(s.history)-1], true
+	}
 	s.ip = make(net.IP, len(publicIP))
 	copy(s.ip, publicIP)
+
+	if len(s.history) == historySize {
+		s.history = s.history[1:]
+	}
+	s.history = append(s.history, models.PublicIPRecord{
+		Time:         now,
+		PublicIP:     publicIP.String(),
+		PublicIPInfo: info,
+	})
+	return previous, changed
 }
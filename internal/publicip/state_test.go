@@ -0,0 +1,41 @@
+package publicip
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_state_setPublicIP(t *testing.T) {
+	t.Parallel()
+
+	s := &state{}
+	ipA, ipB := net.IP{1, 2, 3, 4}, net.IP{5, 6, 7, 8}
+	timeA := time.Unix(1000, 0)
+	timeB := time.Unix(2000, 0)
+	recordA := models.PublicIPRecord{Time: timeA, PublicIP: "1.2.3.4"}
+	recordB := models.PublicIPRecord{Time: timeB, PublicIP: "5.6.7.8"}
+
+	previous, changed := s.setPublicIP(ipA, models.PublicIPInfo{}, timeA)
+	assert.False(t, changed)
+	assert.Equal(t, models.PublicIPRecord{}, previous)
+
+	_, changed = s.setPublicIP(ipA, models.PublicIPInfo{}, timeB)
+	assert.False(t, changed)
+
+	previous, changed = s.setPublicIP(ipB, models.PublicIPInfo{}, timeB)
+	assert.True(t, changed)
+	assert.Equal(t, recordA, previous)
+
+	assert.Equal(t, []models.PublicIPRecord{recordA, recordB}, s.history)
+	assert.Equal(t, uint64(1), s.ipChanges)
+
+	for i := 0; i < historySize; i++ {
+		_, _ = s.setPublicIP(net.IP{10, 0, 0, byte(i)}, models.PublicIPInfo{}, timeB)
+	}
+	assert.Len(t, s.history, historySize)
+	assert.Equal(t, "10.0.0.0", s.history[0].PublicIP)
+}
@@ -0,0 +1,54 @@
+package publicip
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// publicIPChange is the JSON body sent to the change webhook.
+type publicIPChange struct {
+	Previous models.PublicIPRecord `json:"previous"`
+	Current  models.PublicIPRecord `json:"current"`
+}
+
+// notifyChange sends the previous and current public IP address
+// records as JSON to the webhook URL given with a POST request.
+func notifyChange(ctx context.Context, client *http.Client, webhookURL string,
+	previous, current models.PublicIPRecord) (err error) {
+	body, err := json.Marshal(publicIPChange{
+		Previous: previous,
+		Current:  current,
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) { // do not log the URL containing a secret token
+			err = urlErr.Err
+		}
+		return err
+	}
+	if err := response.Body.Close(); err != nil {
+		return err
+	}
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %s", ErrBadHTTPStatus, response.Status)
+	}
+	return nil
+}
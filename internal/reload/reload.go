@@ -0,0 +1,263 @@
+// Package reload defines a reloader applying at runtime the settings
+// which can be reloaded without restarting the program.
+package reload
+
+import (
+	"context"
+	nativeos "os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/dns"
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/qdm12/gluetun/internal/httpproxy"
+	"github.com/qdm12/gluetun/internal/openvpn"
+	"github.com/qdm12/gluetun/internal/shadowsocks"
+	"github.com/qdm12/golibs/logging"
+	"github.com/qdm12/golibs/os"
+	"github.com/qdm12/golibs/params"
+)
+
+type Reloader interface {
+	// Run reloads the settings each time the program receives
+	// the SIGHUP signal, until the context is canceled.
+	Run(ctx context.Context, wg *sync.WaitGroup)
+	// Reload reads again the settings which can be reloaded and
+	// only updates the loops affected by the settings changed.
+	Reload(ctx context.Context) (outcome string, err error)
+	// GetSettings returns the settings last applied.
+	GetSettings() (settings configuration.Settings)
+}
+
+type reloader struct {
+	settings   configuration.Settings
+	settingsMu sync.RWMutex
+	// configFilepath is the path of the configuration file,
+	// which is empty if no configuration file is used.
+	configFilepath   string
+	configFileKeys   configuration.FileKeys
+	environment      configuration.Environment
+	envDefaultsPath  string
+	defaultInterface string
+	openvpnLooper    openvpn.Looper
+	dnsLooper        dns.Looper
+	httpProxyLooper  httpproxy.Looper
+	ssLooper         shadowsocks.Looper
+	fw               firewall.Configurator
+	os               os.OS
+	logger           logging.Logger
+	// reloadMu prevents concurrent reloads.
+	reloadMu sync.Mutex
+}
+
+func New(settings configuration.Settings, configFilepath string,
+	configFileKeys configuration.FileKeys, environment configuration.Environment,
+	envDefaultsPath string,
+	defaultInterface string,
+	openvpnLooper openvpn.Looper, dnsLooper dns.Looper,
+	httpProxyLooper httpproxy.Looper, ssLooper shadowsocks.Looper,
+	fw firewall.Configurator, os os.OS, logger logging.Logger) Reloader {
+	return &reloader{
+		settings:         settings,
+		configFilepath:   configFilepath,
+		configFileKeys:   configFileKeys,
+		environment:      environment,
+		envDefaultsPath:  envDefaultsPath,
+		defaultInterface: defaultInterface,
+		openvpnLooper:    openvpnLooper,
+		dnsLooper:        dnsLooper,
+		httpProxyLooper:  httpProxyLooper,
+		ssLooper:         ssLooper,
+		fw:               fw,
+		os:               os,
+		logger:           logger.NewChild(logging.SetPrefix("reload: ")),
+	}
+}
+
+func (r *reloader) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	signalsCh := make(chan nativeos.Signal, 1)
+	signal.Notify(signalsCh, syscall.SIGHUP)
+	defer signal.Stop(signalsCh)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signalsCh:
+			r.logger.Info("Caught OS signal SIGHUP, reloading settings")
+			outcome, err := r.Reload(ctx)
+			if err != nil {
+				r.logger.Error(err)
+				continue
+			}
+			r.logger.Info(outcome)
+		}
+	}
+}
+
+func (r *reloader) GetSettings() (settings configuration.Settings) {
+	r.settingsMu.RLock()
+	defer r.settingsMu.RUnlock()
+	return r.settings
+}
+
+func (r *reloader) setSettings(settings configuration.Settings) {
+	r.settingsMu.Lock()
+	defer r.settingsMu.Unlock()
+	r.settings = settings
+}
+
+func (r *reloader) Reload(ctx context.Context) (outcome string, err error) {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	if r.configFilepath != "" {
+		keys, err := configuration.LoadFile(r.configFilepath, r.environment, r.configFileKeys)
+		if err != nil {
+			return "", err
+		}
+		r.configFileKeys = keys
+		if err := configuration.ApplyEnvDefaults(r.envDefaultsPath); err != nil {
+			return "", err
+		}
+	}
+
+	old := r.GetSettings()
+	settings := old
+	err = settings.ReadReloadable(params.NewEnv(), r.os, r.logger)
+	if err != nil {
+		return "", r.configFileKeys.WrapError(err, r.configFilepath)
+	}
+
+	// Keep the settings changed so far if applying the next ones fails.
+	applied := old
+	defer func() { r.setSettings(applied) }()
+
+	var reloaded []string
+
+	newSelection := settings.OpenVPN.Provider.ServerSelection
+	if !reflect.DeepEqual(old.OpenVPN.Provider.ServerSelection, newSelection) {
+		_, err := r.openvpnLooper.SetServerSelection(settings.OpenVPN.Provider.Name, newSelection)
+		if err != nil {
+			return "", err
+		}
+		applied.OpenVPN.Provider.ServerSelection = newSelection
+		reloaded = append(reloaded, "server filters")
+	}
+
+	if blocklistsChanged(old.DNS, settings.DNS) {
+		dnsSettings := r.dnsLooper.GetSettings()
+		dnsSettings.BlockCategories = settings.DNS.BlockCategories
+		dnsSettings.AllowedHostnames = settings.DNS.AllowedHostnames
+		dnsSettings.BlocklistURLs = settings.DNS.BlocklistURLs
+		dnsSettings.AllowlistURLs = settings.DNS.AllowlistURLs
+		if _, err := r.dnsLooper.SetBlocklists(ctx, dnsSettings); err != nil {
+			return "", err
+		}
+		applied.DNS = settings.DNS
+		reloaded = append(reloaded, "DNS block lists")
+	}
+
+	if !reflect.DeepEqual(old.Firewall.VPNInputPorts, settings.Firewall.VPNInputPorts) ||
+		!reflect.DeepEqual(old.Firewall.InputPorts, settings.Firewall.InputPorts) {
+		if err := r.setInputPorts(ctx, old.Firewall, settings.Firewall); err != nil {
+			return "", err
+		}
+		applied.Firewall = settings.Firewall
+		reloaded = append(reloaded, "firewall input ports")
+	}
+
+	if !reflect.DeepEqual(old.HTTPProxy, settings.HTTPProxy) {
+		httpProxySettings := r.httpProxyLooper.GetSettings()
+		httpProxySettings.User = settings.HTTPProxy.User
+		httpProxySettings.Password = settings.HTTPProxy.Password
+		httpProxySettings.Users = settings.HTTPProxy.Users
+		r.httpProxyLooper.SetSettings(httpProxySettings)
+		applied.HTTPProxy = settings.HTTPProxy
+		reloaded = append(reloaded, "HTTP proxy credentials")
+	}
+
+	if old.ShadowSocks.Password != settings.ShadowSocks.Password {
+		ssSettings := r.ssLooper.GetSettings()
+		ssSettings.Password = settings.ShadowSocks.Password
+		r.ssLooper.SetSettings(ssSettings)
+		applied.ShadowSocks = settings.ShadowSocks
+		reloaded = append(reloaded, "shadowsocks password")
+	}
+
+	if len(reloaded) == 0 {
+		return "settings left unchanged", nil
+	}
+	return "reloaded " + strings.Join(reloaded, ", "), nil
+}
+
+func blocklistsChanged(old, updated configuration.DNS) bool {
+	return !reflect.DeepEqual(old.BlockCategories, updated.BlockCategories) ||
+		!reflect.DeepEqual(old.AllowedHostnames, updated.AllowedHostnames) ||
+		!reflect.DeepEqual(old.BlocklistURLs, updated.BlocklistURLs) ||
+		!reflect.DeepEqual(old.AllowlistURLs, updated.AllowlistURLs)
+}
+
+// setInputPorts removes the input ports no longer allowed and
+// allows the updated input ports through their interface.
+// Ports forwarded by the VPN provider are not removed, since
+// port forwarding still needs them allowed.
+func (r *reloader) setInputPorts(ctx context.Context, old, updated configuration.Firewall) (err error) {
+	forwarded := make(map[uint16]struct{})
+	for _, port := range r.openvpnLooper.GetPortForwardStatus().Ports {
+		forwarded[port.Port] = struct{}{}
+	}
+
+	for _, port := range removedPorts(old, updated) {
+		if _, ok := forwarded[port]; ok {
+			r.logger.Info("keeping port %d allowed since it is forwarded", port)
+			continue
+		}
+		if err := r.fw.RemoveAllowedPort(ctx, port); err != nil {
+			return err
+		}
+	}
+
+	for _, port := range updated.VPNInputPorts {
+		if err := r.fw.SetAllowedPort(ctx, port, r.fw.GetVPNInterface()); err != nil {
+			return err
+		}
+	}
+
+	for _, port := range updated.InputPorts {
+		if err := r.fw.SetAllowedPort(ctx, port, r.defaultInterface); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removedPorts returns the input ports of the old firewall
+// settings which are not in the updated firewall settings.
+func removedPorts(old, updated configuration.Firewall) (removed []uint16) {
+	kept := make(map[uint16]struct{}, len(updated.VPNInputPorts)+len(updated.InputPorts))
+	for _, port := range updated.VPNInputPorts {
+		kept[port] = struct{}{}
+	}
+	for _, port := range updated.InputPorts {
+		kept[port] = struct{}{}
+	}
+
+	for _, ports := range [][]uint16{old.VPNInputPorts, old.InputPorts} {
+		for _, port := range ports {
+			if _, ok := kept[port]; ok {
+				continue
+			}
+			kept[port] = struct{}{} // avoid removing a port twice
+			removed = append(removed, port)
+		}
+	}
+	return removed
+}
@@ -0,0 +1,182 @@
+package reload
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/firewall/mock_firewall"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/openvpn/mock_openvpn"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/qdm12/golibs/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_removedPorts(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		old     configuration.Firewall
+		updated configuration.Firewall
+		removed []uint16
+	}{
+		"no ports": {},
+		"ports unchanged": {
+			old:     configuration.Firewall{VPNInputPorts: []uint16{1000}, InputPorts: []uint16{2000}},
+			updated: configuration.Firewall{VPNInputPorts: []uint16{1000}, InputPorts: []uint16{2000}},
+		},
+		"ports removed": {
+			old:     configuration.Firewall{VPNInputPorts: []uint16{1000, 1001}, InputPorts: []uint16{2000}},
+			updated: configuration.Firewall{VPNInputPorts: []uint16{1001}, InputPorts: []uint16{3000}},
+			removed: []uint16{1000, 2000},
+		},
+		"port moved to other interface": {
+			old:     configuration.Firewall{VPNInputPorts: []uint16{1000}},
+			updated: configuration.Firewall{InputPorts: []uint16{1000}},
+		},
+		"port in both lists removed once": {
+			old:     configuration.Firewall{VPNInputPorts: []uint16{1000}, InputPorts: []uint16{1000}},
+			removed: []uint16{1000},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			removed := removedPorts(testCase.old, testCase.updated)
+			assert.Equal(t, testCase.removed, removed)
+		})
+	}
+}
+
+// Test_reloader_Reload is not parallel since it
+// reads the settings from the process environment.
+func Test_reloader_Reload(t *testing.T) { //nolint:paralleltest
+	errTest := errors.New("test error")
+
+	t.Setenv("COUNTRY", "france")
+	t.Setenv("FIREWALL_VPN_INPUT_PORTS", "1000,3000")
+	t.Setenv("FIREWALL_INPUT_PORTS", "")
+
+	initial := configuration.Settings{VPNType: constants.OpenVPN}
+	initial.OpenVPN.Provider.Name = constants.Mullvad
+	err := initial.ReadReloadable(params.NewEnv(), nil, nil)
+	require.NoError(t, err)
+
+	sweden := initial.OpenVPN.Provider.ServerSelection
+	sweden.Countries = []string{"sweden"}
+
+	testCases := map[string]struct {
+		env          map[string]string
+		prepareMocks func(ctx context.Context, openvpnLooper *mock_openvpn.MockLooper,
+			fw *mock_firewall.MockConfigurator, logger *mock_logging.MockLogger)
+		outcome  string
+		err      error
+		settings func() configuration.Settings
+	}{
+		"settings left unchanged": {
+			outcome: "settings left unchanged",
+		},
+		"server filters reloaded": {
+			env: map[string]string{"COUNTRY": "sweden"},
+			prepareMocks: func(ctx context.Context, openvpnLooper *mock_openvpn.MockLooper,
+				fw *mock_firewall.MockConfigurator, logger *mock_logging.MockLogger) {
+				openvpnLooper.EXPECT().SetServerSelection(constants.Mullvad, sweden).
+					Return("switching server", nil)
+			},
+			outcome: "reloaded server filters",
+			settings: func() configuration.Settings {
+				settings := initial
+				settings.OpenVPN.Provider.ServerSelection = sweden
+				return settings
+			},
+		},
+		"server filters failing": {
+			env: map[string]string{"COUNTRY": "sweden"},
+			prepareMocks: func(ctx context.Context, openvpnLooper *mock_openvpn.MockLooper,
+				fw *mock_firewall.MockConfigurator, logger *mock_logging.MockLogger) {
+				openvpnLooper.EXPECT().SetServerSelection(constants.Mullvad, sweden).
+					Return("", errTest)
+			},
+			err: errTest,
+		},
+		"input ports reloaded keeping forwarded port": {
+			env: map[string]string{
+				"FIREWALL_VPN_INPUT_PORTS": "2000",
+				"FIREWALL_INPUT_PORTS":     "4000",
+			},
+			prepareMocks: func(ctx context.Context, openvpnLooper *mock_openvpn.MockLooper,
+				fw *mock_firewall.MockConfigurator, logger *mock_logging.MockLogger) {
+				openvpnLooper.EXPECT().GetPortForwardStatus().Return(models.PortForwardStatus{
+					Ports: []models.ForwardedPort{{Port: 3000}},
+				})
+				logger.EXPECT().Info("keeping port %d allowed since it is forwarded", uint16(3000))
+				gomock.InOrder(
+					fw.EXPECT().RemoveAllowedPort(ctx, uint16(1000)).Return(nil),
+					fw.EXPECT().GetVPNInterface().Return("tun1"),
+					fw.EXPECT().SetAllowedPort(ctx, uint16(2000), "tun1").Return(nil),
+					fw.EXPECT().SetAllowedPort(ctx, uint16(4000), "eth0").Return(nil),
+				)
+			},
+			outcome: "reloaded firewall input ports",
+			settings: func() configuration.Settings {
+				settings := initial
+				settings.Firewall.VPNInputPorts = []uint16{2000}
+				settings.Firewall.InputPorts = []uint16{4000}
+				return settings
+			},
+		},
+		"input ports failing": {
+			env: map[string]string{"FIREWALL_VPN_INPUT_PORTS": "1000"},
+			prepareMocks: func(ctx context.Context, openvpnLooper *mock_openvpn.MockLooper,
+				fw *mock_firewall.MockConfigurator, logger *mock_logging.MockLogger) {
+				openvpnLooper.EXPECT().GetPortForwardStatus().Return(models.PortForwardStatus{})
+				fw.EXPECT().RemoveAllowedPort(ctx, uint16(3000)).Return(errTest)
+			},
+			err: errTest,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+
+			for key, value := range testCase.env {
+				t.Setenv(key, value)
+			}
+
+			openvpnLooper := mock_openvpn.NewMockLooper(ctrl)
+			fw := mock_firewall.NewMockConfigurator(ctrl)
+			logger := mock_logging.NewMockLogger(ctrl)
+			if testCase.prepareMocks != nil {
+				testCase.prepareMocks(ctx, openvpnLooper, fw, logger)
+			}
+
+			r := &reloader{
+				settings:         initial,
+				defaultInterface: "eth0",
+				openvpnLooper:    openvpnLooper,
+				fw:               fw,
+				logger:           logger,
+			}
+
+			outcome, err := r.Reload(ctx)
+
+			assert.ErrorIs(t, err, testCase.err)
+			assert.Equal(t, testCase.outcome, outcome)
+			expectedSettings := initial
+			if testCase.settings != nil {
+				expectedSettings = testCase.settings()
+			}
+			assert.Equal(t, expectedSettings, r.GetSettings())
+		})
+	}
+}
@@ -73,5 +73,17 @@ func (r *routing) TearDown() error {
 		return fmt.Errorf("%s: %w", ErrSetup, err)
 	}
 
+	if err := r.SetLANPortsRoute(false); err != nil {
+		return fmt.Errorf("%s: %w", ErrTeardown, err)
+	}
+
+	if err := r.SetBypassSourcesRoute(false); err != nil {
+		return fmt.Errorf("%s: %w", ErrTeardown, err)
+	}
+
+	if err := r.SetMulticastRoute(false); err != nil {
+		return fmt.Errorf("%s: %w", ErrTeardown, err)
+	}
+
 	return nil
 }
@@ -0,0 +1,86 @@
+package routing
+
+import (
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/vishvananda/netlink"
+)
+
+// lanPortsPriority is the priority of the rule routing the marked LAN
+// ports connections, before the rule for the default IP address.
+const lanPortsPriority = priority - 1
+
+// SetLANPortsRoute routes the replies of the marked LAN ports connections
+// through the default interface if enabled is true, and removes the
+// corresponding rule otherwise.
+func (r *routing) SetLANPortsRoute(enabled bool) (err error) {
+	r.stateMutex.Lock()
+	defer r.stateMutex.Unlock()
+
+	if enabled == r.lanPortsRoute {
+		return nil
+	}
+
+	if enabled {
+		err = r.addMarkRule(constants.LANPortsMark, table, lanPortsPriority)
+	} else {
+		err = r.deleteMarkRule(constants.LANPortsMark, table, lanPortsPriority)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot set LAN ports route: %w", err)
+	}
+	r.lanPortsRoute = enabled
+	return nil
+}
+
+func (r *routing) addMarkRule(mark uint32, table, priority int) error {
+	if r.debug {
+		fmt.Printf("ip rule add fwmark %d lookup %d pref %d\n",
+			mark, table, priority)
+	}
+
+	rule := netlink.NewRule()
+	rule.Mark = int(mark)
+	rule.Priority = priority
+	rule.Table = table
+
+	rules, err := netlink.RuleList(netlink.FAMILY_ALL)
+	if err != nil {
+		return fmt.Errorf("cannot add ip rule: %w", err)
+	}
+	for _, existingRule := range rules {
+		if existingRule.Mark == rule.Mark &&
+			existingRule.Priority == rule.Priority &&
+			existingRule.Table == rule.Table {
+			return nil // already exists
+		}
+	}
+
+	return netlink.RuleAdd(rule)
+}
+
+func (r *routing) deleteMarkRule(mark uint32, table, priority int) error {
+	if r.debug {
+		fmt.Printf("ip rule del fwmark %d lookup %d pref %d\n",
+			mark, table, priority)
+	}
+
+	rule := netlink.NewRule()
+	rule.Mark = int(mark)
+	rule.Priority = priority
+	rule.Table = table
+
+	rules, err := netlink.RuleList(netlink.FAMILY_ALL)
+	if err != nil {
+		return fmt.Errorf("cannot delete ip rule: %w", err)
+	}
+	for _, existingRule := range rules {
+		if existingRule.Mark == rule.Mark &&
+			existingRule.Priority == rule.Priority &&
+			existingRule.Table == rule.Table {
+			return netlink.RuleDel(rule)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/qdm12/gluetun/internal/routing (interfaces: Routing)
+
+// Package mock_routing is a generated GoMock package.
+package mock_routing
+
+import (
+	net "net"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	routing "github.com/qdm12/gluetun/internal/routing"
+)
+
+// MockRouting is a mock of Routing interface.
+type MockRouting struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoutingMockRecorder
+}
+
+// MockRoutingMockRecorder is the mock recorder for MockRouting.
+type MockRoutingMockRecorder struct {
+	mock *MockRouting
+}
+
+// NewMockRouting creates a new mock instance.
+func NewMockRouting(ctrl *gomock.Controller) *MockRouting {
+	mock := &MockRouting{ctrl: ctrl}
+	mock.recorder = &MockRoutingMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRouting) EXPECT() *MockRoutingMockRecorder {
+	return m.recorder
+}
+
+// DefaultIP mocks base method.
+func (m *MockRouting) DefaultIP() (net.IP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DefaultIP")
+	ret0, _ := ret[0].(net.IP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DefaultIP indicates an expected call of DefaultIP.
+func (mr *MockRoutingMockRecorder) DefaultIP() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DefaultIP", reflect.TypeOf((*MockRouting)(nil).DefaultIP))
+}
+
+// DefaultRoute mocks base method.
+func (m *MockRouting) DefaultRoute() (string, net.IP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DefaultRoute")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(net.IP)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DefaultRoute indicates an expected call of DefaultRoute.
+func (mr *MockRoutingMockRecorder) DefaultRoute() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DefaultRoute", reflect.TypeOf((*MockRouting)(nil).DefaultRoute))
+}
+
+// IPv6Supported mocks base method.
+func (m *MockRouting) IPv6Supported() (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IPv6Supported")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IPv6Supported indicates an expected call of IPv6Supported.
+func (mr *MockRoutingMockRecorder) IPv6Supported() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IPv6Supported", reflect.TypeOf((*MockRouting)(nil).IPv6Supported))
+}
+
+// LocalNetworks mocks base method.
+func (m *MockRouting) LocalNetworks() ([]routing.LocalNetwork, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LocalNetworks")
+	ret0, _ := ret[0].([]routing.LocalNetwork)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LocalNetworks indicates an expected call of LocalNetworks.
+func (mr *MockRoutingMockRecorder) LocalNetworks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LocalNetworks", reflect.TypeOf((*MockRouting)(nil).LocalNetworks))
+}
+
+// RemoveVPNRoutes mocks base method.
+func (m *MockRouting) RemoveVPNRoutes(arg0 string, arg1 net.IP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveVPNRoutes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveVPNRoutes indicates an expected call of RemoveVPNRoutes.
+func (mr *MockRoutingMockRecorder) RemoveVPNRoutes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveVPNRoutes", reflect.TypeOf((*MockRouting)(nil).RemoveVPNRoutes), arg0, arg1)
+}
+
+// SetBypassSourcesRoute mocks base method.
+func (m *MockRouting) SetBypassSourcesRoute(arg0 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBypassSourcesRoute", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBypassSourcesRoute indicates an expected call of SetBypassSourcesRoute.
+func (mr *MockRoutingMockRecorder) SetBypassSourcesRoute(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBypassSourcesRoute", reflect.TypeOf((*MockRouting)(nil).SetBypassSourcesRoute), arg0)
+}
+
+// SetDebug mocks base method.
+func (m *MockRouting) SetDebug() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetDebug")
+}
+
+// SetDebug indicates an expected call of SetDebug.
+func (mr *MockRoutingMockRecorder) SetDebug() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDebug", reflect.TypeOf((*MockRouting)(nil).SetDebug))
+}
+
+// SetLANPortsRoute mocks base method.
+func (m *MockRouting) SetLANPortsRoute(arg0 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLANPortsRoute", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLANPortsRoute indicates an expected call of SetLANPortsRoute.
+func (mr *MockRoutingMockRecorder) SetLANPortsRoute(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLANPortsRoute", reflect.TypeOf((*MockRouting)(nil).SetLANPortsRoute), arg0)
+}
+
+// SetMulticastRoute mocks base method.
+func (m *MockRouting) SetMulticastRoute(arg0 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMulticastRoute", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMulticastRoute indicates an expected call of SetMulticastRoute.
+func (mr *MockRoutingMockRecorder) SetMulticastRoute(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMulticastRoute", reflect.TypeOf((*MockRouting)(nil).SetMulticastRoute), arg0)
+}
+
+// SetOutboundRoutes mocks base method.
+func (m *MockRouting) SetOutboundRoutes(arg0 []net.IPNet) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOutboundRoutes", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOutboundRoutes indicates an expected call of SetOutboundRoutes.
+func (mr *MockRoutingMockRecorder) SetOutboundRoutes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOutboundRoutes", reflect.TypeOf((*MockRouting)(nil).SetOutboundRoutes), arg0)
+}
+
+// SetVPNEndpointRoute mocks base method.
+func (m *MockRouting) SetVPNEndpointRoute(arg0 net.IP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVPNEndpointRoute", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetVPNEndpointRoute indicates an expected call of SetVPNEndpointRoute.
+func (mr *MockRoutingMockRecorder) SetVPNEndpointRoute(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVPNEndpointRoute", reflect.TypeOf((*MockRouting)(nil).SetVPNEndpointRoute), arg0)
+}
+
+// SetVPNRoutes mocks base method.
+func (m *MockRouting) SetVPNRoutes(arg0 string, arg1 net.IP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVPNRoutes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetVPNRoutes indicates an expected call of SetVPNRoutes.
+func (mr *MockRoutingMockRecorder) SetVPNRoutes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVPNRoutes", reflect.TypeOf((*MockRouting)(nil).SetVPNRoutes), arg0, arg1)
+}
+
+// SetVerbose mocks base method.
+func (m *MockRouting) SetVerbose(arg0 bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetVerbose", arg0)
+}
+
+// SetVerbose indicates an expected call of SetVerbose.
+func (mr *MockRoutingMockRecorder) SetVerbose(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVerbose", reflect.TypeOf((*MockRouting)(nil).SetVerbose), arg0)
+}
+
+// Setup mocks base method.
+func (m *MockRouting) Setup() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Setup")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Setup indicates an expected call of Setup.
+func (mr *MockRoutingMockRecorder) Setup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Setup", reflect.TypeOf((*MockRouting)(nil).Setup))
+}
+
+// TearDown mocks base method.
+func (m *MockRouting) TearDown() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TearDown")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TearDown indicates an expected call of TearDown.
+func (mr *MockRoutingMockRecorder) TearDown() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TearDown", reflect.TypeOf((*MockRouting)(nil).TearDown))
+}
+
+// VPNDestinationIP mocks base method.
+func (m *MockRouting) VPNDestinationIP() (net.IP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VPNDestinationIP")
+	ret0, _ := ret[0].(net.IP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VPNDestinationIP indicates an expected call of VPNDestinationIP.
+func (mr *MockRoutingMockRecorder) VPNDestinationIP() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VPNDestinationIP", reflect.TypeOf((*MockRouting)(nil).VPNDestinationIP))
+}
+
+// VPNLocalGatewayIP mocks base method.
+func (m *MockRouting) VPNLocalGatewayIP() (net.IP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VPNLocalGatewayIP")
+	ret0, _ := ret[0].(net.IP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VPNLocalGatewayIP indicates an expected call of VPNLocalGatewayIP.
+func (mr *MockRoutingMockRecorder) VPNLocalGatewayIP() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VPNLocalGatewayIP", reflect.TypeOf((*MockRouting)(nil).VPNLocalGatewayIP))
+}
@@ -0,0 +1,80 @@
+package routing
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/vishvananda/netlink"
+)
+
+// SetMulticastRoute routes the multicast DNS and LLMNR traffic through
+// the default interface if enabled is true, instead of the VPN, such
+// that it reaches the local network. It removes these routes otherwise.
+// Only the mDNS and LLMNR groups are routed, the other multicast traffic
+// still going through the VPN.
+func (r *routing) SetMulticastRoute(enabled bool) (err error) {
+	r.stateMutex.Lock()
+	defer r.stateMutex.Unlock()
+
+	if enabled == r.multicastRoute {
+		return nil
+	}
+
+	defaultInterface, _, err := r.DefaultRoute()
+	if err != nil {
+		return fmt.Errorf("cannot set multicast route: %w", err)
+	}
+
+	link, err := netlink.LinkByName(defaultInterface)
+	if err != nil {
+		return fmt.Errorf("cannot set multicast route: %w", err)
+	}
+
+	ipv6, err := r.IPv6Supported()
+	if err != nil {
+		return fmt.Errorf("cannot set multicast route: %w", err)
+	}
+
+	for _, route := range multicastDNSRoutes(link.Attrs().Index, ipv6) {
+		route := route
+		if enabled {
+			if r.debug {
+				fmt.Printf("ip route replace %s dev %s scope link\n", route.Dst, defaultInterface)
+			}
+			err = netlink.RouteReplace(&route)
+		} else {
+			if r.debug {
+				fmt.Printf("ip route delete %s dev %s scope link\n", route.Dst, defaultInterface)
+			}
+			err = netlink.RouteDel(&route)
+		}
+		if err != nil {
+			return fmt.Errorf("cannot set multicast route: %w", err)
+		}
+	}
+	r.multicastRoute = enabled
+	return nil
+}
+
+// multicastDNSRoutes returns the routes of the multicast DNS and LLMNR
+// groups through the link given, including the IPv6 groups if ipv6 is true.
+func multicastDNSRoutes(linkIndex int, ipv6 bool) (routes []netlink.Route) {
+	groups := constants.MulticastDNSGroups()
+	routes = make([]netlink.Route, 0, len(groups))
+	for _, group := range groups {
+		bits := 8 * net.IPv4len
+		if group.IP.To4() == nil {
+			if !ipv6 {
+				continue
+			}
+			bits = 8 * net.IPv6len
+		}
+		routes = append(routes, netlink.Route{
+			Dst:       &net.IPNet{IP: group.IP, Mask: net.CIDRMask(bits, bits)},
+			LinkIndex: linkIndex,
+			Scope:     netlink.SCOPE_LINK,
+		})
+	}
+	return routes
+}
@@ -0,0 +1,50 @@
+package routing
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vishvananda/netlink"
+)
+
+func Test_multicastDNSRoutes(t *testing.T) {
+	t.Parallel()
+
+	mDNSv4 := &net.IPNet{IP: net.IP{224, 0, 0, 251}, Mask: net.CIDRMask(32, 32)}
+	llmnrv4 := &net.IPNet{IP: net.IP{224, 0, 0, 252}, Mask: net.CIDRMask(32, 32)}
+	mDNSv6 := &net.IPNet{IP: net.ParseIP("ff02::fb"), Mask: net.CIDRMask(128, 128)}
+	llmnrv6 := &net.IPNet{IP: net.ParseIP("ff02::1:3"), Mask: net.CIDRMask(128, 128)}
+
+	testCases := map[string]struct {
+		ipv6   bool
+		routes []netlink.Route
+	}{
+		"IPv4 only": {
+			routes: []netlink.Route{
+				{Dst: mDNSv4, LinkIndex: 2, Scope: netlink.SCOPE_LINK},
+				{Dst: llmnrv4, LinkIndex: 2, Scope: netlink.SCOPE_LINK},
+			},
+		},
+		"IPv4 and IPv6": {
+			ipv6: true,
+			routes: []netlink.Route{
+				{Dst: mDNSv4, LinkIndex: 2, Scope: netlink.SCOPE_LINK},
+				{Dst: mDNSv6, LinkIndex: 2, Scope: netlink.SCOPE_LINK},
+				{Dst: llmnrv4, LinkIndex: 2, Scope: netlink.SCOPE_LINK},
+				{Dst: llmnrv6, LinkIndex: 2, Scope: netlink.SCOPE_LINK},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			routes := multicastDNSRoutes(2, testCase.ipv6)
+
+			assert.Equal(t, testCase.routes, routes)
+		})
+	}
+}
@@ -60,7 +60,8 @@ func (r *routing) DefaultIP() (ip net.IP, err error) {
 		return nil, fmt.Errorf("cannot find default link name in %d routes", len(routes))
 	}
 
-	return r.assignedIP(defaultLinkName)
+	const ipv4 = true
+	return r.assignedIP(defaultLinkName, ipv4)
 }
 
 func (r *routing) LocalSubnet() (defaultSubnet net.IPNet, err error) {
@@ -117,7 +118,9 @@ func (r *routing) LocalNetworks() (localNetworks []LocalNetwork, err error) {
 		return localNetworks, fmt.Errorf("cannot find any local interfaces")
 	}
 
-	routes, err := netlink.RouteList(nil, netlink.FAMILY_V4)
+	// IPv6 local networks are listed as well, so the firewall
+	// accepts IPv6 traffic to them if IPv6 is enabled.
+	routes, err := netlink.RouteList(nil, netlink.FAMILY_ALL)
 	if err != nil {
 		return localNetworks, fmt.Errorf("cannot list local routes: %w", err)
 	}
@@ -128,6 +131,7 @@ func (r *routing) LocalNetworks() (localNetworks []LocalNetwork, err error) {
 		} else if _, ok := localLinks[route.LinkIndex]; !ok {
 			continue
 		}
+		ipv4 := route.Dst.IP.To4() != nil
 
 		var localNet LocalNetwork
 
@@ -143,8 +147,10 @@ func (r *routing) LocalNetworks() (localNetworks []LocalNetwork, err error) {
 
 		localNet.InterfaceName = link.Attrs().Name
 
-		ip, err := r.assignedIP(localNet.InterfaceName)
-		if err != nil {
+		ip, err := r.assignedIP(localNet.InterfaceName, ipv4)
+		if err != nil && !ipv4 {
+			continue // no IPv6 address assigned to the interface
+		} else if err != nil {
 			return localNetworks, fmt.Errorf("cannot get IP assigned to link: %w", err)
 		}
 
@@ -160,7 +166,9 @@ func (r *routing) LocalNetworks() (localNetworks []LocalNetwork, err error) {
 	return localNetworks, nil
 }
 
-func (r *routing) assignedIP(interfaceName string) (ip net.IP, err error) {
+// assignedIP returns the first IPv4 address assigned to the interface
+// if ipv4 is true, and the first IPv6 address otherwise.
+func (r *routing) assignedIP(interfaceName string, ipv4 bool) (ip net.IP, err error) {
 	iface, err := net.InterfaceByName(interfaceName)
 	if err != nil {
 		return nil, err
@@ -172,9 +180,14 @@ func (r *routing) assignedIP(interfaceName string) (ip net.IP, err error) {
 	for _, address := range addresses {
 		switch value := address.(type) {
 		case *net.IPAddr:
-			return value.IP, nil
+			ip = value.IP
 		case *net.IPNet:
-			return value.IP, nil
+			ip = value.IP
+		default:
+			continue
+		}
+		if (ip.To4() != nil) == ipv4 {
+			return ip, nil
 		}
 	}
 	return nil, fmt.Errorf("IP address not found in addresses of interface %s", interfaceName)
@@ -228,6 +241,16 @@ func (r *routing) VPNLocalGatewayIP() (ip net.IP, err error) {
 	return nil, fmt.Errorf("cannot find VPN local gateway IP address from ip routes")
 }
 
+// IPv6Supported returns true if IPv6 is enabled on the system, which
+// is not the case by default in Docker containers.
+func (r *routing) IPv6Supported() (supported bool, err error) {
+	routes, err := netlink.RouteList(nil, netlink.FAMILY_V6)
+	if err != nil {
+		return false, fmt.Errorf("cannot list IPv6 routes: %w", err)
+	}
+	return len(routes) > 0, nil
+}
+
 func IPIsPrivate(ip net.IP) bool {
 	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
 		return true
@@ -8,11 +8,19 @@ import (
 	"github.com/qdm12/golibs/logging"
 )
 
+//go:generate mockgen -destination=mock_$GOPACKAGE/$GOFILE . Routing
+
 type Routing interface {
 	// Mutations
 	Setup() (err error)
 	TearDown() error
 	SetOutboundRoutes(outboundSubnets []net.IPNet) error
+	SetVPNRoutes(vpnInterface string, endpoint net.IP) error
+	SetVPNEndpointRoute(endpoint net.IP) error
+	RemoveVPNRoutes(vpnInterface string, endpoint net.IP) error
+	SetLANPortsRoute(enabled bool) (err error)
+	SetBypassSourcesRoute(enabled bool) (err error)
+	SetMulticastRoute(enabled bool) (err error)
 
 	// Read only
 	DefaultRoute() (defaultInterface string, defaultGateway net.IP, err error)
@@ -20,6 +28,7 @@ type Routing interface {
 	DefaultIP() (defaultIP net.IP, err error)
 	VPNDestinationIP() (ip net.IP, err error)
 	VPNLocalGatewayIP() (ip net.IP, err error)
+	IPv6Supported() (supported bool, err error)
 
 	// Internal state
 	SetVerbose(verbose bool)
@@ -27,11 +36,14 @@ type Routing interface {
 }
 
 type routing struct {
-	logger          logging.Logger
-	verbose         bool
-	debug           bool
-	outboundSubnets []net.IPNet
-	stateMutex      sync.RWMutex
+	logger             logging.Logger
+	verbose            bool
+	debug              bool
+	outboundSubnets    []net.IPNet
+	lanPortsRoute      bool
+	bypassSourcesRoute bool
+	multicastRoute     bool
+	stateMutex         sync.RWMutex
 }
 
 // NewRouting creates a new routing instance.
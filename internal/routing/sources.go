@@ -0,0 +1,34 @@
+package routing
+
+import (
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+// bypassSourcesPriority is the priority of the rule routing the marked
+// packets from the bypass sources, before the LAN ports rule.
+const bypassSourcesPriority = lanPortsPriority - 1
+
+// SetBypassSourcesRoute routes the marked packets forwarded from the
+// bypass sources through the default interface if enabled is true, and
+// removes the corresponding rule otherwise.
+func (r *routing) SetBypassSourcesRoute(enabled bool) (err error) {
+	r.stateMutex.Lock()
+	defer r.stateMutex.Unlock()
+
+	if enabled == r.bypassSourcesRoute {
+		return nil
+	}
+
+	if enabled {
+		err = r.addMarkRule(constants.BypassSourcesMark, table, bypassSourcesPriority)
+	} else {
+		err = r.deleteMarkRule(constants.BypassSourcesMark, table, bypassSourcesPriority)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot set bypass sources route: %w", err)
+	}
+	r.bypassSourcesRoute = enabled
+	return nil
+}
@@ -4,16 +4,12 @@ import (
 	"net"
 )
 
+// findSubnetsToAdd returns the new subnets not in the old subnets,
+// without duplicates.
 func findSubnetsToAdd(oldSubnets, newSubnets []net.IPNet) (subnetsToAdd []net.IPNet) {
 	for _, newSubnet := range newSubnets {
-		found := false
-		for _, oldSubnet := range oldSubnets {
-			if subnetsAreEqual(oldSubnet, newSubnet) {
-				found = true
-				break
-			}
-		}
-		if !found {
+		if !subnetsContain(oldSubnets, newSubnet) &&
+			!subnetsContain(subnetsToAdd, newSubnet) {
 			subnetsToAdd = append(subnetsToAdd, newSubnet)
 		}
 	}
@@ -36,6 +32,15 @@ func findSubnetsToRemove(oldSubnets, newSubnets []net.IPNet) (subnetsToRemove []
 	return subnetsToRemove
 }
 
+func subnetsContain(subnets []net.IPNet, subnet net.IPNet) bool {
+	for _, s := range subnets {
+		if subnetsAreEqual(s, subnet) {
+			return true
+		}
+	}
+	return false
+}
+
 func subnetsAreEqual(a, b net.IPNet) bool {
 	return a.IP.Equal(b.IP) && a.Mask.String() == b.Mask.String()
 }
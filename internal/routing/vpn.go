@@ -0,0 +1,72 @@
+package routing
+
+import (
+	"fmt"
+	"net"
+)
+
+// vpnDestinations are the two halves of the IPv4 space, which take
+// precedence over the default route without replacing it.
+func vpnDestinations() []net.IPNet {
+	return []net.IPNet{
+		{IP: net.IPv4(0, 0, 0, 0), Mask: net.IPv4Mask(128, 0, 0, 0)},
+		{IP: net.IPv4(128, 0, 0, 0), Mask: net.IPv4Mask(128, 0, 0, 0)},
+	}
+}
+
+// SetVPNRoutes routes all traffic through the VPN interface given, except
+// for the traffic to the VPN endpoint which goes through the default gateway.
+// This is only needed for VPN clients not managing routes themselves.
+func (r *routing) SetVPNRoutes(vpnInterface string, endpoint net.IP) error {
+	if err := r.SetVPNEndpointRoute(endpoint); err != nil {
+		return fmt.Errorf("cannot set VPN routes: %w", err)
+	}
+
+	const mainTable = 0
+	for _, destination := range vpnDestinations() {
+		if err := r.addRouteVia(destination, nil, vpnInterface, mainTable); err != nil {
+			return fmt.Errorf("cannot set VPN routes: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// SetVPNEndpointRoute routes the traffic to the VPN endpoint through the
+// default gateway, so the VPN connection does not go through another tunnel.
+func (r *routing) SetVPNEndpointRoute(endpoint net.IP) error {
+	defaultInterface, defaultGateway, err := r.DefaultRoute()
+	if err != nil {
+		return fmt.Errorf("cannot set VPN endpoint route: %w", err)
+	}
+
+	const mainTable = 0
+	endpointDestination := net.IPNet{IP: endpoint, Mask: net.CIDRMask(32, 32)} //nolint:gomnd
+	if err := r.addRouteVia(endpointDestination, defaultGateway, defaultInterface, mainTable); err != nil {
+		return fmt.Errorf("cannot set VPN endpoint route: %w", err)
+	}
+	return nil
+}
+
+// RemoveVPNRoutes removes the routes set by SetVPNRoutes.
+func (r *routing) RemoveVPNRoutes(vpnInterface string, endpoint net.IP) error {
+	defaultInterface, defaultGateway, err := r.DefaultRoute()
+	if err != nil {
+		return fmt.Errorf("cannot remove VPN routes: %w", err)
+	}
+
+	const mainTable = 0
+	for _, destination := range vpnDestinations() {
+		// the VPN interface may already be deleted, along with its routes
+		if err := r.deleteRouteVia(destination, nil, vpnInterface, mainTable); err != nil {
+			r.logger.Debug(err)
+		}
+	}
+
+	endpointDestination := net.IPNet{IP: endpoint, Mask: net.CIDRMask(32, 32)} //nolint:gomnd
+	if err := r.deleteRouteVia(endpointDestination, defaultGateway, defaultInterface, mainTable); err != nil {
+		return fmt.Errorf("cannot remove VPN routes: %w", err)
+	}
+
+	return nil
+}
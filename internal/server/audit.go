@@ -0,0 +1,212 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qdm12/golibs/logging"
+)
+
+// AuditSettings contains settings for the audit log of
+// the requests changing state.
+type AuditSettings struct {
+	// Enabled is true to record the requests changing state.
+	Enabled bool
+	// Filepath is the file to append the audit entries to as JSON
+	// lines, and is empty to log the audit entries instead.
+	Filepath string
+}
+
+// maxAuditValueLength is the maximum length of the values
+// and result recorded in an audit entry.
+const maxAuditValueLength = 1024
+
+func withAuditMiddleware(childHandler http.Handler, filepath string,
+	logger logging.Logger) *auditMiddleware {
+	return &auditMiddleware{
+		childHandler: childHandler,
+		filepath:     filepath,
+		logger:       logger,
+		timeNow:      time.Now,
+	}
+}
+
+type auditMiddleware struct {
+	childHandler http.Handler
+	filepath     string
+	logger       logging.Logger
+	timeNow      func() time.Time
+	fileMu       sync.Mutex
+}
+
+// auditEntry records a request changing state. Old is the value
+// served on GET for the same route before a PUT request, and New
+// is the value of the request body.
+type auditEntry struct {
+	Time   time.Time       `json:"time"`
+	Actor  string          `json:"actor"`
+	Client string          `json:"client"`
+	Method string          `json:"method"`
+	Route  string          `json:"route"`
+	Old    json.RawMessage `json:"old,omitempty"`
+	New    json.RawMessage `json:"new,omitempty"`
+	Status int             `json:"status"`
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+func (m *auditMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route := strings.TrimSuffix(r.RequestURI, "/")
+	if !changesState(r.Method, route) {
+		m.childHandler.ServeHTTP(w, r)
+		return
+	}
+
+	entry := auditEntry{
+		Time:   m.timeNow(),
+		Actor:  actorFromContext(r.Context()),
+		Client: r.RemoteAddr,
+		Method: r.Method,
+		Route:  route,
+	}
+
+	if r.Method == http.MethodPut && !changesState(http.MethodGet, route) {
+		entry.Old = m.currentValue(r)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	entry.New = auditValue(body)
+
+	recorder := &auditResponseWriter{ResponseWriter: w}
+	m.childHandler.ServeHTTP(recorder, r)
+	entry.Status = recorder.statusCode
+	if entry.Status == 0 {
+		entry.Status = http.StatusOK
+	}
+	entry.Result = auditValue(recorder.body.Bytes())
+
+	m.record(entry)
+}
+
+// currentValue returns the value served for a GET request on the
+// route of the request given, or nil if it cannot be obtained.
+func (m *auditMiddleware) currentValue(r *http.Request) (value json.RawMessage) {
+	getRequest := r.Clone(r.Context())
+	getRequest.Method = http.MethodGet
+	getRequest.Body = http.NoBody
+	getRequest.ContentLength = 0
+	recorder := &auditResponseWriter{ResponseWriter: newDiscardResponseWriter()}
+	m.childHandler.ServeHTTP(recorder, getRequest)
+	if recorder.statusCode != 0 && recorder.statusCode != http.StatusOK {
+		return nil
+	}
+	return auditValue(recorder.body.Bytes())
+}
+
+func (m *auditMiddleware) record(entry auditEntry) {
+	if m.filepath == "" {
+		m.logger.Info("audit: %s %s by %s from %s: %s -> %s: %d %s",
+			entry.Method, entry.Route, entry.Actor, entry.Client,
+			orNone(entry.Old), orNone(entry.New), entry.Status, orNone(entry.Result))
+		return
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		m.logger.Warn("cannot encode audit entry: %s", err)
+		return
+	}
+	b = append(b, '\n')
+
+	m.fileMu.Lock()
+	defer m.fileMu.Unlock()
+	file, err := os.OpenFile(m.filepath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		m.logger.Warn("cannot open audit file: %s", err)
+		return
+	}
+	if _, err := file.Write(b); err != nil {
+		_ = file.Close()
+		m.logger.Warn("cannot write audit entry: %s", err)
+		return
+	}
+	if err := file.Close(); err != nil {
+		m.logger.Warn("cannot close audit file: %s", err)
+	}
+}
+
+// auditValue returns the compacted JSON of the value given, or the
+// value as a JSON string if it is not JSON, truncated to
+// maxAuditValueLength bytes. It returns nil for an empty value.
+func auditValue(b []byte) (value json.RawMessage) {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return nil
+	}
+
+	compacted := new(bytes.Buffer)
+	if len(b) <= maxAuditValueLength && json.Compact(compacted, b) == nil {
+		return compacted.Bytes()
+	}
+
+	s := string(b)
+	if len(s) > maxAuditValueLength {
+		s = s[:maxAuditValueLength] + "..."
+	}
+	value, _ = json.Marshal(s)
+	return value
+}
+
+func orNone(value json.RawMessage) string {
+	if len(value) == 0 {
+		return "none"
+	}
+	return string(value)
+}
+
+// auditResponseWriter records the status code and the beginning
+// of the body written to the response writer it wraps.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (w *auditResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *auditResponseWriter) Write(b []byte) (n int, err error) {
+	if left := maxAuditValueLength + 1 - w.body.Len(); left > 0 {
+		if left > len(b) {
+			left = len(b)
+		}
+		w.body.Write(b[:left])
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// discardResponseWriter is a response writer discarding
+// everything written to it.
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func newDiscardResponseWriter() *discardResponseWriter {
+	return &discardResponseWriter{header: make(http.Header)}
+}
+
+func (w *discardResponseWriter) Header() http.Header         { return w.header }
+func (w *discardResponseWriter) WriteHeader(int)             {}
+func (w *discardResponseWriter) Write(b []byte) (int, error) { return len(b), nil }
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_auditMiddleware(t *testing.T) {
+	t.Parallel()
+
+	status := "running"
+	child := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"status":"` + status + `"}` + "\n"))
+		case http.MethodPut:
+			var data statusWrapper
+			_ = json.NewDecoder(r.Body).Decode(&data)
+			status = data.Status
+			_, _ = w.Write([]byte(`{"outcome":"` + status + `"}` + "\n"))
+		}
+	})
+
+	auditFile := filepath.Join(t.TempDir(), "audit.log")
+	middleware := withAuditMiddleware(child, auditFile, nil)
+	middleware.timeNow = func() time.Time { return time.Unix(0, 0).UTC() }
+
+	request := httptest.NewRequest(http.MethodGet, "/v1/openvpn/status", nil)
+	middleware.ServeHTTP(httptest.NewRecorder(), request)
+
+	request = httptest.NewRequest(http.MethodPut, "/v1/openvpn/status/",
+		strings.NewReader(`{ "status": "stopped" }`))
+	request.RemoteAddr = "1.2.3.4:5678"
+	request = request.WithContext(withActor(request.Context(), "API key"))
+	recorder := httptest.NewRecorder()
+	middleware.ServeHTTP(recorder, request)
+	assert.Equal(t, `{"outcome":"stopped"}`+"\n", recorder.Body.String())
+
+	b, err := os.ReadFile(auditFile)
+	require.NoError(t, err)
+	expected := `{"time":"1970-01-01T00:00:00Z","actor":"API key","client":"1.2.3.4:5678",` +
+		`"method":"PUT","route":"/v1/openvpn/status","old":{"status":"running"},` +
+		`"new":{"status":"stopped"},"status":200,"result":{"outcome":"stopped"}}` + "\n"
+	assert.Equal(t, expected, string(b))
+}
+
+func Test_auditValue(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		value    string
+		expected string
+	}{
+		"empty": {},
+		"json": {
+			value:    "{ \"a\": 1 }\n",
+			expected: `{"a":1}`,
+		},
+		"text": {
+			value:    "invalid status\n",
+			expected: `"invalid status"`,
+		},
+		"truncated": {
+			value:    strings.Repeat("a", maxAuditValueLength+1),
+			expected: `"` + strings.Repeat("a", maxAuditValueLength) + `..."`,
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			value := auditValue([]byte(testCase.value))
+			assert.Equal(t, testCase.expected, string(value))
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/qdm12/golibs/logging"
+)
+
+// AuthSettings contains the credentials to access the control server.
+// If no credential is set, all the endpoints are accessible without
+// authentication, except for the firewall endpoints which are disabled.
+type AuthSettings struct {
+	// APIKey grants access to all the endpoints.
+	APIKey string
+	// ReadOnlyAPIKey grants access only to the endpoints
+	// not changing any state.
+	ReadOnlyAPIKey string
+	// User and Password are the HTTP basic authentication
+	// credentials granting access to all the endpoints.
+	User     string
+	Password string
+}
+
+func (s *AuthSettings) enabled() bool {
+	return s.APIKey != "" || s.ReadOnlyAPIKey != "" || s.User != ""
+}
+
+func (s *AuthSettings) controlEnabled() bool {
+	return s.APIKey != "" || s.User != ""
+}
+
+// role is the role required by an endpoint or granted by credentials,
+// where a higher role includes the lower roles.
+type role uint8
+
+const (
+	roleNone role = iota
+	roleReadOnly
+	roleControl
+)
+
+const (
+	// apiKeyHeader is the HTTP header containing the API key, which can
+	// also be given as a bearer token in the Authorization header.
+	apiKeyHeader = "X-API-Key"
+	bearerPrefix = "Bearer "
+)
+
+func withAuthMiddleware(childHandler http.Handler, settings AuthSettings,
+	logger logging.Logger) *authMiddleware {
+	return &authMiddleware{
+		childHandler: childHandler,
+		settings:     settings,
+		logger:       logger,
+	}
+}
+
+type authMiddleware struct {
+	childHandler http.Handler
+	settings     AuthSettings
+	logger       logging.Logger
+}
+
+func (m *authMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	uri := strings.TrimSuffix(r.RequestURI, "/")
+	if isFirewallRoute(uri) && !m.settings.controlEnabled() {
+		http.Error(w, "firewall endpoints require an API key or a user to be set", http.StatusForbidden)
+		return
+	}
+
+	if !m.settings.enabled() {
+		m.childHandler.ServeHTTP(w, r)
+		return
+	}
+
+	granted, actor := m.settings.grantedRole(r)
+	switch {
+	case granted == roleNone:
+		m.logger.Warn("unauthorized request %s %s from %s", r.Method, uri, r.RemoteAddr)
+		if m.settings.User != "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="gluetun"`)
+		}
+		http.Error(w, "", http.StatusUnauthorized)
+	case granted < requiredRole(r.Method, uri):
+		http.Error(w, "read only credentials cannot be used for "+r.Method+" "+uri, http.StatusForbidden)
+	default:
+		m.childHandler.ServeHTTP(w, r.WithContext(withActor(r.Context(), actor)))
+	}
+}
+
+// grantedRole returns the role granted by the credentials of the
+// request, and the actor identifying the credentials used.
+func (s *AuthSettings) grantedRole(r *http.Request) (granted role, actor string) {
+	if user, password, ok := r.BasicAuth(); ok {
+		// both are compared to not leak which one is wrong with timing
+		userMatch := secretsEqual(user, s.User)
+		passwordMatch := secretsEqual(password, s.Password)
+		if s.User != "" && userMatch && passwordMatch {
+			return roleControl, "user " + user
+		}
+		return roleNone, ""
+	}
+
+	apiKey := r.Header.Get(apiKeyHeader)
+	if authorization := r.Header.Get("Authorization"); apiKey == "" &&
+		strings.HasPrefix(authorization, bearerPrefix) {
+		apiKey = strings.TrimPrefix(authorization, bearerPrefix)
+	}
+	switch {
+	case apiKey == "":
+		return roleNone, ""
+	case s.APIKey != "" && secretsEqual(apiKey, s.APIKey):
+		return roleControl, "API key"
+	case s.ReadOnlyAPIKey != "" && secretsEqual(apiKey, s.ReadOnlyAPIKey):
+		return roleReadOnly, "read only API key"
+	default:
+		return roleNone, ""
+	}
+}
+
+type actorKey struct{}
+
+// withActor returns a copy of the context with the actor
+// identifying the credentials of the request.
+func withActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// actorFromContext returns the actor identifying the credentials of
+// the request, which is anonymous if authentication is disabled.
+func actorFromContext(ctx context.Context) (actor string) {
+	actor, ok := ctx.Value(actorKey{}).(string)
+	if !ok {
+		return "anonymous"
+	}
+	return actor
+}
+
+func secretsEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+func isFirewallRoute(uri string) bool {
+	return uri == "/v1/firewall" || strings.HasPrefix(uri, "/v1/firewall/")
+}
+
+// requiredRole returns the role required for the method and URI.
+// The firewall endpoints and the endpoints changing state require
+// the control role, and the other endpoints the read only role.
+func requiredRole(method, uri string) role {
+	if isFirewallRoute(uri) || changesState(method, uri) {
+		return roleControl
+	}
+	return roleReadOnly
+}
+
+// changesState returns true if the request with the method
+// and URI given changes the state of the program.
+func changesState(method, uri string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead:
+	default:
+		return true
+	}
+
+	switch uri {
+	case "/openvpn/actions/restart", "/unbound/actions/restart", "/updater/restart":
+		// unversioned API endpoints changing state with the GET method
+		return true
+	default:
+		return false
+	}
+}
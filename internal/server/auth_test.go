@@ -0,0 +1,151 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_authMiddleware(t *testing.T) {
+	t.Parallel()
+
+	settings := AuthSettings{
+		APIKey:         "control",
+		ReadOnlyAPIKey: "readonly",
+		User:           "admin",
+		Password:       "password",
+	}
+
+	testCases := map[string]struct {
+		settings    AuthSettings
+		method      string
+		uri         string
+		setHeaders  func(r *http.Request)
+		warnLogged  bool
+		statusCode  int
+		childCalled bool
+	}{
+		"no credentials set": {
+			method:      http.MethodPut,
+			uri:         "/v1/openvpn/status",
+			statusCode:  http.StatusOK,
+			childCalled: true,
+		},
+		"firewall disabled without control credentials": {
+			settings:   AuthSettings{ReadOnlyAPIKey: "readonly"},
+			method:     http.MethodGet,
+			uri:        "/v1/firewall/status",
+			statusCode: http.StatusForbidden,
+		},
+		"missing credentials": {
+			settings:   settings,
+			method:     http.MethodGet,
+			uri:        "/v1/version",
+			warnLogged: true,
+			statusCode: http.StatusUnauthorized,
+		},
+		"wrong API key": {
+			settings: settings,
+			method:   http.MethodGet,
+			uri:      "/v1/version",
+			setHeaders: func(r *http.Request) {
+				r.Header.Set("X-API-Key", "wrong")
+			},
+			warnLogged: true,
+			statusCode: http.StatusUnauthorized,
+		},
+		"read only API key for read only route": {
+			settings: settings,
+			method:   http.MethodGet,
+			uri:      "/v1/openvpn/status",
+			setHeaders: func(r *http.Request) {
+				r.Header.Set("X-API-Key", "readonly")
+			},
+			statusCode:  http.StatusOK,
+			childCalled: true,
+		},
+		"read only API key for control route": {
+			settings: settings,
+			method:   http.MethodPut,
+			uri:      "/v1/openvpn/status",
+			setHeaders: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer readonly")
+			},
+			statusCode: http.StatusForbidden,
+		},
+		"read only API key for unversioned restart": {
+			settings: settings,
+			method:   http.MethodGet,
+			uri:      "/openvpn/actions/restart",
+			setHeaders: func(r *http.Request) {
+				r.Header.Set("X-API-Key", "readonly")
+			},
+			statusCode: http.StatusForbidden,
+		},
+		"control API key as bearer token": {
+			settings: settings,
+			method:   http.MethodGet,
+			uri:      "/v1/firewall/rules",
+			setHeaders: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer control")
+			},
+			statusCode:  http.StatusOK,
+			childCalled: true,
+		},
+		"basic authentication": {
+			settings: settings,
+			method:   http.MethodPut,
+			uri:      "/v1/firewall/status/",
+			setHeaders: func(r *http.Request) {
+				r.SetBasicAuth("admin", "password")
+			},
+			statusCode:  http.StatusOK,
+			childCalled: true,
+		},
+		"wrong basic authentication password": {
+			settings: settings,
+			method:   http.MethodGet,
+			uri:      "/v1/version",
+			setHeaders: func(r *http.Request) {
+				r.SetBasicAuth("admin", "control")
+			},
+			warnLogged: true,
+			statusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			logger := mock_logging.NewMockLogger(ctrl)
+			if testCase.warnLogged {
+				logger.EXPECT().Warn("unauthorized request %s %s from %s",
+					testCase.method, gomock.Any(), gomock.Any())
+			}
+
+			childCalled := false
+			child := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				childCalled = true
+			})
+			middleware := withAuthMiddleware(child, testCase.settings, logger)
+
+			request := httptest.NewRequest(testCase.method, testCase.uri, nil)
+			if testCase.setHeaders != nil {
+				testCase.setHeaders(request)
+			}
+			recorder := httptest.NewRecorder()
+
+			middleware.ServeHTTP(recorder, request)
+
+			assert.Equal(t, testCase.statusCode, recorder.Code)
+			assert.Equal(t, testCase.childCalled, childCalled)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+func withCORSMiddleware(childHandler http.Handler, origins []string) *corsMiddleware {
+	allowed := make(map[string]struct{}, len(origins))
+	allowAll := false
+	for _, origin := range origins {
+		if origin == "*" {
+			allowAll = true
+			continue
+		}
+		allowed[strings.ToLower(strings.TrimSuffix(origin, "/"))] = struct{}{}
+	}
+	return &corsMiddleware{
+		childHandler: childHandler,
+		allowed:      allowed,
+		allowAll:     allowAll,
+	}
+}
+
+// corsMiddleware sets the CORS headers for the allowed origins, and
+// answers the preflight requests, which carry no credentials, before
+// the authentication middleware.
+type corsMiddleware struct {
+	childHandler http.Handler
+	allowed      map[string]struct{}
+	allowAll     bool
+}
+
+func (m *corsMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin == "" || !m.originAllowed(origin) {
+		m.childHandler.ServeHTTP(w, r)
+		return
+	}
+
+	header := w.Header()
+	header.Add("Vary", "Origin")
+	header.Set("Access-Control-Allow-Origin", origin)
+	header.Set("Access-Control-Allow-Credentials", "true")
+
+	preflight := r.Method == http.MethodOptions &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+	if !preflight {
+		m.childHandler.ServeHTTP(w, r)
+		return
+	}
+
+	header.Set("Access-Control-Allow-Methods", "GET, PUT, POST, DELETE")
+	header.Set("Access-Control-Allow-Headers", "Authorization, Content-Type, "+apiKeyHeader)
+	const maxAgeSeconds = "600"
+	header.Set("Access-Control-Max-Age", maxAgeSeconds)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *corsMiddleware) originAllowed(origin string) bool {
+	if m.allowAll {
+		return true
+	}
+	_, ok := m.allowed[strings.ToLower(origin)]
+	return ok
+}
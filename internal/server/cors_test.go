@@ -0,0 +1,78 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_corsMiddleware(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		origins     []string
+		method      string
+		headers     map[string]string
+		statusCode  int
+		allowOrigin string
+		childCalled bool
+	}{
+		"no origin": {
+			origins:     []string{"https://ui.example.com"},
+			method:      http.MethodGet,
+			statusCode:  http.StatusOK,
+			childCalled: true,
+		},
+		"origin not allowed": {
+			origins:     []string{"https://ui.example.com"},
+			method:      http.MethodGet,
+			headers:     map[string]string{"Origin": "https://evil.com"},
+			statusCode:  http.StatusOK,
+			childCalled: true,
+		},
+		"origin allowed": {
+			origins:     []string{"https://UI.example.com/"},
+			method:      http.MethodGet,
+			headers:     map[string]string{"Origin": "https://ui.example.com"},
+			statusCode:  http.StatusOK,
+			allowOrigin: "https://ui.example.com",
+			childCalled: true,
+		},
+		"preflight": {
+			origins: []string{"*"},
+			method:  http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://ui.example.com",
+				"Access-Control-Request-Method": http.MethodPut,
+			},
+			statusCode:  http.StatusNoContent,
+			allowOrigin: "https://ui.example.com",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			childCalled := false
+			child := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				childCalled = true
+			})
+			middleware := withCORSMiddleware(child, testCase.origins)
+
+			request := httptest.NewRequest(testCase.method, "/v1/version", nil)
+			for key, value := range testCase.headers {
+				request.Header.Set(key, value)
+			}
+			recorder := httptest.NewRecorder()
+			middleware.ServeHTTP(recorder, request)
+
+			assert.Equal(t, testCase.statusCode, recorder.Code)
+			assert.Equal(t, testCase.allowOrigin, recorder.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, testCase.childCalled, childCalled)
+		})
+	}
+}
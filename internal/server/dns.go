@@ -2,6 +2,7 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -33,6 +34,50 @@ func (h *dnsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		default:
 			http.Error(w, "", http.StatusNotFound)
 		}
+	case "/stats":
+		switch r.Method {
+		case http.MethodGet:
+			h.getStats(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/cache/flush":
+		switch r.Method {
+		case http.MethodPost:
+			h.flushCache(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/restart":
+		switch r.Method {
+		case http.MethodPost:
+			h.restart(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/blocklists/reload":
+		switch r.Method {
+		case http.MethodPost:
+			h.reloadBlocklists(w, r)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/blocklists/categories":
+		switch r.Method {
+		case http.MethodGet:
+			h.getBlockCategories(w)
+		case http.MethodPut:
+			h.setBlockCategories(w, r)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/queries":
+		switch r.Method {
+		case http.MethodGet:
+			h.getQueries(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
 	default:
 		http.Error(w, "", http.StatusNotFound)
 	}
@@ -73,3 +118,89 @@ func (h *dnsHandler) setStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func (h *dnsHandler) getQueries(w http.ResponseWriter) {
+	queries := h.looper.GetQueries()
+	encoder := json.NewEncoder(w)
+	data := queriesWrapper{Queries: queries}
+	if err := encoder.Encode(data); err != nil {
+		h.logger.Warn(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *dnsHandler) getStats(w http.ResponseWriter) {
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(h.looper.GetStats()); err != nil {
+		h.logger.Warn(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *dnsHandler) flushCache(w http.ResponseWriter) {
+	outcome := h.looper.FlushCache()
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.logger.Warn(err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *dnsHandler) writeOutcome(w http.ResponseWriter, outcome string, err error) {
+	switch {
+	case errors.Is(err, dns.ErrNotRunning):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case errors.Is(err, dns.ErrBlockCategoryUnknown):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.logger.Warn(err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *dnsHandler) restart(w http.ResponseWriter) {
+	outcome, err := h.looper.Restart()
+	h.writeOutcome(w, outcome, err)
+}
+
+// reloadBlocklists downloads the block lists again and responds
+// once done, which can take a few seconds.
+func (h *dnsHandler) reloadBlocklists(w http.ResponseWriter, r *http.Request) {
+	outcome, err := h.looper.ReloadBlocklists(r.Context())
+	h.writeOutcome(w, outcome, err)
+}
+
+func (h *dnsHandler) getBlockCategories(w http.ResponseWriter) {
+	categories := h.looper.GetSettings().BlockCategories
+	if categories == nil {
+		categories = []string{}
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(blockCategoriesWrapper{Categories: categories}); err != nil {
+		h.logger.Warn(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *dnsHandler) setBlockCategories(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var data blockCategoriesWrapper
+	if err := decoder.Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	outcome, err := h.looper.SetBlockCategories(r.Context(), data.Categories)
+	h.writeOutcome(w, outcome, err)
+}
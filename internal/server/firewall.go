@@ -0,0 +1,275 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/qdm12/golibs/logging"
+)
+
+func newFirewallHandler(fw firewall.Configurator, logger logging.Logger,
+	lanInterface string) http.Handler {
+	return &firewallHandler{
+		fw:           fw,
+		logger:       logger,
+		lanInterface: lanInterface,
+	}
+}
+
+type firewallHandler struct {
+	fw           firewall.Configurator
+	logger       logging.Logger
+	lanInterface string
+	// reenableTimer re-enables the firewall after it is disabled
+	// temporarily, and is nil if no re-enabling is scheduled.
+	reenableTimer *time.Timer
+	timerMutex    sync.Mutex
+}
+
+// ServeHTTP serves the firewall endpoints, whose access is
+// restricted by the authentication middleware.
+func (h *firewallHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/firewall")
+	switch r.RequestURI {
+	case "/status":
+		switch r.Method {
+		case http.MethodGet:
+			h.getStatus(w)
+		case http.MethodPut:
+			h.setStatus(w, r)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/ports":
+		switch r.Method {
+		case http.MethodGet:
+			h.getPorts(w)
+		case http.MethodPut:
+			h.setPort(w, r)
+		case http.MethodDelete:
+			h.removePort(w, r)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/rules":
+		switch r.Method {
+		case http.MethodGet:
+			h.getRules(w, r)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/counters":
+		switch r.Method {
+		case http.MethodGet:
+			h.getCounters(w, r)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	default:
+		http.Error(w, "", http.StatusNotFound)
+	}
+}
+
+type firewallStatusWrapper struct {
+	Enabled bool `json:"enabled"`
+	// Duration is the duration after which the firewall is enabled
+	// again when disabling it, and is ignored if empty.
+	Duration string `json:"duration,omitempty"`
+}
+
+func (h *firewallHandler) getStatus(w http.ResponseWriter) {
+	encoder := json.NewEncoder(w)
+	data := firewallStatusWrapper{Enabled: h.fw.GetEnabled()}
+	if err := encoder.Encode(data); err != nil {
+		h.logger.Warn(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *firewallHandler) setStatus(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var data firewallStatusWrapper
+	if err := decoder.Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var duration time.Duration
+	if data.Duration != "" {
+		var err error
+		duration, err = time.ParseDuration(data.Duration)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	h.timerMutex.Lock()
+	defer h.timerMutex.Unlock()
+	if h.reenableTimer != nil {
+		h.reenableTimer.Stop()
+		h.reenableTimer = nil
+	}
+
+	// the firewall change must not be interrupted if the client disconnects
+	if err := h.fw.SetEnabled(context.Background(), data.Enabled); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	outcome := "disabled"
+	if data.Enabled {
+		outcome = "enabled"
+	} else if duration > 0 {
+		outcome = "disabled for " + duration.String()
+		var timer *time.Timer
+		timer = time.AfterFunc(duration, func() { h.reenable(timer) })
+		h.reenableTimer = timer
+	}
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.logger.Warn(err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *firewallHandler) reenable(timer *time.Timer) {
+	h.timerMutex.Lock()
+	defer h.timerMutex.Unlock()
+	if h.reenableTimer != timer { // status changed since
+		return
+	}
+	h.reenableTimer = nil
+	h.logger.Info("re-enabling firewall after it was temporarily disabled")
+	if err := h.fw.SetEnabled(context.Background(), true); err != nil {
+		h.logger.Error(err)
+	}
+}
+
+type firewallPortWrapper struct {
+	Port uint16 `json:"port"`
+	// Interface is the network interface name, or vpn or lan
+	// when setting a port.
+	Interface string `json:"interface,omitempty"`
+}
+
+func (h *firewallHandler) getPorts(w http.ResponseWriter) {
+	ports := h.fw.GetAllowedPorts()
+	data := make([]firewallPortWrapper, 0, len(ports))
+	for port, intf := range ports {
+		data = append(data, firewallPortWrapper{Port: port, Interface: intf})
+	}
+	sort.Slice(data, func(i, j int) bool {
+		return data[i].Port < data[j].Port
+	})
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.logger.Warn(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+var errFirewallPortInvalid = errors.New("port must be between 1 and 65535")
+
+// decodePort decodes the port data from the request body, and writes
+// a bad request error to the response writer if it is not valid.
+func decodePort(w http.ResponseWriter, r *http.Request) (data firewallPortWrapper, ok bool) {
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return data, false
+	}
+	if data.Port == 0 { // values above 65535 do not decode in an uint16
+		http.Error(w, errFirewallPortInvalid.Error(), http.StatusBadRequest)
+		return data, false
+	}
+	return data, true
+}
+
+func (h *firewallHandler) setPort(w http.ResponseWriter, r *http.Request) {
+	data, ok := decodePort(w, r)
+	if !ok {
+		return
+	}
+
+	var intf string
+	switch data.Interface {
+	case "vpn":
+		intf = h.fw.GetVPNInterface()
+	case "lan":
+		intf = h.lanInterface
+	default:
+		errString := fmt.Sprintf("invalid interface %q: possible values are: vpn, lan", data.Interface)
+		http.Error(w, errString, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.fw.SetAllowedPort(context.Background(), data.Port, intf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	outcome := fmt.Sprintf("port %d opened on interface %s", data.Port, intf)
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.logger.Warn(err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *firewallHandler) removePort(w http.ResponseWriter, r *http.Request) {
+	data, ok := decodePort(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.fw.RemoveAllowedPort(context.Background(), data.Port); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	outcome := fmt.Sprintf("port %d closed", data.Port)
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.logger.Warn(err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *firewallHandler) getRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.fw.GetRules(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if _, err := w.Write([]byte(rules + "\n")); err != nil {
+		h.logger.Warn(err)
+	}
+}
+
+func (h *firewallHandler) getCounters(w http.ResponseWriter, r *http.Request) {
+	counters, err := h.fw.GetCounters(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if _, err := w.Write([]byte(counters + "\n")); err != nil {
+		h.logger.Warn(err)
+	}
+}
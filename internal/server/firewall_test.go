@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/firewall/mock_firewall"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_firewallHandler(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	ctx := context.Background()
+
+	testCases := map[string]struct {
+		method       string
+		path         string
+		body         string
+		prepareMocks func(fw *mock_firewall.MockConfigurator)
+		statusCode   int
+		responseBody string
+	}{
+		"get status": {
+			method: http.MethodGet,
+			path:   "/firewall/status",
+			prepareMocks: func(fw *mock_firewall.MockConfigurator) {
+				fw.EXPECT().GetEnabled().Return(true)
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"enabled":true}` + "\n",
+		},
+		"enable": {
+			method: http.MethodPut,
+			path:   "/firewall/status",
+			body:   `{"enabled":true}`,
+			prepareMocks: func(fw *mock_firewall.MockConfigurator) {
+				fw.EXPECT().SetEnabled(ctx, true).Return(nil)
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"outcome":"enabled"}` + "\n",
+		},
+		"disable temporarily": {
+			method: http.MethodPut,
+			path:   "/firewall/status",
+			body:   `{"enabled":false,"duration":"1h"}`,
+			prepareMocks: func(fw *mock_firewall.MockConfigurator) {
+				fw.EXPECT().SetEnabled(ctx, false).Return(nil)
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"outcome":"disabled for 1h0m0s"}` + "\n",
+		},
+		"set status failing": {
+			method: http.MethodPut,
+			path:   "/firewall/status",
+			body:   `{"enabled":false}`,
+			prepareMocks: func(fw *mock_firewall.MockConfigurator) {
+				fw.EXPECT().SetEnabled(ctx, false).Return(errTest)
+			},
+			statusCode:   http.StatusInternalServerError,
+			responseBody: "test error\n",
+		},
+		"get ports": {
+			method: http.MethodGet,
+			path:   "/firewall/ports",
+			prepareMocks: func(fw *mock_firewall.MockConfigurator) {
+				fw.EXPECT().GetAllowedPorts().Return(map[uint16]string{
+					8000: "tun0",
+					443:  "eth0",
+				})
+			},
+			statusCode: http.StatusOK,
+			responseBody: `[{"port":443,"interface":"eth0"},` +
+				`{"port":8000,"interface":"tun0"}]` + "\n",
+		},
+		"set port on VPN interface": {
+			method: http.MethodPut,
+			path:   "/firewall/ports",
+			body:   `{"port":8000,"interface":"vpn"}`,
+			prepareMocks: func(fw *mock_firewall.MockConfigurator) {
+				fw.EXPECT().GetVPNInterface().Return("tun1")
+				fw.EXPECT().SetAllowedPort(ctx, uint16(8000), "tun1").Return(nil)
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"outcome":"port 8000 opened on interface tun1"}` + "\n",
+		},
+		"set port on LAN interface": {
+			method: http.MethodPut,
+			path:   "/firewall/ports",
+			body:   `{"port":65535,"interface":"lan"}`,
+			prepareMocks: func(fw *mock_firewall.MockConfigurator) {
+				fw.EXPECT().SetAllowedPort(ctx, uint16(65535), "eth0").Return(nil)
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"outcome":"port 65535 opened on interface eth0"}` + "\n",
+		},
+		"set port zero": {
+			method:       http.MethodPut,
+			path:         "/firewall/ports",
+			body:         `{"port":0,"interface":"vpn"}`,
+			statusCode:   http.StatusBadRequest,
+			responseBody: "port must be between 1 and 65535\n",
+		},
+		"set port missing": {
+			method:       http.MethodPut,
+			path:         "/firewall/ports",
+			body:         `{"interface":"vpn"}`,
+			statusCode:   http.StatusBadRequest,
+			responseBody: "port must be between 1 and 65535\n",
+		},
+		"set port too high": {
+			method:     http.MethodPut,
+			path:       "/firewall/ports",
+			body:       `{"port":65536,"interface":"vpn"}`,
+			statusCode: http.StatusBadRequest,
+			responseBody: "json: cannot unmarshal number 65536 into Go struct field " +
+				"firewallPortWrapper.port of type uint16\n",
+		},
+		"set port with invalid interface": {
+			method:       http.MethodPut,
+			path:         "/firewall/ports",
+			body:         `{"port":8000,"interface":"eth1"}`,
+			statusCode:   http.StatusBadRequest,
+			responseBody: `invalid interface "eth1": possible values are: vpn, lan` + "\n",
+		},
+		"set port failing": {
+			method: http.MethodPut,
+			path:   "/firewall/ports",
+			body:   `{"port":8000,"interface":"vpn"}`,
+			prepareMocks: func(fw *mock_firewall.MockConfigurator) {
+				fw.EXPECT().GetVPNInterface().Return("tun0")
+				fw.EXPECT().SetAllowedPort(ctx, uint16(8000), "tun0").Return(errTest)
+			},
+			statusCode:   http.StatusInternalServerError,
+			responseBody: "test error\n",
+		},
+		"remove port": {
+			method: http.MethodDelete,
+			path:   "/firewall/ports",
+			body:   `{"port":8000}`,
+			prepareMocks: func(fw *mock_firewall.MockConfigurator) {
+				fw.EXPECT().RemoveAllowedPort(ctx, uint16(8000)).Return(nil)
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"outcome":"port 8000 closed"}` + "\n",
+		},
+		"remove port zero": {
+			method:       http.MethodDelete,
+			path:         "/firewall/ports",
+			body:         `{"port":0}`,
+			statusCode:   http.StatusBadRequest,
+			responseBody: "port must be between 1 and 65535\n",
+		},
+		"remove port failing": {
+			method: http.MethodDelete,
+			path:   "/firewall/ports",
+			body:   `{"port":8000}`,
+			prepareMocks: func(fw *mock_firewall.MockConfigurator) {
+				fw.EXPECT().RemoveAllowedPort(ctx, uint16(8000)).Return(errTest)
+			},
+			statusCode:   http.StatusInternalServerError,
+			responseBody: "test error\n",
+		},
+		"wrong method": {
+			method:       http.MethodPost,
+			path:         "/firewall/ports",
+			statusCode:   http.StatusNotFound,
+			responseBody: "\n",
+		},
+		"unknown path": {
+			method:       http.MethodGet,
+			path:         "/firewall/unknown",
+			statusCode:   http.StatusNotFound,
+			responseBody: "\n",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			fw := mock_firewall.NewMockConfigurator(ctrl)
+			if testCase.prepareMocks != nil {
+				testCase.prepareMocks(fw)
+			}
+			handler := newFirewallHandler(fw, nil, "eth0")
+
+			request := httptest.NewRequest(testCase.method, testCase.path,
+				strings.NewReader(testCase.body))
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, testCase.statusCode, recorder.Code)
+			assert.Equal(t, testCase.responseBody, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,499 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/dns"
+	gluetunLogging "github.com/qdm12/gluetun/internal/logging"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/openvpn"
+	"github.com/qdm12/gluetun/internal/publicip"
+	"github.com/qdm12/gluetun/internal/updater"
+	gluetunv1 "github.com/qdm12/gluetun/pkg/proto/gluetun/v1"
+	"github.com/qdm12/golibs/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type grpcServer struct {
+	settings ListenSettings
+	logger   logging.Logger
+	server   *grpc.Server
+}
+
+// NewGRPC creates the gRPC control server, serving the control
+// operations of the v1 HTTP API with the same credentials.
+// The Unix socket of the listen settings is ignored.
+func NewGRPC(listenSettings ListenSettings, logger logging.Logger,
+	logBuffer *gluetunLogging.Buffer, buildInfo models.BuildInformation,
+	openvpnLooper openvpn.Looper, dnsLooper dns.Looper,
+	updaterLooper updater.Looper, publicIPLooper publicip.Looper,
+	authSettings AuthSettings) (Server, error) {
+	serverLogger := logger.NewChild(logging.SetPrefix("grpc server: "))
+	auth := &grpcAuth{settings: authSettings, logger: serverLogger}
+	options := []grpc.ServerOption{
+		grpc.UnaryInterceptor(auth.unaryInterceptor),
+		grpc.StreamInterceptor(auth.streamInterceptor),
+	}
+
+	if listenSettings.TLS {
+		certificate, err := loadCertificate(listenSettings.TLSCertFile, listenSettings.TLSKeyFile,
+			constants.ControlServerCertificate, constants.ControlServerKey)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load TLS certificate: %w", err)
+		}
+		options = append(options, grpc.Creds(credentials.NewTLS(&tls.Config{
+			Certificates: []tls.Certificate{certificate},
+			MinVersion:   tls.VersionTLS12,
+		})))
+	}
+
+	server := grpc.NewServer(options...)
+	gluetunv1.RegisterControlServer(server, &grpcControl{
+		logBuffer: logBuffer,
+		buildInfo: buildInfo,
+		openvpn:   openvpnLooper,
+		dns:       dnsLooper,
+		updater:   updaterLooper,
+		publicip:  publicIPLooper,
+	})
+
+	return &grpcServer{
+		settings: listenSettings,
+		logger:   serverLogger,
+		server:   server,
+	}, nil
+}
+
+func (s *grpcServer) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	listener, err := net.Listen("tcp", s.settings.Address)
+	if err != nil {
+		s.logger.Error(err)
+		return
+	}
+
+	go func() {
+		<-ctx.Done()
+		s.logger.Warn("context canceled: shutting down")
+		stopped := make(chan struct{})
+		go func() {
+			s.server.GracefulStop()
+			close(stopped)
+		}()
+		// streams only return once stopped by the client,
+		// so stop them after a grace duration.
+		const shutdownGraceDuration = 2 * time.Second
+		timer := time.NewTimer(shutdownGraceDuration)
+		defer timer.Stop()
+		select {
+		case <-stopped:
+		case <-timer.C:
+			s.server.Stop()
+		}
+	}()
+
+	s.logger.Info("listening on %s", listener.Addr())
+	if err := s.server.Serve(listener); err != nil {
+		s.logger.Error(err)
+	}
+	s.logger.Warn("shut down")
+}
+
+type grpcAuth struct {
+	settings AuthSettings
+	logger   logging.Logger
+}
+
+func (a *grpcAuth) unaryInterceptor(ctx context.Context, request interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (response interface{}, err error) {
+	ctx, err = a.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, request)
+}
+
+func (a *grpcAuth) streamInterceptor(server interface{}, stream grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	ctx, err := a.authenticate(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(server, &authenticatedStream{ServerStream: stream, ctx: ctx})
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context { return s.ctx }
+
+// authenticate checks the credentials of the request metadata, which are
+// the same as the HTTP headers of the HTTP control server: the API key
+// in the x-api-key metadata or as a bearer token in the authorization
+// metadata, or basic authentication credentials in the authorization
+// metadata. It returns the context with the actor of the credentials.
+func (a *grpcAuth) authenticate(ctx context.Context, fullMethod string) (
+	authenticated context.Context, err error) {
+	if !a.settings.enabled() {
+		return ctx, nil
+	}
+
+	header := make(http.Header)
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range []string{apiKeyHeader, "Authorization"} {
+		if values := md.Get(key); len(values) > 0 {
+			header.Set(key, values[0])
+		}
+	}
+
+	granted, actor := a.settings.grantedRole(&http.Request{Header: header})
+	switch {
+	case granted == roleNone:
+		remoteAddress := "unknown address"
+		if p, ok := peer.FromContext(ctx); ok {
+			remoteAddress = p.Addr.String()
+		}
+		a.logger.Warn("unauthorized call %s from %s", fullMethod, remoteAddress)
+		return nil, status.Error(codes.Unauthenticated, "credentials are missing or invalid")
+	case granted < grpcRequiredRole(fullMethod):
+		return nil, status.Error(codes.PermissionDenied,
+			"read only credentials cannot be used for "+fullMethod)
+	default:
+		return withActor(ctx, actor), nil
+	}
+}
+
+// grpcRequiredRole returns the role required for the full method name
+// given. The methods getting or watching a state require the read only
+// role, and the other methods the control role.
+func grpcRequiredRole(fullMethod string) role {
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	if strings.HasPrefix(method, "Get") || strings.HasPrefix(method, "Watch") {
+		return roleReadOnly
+	}
+	return roleControl
+}
+
+type grpcControl struct {
+	gluetunv1.UnimplementedControlServer
+	logBuffer *gluetunLogging.Buffer
+	buildInfo models.BuildInformation
+	openvpn   openvpn.Looper
+	dns       dns.Looper
+	updater   updater.Looper
+	publicip  publicip.Looper
+}
+
+// grpcError returns the gRPC status error of the error given, with
+// the code corresponding to the HTTP status code of the HTTP API.
+func grpcError(err error) error {
+	switch {
+	case errors.Is(err, dns.ErrNotRunning),
+		errors.Is(err, openvpn.ErrPauseFirewallDisabled),
+		errors.Is(err, openvpn.ErrPortForwardingDisabled),
+		errors.Is(err, openvpn.ErrPortForwardingNotRunning):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, dns.ErrBlockCategoryUnknown),
+		errors.Is(err, configuration.ErrUpdaterProviderUnknown):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func statusFromRequest(request *gluetunv1.SetStatusRequest) (status models.LoopStatus, err error) {
+	wrapper := statusWrapper{Status: request.GetStatus()}
+	return wrapper.getStatus()
+}
+
+func (c *grpcControl) GetVersion(context.Context, *gluetunv1.GetVersionRequest) (
+	*gluetunv1.BuildInformation, error) {
+	return &gluetunv1.BuildInformation{
+		Version:   c.buildInfo.Version,
+		Commit:    c.buildInfo.Commit,
+		BuildDate: c.buildInfo.BuildDate,
+	}, nil
+}
+
+func (c *grpcControl) GetOpenVPNStatus(context.Context, *gluetunv1.GetStatusRequest) (
+	*gluetunv1.StatusResponse, error) {
+	return &gluetunv1.StatusResponse{Status: string(c.openvpn.GetStatus())}, nil
+}
+
+func (c *grpcControl) SetOpenVPNStatus(_ context.Context, request *gluetunv1.SetStatusRequest) (
+	*gluetunv1.OutcomeResponse, error) {
+	loopStatus, err := statusFromRequest(request)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	outcome, err := c.openvpn.SetStatus(loopStatus)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &gluetunv1.OutcomeResponse{Outcome: outcome}, nil
+}
+
+func (c *grpcControl) GetOpenVPNState(context.Context, *gluetunv1.GetOpenVPNStateRequest) (
+	*gluetunv1.OpenVPNState, error) {
+	state := c.openvpn.GetConnectionState()
+	return &gluetunv1.OpenVPNState{
+		State:   string(state.State),
+		Failure: string(state.Failure),
+	}, nil
+}
+
+func (c *grpcControl) SwitchServer(context.Context, *gluetunv1.SwitchServerRequest) (
+	*gluetunv1.OutcomeResponse, error) {
+	return &gluetunv1.OutcomeResponse{Outcome: c.openvpn.SwitchServer()}, nil
+}
+
+func (c *grpcControl) GetVPNSettings(context.Context, *gluetunv1.GetVPNSettingsRequest) (
+	*gluetunv1.VPNSettings, error) {
+	settings := c.openvpn.GetSettings()
+	selection := settings.Provider.ServerSelection
+	return &gluetunv1.VPNSettings{
+		Provider:  settings.Provider.Name,
+		Countries: selection.Countries,
+		Regions:   selection.Regions,
+		Cities:    selection.Cities,
+		Hostnames: selection.Hostnames,
+	}, nil
+}
+
+func (c *grpcControl) SetVPNSettings(_ context.Context, request *gluetunv1.VPNSettings) (
+	*gluetunv1.OutcomeResponse, error) {
+	outcome, err := setServerFilters(c.openvpn, vpnSettingsWrapper{
+		Provider:  request.GetProvider(),
+		Countries: request.GetCountries(),
+		Regions:   request.GetRegions(),
+		Cities:    request.GetCities(),
+		Hostnames: request.GetHostnames(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &gluetunv1.OutcomeResponse{Outcome: outcome}, nil
+}
+
+func (c *grpcControl) PauseVPN(context.Context, *gluetunv1.PauseVPNRequest) (
+	*gluetunv1.OutcomeResponse, error) {
+	outcome, err := c.openvpn.Pause()
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &gluetunv1.OutcomeResponse{Outcome: outcome}, nil
+}
+
+func (c *grpcControl) ResumeVPN(context.Context, *gluetunv1.ResumeVPNRequest) (
+	*gluetunv1.OutcomeResponse, error) {
+	outcome, err := c.openvpn.Resume()
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &gluetunv1.OutcomeResponse{Outcome: outcome}, nil
+}
+
+func portForwardStatusToProto(portForwardStatus models.PortForwardStatus) *gluetunv1.PortForwardStatus {
+	message := &gluetunv1.PortForwardStatus{
+		Provider: portForwardStatus.Provider,
+		Port:     uint32(portForwardStatus.Port),
+	}
+	if !portForwardStatus.Expiration.IsZero() {
+		message.ExpiresAt = timestamppb.New(portForwardStatus.Expiration)
+	}
+	return message
+}
+
+func (c *grpcControl) GetPortForward(context.Context, *gluetunv1.GetPortForwardRequest) (
+	*gluetunv1.PortForwardStatus, error) {
+	return portForwardStatusToProto(c.openvpn.GetPortForwardStatus()), nil
+}
+
+func (c *grpcControl) RenewPortForward(context.Context, *gluetunv1.RenewPortForwardRequest) (
+	*gluetunv1.OutcomeResponse, error) {
+	if err := c.openvpn.RenewPortForward(); err != nil {
+		return nil, grpcError(err)
+	}
+	return &gluetunv1.OutcomeResponse{Outcome: "renewing"}, nil
+}
+
+func (c *grpcControl) WatchPortForward(_ *gluetunv1.WatchPortForwardRequest,
+	stream gluetunv1.Control_WatchPortForwardServer) error {
+	updates, unsubscribe := c.openvpn.SubscribePortForward()
+	defer unsubscribe()
+
+	portForwardStatus := c.openvpn.GetPortForwardStatus()
+	for {
+		if err := stream.Send(portForwardStatusToProto(portForwardStatus)); err != nil {
+			return err
+		}
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case portForwardStatus = <-updates:
+		}
+	}
+}
+
+func (c *grpcControl) GetDNSStatus(context.Context, *gluetunv1.GetStatusRequest) (
+	*gluetunv1.StatusResponse, error) {
+	return &gluetunv1.StatusResponse{Status: string(c.dns.GetStatus())}, nil
+}
+
+func (c *grpcControl) SetDNSStatus(_ context.Context, request *gluetunv1.SetStatusRequest) (
+	*gluetunv1.OutcomeResponse, error) {
+	loopStatus, err := statusFromRequest(request)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	outcome, err := c.dns.SetStatus(loopStatus)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &gluetunv1.OutcomeResponse{Outcome: outcome}, nil
+}
+
+func (c *grpcControl) RestartDNS(context.Context, *gluetunv1.RestartDNSRequest) (
+	*gluetunv1.OutcomeResponse, error) {
+	outcome, err := c.dns.Restart()
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &gluetunv1.OutcomeResponse{Outcome: outcome}, nil
+}
+
+func (c *grpcControl) FlushDNSCache(context.Context, *gluetunv1.FlushDNSCacheRequest) (
+	*gluetunv1.OutcomeResponse, error) {
+	return &gluetunv1.OutcomeResponse{Outcome: c.dns.FlushCache()}, nil
+}
+
+func (c *grpcControl) ReloadDNSBlocklists(ctx context.Context, _ *gluetunv1.ReloadDNSBlocklistsRequest) (
+	*gluetunv1.OutcomeResponse, error) {
+	outcome, err := c.dns.ReloadBlocklists(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &gluetunv1.OutcomeResponse{Outcome: outcome}, nil
+}
+
+func (c *grpcControl) GetDNSBlockCategories(context.Context, *gluetunv1.GetDNSBlockCategoriesRequest) (
+	*gluetunv1.DNSBlockCategories, error) {
+	return &gluetunv1.DNSBlockCategories{Categories: c.dns.GetSettings().BlockCategories}, nil
+}
+
+func (c *grpcControl) SetDNSBlockCategories(ctx context.Context, request *gluetunv1.DNSBlockCategories) (
+	*gluetunv1.OutcomeResponse, error) {
+	outcome, err := c.dns.SetBlockCategories(ctx, request.GetCategories())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &gluetunv1.OutcomeResponse{Outcome: outcome}, nil
+}
+
+func (c *grpcControl) GetUpdaterStatus(context.Context, *gluetunv1.GetStatusRequest) (
+	*gluetunv1.StatusResponse, error) {
+	return &gluetunv1.StatusResponse{Status: string(c.updater.GetStatus())}, nil
+}
+
+func (c *grpcControl) SetUpdaterStatus(_ context.Context, request *gluetunv1.SetStatusRequest) (
+	*gluetunv1.OutcomeResponse, error) {
+	loopStatus, err := statusFromRequest(request)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	outcome, err := c.updater.SetStatus(loopStatus)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &gluetunv1.OutcomeResponse{Outcome: outcome}, nil
+}
+
+func (c *grpcControl) RunUpdate(ctx context.Context, request *gluetunv1.RunUpdateRequest) (
+	*gluetunv1.RunUpdateResponse, error) {
+	results, err := c.updater.RunUpdate(ctx, request.GetProviders())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	response := &gluetunv1.RunUpdateResponse{
+		Results: make([]*gluetunv1.ServersUpdate, len(results)),
+	}
+	for i, result := range results {
+		response.Results[i] = &gluetunv1.ServersUpdate{
+			Provider: result.Provider,
+			Previous: uint32(result.Previous),
+			Servers:  uint32(result.Servers),
+			Error:    result.Error,
+		}
+	}
+	return response, nil
+}
+
+func (c *grpcControl) GetPublicIP(context.Context, *gluetunv1.GetPublicIPRequest) (
+	*gluetunv1.PublicIP, error) {
+	var publicIP string
+	if ip := c.publicip.GetPublicIP(); ip != nil {
+		publicIP = ip.String()
+	}
+	return &gluetunv1.PublicIP{PublicIp: publicIP}, nil
+}
+
+func logLineToProto(line gluetunLogging.LogLine) *gluetunv1.LogLine {
+	message := &gluetunv1.LogLine{
+		Level:   line.Level,
+		Message: line.Message,
+	}
+	if !line.Time.IsZero() {
+		message.Time = timestamppb.New(line.Time)
+	}
+	return message
+}
+
+func (c *grpcControl) WatchLogs(request *gluetunv1.WatchLogsRequest,
+	stream gluetunv1.Control_WatchLogsServer) error {
+	level := strings.ToLower(request.GetLevel())
+	if !gluetunLogging.ValidLevel(level) {
+		return status.Error(codes.InvalidArgument,
+			"invalid level "+level+": possible values are: debug, info, warn, error")
+	}
+
+	last, lines, unsubscribe := c.logBuffer.Subscribe(int(request.GetTail()), level)
+	defer unsubscribe()
+
+	for _, line := range last {
+		if err := stream.Send(logLineToProto(line)); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case line := <-lines:
+			if err := stream.Send(logLineToProto(line)); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/dns"
+	gluetunLogging "github.com/qdm12/gluetun/internal/logging"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/openvpn"
+	"github.com/qdm12/gluetun/internal/openvpn/mock_openvpn"
+	gluetunv1 "github.com/qdm12/gluetun/pkg/proto/gluetun/v1"
+	"github.com/qdm12/golibs/logging"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestGRPCClient serves the control given in memory with the
+// authentication settings given, and returns a client to it.
+func newTestGRPCClient(t *testing.T, authSettings AuthSettings,
+	logger logging.Logger, control *grpcControl) gluetunv1.ControlClient {
+	t.Helper()
+
+	auth := &grpcAuth{settings: authSettings, logger: logger}
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(auth.unaryInterceptor),
+		grpc.StreamInterceptor(auth.streamInterceptor),
+	)
+	gluetunv1.RegisterControlServer(server, control)
+
+	const bufferSize = 1024 * 1024
+	listener := bufconn.Listen(bufferSize)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	connection, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = connection.Close() })
+
+	return gluetunv1.NewControlClient(connection)
+}
+
+func Test_grpcAuth(t *testing.T) {
+	t.Parallel()
+
+	settings := AuthSettings{
+		APIKey:         "control",
+		ReadOnlyAPIKey: "readonly",
+		User:           "admin",
+		Password:       "password",
+	}
+	basicAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte("admin:password"))
+
+	testCases := map[string]struct {
+		settings   AuthSettings
+		metadata   []string
+		control    bool
+		warnLogged bool
+		code       codes.Code
+	}{
+		"no credentials set": {
+			control: true,
+			code:    codes.OK,
+		},
+		"missing credentials": {
+			settings:   settings,
+			warnLogged: true,
+			code:       codes.Unauthenticated,
+		},
+		"wrong API key": {
+			settings:   settings,
+			metadata:   []string{"x-api-key", "wrong"},
+			warnLogged: true,
+			code:       codes.Unauthenticated,
+		},
+		"read only API key for read only method": {
+			settings: settings,
+			metadata: []string{"x-api-key", "readonly"},
+			code:     codes.OK,
+		},
+		"read only API key for control method": {
+			settings: settings,
+			metadata: []string{"authorization", "Bearer readonly"},
+			control:  true,
+			code:     codes.PermissionDenied,
+		},
+		"control API key as bearer token": {
+			settings: settings,
+			metadata: []string{"authorization", "Bearer control"},
+			control:  true,
+			code:     codes.OK,
+		},
+		"basic authentication": {
+			settings: settings,
+			metadata: []string{"authorization", basicAuth},
+			control:  true,
+			code:     codes.OK,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			logger := mock_logging.NewMockLogger(ctrl)
+			if testCase.warnLogged {
+				logger.EXPECT().Warn("unauthorized call %s from %s",
+					gomock.Any(), gomock.Any())
+			}
+
+			looper := mock_openvpn.NewMockLooper(ctrl)
+			if testCase.code == codes.OK {
+				if testCase.control {
+					looper.EXPECT().SwitchServer().Return("switching")
+				} else {
+					looper.EXPECT().GetStatus().Return(constants.Running)
+				}
+			}
+
+			client := newTestGRPCClient(t, testCase.settings, logger,
+				&grpcControl{openvpn: looper})
+
+			ctx := metadata.AppendToOutgoingContext(context.Background(), testCase.metadata...)
+			var err error
+			if testCase.control {
+				_, err = client.SwitchServer(ctx, &gluetunv1.SwitchServerRequest{})
+			} else {
+				_, err = client.GetOpenVPNStatus(ctx, &gluetunv1.GetStatusRequest{})
+			}
+			assert.Equal(t, testCase.code, status.Code(err))
+		})
+	}
+}
+
+func Test_grpcAuth_stream(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	settings := AuthSettings{APIKey: "control", ReadOnlyAPIKey: "readonly"}
+	logger := mock_logging.NewMockLogger(ctrl)
+	logger.EXPECT().Warn("unauthorized call %s from %s",
+		"/gluetun.v1.Control/WatchLogs", gomock.Any())
+
+	logBuffer := gluetunLogging.NewBuffer(10)
+	client := newTestGRPCClient(t, settings, logger, &grpcControl{logBuffer: logBuffer})
+
+	stream, err := client.WatchLogs(context.Background(), &gluetunv1.WatchLogsRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "readonly")
+	stream, err = client.WatchLogs(ctx, &gluetunv1.WatchLogsRequest{Level: "info"})
+	require.NoError(t, err)
+	_, err = logBuffer.Write([]byte("2021/02/15 10:00:00 INFO hello\n"))
+	require.NoError(t, err)
+	line, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", line.GetMessage())
+}
+
+func Test_grpcRequiredRole(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]role{
+		"/gluetun.v1.Control/GetVersion":       roleReadOnly,
+		"/gluetun.v1.Control/GetOpenVPNStatus": roleReadOnly,
+		"/gluetun.v1.Control/WatchLogs":        roleReadOnly,
+		"/gluetun.v1.Control/WatchPortForward": roleReadOnly,
+		"/gluetun.v1.Control/SetVPNSettings":   roleControl,
+		"/gluetun.v1.Control/SwitchServer":     roleControl,
+		"/gluetun.v1.Control/RunUpdate":        roleControl,
+	}
+
+	for fullMethod, expected := range testCases {
+		fullMethod, expected := fullMethod, expected
+		t.Run(fullMethod, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, expected, grpcRequiredRole(fullMethod))
+		})
+	}
+}
+
+func Test_grpcError(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		err  error
+		code codes.Code
+	}{
+		"DNS not running": {
+			err:  dns.ErrNotRunning,
+			code: codes.FailedPrecondition,
+		},
+		"port forwarding disabled": {
+			err:  openvpn.ErrPortForwardingDisabled,
+			code: codes.FailedPrecondition,
+		},
+		"unknown block category": {
+			err:  dns.ErrBlockCategoryUnknown,
+			code: codes.InvalidArgument,
+		},
+		"unknown updater provider": {
+			err:  configuration.ErrUpdaterProviderUnknown,
+			code: codes.InvalidArgument,
+		},
+		"context canceled": {
+			err:  context.Canceled,
+			code: codes.Canceled,
+		},
+		"other error": {
+			err:  assert.AnError,
+			code: codes.Internal,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := grpcError(testCase.err)
+			assert.Equal(t, testCase.code, status.Code(err))
+			assert.Equal(t, testCase.err.Error(), status.Convert(err).Message())
+		})
+	}
+}
+
+func Test_grpcControl_SetVPNSettings(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	looper := mock_openvpn.NewMockLooper(ctrl)
+	settings := configuration.OpenVPN{}
+	settings.Provider.Name = "mullvad"
+	looper.EXPECT().GetSettings().Return(settings).AnyTimes()
+	looper.EXPECT().SetServerSelection("mullvad", configuration.ServerSelection{
+		Countries: []string{"sweden"},
+	}).Return("server filters updated", nil)
+
+	client := newTestGRPCClient(t, AuthSettings{}, nil, &grpcControl{openvpn: looper})
+
+	response, err := client.SetVPNSettings(context.Background(), &gluetunv1.VPNSettings{
+		Countries: []string{"sweden"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "server filters updated", response.GetOutcome())
+}
+
+func Test_grpcControl_WatchPortForward(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	updates := make(chan models.PortForwardStatus)
+	looper := mock_openvpn.NewMockLooper(ctrl)
+	looper.EXPECT().SubscribePortForward().Return(updates, func() {})
+	looper.EXPECT().GetPortForwardStatus().Return(models.PortForwardStatus{})
+
+	client := newTestGRPCClient(t, AuthSettings{}, nil, &grpcControl{openvpn: looper})
+
+	stream, err := client.WatchPortForward(context.Background(), &gluetunv1.WatchPortForwardRequest{})
+	require.NoError(t, err)
+
+	message, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), message.GetPort())
+	assert.Nil(t, message.GetExpiresAt())
+
+	expiration := time.Unix(1600000000, 0)
+	updates <- models.PortForwardStatus{Provider: "pia", Port: 1234, Expiration: expiration}
+	message, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "pia", message.GetProvider())
+	assert.Equal(t, uint32(1234), message.GetPort())
+	assert.Equal(t, expiration.UTC(), message.GetExpiresAt().AsTime())
+}
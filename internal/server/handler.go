@@ -5,31 +5,70 @@ import (
 	"strings"
 
 	"github.com/qdm12/gluetun/internal/dns"
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/qdm12/gluetun/internal/healthcheck"
+	"github.com/qdm12/gluetun/internal/httpproxy"
+	gluetunLogging "github.com/qdm12/gluetun/internal/logging"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/openvpn"
 	"github.com/qdm12/gluetun/internal/publicip"
+	"github.com/qdm12/gluetun/internal/reload"
 	"github.com/qdm12/gluetun/internal/updater"
 	"github.com/qdm12/golibs/logging"
 )
 
-func newHandler(logger logging.Logger, logging bool,
+func newHandler(logger logging.Logger, logging, metrics bool,
+	logBuffer *gluetunLogging.Buffer,
 	buildInfo models.BuildInformation,
+	reloader reload.Reloader,
 	openvpnLooper openvpn.Looper,
-	unboundLooper dns.Looper,
+	dnsLooper dns.Looper,
 	updaterLooper updater.Looper,
 	publicIPLooper publicip.Looper,
+	httpProxyLooper httpproxy.Looper,
+	healthchecker healthcheck.Server,
+	fw firewall.Configurator,
+	firewallSettings FirewallSettings,
+	authSettings AuthSettings,
+	accessSettings AccessSettings,
+	auditSettings AuditSettings,
 ) http.Handler {
 	handler := &handler{}
 
 	openvpn := newOpenvpnHandler(openvpnLooper, logger)
-	dns := newDNSHandler(unboundLooper, logger)
+	vpn := newVPNHandler(openvpnLooper, logger)
+	servers := newServersHandler(openvpnLooper, logger)
+	portForward := newPortForwardHandler(openvpnLooper, logger)
+	logs := newLogsHandler(logBuffer, logger)
+	settingsHandler := newSettingsHandler(reloader, openvpnLooper, dnsLooper,
+		updaterLooper, publicIPLooper, fw, logger)
+	dns := newDNSHandler(dnsLooper, logger)
 	updater := newUpdaterHandler(updaterLooper, logger)
 	publicip := newPublicIPHandler(publicIPLooper, logger)
+	firewall := newFirewallHandler(fw, logger, firewallSettings.LANInterface)
+	health := newHealthHandler(healthchecker, logger)
 
-	handler.v0 = newHandlerV0(logger, openvpnLooper, unboundLooper, updaterLooper)
-	handler.v1 = newHandlerV1(logger, buildInfo, openvpn, dns, updater, publicip)
+	handler.v0 = newHandlerV0(logger, openvpnLooper, dnsLooper, updaterLooper)
+	handler.v1 = newHandlerV1(logger, buildInfo, openvpn, vpn, servers, portForward,
+		settingsHandler, logs, dns, updater, publicip, firewall, health)
+	if metrics {
+		handler.metrics = newMetricsHandler(openvpnLooper, dnsLooper, publicIPLooper,
+			httpProxyLooper, healthchecker, firewallSettings.VPNInterface, logger)
+	}
+
+	var handlerWithAudit http.Handler = handler
+	if auditSettings.Enabled {
+		handlerWithAudit = withAuditMiddleware(handler, auditSettings.Filepath, logger)
+	}
 
-	handlerWithLog := withLogMiddleware(handler, logger, logging)
+	var handlerWithAccess http.Handler = withAuthMiddleware(handlerWithAudit, authSettings, logger)
+	if accessSettings.RateLimit > 0 {
+		handlerWithAccess = withRateLimitMiddleware(handlerWithAccess, accessSettings.RateLimit, logger)
+	}
+	if len(accessSettings.CORSOrigins) > 0 {
+		handlerWithAccess = withCORSMiddleware(handlerWithAccess, accessSettings.CORSOrigins)
+	}
+	handlerWithLog := withLogMiddleware(handlerWithAccess, logger, logging)
 	handler.setLogEnabled = handlerWithLog.setEnabled
 
 	return handlerWithLog
@@ -38,11 +77,16 @@ func newHandler(logger logging.Logger, logging bool,
 type handler struct {
 	v0            http.Handler
 	v1            http.Handler
+	metrics       http.Handler // nil if metrics are disabled
 	setLogEnabled func(enabled bool)
 }
 
 func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	r.RequestURI = strings.TrimSuffix(r.RequestURI, "/")
+	if r.RequestURI == "/metrics" && h.metrics != nil {
+		h.metrics.ServeHTTP(w, r)
+		return
+	}
 	if !strings.HasPrefix(r.RequestURI, "/v1/") && r.RequestURI != "/v1" {
 		h.v0.ServeHTTP(w, r)
 		return
@@ -11,38 +11,68 @@ import (
 )
 
 func newHandlerV1(logger logging.Logger, buildInfo models.BuildInformation,
-	openvpn, dns, updater, publicip http.Handler) http.Handler {
+	openvpn, vpn, servers, portForward, settings, logs, dns, updater, publicip, firewall, health http.Handler) http.Handler {
 	return &handlerV1{
-		logger:    logger,
-		buildInfo: buildInfo,
-		openvpn:   openvpn,
-		dns:       dns,
-		updater:   updater,
-		publicip:  publicip,
+		logger:      logger,
+		buildInfo:   buildInfo,
+		openvpn:     openvpn,
+		vpn:         vpn,
+		servers:     servers,
+		portForward: portForward,
+		settings:    settings,
+		logs:        logs,
+		dns:         dns,
+		updater:     updater,
+		publicip:    publicip,
+		firewall:    firewall,
+		health:      health,
 	}
 }
 
 type handlerV1 struct {
-	logger    logging.Logger
-	buildInfo models.BuildInformation
-	openvpn   http.Handler
-	dns       http.Handler
-	updater   http.Handler
-	publicip  http.Handler
+	logger      logging.Logger
+	buildInfo   models.BuildInformation
+	openvpn     http.Handler
+	vpn         http.Handler
+	servers     http.Handler
+	portForward http.Handler
+	settings    http.Handler
+	logs        http.Handler
+	dns         http.Handler
+	updater     http.Handler
+	publicip    http.Handler
+	firewall    http.Handler
+	health      http.Handler
 }
 
 func (h *handlerV1) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	switch {
 	case r.RequestURI == "/version" && r.Method == http.MethodGet:
 		h.getVersion(w)
+	case r.RequestURI == "/openapi.json" && r.Method == http.MethodGet:
+		h.getOpenAPISpec(w)
 	case strings.HasPrefix(r.RequestURI, "/openvpn"):
 		h.openvpn.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/vpn"):
+		h.vpn.ServeHTTP(w, r)
+	case r.RequestURI == "/servers" || strings.HasPrefix(r.RequestURI, "/servers?"):
+		h.servers.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/portforward"):
+		h.portForward.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/settings"):
+		h.settings.ServeHTTP(w, r)
+	case r.RequestURI == "/logs" || strings.HasPrefix(r.RequestURI, "/logs?"):
+		h.logs.ServeHTTP(w, r)
 	case strings.HasPrefix(r.RequestURI, "/dns"):
 		h.dns.ServeHTTP(w, r)
 	case strings.HasPrefix(r.RequestURI, "/updater"):
 		h.updater.ServeHTTP(w, r)
 	case strings.HasPrefix(r.RequestURI, "/publicip"):
 		h.publicip.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/firewall"):
+		h.firewall.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/health"):
+		h.health.ServeHTTP(w, r)
 	default:
 		errString := fmt.Sprintf("%s %s not found", r.Method, r.RequestURI)
 		http.Error(w, errString, http.StatusNotFound)
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/healthcheck"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/logging"
+)
+
+func newHealthHandler(healthchecker healthcheck.Server, logger logging.Logger) http.Handler {
+	return &healthHandler{
+		healthchecker: healthchecker,
+		logger:        logger,
+	}
+}
+
+type healthHandler struct {
+	healthchecker healthcheck.Server
+	logger        logging.Logger
+}
+
+func (h *healthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/health")
+	switch r.RequestURI {
+	case "/history":
+		switch r.Method {
+		case http.MethodGet:
+			h.getHistory(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	default:
+		http.Error(w, "", http.StatusNotFound)
+	}
+}
+
+type healthHistoryWrapper struct {
+	Probes []models.HealthProbe `json:"probes"`
+}
+
+func (h *healthHandler) getHistory(w http.ResponseWriter) {
+	data := healthHistoryWrapper{Probes: h.healthchecker.GetHistory()}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.logger.Warn(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	gluetunLogging "github.com/qdm12/gluetun/internal/logging"
+	"github.com/qdm12/golibs/logging"
+)
+
+func newLogsHandler(buffer *gluetunLogging.Buffer, logger logging.Logger) http.Handler {
+	return &logsHandler{
+		buffer: buffer,
+		logger: logger,
+	}
+}
+
+type logsHandler struct {
+	buffer *gluetunLogging.Buffer
+	logger logging.Logger
+}
+
+func (h *logsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "", http.StatusNotFound)
+		return
+	}
+	h.getLogs(w, r)
+}
+
+type logsWrapper struct {
+	Logs []gluetunLogging.LogLine `json:"logs"`
+}
+
+// getLogs writes the last log lines, optionally limited to the
+// number given by the tail query parameter, and to the level given
+// by the level query parameter and the more severe levels.
+func (h *logsHandler) getLogs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var tail int
+	if s := query.Get("tail"); s != "" {
+		var err error
+		tail, err = strconv.Atoi(s)
+		if err != nil || tail < 0 {
+			http.Error(w, "invalid tail: "+s, http.StatusBadRequest)
+			return
+		}
+	}
+
+	level := strings.ToLower(query.Get("level"))
+	if !gluetunLogging.ValidLevel(level) {
+		http.Error(w, "invalid level "+level+": possible values are: debug, info, warn, error",
+			http.StatusBadRequest)
+		return
+	}
+
+	data := logsWrapper{Logs: h.buffer.Lines(tail, level)}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.logger.Warn(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
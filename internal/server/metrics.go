@@ -0,0 +1,182 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/dns"
+	"github.com/qdm12/gluetun/internal/healthcheck"
+	"github.com/qdm12/gluetun/internal/httpproxy"
+	"github.com/qdm12/gluetun/internal/openvpn"
+	"github.com/qdm12/gluetun/internal/publicip"
+	"github.com/qdm12/golibs/logging"
+)
+
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+func newMetricsHandler(openvpnLooper openvpn.Looper, dnsLooper dns.Looper,
+	publicIPLooper publicip.Looper, httpProxyLooper httpproxy.Looper,
+	healthchecker healthcheck.Server, vpnInterface string, logger logging.Logger) http.Handler {
+	return &metricsHandler{
+		openvpn:       openvpnLooper,
+		dns:           dnsLooper,
+		httpProxy:     httpProxyLooper,
+		publicip:      publicIPLooper,
+		healthchecker: healthchecker,
+		vpnInterface:  vpnInterface,
+		statisticsDir: "/sys/class/net",
+		logger:        logger,
+	}
+}
+
+type metricsHandler struct {
+	openvpn       openvpn.Looper
+	dns           dns.Looper
+	httpProxy     httpproxy.Looper
+	publicip      publicip.Looper
+	healthchecker healthcheck.Server
+	vpnInterface  string
+	statisticsDir string
+	logger        logging.Logger
+}
+
+func (h *metricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", metricsContentType)
+	if err := h.write(w); err != nil {
+		h.logger.Warn(err)
+	}
+}
+
+// metric is a Prometheus metric with a single value.
+type metric struct {
+	name   string
+	help   string
+	kind   string // counter or gauge
+	value  float64
+	absent bool // true to not write the metric
+}
+
+// write writes the metrics in the Prometheus text exposition format.
+func (h *metricsHandler) write(w io.Writer) (err error) {
+	const counter, gauge = "counter", "gauge"
+
+	connectionState := h.openvpn.GetConnectionState()
+	tunnelStats := h.openvpn.GetTunnelStats()
+	var up, uptime float64
+	if connectionState.State == constants.OpenVPNUp {
+		up = 1
+		uptime = time.Since(tunnelStats.UpSince).Seconds()
+	}
+
+	dnsStats := h.dns.GetStats()
+	upstreamLatency, err := time.ParseDuration(dnsStats.UpstreamLatency)
+	upstreamLatencyAbsent := err != nil
+
+	healthStats := h.healthchecker.GetStats()
+	var healthy float64
+	if healthStats.Healthy {
+		healthy = 1
+	}
+
+	httpProxyStats := h.httpProxy.GetStats()
+
+	receivedBytes, receivedErr := h.interfaceStatistic("rx_bytes")
+	transmittedBytes, transmittedErr := h.interfaceStatistic("tx_bytes")
+
+	metrics := []metric{
+		{name: "gluetun_vpn_up", help: "Whether the VPN tunnel is up.",
+			kind: gauge, value: up},
+		{name: "gluetun_vpn_uptime_seconds", help: "Time since the VPN tunnel came up.",
+			kind: gauge, value: uptime},
+		{name: "gluetun_vpn_reconnects_total", help: "Number of times the VPN tunnel came up again.",
+			kind: counter, value: float64(tunnelStats.Reconnects)},
+		{name: "gluetun_vpn_receive_bytes_total", help: "Bytes received on the VPN interface.",
+			kind: counter, value: receivedBytes, absent: receivedErr != nil},
+		{name: "gluetun_vpn_transmit_bytes_total", help: "Bytes transmitted on the VPN interface.",
+			kind: counter, value: transmittedBytes, absent: transmittedErr != nil},
+		{name: "gluetun_port_forwarded", help: "Port forwarded by the VPN provider, 0 if none.",
+			kind: gauge, value: float64(h.openvpn.GetPortForwarded())},
+		{name: "gluetun_public_ip_changes_total", help: "Number of times the public IP address changed.",
+			kind: counter, value: float64(h.publicip.GetPublicIPChanges())},
+		{name: "gluetun_healthcheck_latency_seconds", help: "Duration of the last successful health check.",
+			kind: gauge, value: h.healthchecker.GetLatency().Seconds()},
+		{name: "gluetun_healthy", help: "Whether the health checks report the connection as healthy.",
+			kind: gauge, value: healthy},
+		{name: "gluetun_healthcheck_probes_total", help: "Number of health check runs.",
+			kind: counter, value: float64(healthStats.Probes)},
+		{name: "gluetun_healthcheck_failures_total", help: "Number of failed health check runs.",
+			kind: counter, value: float64(healthStats.Failures)},
+		{name: "gluetun_healthcheck_latency_average_seconds",
+			help: "Average duration of the recent successful health checks.",
+			kind: gauge, value: healthStats.AverageLatency.Seconds(), absent: healthStats.AverageLatency == 0},
+		{name: "gluetun_dns_queries_total", help: "Number of DNS queries answered.",
+			kind: counter, value: float64(dnsStats.Queries)},
+		{name: "gluetun_dns_cache_hits_total", help: "Number of DNS queries answered from the cache.",
+			kind: counter, value: float64(dnsStats.CacheHits)},
+		{name: "gluetun_dns_cache_misses_total", help: "Number of DNS queries missing the cache.",
+			kind: counter, value: float64(dnsStats.CacheMisses)},
+		{name: "gluetun_dns_cache_entries", help: "Number of entries in the DNS cache.",
+			kind: gauge, value: float64(dnsStats.CacheEntries)},
+		{name: "gluetun_dns_blocked_total", help: "Number of DNS queries blocked.",
+			kind: counter, value: float64(dnsStats.Blocked)},
+		{name: "gluetun_dns_failed_total", help: "Number of DNS queries failing to resolve.",
+			kind: counter, value: float64(dnsStats.Failed)},
+		{name: "gluetun_dns_bogus_total", help: "Number of DNS responses failing DNSSEC validation.",
+			kind: counter, value: float64(dnsStats.Bogus)},
+		{name: "gluetun_dns_upstream_latency_seconds", help: "Average duration of the upstream DNS queries.",
+			kind: gauge, value: upstreamLatency.Seconds(), absent: upstreamLatencyAbsent},
+		{name: "gluetun_httpproxy_requests_total", help: "Number of requests to the HTTP proxy.",
+			kind: counter, value: float64(httpProxyStats.Requests)},
+		{name: "gluetun_httpproxy_denied_total", help: "Number of HTTP proxy requests denied.",
+			kind: counter, value: float64(httpProxyStats.Denied)},
+		{name: "gluetun_httpproxy_failed_total", help: "Number of HTTP proxy requests failing.",
+			kind: counter, value: float64(httpProxyStats.Failed)},
+		{name: "gluetun_httpproxy_sent_bytes_total", help: "Bytes sent to destinations by the HTTP proxy.",
+			kind: counter, value: float64(httpProxyStats.BytesSent)},
+		{name: "gluetun_httpproxy_received_bytes_total",
+			help: "Bytes received from destinations by the HTTP proxy.",
+			kind: counter, value: float64(httpProxyStats.BytesReceived)},
+	}
+
+	return writeMetrics(w, metrics)
+}
+
+func writeMetrics(w io.Writer, metrics []metric) (err error) {
+	sb := strings.Builder{}
+	for _, m := range metrics {
+		if m.absent {
+			continue
+		}
+		fmt.Fprintf(&sb, "# HELP %s %s\n", m.name, m.help)
+		fmt.Fprintf(&sb, "# TYPE %s %s\n", m.name, m.kind)
+		fmt.Fprintf(&sb, "%s %s\n", m.name, strconv.FormatFloat(m.value, 'g', -1, 64))
+	}
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
+
+// interfaceStatistic reads a statistic of the VPN network interface,
+// such as rx_bytes, from the sysfs file system.
+func (h *metricsHandler) interfaceStatistic(name string) (value float64, err error) {
+	path := filepath.Join(h.statisticsDir, h.vpnInterface, "statistics", name)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64) //nolint:gomnd
+	if err != nil {
+		return 0, err
+	}
+	return float64(n), nil
+}
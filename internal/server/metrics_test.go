@@ -0,0 +1,31 @@
+package server
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeMetrics(t *testing.T) {
+	t.Parallel()
+
+	metrics := []metric{
+		{name: "a_total", help: "Help A.", kind: "counter", value: 12345678},
+		{name: "b", help: "Help B.", kind: "gauge", value: 0.25},
+		{name: "c", help: "Help C.", kind: "gauge", absent: true},
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	err := writeMetrics(buffer, metrics)
+	require.NoError(t, err)
+
+	const expected = "# HELP a_total Help A.\n" +
+		"# TYPE a_total counter\n" +
+		"a_total 1.2345678e+07\n" +
+		"# HELP b Help B.\n" +
+		"# TYPE b gauge\n" +
+		"b 0.25\n"
+	assert.Equal(t, expected, buffer.String())
+}
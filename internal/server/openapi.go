@@ -0,0 +1,19 @@
+package server
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPISpec is the OpenAPI specification of the v1 API, which
+// must be updated together with the v1 handlers and pkg/client.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+func (h *handlerV1) getOpenAPISpec(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(openAPISpec); err != nil {
+		h.logger.Warn(err)
+	}
+}
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_openAPISpec(t *testing.T) {
+	t.Parallel()
+
+	var spec struct {
+		OpenAPI string                     `json:"openapi"`
+		Paths   map[string]json.RawMessage `json:"paths"`
+	}
+	err := json.Unmarshal(openAPISpec, &spec)
+	require.NoError(t, err)
+
+	assert.Equal(t, "3.0.3", spec.OpenAPI)
+	for _, path := range []string{"/version", "/settings", "/settings/reload", "/logs", "/openvpn/status", "/vpn/settings", "/vpn/pause",
+		"/servers", "/portforward", "/portforward/renew", "/dns/stats", "/dns/restart",
+		"/dns/blocklists/categories", "/updater/run", "/publicip/ip", "/publicip/history", "/publicip/comparison", "/firewall/ports", "/health/history"} {
+		assert.Contains(t, spec.Paths, path)
+	}
+}
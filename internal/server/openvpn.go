@@ -33,6 +33,13 @@ func (h *openvpnHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		default:
 			http.Error(w, "", http.StatusNotFound)
 		}
+	case "/state":
+		switch r.Method {
+		case http.MethodGet:
+			h.getConnectionState(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
 	case "/settings":
 		switch r.Method {
 		case http.MethodGet:
@@ -47,6 +54,13 @@ func (h *openvpnHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		default:
 			http.Error(w, "", http.StatusNotFound)
 		}
+	case "/switch":
+		switch r.Method {
+		case http.MethodPut:
+			h.switchServer(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
 	default:
 		http.Error(w, "", http.StatusNotFound)
 	}
@@ -88,10 +102,18 @@ func (h *openvpnHandler) setStatus(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *openvpnHandler) switchServer(w http.ResponseWriter) {
+	outcome := h.looper.SwitchServer()
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.logger.Warn(err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
 func (h *openvpnHandler) getSettings(w http.ResponseWriter) {
-	settings := h.looper.GetSettings()
-	settings.User = "redacted"
-	settings.Password = "redacted"
+	settings := redactOpenVPN(h.looper.GetSettings())
 	encoder := json.NewEncoder(w)
 	if err := encoder.Encode(settings); err != nil {
 		h.logger.Warn(err)
@@ -110,3 +132,13 @@ func (h *openvpnHandler) getPortForwarded(w http.ResponseWriter) {
 		return
 	}
 }
+
+func (h *openvpnHandler) getConnectionState(w http.ResponseWriter) {
+	connectionState := h.looper.GetConnectionState()
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(connectionState); err != nil {
+		h.logger.Warn(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/openvpn"
+	"github.com/qdm12/golibs/logging"
+)
+
+func newPortForwardHandler(looper openvpn.Looper, logger logging.Logger) http.Handler {
+	return &portForwardHandler{
+		looper: looper,
+		logger: logger,
+	}
+}
+
+type portForwardHandler struct {
+	looper openvpn.Looper
+	logger logging.Logger
+}
+
+func (h *portForwardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/portforward")
+	switch r.RequestURI {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			h.getStatus(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/renew":
+		switch r.Method {
+		case http.MethodPost:
+			h.renew(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/events":
+		switch r.Method {
+		case http.MethodGet:
+			h.streamEvents(w, r)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	default:
+		http.Error(w, "", http.StatusNotFound)
+	}
+}
+
+func (h *portForwardHandler) getStatus(w http.ResponseWriter) {
+	status := h.looper.GetPortForwardStatus()
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(status); err != nil {
+		h.logger.Warn(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// renew requests a new port forwarded and responds without waiting
+// for it, which can be obtained from the status or the events.
+func (h *portForwardHandler) renew(w http.ResponseWriter) {
+	err := h.looper.RenewPortForward()
+	switch {
+	case errors.Is(err, openvpn.ErrPortForwardingDisabled),
+		errors.Is(err, openvpn.ErrPortForwardingNotRunning):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: "renewing"}); err != nil {
+		h.logger.Warn(err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+// streamEvents streams the status of the port forwarded as server-sent
+// events, first with the current status and then on each change,
+// until the client disconnects.
+func (h *portForwardHandler) streamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	updates, unsubscribe := h.looper.SubscribePortForward()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	status := h.looper.GetPortForwardStatus()
+	for {
+		data, err := json.Marshal(status)
+		if err != nil {
+			h.logger.Warn(err)
+			return
+		}
+		if _, err := fmt.Fprintf(w, "event: portforward\ndata: %s\n\n", data); err != nil {
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case status = <-updates:
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/publicip"
 	"github.com/qdm12/golibs/logging"
 )
@@ -33,22 +34,67 @@ func (h *publicIPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		default:
 			http.Error(w, "", http.StatusNotFound)
 		}
+	case "/history":
+		switch r.Method {
+		case http.MethodGet:
+			h.getHistory(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/comparison":
+		switch r.Method {
+		case http.MethodGet:
+			h.getComparison(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
 	default:
 		http.Error(w, "", http.StatusNotFound)
 	}
 }
 
 type publicIPWrapper struct {
-	PublicIP string `json:"public_ip"`
+	PublicIP   string `json:"public_ip"`
+	PublicIPv6 string `json:"public_ipv6,omitempty"`
+	models.PublicIPInfo
 }
 
 func (h *publicIPHandler) getPublicIP(w http.ResponseWriter) {
 	publicIP := h.looper.GetPublicIP()
 	encoder := json.NewEncoder(w)
-	data := publicIPWrapper{PublicIP: publicIP.String()}
+	data := publicIPWrapper{
+		PublicIP:     publicIP.String(),
+		PublicIPInfo: h.looper.GetPublicIPInfo(),
+	}
+	if publicIPv6 := h.looper.GetPublicIPv6(); publicIPv6 != nil {
+		data.PublicIPv6 = publicIPv6.String()
+	}
 	if err := encoder.Encode(data); err != nil {
 		h.logger.Warn(err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 }
+
+type publicIPHistoryWrapper struct {
+	Records []models.PublicIPRecord `json:"records"`
+}
+
+func (h *publicIPHandler) getHistory(w http.ResponseWriter) {
+	data := publicIPHistoryWrapper{Records: h.looper.GetPublicIPHistory()}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.logger.Warn(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *publicIPHandler) getComparison(w http.ResponseWriter) {
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(h.looper.GetPublicIPComparison()); err != nil {
+		h.logger.Warn(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/qdm12/golibs/logging"
+)
+
+func withRateLimitMiddleware(childHandler http.Handler, perMinute int,
+	logger logging.Logger) *rateLimitMiddleware {
+	return &rateLimitMiddleware{
+		childHandler: childHandler,
+		limiter:      newRateLimiter(perMinute, time.Now),
+		logger:       logger,
+	}
+}
+
+// rateLimitMiddleware limits the number of requests of each
+// client IP address, answering 429 to requests over the limit.
+type rateLimitMiddleware struct {
+	childHandler http.Handler
+	limiter      *rateLimiter
+	logger       logging.Logger
+}
+
+func (m *rateLimitMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	client := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		client = host
+	}
+
+	allowed, retryAfter := m.limiter.allow(client)
+	if !allowed {
+		m.logger.Debug("rate limited request %s %s from %s", r.Method, r.RequestURI, client)
+		seconds := int(retryAfter.Seconds()) + 1
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+		return
+	}
+	m.childHandler.ServeHTTP(w, r)
+}
+
+// rateLimiter is a token bucket rate limiter per client,
+// allowing bursts of up to the number of requests per minute.
+type rateLimiter struct {
+	capacity float64
+	// interval is the time to refill one token.
+	interval time.Duration
+	buckets  map[string]*tokenBucket
+	// lastCleanup is the last time the full buckets were removed.
+	lastCleanup time.Time
+	timeNow     func() time.Time
+	mutex       sync.Mutex
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(perMinute int, timeNow func() time.Time) *rateLimiter {
+	return &rateLimiter{
+		capacity:    float64(perMinute),
+		interval:    time.Minute / time.Duration(perMinute),
+		buckets:     make(map[string]*tokenBucket),
+		lastCleanup: timeNow(),
+		timeNow:     timeNow,
+	}
+}
+
+// allow returns true if the client can make a request, or false
+// and the duration to wait before the next request is allowed.
+func (l *rateLimiter) allow(client string) (allowed bool, retryAfter time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.timeNow()
+	l.cleanup(now)
+
+	bucket, ok := l.buckets[client]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.capacity, last: now}
+		l.buckets[client] = bucket
+	}
+	bucket.tokens = l.refilled(bucket, now)
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return false, time.Duration((1 - bucket.tokens) * float64(l.interval))
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+func (l *rateLimiter) refilled(bucket *tokenBucket, now time.Time) (tokens float64) {
+	tokens = bucket.tokens + float64(now.Sub(bucket.last))/float64(l.interval)
+	if tokens > l.capacity {
+		tokens = l.capacity
+	}
+	return tokens
+}
+
+// cleanup removes the buckets refilled completely, at most once a
+// minute, to not keep a bucket for each client ever seen.
+func (l *rateLimiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < time.Minute {
+		return
+	}
+	l.lastCleanup = now
+	for client, bucket := range l.buckets {
+		if l.refilled(bucket, now) == l.capacity {
+			delete(l.buckets, client)
+		}
+	}
+}
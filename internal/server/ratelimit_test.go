@@ -0,0 +1,39 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_rateLimiter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	timeNow := func() time.Time { return now }
+	limiter := newRateLimiter(2, timeNow)
+
+	allowed, _ := limiter.allow("1.2.3.4")
+	assert.True(t, allowed)
+	allowed, _ = limiter.allow("1.2.3.4")
+	assert.True(t, allowed)
+	allowed, retryAfter := limiter.allow("1.2.3.4")
+	assert.False(t, allowed)
+	assert.Equal(t, 30*time.Second, retryAfter)
+
+	// other clients are not limited
+	allowed, _ = limiter.allow("5.6.7.8")
+	assert.True(t, allowed)
+
+	now = now.Add(30 * time.Second)
+	allowed, _ = limiter.allow("1.2.3.4")
+	assert.True(t, allowed)
+	allowed, _ = limiter.allow("1.2.3.4")
+	assert.False(t, allowed)
+
+	// full buckets are removed after a minute
+	now = now.Add(2 * time.Minute)
+	limiter.cleanup(now)
+	assert.Empty(t, limiter.buckets)
+}
@@ -3,14 +3,24 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
 	"net/http"
+	"os"
 	"sync"
 	"time"
 
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/dns"
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/qdm12/gluetun/internal/healthcheck"
+	"github.com/qdm12/gluetun/internal/httpproxy"
+	gluetunLogging "github.com/qdm12/gluetun/internal/logging"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/openvpn"
 	"github.com/qdm12/gluetun/internal/publicip"
+	"github.com/qdm12/gluetun/internal/reload"
 	"github.com/qdm12/gluetun/internal/updater"
 	"github.com/qdm12/golibs/logging"
 )
@@ -20,28 +30,83 @@ type Server interface {
 }
 
 type server struct {
-	address string
-	logger  logging.Logger
-	handler http.Handler
+	settings ListenSettings
+	logger   logging.Logger
+	handler  http.Handler
 }
 
-func New(address string, logEnabled bool, logger logging.Logger,
-	buildInfo models.BuildInformation,
-	openvpnLooper openvpn.Looper, unboundLooper dns.Looper,
-	updaterLooper updater.Looper, publicIPLooper publicip.Looper) Server {
+// ListenSettings contains settings for the listeners of the server.
+type ListenSettings struct {
+	// Address is the TCP listening address.
+	Address string
+	// TLS is true to serve HTTPS instead of HTTP on the TCP address.
+	TLS bool
+	// TLSCertFile and TLSKeyFile are the TLS certificate and key file
+	// paths. If they are empty, a self-signed certificate is used.
+	TLSCertFile string
+	TLSKeyFile  string
+	// UnixSocket is the Unix domain socket file path to listen on
+	// in addition to the TCP address, and is empty to disable it.
+	UnixSocket string
+}
+
+// AccessSettings contains settings to access the server
+// from browsers and to limit the requests of clients.
+type AccessSettings struct {
+	// CORSOrigins are the origins allowed for cross origin
+	// requests, where * allows all origins.
+	CORSOrigins []string
+	// RateLimit is the maximum number of requests per minute
+	// per client IP address, and is 0 to disable rate limiting.
+	RateLimit int
+}
+
+// FirewallSettings contains settings for the firewall endpoints.
+type FirewallSettings struct {
+	// VPNInterface and LANInterface are the network interfaces
+	// to open ports on for the vpn and lan interface values.
+	VPNInterface string
+	LANInterface string
+}
+
+// New creates the control server. The log buffer contains the last
+// log lines, the settings are the settings read at start, and the
+// healthchecker is used for the metrics, only if metricsEnabled is true.
+func New(listenSettings ListenSettings, logEnabled, metricsEnabled bool,
+	logger logging.Logger, logBuffer *gluetunLogging.Buffer,
+	buildInfo models.BuildInformation, reloader reload.Reloader,
+	openvpnLooper openvpn.Looper, dnsLooper dns.Looper,
+	updaterLooper updater.Looper, publicIPLooper publicip.Looper,
+	httpProxyLooper httpproxy.Looper, healthchecker healthcheck.Server,
+	fw firewall.Configurator, firewallSettings FirewallSettings,
+	authSettings AuthSettings, accessSettings AccessSettings,
+	auditSettings AuditSettings) Server {
 	serverLogger := logger.NewChild(logging.SetPrefix("http server: "))
-	handler := newHandler(serverLogger, logEnabled, buildInfo,
-		openvpnLooper, unboundLooper, updaterLooper, publicIPLooper)
+	handler := newHandler(serverLogger, logEnabled, metricsEnabled, logBuffer, buildInfo, reloader,
+		openvpnLooper, dnsLooper, updaterLooper, publicIPLooper, httpProxyLooper, healthchecker,
+		fw, firewallSettings, authSettings, accessSettings, auditSettings)
 	return &server{
-		address: address,
-		logger:  serverLogger,
-		handler: handler,
+		settings: listenSettings,
+		logger:   serverLogger,
+		handler:  handler,
 	}
 }
 
 func (s *server) Run(ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
-	server := http.Server{Addr: s.address, Handler: s.handler}
+
+	listeners, err := s.listen()
+	if err != nil {
+		s.logger.Error(err)
+		return
+	}
+
+	server := http.Server{
+		Handler: s.handler,
+		// Request contexts are canceled on shutdown so long
+		// lived requests such as event streams return.
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
 	go func() {
 		<-ctx.Done()
 		s.logger.Warn("context canceled: shutting down")
@@ -52,10 +117,64 @@ func (s *server) Run(ctx context.Context, wg *sync.WaitGroup) {
 			s.logger.Error("failed shutting down: %s", err)
 		}
 	}()
-	s.logger.Info("listening on %s", s.address)
-	err := server.ListenAndServe()
-	if err != nil && ctx.Err() != context.Canceled {
-		s.logger.Error(err)
+
+	serveWg := &sync.WaitGroup{}
+	for _, listener := range listeners {
+		serveWg.Add(1)
+		go func(listener net.Listener) {
+			defer serveWg.Done()
+			s.logger.Info("listening on %s", listener.Addr())
+			err := server.Serve(listener)
+			if err != nil && err != http.ErrServerClosed {
+				s.logger.Error(err)
+			}
+		}(listener)
 	}
+	serveWg.Wait()
 	s.logger.Warn("shut down")
 }
+
+// listen returns the TCP listener, wrapped with TLS if enabled,
+// and the Unix socket listener if enabled.
+func (s *server) listen() (listeners []net.Listener, err error) {
+	listener, err := net.Listen("tcp", s.settings.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.settings.TLS {
+		certificate, err := loadCertificate(s.settings.TLSCertFile, s.settings.TLSKeyFile,
+			constants.ControlServerCertificate, constants.ControlServerKey)
+		if err != nil {
+			_ = listener.Close()
+			return nil, fmt.Errorf("cannot load TLS certificate: %w", err)
+		}
+		listener = tls.NewListener(listener, &tls.Config{
+			Certificates: []tls.Certificate{certificate},
+			MinVersion:   tls.VersionTLS12,
+		})
+	}
+	listeners = append(listeners, listener)
+
+	if s.settings.UnixSocket != "" {
+		// remove the socket file left from a previous run
+		if err := os.Remove(s.settings.UnixSocket); err != nil && !os.IsNotExist(err) {
+			_ = listener.Close()
+			return nil, err
+		}
+		unixListener, err := net.Listen("unix", s.settings.UnixSocket)
+		if err != nil {
+			_ = listener.Close()
+			return nil, err
+		}
+		const socketPerm = 0660
+		if err := os.Chmod(s.settings.UnixSocket, socketPerm); err != nil {
+			_ = listener.Close()
+			_ = unixListener.Close()
+			return nil, err
+		}
+		listeners = append(listeners, unixListener)
+	}
+
+	return listeners, nil
+}
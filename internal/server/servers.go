@@ -0,0 +1,157 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/openvpn"
+	"github.com/qdm12/golibs/logging"
+)
+
+func newServersHandler(looper openvpn.Looper, logger logging.Logger) http.Handler {
+	return &serversHandler{
+		looper: looper,
+		logger: logger,
+	}
+}
+
+type serversHandler struct {
+	looper openvpn.Looper
+	logger logging.Logger
+}
+
+func (h *serversHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "", http.StatusNotFound)
+		return
+	}
+	h.getServers(w, r)
+}
+
+// serverFilterKeys are the query parameters filtering the
+// servers on the JSON field of the same name.
+var serverFilterKeys = [...]string{"country", "region", "city", "hostname"} //nolint:gochecknoglobals
+
+// getServers writes the servers of the provider given in the query,
+// or of the current provider, filtered by the query parameters.
+// Each filter parameter can be repeated or contain comma separated values.
+func (h *serversHandler) getServers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	providerName := strings.ToLower(query.Get("provider"))
+	switch providerName {
+	case "":
+		providerName = h.looper.GetSettings().Provider.Name
+	case "pia":
+		providerName = constants.PrivateInternetAccess
+	}
+
+	servers, ok := providerServers(h.looper.GetServers(), providerName)
+	if !ok {
+		http.Error(w, "unknown provider: "+providerName, http.StatusBadRequest)
+		return
+	}
+
+	// servers are filtered on their JSON fields to support all providers
+	b, err := json.Marshal(servers)
+	if err != nil {
+		h.logger.Warn(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	var rawServers []map[string]json.RawMessage
+	if err := json.Unmarshal(b, &rawServers); err != nil {
+		h.logger.Warn(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	data := serversWrapper{
+		Provider: providerName,
+		Servers:  filterServers(rawServers, query),
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.logger.Warn(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func filterServers(servers []map[string]json.RawMessage,
+	query url.Values) (filtered []map[string]json.RawMessage) {
+	filtered = make([]map[string]json.RawMessage, 0, len(servers))
+	for _, server := range servers {
+		if serverMatches(server, query) {
+			filtered = append(filtered, server)
+		}
+	}
+	return filtered
+}
+
+func serverMatches(server map[string]json.RawMessage, query url.Values) (match bool) {
+	for _, key := range serverFilterKeys {
+		var possibilities []string
+		for _, value := range query[key] {
+			possibilities = append(possibilities, strings.Split(value, ",")...)
+		}
+		if len(possibilities) == 0 {
+			continue
+		}
+
+		var value string
+		if err := json.Unmarshal(server[key], &value); err != nil {
+			return false // field missing or not a string
+		}
+		matched := false
+		for _, possibility := range possibilities {
+			if strings.EqualFold(value, strings.TrimSpace(possibility)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// providerServers returns the servers of the provider,
+// and false if the provider is unknown.
+func providerServers(allServers models.AllServers, provider string) ( //nolint:gocyclo
+	servers interface{}, ok bool) {
+	switch provider {
+	case constants.Cyberghost:
+		return allServers.Cyberghost.Servers, true
+	case constants.Fastestvpn:
+		return allServers.Fastestvpn.Servers, true
+	case constants.HideMyAss:
+		return allServers.HideMyAss.Servers, true
+	case constants.Mullvad:
+		return allServers.Mullvad.Servers, true
+	case constants.Nordvpn:
+		return allServers.Nordvpn.Servers, true
+	case constants.Privado:
+		return allServers.Privado.Servers, true
+	case constants.PrivateInternetAccess:
+		return allServers.Pia.Servers, true
+	case constants.Privatevpn:
+		return allServers.Privatevpn.Servers, true
+	case constants.Purevpn:
+		return allServers.Purevpn.Servers, true
+	case constants.Surfshark:
+		return allServers.Surfshark.Servers, true
+	case constants.Torguard:
+		return allServers.Torguard.Servers, true
+	case constants.Vyprvpn:
+		return allServers.Vyprvpn.Servers, true
+	case constants.Windscribe:
+		return allServers.Windscribe.Servers, true
+	default:
+		return nil, false
+	}
+}
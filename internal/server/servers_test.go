@@ -0,0 +1,58 @@
+package server
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_filterServers(t *testing.T) {
+	t.Parallel()
+
+	servers := []map[string]json.RawMessage{
+		{"country": json.RawMessage(`"Canada"`), "city": json.RawMessage(`"Montreal"`)},
+		{"country": json.RawMessage(`"Canada"`), "city": json.RawMessage(`"Toronto"`)},
+		{"country": json.RawMessage(`"France"`), "city": json.RawMessage(`"Paris"`)},
+		{"region": json.RawMessage(`"Europe"`)},
+	}
+
+	testCases := map[string]struct {
+		query    url.Values
+		filtered []map[string]json.RawMessage
+	}{
+		"no filter": {
+			filtered: servers,
+		},
+		"case insensitive": {
+			query:    url.Values{"country": {"canada"}},
+			filtered: servers[:2],
+		},
+		"comma separated and repeated": {
+			query:    url.Values{"city": {"montreal, paris", "london"}},
+			filtered: []map[string]json.RawMessage{servers[0], servers[2]},
+		},
+		"multiple keys": {
+			query:    url.Values{"country": {"canada"}, "city": {"toronto"}},
+			filtered: servers[1:2],
+		},
+		"key missing": {
+			query:    url.Values{"region": {"europe"}},
+			filtered: servers[3:],
+		},
+		"no match": {
+			query:    url.Values{"country": {"germany"}},
+			filtered: []map[string]json.RawMessage{},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			filtered := filterServers(servers, testCase.query)
+			assert.Equal(t, testCase.filtered, filtered)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/dns"
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/qdm12/gluetun/internal/openvpn"
+	"github.com/qdm12/gluetun/internal/publicip"
+	"github.com/qdm12/gluetun/internal/reload"
+	"github.com/qdm12/gluetun/internal/updater"
+	"github.com/qdm12/golibs/logging"
+)
+
+func newSettingsHandler(reloader reload.Reloader,
+	openvpnLooper openvpn.Looper, dnsLooper dns.Looper,
+	updaterLooper updater.Looper, publicIPLooper publicip.Looper,
+	fw firewall.Configurator, logger logging.Logger) http.Handler {
+	return &settingsHandler{
+		reloader:       reloader,
+		openvpnLooper:  openvpnLooper,
+		dnsLooper:      dnsLooper,
+		updaterLooper:  updaterLooper,
+		publicIPLooper: publicIPLooper,
+		fw:             fw,
+		logger:         logger,
+	}
+}
+
+type settingsHandler struct {
+	reloader       reload.Reloader
+	openvpnLooper  openvpn.Looper
+	dnsLooper      dns.Looper
+	updaterLooper  updater.Looper
+	publicIPLooper publicip.Looper
+	fw             firewall.Configurator
+	logger         logging.Logger
+}
+
+func (h *settingsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/settings")
+	switch r.RequestURI {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			h.getSettings(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/reload":
+		switch r.Method {
+		case http.MethodPost:
+			h.reload(w, r)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	default:
+		http.Error(w, "", http.StatusNotFound)
+	}
+}
+
+// getSettings writes the settings in use, with the settings
+// changeable at runtime taken from their loop, and with
+// the secrets redacted.
+func (h *settingsHandler) getSettings(w http.ResponseWriter) {
+	settings := h.reloader.GetSettings()
+	settings.OpenVPN = h.openvpnLooper.GetSettings()
+	settings.DNS = h.dnsLooper.GetSettings()
+	settings.Updater = h.updaterLooper.GetSettings()
+	settings.PublicIP = h.publicIPLooper.GetSettings()
+	settings.Firewall.Enabled = h.fw.GetEnabled()
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(redactSettings(settings)); err != nil {
+		h.logger.Warn(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// reload reloads the settings which can be changed without restarting.
+func (h *settingsHandler) reload(w http.ResponseWriter, r *http.Request) {
+	outcome, err := h.reloader.Reload(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.logger.Warn(err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+// redact returns the secret given redacted, or the
+// empty string if the secret is not set.
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return "redacted"
+}
+
+// redactSettings returns a copy of the settings with the secrets
+// redacted. Secrets not encoded to JSON are not redacted.
+func redactSettings(settings configuration.Settings) configuration.Settings {
+	settings.OpenVPN = redactOpenVPN(settings.OpenVPN)
+	settings.Wireguard.PrivateKey = redact(settings.Wireguard.PrivateKey)
+	settings.Wireguard.PreSharedKey = redact(settings.Wireguard.PreSharedKey)
+	settings.HTTPProxy.Password = redact(settings.HTTPProxy.Password)
+	settings.HTTPProxy.Users = redactHTTPProxyUsers(settings.HTTPProxy.Users)
+	settings.ShadowSocks.Password = redact(settings.ShadowSocks.Password)
+	settings.ControlServer.APIKey = redact(settings.ControlServer.APIKey)
+	settings.ControlServer.ReadOnlyAPIKey = redact(settings.ControlServer.ReadOnlyAPIKey)
+	settings.ControlServer.Password = redact(settings.ControlServer.Password)
+	settings.Health.PingURL = redact(settings.Health.PingURL)
+	settings.PublicIP.InfoAPIKey = redact(settings.PublicIP.InfoAPIKey)
+	settings.PublicIP.ChangeWebhook = redact(settings.PublicIP.ChangeWebhook)
+	settings.Health.PingFailURL = redact(settings.Health.PingFailURL)
+	return settings
+}
+
+func redactHTTPProxyUsers(users []configuration.HTTPProxyUser) []configuration.HTTPProxyUser {
+	if len(users) == 0 {
+		return users
+	}
+	redacted := make([]configuration.HTTPProxyUser, len(users))
+	for i, user := range users {
+		user.Password = redact(user.Password)
+		redacted[i] = user
+	}
+	return redacted
+}
+
+func redactOpenVPN(settings configuration.OpenVPN) configuration.OpenVPN {
+	settings.User = redact(settings.User)
+	settings.Password = redact(settings.Password)
+	settings.Provider.PortForwarding.UpCommand = redact(settings.Provider.PortForwarding.UpCommand)
+	settings.Provider.PortForwarding.TorrentClient.Password = redact(
+		settings.Provider.PortForwarding.TorrentClient.Password)
+	if settings.Backup != nil {
+		backup := redactOpenVPN(*settings.Backup)
+		settings.Backup = &backup
+	}
+	return settings
+}
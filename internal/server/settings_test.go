@@ -0,0 +1,62 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_redactSettings(t *testing.T) {
+	t.Parallel()
+
+	settings := configuration.Settings{
+		OpenVPN: configuration.OpenVPN{
+			User:     "user",
+			Password: "password",
+			Backup: &configuration.OpenVPN{
+				User: "backup",
+			},
+		},
+		Wireguard: configuration.Wireguard{
+			PrivateKey: "key",
+		},
+		HTTPProxy: configuration.HTTPProxy{
+			Users: []configuration.HTTPProxyUser{
+				{Name: "a", Password: "password", Ports: []uint16{443}},
+			},
+		},
+		ControlServer: configuration.ControlServer{
+			Port:   8000,
+			APIKey: "key",
+		},
+	}
+
+	redacted := redactSettings(settings)
+
+	expected := configuration.Settings{
+		OpenVPN: configuration.OpenVPN{
+			User:     "redacted",
+			Password: "redacted",
+			Backup: &configuration.OpenVPN{
+				User: "redacted",
+			},
+		},
+		Wireguard: configuration.Wireguard{
+			PrivateKey: "redacted",
+		},
+		HTTPProxy: configuration.HTTPProxy{
+			Users: []configuration.HTTPProxyUser{
+				{Name: "a", Password: "redacted", Ports: []uint16{443}},
+			},
+		},
+		ControlServer: configuration.ControlServer{
+			Port:   8000,
+			APIKey: "redacted",
+		},
+	}
+	assert.Equal(t, expected, redacted)
+	// the settings given are not modified
+	assert.Equal(t, "backup", settings.OpenVPN.Backup.User)
+	assert.Equal(t, "password", settings.HTTPProxy.Users[0].Password)
+}
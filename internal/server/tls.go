@@ -0,0 +1,89 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"time"
+)
+
+// selfSignedValidity is the validity duration of the
+// self-signed certificate generated.
+const selfSignedValidity = 10 * 365 * 24 * time.Hour
+
+// loadCertificate loads the TLS certificate and key files given, or the
+// self-signed certificate and key files if the files given are empty.
+// The self-signed certificate and key are generated if they do not exist.
+func loadCertificate(certFile, keyFile, selfSignedCertFile, selfSignedKeyFile string) (
+	certificate tls.Certificate, err error) {
+	if certFile != "" {
+		return tls.LoadX509KeyPair(certFile, keyFile)
+	}
+
+	certificate, err = tls.LoadX509KeyPair(selfSignedCertFile, selfSignedKeyFile)
+	if err == nil {
+		return certificate, nil
+	} else if !os.IsNotExist(err) {
+		return certificate, err
+	}
+
+	certPEM, keyPEM, err := generateSelfSigned(time.Now())
+	if err != nil {
+		return certificate, fmt.Errorf("cannot generate self-signed certificate: %w", err)
+	}
+	const certPerm, keyPerm = 0644, 0600
+	if err := ioutil.WriteFile(selfSignedCertFile, certPEM, certPerm); err != nil {
+		return certificate, err
+	}
+	if err := ioutil.WriteFile(selfSignedKeyFile, keyPEM, keyPerm); err != nil {
+		return certificate, err
+	}
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
+// generateSelfSigned generates a self-signed certificate
+// and its ECDSA key, both PEM encoded.
+func generateSelfSigned(now time.Time) (certPEM, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	const serialNumberBits = 128
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialNumberBits))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: "gluetun"},
+		DNSNames:              []string{"gluetun", "localhost"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"crypto/x509"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_loadCertificate(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "controlserver.crt")
+	keyFile := filepath.Join(dir, "controlserver.key")
+
+	generated, err := loadCertificate("", "", certFile, keyFile)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(generated.Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, "gluetun", leaf.Subject.CommonName)
+
+	// the self-signed certificate generated is reused
+	loaded, err := loadCertificate("", "", certFile, keyFile)
+	require.NoError(t, err)
+	assert.Equal(t, generated.Certificate, loaded.Certificate)
+
+	loaded, err = loadCertificate(certFile, keyFile, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, generated.Certificate, loaded.Certificate)
+}
@@ -2,9 +2,13 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strings"
 
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/updater"
 	"github.com/qdm12/golibs/logging"
 )
@@ -35,6 +39,13 @@ func (h *updaterHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		default:
 			http.Error(w, "", http.StatusNotFound)
 		}
+	case "/run":
+		switch r.Method {
+		case http.MethodPost:
+			h.runUpdate(w, r)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
 	default:
 		http.Error(w, "", http.StatusNotFound)
 	}
@@ -75,3 +86,41 @@ func (h *updaterHandler) setStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+type updaterRunWrapper struct {
+	// Providers are the VPN providers to update, and
+	// default to the providers set in the settings.
+	Providers []string `json:"providers"`
+}
+
+type updateResultsWrapper struct {
+	Results []models.ServersUpdate `json:"results"`
+}
+
+// runUpdate updates the servers and responds once done,
+// which can take several minutes.
+func (h *updaterHandler) runUpdate(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var data updaterRunWrapper
+	if err := decoder.Decode(&data); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	results, err := h.looper.RunUpdate(r.Context(), data.Providers)
+	switch {
+	case errors.Is(err, configuration.ErrUpdaterProviderUnknown):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(updateResultsWrapper{Results: results}); err != nil {
+		h.logger.Warn(err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/openvpn"
+	"github.com/qdm12/golibs/logging"
+)
+
+func newVPNHandler(looper openvpn.Looper, logger logging.Logger) http.Handler {
+	return &vpnHandler{
+		looper: looper,
+		logger: logger,
+	}
+}
+
+type vpnHandler struct {
+	looper openvpn.Looper
+	logger logging.Logger
+}
+
+func (h *vpnHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/vpn")
+	switch r.RequestURI {
+	case "/settings":
+		switch r.Method {
+		case http.MethodGet:
+			h.getSettings(w)
+		case http.MethodPut:
+			h.setSettings(w, r)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/pause":
+		switch r.Method {
+		case http.MethodPost:
+			h.pause(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/resume":
+		switch r.Method {
+		case http.MethodPost:
+			h.resume(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	default:
+		http.Error(w, "", http.StatusNotFound)
+	}
+}
+
+func (h *vpnHandler) getSettings(w http.ResponseWriter) {
+	settings := h.looper.GetSettings()
+	selection := settings.Provider.ServerSelection
+	data := vpnSettingsWrapper{
+		Provider:  settings.Provider.Name,
+		Countries: selection.Countries,
+		Regions:   selection.Regions,
+		Cities:    selection.Cities,
+		Hostnames: selection.Hostnames,
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.logger.Warn(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// setSettings sets the VPN provider and the server filters, and the
+// OpenVPN loop reconnects to a server matching them.
+func (h *vpnHandler) setSettings(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var data vpnSettingsWrapper
+	if err := decoder.Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	outcome, err := setServerFilters(h.looper, data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.logger.Warn(err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+var errProviderChangePortForwarding = errors.New("cannot change the VPN provider with port forwarding enabled")
+
+// setServerFilters sets the VPN provider and the server filters given.
+// The other server selection settings are kept, unless the provider
+// changes. An empty provider keeps the current provider.
+func setServerFilters(looper openvpn.Looper, data vpnSettingsWrapper) (outcome string, err error) {
+	settings := looper.GetSettings()
+	selection := settings.Provider.ServerSelection
+	providerName := strings.ToLower(data.Provider)
+	switch providerName {
+	case "", settings.Provider.Name:
+		providerName = settings.Provider.Name
+	default:
+		if settings.Provider.PortForwarding.Enabled {
+			return "", errProviderChangePortForwarding
+		}
+		// provider specific settings do not apply to the new provider
+		selection = configuration.ServerSelection{
+			Protocol: selection.Protocol,
+			Latency:  selection.Latency,
+		}
+	}
+	selection.Countries = data.Countries
+	selection.Regions = data.Regions
+	selection.Cities = data.Cities
+	selection.Hostnames = data.Hostnames
+
+	return looper.SetServerSelection(providerName, selection)
+}
+
+// pause stops the tunnel while the firewall keeps blocking
+// traffic, without stopping the container.
+func (h *vpnHandler) pause(w http.ResponseWriter) {
+	outcome, err := h.looper.Pause()
+	switch {
+	case errors.Is(err, openvpn.ErrPauseFirewallDisabled):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.logger.Warn(err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *vpnHandler) resume(w http.ResponseWriter) {
+	outcome, err := h.looper.Resume()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.logger.Warn(err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
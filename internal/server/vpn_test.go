@@ -0,0 +1,168 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/openvpn"
+	"github.com/qdm12/gluetun/internal/openvpn/mock_openvpn"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_vpnHandler(t *testing.T) {
+	t.Parallel()
+
+	settings := configuration.OpenVPN{}
+	settings.Provider.Name = "mullvad"
+	settings.Provider.ServerSelection = configuration.ServerSelection{
+		Protocol:  "udp",
+		Countries: []string{"sweden"},
+		Owned:     true,
+	}
+	portForwardSettings := settings
+	portForwardSettings.Provider.PortForwarding.Enabled = true
+
+	testCases := map[string]struct {
+		method       string
+		path         string
+		body         string
+		prepareMocks func(looper *mock_openvpn.MockLooper)
+		statusCode   int
+		responseBody string
+	}{
+		"get settings": {
+			method: http.MethodGet,
+			path:   "/vpn/settings",
+			prepareMocks: func(looper *mock_openvpn.MockLooper) {
+				looper.EXPECT().GetSettings().Return(settings)
+			},
+			statusCode: http.StatusOK,
+			responseBody: `{"provider":"mullvad","countries":["sweden"],` +
+				`"regions":null,"cities":null,"hostnames":null}` + "\n",
+		},
+		"malformed settings": {
+			method:       http.MethodPut,
+			path:         "/vpn/settings",
+			body:         "{",
+			statusCode:   http.StatusBadRequest,
+			responseBody: "unexpected EOF\n",
+		},
+		"set server filters": {
+			method: http.MethodPut,
+			path:   "/vpn/settings",
+			body:   `{"cities":["gothenburg"]}`,
+			prepareMocks: func(looper *mock_openvpn.MockLooper) {
+				looper.EXPECT().GetSettings().Return(settings)
+				looper.EXPECT().SetServerSelection("mullvad", configuration.ServerSelection{
+					Protocol: "udp",
+					Cities:   []string{"gothenburg"},
+					Owned:    true,
+				}).Return("switching server", nil)
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"outcome":"switching server"}` + "\n",
+		},
+		"change provider": {
+			method: http.MethodPut,
+			path:   "/vpn/settings",
+			body:   `{"provider":"Surfshark","countries":["france"]}`,
+			prepareMocks: func(looper *mock_openvpn.MockLooper) {
+				looper.EXPECT().GetSettings().Return(settings)
+				looper.EXPECT().SetServerSelection("surfshark", configuration.ServerSelection{
+					Protocol:  "udp",
+					Countries: []string{"france"},
+				}).Return("switching server", nil)
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"outcome":"switching server"}` + "\n",
+		},
+		"change provider with port forwarding": {
+			method: http.MethodPut,
+			path:   "/vpn/settings",
+			body:   `{"provider":"surfshark"}`,
+			prepareMocks: func(looper *mock_openvpn.MockLooper) {
+				looper.EXPECT().GetSettings().Return(portForwardSettings)
+			},
+			statusCode:   http.StatusBadRequest,
+			responseBody: "cannot change the VPN provider with port forwarding enabled\n",
+		},
+		"no server matching": {
+			method: http.MethodPut,
+			path:   "/vpn/settings",
+			body:   `{"countries":["atlantis"]}`,
+			prepareMocks: func(looper *mock_openvpn.MockLooper) {
+				looper.EXPECT().GetSettings().Return(settings)
+				looper.EXPECT().SetServerSelection("mullvad", gomock.Any()).
+					Return("", openvpn.ErrServerSelectionNoServer)
+			},
+			statusCode:   http.StatusBadRequest,
+			responseBody: "no server matches the server selection\n",
+		},
+		"pause": {
+			method: http.MethodPost,
+			path:   "/vpn/pause",
+			prepareMocks: func(looper *mock_openvpn.MockLooper) {
+				looper.EXPECT().Pause().Return("paused", nil)
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"outcome":"paused"}` + "\n",
+		},
+		"pause with firewall disabled": {
+			method: http.MethodPost,
+			path:   "/vpn/pause",
+			prepareMocks: func(looper *mock_openvpn.MockLooper) {
+				looper.EXPECT().Pause().Return("", openvpn.ErrPauseFirewallDisabled)
+			},
+			statusCode:   http.StatusConflict,
+			responseBody: openvpn.ErrPauseFirewallDisabled.Error() + "\n",
+		},
+		"resume": {
+			method: http.MethodPost,
+			path:   "/vpn/resume",
+			prepareMocks: func(looper *mock_openvpn.MockLooper) {
+				looper.EXPECT().Resume().Return("resumed", nil)
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"outcome":"resumed"}` + "\n",
+		},
+		"wrong method": {
+			method:       http.MethodGet,
+			path:         "/vpn/pause",
+			statusCode:   http.StatusNotFound,
+			responseBody: "\n",
+		},
+		"unknown path": {
+			method:       http.MethodGet,
+			path:         "/vpn/unknown",
+			statusCode:   http.StatusNotFound,
+			responseBody: "\n",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			looper := mock_openvpn.NewMockLooper(ctrl)
+			if testCase.prepareMocks != nil {
+				testCase.prepareMocks(looper)
+			}
+			handler := newVPNHandler(looper, nil)
+
+			request := httptest.NewRequest(testCase.method, testCase.path,
+				strings.NewReader(testCase.body))
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, testCase.statusCode, recorder.Code)
+			assert.Equal(t, testCase.responseBody, recorder.Body.String())
+		})
+	}
+}
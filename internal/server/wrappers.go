@@ -1,6 +1,7 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/qdm12/gluetun/internal/constants"
@@ -30,3 +31,25 @@ type portWrapper struct {
 type outcomeWrapper struct {
 	Outcome string `json:"outcome"`
 }
+
+// vpnSettingsWrapper contains the VPN provider and the server filters.
+type vpnSettingsWrapper struct {
+	Provider  string   `json:"provider"`
+	Countries []string `json:"countries"`
+	Regions   []string `json:"regions"`
+	Cities    []string `json:"cities"`
+	Hostnames []string `json:"hostnames"`
+}
+
+type serversWrapper struct {
+	Provider string                       `json:"provider"`
+	Servers  []map[string]json.RawMessage `json:"servers"`
+}
+
+type queriesWrapper struct {
+	Queries []models.DNSQuery `json:"queries"`
+}
+
+type blockCategoriesWrapper struct {
+	Categories []string `json:"categories"`
+}
@@ -3,7 +3,6 @@ package shadowsocks
 
 import (
 	"context"
-	"fmt"
 	"sync"
 	"time"
 
@@ -99,7 +98,7 @@ func (l *looper) Run(ctx context.Context, wg *sync.WaitGroup) {
 
 		waitError := make(chan error)
 		go func() {
-			waitError <- server.Listen(shadowsocksCtx, fmt.Sprintf("0.0.0.0:%d", settings.Port))
+			waitError <- l.listen(shadowsocksCtx, server, settings)
 		}()
 		if err != nil {
 			crashed = true
@@ -0,0 +1,110 @@
+package shadowsocks
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	shadowsockslib "github.com/qdm12/ss-server/pkg"
+)
+
+var ErrPluginExited = errors.New("plugin exited")
+
+// listen runs the Shadowsocks server until the context is canceled.
+// If a SIP003 plugin is set, the server listens on a local port and
+// the plugin listens on the Shadowsocks port, forwarding to the server.
+func (l *looper) listen(ctx context.Context, server shadowsockslib.Server,
+	settings configuration.ShadowSocks) error {
+	if settings.Plugin == "" {
+		return server.Listen(ctx, fmt.Sprintf("0.0.0.0:%d", settings.Port))
+	}
+
+	localPort, err := getFreeLocalPort()
+	if err != nil {
+		return fmt.Errorf("cannot find a local port for the plugin: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.Listen(ctx, net.JoinHostPort("127.0.0.1", strconv.Itoa(localPort)))
+	}()
+
+	pluginErr := make(chan error, 1)
+	go func() {
+		pluginErr <- l.runPlugin(ctx, settings, localPort)
+	}()
+
+	select {
+	case err = <-serverErr:
+		cancel()
+		<-pluginErr
+	case err = <-pluginErr:
+		cancel()
+		<-serverErr
+	}
+	return err
+}
+
+// runPlugin runs the SIP003 plugin until the context is canceled,
+// passing it the addresses and options with environment variables.
+func (l *looper) runPlugin(ctx context.Context,
+	settings configuration.ShadowSocks, localPort int) error {
+	cmd := exec.CommandContext(ctx, settings.Plugin) //nolint:gosec
+	cmd.Env = append(os.Environ(),
+		"SS_REMOTE_HOST=0.0.0.0",
+		"SS_REMOTE_PORT="+strconv.Itoa(int(settings.Port)),
+		"SS_LOCAL_HOST=127.0.0.1",
+		"SS_LOCAL_PORT="+strconv.Itoa(localPort),
+		"SS_PLUGIN_OPTIONS="+settings.PluginOptions,
+	)
+	output, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	cmd.Stderr = cmd.Stdout
+
+	l.logger.Info("starting plugin %s", settings.Plugin)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("cannot start plugin: %w", err)
+	}
+	l.logPluginOutput(output, settings.Log)
+
+	err = cmd.Wait()
+	switch {
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case err != nil:
+		return fmt.Errorf("%w: %s", ErrPluginExited, err)
+	default:
+		return ErrPluginExited
+	}
+}
+
+func (l *looper) logPluginOutput(output io.Reader, enabled bool) {
+	scanner := bufio.NewScanner(output)
+	for scanner.Scan() {
+		if enabled {
+			l.logger.Info("plugin: %s", scanner.Text())
+		}
+	}
+}
+
+// getFreeLocalPort returns a TCP port currently free on the loopback interface.
+func getFreeLocalPort() (port int, err error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	port = listener.Addr().(*net.TCPAddr).Port
+	return port, listener.Close()
+}
@@ -30,6 +30,7 @@ func (s *storage) mergeServers(hardcoded, persisted models.AllServers) models.Al
 		Torguard:   s.mergeTorguard(hardcoded.Torguard, persisted.Torguard),
 		Vyprvpn:    s.mergeVyprvpn(hardcoded.Vyprvpn, persisted.Vyprvpn),
 		Windscribe: s.mergeWindscribe(hardcoded.Windscribe, persisted.Windscribe),
+		Latencies:  persisted.Latencies,
 	}
 }
 
@@ -0,0 +1,48 @@
+// Package tcpprobe probes servers by establishing TCP connections,
+// to measure their latency or to check they are reachable.
+package tcpprobe
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Latency returns the time taken to establish a TCP connection to the IP
+// address and port given. A refused connection still counts as a measurement
+// since the server did reply.
+func Latency(ctx context.Context, ip net.IP, port uint16,
+	timeout time.Duration) (latency time.Duration, err error) {
+	latency, err = dial(ctx, ip, port, timeout)
+	if err != nil && !errors.Is(err, syscall.ECONNREFUSED) {
+		return 0, err
+	}
+	return latency, nil
+}
+
+// Connect returns an error if a TCP connection to the IP address and port
+// given cannot be established within the timeout given. Unlike Latency,
+// a refused connection is an error, since it can be sent by any middlebox
+// on the path to the server.
+func Connect(ctx context.Context, ip net.IP, port uint16,
+	timeout time.Duration) (err error) {
+	_, err = dial(ctx, ip, port, timeout)
+	return err
+}
+
+func dial(ctx context.Context, ip net.IP, port uint16,
+	timeout time.Duration) (latency time.Duration, err error) {
+	dialer := net.Dialer{Timeout: timeout}
+	address := net.JoinHostPort(ip.String(), strconv.Itoa(int(port)))
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	latency = time.Since(start)
+	if err != nil {
+		return latency, err
+	}
+	_ = conn.Close()
+	return latency, nil
+}
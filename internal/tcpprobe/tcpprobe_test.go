@@ -0,0 +1,95 @@
+package tcpprobe
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPort returns the port of a local TCP listener, which is
+// closed before returning if listening is false.
+func newPort(t *testing.T, listening bool) (port uint16) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port = uint16(listener.Addr().(*net.TCPAddr).Port)
+	if !listening {
+		require.NoError(t, listener.Close())
+		return port
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	return port
+}
+
+func Test_Latency(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		listening bool
+	}{
+		"connection accepted": {
+			listening: true,
+		},
+		"connection refused": {},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			port := newPort(t, testCase.listening)
+
+			latency, err := Latency(context.Background(), net.IPv4(127, 0, 0, 1), port, time.Second)
+
+			require.NoError(t, err)
+			assert.Greater(t, int64(latency), int64(0))
+		})
+	}
+}
+
+func Test_Connect(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		listening bool
+		err       error
+	}{
+		"connection accepted": {
+			listening: true,
+		},
+		"connection refused": {
+			err: syscall.ECONNREFUSED,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			port := newPort(t, testCase.listening)
+
+			err := Connect(context.Background(), net.IPv4(127, 0, 0, 1), port, time.Second)
+
+			assert.ErrorIs(t, err, testCase.err)
+		})
+	}
+}
+
+func Test_Latency_canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	port := newPort(t, true)
+
+	_, err := Latency(ctx, net.IPv4(127, 0, 0, 1), port, time.Second)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
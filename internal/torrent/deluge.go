@@ -0,0 +1,89 @@
+package torrent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type deluge struct {
+	client   *http.Client
+	baseURL  string
+	password string
+	// id is the ID of the last JSON-RPC call.
+	id int
+}
+
+// SetPort logs in the Deluge Web UI and sets the listening port of the
+// daemon it is connected to through the JSON-RPC API.
+func (d *deluge) SetPort(ctx context.Context, port uint16) (err error) {
+	var loggedIn bool
+	if err := d.call(ctx, "auth.login", []interface{}{d.password}, &loggedIn); err != nil {
+		return err
+	} else if !loggedIn {
+		return ErrLoginFailed
+	}
+
+	config := map[string]interface{}{
+		"listen_ports": []uint16{port, port},
+		"random_port":  false,
+	}
+	return d.call(ctx, "core.set_config", []interface{}{config}, nil)
+}
+
+func (d *deluge) call(ctx context.Context, method string,
+	params []interface{}, result interface{}) (err error) {
+	d.id++
+	call := struct {
+		Method string        `json:"method"`
+		Params []interface{} `json:"params"`
+		ID     int           `json:"id"`
+	}{
+		Method: method,
+		Params: params,
+		ID:     d.id,
+	}
+	body, err := json.Marshal(call)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		d.baseURL+"/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if err := checkStatus(response); err != nil {
+		return err
+	}
+
+	var data struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return fmt.Errorf("%w: %s", ErrDecodeResponse, err)
+	} else if data.Error != nil {
+		return fmt.Errorf("%w: %s: %s", ErrRPCFailed, method, data.Error.Message)
+	}
+
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data.Result, result); err != nil {
+		return fmt.Errorf("%w: %s", ErrDecodeResponse, err)
+	}
+	return nil
+}
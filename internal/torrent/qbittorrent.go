@@ -0,0 +1,64 @@
+package torrent
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type qbittorrent struct {
+	client   *http.Client
+	baseURL  string
+	user     string
+	password string
+}
+
+// SetPort logs in the qBittorrent Web API and sets its listening port.
+func (q *qbittorrent) SetPort(ctx context.Context, port uint16) (err error) {
+	values := url.Values{
+		"username": {q.user},
+		"password": {q.password},
+	}
+	body, err := q.post(ctx, "/api/v2/auth/login", values)
+	if err != nil {
+		return err
+	} else if body != "Ok." { // qBittorrent answers 200 with Fails. on bad credentials
+		return fmt.Errorf("%w: %s", ErrLoginFailed, body)
+	}
+
+	preferences := `{"listen_port":` + strconv.Itoa(int(port)) + `}`
+	_, err = q.post(ctx, "/api/v2/app/setPreferences", url.Values{"json": {preferences}})
+	return err
+}
+
+func (q *qbittorrent) post(ctx context.Context, path string, values url.Values) (
+	body string, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		q.baseURL+path, strings.NewReader(values.Encode()))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// the Referer must match the host for the CSRF protection
+	request.Header.Set("Referer", q.baseURL)
+
+	response, err := q.client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if err := checkStatus(response); err != nil {
+		return "", err
+	}
+
+	b, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
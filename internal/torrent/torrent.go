@@ -0,0 +1,75 @@
+// Package torrent sets the listening port of torrent clients
+// through their web API, to listen on the port forwarded.
+package torrent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+var (
+	ErrClientUnknown     = errors.New("torrent client is unknown")
+	ErrBadStatusCode     = errors.New("bad HTTP status")
+	ErrLoginFailed       = errors.New("login failed")
+	ErrRPCFailed         = errors.New("RPC call failed")
+	ErrDecodeResponse    = errors.New("cannot decode response")
+	ErrSessionIDNotFound = errors.New("session ID not found in response")
+)
+
+// PortSetter sets the listening port of a torrent client.
+type PortSetter interface {
+	SetPort(ctx context.Context, port uint16) (err error)
+}
+
+// New returns a port setter for the torrent client settings given,
+// using the HTTP client given with its own cookie jar.
+func New(client *http.Client, settings configuration.TorrentClient) (
+	portSetter PortSetter, err error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	clientCopy := *client
+	clientCopy.Jar = jar
+
+	baseURL := strings.TrimSuffix(settings.URL, "/")
+	switch settings.Name {
+	case constants.QBittorrent:
+		return &qbittorrent{
+			client:   &clientCopy,
+			baseURL:  baseURL,
+			user:     settings.User,
+			password: settings.Password,
+		}, nil
+	case constants.Transmission:
+		return &transmission{
+			client:   &clientCopy,
+			baseURL:  baseURL,
+			user:     settings.User,
+			password: settings.Password,
+		}, nil
+	case constants.Deluge:
+		return &deluge{
+			client:   &clientCopy,
+			baseURL:  baseURL,
+			password: settings.Password,
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrClientUnknown, settings.Name)
+	}
+}
+
+// checkStatus returns an error if the response status code is not 200.
+func checkStatus(response *http.Response) (err error) {
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s", ErrBadStatusCode, response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package torrent
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SetPort(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		name       string
+		handler    http.HandlerFunc
+		errWrapped error
+		err        string
+	}{
+		"qbittorrent": {
+			name: constants.QBittorrent,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, r.ParseForm())
+				switch r.URL.Path {
+				case "/api/v2/auth/login":
+					assert.Equal(t, "user", r.PostForm.Get("username"))
+					assert.Equal(t, "password", r.PostForm.Get("password"))
+					http.SetCookie(w, &http.Cookie{Name: "SID", Value: "sid", Path: "/"})
+					_, _ = w.Write([]byte("Ok."))
+				case "/api/v2/app/setPreferences":
+					_, err := r.Cookie("SID")
+					assert.NoError(t, err)
+					assert.Equal(t, `{"listen_port":1234}`, r.PostForm.Get("json"))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			},
+		},
+		"qbittorrent bad credentials": {
+			name: constants.QBittorrent,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("Fails."))
+			},
+			errWrapped: ErrLoginFailed,
+			err:        "login failed: Fails.",
+		},
+		"transmission": {
+			name: constants.Transmission,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				user, password, _ := r.BasicAuth()
+				assert.Equal(t, "user", user)
+				assert.Equal(t, "password", password)
+				if r.Header.Get(transmissionSessionHeader) != "session" {
+					w.Header().Set(transmissionSessionHeader, "session")
+					w.WriteHeader(http.StatusConflict)
+					return
+				}
+				b, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				assert.JSONEq(t, `{"method":"session-set","arguments":{"peer-port":1234}}`, string(b))
+				_, _ = w.Write([]byte(`{"result":"success"}`))
+			},
+		},
+		"deluge": {
+			name: constants.Deluge,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				var call struct {
+					Method string            `json:"method"`
+					Params []json.RawMessage `json:"params"`
+				}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&call))
+				switch call.Method {
+				case "auth.login":
+					assert.Equal(t, `"password"`, string(call.Params[0]))
+					_, _ = w.Write([]byte(`{"result":true,"error":null}`))
+				case "core.set_config":
+					assert.JSONEq(t, `{"listen_ports":[1234,1234],"random_port":false}`, string(call.Params[0]))
+					_, _ = w.Write([]byte(`{"result":null,"error":null}`))
+				}
+			},
+		},
+		"deluge RPC error": {
+			name: constants.Deluge,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"result":null,"error":{"message":"oops"}}`))
+			},
+			errWrapped: ErrRPCFailed,
+			err:        "RPC call failed: auth.login: oops",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(testCase.handler)
+			t.Cleanup(server.Close)
+
+			settings := configuration.TorrentClient{
+				Name:     testCase.name,
+				URL:      server.URL + "/",
+				User:     "user",
+				Password: "password",
+			}
+			portSetter, err := New(server.Client(), settings)
+			require.NoError(t, err)
+
+			err = portSetter.SetPort(context.Background(), 1234)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.err)
+			}
+		})
+	}
+}
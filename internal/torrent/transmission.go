@@ -0,0 +1,85 @@
+package torrent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// transmissionSessionHeader is the header carrying the session ID
+// required by Transmission to protect against CSRF attacks.
+const transmissionSessionHeader = "X-Transmission-Session-Id"
+
+type transmission struct {
+	client    *http.Client
+	baseURL   string
+	user      string
+	password  string
+	sessionID string
+}
+
+// SetPort sets the peer port of Transmission through its RPC API.
+func (t *transmission) SetPort(ctx context.Context, port uint16) (err error) {
+	call := struct {
+		Method    string            `json:"method"`
+		Arguments map[string]uint16 `json:"arguments"`
+	}{
+		Method:    "session-set",
+		Arguments: map[string]uint16{"peer-port": port},
+	}
+	body, err := json.Marshal(call)
+	if err != nil {
+		return err
+	}
+
+	response, err := t.post(ctx, body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode == http.StatusConflict {
+		// session ID missing or expired, retry with the new one given
+		_ = response.Body.Close()
+		t.sessionID = response.Header.Get(transmissionSessionHeader)
+		if t.sessionID == "" {
+			return ErrSessionIDNotFound
+		}
+		response, err = t.post(ctx, body)
+		if err != nil {
+			return err
+		}
+	}
+	defer response.Body.Close()
+
+	if err := checkStatus(response); err != nil {
+		return err
+	}
+
+	var result struct {
+		Result string `json:"result"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return fmt.Errorf("%w: %s", ErrDecodeResponse, err)
+	} else if result.Result != "success" {
+		return fmt.Errorf("%w: %s", ErrRPCFailed, result.Result)
+	}
+	return nil
+}
+
+func (t *transmission) post(ctx context.Context, body []byte) (
+	response *http.Response, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		t.baseURL+"/transmission/rpc", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if t.sessionID != "" {
+		request.Header.Set(transmissionSessionHeader, t.sessionID)
+	}
+	if t.user != "" || t.password != "" {
+		request.SetBasicAuth(t.user, t.password)
+	}
+	return t.client.Do(request)
+}
@@ -0,0 +1,77 @@
+package transparentproxy
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"syscall"
+	"unsafe"
+)
+
+// soOriginalDestination is the socket option to obtain the original
+// destination of a connection redirected by the firewall, for both
+// IPv4 and IPv6 connections.
+const soOriginalDestination = 80
+
+// originalDestination returns the destination of the connection
+// before it was redirected by the firewall.
+func originalDestination(connection *net.TCPConn) (destination *net.TCPAddr, err error) {
+	rawConnection, err := connection.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	isIPv4 := connection.LocalAddr().(*net.TCPAddr).IP.To4() != nil
+	var getErr error
+	err = rawConnection.Control(func(fd uintptr) {
+		if isIPv4 {
+			destination, getErr = originalDestinationIPv4(int(fd))
+		} else {
+			destination, getErr = originalDestinationIPv6(int(fd))
+		}
+	})
+	if err != nil {
+		return nil, err
+	} else if getErr != nil {
+		return nil, fmt.Errorf("cannot get original destination: %w", getErr)
+	}
+	return destination, nil
+}
+
+func originalDestinationIPv4(fd int) (destination *net.TCPAddr, err error) {
+	// The sockaddr_in structure returned fits in the IPv6Mreq structure.
+	mreq, err := syscall.GetsockoptIPv6Mreq(fd, syscall.IPPROTO_IP, soOriginalDestination)
+	if err != nil {
+		return nil, err
+	}
+	return sockaddrInToTCPAddr(mreq.Multiaddr), nil
+}
+
+// sockaddrInToTCPAddr converts the raw sockaddr_in structure given.
+func sockaddrInToTCPAddr(address [16]byte) (tcpAddress *net.TCPAddr) {
+	return &net.TCPAddr{
+		IP:   net.IPv4(address[4], address[5], address[6], address[7]),
+		Port: int(binary.BigEndian.Uint16(address[2:4])),
+	}
+}
+
+func originalDestinationIPv6(fd int) (destination *net.TCPAddr, err error) {
+	// The sockaddr_in6 structure returned fits in the IPv6MTUInfo structure.
+	info, err := syscall.GetsockoptIPv6MTUInfo(fd, syscall.IPPROTO_IPV6, soOriginalDestination)
+	if err != nil {
+		return nil, err
+	}
+	return sockaddrIn6ToTCPAddr(info.Addr), nil
+}
+
+// sockaddrIn6ToTCPAddr converts the raw sockaddr_in6 structure given.
+func sockaddrIn6ToTCPAddr(address syscall.RawSockaddrInet6) (tcpAddress *net.TCPAddr) {
+	// The port is in network byte order in memory.
+	port := (*[2]byte)(unsafe.Pointer(&address.Port))
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, address.Addr[:])
+	return &net.TCPAddr{
+		IP:   ip,
+		Port: int(binary.BigEndian.Uint16(port[:])),
+	}
+}
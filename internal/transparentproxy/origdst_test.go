@@ -0,0 +1,73 @@
+package transparentproxy
+
+import (
+	"net"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_sockaddrInToTCPAddr(t *testing.T) {
+	t.Parallel()
+
+	address := [16]byte{syscall.AF_INET, 0, 0x1f, 0x90, 1, 2, 3, 4}
+
+	tcpAddress := sockaddrInToTCPAddr(address)
+
+	assert.Equal(t, "1.2.3.4:8080", tcpAddress.String())
+}
+
+func Test_sockaddrIn6ToTCPAddr(t *testing.T) {
+	t.Parallel()
+
+	address := syscall.RawSockaddrInet6{
+		Family: syscall.AF_INET6,
+		Addr:   [16]byte{0x20, 0x01, 0x0d, 0xb8, 15: 1},
+	}
+	port := (*[2]byte)(unsafe.Pointer(&address.Port))
+	port[0], port[1] = 0x1f, 0x90
+
+	tcpAddress := sockaddrIn6ToTCPAddr(address)
+
+	assert.Equal(t, "[2001:db8::1]:8080", tcpAddress.String())
+}
+
+func Test_originalDestination_notRedirected(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"ipv4": "127.0.0.1:0",
+		"ipv6": "[::1]:0",
+	}
+
+	for name, address := range testCases {
+		address := address
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			listener, err := net.Listen("tcp", address)
+			if err != nil {
+				t.Skipf("cannot listen on %s: %s", address, err)
+			}
+			defer listener.Close()
+
+			client, err := net.Dial("tcp", listener.Addr().String())
+			require.NoError(t, err)
+			defer client.Close()
+			connection, err := listener.Accept()
+			require.NoError(t, err)
+			defer connection.Close()
+
+			// The connection was not redirected by the firewall,
+			// so it has no original destination to look up.
+			destination, err := originalDestination(connection.(*net.TCPConn))
+
+			assert.Nil(t, destination)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "cannot get original destination: ")
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Package transparentproxy defines a TCP proxy for the connections
+// redirected to it by the firewall, connecting to their original
+// destination through the VPN.
+package transparentproxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/qdm12/golibs/logging"
+)
+
+type Server interface {
+	Run(ctx context.Context, wg *sync.WaitGroup)
+}
+
+type server struct {
+	address string
+	logger  logging.Logger
+	dialer  *net.Dialer
+	// originalDestination is injected for tests.
+	originalDestination func(connection *net.TCPConn) (destination *net.TCPAddr, err error)
+}
+
+func New(logger logging.Logger, listeningPort uint16) Server {
+	const dialTimeout = 10 * time.Second
+	return &server{
+		address: fmt.Sprintf(":%d", listeningPort),
+		logger:  logger.NewChild(logging.SetPrefix("transparent proxy: ")),
+		dialer:  &net.Dialer{Timeout: dialTimeout},
+
+		originalDestination: originalDestination,
+	}
+}
+
+func (s *server) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	listenConfig := net.ListenConfig{}
+	listener, err := listenConfig.Listen(ctx, "tcp", s.address)
+	if err != nil {
+		s.logger.Error(err)
+		return
+	}
+	s.logger.Info("listening on %s", s.address)
+
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+
+	connectionsWg := &sync.WaitGroup{}
+	defer connectionsWg.Wait()
+	for {
+		connection, err := listener.Accept()
+		if ctx.Err() != nil {
+			return
+		} else if err != nil {
+			s.logger.Error(err)
+			continue
+		}
+		connectionsWg.Add(1)
+		go s.handle(ctx, connectionsWg, connection.(*net.TCPConn))
+	}
+}
+
+func (s *server) handle(ctx context.Context, wg *sync.WaitGroup, connection *net.TCPConn) {
+	defer wg.Done()
+	defer connection.Close()
+
+	destination, err := s.originalDestination(connection)
+	if err != nil {
+		s.logger.Debug("connection from %s: %s", connection.RemoteAddr(), err)
+		return
+	}
+
+	destinationConnection, err := s.dialer.DialContext(ctx, "tcp", destination.String())
+	if err != nil {
+		s.logger.Debug("connection from %s: %s", connection.RemoteAddr(), err)
+		return
+	}
+	defer destinationConnection.Close()
+	s.logger.Debug("proxying %s to %s", connection.RemoteAddr(), destination)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		_ = connection.Close()
+		_ = destinationConnection.Close()
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = io.Copy(destinationConnection, connection)
+		_ = destinationConnection.(*net.TCPConn).CloseWrite()
+	}()
+	_, _ = io.Copy(connection, destinationConnection)
+	_ = connection.CloseWrite()
+	<-done
+}
@@ -0,0 +1,160 @@
+package transparentproxy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runEchoServer runs a TCP server replying with the data it receives,
+// and returns its address.
+func runEchoServer(t *testing.T) (address *net.TCPAddr) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			connection, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer connection.Close()
+				_, _ = io.Copy(connection, connection)
+			}()
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr)
+}
+
+// proxyConnection returns a client connection proxied by the
+// server given, and a channel closed once the proxying is done.
+func proxyConnection(ctx context.Context, t *testing.T, s *server) (
+	client *net.TCPConn, done <-chan struct{}) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	connection, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = connection.Close() })
+	accepted, err := listener.Accept()
+	require.NoError(t, err)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go s.handle(ctx, wg, accepted.(*net.TCPConn))
+	handled := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(handled)
+	}()
+	return connection.(*net.TCPConn), handled
+}
+
+func Test_server_handle(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	echoAddress := runEchoServer(t)
+
+	// closedAddress is an address with nothing listening on it.
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddress := closedListener.Addr().(*net.TCPAddr)
+	require.NoError(t, closedListener.Close())
+
+	testCases := map[string]struct {
+		destination    *net.TCPAddr
+		destinationErr error
+		response       string
+	}{
+		"proxied to original destination": {
+			destination: echoAddress,
+			response:    "hello",
+		},
+		"original destination not found": {
+			destinationErr: errTest,
+		},
+		"original destination unreachable": {
+			destination: closedAddress,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			logger := mock_logging.NewMockLogger(ctrl)
+			logger.EXPECT().Debug(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			s := &server{
+				logger: logger,
+				dialer: &net.Dialer{Timeout: time.Second},
+				originalDestination: func(*net.TCPConn) (*net.TCPAddr, error) {
+					return testCase.destination, testCase.destinationErr
+				},
+			}
+
+			client, done := proxyConnection(context.Background(), t, s)
+
+			_, err := client.Write([]byte("hello"))
+			require.NoError(t, err)
+			require.NoError(t, client.CloseWrite())
+			response, _ := ioutil.ReadAll(client)
+			<-done
+
+			assert.Equal(t, testCase.response, string(response))
+		})
+	}
+}
+
+func Test_server_handle_canceled(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	logger := mock_logging.NewMockLogger(ctrl)
+	logger.EXPECT().Debug(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	echoAddress := runEchoServer(t)
+	s := &server{
+		logger: logger,
+		dialer: &net.Dialer{Timeout: time.Second},
+		originalDestination: func(*net.TCPConn) (*net.TCPAddr, error) {
+			return echoAddress, nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client, done := proxyConnection(ctx, t, s)
+
+	_, err := client.Write([]byte("hello"))
+	require.NoError(t, err)
+	response := make([]byte, len("hello"))
+	_, err = io.ReadFull(client, response)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(response))
+
+	// The connections are closed once the context is canceled,
+	// even though the client did not close its connection.
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection not closed after context canceled")
+	}
+}
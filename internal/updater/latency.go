@@ -0,0 +1,163 @@
+package updater
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/tcpprobe"
+)
+
+type latencyFunc func(ctx context.Context, ip net.IP) (latency time.Duration, err error)
+
+// newTCPLatency returns a function measuring the time taken to establish
+// a TCP connection to the given IP address and port.
+func newTCPLatency(port uint16, timeout time.Duration) latencyFunc {
+	return func(ctx context.Context, ip net.IP) (latency time.Duration, err error) {
+		return tcpprobe.Latency(ctx, ip, port, timeout)
+	}
+}
+
+func measureLatencies(ctx context.Context, measure latencyFunc,
+	ips []net.IP, parallelism int) (latencies models.IPLatencies) {
+	type result struct {
+		ip      string
+		latency time.Duration
+		ok      bool
+	}
+
+	results := make(chan result)
+	ipsCh := make(chan net.IP)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ip := range ipsCh {
+				latency, err := measure(ctx, ip)
+				results <- result{ip: ip.String(), latency: latency, ok: err == nil}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(ipsCh)
+		for _, ip := range ips {
+			select {
+			case ipsCh <- ip:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	latencies = make(models.IPLatencies, len(ips))
+	for r := range results {
+		if r.ok {
+			latencies[r.ip] = r.latency
+		}
+	}
+	return latencies
+}
+
+func (u *updater) updateLatencies(ctx context.Context, settings configuration.Updater) {
+	ips := u.getUpdatedIPs(settings)
+	u.logger.Info("measuring latency of %d servers IP addresses...", len(ips))
+	const parallelism = 32
+	latencies := measureLatencies(ctx, u.measureLatency, ips, parallelism)
+	if ctx.Err() != nil {
+		return
+	}
+
+	if u.servers.Latencies == nil {
+		u.servers.Latencies = make(models.IPLatencies, len(latencies))
+	}
+	for _, ip := range ips {
+		key := ip.String()
+		latency, ok := latencies[key]
+		if !ok { // unreachable
+			delete(u.servers.Latencies, key)
+			continue
+		}
+		u.servers.Latencies[key] = latency
+	}
+	u.logger.Info("measured latency of %d out of %d IP addresses", len(latencies), len(ips))
+}
+
+//nolint:gocyclo
+func (u *updater) getUpdatedIPs(settings configuration.Updater) (ips []net.IP) {
+	if settings.Cyberghost {
+		for _, server := range u.servers.Cyberghost.Servers {
+			ips = append(ips, server.IPs...)
+		}
+	}
+	if settings.Fastestvpn {
+		for _, server := range u.servers.Fastestvpn.Servers {
+			ips = append(ips, server.IPs...)
+		}
+	}
+	if settings.HideMyAss {
+		for _, server := range u.servers.HideMyAss.Servers {
+			ips = append(ips, server.IPs...)
+		}
+	}
+	if settings.Mullvad {
+		for _, server := range u.servers.Mullvad.Servers {
+			ips = append(ips, server.IPs...)
+		}
+	}
+	if settings.Nordvpn {
+		for _, server := range u.servers.Nordvpn.Servers {
+			ips = append(ips, server.IP)
+		}
+	}
+	if settings.Privado {
+		for _, server := range u.servers.Privado.Servers {
+			ips = append(ips, server.IP)
+		}
+	}
+	if settings.PIA {
+		for _, server := range u.servers.Pia.Servers {
+			ips = append(ips, server.IP)
+		}
+	}
+	if settings.Privatevpn {
+		for _, server := range u.servers.Privatevpn.Servers {
+			ips = append(ips, server.IPs...)
+		}
+	}
+	if settings.Purevpn {
+		for _, server := range u.servers.Purevpn.Servers {
+			ips = append(ips, server.IPs...)
+		}
+	}
+	if settings.Surfshark {
+		for _, server := range u.servers.Surfshark.Servers {
+			ips = append(ips, server.IPs...)
+		}
+	}
+	if settings.Torguard {
+		for _, server := range u.servers.Torguard.Servers {
+			ips = append(ips, server.IP)
+		}
+	}
+	if settings.Vyprvpn {
+		for _, server := range u.servers.Vyprvpn.Servers {
+			ips = append(ips, server.IPs...)
+		}
+	}
+	if settings.Windscribe {
+		for _, server := range u.servers.Windscribe.Servers {
+			ips = append(ips, server.IP)
+		}
+	}
+	return uniqueSortedIPs(ips)
+}
@@ -0,0 +1,163 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/stretchr/testify/assert"
+)
+
+// newFakeLatency returns a latency function returning the latencies
+// given, and an error for the IP addresses without latency.
+func newFakeLatency(latencies models.IPLatencies) latencyFunc {
+	errUnreachable := errors.New("unreachable")
+	return func(ctx context.Context, ip net.IP) (latency time.Duration, err error) {
+		latency, ok := latencies[ip.String()]
+		if !ok {
+			return 0, errUnreachable
+		}
+		return latency, nil
+	}
+}
+
+func Test_measureLatencies(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ips         []net.IP
+		parallelism int
+		measured    models.IPLatencies
+		latencies   models.IPLatencies
+	}{
+		"no IP address": {
+			parallelism: 1,
+			latencies:   models.IPLatencies{},
+		},
+		"unreachable IP address": {
+			ips:         []net.IP{{1, 1, 1, 1}, {2, 2, 2, 2}},
+			parallelism: 1,
+			measured: models.IPLatencies{
+				"1.1.1.1": time.Millisecond,
+			},
+			latencies: models.IPLatencies{
+				"1.1.1.1": time.Millisecond,
+			},
+		},
+		"more IP addresses than parallelism": {
+			ips:         []net.IP{{1, 1, 1, 1}, {2, 2, 2, 2}, {3, 3, 3, 3}},
+			parallelism: 2,
+			measured: models.IPLatencies{
+				"1.1.1.1": time.Millisecond,
+				"2.2.2.2": 2 * time.Millisecond,
+				"3.3.3.3": 3 * time.Millisecond,
+			},
+			latencies: models.IPLatencies{
+				"1.1.1.1": time.Millisecond,
+				"2.2.2.2": 2 * time.Millisecond,
+				"3.3.3.3": 3 * time.Millisecond,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			latencies := measureLatencies(context.Background(), newFakeLatency(testCase.measured),
+				testCase.ips, testCase.parallelism)
+
+			assert.Equal(t, testCase.latencies, latencies)
+		})
+	}
+}
+
+func Test_measureLatencies_canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	measure := func(ctx context.Context, ip net.IP) (latency time.Duration, err error) {
+		return 0, ctx.Err()
+	}
+	ips := []net.IP{{1, 1, 1, 1}, {2, 2, 2, 2}}
+
+	latencies := measureLatencies(ctx, measure, ips, 1)
+
+	assert.Empty(t, latencies)
+}
+
+func Test_updater_updateLatencies(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	logger := mock_logging.NewMockLogger(ctrl)
+	logger.EXPECT().Info("measuring latency of %d servers IP addresses...", 3)
+	logger.EXPECT().Info("measured latency of %d out of %d IP addresses", 2, 3)
+
+	u := &updater{
+		logger: logger,
+		measureLatency: newFakeLatency(models.IPLatencies{
+			"1.1.1.1": time.Millisecond,
+			"2.2.2.2": 2 * time.Millisecond,
+		}),
+		servers: models.AllServers{
+			Mullvad: models.MullvadServers{
+				Servers: []models.MullvadServer{
+					{IPs: []net.IP{{1, 1, 1, 1}, {2, 2, 2, 2}}},
+					{IPs: []net.IP{{3, 3, 3, 3}}},
+				},
+			},
+			Latencies: models.IPLatencies{
+				"2.2.2.2": 5 * time.Millisecond, // outdated
+				"3.3.3.3": 5 * time.Millisecond, // no longer reachable
+				"4.4.4.4": 5 * time.Millisecond, // provider not updated
+			},
+		},
+	}
+	settings := configuration.Updater{Mullvad: true}
+
+	u.updateLatencies(context.Background(), settings)
+
+	expected := models.IPLatencies{
+		"1.1.1.1": time.Millisecond,
+		"2.2.2.2": 2 * time.Millisecond,
+		"4.4.4.4": 5 * time.Millisecond,
+	}
+	assert.Equal(t, expected, u.servers.Latencies)
+}
+
+func Test_updater_updateLatencies_canceled(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	logger := mock_logging.NewMockLogger(ctrl)
+	logger.EXPECT().Info("measuring latency of %d servers IP addresses...", 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	u := &updater{
+		logger:         logger,
+		measureLatency: newFakeLatency(nil),
+		servers: models.AllServers{
+			Mullvad: models.MullvadServers{
+				Servers: []models.MullvadServer{{IPs: []net.IP{{1, 1, 1, 1}}}},
+			},
+			Latencies: models.IPLatencies{"1.1.1.1": 5 * time.Millisecond},
+		},
+	}
+
+	u.updateLatencies(ctx, configuration.Updater{Mullvad: true})
+
+	// latencies are left unchanged if the measurement is canceled
+	assert.Equal(t, models.IPLatencies{"1.1.1.1": 5 * time.Millisecond}, u.servers.Latencies)
+}
@@ -20,6 +20,7 @@ type Looper interface {
 	SetStatus(status models.LoopStatus) (outcome string, err error)
 	GetSettings() (settings configuration.Updater)
 	SetSettings(settings configuration.Updater) (outcome string)
+	RunUpdate(ctx context.Context, providers []string) (results []models.ServersUpdate, err error)
 }
 
 type looper struct {
@@ -202,3 +203,28 @@ func (l *looper) RunRestartTicker(ctx context.Context, wg *sync.WaitGroup) {
 		}
 	}
 }
+
+// RunUpdate updates the servers of the providers given, or of the
+// providers set in the settings if none is given, and stores them.
+// It blocks until the update completes.
+func (l *looper) RunUpdate(ctx context.Context, providers []string) (
+	results []models.ServersUpdate, err error) {
+	settings := l.GetSettings()
+	if len(providers) > 0 {
+		if err := settings.SetProviders(providers); err != nil {
+			return nil, err
+		}
+	}
+
+	servers, results, err := l.updater.UpdateProviders(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	l.setAllServers(servers)
+	if err := l.storage.FlushToFile(servers); err != nil {
+		return results, err
+	}
+	l.logger.Info("Updated servers information")
+	return results, nil
+}
@@ -52,6 +52,9 @@ func findMullvadServers(ctx context.Context, client *http.Client) (servers []mod
 		Provider string `json:"provider"`
 		IPv4     string `json:"ipv4_addr_in"`
 		IPv6     string `json:"ipv6_addr_in"`
+		// MultiHopPort is the port to connect to on any entry
+		// server to exit through this server.
+		MultiHopPort uint16 `json:"multihop_port"`
 	}
 	if err := decoder.Decode(&data); err != nil {
 		return nil, err
@@ -77,9 +80,12 @@ func findMullvadServers(ctx context.Context, client *http.Client) (servers []mod
 		if server, ok := serversByKey[key]; ok {
 			server.IPs = append(server.IPs, ipv4)
 			server.IPsV6 = append(server.IPsV6, ipv6)
+			if jsonServer.MultiHopPort > 0 {
+				server.MultiHopPorts = append(server.MultiHopPorts, jsonServer.MultiHopPort)
+			}
 			serversByKey[key] = server
 		} else {
-			serversByKey[key] = models.MullvadServer{
+			server := models.MullvadServer{
 				IPs:     []net.IP{ipv4},
 				IPsV6:   []net.IP{ipv6},
 				Country: jsonServer.Country,
@@ -87,11 +93,18 @@ func findMullvadServers(ctx context.Context, client *http.Client) (servers []mod
 				ISP:     jsonServer.Provider,
 				Owned:   jsonServer.Owned,
 			}
+			if jsonServer.MultiHopPort > 0 {
+				server.MultiHopPorts = []uint16{jsonServer.MultiHopPort}
+			}
+			serversByKey[key] = server
 		}
 	}
 	for _, server := range serversByKey {
 		server.IPs = uniqueSortedIPs(server.IPs)
 		server.IPsV6 = uniqueSortedIPs(server.IPsV6)
+		sort.Slice(server.MultiHopPorts, func(i, j int) bool {
+			return server.MultiHopPorts[i] < server.MultiHopPorts[j]
+		})
 		servers = append(servers, server)
 	}
 	sort.Slice(servers, func(i, j int) bool {
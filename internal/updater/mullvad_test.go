@@ -17,12 +17,18 @@ func Test_stringifyMullvadServers(t *testing.T) {
 		Owned:   true,
 		IPs:     []net.IP{{1, 1, 1, 1}},
 		IPsV6:   []net.IP{{1, 1, 1, 1}},
+	}, {
+		Country:       "webland",
+		City:          "othercity",
+		IPs:           []net.IP{{2, 2, 2, 2}},
+		MultiHopPorts: []uint16{3001, 3002},
 	}}
 	//nolint:lll
 	expected := `
 func MullvadServers() []models.MullvadServer {
 	return []models.MullvadServer{
 		{Country: "webland", City: "webcity", ISP: "not nsa", Owned: true, IPs: []net.IP{{1, 1, 1, 1}}, IPsV6: []net.IP{{1, 1, 1, 1}}},
+		{Country: "webland", City: "othercity", ISP: "", Owned: false, IPs: []net.IP{{2, 2, 2, 2}}, IPsV6: []net.IP{}, MultiHopPorts: []uint16{3001, 3002}},
 	}
 }
 `
@@ -11,7 +11,9 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider"
 )
 
 func (u *updater) updateNordvpn(ctx context.Context) (err error) {
@@ -24,6 +26,12 @@ func (u *updater) updateNordvpn(ctx context.Context) (err error) {
 	if err != nil {
 		return fmt.Errorf("cannot update Nordvpn servers: %w", err)
 	}
+	if err := provider.SetNordlynxPublicKeys(ctx, u.client, servers); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		u.logger.Warn("Nordvpn: %s", err)
+	}
 	if u.options.Stdout {
 		u.println(stringifyNordvpnServers(servers))
 	}
@@ -65,6 +73,9 @@ func findNordvpnServers(ctx context.Context, client *http.Client) (
 			UDP bool `json:"openvpn_udp"`
 			TCP bool `json:"openvpn_tcp"`
 		} `json:"features"`
+		Categories []struct {
+			Name string `json:"name"`
+		} `json:"categories"`
 	}
 	if err := decoder.Decode(&data); err != nil {
 		return nil, nil, err
@@ -108,6 +119,16 @@ func findNordvpnServers(ctx context.Context, client *http.Client) (
 			TCP:    jsonServer.Features.TCP,
 			UDP:    jsonServer.Features.UDP,
 		}
+		for _, category := range jsonServer.Categories {
+			switch category.Name {
+			case "Double VPN":
+				server.MultiHop = nordvpnMultiHop(jsonServer.Name)
+			case "P2P":
+				server.Features = append(server.Features, constants.P2P)
+			case "Onion Over VPN":
+				server.Features = append(server.Features, constants.Tor)
+			}
+		}
 		servers = append(servers, server)
 	}
 	return servers, warnings, nil
@@ -123,3 +144,20 @@ func stringifyNordvpnServers(servers []models.NordvpnServer) (s string) {
 	s += "}"
 	return s
 }
+
+// nordvpnMultiHop extracts the entry and exit countries from
+// a double VPN server name such as "Canada - United States #1".
+func nordvpnMultiHop(name string) (multiHop *models.MultiHop) {
+	if i := strings.IndexRune(name, '#'); i >= 0 {
+		name = name[:i]
+	}
+	parts := strings.Split(name, " - ")
+	const expectedParts = 2
+	if len(parts) != expectedParts {
+		return nil
+	}
+	return &models.MultiHop{
+		EntryCountry: strings.TrimSpace(parts[0]),
+		ExitCountry:  strings.TrimSpace(parts[1]),
+	}
+}
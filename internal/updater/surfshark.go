@@ -143,8 +143,9 @@ func findSurfsharkServersFromZip(ctx context.Context, client *http.Client, looku
 			warnings = append(warnings, warning)
 		}
 		server := models.SurfsharkServer{
-			Region: region,
-			IPs:    uniqueSortedIPs(IPs),
+			Region:   region,
+			IPs:      uniqueSortedIPs(IPs),
+			MultiHop: surfsharkMultiHop(subdomain),
 		}
 		servers = append(servers, server)
 	}
@@ -175,8 +176,9 @@ func getRemainingServers(ctx context.Context, mapping map[string]string, lookupI
 	for host, IPs := range hostToIPs {
 		subdomain := strings.TrimSuffix(host, ".prod.surfshark.com")
 		server := models.SurfsharkServer{
-			Region: mapping[subdomain],
-			IPs:    uniqueSortedIPs(IPs),
+			Region:   mapping[subdomain],
+			IPs:      uniqueSortedIPs(IPs),
+			MultiHop: surfsharkMultiHop(subdomain),
 		}
 		servers = append(servers, server)
 	}
@@ -195,6 +197,38 @@ func stringifySurfsharkServers(servers []models.SurfsharkServer) (s string) {
 	return s
 }
 
+// surfsharkMultiHop returns the multi-hop entry and exit countries
+// for subdomains made of two country codes such as "au-us".
+func surfsharkMultiHop(subdomain string) (multiHop *models.MultiHop) {
+	parts := strings.Split(subdomain, "-")
+	const codeLength = 2
+	if len(parts) != 2 || len(parts[0]) != codeLength || len(parts[1]) != codeLength {
+		return nil
+	}
+	codeToCountry := surfsharkCountryCodes()
+	entry, exit := codeToCountry[parts[0]], codeToCountry[parts[1]]
+	if entry == "" || exit == "" {
+		return nil
+	}
+	return &models.MultiHop{EntryCountry: entry, ExitCountry: exit}
+}
+
+func surfsharkCountryCodes() map[string]string {
+	return map[string]string{
+		"au": "Australia",
+		"ca": "Canada",
+		"de": "Germany",
+		"fr": "France",
+		"hk": "Hong Kong",
+		"in": "India",
+		"nl": "Netherlands",
+		"se": "Sweden",
+		"sg": "Singapore",
+		"uk": "United Kingdom",
+		"us": "United States",
+	}
+}
+
 func surfsharkSubdomainToRegion() (mapping map[string]string) {
 	return map[string]string{
 		"ae-dub":       "United Arab Emirates",
@@ -5,15 +5,19 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/golibs/logging"
 )
 
 type Updater interface {
 	UpdateServers(ctx context.Context) (allServers models.AllServers, err error)
+	UpdateProviders(ctx context.Context, settings configuration.Updater) (
+		allServers models.AllServers, results []models.ServersUpdate, err error)
 }
 
 type updater struct {
@@ -22,13 +26,16 @@ type updater struct {
 
 	// state
 	servers models.AllServers
+	// updateMu prevents concurrent updates of the servers.
+	updateMu sync.Mutex
 
 	// Functions for tests
-	logger   logging.Logger
-	timeNow  func() time.Time
-	println  func(s string)
-	lookupIP lookupIPFunc
-	client   *http.Client
+	logger         logging.Logger
+	timeNow        func() time.Time
+	println        func(s string)
+	lookupIP       lookupIPFunc
+	measureLatency latencyFunc
+	client         *http.Client
 }
 
 func New(settings configuration.Updater, httpClient *http.Client,
@@ -37,150 +44,103 @@ func New(settings configuration.Updater, httpClient *http.Client,
 		settings.DNSAddress = "1.1.1.1"
 	}
 	resolver := newResolver(settings.DNSAddress)
+	const latencyPort, latencyTimeout = 443, 3 * time.Second
 	return &updater{
-		logger:   logger,
-		timeNow:  time.Now,
-		println:  func(s string) { fmt.Println(s) },
-		lookupIP: newLookupIP(resolver),
-		client:   httpClient,
-		options:  settings,
-		servers:  currentServers,
+		logger:         logger,
+		timeNow:        time.Now,
+		println:        func(s string) { fmt.Println(s) },
+		lookupIP:       newLookupIP(resolver),
+		measureLatency: newTCPLatency(latencyPort, latencyTimeout),
+		client:         httpClient,
+		options:        settings,
+		servers:        currentServers,
 	}
 }
 
-//nolint:gocognit,gocyclo
-func (u *updater) UpdateServers(ctx context.Context) (allServers models.AllServers, err error) {
-	if u.options.Cyberghost {
-		u.logger.Info("updating Cyberghost servers...")
-		if err := u.updateCyberghost(ctx); err != nil {
-			if ctxErr := ctx.Err(); ctxErr != nil {
-				return allServers, ctxErr
-			}
-			u.logger.Error(err)
-		}
-	}
-
-	if u.options.Fastestvpn {
-		u.logger.Info("updating Fastestvpn servers...")
-		if err := u.updateFastestvpn(ctx); err != nil {
-			u.logger.Error(err)
-		}
-		if err := ctx.Err(); err != nil {
-			return allServers, err
-		}
-	}
-
-	if u.options.HideMyAss {
-		u.logger.Info("updating HideMyAss servers...")
-		if err := u.updateHideMyAss(ctx); err != nil {
-			u.logger.Error(err)
-		}
-		if err := ctx.Err(); err != nil {
-			return allServers, err
-		}
-	}
-
-	if u.options.Mullvad {
-		u.logger.Info("updating Mullvad servers...")
-		if err := u.updateMullvad(ctx); err != nil {
-			u.logger.Error(err)
-		}
-		if err := ctx.Err(); err != nil {
-			return allServers, err
-		}
-	}
-
-	if u.options.Nordvpn {
-		// TODO support servers offering only TCP or only UDP
-		u.logger.Info("updating NordVPN servers...")
-		if err := u.updateNordvpn(ctx); err != nil {
-			u.logger.Error(err)
-		}
-		if err := ctx.Err(); err != nil {
-			return allServers, err
-		}
-	}
-
-	if u.options.Privado {
-		u.logger.Info("updating Privado servers...")
-		if err := u.updatePrivado(ctx); err != nil {
-			u.logger.Error(err)
-		}
-		if ctx.Err() != nil {
-			return allServers, ctx.Err()
-		}
-	}
-
-	if u.options.PIA {
-		u.logger.Info("updating Private Internet Access servers...")
-		if err := u.updatePIA(ctx); err != nil {
-			u.logger.Error(err)
-		}
-		if ctx.Err() != nil {
-			return allServers, ctx.Err()
-		}
-	}
-
-	if u.options.Privatevpn {
-		u.logger.Info("updating Privatevpn servers...")
-		if err := u.updatePrivatevpn(ctx); err != nil {
-			if ctxErr := ctx.Err(); ctxErr != nil {
-				return allServers, ctxErr
-			}
-			u.logger.Error(err)
-		}
-	}
-
-	if u.options.Purevpn {
-		u.logger.Info("updating PureVPN servers...")
-		// TODO support servers offering only TCP or only UDP
-		if err := u.updatePurevpn(ctx); err != nil {
-			if ctxErr := ctx.Err(); ctxErr != nil {
-				return allServers, ctxErr
-			}
-			u.logger.Error(err)
-		}
-	}
+// providerUpdater updates the servers of a VPN provider if enabled.
+type providerUpdater struct {
+	enabled bool
+	update  func(ctx context.Context) (err error)
+	// count returns the number of servers of the provider.
+	count func() int
+}
 
-	if u.options.Surfshark {
-		u.logger.Info("updating Surfshark servers...")
-		if err := u.updateSurfshark(ctx); err != nil {
-			if ctxErr := ctx.Err(); ctxErr != nil {
-				return allServers, ctxErr
-			}
-			u.logger.Error(err)
-		}
+// providerUpdaters returns the servers updater of each VPN provider,
+// enabled as in the settings given. A new VPN provider must be
+// registered here as well as in constants.ProviderCapabilities.
+func (u *updater) providerUpdaters(settings configuration.Updater) map[string]providerUpdater {
+	s := &u.servers
+	return map[string]providerUpdater{
+		constants.Cyberghost: {settings.Cyberghost, u.updateCyberghost,
+			func() int { return len(s.Cyberghost.Servers) }},
+		constants.Fastestvpn: {settings.Fastestvpn, u.updateFastestvpn,
+			func() int { return len(s.Fastestvpn.Servers) }},
+		constants.HideMyAss: {settings.HideMyAss, u.updateHideMyAss,
+			func() int { return len(s.HideMyAss.Servers) }},
+		constants.Mullvad: {settings.Mullvad, u.updateMullvad,
+			func() int { return len(s.Mullvad.Servers) }},
+		constants.Nordvpn: {settings.Nordvpn, u.updateNordvpn,
+			func() int { return len(s.Nordvpn.Servers) }},
+		constants.Privado: {settings.Privado, u.updatePrivado,
+			func() int { return len(s.Privado.Servers) }},
+		constants.PrivateInternetAccess: {settings.PIA, u.updatePIA,
+			func() int { return len(s.Pia.Servers) }},
+		constants.Privatevpn: {settings.Privatevpn, u.updatePrivatevpn,
+			func() int { return len(s.Privatevpn.Servers) }},
+		constants.Purevpn: {settings.Purevpn, u.updatePurevpn,
+			func() int { return len(s.Purevpn.Servers) }},
+		constants.Surfshark: {settings.Surfshark, u.updateSurfshark,
+			func() int { return len(s.Surfshark.Servers) }},
+		constants.Torguard: {settings.Torguard, u.updateTorguard,
+			func() int { return len(s.Torguard.Servers) }},
+		constants.Vyprvpn: {settings.Vyprvpn, u.updateVyprvpn,
+			func() int { return len(s.Vyprvpn.Servers) }},
+		constants.Windscribe: {settings.Windscribe, u.updateWindscribe,
+			func() int { return len(s.Windscribe.Servers) }},
 	}
+}
 
-	if u.options.Torguard {
-		u.logger.Info("updating Torguard servers...")
-		if err := u.updateTorguard(ctx); err != nil {
-			if ctxErr := ctx.Err(); ctxErr != nil {
-				return allServers, ctxErr
-			}
-			u.logger.Error(err)
-		}
-	}
+func (u *updater) UpdateServers(ctx context.Context) (allServers models.AllServers, err error) {
+	allServers, _, err = u.UpdateProviders(ctx, u.options)
+	return allServers, err
+}
 
-	if u.options.Vyprvpn {
-		u.logger.Info("updating Vyprvpn servers...")
-		if err := u.updateVyprvpn(ctx); err != nil {
+// UpdateProviders updates the servers of the providers enabled in the
+// settings given, and returns the update result of each provider.
+// A provider failing to update keeps its previous servers.
+func (u *updater) UpdateProviders(ctx context.Context, settings configuration.Updater) (
+	allServers models.AllServers, results []models.ServersUpdate, err error) {
+	u.updateMu.Lock()
+	defer u.updateMu.Unlock()
+
+	updaters := u.providerUpdaters(settings)
+	for _, provider := range constants.VPNProviders() {
+		updater := updaters[provider]
+		if !updater.enabled {
+			continue
+		}
+		u.logger.Info("updating %s servers...", provider)
+		result := models.ServersUpdate{
+			Provider: provider,
+			Previous: updater.count(),
+		}
+		if err := updater.update(ctx); err != nil {
 			if ctxErr := ctx.Err(); ctxErr != nil {
-				return allServers, ctxErr
+				return allServers, nil, ctxErr
 			}
 			u.logger.Error(err)
+			result.Error = err.Error()
 		}
+		result.Servers = updater.count()
+		results = append(results, result)
 	}
 
-	if u.options.Windscribe {
-		u.logger.Info("updating Windscribe servers...")
-		if err := u.updateWindscribe(ctx); err != nil {
-			if ctxErr := ctx.Err(); ctxErr != nil {
-				return allServers, ctxErr
-			}
-			u.logger.Error(err)
+	if settings.Latency {
+		u.updateLatencies(ctx, settings)
+		if err := ctx.Err(); err != nil {
+			return allServers, nil, err
 		}
 	}
 
-	return u.servers, nil
+	return u.servers, results, nil
 }
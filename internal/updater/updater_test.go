@@ -0,0 +1,22 @@
+package updater
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_updater_providerUpdaters(t *testing.T) {
+	t.Parallel()
+
+	u := &updater{}
+	updaters := u.providerUpdaters(configuration.Updater{})
+
+	for _, provider := range constants.VPNProviders() {
+		_, ok := updaters[provider]
+		assert.True(t, ok, "no servers updater registered for VPN provider %s", provider)
+	}
+	assert.Len(t, updaters, len(constants.VPNProviders()))
+}
@@ -7,8 +7,10 @@ import (
 	"net"
 	"net/http"
 	"sort"
+	"strings"
 	"time"
 
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/models"
 )
 
@@ -49,6 +51,7 @@ func findWindscribeServers(ctx context.Context, client *http.Client) (servers []
 	var jsonData struct {
 		Data []struct {
 			Region string `json:"name"`
+			P2P    int    `json:"p2p"`
 			Groups []struct {
 				City  string `json:"city"`
 				Nodes []struct {
@@ -70,12 +73,21 @@ func findWindscribeServers(ctx context.Context, client *http.Client) (servers []
 		region := regionBlock.Region
 		for _, group := range regionBlock.Groups {
 			city := group.City
+			var features []string
+			if regionBlock.P2P == 1 {
+				features = append(features, constants.P2P)
+			}
+			// Windflix locations are dedicated to streaming services
+			if strings.Contains(strings.ToLower(region+" "+city), "windflix") {
+				features = append(features, constants.Streaming)
+			}
 			for _, node := range group.Nodes {
 				server := models.WindscribeServer{
 					Region:   region,
 					City:     city,
 					Hostname: node.Hostname,
 					IP:       node.OpenvpnIP,
+					Features: features,
 				}
 				servers = append(servers, server)
 			}
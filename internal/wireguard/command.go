@@ -0,0 +1,84 @@
+package wireguard
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/golibs/os"
+)
+
+const (
+	privateKeyPath   = "/tmp/gluetun/wireguard_private.key"
+	preSharedKeyPath = "/tmp/gluetun/wireguard_preshared.key"
+)
+
+// Configure sets the keys and the peer of the Wireguard device using
+// the wg tool. Keys are written to temporary files since wg only
+// accepts keys from files, and these files are removed right after.
+func (c *configurator) Configure(ctx context.Context, settings configuration.Wireguard) (err error) {
+	if err := c.writeKeyFile(privateKeyPath, settings.PrivateKey); err != nil {
+		return err
+	}
+	defer c.removeKeyFile(privateKeyPath)
+
+	const keepAlive = "25"
+	endpoint := net.JoinHostPort(settings.EndpointIP.String(), strconv.Itoa(int(settings.EndpointPort)))
+	args := []string{"set", settings.Interface,
+		"private-key", privateKeyPath,
+		"peer", settings.PublicKey,
+	}
+	if settings.PreSharedKey != "" {
+		if err := c.writeKeyFile(preSharedKeyPath, settings.PreSharedKey); err != nil {
+			return err
+		}
+		defer c.removeKeyFile(preSharedKeyPath)
+		args = append(args, "preshared-key", preSharedKeyPath)
+	}
+	args = append(args,
+		"endpoint", endpoint,
+		"allowed-ips", "0.0.0.0/0",
+		"persistent-keepalive", keepAlive,
+	)
+
+	output, err := c.commander.Run(ctx, "wg", args...)
+	if err != nil {
+		return fmt.Errorf("cannot configure device %s: %w: %s", settings.Interface, err, output)
+	}
+	return nil
+}
+
+func (c *configurator) writeKeyFile(path, key string) error {
+	file, err := c.os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0400)
+	if err != nil {
+		return err
+	}
+	if _, err := file.WriteString(key); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (c *configurator) removeKeyFile(path string) {
+	if err := c.os.Remove(path); err != nil {
+		c.logger.Warn(err)
+	}
+}
+
+func (c *configurator) Version(ctx context.Context) (string, error) {
+	output, err := c.commander.Run(ctx, "wg", "--version")
+	if err != nil {
+		return "", err
+	}
+	firstLine := strings.Split(output, "\n")[0]
+	words := strings.Fields(firstLine)
+	const minWords = 2
+	if len(words) < minWords {
+		return "", fmt.Errorf("wg --version: first line is too short: %q", firstLine)
+	}
+	return words[1], nil
+}
@@ -0,0 +1,206 @@
+package wireguard
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/golibs/command/mock_command"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/qdm12/golibs/os"
+	"github.com/qdm12/golibs/os/mock_os"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_configurator_Configure(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	settings := configuration.Wireguard{
+		Interface:    "wg0",
+		PrivateKey:   "private",
+		PublicKey:    "public",
+		EndpointIP:   net.IPv4(1, 2, 3, 4),
+		EndpointPort: 51820,
+	}
+	withPreSharedKey := settings
+	withPreSharedKey.PreSharedKey = "preshared"
+
+	const flags = os.O_CREATE | os.O_TRUNC | os.O_WRONLY
+	const perm = os.FileMode(0400)
+
+	testCases := map[string]struct {
+		settings     configuration.Wireguard
+		prepareMocks func(ctx context.Context, osMock *mock_os.MockOS,
+			commander *mock_command.MockCommander, ctrl *gomock.Controller)
+		errMessage string
+	}{
+		"private key file not writable": {
+			settings: settings,
+			prepareMocks: func(ctx context.Context, osMock *mock_os.MockOS,
+				commander *mock_command.MockCommander, ctrl *gomock.Controller) {
+				osMock.EXPECT().OpenFile(privateKeyPath, flags, perm).Return(nil, errTest)
+			},
+			errMessage: "test error",
+		},
+		"peer configured": {
+			settings: settings,
+			prepareMocks: func(ctx context.Context, osMock *mock_os.MockOS,
+				commander *mock_command.MockCommander, ctrl *gomock.Controller) {
+				file := mock_os.NewMockFile(ctrl)
+				file.EXPECT().WriteString("private").Return(len("private"), nil)
+				file.EXPECT().Close().Return(nil)
+				osMock.EXPECT().OpenFile(privateKeyPath, flags, perm).Return(file, nil)
+				commander.EXPECT().Run(ctx, "wg", "set", "wg0",
+					"private-key", privateKeyPath,
+					"peer", "public",
+					"endpoint", "1.2.3.4:51820",
+					"allowed-ips", "0.0.0.0/0",
+					"persistent-keepalive", "25").Return("", nil)
+				osMock.EXPECT().Remove(privateKeyPath).Return(nil)
+			},
+		},
+		"peer configured with pre-shared key": {
+			settings: withPreSharedKey,
+			prepareMocks: func(ctx context.Context, osMock *mock_os.MockOS,
+				commander *mock_command.MockCommander, ctrl *gomock.Controller) {
+				privateKeyFile := mock_os.NewMockFile(ctrl)
+				privateKeyFile.EXPECT().WriteString("private").Return(len("private"), nil)
+				privateKeyFile.EXPECT().Close().Return(nil)
+				osMock.EXPECT().OpenFile(privateKeyPath, flags, perm).Return(privateKeyFile, nil)
+				preSharedKeyFile := mock_os.NewMockFile(ctrl)
+				preSharedKeyFile.EXPECT().WriteString("preshared").Return(len("preshared"), nil)
+				preSharedKeyFile.EXPECT().Close().Return(nil)
+				osMock.EXPECT().OpenFile(preSharedKeyPath, flags, perm).Return(preSharedKeyFile, nil)
+				commander.EXPECT().Run(ctx, "wg", "set", "wg0",
+					"private-key", privateKeyPath,
+					"peer", "public",
+					"preshared-key", preSharedKeyPath,
+					"endpoint", "1.2.3.4:51820",
+					"allowed-ips", "0.0.0.0/0",
+					"persistent-keepalive", "25").Return("", nil)
+				osMock.EXPECT().Remove(preSharedKeyPath).Return(nil)
+				osMock.EXPECT().Remove(privateKeyPath).Return(nil)
+			},
+		},
+		"key write failing": {
+			settings: settings,
+			prepareMocks: func(ctx context.Context, osMock *mock_os.MockOS,
+				commander *mock_command.MockCommander, ctrl *gomock.Controller) {
+				file := mock_os.NewMockFile(ctrl)
+				file.EXPECT().WriteString("private").Return(0, errTest)
+				file.EXPECT().Close().Return(nil)
+				osMock.EXPECT().OpenFile(privateKeyPath, flags, perm).Return(file, nil)
+			},
+			errMessage: "test error",
+		},
+		"wg command failing": {
+			settings: settings,
+			prepareMocks: func(ctx context.Context, osMock *mock_os.MockOS,
+				commander *mock_command.MockCommander, ctrl *gomock.Controller) {
+				file := mock_os.NewMockFile(ctrl)
+				file.EXPECT().WriteString("private").Return(len("private"), nil)
+				file.EXPECT().Close().Return(nil)
+				osMock.EXPECT().OpenFile(privateKeyPath, flags, perm).Return(file, nil)
+				commander.EXPECT().Run(ctx, "wg", gomock.Any()).Return("Invalid key", errTest)
+				osMock.EXPECT().Remove(privateKeyPath).Return(nil)
+			},
+			errMessage: "cannot configure device wg0: test error: Invalid key",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+
+			osMock := mock_os.NewMockOS(ctrl)
+			commander := mock_command.NewMockCommander(ctrl)
+			testCase.prepareMocks(ctx, osMock, commander, ctrl)
+
+			c := &configurator{
+				logger:    mock_logging.NewMockLogger(ctrl),
+				commander: commander,
+				os:        osMock,
+			}
+
+			err := c.Configure(ctx, testCase.settings)
+
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_configurator_removeKeyFile(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	errTest := errors.New("test error")
+	osMock := mock_os.NewMockOS(ctrl)
+	osMock.EXPECT().Remove(privateKeyPath).Return(errTest)
+	logger := mock_logging.NewMockLogger(ctrl)
+	logger.EXPECT().Warn(errTest)
+
+	c := &configurator{logger: logger, os: osMock}
+
+	c.removeKeyFile(privateKeyPath)
+}
+
+func Test_configurator_Version(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		output     string
+		runErr     error
+		version    string
+		errMessage string
+	}{
+		"version": {
+			output:  "wireguard-tools v1.0.20210223 - https://git.zx2c4.com/wireguard-tools/\n",
+			version: "v1.0.20210223",
+		},
+		"command error": {
+			runErr:     errTest,
+			errMessage: "test error",
+		},
+		"output too short": {
+			output:     "wireguard-tools",
+			errMessage: `wg --version: first line is too short: "wireguard-tools"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+
+			commander := mock_command.NewMockCommander(ctrl)
+			commander.EXPECT().Run(ctx, "wg", "--version").
+				Return(testCase.output, testCase.runErr)
+			c := &configurator{commander: commander}
+
+			version, err := c.Version(ctx)
+
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.version, version)
+		})
+	}
+}
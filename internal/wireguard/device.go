@@ -0,0 +1,66 @@
+package wireguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/vishvananda/netlink"
+)
+
+// mtu is the default MTU used by wg-quick.
+const mtu = 1420
+
+func (c *configurator) AddDevice(name string) error {
+	c.logger.Info("creating device %s", name)
+	link := &netlink.GenericLink{
+		LinkAttrs: netlink.LinkAttrs{Name: name, MTU: mtu},
+		LinkType:  "wireguard",
+	}
+	if err := netlink.LinkAdd(link); err != nil {
+		return fmt.Errorf("cannot add device %s: %w", name, err)
+	}
+	return nil
+}
+
+// DeleteDevice deletes the device with the given name,
+// and does nothing if the device does not exist.
+func (c *configurator) DeleteDevice(name string) error {
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		if errors.As(err, &netlink.LinkNotFoundError{}) {
+			return nil
+		}
+		return fmt.Errorf("cannot delete device %s: %w", name, err)
+	}
+	c.logger.Info("deleting device %s", name)
+	if err := netlink.LinkDel(link); err != nil {
+		return fmt.Errorf("cannot delete device %s: %w", name, err)
+	}
+	return nil
+}
+
+func (c *configurator) AddAddresses(name string, addresses []net.IPNet) error {
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		return fmt.Errorf("cannot add addresses to device %s: %w", name, err)
+	}
+	for _, address := range addresses {
+		address := address
+		if err := netlink.AddrAdd(link, &netlink.Addr{IPNet: &address}); err != nil {
+			return fmt.Errorf("cannot add address %s to device %s: %w", address.String(), name, err)
+		}
+	}
+	return nil
+}
+
+func (c *configurator) SetDeviceUp(name string) error {
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		return fmt.Errorf("cannot set device %s up: %w", name, err)
+	}
+	if err := netlink.LinkSetUp(link); err != nil {
+		return fmt.Errorf("cannot set device %s up: %w", name, err)
+	}
+	return nil
+}
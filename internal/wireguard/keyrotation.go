@@ -0,0 +1,152 @@
+package wireguard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/golibs/os"
+)
+
+// newKeyRotationTimer returns a timer firing after the key rotation
+// period, or a stopped timer if key rotation is disabled.
+func newKeyRotationTimer(settings configuration.Wireguard) (timer *time.Timer) {
+	if settings.KeyRotationPeriod == 0 {
+		timer = time.NewTimer(time.Hour)
+		timer.Stop()
+		return timer
+	}
+	return time.NewTimer(settings.KeyRotationPeriod)
+}
+
+// rotatedKey is the rotated key data persisted to file, so the key
+// registered and its addresses are used again after a restart.
+type rotatedKey struct {
+	PrivateKey string `json:"private_key"`
+	// Addresses are the interface addresses in CIDR notation.
+	Addresses []string `json:"addresses"`
+}
+
+var ErrRotatedKeyAddress = errors.New("rotated key address is not valid")
+
+// apply returns the settings given using the rotated key and addresses.
+func (k rotatedKey) apply(settings configuration.Wireguard) (
+	applied configuration.Wireguard, err error) {
+	applied = settings
+	applied.PrivateKey = k.PrivateKey
+	if len(k.Addresses) == 0 {
+		return applied, nil
+	}
+	applied.Addresses = make([]net.IPNet, len(k.Addresses))
+	for i, address := range k.Addresses {
+		ip, ipNet, err := net.ParseCIDR(address)
+		if err != nil {
+			return settings, fmt.Errorf("%w: %s", ErrRotatedKeyAddress, err)
+		}
+		ipNet.IP = ip
+		applied.Addresses[i] = *ipNet
+	}
+	return applied, nil
+}
+
+// readRotatedKey returns the last rotated key data persisted to
+// file, or an empty private key if the key was never rotated.
+func (l *looper) readRotatedKey() (key rotatedKey, err error) {
+	file, err := l.openFile(constants.WireguardRotatedKey, os.O_RDONLY, 0)
+	if os.IsNotExist(err) {
+		return key, nil
+	} else if err != nil {
+		return key, err
+	}
+
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&key); err != nil {
+		_ = file.Close()
+		return key, err
+	}
+	return key, file.Close()
+}
+
+// writeRotatedKey persists the private key and addresses of the
+// settings given together, so they cannot get out of sync.
+func (l *looper) writeRotatedKey(settings configuration.Wireguard) error {
+	key := rotatedKey{
+		PrivateKey: settings.PrivateKey,
+		Addresses:  make([]string, len(settings.Addresses)),
+	}
+	for i, address := range settings.Addresses {
+		key.Addresses[i] = address.String()
+	}
+
+	file, err := l.openFile(constants.WireguardRotatedKey, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	if err := encoder.Encode(key); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// rotateKey registers a new key pair with the VPN provider in place
+// of the current one, and returns the settings using the new key.
+func (l *looper) rotateKey(ctx context.Context, settings configuration.Wireguard) (
+	rotated configuration.Wireguard, err error) {
+	oldPublicKey, err := PublicKey(settings.PrivateKey)
+	if err != nil {
+		return rotated, err
+	}
+
+	privateKey, publicKey, err := GenerateKeyPair()
+	if err != nil {
+		return rotated, err
+	}
+
+	addresses, err := l.replaceKey(ctx, l.client,
+		settings.Account, oldPublicKey, publicKey)
+	if err != nil {
+		return rotated, fmt.Errorf("cannot replace key: %w", err)
+	}
+
+	rotated = settings
+	rotated.PrivateKey = privateKey
+	if addresses = keepAddressFamilies(addresses, settings.Addresses); len(addresses) > 0 {
+		rotated.Addresses = addresses
+	}
+
+	// The old key is no longer registered, so use the new key
+	// even if it cannot be persisted to file.
+	if err := l.writeRotatedKey(rotated); err != nil {
+		l.logger.Error("cannot persist rotated key: %s", err)
+	}
+
+	return rotated, nil
+}
+
+// keepAddressFamilies returns the addresses of the IP families
+// (IPv4 or IPv6) already used by the current addresses.
+func keepAddressFamilies(addresses, current []net.IPNet) (kept []net.IPNet) {
+	var ipv4, ipv6 bool
+	for _, address := range current {
+		if address.IP.To4() != nil {
+			ipv4 = true
+		} else {
+			ipv6 = true
+		}
+	}
+
+	for _, address := range addresses {
+		isIPv4 := address.IP.To4() != nil
+		if (isIPv4 && ipv4) || (!isIPv4 && ipv6) {
+			kept = append(kept, address)
+		}
+	}
+	return kept
+}
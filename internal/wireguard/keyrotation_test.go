@@ -0,0 +1,182 @@
+package wireguard
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	nativeos "os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/golibs/os"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_keepAddressFamilies(t *testing.T) {
+	t.Parallel()
+
+	ipv4 := net.IPNet{IP: net.IPv4(10, 1, 2, 3), Mask: net.CIDRMask(32, 32)}
+	ipv6 := net.IPNet{IP: net.ParseIP("fc00::1"), Mask: net.CIDRMask(128, 128)}
+
+	testCases := map[string]struct {
+		addresses []net.IPNet
+		current   []net.IPNet
+		kept      []net.IPNet
+	}{
+		"ipv4 only": {
+			addresses: []net.IPNet{ipv4, ipv6},
+			current:   []net.IPNet{ipv4},
+			kept:      []net.IPNet{ipv4},
+		},
+		"ipv4 and ipv6": {
+			addresses: []net.IPNet{ipv4, ipv6},
+			current:   []net.IPNet{ipv6, ipv4},
+			kept:      []net.IPNet{ipv4, ipv6},
+		},
+		"no current address": {
+			addresses: []net.IPNet{ipv4, ipv6},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			kept := keepAddressFamilies(testCase.addresses, testCase.current)
+
+			assert.Equal(t, testCase.kept, kept)
+		})
+	}
+}
+
+func Test_rotatedKey_apply(t *testing.T) {
+	t.Parallel()
+
+	ipv4 := net.IPNet{IP: net.IPv4(10, 1, 2, 3), Mask: net.CIDRMask(32, 32)}
+	ipv6 := net.IPNet{IP: net.ParseIP("fc00::1"), Mask: net.CIDRMask(128, 128)}
+	settings := configuration.Wireguard{
+		PrivateKey: "old",
+		Addresses:  []net.IPNet{{IP: net.IPv4(10, 0, 0, 1), Mask: net.CIDRMask(32, 32)}},
+	}
+
+	testCases := map[string]struct {
+		key        rotatedKey
+		applied    configuration.Wireguard
+		errMessage string
+	}{
+		"key only": {
+			key: rotatedKey{PrivateKey: "new"},
+			applied: configuration.Wireguard{
+				PrivateKey: "new",
+				Addresses:  settings.Addresses,
+			},
+		},
+		"key and addresses": {
+			key: rotatedKey{PrivateKey: "new", Addresses: []string{"10.1.2.3/32", "fc00::1/128"}},
+			applied: configuration.Wireguard{
+				PrivateKey: "new",
+				Addresses:  []net.IPNet{ipv4, ipv6},
+			},
+		},
+		"invalid address": {
+			key:        rotatedKey{PrivateKey: "new", Addresses: []string{"10.1.2.3"}},
+			applied:    settings,
+			errMessage: "rotated key address is not valid: invalid CIDR address: 10.1.2.3",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			applied, err := testCase.key.apply(settings)
+
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, len(testCase.applied.Addresses), len(applied.Addresses))
+			for i := range testCase.applied.Addresses {
+				assert.Equal(t, testCase.applied.Addresses[i].String(), applied.Addresses[i].String())
+			}
+			assert.Equal(t, testCase.applied.PrivateKey, applied.PrivateKey)
+		})
+	}
+}
+
+func Test_looper_rotateKey(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	privateKey, _, err := GenerateKeyPair()
+	require.NoError(t, err)
+	settings := configuration.Wireguard{
+		PrivateKey: privateKey,
+		Account:    "1234",
+		Addresses:  []net.IPNet{{IP: net.IPv4(10, 0, 0, 1), Mask: net.CIDRMask(32, 32)}},
+	}
+	newAddress := net.IPNet{IP: net.IPv4(10, 1, 2, 3), Mask: net.CIDRMask(32, 32)}
+
+	testCases := map[string]struct {
+		replaceErr error
+		rotated    bool
+	}{
+		"key rotated and persisted": {
+			rotated: true,
+		},
+		"key replacement failed": {
+			replaceErr: errTest,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "wireguard.json")
+			l := &looper{
+				openFile: func(name string, flag int, perm os.FileMode) (os.File, error) {
+					return nativeos.OpenFile(path, flag, nativeos.FileMode(perm))
+				},
+				replaceKey: func(ctx context.Context, client *http.Client,
+					account, oldPublicKey, newPublicKey string) (addresses []net.IPNet, err error) {
+					assert.Equal(t, "1234", account)
+					expectedOldPublicKey, err := PublicKey(privateKey)
+					require.NoError(t, err)
+					assert.Equal(t, expectedOldPublicKey, oldPublicKey)
+					return []net.IPNet{newAddress}, testCase.replaceErr
+				},
+			}
+
+			rotated, err := l.rotateKey(context.Background(), settings)
+
+			key, readErr := l.readRotatedKey()
+			require.NoError(t, readErr)
+
+			if !testCase.rotated {
+				assert.ErrorIs(t, err, testCase.replaceErr)
+				assert.Equal(t, rotatedKey{}, key)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEqual(t, privateKey, rotated.PrivateKey)
+			assert.Equal(t, []net.IPNet{newAddress}, rotated.Addresses)
+
+			// the rotated key and addresses are used again after a restart
+			restarted, err := key.apply(settings)
+			require.NoError(t, err)
+			assert.Equal(t, rotated.PrivateKey, restarted.PrivateKey)
+			require.Len(t, restarted.Addresses, 1)
+			assert.Equal(t, newAddress.String(), restarted.Addresses[0].String())
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package wireguard
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/curve25519"
+)
+
+var ErrPrivateKeyInvalid = errors.New("private key is not valid")
+
+// GenerateKeyPair returns a new base64 encoded Wireguard key pair.
+func GenerateKeyPair() (privateKey, publicKey string, err error) {
+	key := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(key); err != nil {
+		return "", "", fmt.Errorf("cannot generate private key: %w", err)
+	}
+
+	// Clamp the private key as Wireguard does
+	key[0] &= 248
+	key[31] = (key[31] & 127) | 64 //nolint:gomnd
+
+	privateKey = base64.StdEncoding.EncodeToString(key)
+	publicKey, err = PublicKey(privateKey)
+	if err != nil {
+		return "", "", err
+	}
+	return privateKey, publicKey, nil
+}
+
+// PublicKey returns the base64 encoded public key
+// of the base64 encoded private key given.
+func PublicKey(privateKey string) (publicKey string, err error) {
+	key, err := base64.StdEncoding.DecodeString(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrPrivateKeyInvalid, err)
+	}
+
+	b, err := curve25519.X25519(key, curve25519.Basepoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrPrivateKeyInvalid, err)
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
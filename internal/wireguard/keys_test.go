@@ -0,0 +1,57 @@
+package wireguard
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GenerateKeyPair(t *testing.T) {
+	t.Parallel()
+
+	privateKey, publicKey, err := GenerateKeyPair()
+	require.NoError(t, err)
+
+	b, err := base64.StdEncoding.DecodeString(privateKey)
+	require.NoError(t, err)
+	assert.Len(t, b, 32)
+	assert.Zero(t, b[0]&7)
+
+	expectedPublicKey, err := PublicKey(privateKey)
+	require.NoError(t, err)
+	assert.Equal(t, expectedPublicKey, publicKey)
+}
+
+func Test_PublicKey(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		privateKey string
+		publicKey  string
+		err        error
+	}{
+		"invalid base64": {
+			privateKey: "x",
+			err:        ErrPrivateKeyInvalid,
+		},
+		"valid key": {
+			// Key pair generated with wg genkey and wg pubkey
+			privateKey: "yAnz5TF+lXXJte14tji3zlMNq+hd2rYUIgJBgB3fBmk=",
+			publicKey:  "HIgo9xNzJMWLKASShiTqIybxZ0U3wGLiUeJ1PKf8ykw=",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			publicKey, err := PublicKey(testCase.privateKey)
+
+			assert.ErrorIs(t, err, testCase.err)
+			assert.Equal(t, testCase.publicKey, publicKey)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package wireguard
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider"
+	"github.com/qdm12/gluetun/internal/routing"
+	"github.com/qdm12/golibs/logging"
+	"github.com/qdm12/golibs/os"
+)
+
+type Looper interface {
+	Run(ctx context.Context, wg *sync.WaitGroup)
+	GetStatus() (status models.LoopStatus)
+	SetStatus(status models.LoopStatus) (outcome string, err error)
+	GetSettings() (settings configuration.Wireguard)
+	SetSettings(settings configuration.Wireguard) (outcome string)
+}
+
+type looper struct {
+	state state
+	// Configurators
+	conf    Configurator
+	fw      firewall.Configurator
+	routing routing.Routing
+	// Other objects
+	logger      logging.Logger
+	client      *http.Client
+	openFile    os.OpenFileFunc
+	tunnelReady chan<- struct{}
+	replaceKey  func(ctx context.Context, client *http.Client,
+		account, oldPublicKey, newPublicKey string) (addresses []net.IPNet, err error)
+	// Internal channels and locks
+	loopLock      sync.Mutex
+	running       chan models.LoopStatus
+	stop, stopped chan struct{}
+	start         chan struct{}
+	crashed       bool
+	backoffTime   time.Duration
+}
+
+const defaultBackoffTime = 15 * time.Second
+
+func NewLooper(settings configuration.Wireguard,
+	conf Configurator, fw firewall.Configurator, routing routing.Routing,
+	logger logging.Logger, client *http.Client, openFile os.OpenFileFunc,
+	tunnelReady chan<- struct{}) Looper {
+	return &looper{
+		state: state{
+			status:   constants.Stopped,
+			settings: settings,
+		},
+		conf:        conf,
+		fw:          fw,
+		routing:     routing,
+		logger:      logger.NewChild(logging.SetPrefix("wireguard: ")),
+		client:      client,
+		openFile:    openFile,
+		tunnelReady: tunnelReady,
+		replaceKey:  provider.MullvadReplaceWireguardKey,
+		start:       make(chan struct{}),
+		running:     make(chan models.LoopStatus),
+		stop:        make(chan struct{}),
+		stopped:     make(chan struct{}),
+		backoffTime: defaultBackoffTime,
+	}
+}
+
+func (l *looper) signalCrashedStatus() {
+	if !l.crashed {
+		l.crashed = true
+		l.running <- constants.Crashed
+	}
+}
+
+func (l *looper) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	select {
+	case <-l.start:
+	case <-ctx.Done():
+		return
+	}
+	defer l.logger.Warn("loop exited")
+
+	if settings := l.state.getSettings(); settings.KeyRotationPeriod > 0 {
+		key, err := l.readRotatedKey()
+		if err == nil && key.PrivateKey != "" {
+			settings, err = key.apply(settings)
+			if err == nil {
+				l.state.setSettings(settings)
+			}
+		}
+		if err != nil {
+			l.logger.Error("cannot read rotated key: %s", err)
+		}
+	}
+
+	for ctx.Err() == nil {
+		settings := l.state.getSettings()
+
+		if err := l.setup(ctx, settings); err != nil {
+			l.teardown(ctx, settings)
+			l.signalCrashedStatus()
+			l.logAndWait(ctx, err)
+			continue
+		}
+
+		l.tunnelReady <- struct{}{}
+
+		if l.crashed {
+			l.crashed = false
+			l.backoffTime = defaultBackoffTime
+			l.state.setStatusWithLock(constants.Running)
+		} else {
+			l.running <- constants.Running
+		}
+
+		keyRotationTimer := newKeyRotationTimer(settings)
+
+		stayHere := true
+		for stayHere {
+			select {
+			case <-ctx.Done():
+				l.logger.Warn("context canceled: exiting loop")
+				keyRotationTimer.Stop()
+				l.teardown(ctx, settings)
+				return
+			case <-l.stop:
+				l.logger.Info("stopping")
+				l.teardown(ctx, settings)
+				l.stopped <- struct{}{}
+			case <-l.start:
+				l.logger.Info("starting")
+				stayHere = false
+			case <-keyRotationTimer.C:
+				rotated, err := l.rotateKey(ctx, settings)
+				if err != nil {
+					l.logger.Error("cannot rotate key: %s", err)
+					keyRotationTimer.Reset(settings.KeyRotationPeriod)
+					break
+				}
+				l.state.setSettings(rotated)
+				l.logger.Info("reconnecting with the rotated key")
+				l.teardown(ctx, settings)
+				// Do not signal the running status again once reconnected
+				l.state.setStatusWithLock(constants.Starting)
+				l.crashed = true
+				stayHere = false
+			}
+		}
+		keyRotationTimer.Stop()
+	}
+}
+
+func (l *looper) setup(ctx context.Context, settings configuration.Wireguard) (err error) {
+	connection := models.OpenVPNConnection{
+		IP:       settings.EndpointIP,
+		Port:     settings.EndpointPort,
+		Protocol: constants.UDP,
+	}
+	if err := l.fw.SetVPNConnection(ctx, connection); err != nil {
+		return err
+	}
+
+	if err := l.fw.SetVPNInterface(ctx, settings.Interface); err != nil {
+		return err
+	}
+
+	// Remove any device left over from a previous run
+	if err := l.conf.DeleteDevice(settings.Interface); err != nil {
+		return err
+	}
+
+	if err := l.conf.AddDevice(settings.Interface); err != nil {
+		return err
+	}
+
+	if err := l.conf.Configure(ctx, settings); err != nil {
+		return err
+	}
+
+	if err := l.conf.AddAddresses(settings.Interface, settings.Addresses); err != nil {
+		return err
+	}
+
+	if err := l.conf.SetDeviceUp(settings.Interface); err != nil {
+		return err
+	}
+
+	return l.routing.SetVPNRoutes(settings.Interface, settings.EndpointIP)
+}
+
+func (l *looper) teardown(ctx context.Context, settings configuration.Wireguard) {
+	if err := l.routing.RemoveVPNRoutes(settings.Interface, settings.EndpointIP); err != nil {
+		l.logger.Error(err)
+	}
+	if err := l.conf.DeleteDevice(settings.Interface); err != nil {
+		l.logger.Error(err)
+	}
+	if ctx.Err() != nil {
+		return // exiting
+	}
+	if err := l.fw.FlushTunnelConnections(ctx); err != nil {
+		l.logger.Warn(err)
+	}
+}
+
+func (l *looper) logAndWait(ctx context.Context, err error) {
+	if err != nil {
+		l.logger.Error(err)
+	}
+	l.logger.Info("retrying in %s", l.backoffTime)
+	timer := time.NewTimer(l.backoffTime)
+	l.backoffTime *= 2
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		if !timer.Stop() {
+			<-timer.C
+		}
+	}
+}
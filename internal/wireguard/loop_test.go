@@ -0,0 +1,172 @@
+package wireguard
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/firewall/mock_firewall"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/routing/mock_routing"
+	"github.com/qdm12/gluetun/internal/wireguard/mock_wireguard"
+	"github.com/qdm12/golibs/logging/mock_logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_looper_setup(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	settings := configuration.Wireguard{
+		Interface:    "wg0",
+		EndpointIP:   net.IPv4(1, 2, 3, 4),
+		EndpointPort: 51820,
+		Addresses:    []net.IPNet{{IP: net.IPv4(10, 0, 0, 1), Mask: net.CIDRMask(32, 32)}},
+	}
+	connection := models.OpenVPNConnection{
+		IP:       settings.EndpointIP,
+		Port:     settings.EndpointPort,
+		Protocol: constants.UDP,
+	}
+
+	type mocks struct {
+		conf    *mock_wireguard.MockConfigurator
+		fw      *mock_firewall.MockConfigurator
+		routing *mock_routing.MockRouting
+	}
+	// steps are the setup steps in order, each returning the error given.
+	steps := []func(ctx context.Context, m mocks, err error) *gomock.Call{
+		func(ctx context.Context, m mocks, err error) *gomock.Call {
+			return m.fw.EXPECT().SetVPNConnection(ctx, connection).Return(err)
+		},
+		func(ctx context.Context, m mocks, err error) *gomock.Call {
+			return m.fw.EXPECT().SetVPNInterface(ctx, "wg0").Return(err)
+		},
+		func(ctx context.Context, m mocks, err error) *gomock.Call {
+			return m.conf.EXPECT().DeleteDevice("wg0").Return(err)
+		},
+		func(ctx context.Context, m mocks, err error) *gomock.Call {
+			return m.conf.EXPECT().AddDevice("wg0").Return(err)
+		},
+		func(ctx context.Context, m mocks, err error) *gomock.Call {
+			return m.conf.EXPECT().Configure(ctx, settings).Return(err)
+		},
+		func(ctx context.Context, m mocks, err error) *gomock.Call {
+			return m.conf.EXPECT().AddAddresses("wg0", settings.Addresses).Return(err)
+		},
+		func(ctx context.Context, m mocks, err error) *gomock.Call {
+			return m.conf.EXPECT().SetDeviceUp("wg0").Return(err)
+		},
+		func(ctx context.Context, m mocks, err error) *gomock.Call {
+			return m.routing.EXPECT().SetVPNRoutes("wg0", settings.EndpointIP).Return(err)
+		},
+	}
+
+	testCases := map[string]struct {
+		failingStep int // -1 for no failure
+	}{
+		"success":                  {failingStep: -1},
+		"VPN connection failing":   {failingStep: 0},
+		"VPN interface failing":    {failingStep: 1},
+		"device deletion failing":  {failingStep: 2},
+		"device creation failing":  {failingStep: 3},
+		"peer configuration fails": {failingStep: 4},
+		"addresses failing":        {failingStep: 5},
+		"device up failing":        {failingStep: 6},
+		"routes failing":           {failingStep: 7},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+
+			m := mocks{
+				conf:    mock_wireguard.NewMockConfigurator(ctrl),
+				fw:      mock_firewall.NewMockConfigurator(ctrl),
+				routing: mock_routing.NewMockRouting(ctrl),
+			}
+			var calls []*gomock.Call
+			for i, step := range steps {
+				if i == testCase.failingStep {
+					calls = append(calls, step(ctx, m, errTest))
+					break
+				}
+				calls = append(calls, step(ctx, m, nil))
+			}
+			gomock.InOrder(calls...)
+
+			l := &looper{conf: m.conf, fw: m.fw, routing: m.routing}
+
+			err := l.setup(ctx, settings)
+
+			if testCase.failingStep >= 0 {
+				assert.ErrorIs(t, err, errTest)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_looper_teardown(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	settings := configuration.Wireguard{
+		Interface:  "wg0",
+		EndpointIP: net.IPv4(1, 2, 3, 4),
+	}
+
+	testCases := map[string]struct {
+		canceled bool
+		err      error
+	}{
+		"teardown": {},
+		"teardown errors logged": {
+			err: errTest,
+		},
+		"exiting": {
+			canceled: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if testCase.canceled {
+				cancel()
+			}
+
+			logger := mock_logging.NewMockLogger(ctrl)
+			routing := mock_routing.NewMockRouting(ctrl)
+			routing.EXPECT().RemoveVPNRoutes("wg0", settings.EndpointIP).Return(testCase.err)
+			conf := mock_wireguard.NewMockConfigurator(ctrl)
+			conf.EXPECT().DeleteDevice("wg0").Return(testCase.err)
+			fw := mock_firewall.NewMockConfigurator(ctrl)
+			if !testCase.canceled {
+				fw.EXPECT().FlushTunnelConnections(ctx).Return(testCase.err)
+			}
+			if testCase.err != nil {
+				logger.EXPECT().Error(testCase.err).Times(2)
+				logger.EXPECT().Warn(testCase.err)
+			}
+
+			l := &looper{conf: conf, fw: fw, routing: routing, logger: logger}
+
+			l.teardown(ctx, settings)
+		})
+	}
+}
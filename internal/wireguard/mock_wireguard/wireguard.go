@@ -0,0 +1,122 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/qdm12/gluetun/internal/wireguard (interfaces: Configurator)
+
+// Package mock_wireguard is a generated GoMock package.
+package mock_wireguard
+
+import (
+	context "context"
+	net "net"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	configuration "github.com/qdm12/gluetun/internal/configuration"
+)
+
+// MockConfigurator is a mock of Configurator interface.
+type MockConfigurator struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfiguratorMockRecorder
+}
+
+// MockConfiguratorMockRecorder is the mock recorder for MockConfigurator.
+type MockConfiguratorMockRecorder struct {
+	mock *MockConfigurator
+}
+
+// NewMockConfigurator creates a new mock instance.
+func NewMockConfigurator(ctrl *gomock.Controller) *MockConfigurator {
+	mock := &MockConfigurator{ctrl: ctrl}
+	mock.recorder = &MockConfiguratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfigurator) EXPECT() *MockConfiguratorMockRecorder {
+	return m.recorder
+}
+
+// AddAddresses mocks base method.
+func (m *MockConfigurator) AddAddresses(arg0 string, arg1 []net.IPNet) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAddresses", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAddresses indicates an expected call of AddAddresses.
+func (mr *MockConfiguratorMockRecorder) AddAddresses(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAddresses", reflect.TypeOf((*MockConfigurator)(nil).AddAddresses), arg0, arg1)
+}
+
+// AddDevice mocks base method.
+func (m *MockConfigurator) AddDevice(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDevice", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDevice indicates an expected call of AddDevice.
+func (mr *MockConfiguratorMockRecorder) AddDevice(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDevice", reflect.TypeOf((*MockConfigurator)(nil).AddDevice), arg0)
+}
+
+// Configure mocks base method.
+func (m *MockConfigurator) Configure(arg0 context.Context, arg1 configuration.Wireguard) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Configure", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Configure indicates an expected call of Configure.
+func (mr *MockConfiguratorMockRecorder) Configure(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Configure", reflect.TypeOf((*MockConfigurator)(nil).Configure), arg0, arg1)
+}
+
+// DeleteDevice mocks base method.
+func (m *MockConfigurator) DeleteDevice(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDevice", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDevice indicates an expected call of DeleteDevice.
+func (mr *MockConfiguratorMockRecorder) DeleteDevice(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDevice", reflect.TypeOf((*MockConfigurator)(nil).DeleteDevice), arg0)
+}
+
+// SetDeviceUp mocks base method.
+func (m *MockConfigurator) SetDeviceUp(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDeviceUp", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDeviceUp indicates an expected call of SetDeviceUp.
+func (mr *MockConfiguratorMockRecorder) SetDeviceUp(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeviceUp", reflect.TypeOf((*MockConfigurator)(nil).SetDeviceUp), arg0)
+}
+
+// Version mocks base method.
+func (m *MockConfigurator) Version(arg0 context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Version", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Version indicates an expected call of Version.
+func (mr *MockConfiguratorMockRecorder) Version(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Version", reflect.TypeOf((*MockConfigurator)(nil).Version), arg0)
+}
@@ -0,0 +1,38 @@
+// Package wireguard defines interfaces to interact with Wireguard
+// and run it in a stateful loop.
+package wireguard
+
+import (
+	"context"
+	"net"
+
+	"github.com/qdm12/gluetun/internal/configuration"
+	"github.com/qdm12/golibs/command"
+	"github.com/qdm12/golibs/logging"
+	"github.com/qdm12/golibs/os"
+)
+
+//go:generate mockgen -destination=mock_$GOPACKAGE/$GOFILE . Configurator
+
+type Configurator interface {
+	Version(ctx context.Context) (string, error)
+	AddDevice(name string) error
+	DeleteDevice(name string) error
+	Configure(ctx context.Context, settings configuration.Wireguard) error
+	AddAddresses(name string, addresses []net.IPNet) error
+	SetDeviceUp(name string) error
+}
+
+type configurator struct {
+	logger    logging.Logger
+	commander command.Commander
+	os        os.OS
+}
+
+func NewConfigurator(logger logging.Logger, os os.OS) Configurator {
+	return &configurator{
+		logger:    logger.NewChild(logging.SetPrefix("wireguard configurator: ")),
+		commander: command.NewCommander(),
+		os:        os,
+	}
+}
@@ -0,0 +1,215 @@
+// Package client is a Go client for the v1 API of the Gluetun
+// control server, as specified in its /v1/openapi.json document.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrBadStatusCode = errors.New("bad HTTP status code")
+)
+
+// Client is a client for the control server v1 API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	apiKey     string
+	user       string
+	password   string
+}
+
+// Option is an option to create the client.
+type Option func(c *Client)
+
+// WithHTTPClient sets the HTTP client to use, which
+// defaults to http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithAPIKey sets the API key sent in the X-API-Key header.
+func WithAPIKey(apiKey string) Option {
+	return func(c *Client) { c.apiKey = apiKey }
+}
+
+// WithBasicAuth sets the HTTP basic authentication credentials.
+func WithBasicAuth(user, password string) Option {
+	return func(c *Client) {
+		c.user = user
+		c.password = password
+	}
+}
+
+// New creates a client for the control server at the base URL
+// given, such as http://localhost:8000.
+func New(baseURL string, options ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/") + "/v1",
+		httpClient: http.DefaultClient,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// do sends a request to the path with the JSON encoding of body if
+// it is not nil, and decodes the JSON response into result if it
+// is not nil.
+func (c *Client) do(ctx context.Context, method, path string,
+	body, result interface{}) (err error) {
+	response, err := c.send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if result != nil {
+		decoder := json.NewDecoder(response.Body)
+		if err := decoder.Decode(result); err != nil {
+			return fmt.Errorf("cannot decode response for %s %s: %w", method, path, err)
+		}
+	}
+
+	return response.Body.Close()
+}
+
+// doText sends a request to the path and returns the text response.
+func (c *Client) doText(ctx context.Context, method, path string) (text string, err error) {
+	response, err := c.send(ctx, method, path, nil)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	b, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(string(b), "\n"), response.Body.Close()
+}
+
+func (c *Client) send(ctx context.Context, method, path string,
+	body interface{}) (response *http.Response, err error) {
+	var bodyReader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		bodyReader = bytes.NewReader(b)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		request.Header.Set("X-API-Key", c.apiKey)
+	}
+	if c.user != "" {
+		request.SetBasicAuth(c.user, c.password)
+	}
+
+	response, err = c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		const maxErrorLength = 1024
+		b, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorLength))
+		_ = response.Body.Close()
+		return nil, fmt.Errorf("%w: %s for %s %s: %s", ErrBadStatusCode,
+			response.Status, method, path, strings.TrimSpace(string(b)))
+	}
+
+	return response, nil
+}
+
+// Version returns the build information of the server.
+func (c *Client) Version(ctx context.Context) (info BuildInformation, err error) {
+	err = c.do(ctx, http.MethodGet, "/version", nil, &info)
+	return info, err
+}
+
+// Settings returns the settings in use, with the secrets redacted.
+func (c *Client) Settings(ctx context.Context) (settings json.RawMessage, err error) {
+	err = c.do(ctx, http.MethodGet, "/settings", nil, &settings)
+	return settings, err
+}
+
+// ReloadSettings reloads the settings which can be changed
+// without restarting, and returns the outcome.
+func (c *Client) ReloadSettings(ctx context.Context) (outcome string, err error) {
+	var data outcomeWrapper
+	err = c.do(ctx, http.MethodPost, "/settings/reload", nil, &data)
+	return data.Outcome, err
+}
+
+// Logs returns the last tail log lines, or all the lines kept if tail
+// is 0, of the level given and more severe levels, or of all levels
+// if level is empty.
+func (c *Client) Logs(ctx context.Context, tail int, level string) (lines []LogLine, err error) {
+	values := url.Values{}
+	if tail > 0 {
+		values.Set("tail", strconv.Itoa(tail))
+	}
+	if level != "" {
+		values.Set("level", level)
+	}
+	path := "/logs"
+	if len(values) > 0 {
+		path += "?" + values.Encode()
+	}
+	var data logsWrapper
+	err = c.do(ctx, http.MethodGet, path, nil, &data)
+	return data.Logs, err
+}
+
+// OpenAPISpec returns the OpenAPI specification served.
+func (c *Client) OpenAPISpec(ctx context.Context) (spec json.RawMessage, err error) {
+	err = c.do(ctx, http.MethodGet, "/openapi.json", nil, &spec)
+	return spec, err
+}
+
+// Servers returns the servers of the VPN provider, or of the current
+// provider if it is empty, matching the filters.
+func (c *Client) Servers(ctx context.Context, provider string,
+	filters ServerFilters) (servers Servers, err error) {
+	values := url.Values{}
+	if provider != "" {
+		values.Set("provider", provider)
+	}
+	for key, possibilities := range map[string][]string{
+		"country":  filters.Countries,
+		"region":   filters.Regions,
+		"city":     filters.Cities,
+		"hostname": filters.Hostnames,
+	} {
+		for _, possibility := range possibilities {
+			values.Add(key, possibility)
+		}
+	}
+
+	path := "/servers"
+	if len(values) > 0 {
+		path += "?" + values.Encode()
+	}
+	err = c.do(ctx, http.MethodGet, path, nil, &servers)
+	return servers, err
+}
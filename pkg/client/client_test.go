@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Client(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "key" {
+			http.Error(w, "invalid API key", http.StatusUnauthorized)
+			return
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "PUT /v1/openvpn/status":
+			_, _ = w.Write([]byte(`{"outcome":"stopped"}` + "\n"))
+		case "GET /v1/servers":
+			assert.Equal(t, "pia", r.URL.Query().Get("provider"))
+			assert.Equal(t, []string{"canada", "france"}, r.URL.Query()["country"])
+			_, _ = w.Write([]byte(`{"provider":"private internet access","servers":[{"region":"CA Montreal"}]}`))
+		case "GET /v1/portforward/events":
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("event: portforward\ndata: {\"provider\":\"pia\",\"port\":0}\n\n" +
+				"event: portforward\ndata: {\"provider\":\"pia\",\"port\":5000}\n\n"))
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+	client := New(server.URL+"/", WithAPIKey("key"))
+
+	outcome, err := client.SetOpenVPNStatus(ctx, StatusStopped)
+	require.NoError(t, err)
+	assert.Equal(t, "stopped", outcome)
+
+	servers, err := client.Servers(ctx, "pia", ServerFilters{Countries: []string{"canada", "france"}})
+	require.NoError(t, err)
+	expected := Servers{
+		Provider: "private internet access",
+		Servers:  []map[string]interface{}{{"region": "CA Montreal"}},
+	}
+	assert.Equal(t, expected, servers)
+
+	events, err := client.PortForwardEvents(ctx)
+	require.NoError(t, err)
+	var ports []uint16
+	for status := range events {
+		ports = append(ports, status.Port)
+	}
+	assert.Equal(t, []uint16{0, 5000}, ports)
+
+	_, err = client.DNSStats(ctx)
+	assert.ErrorIs(t, err, ErrBadStatusCode)
+
+	_, err = New(server.URL).PublicIP(ctx)
+	require.ErrorIs(t, err, ErrBadStatusCode)
+	assert.EqualError(t, err, "bad HTTP status code: 401 Unauthorized for GET /publicip/ip: invalid API key")
+}
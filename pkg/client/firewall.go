@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// FirewallEnabled returns true if the firewall is enabled.
+func (c *Client) FirewallEnabled(ctx context.Context) (enabled bool, err error) {
+	var data firewallStatusWrapper
+	err = c.do(ctx, http.MethodGet, "/firewall/status", nil, &data)
+	return data.Enabled, err
+}
+
+// SetFirewallEnabled enables or disables the firewall. When disabling
+// it, a duration greater than zero re-enables it after the duration.
+func (c *Client) SetFirewallEnabled(ctx context.Context, enabled bool,
+	duration time.Duration) (outcome string, err error) {
+	body := firewallStatusWrapper{Enabled: enabled}
+	if duration > 0 {
+		body.Duration = duration.String()
+	}
+	var data outcomeWrapper
+	err = c.do(ctx, http.MethodPut, "/firewall/status", body, &data)
+	return data.Outcome, err
+}
+
+// FirewallPorts returns the input ports allowed through the firewall.
+func (c *Client) FirewallPorts(ctx context.Context) (ports []FirewallPort, err error) {
+	err = c.do(ctx, http.MethodGet, "/firewall/ports", nil, &ports)
+	return ports, err
+}
+
+// SetFirewallPort allows the input port through the firewall on
+// the interface, which must be vpn or lan.
+func (c *Client) SetFirewallPort(ctx context.Context, port uint16,
+	intf string) (outcome string, err error) {
+	var data outcomeWrapper
+	body := FirewallPort{Port: port, Interface: intf}
+	err = c.do(ctx, http.MethodPut, "/firewall/ports", body, &data)
+	return data.Outcome, err
+}
+
+// RemoveFirewallPort removes the input port allowed through the firewall.
+func (c *Client) RemoveFirewallPort(ctx context.Context, port uint16) (outcome string, err error) {
+	var data outcomeWrapper
+	err = c.do(ctx, http.MethodDelete, "/firewall/ports", FirewallPort{Port: port}, &data)
+	return data.Outcome, err
+}
+
+// FirewallRules returns the firewall rules as text.
+func (c *Client) FirewallRules(ctx context.Context) (rules string, err error) {
+	return c.doText(ctx, http.MethodGet, "/firewall/rules")
+}
+
+// FirewallCounters returns the firewall rules counters as text.
+func (c *Client) FirewallCounters(ctx context.Context) (counters string, err error) {
+	return c.doText(ctx, http.MethodGet, "/firewall/counters")
+}
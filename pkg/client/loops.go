@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+func (c *Client) getStatus(ctx context.Context, path string) (status string, err error) {
+	var data statusWrapper
+	err = c.do(ctx, http.MethodGet, path, nil, &data)
+	return data.Status, err
+}
+
+func (c *Client) setStatus(ctx context.Context, path, status string) (outcome string, err error) {
+	var data outcomeWrapper
+	err = c.do(ctx, http.MethodPut, path, statusWrapper{Status: status}, &data)
+	return data.Outcome, err
+}
+
+// OpenVPNStatus returns the status of the OpenVPN loop.
+func (c *Client) OpenVPNStatus(ctx context.Context) (status string, err error) {
+	return c.getStatus(ctx, "/openvpn/status")
+}
+
+// SetOpenVPNStatus sets the status of the OpenVPN loop
+// to StatusRunning or StatusStopped.
+func (c *Client) SetOpenVPNStatus(ctx context.Context, status string) (outcome string, err error) {
+	return c.setStatus(ctx, "/openvpn/status", status)
+}
+
+// OpenVPNState returns the state of the OpenVPN connection.
+func (c *Client) OpenVPNState(ctx context.Context) (state ConnectionState, err error) {
+	err = c.do(ctx, http.MethodGet, "/openvpn/state", nil, &state)
+	return state, err
+}
+
+// OpenVPNSettings returns the OpenVPN settings, with the
+// credentials redacted.
+func (c *Client) OpenVPNSettings(ctx context.Context) (settings json.RawMessage, err error) {
+	err = c.do(ctx, http.MethodGet, "/openvpn/settings", nil, &settings)
+	return settings, err
+}
+
+// PortForwarded returns the port forwarded, and 0 if there is none.
+func (c *Client) PortForwarded(ctx context.Context) (port uint16, err error) {
+	var data portWrapper
+	err = c.do(ctx, http.MethodGet, "/openvpn/portforwarded", nil, &data)
+	return data.Port, err
+}
+
+// SwitchServer switches to another server matching the settings.
+func (c *Client) SwitchServer(ctx context.Context) (outcome string, err error) {
+	var data outcomeWrapper
+	err = c.do(ctx, http.MethodPut, "/openvpn/switch", nil, &data)
+	return data.Outcome, err
+}
+
+// VPNSettings returns the VPN provider and server filters.
+func (c *Client) VPNSettings(ctx context.Context) (settings VPNSettings, err error) {
+	err = c.do(ctx, http.MethodGet, "/vpn/settings", nil, &settings)
+	return settings, err
+}
+
+// SetVPNSettings sets the VPN provider and server filters,
+// and reconnects to a server matching them.
+func (c *Client) SetVPNSettings(ctx context.Context, settings VPNSettings) (outcome string, err error) {
+	var data outcomeWrapper
+	err = c.do(ctx, http.MethodPut, "/vpn/settings", settings, &data)
+	return data.Outcome, err
+}
+
+// PauseVPN stops the tunnel while the firewall keeps blocking
+// traffic, until ResumeVPN is called.
+func (c *Client) PauseVPN(ctx context.Context) (outcome string, err error) {
+	var data outcomeWrapper
+	err = c.do(ctx, http.MethodPost, "/vpn/pause", nil, &data)
+	return data.Outcome, err
+}
+
+// ResumeVPN starts the tunnel again if it is paused.
+func (c *Client) ResumeVPN(ctx context.Context) (outcome string, err error) {
+	var data outcomeWrapper
+	err = c.do(ctx, http.MethodPost, "/vpn/resume", nil, &data)
+	return data.Outcome, err
+}
+
+// DNSStatus returns the status of the DNS loop.
+func (c *Client) DNSStatus(ctx context.Context) (status string, err error) {
+	return c.getStatus(ctx, "/dns/status")
+}
+
+// SetDNSStatus sets the status of the DNS loop
+// to StatusRunning or StatusStopped.
+func (c *Client) SetDNSStatus(ctx context.Context, status string) (outcome string, err error) {
+	return c.setStatus(ctx, "/dns/status", status)
+}
+
+// DNSStats returns the statistics of the DNS server.
+func (c *Client) DNSStats(ctx context.Context) (stats DNSStats, err error) {
+	err = c.do(ctx, http.MethodGet, "/dns/stats", nil, &stats)
+	return stats, err
+}
+
+// DNSQueries returns the recent queries of the DNS server.
+func (c *Client) DNSQueries(ctx context.Context) (queries []DNSQuery, err error) {
+	var data queriesWrapper
+	err = c.do(ctx, http.MethodGet, "/dns/queries", nil, &data)
+	return data.Queries, err
+}
+
+// FlushDNSCache flushes the cache of the DNS server.
+func (c *Client) FlushDNSCache(ctx context.Context) (outcome string, err error) {
+	var data outcomeWrapper
+	err = c.do(ctx, http.MethodPost, "/dns/cache/flush", nil, &data)
+	return data.Outcome, err
+}
+
+// RestartDNS restarts the DNS server if it is running.
+func (c *Client) RestartDNS(ctx context.Context) (outcome string, err error) {
+	var data outcomeWrapper
+	err = c.do(ctx, http.MethodPost, "/dns/restart", nil, &data)
+	return data.Outcome, err
+}
+
+// ReloadDNSBlocklists downloads the DNS block lists again
+// and returns once done.
+func (c *Client) ReloadDNSBlocklists(ctx context.Context) (outcome string, err error) {
+	var data outcomeWrapper
+	err = c.do(ctx, http.MethodPost, "/dns/blocklists/reload", nil, &data)
+	return data.Outcome, err
+}
+
+// DNSBlockCategories returns the DNS block categories enabled.
+func (c *Client) DNSBlockCategories(ctx context.Context) (categories []string, err error) {
+	var data blockCategoriesWrapper
+	err = c.do(ctx, http.MethodGet, "/dns/blocklists/categories", nil, &data)
+	return data.Categories, err
+}
+
+// SetDNSBlockCategories sets the DNS block categories, which can be
+// malicious, surveillance and ads, and updates the block list.
+func (c *Client) SetDNSBlockCategories(ctx context.Context, categories []string) (outcome string, err error) {
+	var data outcomeWrapper
+	body := blockCategoriesWrapper{Categories: categories}
+	err = c.do(ctx, http.MethodPut, "/dns/blocklists/categories", body, &data)
+	return data.Outcome, err
+}
+
+// UpdaterStatus returns the status of the servers updater loop.
+func (c *Client) UpdaterStatus(ctx context.Context) (status string, err error) {
+	return c.getStatus(ctx, "/updater/status")
+}
+
+// SetUpdaterStatus sets the status of the servers updater
+// loop to StatusRunning or StatusStopped.
+func (c *Client) SetUpdaterStatus(ctx context.Context, status string) (outcome string, err error) {
+	return c.setStatus(ctx, "/updater/status", status)
+}
+
+// RunUpdate updates and stores the servers of the VPN providers given,
+// or of the providers set in the settings if none is given, and
+// returns the update result of each provider once done.
+func (c *Client) RunUpdate(ctx context.Context, providers ...string) (results []ServersUpdate, err error) {
+	var data updateResultsWrapper
+	body := updaterRunWrapper{Providers: providers}
+	err = c.do(ctx, http.MethodPost, "/updater/run", body, &data)
+	return data.Results, err
+}
+
+// PublicIP returns the public IP address found.
+func (c *Client) PublicIP(ctx context.Context) (publicIP string, err error) {
+	info, err := c.PublicIPInfo(ctx)
+	return info.PublicIP, err
+}
+
+// PublicIPInfo returns the public IP address found with its
+// location and organization.
+func (c *Client) PublicIPInfo(ctx context.Context) (info PublicIPInfo, err error) {
+	err = c.do(ctx, http.MethodGet, "/publicip/ip", nil, &info)
+	return info, err
+}
+
+// PublicIPHistory returns the last public IP addresses found, oldest first.
+func (c *Client) PublicIPHistory(ctx context.Context) (records []PublicIPRecord, err error) {
+	var data publicIPHistoryWrapper
+	err = c.do(ctx, http.MethodGet, "/publicip/history", nil, &data)
+	return data.Records, err
+}
+
+// PublicIPComparison returns the comparison of the public IP address
+// with the public IP address before connecting to the VPN.
+func (c *Client) PublicIPComparison(ctx context.Context) (comparison PublicIPComparison, err error) {
+	err = c.do(ctx, http.MethodGet, "/publicip/comparison", nil, &comparison)
+	return comparison, err
+}
+
+// HealthHistory returns the results of the last health check runs, oldest first.
+func (c *Client) HealthHistory(ctx context.Context) (probes []HealthProbe, err error) {
+	var data healthHistoryWrapper
+	err = c.do(ctx, http.MethodGet, "/health/history", nil, &data)
+	return data.Probes, err
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// PortForward returns the status of the port forwarded.
+func (c *Client) PortForward(ctx context.Context) (status PortForwardStatus, err error) {
+	err = c.do(ctx, http.MethodGet, "/portforward", nil, &status)
+	return status, err
+}
+
+// RenewPortForward requests a new port forwarded, without
+// waiting for it to be obtained.
+func (c *Client) RenewPortForward(ctx context.Context) (outcome string, err error) {
+	var data outcomeWrapper
+	err = c.do(ctx, http.MethodPost, "/portforward/renew", nil, &data)
+	return data.Outcome, err
+}
+
+// PortForwardEvents returns a channel receiving the current status
+// of the port forwarded, and then its status on each change. The
+// channel is closed once the context is canceled or the stream ends.
+func (c *Client) PortForwardEvents(ctx context.Context) (
+	statuses <-chan PortForwardStatus, err error) {
+	response, err := c.send(ctx, http.MethodGet, "/portforward/events", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	channel := make(chan PortForwardStatus)
+	go func() {
+		defer close(channel)
+		defer response.Body.Close()
+		scanner := bufio.NewScanner(response.Body)
+		for scanner.Scan() {
+			data := strings.TrimPrefix(scanner.Text(), "data: ")
+			if data == scanner.Text() { // not a data line
+				continue
+			}
+			var status PortForwardStatus
+			if err := json.Unmarshal([]byte(data), &status); err != nil {
+				continue
+			}
+			select {
+			case channel <- status:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return channel, nil
+}
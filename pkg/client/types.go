@@ -0,0 +1,206 @@
+package client
+
+import "time"
+
+// Loop statuses.
+const (
+	StatusStopped = "stopped"
+	StatusRunning = "running"
+)
+
+type BuildInformation struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+}
+
+// LogLine is a log line of the server. Its time and level
+// are empty if it continues the previous line.
+type LogLine struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+}
+
+type logsWrapper struct {
+	Logs []LogLine `json:"logs"`
+}
+
+type statusWrapper struct {
+	Status string `json:"status"`
+}
+
+type outcomeWrapper struct {
+	Outcome string `json:"outcome"`
+}
+
+type portWrapper struct {
+	Port uint16 `json:"port"`
+}
+
+// PortForwardStatus is the status of the port forwarded.
+type PortForwardStatus struct {
+	Provider string `json:"provider"`
+	// Port is the port forwarded, and is 0 if no port is forwarded.
+	Port uint16 `json:"port"`
+	// Expiration is the time the port forwarded expires, and is
+	// the zero time if it does not expire or no port is forwarded.
+	Expiration time.Time `json:"expires_at"`
+	// Ports are all the ports forwarded, the first one being Port.
+	Ports []ForwardedPort `json:"ports"`
+}
+
+// ForwardedPort is a port forwarded through the VPN tunnel.
+type ForwardedPort struct {
+	Port uint16 `json:"port"`
+	// Expiration is the time the port forwarded expires,
+	// and is the zero time if it does not expire.
+	Expiration time.Time `json:"expires_at"`
+}
+
+// HealthProbe is the result of a run of the health checks.
+type HealthProbe struct {
+	Time    time.Time `json:"time"`
+	Success bool      `json:"success"`
+	// Latency is the duration of the health checks, such as 150ms.
+	Latency string `json:"latency"`
+	// Error is the error of the health checks if they failed.
+	Error string `json:"error,omitempty"`
+}
+
+type healthHistoryWrapper struct {
+	Probes []HealthProbe `json:"probes"`
+}
+
+// ConnectionState is the state of the OpenVPN connection
+// with the reason of its last failure, if any.
+type ConnectionState struct {
+	State   string `json:"state"`
+	Failure string `json:"failure,omitempty"`
+}
+
+// VPNSettings contains the VPN provider and the server filters.
+type VPNSettings struct {
+	Provider  string   `json:"provider"`
+	Countries []string `json:"countries"`
+	Regions   []string `json:"regions"`
+	Cities    []string `json:"cities"`
+	Hostnames []string `json:"hostnames"`
+}
+
+// ServerFilters are the filters to list servers. The possible
+// values of each filter are matched case insensitively.
+type ServerFilters struct {
+	Countries []string
+	Regions   []string
+	Cities    []string
+	Hostnames []string
+}
+
+// Servers are the servers of a VPN provider, whose fields
+// depend on the provider.
+type Servers struct {
+	Provider string                   `json:"provider"`
+	Servers  []map[string]interface{} `json:"servers"`
+}
+
+type DNSStats struct {
+	Queries      uint64 `json:"queries"`
+	CacheHits    uint64 `json:"cache_hits"`
+	CacheMisses  uint64 `json:"cache_misses"`
+	CacheEntries int    `json:"cache_entries"`
+	Blocked      uint64 `json:"blocked"`
+	Failed       uint64 `json:"failed"`
+	Bogus        uint64 `json:"bogus"`
+	// UpstreamLatency is the average duration of the queries
+	// to the upstream servers, and is empty if there was none.
+	UpstreamLatency string `json:"upstream_latency,omitempty"`
+}
+
+type DNSQuery struct {
+	Time    time.Time `json:"time"`
+	Client  string    `json:"client,omitempty"`
+	Name    string    `json:"name"`
+	Type    uint16    `json:"type"`
+	Outcome string    `json:"outcome"`
+}
+
+type queriesWrapper struct {
+	Queries []DNSQuery `json:"queries"`
+}
+
+type blockCategoriesWrapper struct {
+	Categories []string `json:"categories"`
+}
+
+type updaterRunWrapper struct {
+	Providers []string `json:"providers,omitempty"`
+}
+
+// ServersUpdate is the result of the servers update of a VPN provider.
+type ServersUpdate struct {
+	Provider string `json:"provider"`
+	// Previous and Servers are the number of servers
+	// before and after the update.
+	Previous int `json:"previous"`
+	Servers  int `json:"servers"`
+	// Error is the update error, and is empty if the update succeeded.
+	Error string `json:"error,omitempty"`
+}
+
+type updateResultsWrapper struct {
+	Results []ServersUpdate `json:"results"`
+}
+
+// PublicIPInfo is the public IP address with information on it,
+// which is empty if the information provider is disabled or failed.
+type PublicIPInfo struct {
+	PublicIP string `json:"public_ip"`
+	// PublicIPv6 is the public IPv6 address, and is empty
+	// if IPv6 is disabled or it cannot be found.
+	PublicIPv6 string `json:"public_ipv6,omitempty"`
+	Country    string `json:"country"`
+	Region     string `json:"region"`
+	City       string `json:"city"`
+	// ASN is the autonomous system number of the
+	// IP address, and is 0 if it is unknown.
+	ASN          uint32 `json:"asn"`
+	Organization string `json:"organization"`
+}
+
+// PublicIPRecord is a public IP address with information on it,
+// and the time it was first found.
+type PublicIPRecord struct {
+	Time time.Time `json:"time"`
+	PublicIPInfo
+}
+
+type publicIPHistoryWrapper struct {
+	Records []PublicIPRecord `json:"records"`
+}
+
+// PublicIPComparison compares the public IP address before connecting
+// to the VPN with the public IP address found through the VPN. Its
+// public IP addresses are empty if the comparison is disabled.
+type PublicIPComparison struct {
+	PreVPN    PublicIPRecord `json:"pre_vpn"`
+	VPN       PublicIPRecord `json:"vpn"`
+	IPChanged bool           `json:"ip_changed"`
+	// ASNChanged and CountryChanged are false if either
+	// of the ASNs or countries compared is unknown.
+	ASNChanged     bool `json:"asn_changed"`
+	CountryChanged bool `json:"country_changed"`
+}
+
+type firewallStatusWrapper struct {
+	Enabled  bool   `json:"enabled"`
+	Duration string `json:"duration,omitempty"`
+}
+
+// FirewallPort is a port allowed through the firewall.
+type FirewallPort struct {
+	Port uint16 `json:"port"`
+	// Interface is the network interface name, or vpn or lan
+	// when setting a port.
+	Interface string `json:"interface,omitempty"`
+}
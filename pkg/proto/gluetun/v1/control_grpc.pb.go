@@ -0,0 +1,1028 @@
+// Protobuf definitions of the control operations of the v1 HTTP API,
+// served by the gRPC control server alongside the HTTP one when
+// HTTP_CONTROL_SERVER_GRPC is on. The Go code is generated in
+// pkg/proto/gluetun/v1 with go generate.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: gluetun/v1/control.proto
+
+package gluetunv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Control_GetVersion_FullMethodName            = "/gluetun.v1.Control/GetVersion"
+	Control_GetOpenVPNStatus_FullMethodName      = "/gluetun.v1.Control/GetOpenVPNStatus"
+	Control_SetOpenVPNStatus_FullMethodName      = "/gluetun.v1.Control/SetOpenVPNStatus"
+	Control_GetOpenVPNState_FullMethodName       = "/gluetun.v1.Control/GetOpenVPNState"
+	Control_SwitchServer_FullMethodName          = "/gluetun.v1.Control/SwitchServer"
+	Control_GetVPNSettings_FullMethodName        = "/gluetun.v1.Control/GetVPNSettings"
+	Control_SetVPNSettings_FullMethodName        = "/gluetun.v1.Control/SetVPNSettings"
+	Control_PauseVPN_FullMethodName              = "/gluetun.v1.Control/PauseVPN"
+	Control_ResumeVPN_FullMethodName             = "/gluetun.v1.Control/ResumeVPN"
+	Control_GetPortForward_FullMethodName        = "/gluetun.v1.Control/GetPortForward"
+	Control_RenewPortForward_FullMethodName      = "/gluetun.v1.Control/RenewPortForward"
+	Control_WatchPortForward_FullMethodName      = "/gluetun.v1.Control/WatchPortForward"
+	Control_GetDNSStatus_FullMethodName          = "/gluetun.v1.Control/GetDNSStatus"
+	Control_SetDNSStatus_FullMethodName          = "/gluetun.v1.Control/SetDNSStatus"
+	Control_RestartDNS_FullMethodName            = "/gluetun.v1.Control/RestartDNS"
+	Control_FlushDNSCache_FullMethodName         = "/gluetun.v1.Control/FlushDNSCache"
+	Control_ReloadDNSBlocklists_FullMethodName   = "/gluetun.v1.Control/ReloadDNSBlocklists"
+	Control_GetDNSBlockCategories_FullMethodName = "/gluetun.v1.Control/GetDNSBlockCategories"
+	Control_SetDNSBlockCategories_FullMethodName = "/gluetun.v1.Control/SetDNSBlockCategories"
+	Control_GetUpdaterStatus_FullMethodName      = "/gluetun.v1.Control/GetUpdaterStatus"
+	Control_SetUpdaterStatus_FullMethodName      = "/gluetun.v1.Control/SetUpdaterStatus"
+	Control_RunUpdate_FullMethodName             = "/gluetun.v1.Control/RunUpdate"
+	Control_GetPublicIP_FullMethodName           = "/gluetun.v1.Control/GetPublicIP"
+	Control_WatchLogs_FullMethodName             = "/gluetun.v1.Control/WatchLogs"
+)
+
+// ControlClient is the client API for Control service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ControlClient interface {
+	GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*BuildInformation, error)
+	GetOpenVPNStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	SetOpenVPNStatus(ctx context.Context, in *SetStatusRequest, opts ...grpc.CallOption) (*OutcomeResponse, error)
+	GetOpenVPNState(ctx context.Context, in *GetOpenVPNStateRequest, opts ...grpc.CallOption) (*OpenVPNState, error)
+	SwitchServer(ctx context.Context, in *SwitchServerRequest, opts ...grpc.CallOption) (*OutcomeResponse, error)
+	GetVPNSettings(ctx context.Context, in *GetVPNSettingsRequest, opts ...grpc.CallOption) (*VPNSettings, error)
+	SetVPNSettings(ctx context.Context, in *VPNSettings, opts ...grpc.CallOption) (*OutcomeResponse, error)
+	PauseVPN(ctx context.Context, in *PauseVPNRequest, opts ...grpc.CallOption) (*OutcomeResponse, error)
+	ResumeVPN(ctx context.Context, in *ResumeVPNRequest, opts ...grpc.CallOption) (*OutcomeResponse, error)
+	GetPortForward(ctx context.Context, in *GetPortForwardRequest, opts ...grpc.CallOption) (*PortForwardStatus, error)
+	RenewPortForward(ctx context.Context, in *RenewPortForwardRequest, opts ...grpc.CallOption) (*OutcomeResponse, error)
+	// WatchPortForward streams the current status of the port
+	// forwarded, and then its status on each change.
+	WatchPortForward(ctx context.Context, in *WatchPortForwardRequest, opts ...grpc.CallOption) (Control_WatchPortForwardClient, error)
+	GetDNSStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	SetDNSStatus(ctx context.Context, in *SetStatusRequest, opts ...grpc.CallOption) (*OutcomeResponse, error)
+	RestartDNS(ctx context.Context, in *RestartDNSRequest, opts ...grpc.CallOption) (*OutcomeResponse, error)
+	FlushDNSCache(ctx context.Context, in *FlushDNSCacheRequest, opts ...grpc.CallOption) (*OutcomeResponse, error)
+	ReloadDNSBlocklists(ctx context.Context, in *ReloadDNSBlocklistsRequest, opts ...grpc.CallOption) (*OutcomeResponse, error)
+	GetDNSBlockCategories(ctx context.Context, in *GetDNSBlockCategoriesRequest, opts ...grpc.CallOption) (*DNSBlockCategories, error)
+	SetDNSBlockCategories(ctx context.Context, in *DNSBlockCategories, opts ...grpc.CallOption) (*OutcomeResponse, error)
+	GetUpdaterStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	SetUpdaterStatus(ctx context.Context, in *SetStatusRequest, opts ...grpc.CallOption) (*OutcomeResponse, error)
+	RunUpdate(ctx context.Context, in *RunUpdateRequest, opts ...grpc.CallOption) (*RunUpdateResponse, error)
+	GetPublicIP(ctx context.Context, in *GetPublicIPRequest, opts ...grpc.CallOption) (*PublicIP, error)
+	// WatchLogs streams the log lines at the level given
+	// or more severe, starting with the last tail lines.
+	WatchLogs(ctx context.Context, in *WatchLogsRequest, opts ...grpc.CallOption) (Control_WatchLogsClient, error)
+}
+
+type controlClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewControlClient(cc grpc.ClientConnInterface) ControlClient {
+	return &controlClient{cc}
+}
+
+func (c *controlClient) GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*BuildInformation, error) {
+	out := new(BuildInformation)
+	err := c.cc.Invoke(ctx, Control_GetVersion_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) GetOpenVPNStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, Control_GetOpenVPNStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) SetOpenVPNStatus(ctx context.Context, in *SetStatusRequest, opts ...grpc.CallOption) (*OutcomeResponse, error) {
+	out := new(OutcomeResponse)
+	err := c.cc.Invoke(ctx, Control_SetOpenVPNStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) GetOpenVPNState(ctx context.Context, in *GetOpenVPNStateRequest, opts ...grpc.CallOption) (*OpenVPNState, error) {
+	out := new(OpenVPNState)
+	err := c.cc.Invoke(ctx, Control_GetOpenVPNState_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) SwitchServer(ctx context.Context, in *SwitchServerRequest, opts ...grpc.CallOption) (*OutcomeResponse, error) {
+	out := new(OutcomeResponse)
+	err := c.cc.Invoke(ctx, Control_SwitchServer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) GetVPNSettings(ctx context.Context, in *GetVPNSettingsRequest, opts ...grpc.CallOption) (*VPNSettings, error) {
+	out := new(VPNSettings)
+	err := c.cc.Invoke(ctx, Control_GetVPNSettings_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) SetVPNSettings(ctx context.Context, in *VPNSettings, opts ...grpc.CallOption) (*OutcomeResponse, error) {
+	out := new(OutcomeResponse)
+	err := c.cc.Invoke(ctx, Control_SetVPNSettings_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) PauseVPN(ctx context.Context, in *PauseVPNRequest, opts ...grpc.CallOption) (*OutcomeResponse, error) {
+	out := new(OutcomeResponse)
+	err := c.cc.Invoke(ctx, Control_PauseVPN_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) ResumeVPN(ctx context.Context, in *ResumeVPNRequest, opts ...grpc.CallOption) (*OutcomeResponse, error) {
+	out := new(OutcomeResponse)
+	err := c.cc.Invoke(ctx, Control_ResumeVPN_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) GetPortForward(ctx context.Context, in *GetPortForwardRequest, opts ...grpc.CallOption) (*PortForwardStatus, error) {
+	out := new(PortForwardStatus)
+	err := c.cc.Invoke(ctx, Control_GetPortForward_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) RenewPortForward(ctx context.Context, in *RenewPortForwardRequest, opts ...grpc.CallOption) (*OutcomeResponse, error) {
+	out := new(OutcomeResponse)
+	err := c.cc.Invoke(ctx, Control_RenewPortForward_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) WatchPortForward(ctx context.Context, in *WatchPortForwardRequest, opts ...grpc.CallOption) (Control_WatchPortForwardClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Control_ServiceDesc.Streams[0], Control_WatchPortForward_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlWatchPortForwardClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Control_WatchPortForwardClient interface {
+	Recv() (*PortForwardStatus, error)
+	grpc.ClientStream
+}
+
+type controlWatchPortForwardClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlWatchPortForwardClient) Recv() (*PortForwardStatus, error) {
+	m := new(PortForwardStatus)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controlClient) GetDNSStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, Control_GetDNSStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) SetDNSStatus(ctx context.Context, in *SetStatusRequest, opts ...grpc.CallOption) (*OutcomeResponse, error) {
+	out := new(OutcomeResponse)
+	err := c.cc.Invoke(ctx, Control_SetDNSStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) RestartDNS(ctx context.Context, in *RestartDNSRequest, opts ...grpc.CallOption) (*OutcomeResponse, error) {
+	out := new(OutcomeResponse)
+	err := c.cc.Invoke(ctx, Control_RestartDNS_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) FlushDNSCache(ctx context.Context, in *FlushDNSCacheRequest, opts ...grpc.CallOption) (*OutcomeResponse, error) {
+	out := new(OutcomeResponse)
+	err := c.cc.Invoke(ctx, Control_FlushDNSCache_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) ReloadDNSBlocklists(ctx context.Context, in *ReloadDNSBlocklistsRequest, opts ...grpc.CallOption) (*OutcomeResponse, error) {
+	out := new(OutcomeResponse)
+	err := c.cc.Invoke(ctx, Control_ReloadDNSBlocklists_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) GetDNSBlockCategories(ctx context.Context, in *GetDNSBlockCategoriesRequest, opts ...grpc.CallOption) (*DNSBlockCategories, error) {
+	out := new(DNSBlockCategories)
+	err := c.cc.Invoke(ctx, Control_GetDNSBlockCategories_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) SetDNSBlockCategories(ctx context.Context, in *DNSBlockCategories, opts ...grpc.CallOption) (*OutcomeResponse, error) {
+	out := new(OutcomeResponse)
+	err := c.cc.Invoke(ctx, Control_SetDNSBlockCategories_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) GetUpdaterStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, Control_GetUpdaterStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) SetUpdaterStatus(ctx context.Context, in *SetStatusRequest, opts ...grpc.CallOption) (*OutcomeResponse, error) {
+	out := new(OutcomeResponse)
+	err := c.cc.Invoke(ctx, Control_SetUpdaterStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) RunUpdate(ctx context.Context, in *RunUpdateRequest, opts ...grpc.CallOption) (*RunUpdateResponse, error) {
+	out := new(RunUpdateResponse)
+	err := c.cc.Invoke(ctx, Control_RunUpdate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) GetPublicIP(ctx context.Context, in *GetPublicIPRequest, opts ...grpc.CallOption) (*PublicIP, error) {
+	out := new(PublicIP)
+	err := c.cc.Invoke(ctx, Control_GetPublicIP_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) WatchLogs(ctx context.Context, in *WatchLogsRequest, opts ...grpc.CallOption) (Control_WatchLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Control_ServiceDesc.Streams[1], Control_WatchLogs_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlWatchLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Control_WatchLogsClient interface {
+	Recv() (*LogLine, error)
+	grpc.ClientStream
+}
+
+type controlWatchLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlWatchLogsClient) Recv() (*LogLine, error) {
+	m := new(LogLine)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ControlServer is the server API for Control service.
+// All implementations must embed UnimplementedControlServer
+// for forward compatibility
+type ControlServer interface {
+	GetVersion(context.Context, *GetVersionRequest) (*BuildInformation, error)
+	GetOpenVPNStatus(context.Context, *GetStatusRequest) (*StatusResponse, error)
+	SetOpenVPNStatus(context.Context, *SetStatusRequest) (*OutcomeResponse, error)
+	GetOpenVPNState(context.Context, *GetOpenVPNStateRequest) (*OpenVPNState, error)
+	SwitchServer(context.Context, *SwitchServerRequest) (*OutcomeResponse, error)
+	GetVPNSettings(context.Context, *GetVPNSettingsRequest) (*VPNSettings, error)
+	SetVPNSettings(context.Context, *VPNSettings) (*OutcomeResponse, error)
+	PauseVPN(context.Context, *PauseVPNRequest) (*OutcomeResponse, error)
+	ResumeVPN(context.Context, *ResumeVPNRequest) (*OutcomeResponse, error)
+	GetPortForward(context.Context, *GetPortForwardRequest) (*PortForwardStatus, error)
+	RenewPortForward(context.Context, *RenewPortForwardRequest) (*OutcomeResponse, error)
+	// WatchPortForward streams the current status of the port
+	// forwarded, and then its status on each change.
+	WatchPortForward(*WatchPortForwardRequest, Control_WatchPortForwardServer) error
+	GetDNSStatus(context.Context, *GetStatusRequest) (*StatusResponse, error)
+	SetDNSStatus(context.Context, *SetStatusRequest) (*OutcomeResponse, error)
+	RestartDNS(context.Context, *RestartDNSRequest) (*OutcomeResponse, error)
+	FlushDNSCache(context.Context, *FlushDNSCacheRequest) (*OutcomeResponse, error)
+	ReloadDNSBlocklists(context.Context, *ReloadDNSBlocklistsRequest) (*OutcomeResponse, error)
+	GetDNSBlockCategories(context.Context, *GetDNSBlockCategoriesRequest) (*DNSBlockCategories, error)
+	SetDNSBlockCategories(context.Context, *DNSBlockCategories) (*OutcomeResponse, error)
+	GetUpdaterStatus(context.Context, *GetStatusRequest) (*StatusResponse, error)
+	SetUpdaterStatus(context.Context, *SetStatusRequest) (*OutcomeResponse, error)
+	RunUpdate(context.Context, *RunUpdateRequest) (*RunUpdateResponse, error)
+	GetPublicIP(context.Context, *GetPublicIPRequest) (*PublicIP, error)
+	// WatchLogs streams the log lines at the level given
+	// or more severe, starting with the last tail lines.
+	WatchLogs(*WatchLogsRequest, Control_WatchLogsServer) error
+	mustEmbedUnimplementedControlServer()
+}
+
+// UnimplementedControlServer must be embedded to have forward compatible implementations.
+type UnimplementedControlServer struct {
+}
+
+func (UnimplementedControlServer) GetVersion(context.Context, *GetVersionRequest) (*BuildInformation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVersion not implemented")
+}
+func (UnimplementedControlServer) GetOpenVPNStatus(context.Context, *GetStatusRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOpenVPNStatus not implemented")
+}
+func (UnimplementedControlServer) SetOpenVPNStatus(context.Context, *SetStatusRequest) (*OutcomeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetOpenVPNStatus not implemented")
+}
+func (UnimplementedControlServer) GetOpenVPNState(context.Context, *GetOpenVPNStateRequest) (*OpenVPNState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOpenVPNState not implemented")
+}
+func (UnimplementedControlServer) SwitchServer(context.Context, *SwitchServerRequest) (*OutcomeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SwitchServer not implemented")
+}
+func (UnimplementedControlServer) GetVPNSettings(context.Context, *GetVPNSettingsRequest) (*VPNSettings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVPNSettings not implemented")
+}
+func (UnimplementedControlServer) SetVPNSettings(context.Context, *VPNSettings) (*OutcomeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVPNSettings not implemented")
+}
+func (UnimplementedControlServer) PauseVPN(context.Context, *PauseVPNRequest) (*OutcomeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseVPN not implemented")
+}
+func (UnimplementedControlServer) ResumeVPN(context.Context, *ResumeVPNRequest) (*OutcomeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeVPN not implemented")
+}
+func (UnimplementedControlServer) GetPortForward(context.Context, *GetPortForwardRequest) (*PortForwardStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPortForward not implemented")
+}
+func (UnimplementedControlServer) RenewPortForward(context.Context, *RenewPortForwardRequest) (*OutcomeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenewPortForward not implemented")
+}
+func (UnimplementedControlServer) WatchPortForward(*WatchPortForwardRequest, Control_WatchPortForwardServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchPortForward not implemented")
+}
+func (UnimplementedControlServer) GetDNSStatus(context.Context, *GetStatusRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDNSStatus not implemented")
+}
+func (UnimplementedControlServer) SetDNSStatus(context.Context, *SetStatusRequest) (*OutcomeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDNSStatus not implemented")
+}
+func (UnimplementedControlServer) RestartDNS(context.Context, *RestartDNSRequest) (*OutcomeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestartDNS not implemented")
+}
+func (UnimplementedControlServer) FlushDNSCache(context.Context, *FlushDNSCacheRequest) (*OutcomeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FlushDNSCache not implemented")
+}
+func (UnimplementedControlServer) ReloadDNSBlocklists(context.Context, *ReloadDNSBlocklistsRequest) (*OutcomeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadDNSBlocklists not implemented")
+}
+func (UnimplementedControlServer) GetDNSBlockCategories(context.Context, *GetDNSBlockCategoriesRequest) (*DNSBlockCategories, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDNSBlockCategories not implemented")
+}
+func (UnimplementedControlServer) SetDNSBlockCategories(context.Context, *DNSBlockCategories) (*OutcomeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDNSBlockCategories not implemented")
+}
+func (UnimplementedControlServer) GetUpdaterStatus(context.Context, *GetStatusRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUpdaterStatus not implemented")
+}
+func (UnimplementedControlServer) SetUpdaterStatus(context.Context, *SetStatusRequest) (*OutcomeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUpdaterStatus not implemented")
+}
+func (UnimplementedControlServer) RunUpdate(context.Context, *RunUpdateRequest) (*RunUpdateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunUpdate not implemented")
+}
+func (UnimplementedControlServer) GetPublicIP(context.Context, *GetPublicIPRequest) (*PublicIP, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPublicIP not implemented")
+}
+func (UnimplementedControlServer) WatchLogs(*WatchLogsRequest, Control_WatchLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchLogs not implemented")
+}
+func (UnimplementedControlServer) mustEmbedUnimplementedControlServer() {}
+
+// UnsafeControlServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ControlServer will
+// result in compilation errors.
+type UnsafeControlServer interface {
+	mustEmbedUnimplementedControlServer()
+}
+
+func RegisterControlServer(s grpc.ServiceRegistrar, srv ControlServer) {
+	s.RegisterService(&Control_ServiceDesc, srv)
+}
+
+func _Control_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).GetVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_GetVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).GetVersion(ctx, req.(*GetVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_GetOpenVPNStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).GetOpenVPNStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_GetOpenVPNStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).GetOpenVPNStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_SetOpenVPNStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).SetOpenVPNStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_SetOpenVPNStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).SetOpenVPNStatus(ctx, req.(*SetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_GetOpenVPNState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOpenVPNStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).GetOpenVPNState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_GetOpenVPNState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).GetOpenVPNState(ctx, req.(*GetOpenVPNStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_SwitchServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SwitchServerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).SwitchServer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_SwitchServer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).SwitchServer(ctx, req.(*SwitchServerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_GetVPNSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVPNSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).GetVPNSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_GetVPNSettings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).GetVPNSettings(ctx, req.(*GetVPNSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_SetVPNSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VPNSettings)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).SetVPNSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_SetVPNSettings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).SetVPNSettings(ctx, req.(*VPNSettings))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_PauseVPN_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseVPNRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).PauseVPN(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_PauseVPN_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).PauseVPN(ctx, req.(*PauseVPNRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_ResumeVPN_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeVPNRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).ResumeVPN(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_ResumeVPN_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).ResumeVPN(ctx, req.(*ResumeVPNRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_GetPortForward_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPortForwardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).GetPortForward(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_GetPortForward_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).GetPortForward(ctx, req.(*GetPortForwardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_RenewPortForward_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenewPortForwardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).RenewPortForward(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_RenewPortForward_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).RenewPortForward(ctx, req.(*RenewPortForwardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_WatchPortForward_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchPortForwardRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServer).WatchPortForward(m, &controlWatchPortForwardServer{stream})
+}
+
+type Control_WatchPortForwardServer interface {
+	Send(*PortForwardStatus) error
+	grpc.ServerStream
+}
+
+type controlWatchPortForwardServer struct {
+	grpc.ServerStream
+}
+
+func (x *controlWatchPortForwardServer) Send(m *PortForwardStatus) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Control_GetDNSStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).GetDNSStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_GetDNSStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).GetDNSStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_SetDNSStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).SetDNSStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_SetDNSStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).SetDNSStatus(ctx, req.(*SetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_RestartDNS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestartDNSRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).RestartDNS(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_RestartDNS_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).RestartDNS(ctx, req.(*RestartDNSRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_FlushDNSCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FlushDNSCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).FlushDNSCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_FlushDNSCache_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).FlushDNSCache(ctx, req.(*FlushDNSCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_ReloadDNSBlocklists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadDNSBlocklistsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).ReloadDNSBlocklists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_ReloadDNSBlocklists_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).ReloadDNSBlocklists(ctx, req.(*ReloadDNSBlocklistsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_GetDNSBlockCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDNSBlockCategoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).GetDNSBlockCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_GetDNSBlockCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).GetDNSBlockCategories(ctx, req.(*GetDNSBlockCategoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_SetDNSBlockCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DNSBlockCategories)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).SetDNSBlockCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_SetDNSBlockCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).SetDNSBlockCategories(ctx, req.(*DNSBlockCategories))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_GetUpdaterStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).GetUpdaterStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_GetUpdaterStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).GetUpdaterStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_SetUpdaterStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).SetUpdaterStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_SetUpdaterStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).SetUpdaterStatus(ctx, req.(*SetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_RunUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).RunUpdate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_RunUpdate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).RunUpdate(ctx, req.(*RunUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_GetPublicIP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPublicIPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).GetPublicIP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_GetPublicIP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).GetPublicIP(ctx, req.(*GetPublicIPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_WatchLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServer).WatchLogs(m, &controlWatchLogsServer{stream})
+}
+
+type Control_WatchLogsServer interface {
+	Send(*LogLine) error
+	grpc.ServerStream
+}
+
+type controlWatchLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *controlWatchLogsServer) Send(m *LogLine) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Control_ServiceDesc is the grpc.ServiceDesc for Control service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Control_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gluetun.v1.Control",
+	HandlerType: (*ControlServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetVersion",
+			Handler:    _Control_GetVersion_Handler,
+		},
+		{
+			MethodName: "GetOpenVPNStatus",
+			Handler:    _Control_GetOpenVPNStatus_Handler,
+		},
+		{
+			MethodName: "SetOpenVPNStatus",
+			Handler:    _Control_SetOpenVPNStatus_Handler,
+		},
+		{
+			MethodName: "GetOpenVPNState",
+			Handler:    _Control_GetOpenVPNState_Handler,
+		},
+		{
+			MethodName: "SwitchServer",
+			Handler:    _Control_SwitchServer_Handler,
+		},
+		{
+			MethodName: "GetVPNSettings",
+			Handler:    _Control_GetVPNSettings_Handler,
+		},
+		{
+			MethodName: "SetVPNSettings",
+			Handler:    _Control_SetVPNSettings_Handler,
+		},
+		{
+			MethodName: "PauseVPN",
+			Handler:    _Control_PauseVPN_Handler,
+		},
+		{
+			MethodName: "ResumeVPN",
+			Handler:    _Control_ResumeVPN_Handler,
+		},
+		{
+			MethodName: "GetPortForward",
+			Handler:    _Control_GetPortForward_Handler,
+		},
+		{
+			MethodName: "RenewPortForward",
+			Handler:    _Control_RenewPortForward_Handler,
+		},
+		{
+			MethodName: "GetDNSStatus",
+			Handler:    _Control_GetDNSStatus_Handler,
+		},
+		{
+			MethodName: "SetDNSStatus",
+			Handler:    _Control_SetDNSStatus_Handler,
+		},
+		{
+			MethodName: "RestartDNS",
+			Handler:    _Control_RestartDNS_Handler,
+		},
+		{
+			MethodName: "FlushDNSCache",
+			Handler:    _Control_FlushDNSCache_Handler,
+		},
+		{
+			MethodName: "ReloadDNSBlocklists",
+			Handler:    _Control_ReloadDNSBlocklists_Handler,
+		},
+		{
+			MethodName: "GetDNSBlockCategories",
+			Handler:    _Control_GetDNSBlockCategories_Handler,
+		},
+		{
+			MethodName: "SetDNSBlockCategories",
+			Handler:    _Control_SetDNSBlockCategories_Handler,
+		},
+		{
+			MethodName: "GetUpdaterStatus",
+			Handler:    _Control_GetUpdaterStatus_Handler,
+		},
+		{
+			MethodName: "SetUpdaterStatus",
+			Handler:    _Control_SetUpdaterStatus_Handler,
+		},
+		{
+			MethodName: "RunUpdate",
+			Handler:    _Control_RunUpdate_Handler,
+		},
+		{
+			MethodName: "GetPublicIP",
+			Handler:    _Control_GetPublicIP_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchPortForward",
+			Handler:       _Control_WatchPortForward_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchLogs",
+			Handler:       _Control_WatchLogs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "gluetun/v1/control.proto",
+}
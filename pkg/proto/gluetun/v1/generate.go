@@ -0,0 +1,5 @@
+// Package gluetunv1 contains the Go code generated from the protobuf
+// definitions of the gRPC control API in api/proto/gluetun/v1.
+package gluetunv1
+
+//go:generate protoc -I ../../../../api/proto --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative gluetun/v1/control.proto